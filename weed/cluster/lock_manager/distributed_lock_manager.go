@@ -13,16 +13,18 @@ const LiveLockTTL = time.Second * 7
 var NoLockServerError = fmt.Errorf("no lock server found")
 
 type DistributedLockManager struct {
-	lockManager *LockManager
-	LockRing    *LockRing
-	Host        pb.ServerAddress
+	lockManager      *LockManager
+	rangeLockManager *RangeLockManager
+	LockRing         *LockRing
+	Host             pb.ServerAddress
 }
 
 func NewDistributedLockManager(host pb.ServerAddress) *DistributedLockManager {
 	return &DistributedLockManager{
-		lockManager: NewLockManager(),
-		LockRing:    NewLockRing(time.Second * 5),
-		Host:        host,
+		lockManager:      NewLockManager(),
+		rangeLockManager: NewRangeLockManager(),
+		LockRing:         NewLockRing(time.Second * 5),
+		Host:             host,
 	}
 }
 
@@ -101,3 +103,46 @@ func (dlm *DistributedLockManager) IsLocal(key string) bool {
 	}
 	return hashKeyToServer(key, servers) == dlm.Host
 }
+
+// RangeLock adds the byte range lock, or only tests it, on the filer owning the key
+func (dlm *DistributedLockManager) RangeLock(key string, lock *RangeLock, isTest bool) (conflict *RangeLock, movedTo pb.ServerAddress, err error) {
+	movedTo, err = dlm.findLockOwningFiler(key)
+	if err != nil || movedTo != dlm.Host {
+		return
+	}
+	if isTest {
+		conflict = dlm.rangeLockManager.TestLock(key, lock)
+	} else {
+		conflict = dlm.rangeLockManager.Lock(key, lock)
+	}
+	return
+}
+
+func (dlm *DistributedLockManager) RangeUnlock(key string, owner *RangeLock) (movedTo pb.ServerAddress, err error) {
+	movedTo, err = dlm.findLockOwningFiler(key)
+	if err != nil || movedTo != dlm.Host {
+		return
+	}
+	dlm.rangeLockManager.Unlock(key, owner)
+	return
+}
+
+func (dlm *DistributedLockManager) RenewRangeLocks(key string, client string, heldLocks []*RangeLock, expiredAtNs int64) (conflict *RangeLock, movedTo pb.ServerAddress, err error) {
+	movedTo, err = dlm.findLockOwningFiler(key)
+	if err != nil || movedTo != dlm.Host {
+		return
+	}
+	conflict = dlm.rangeLockManager.Renew(key, client, heldLocks, expiredAtNs)
+	return
+}
+
+// InsertRangeLocks is used to move the range locks of a key to a server unconditionally
+func (dlm *DistributedLockManager) InsertRangeLocks(key string, locks []*RangeLock) {
+	dlm.rangeLockManager.InsertLocks(key, locks)
+}
+func (dlm *DistributedLockManager) SelectNotOwnedRangeLocks(servers []pb.ServerAddress) (locks map[string][]*RangeLock) {
+	return dlm.rangeLockManager.SelectLocks(func(key string) bool {
+		server := hashKeyToServer(key, servers)
+		return server != dlm.Host
+	})
+}
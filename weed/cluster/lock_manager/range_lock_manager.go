@@ -0,0 +1,236 @@
+package lock_manager
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+)
+
+// RangeLock is a POSIX advisory lock on a byte range, held by one lock owner of one client
+type RangeLock struct {
+	Start       int64
+	End         int64 // inclusive
+	IsExclusive bool
+	Client      string
+	Owner       uint64
+	Pid         uint32
+	ExpiredAtNs int64
+}
+
+func (l *RangeLock) isSameOwner(other *RangeLock) bool {
+	return l.Client == other.Client && l.Owner == other.Owner
+}
+
+func (l *RangeLock) overlaps(start, end int64) bool {
+	return l.Start <= end && start <= l.End
+}
+
+func (l *RangeLock) conflictsWith(other *RangeLock) bool {
+	if l.isSameOwner(other) || !l.IsExclusive && !other.IsExclusive {
+		return false
+	}
+	return l.overlaps(other.Start, other.End)
+}
+
+func (l *RangeLock) String() string {
+	lockType := "shared"
+	if l.IsExclusive {
+		lockType = "exclusive"
+	}
+	return fmt.Sprintf("%s [%d,%d] by %s/%d pid %d", lockType, l.Start, l.End, l.Client, l.Owner, l.Pid)
+}
+
+// RangeLockManager keeps the byte range locks of each key, usually one file,
+// following the POSIX record lock semantics:
+// the locks of the same owner never conflict, and a new lock replaces the owner's locks on the same range.
+type RangeLockManager struct {
+	locks      map[string][]*RangeLock
+	accessLock sync.Mutex
+}
+
+func NewRangeLockManager() *RangeLockManager {
+	t := &RangeLockManager{
+		locks: make(map[string][]*RangeLock),
+	}
+	go t.CleanUp()
+	return t
+}
+
+// TestLock returns a lock conflicting with the lock, if any
+func (rlm *RangeLockManager) TestLock(key string, lock *RangeLock) (conflict *RangeLock) {
+	rlm.accessLock.Lock()
+	defer rlm.accessLock.Unlock()
+
+	return rlm.findConflict(key, lock)
+}
+
+// Lock adds the lock, unless it conflicts with a lock of another owner, which is returned
+func (rlm *RangeLockManager) Lock(key string, lock *RangeLock) (conflict *RangeLock) {
+	rlm.accessLock.Lock()
+	defer rlm.accessLock.Unlock()
+
+	if conflict = rlm.findConflict(key, lock); conflict != nil {
+		glog.V(4).Infof("range lock %s %v conflicts with %v", key, lock, conflict)
+		return
+	}
+	locks := removeRange(rlm.locks[key], lock, lock.Start, lock.End)
+	rlm.locks[key] = append(locks, lock)
+	glog.V(4).Infof("range lock %s %v", key, lock)
+	return nil
+}
+
+// Unlock removes the range from the locks of the owner
+func (rlm *RangeLockManager) Unlock(key string, owner *RangeLock) {
+	rlm.accessLock.Lock()
+	defer rlm.accessLock.Unlock()
+
+	locks := removeRange(rlm.locks[key], owner, owner.Start, owner.End)
+	if len(locks) == 0 {
+		delete(rlm.locks, key)
+	} else {
+		rlm.locks[key] = locks
+	}
+	glog.V(4).Infof("range unlock %s %v", key, owner)
+}
+
+// Renew replaces the locks of the client with the held locks, extended to the new expiration time.
+// It re-creates the locks lost by a restarted or newly assigned lock server,
+// unless they conflict with the locks taken by the other clients meanwhile.
+func (rlm *RangeLockManager) Renew(key string, client string, heldLocks []*RangeLock, expiredAtNs int64) (conflict *RangeLock) {
+	rlm.accessLock.Lock()
+	defer rlm.accessLock.Unlock()
+
+	var locks []*RangeLock
+	for _, lock := range rlm.activeLocks(key) {
+		if lock.Client != client {
+			locks = append(locks, lock)
+		}
+	}
+	for _, held := range heldLocks {
+		for _, lock := range locks {
+			if lock.conflictsWith(held) {
+				return lock
+			}
+		}
+	}
+	for _, held := range heldLocks {
+		renewed := *held
+		renewed.Client, renewed.ExpiredAtNs = client, expiredAtNs
+		locks = append(locks, &renewed)
+	}
+	if len(locks) == 0 {
+		delete(rlm.locks, key)
+	} else {
+		rlm.locks[key] = locks
+	}
+	return nil
+}
+
+// GetLocks returns the active locks of the key
+func (rlm *RangeLockManager) GetLocks(key string) []*RangeLock {
+	rlm.accessLock.Lock()
+	defer rlm.accessLock.Unlock()
+
+	return append([]*RangeLock(nil), rlm.activeLocks(key)...)
+}
+
+func (rlm *RangeLockManager) findConflict(key string, lock *RangeLock) *RangeLock {
+	for _, existing := range rlm.activeLocks(key) {
+		if existing.conflictsWith(lock) {
+			return existing
+		}
+	}
+	return nil
+}
+
+func (rlm *RangeLockManager) activeLocks(key string) []*RangeLock {
+	locks := rlm.locks[key]
+	now := time.Now().UnixNano()
+	active := locks[:0]
+	for _, lock := range locks {
+		if lock.ExpiredAtNs > 0 && lock.ExpiredAtNs < now {
+			glog.V(4).Infof("range lock %s %v expired at %v", key, lock, time.Unix(0, lock.ExpiredAtNs))
+			continue
+		}
+		active = append(active, lock)
+	}
+	if len(active) == 0 {
+		delete(rlm.locks, key)
+		return nil
+	}
+	rlm.locks[key] = active
+	return active
+}
+
+// removeRange removes the range from the locks of the owner, splitting the locks partially in the range
+func removeRange(locks []*RangeLock, owner *RangeLock, start, end int64) (remaining []*RangeLock) {
+	for _, lock := range locks {
+		if !lock.isSameOwner(owner) || !lock.overlaps(start, end) {
+			remaining = append(remaining, lock)
+			continue
+		}
+		if lock.Start < start {
+			before := *lock
+			before.End = start - 1
+			remaining = append(remaining, &before)
+		}
+		if end < lock.End {
+			after := *lock
+			after.Start = end + 1
+			remaining = append(remaining, &after)
+		}
+	}
+	return
+}
+
+func (rlm *RangeLockManager) CleanUp() {
+
+	for {
+		time.Sleep(1 * time.Minute)
+
+		rlm.accessLock.Lock()
+		for key := range rlm.locks {
+			rlm.activeLocks(key)
+		}
+		rlm.accessLock.Unlock()
+	}
+}
+
+// SelectLocks takes out the locks of the keys selected by selectFn
+func (rlm *RangeLockManager) SelectLocks(selectFn func(key string) bool) (locks map[string][]*RangeLock) {
+	rlm.accessLock.Lock()
+	defer rlm.accessLock.Unlock()
+
+	locks = make(map[string][]*RangeLock)
+	for key := range rlm.locks {
+		if active := rlm.activeLocks(key); len(active) > 0 && selectFn(key) {
+			glog.V(4).Infof("range locks %s selected and deleted", key)
+			locks[key] = active
+			delete(rlm.locks, key)
+		}
+	}
+	return
+}
+
+// InsertLocks adds the locks unconditionally
+func (rlm *RangeLockManager) InsertLocks(key string, locks []*RangeLock) {
+	rlm.accessLock.Lock()
+	defer rlm.accessLock.Unlock()
+
+	rlm.locks[key] = append(rlm.locks[key], locks...)
+}
+
+// Keys returns the keys having active locks
+func (rlm *RangeLockManager) Keys() (keys []string) {
+	rlm.accessLock.Lock()
+	defer rlm.accessLock.Unlock()
+
+	for key := range rlm.locks {
+		if len(rlm.activeLocks(key)) > 0 {
+			keys = append(keys, key)
+		}
+	}
+	return
+}
@@ -0,0 +1,60 @@
+package lock_manager
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRangeLock(t *testing.T) {
+	rlm := NewRangeLockManager()
+	key := "posix:/a.txt"
+
+	// shared locks of different owners
+	assert.Nil(t, rlm.Lock(key, &RangeLock{Start: 0, End: 99, Client: "c1", Owner: 1}))
+	assert.Nil(t, rlm.Lock(key, &RangeLock{Start: 50, End: 149, Client: "c2", Owner: 1}))
+	conflict := rlm.TestLock(key, &RangeLock{Start: 120, End: 130, IsExclusive: true, Client: "c1", Owner: 1})
+	assert.Equal(t, "c2", conflict.Client)
+	assert.Nil(t, rlm.TestLock(key, &RangeLock{Start: 150, End: 200, IsExclusive: true, Client: "c1", Owner: 1}))
+
+	// the same owner upgrades its own lock, only where no other owner holds one
+	assert.NotNil(t, rlm.Lock(key, &RangeLock{Start: 0, End: 99, IsExclusive: true, Client: "c1", Owner: 1}))
+	assert.Nil(t, rlm.Lock(key, &RangeLock{Start: 0, End: 49, IsExclusive: true, Client: "c1", Owner: 1}))
+	assert.Equal(t, 3, len(rlm.GetLocks(key)))
+	assert.NotNil(t, rlm.TestLock(key, &RangeLock{Start: 10, End: 10, Client: "c1", Owner: 2}))
+
+	// unlocking the middle of a range splits it
+	rlm.Unlock(key, &RangeLock{Start: 60, End: 69, Client: "c2", Owner: 1})
+	assert.Equal(t, 4, len(rlm.GetLocks(key)))
+	assert.Nil(t, rlm.TestLock(key, &RangeLock{Start: 60, End: 69, IsExclusive: true, Client: "c1", Owner: 1}))
+	assert.NotNil(t, rlm.TestLock(key, &RangeLock{Start: 70, End: 70, IsExclusive: true, Client: "c1", Owner: 1}))
+
+	rlm.Unlock(key, &RangeLock{Start: 0, End: 1 << 62, Client: "c1", Owner: 1})
+	rlm.Unlock(key, &RangeLock{Start: 0, End: 1 << 62, Client: "c2", Owner: 1})
+	assert.Equal(t, 0, len(rlm.GetLocks(key)))
+	assert.Equal(t, 0, len(rlm.Keys()))
+}
+
+func TestRangeLockRenew(t *testing.T) {
+	rlm := NewRangeLockManager()
+	key := "flock:/a.txt"
+	expiredAtNs := time.Now().Add(time.Minute).UnixNano()
+
+	// an expired lock does not conflict
+	assert.Nil(t, rlm.Lock(key, &RangeLock{Start: 0, End: 99, IsExclusive: true, Client: "c1", Owner: 1, ExpiredAtNs: time.Now().Add(-time.Second).UnixNano()}))
+	assert.Nil(t, rlm.Lock(key, &RangeLock{Start: 0, End: 99, IsExclusive: true, Client: "c2", Owner: 1, ExpiredAtNs: expiredAtNs}))
+
+	// re-creating the lost locks conflicting with the other clients
+	conflict := rlm.Renew(key, "c1", []*RangeLock{{Start: 0, End: 99, IsExclusive: true, Client: "c1", Owner: 1}}, expiredAtNs)
+	assert.Equal(t, "c2", conflict.Client)
+
+	// re-creating the lost locks on a new lock server
+	assert.Nil(t, rlm.Renew(key, "c3", []*RangeLock{{Start: 100, End: 199, IsExclusive: true, Client: "c3", Owner: 7}}, expiredAtNs))
+	assert.NotNil(t, rlm.TestLock(key, &RangeLock{Start: 150, End: 150, Client: "c1", Owner: 1}))
+	assert.Nil(t, rlm.Renew(key, "c3", nil, expiredAtNs))
+	assert.Nil(t, rlm.TestLock(key, &RangeLock{Start: 150, End: 150, Client: "c1", Owner: 1}))
+
+	selected := rlm.SelectLocks(func(key string) bool { return true })
+	assert.Equal(t, 1, len(selected[key]))
+	assert.Equal(t, 0, len(rlm.Keys()))
+}
@@ -27,12 +27,14 @@ var Commands = []*Command{
 	cmdFilerReplicate,
 	cmdFilerSynchronize,
 	cmdFix,
+	cmdFtp,
 	cmdFuse,
 	cmdIam,
 	cmdMaster,
 	cmdMasterFollower,
 	cmdMount,
 	cmdMqBroker,
+	cmdMqKafka,
 	cmdS3,
 	cmdScaffold,
 	cmdServer,
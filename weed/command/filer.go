@@ -109,7 +109,9 @@ func init() {
 	filerS3Options.port = cmdFiler.Flag.Int("s3.port", 8333, "s3 server http listen port")
 	filerS3Options.portHttps = cmdFiler.Flag.Int("s3.port.https", 0, "s3 server https listen port")
 	filerS3Options.portGrpc = cmdFiler.Flag.Int("s3.port.grpc", 0, "s3 server grpc listen port")
+	filerS3Options.portWebsite = cmdFiler.Flag.Int("s3.port.website", 0, "s3 static website http listen port, 0 to disable")
 	filerS3Options.domainName = cmdFiler.Flag.String("s3.domainName", "", "suffix of the host name in comma separated list, {bucket}.{domainName}")
+	filerS3Options.websiteDomainName = cmdFiler.Flag.String("s3.website.domainName", "", "suffix of the website host name in comma separated list, {bucket}.{website.domainName}, otherwise the host name is the bucket name")
 	filerS3Options.allowedOrigins = cmdFiler.Flag.String("s3.allowedOrigins", "*", "comma separated list of allowed origins")
 	filerS3Options.dataCenter = cmdFiler.Flag.String("s3.dataCenter", "", "prefer to read and write to volumes in this data center")
 	filerS3Options.tlsPrivateKey = cmdFiler.Flag.String("s3.key.file", "", "path to the TLS private key file")
@@ -119,6 +121,9 @@ func init() {
 	filerS3Options.allowEmptyFolder = cmdFiler.Flag.Bool("s3.allowEmptyFolder", true, "allow empty folders")
 	filerS3Options.allowDeleteBucketNotEmpty = cmdFiler.Flag.Bool("s3.allowDeleteBucketNotEmpty", true, "allow recursive deleting all entries along with bucket")
 	filerS3Options.localSocket = cmdFiler.Flag.String("s3.localSocket", "", "default to /tmp/seaweedfs-s3-<port>.sock")
+	filerS3Options.lifecycleIntervalMinutes = cmdFiler.Flag.Int("s3.lifecycleIntervalMinutes", 60, "interval to apply bucket lifecycle rules, 0 to disable")
+	filerS3Options.storageClassDiskTypes = cmdFiler.Flag.String("s3.storageClassDiskTypes", "", "storage classes that lifecycle rules can transition objects to, and their disk types, e.g. \"STANDARD_IA=hdd,GLACIER=archive\"")
+	filerS3Options.trustedProxies = cmdFiler.Flag.String("s3.trustedProxies", "", "comma separated ip addresses or cidrs of the reverse proxies, whose X-Forwarded-For and X-Forwarded-Proto headers are used by the bucket policies")
 
 	// start webdav on filer
 	filerStartWebDav = cmdFiler.Flag.Bool("webdav", false, "whether to start webdav gateway")
@@ -0,0 +1,200 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+	"time"
+
+	ftpserver "github.com/fclairamb/ftpserverlib"
+
+	"github.com/seaweedfs/seaweedfs/weed/ftpd"
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/pb"
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/security"
+	"github.com/seaweedfs/seaweedfs/weed/util"
+)
+
+var (
+	ftpStandaloneOptions FtpOptions
+)
+
+type FtpOptions struct {
+	filer            *string
+	ip               *string
+	bindIp           *string
+	port             *int
+	filerRootPath    *string
+	usersFile        *string
+	s3ConfigFile     *string
+	passivePortStart *int
+	passivePortStop  *int
+	collection       *string
+	replication      *string
+	disk             *string
+	cacheDir         *string
+	cacheSizeMB      *int64
+	maxMB            *int
+	tlsMode          *string
+	tlsRequired      *bool
+	tlsPrivateKey    *string
+	tlsCertificate   *string
+}
+
+func init() {
+	cmdFtp.Run = runFtp // break init cycle
+	ftpStandaloneOptions.filer = cmdFtp.Flag.String("filer", "localhost:8888", "filer server address")
+	ftpStandaloneOptions.ip = cmdFtp.Flag.String("ip", util.DetectedHostAddress(), "ftp server public ip address, sent to the clients for the passive transfers")
+	ftpStandaloneOptions.bindIp = cmdFtp.Flag.String("ip.bind", "", "ip address to bind to. If empty, default to same as -ip option.")
+	ftpStandaloneOptions.port = cmdFtp.Flag.Int("port", 8021, "ftp server listen port")
+	ftpStandaloneOptions.filerRootPath = cmdFtp.Flag.String("filer.path", "/", "the remote path on the filer server for the users without a home folder")
+	ftpStandaloneOptions.usersFile = cmdFtp.Flag.String("users", "", "the json file of the ftp users, with the passwords and the home folders")
+	ftpStandaloneOptions.s3ConfigFile = cmdFtp.Flag.String("s3.config", "", "the s3 identities json file, default to the identities saved in the filer. The identities log in with the access key and the secret key, and see the buckets, only with -tls.required or -tls.mode=implicit.")
+	ftpStandaloneOptions.passivePortStart = cmdFtp.Flag.Int("passivePortStart", 30000, "passive port start range")
+	ftpStandaloneOptions.passivePortStop = cmdFtp.Flag.Int("passivePortStop", 30100, "passive port stop range")
+	ftpStandaloneOptions.collection = cmdFtp.Flag.String("collection", "", "collection to create the files")
+	ftpStandaloneOptions.replication = cmdFtp.Flag.String("replication", "", "replication to create the files")
+	ftpStandaloneOptions.disk = cmdFtp.Flag.String("disk", "", "[hdd|ssd|<tag>] hard drive or solid state drive or any tag")
+	ftpStandaloneOptions.cacheDir = cmdFtp.Flag.String("cacheDir", os.TempDir(), "local cache directory for file chunks")
+	ftpStandaloneOptions.cacheSizeMB = cmdFtp.Flag.Int64("cacheCapacityMB", 0, "local cache capacity in MB")
+	ftpStandaloneOptions.maxMB = cmdFtp.Flag.Int("maxMB", 4, "split files larger than the limit")
+	ftpStandaloneOptions.tlsMode = cmdFtp.Flag.String("tls.mode", "", "[explicit|implicit] FTPS with AUTH TLS on the ftp port, or TLS from the connection start. Empty to disable FTPS.")
+	ftpStandaloneOptions.tlsRequired = cmdFtp.Flag.Bool("tls.required", false, "reject the clients without AUTH TLS, in the explicit tls mode. Needed for the S3 identities to log in.")
+	ftpStandaloneOptions.tlsPrivateKey = cmdFtp.Flag.String("key.file", "", "path to the TLS private key file")
+	ftpStandaloneOptions.tlsCertificate = cmdFtp.Flag.String("cert.file", "", "path to the TLS certificate file")
+}
+
+var cmdFtp = &Command{
+	UsageLine: "ftp [-port=8021] [-filer=<ip:port>] [-users=<users.json>] [-tls.mode=explicit|implicit]",
+	Short:     "start an ftp server that is backed by a filer",
+	Long: `start an ftp server that is backed by a filer.
+
+	The users are defined in the json file of the -users option, e.g.
+
+	{
+	  "users": [
+	    {"name": "partner1", "password": "$2a$10$...", "home": "/partners/partner1"},
+	    {"name": "auditor", "password": "secret", "home": "/partners", "readOnly": true}
+	  ]
+	}
+
+	The password can be a bcrypt hash or plain text. The home folder is the root folder
+	seen by the user, and is relative to -filer.path unless starting with "/".
+
+	The S3 identities can also log in, with the access key or the identity name as the
+	user name, and the secret key as the password, only when TLS is required, so the
+	secret keys are never sent in clear text. They see the buckets, with the same
+	permissions as in S3.
+
+	The buckets with versioning, object lock, a bucket policy or default encryption
+	are changed only through S3, and the buckets with a policy are not served. The
+	objects encrypted by S3 or with an object acl are read only through S3.
+
+	FTPS needs -cert.file and -key.file, with -tls.mode=explicit for AUTH TLS on the
+	ftp port, or -tls.mode=implicit for TLS from the connection start, usually on port 990.
+
+`,
+}
+
+func runFtp(cmd *Command, args []string) bool {
+
+	util.LoadSecurityConfiguration()
+
+	glog.V(0).Infof("Starting Seaweed FTP Server %s at port %d", util.Version(), *ftpStandaloneOptions.port)
+
+	return ftpStandaloneOptions.startFtpServer()
+
+}
+
+func (ftpOpt *FtpOptions) startFtpServer() bool {
+
+	// detect current user
+	uid, gid := uint32(0), uint32(0)
+	if u, err := user.Current(); err == nil {
+		if parsedId, pe := strconv.ParseUint(u.Uid, 10, 32); pe == nil {
+			uid = uint32(parsedId)
+		}
+		if parsedId, pe := strconv.ParseUint(u.Gid, 10, 32); pe == nil {
+			gid = uint32(parsedId)
+		}
+	}
+
+	if *ftpOpt.bindIp == "" {
+		*ftpOpt.bindIp = *ftpOpt.ip
+	}
+
+	// parse filer grpc address
+	filerAddress := pb.ServerAddress(*ftpOpt.filer)
+
+	grpcDialOption := security.LoadClientTLS(util.GetViper(), "grpc.client")
+
+	var cipher bool
+	filerBucketsPath := "/buckets"
+	// connect to filer
+	for {
+		err := pb.WithGrpcFilerClient(false, 0, filerAddress, grpcDialOption, func(client filer_pb.SeaweedFilerClient) error {
+			resp, err := client.GetFilerConfiguration(context.Background(), &filer_pb.GetFilerConfigurationRequest{})
+			if err != nil {
+				return fmt.Errorf("get filer %s configuration: %v", filerAddress, err)
+			}
+			cipher = resp.Cipher
+			filerBucketsPath = resp.DirBuckets
+			return nil
+		})
+		if err != nil {
+			glog.V(0).Infof("wait to connect to filer %s grpc address %s", *ftpOpt.filer, filerAddress.ToGrpcAddress())
+			time.Sleep(time.Second)
+		} else {
+			glog.V(0).Infof("connected to filer %s grpc address %s", *ftpOpt.filer, filerAddress.ToGrpcAddress())
+			break
+		}
+	}
+
+	listenAddress := util.JoinHostPort(*ftpOpt.bindIp, *ftpOpt.port)
+	ftpListener, err := util.NewListener(listenAddress, time.Duration(10)*time.Second)
+	if err != nil {
+		glog.Fatalf("FTP Server listener on %s error: %v", listenAddress, err)
+	}
+
+	ftpS, ftpServer_err := ftpd.NewFtpServer(ftpListener, &ftpd.FtpServerOption{
+		Filer:            filerAddress,
+		IP:               *ftpOpt.ip,
+		IpBind:           *ftpOpt.bindIp,
+		Port:             *ftpOpt.port,
+		FtpRoot:          *ftpOpt.filerRootPath,
+		BucketsPath:      filerBucketsPath,
+		GrpcDialOption:   grpcDialOption,
+		PassivePortStart: *ftpOpt.passivePortStart,
+		PassivePortStop:  *ftpOpt.passivePortStop,
+		Collection:       *ftpOpt.collection,
+		Replication:      *ftpOpt.replication,
+		DiskType:         *ftpOpt.disk,
+		Uid:              uid,
+		Gid:              gid,
+		Cipher:           cipher,
+		CacheDir:         util.ResolvePath(*ftpOpt.cacheDir),
+		CacheSizeMB:      *ftpOpt.cacheSizeMB,
+		MaxMB:            *ftpOpt.maxMB,
+		UsersFile:        util.ResolvePath(*ftpOpt.usersFile),
+		S3ConfigFile:     util.ResolvePath(*ftpOpt.s3ConfigFile),
+		TlsMode:          *ftpOpt.tlsMode,
+		TlsRequired:      *ftpOpt.tlsRequired,
+		TlsCertificate:   *ftpOpt.tlsCertificate,
+		TlsPrivateKey:    *ftpOpt.tlsPrivateKey,
+	})
+	if ftpServer_err != nil {
+		glog.Fatalf("FTP Server startup error: %v", ftpServer_err)
+	}
+
+	glog.V(0).Infof("Start Seaweed FTP Server %s at %s", util.Version(), listenAddress)
+	ftpServer := ftpserver.NewFtpServer(ftpS)
+	if err = ftpServer.ListenAndServe(); err != nil {
+		glog.Fatalf("FTP Server Fail to serve: %v", err)
+	}
+
+	return true
+
+}
@@ -0,0 +1,87 @@
+package command
+
+import (
+	"fmt"
+
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/mq/kafka_gateway"
+	"github.com/seaweedfs/seaweedfs/weed/pb"
+	"github.com/seaweedfs/seaweedfs/weed/security"
+	"github.com/seaweedfs/seaweedfs/weed/util"
+)
+
+var (
+	mqKafkaGatewayOptions MessageQueueKafkaGatewayOptions
+)
+
+type MessageQueueKafkaGatewayOptions struct {
+	brokers           *string
+	filer             *string
+	ip                *string
+	port              *int
+	namespace         *string
+	defaultPartitions *int
+}
+
+func init() {
+	cmdMqKafka.Run = runMqKafka // break init cycle
+	mqKafkaGatewayOptions.brokers = cmdMqKafka.Flag.String("brokers", "localhost:17777", "comma-separated message queue brokers")
+	mqKafkaGatewayOptions.filer = cmdMqKafka.Flag.String("filer", "localhost:8888", "filer server address, to read and save the consumer group offsets")
+	mqKafkaGatewayOptions.ip = cmdMqKafka.Flag.String("ip", util.DetectedHostAddress(), "gateway host address advertised to the kafka clients")
+	mqKafkaGatewayOptions.port = cmdMqKafka.Flag.Int("port", 9092, "kafka protocol listen port")
+	mqKafkaGatewayOptions.namespace = cmdMqKafka.Flag.String("namespace", "kafka", "message queue namespace of the kafka topics")
+	mqKafkaGatewayOptions.defaultPartitions = cmdMqKafka.Flag.Int("defaultPartitions", 4, "partition count of the topics created on first use, 0 to disable the auto creation")
+}
+
+var cmdMqKafka = &Command{
+	UsageLine: "mq.kafka [-port=9092] [-brokers=<ip:port>] [-filer=<ip:port>]",
+	Short:     "<WIP> start a kafka protocol gateway in front of the message queue brokers",
+	Long: `start a kafka protocol gateway in front of the message queue brokers
+
+	The kafka clients can produce and consume the message queue topics with the kafka wire protocol.
+	The kafka topics are the topics in the namespace, and the kafka partitions are the topic partitions ordered by range.
+	The kafka offset of a message is its timestamp in nanoseconds.
+
+	The consumer group offsets are saved via filer, and shared with the message queue subscribers of the same consumer group.
+	The members of a kafka consumer group should connect to the same gateway.
+
+	Supported APIs: ApiVersions, Metadata, Produce, Fetch, ListOffsets, FindCoordinator,
+	JoinGroup, SyncGroup, Heartbeat, LeaveGroup, OffsetCommit and OffsetFetch.
+
+`,
+}
+
+func runMqKafka(cmd *Command, args []string) bool {
+
+	util.LoadSecurityConfiguration()
+
+	return mqKafkaGatewayOptions.startKafkaGateway()
+
+}
+
+func (opt *MessageQueueKafkaGatewayOptions) startKafkaGateway() bool {
+
+	grpcDialOption := security.LoadClientTLS(util.GetViper(), "grpc.msg_broker")
+
+	gateway := kafka_gateway.NewKafkaGateway(&kafka_gateway.KafkaGatewayOption{
+		Brokers:               pb.ServerAddresses(*opt.brokers).ToAddresses(),
+		Filer:                 pb.ServerAddress(*opt.filer),
+		GrpcDialOption:        grpcDialOption,
+		Namespace:             *opt.namespace,
+		Ip:                    *opt.ip,
+		Port:                  *opt.port,
+		DefaultPartitionCount: int32(*opt.defaultPartitions),
+	})
+
+	listener, _, err := util.NewIpAndLocalListeners("", *opt.port, 0)
+	if err != nil {
+		glog.Fatalf("failed to listen on kafka port %d: %v", *opt.port, err)
+	}
+	glog.V(0).Infof("Start Seaweed Kafka Gateway %s at %s", util.Version(), fmt.Sprintf("%s:%d", *opt.ip, *opt.port))
+	if err = gateway.Serve(listener); err != nil {
+		glog.Fatalf("kafka gateway serve: %v", err)
+	}
+
+	return true
+
+}
@@ -41,8 +41,10 @@ type S3Options struct {
 	port                      *int
 	portHttps                 *int
 	portGrpc                  *int
+	portWebsite               *int
 	config                    *string
 	domainName                *string
+	websiteDomainName         *string
 	allowedOrigins            *string
 	tlsPrivateKey             *string
 	tlsCertificate            *string
@@ -56,6 +58,9 @@ type S3Options struct {
 	localFilerSocket          *string
 	dataCenter                *string
 	localSocket               *string
+	lifecycleIntervalMinutes  *int
+	storageClassDiskTypes     *string
+	trustedProxies            *string
 	certProvider              certprovider.Provider
 }
 
@@ -66,7 +71,9 @@ func init() {
 	s3StandaloneOptions.port = cmdS3.Flag.Int("port", 8333, "s3 server http listen port")
 	s3StandaloneOptions.portHttps = cmdS3.Flag.Int("port.https", 0, "s3 server https listen port")
 	s3StandaloneOptions.portGrpc = cmdS3.Flag.Int("port.grpc", 0, "s3 server grpc listen port")
+	s3StandaloneOptions.portWebsite = cmdS3.Flag.Int("port.website", 0, "s3 static website http listen port, 0 to disable")
 	s3StandaloneOptions.domainName = cmdS3.Flag.String("domainName", "", "suffix of the host name in comma separated list, {bucket}.{domainName}")
+	s3StandaloneOptions.websiteDomainName = cmdS3.Flag.String("website.domainName", "", "suffix of the website host name in comma separated list, {bucket}.{website.domainName}, otherwise the host name is the bucket name")
 	s3StandaloneOptions.allowedOrigins = cmdS3.Flag.String("allowedOrigins", "*", "comma separated list of allowed origins")
 	s3StandaloneOptions.dataCenter = cmdS3.Flag.String("dataCenter", "", "prefer to read and write to volumes in this data center")
 	s3StandaloneOptions.config = cmdS3.Flag.String("config", "", "path to the config file")
@@ -81,6 +88,9 @@ func init() {
 	s3StandaloneOptions.allowDeleteBucketNotEmpty = cmdS3.Flag.Bool("allowDeleteBucketNotEmpty", true, "allow recursive deleting all entries along with bucket")
 	s3StandaloneOptions.localFilerSocket = cmdS3.Flag.String("localFilerSocket", "", "local filer socket path")
 	s3StandaloneOptions.localSocket = cmdS3.Flag.String("localSocket", "", "default to /tmp/seaweedfs-s3-<port>.sock")
+	s3StandaloneOptions.lifecycleIntervalMinutes = cmdS3.Flag.Int("lifecycleIntervalMinutes", 60, "interval to apply bucket lifecycle rules, 0 to disable")
+	s3StandaloneOptions.storageClassDiskTypes = cmdS3.Flag.String("storageClassDiskTypes", "", "storage classes that lifecycle rules can transition objects to, and their disk types, e.g. \"STANDARD_IA=hdd,GLACIER=archive\"")
+	s3StandaloneOptions.trustedProxies = cmdS3.Flag.String("trustedProxies", "", "comma separated ip addresses or cidrs of the reverse proxies, whose X-Forwarded-For and X-Forwarded-Proto headers are used by the bucket policies")
 }
 
 var cmdS3 = &Command{
@@ -242,6 +252,10 @@ func (s3opt *S3Options) startS3Server() bool {
 		LocalFilerSocket:          localFilerSocket,
 		DataCenter:                *s3opt.dataCenter,
 		FilerGroup:                filerGroup,
+		LifecycleInterval:         time.Duration(*s3opt.lifecycleIntervalMinutes) * time.Minute,
+		StorageClassDiskTypes:     *s3opt.storageClassDiskTypes,
+		WebsiteDomainName:         *s3opt.websiteDomainName,
+		TrustedProxies:            util.StringSplit(*s3opt.trustedProxies, ","),
 	})
 	if s3ApiServer_err != nil {
 		glog.Fatalf("S3 API Server startup error: %v", s3ApiServer_err)
@@ -301,6 +315,24 @@ func (s3opt *S3Options) startS3Server() bool {
 	}
 	go grpcS.Serve(grpcL)
 
+	// starting static website server
+	if *s3opt.portWebsite > 0 {
+		websiteS := &http.Server{Handler: http.HandlerFunc(s3ApiServer.WebsiteHandler)}
+		websiteL, websiteLocalL, err := util.NewIpAndLocalListeners(*s3opt.bindIp, *s3opt.portWebsite, time.Duration(10)*time.Second)
+		if err != nil {
+			glog.Fatalf("s3 failed to listen on website port %d: %v", *s3opt.portWebsite, err)
+		}
+		glog.V(0).Infof("Start Seaweed S3 Website Server %s at http port %d", util.Version(), *s3opt.portWebsite)
+		if websiteLocalL != nil {
+			go websiteS.Serve(websiteLocalL)
+		}
+		go func() {
+			if err := websiteS.Serve(websiteL); err != nil {
+				glog.Fatalf("S3 Website Server Fail to serve: %v", err)
+			}
+		}()
+	}
+
 	if *s3opt.tlsPrivateKey != "" {
 		pemfileOptions := pemfile.Options{
 			CertFile:        *s3opt.tlsCertificate,
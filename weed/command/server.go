@@ -145,7 +145,9 @@ func init() {
 	s3Options.port = cmdServer.Flag.Int("s3.port", 8333, "s3 server http listen port")
 	s3Options.portHttps = cmdServer.Flag.Int("s3.port.https", 0, "s3 server https listen port")
 	s3Options.portGrpc = cmdServer.Flag.Int("s3.port.grpc", 0, "s3 server grpc listen port")
+	s3Options.portWebsite = cmdServer.Flag.Int("s3.port.website", 0, "s3 static website http listen port, 0 to disable")
 	s3Options.domainName = cmdServer.Flag.String("s3.domainName", "", "suffix of the host name in comma separated list, {bucket}.{domainName}")
+	s3Options.websiteDomainName = cmdServer.Flag.String("s3.website.domainName", "", "suffix of the website host name in comma separated list, {bucket}.{website.domainName}, otherwise the host name is the bucket name")
 	s3Options.allowedOrigins = cmdServer.Flag.String("s3.allowedOrigins", "*", "comma separated list of allowed origins")
 	s3Options.tlsPrivateKey = cmdServer.Flag.String("s3.key.file", "", "path to the TLS private key file")
 	s3Options.tlsCertificate = cmdServer.Flag.String("s3.cert.file", "", "path to the TLS certificate file")
@@ -156,6 +158,9 @@ func init() {
 	s3Options.allowEmptyFolder = cmdServer.Flag.Bool("s3.allowEmptyFolder", true, "allow empty folders")
 	s3Options.allowDeleteBucketNotEmpty = cmdServer.Flag.Bool("s3.allowDeleteBucketNotEmpty", true, "allow recursive deleting all entries along with bucket")
 	s3Options.localSocket = cmdServer.Flag.String("s3.localSocket", "", "default to /tmp/seaweedfs-s3-<port>.sock")
+	s3Options.lifecycleIntervalMinutes = cmdServer.Flag.Int("s3.lifecycleIntervalMinutes", 60, "interval to apply bucket lifecycle rules, 0 to disable")
+	s3Options.storageClassDiskTypes = cmdServer.Flag.String("s3.storageClassDiskTypes", "", "storage classes that lifecycle rules can transition objects to, and their disk types, e.g. \"STANDARD_IA=hdd,GLACIER=archive\"")
+	s3Options.trustedProxies = cmdServer.Flag.String("s3.trustedProxies", "", "comma separated ip addresses or cidrs of the reverse proxies, whose X-Forwarded-For and X-Forwarded-Proto headers are used by the bucket policies")
 
 	iamOptions.port = cmdServer.Flag.Int("iam.port", 8111, "iam server http listen port")
 
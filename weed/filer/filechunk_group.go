@@ -115,10 +115,9 @@ func (group *ChunkGroup) SetChunks(chunks []*filer_pb.FileChunk) error {
 const (
 	// see weedfs_file_lseek.go
 	SEEK_DATA uint32 = 3 // seek to next data after the offset
-	// SEEK_HOLE uint32 = 4 // seek to next hole after the offset
+	SEEK_HOLE uint32 = 4 // seek to next hole after the offset
 )
 
-// FIXME: needa tests
 func (group *ChunkGroup) SearchChunks(offset, fileSize int64, whence uint32) (found bool, out int64) {
 	group.sectionsLock.RLock()
 	defer group.sectionsLock.RUnlock()
@@ -136,7 +135,7 @@ func (group *ChunkGroup) doSearchChunks(offset, fileSize int64, whence uint32) (
 				continue
 			}
 			sectionStart := section.DataStartOffset(group, offset, fileSize)
-			if sectionStart == -1 {
+			if sectionStart == -1 || sectionStart >= fileSize {
 				continue
 			}
 			return true, sectionStart
@@ -144,16 +143,20 @@ func (group *ChunkGroup) doSearchChunks(offset, fileSize int64, whence uint32) (
 		return false, 0
 	} else {
 		// whence == SEEK_HOLE
-		for si := sectionIndex; si < maxSectionIndex; si++ {
+		for si := sectionIndex; si < maxSectionIndex+1; si++ {
+			offset = max(offset, int64(si)*SectionSize)
+			if offset >= fileSize {
+				break
+			}
 			section, foundSection := group.sections[si]
 			if !foundSection {
 				return true, offset
 			}
+			// the data may continue in the next section
 			holeStart := section.NextStopOffset(group, offset, fileSize)
-			if holeStart%SectionSize == 0 {
-				continue
+			if holeStart < int64(si+1)*SectionSize {
+				return true, min(holeStart, fileSize)
 			}
-			return true, holeStart
 		}
 		return true, fileSize
 	}
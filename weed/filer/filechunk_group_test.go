@@ -1,6 +1,7 @@
 package filer
 
 import (
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -34,3 +35,42 @@ func TestChunkGroup_doSearchChunks(t *testing.T) {
 		})
 	}
 }
+
+func TestChunkGroup_SearchChunksWithHoles(t *testing.T) {
+	// data in [0,100), [200,300) and [SectionSize-50, SectionSize+50), holes elsewhere
+	group, err := NewChunkGroup(nil, nil, []*filer_pb.FileChunk{
+		{FileId: "1,1", Offset: 0, Size: 100, ModifiedTsNs: 1},
+		{FileId: "1,2", Offset: 200, Size: 100, ModifiedTsNs: 2},
+		{FileId: "1,3", Offset: SectionSize - 50, Size: 100, ModifiedTsNs: 3},
+	})
+	assert.Nil(t, err)
+	fileSize := int64(SectionSize * 2)
+
+	tests := []struct {
+		offset    int64
+		whence    uint32
+		wantFound bool
+		wantOut   int64
+	}{
+		{0, SEEK_DATA, true, 0},
+		{50, SEEK_DATA, true, 50},
+		{100, SEEK_DATA, true, 200},
+		{300, SEEK_DATA, true, SectionSize - 50},
+		{SectionSize + 50, SEEK_DATA, false, 0},
+		{0, SEEK_HOLE, true, 100},
+		{150, SEEK_HOLE, true, 150},
+		{250, SEEK_HOLE, true, 300},
+		{SectionSize - 10, SEEK_HOLE, true, SectionSize + 50},
+		{SectionSize + 60, SEEK_HOLE, true, SectionSize + 60},
+	}
+	for _, tt := range tests {
+		gotFound, gotOut := group.SearchChunks(tt.offset, fileSize, tt.whence)
+		assert.Equalf(t, tt.wantFound, gotFound, "SearchChunks(%v, %v)", tt.offset, tt.whence)
+		assert.Equalf(t, tt.wantOut, gotOut, "SearchChunks(%v, %v)", tt.offset, tt.whence)
+	}
+
+	// the data up to the end of the file
+	found, out := group.SearchChunks(250, 300, SEEK_HOLE)
+	assert.True(t, found)
+	assert.Equal(t, int64(300), out)
+}
@@ -116,7 +116,7 @@ func (section *FileChunkSection) DataStartOffset(group *ChunkGroup, offset int64
 			continue
 		}
 		if offset < visible.start {
-			return offset
+			return visible.start
 		}
 		return offset
 	}
@@ -0,0 +1,33 @@
+package filer
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+)
+
+// ReadChunkFunc reads the whole data of a chunk
+type ReadChunkFunc func(chunk *filer_pb.FileChunk) ([]byte, error)
+
+// CopyChunks copies the data chunks into new chunks at the same offsets and with the same modification times,
+// so the copy does not share any chunk with the source, and deleting either one keeps the other.
+// The manifest chunks are to be resolved into their data chunks before, and the copied chunks are manifestized again.
+func CopyChunks(dataChunks []*filer_pb.FileChunk, readChunk ReadChunkFunc, saveFunc SaveDataAsChunkFunctionType) ([]*filer_pb.FileChunk, error) {
+	var copied []*filer_pb.FileChunk
+	for _, chunk := range dataChunks {
+		if chunk.IsChunkManifest {
+			return nil, fmt.Errorf("manifest chunk %s is not resolved", chunk.GetFileIdString())
+		}
+		data, err := readChunk(chunk)
+		if err != nil {
+			return nil, fmt.Errorf("read chunk %s: %v", chunk.GetFileIdString(), err)
+		}
+		newChunk, err := saveFunc(bytes.NewReader(data), "", chunk.Offset, chunk.ModifiedTsNs)
+		if err != nil {
+			return nil, fmt.Errorf("copy chunk %s: %v", chunk.GetFileIdString(), err)
+		}
+		copied = append(copied, newChunk)
+	}
+	return MaybeManifestize(saveFunc, copied)
+}
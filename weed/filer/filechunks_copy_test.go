@@ -0,0 +1,68 @@
+package filer
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+)
+
+func TestCopyChunks(t *testing.T) {
+	// the volume servers
+	stored := map[string][]byte{
+		"1,a": []byte("hello "),
+		"1,b": []byte("world"),
+	}
+	readChunk := func(chunk *filer_pb.FileChunk) ([]byte, error) {
+		data, found := stored[chunk.GetFileIdString()]
+		if !found {
+			return nil, fmt.Errorf("%s not found", chunk.GetFileIdString())
+		}
+		return data, nil
+	}
+	saveFunc := func(reader io.Reader, name string, offset int64, tsNs int64) (*filer_pb.FileChunk, error) {
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, err
+		}
+		fileId := fmt.Sprintf("2,%d", len(stored))
+		stored[fileId] = data
+		return &filer_pb.FileChunk{FileId: fileId, Offset: offset, Size: uint64(len(data)), ModifiedTsNs: tsNs}, nil
+	}
+
+	source := []*filer_pb.FileChunk{
+		{FileId: "1,a", Offset: 0, Size: 6, ModifiedTsNs: 1},
+		{FileId: "1,b", Offset: 6, Size: 5, ModifiedTsNs: 2},
+	}
+	copied, err := CopyChunks(source, readChunk, saveFunc)
+	assert.Nil(t, err)
+	assert.Len(t, copied, 2)
+	for i, chunk := range copied {
+		assert.NotEqual(t, source[i].FileId, chunk.FileId)
+		assert.Equal(t, source[i].Offset, chunk.Offset)
+		assert.Equal(t, source[i].Size, chunk.Size)
+		assert.Equal(t, source[i].ModifiedTsNs, chunk.ModifiedTsNs)
+	}
+
+	// deleting the source keeps the copy
+	for _, chunk := range source {
+		delete(stored, chunk.FileId)
+	}
+	var content []byte
+	for _, chunk := range copied {
+		data, err := readChunk(chunk)
+		assert.Nil(t, err)
+		content = append(content, data...)
+	}
+	assert.Equal(t, "hello world", string(content))
+
+	// the chunks deleted since can not be copied
+	_, err = CopyChunks(source, readChunk, saveFunc)
+	assert.NotNil(t, err)
+
+	_, err = CopyChunks([]*filer_pb.FileChunk{{FileId: "1,m", IsChunkManifest: true}}, readChunk, saveFunc)
+	assert.NotNil(t, err, "the manifest chunks are resolved before")
+}
@@ -6,6 +6,8 @@ import (
 	"os"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/seaweedfs/seaweedfs/weed/cluster/lock_manager"
@@ -52,6 +54,11 @@ type Filer struct {
 	RemoteStorage       *FilerRemoteStorage
 	Dlm                 *lock_manager.DistributedLockManager
 	MaxFilenameLength   uint32
+	DirectoryQuotas     *DirectoryQuotas
+	chunkRefLocks       [chunkRefLockCount]sync.Mutex
+	chunkRefLocker      func(key string) (unlock func())
+	hasSnapshots        atomic.Bool
+	hasSnapshotsCheckNs atomic.Int64
 }
 
 func NewFiler(masters pb.ServerDiscovery, grpcDialOption grpc.DialOption, filerHost pb.ServerAddress, filerGroup string, collection string, replication string, dataCenter string, maxFilenameLength uint32, notifyFn func()) *Filer {
@@ -64,6 +71,7 @@ func NewFiler(masters pb.ServerDiscovery, grpcDialOption grpc.DialOption, filerH
 		UniqueFilerId:       util.RandomInt32(),
 		Dlm:                 lock_manager.NewDistributedLockManager(filerHost),
 		MaxFilenameLength:   maxFilenameLength,
+		DirectoryQuotas:     NewDirectoryQuotas(),
 	}
 	if f.UniqueFilerId < 0 {
 		f.UniqueFilerId = -f.UniqueFilerId
@@ -74,6 +82,7 @@ func NewFiler(masters pb.ServerDiscovery, grpcDialOption grpc.DialOption, filerH
 	f.metaLogReplication = replication
 
 	go f.loopProcessingDeletion()
+	go f.loopPurgingTrash()
 
 	return f
 }
@@ -195,6 +204,15 @@ func (f *Filer) CreateEntry(ctx context.Context, entry *Entry, o_excl bool, isFr
 		return fmt.Errorf("entry name too long")
 	}
 
+	if !isFromOtherCluster {
+		if err := f.CheckSnapshotWritable(ctx, entry.FullPath); err != nil {
+			return err
+		}
+		if err := f.CheckTrashWritable(ctx, entry.FullPath); err != nil {
+			return err
+		}
+	}
+
 	oldEntry, _ := f.FindEntry(ctx, entry.FullPath)
 
 	/*
@@ -205,6 +223,12 @@ func (f *Filer) CreateEntry(ctx context.Context, entry *Entry, o_excl bool, isFr
 		}
 	*/
 
+	if !isFromOtherCluster {
+		if err := f.CheckDirectoryQuota(ctx, oldEntry, entry); err != nil {
+			return err
+		}
+	}
+
 	if oldEntry == nil {
 
 		if !skipCreateParentDir {
@@ -233,7 +257,7 @@ func (f *Filer) CreateEntry(ctx context.Context, entry *Entry, o_excl bool, isFr
 
 	f.NotifyUpdateEvent(ctx, oldEntry, entry, true, isFromOtherCluster, signatures)
 
-	f.deleteChunksIfNotNew(oldEntry, entry)
+	f.deleteChunksIfNotNew(ctx, oldEntry, entry)
 
 	glog.V(4).Infof("CreateEntry %s: created", entry.FullPath)
 
@@ -160,6 +160,21 @@ func (fc *FilerConf) MatchStorageRule(path string) (pathConf *filer_pb.FilerConf
 	return pathConf
 }
 
+// GetCollectionEcScheme returns the erasure coding scheme configured for the collection,
+// either on a location writing to the collection, or on the bucket of the collection
+func (fc *FilerConf) GetCollectionEcScheme(collection, bucketsPath string) (ecScheme string) {
+	fc.rules.Walk(func(key []byte, value *filer_pb.FilerConf_PathConf) bool {
+		if ecScheme == "" && value.Collection == collection {
+			ecScheme = value.EcScheme
+		}
+		return true
+	})
+	if ecScheme == "" {
+		ecScheme = fc.MatchStorageRule(fmt.Sprintf("%s/%s/", bucketsPath, collection)).EcScheme
+	}
+	return
+}
+
 func (fc *FilerConf) GetCollectionTtls(collection string) (ttls map[string]string) {
 	ttls = make(map[string]string)
 	fc.rules.Walk(func(key []byte, value *filer_pb.FilerConf_PathConf) bool {
@@ -190,6 +205,11 @@ func mergePathConf(a, b *filer_pb.FilerConf_PathConf) {
 	a.DataNode = util.Nvl(b.DataNode, a.DataNode)
 	a.DisableChunkDeletion = b.DisableChunkDeletion || a.DisableChunkDeletion
 	a.Worm = b.Worm || a.Worm
+	a.Trash = b.Trash || a.Trash
+	if b.TrashRetentionDays > 0 {
+		a.TrashRetentionDays = b.TrashRetentionDays
+	}
+	a.EcScheme = util.Nvl(b.EcScheme, a.EcScheme)
 }
 
 func (fc *FilerConf) ToProto() *filer_pb.FilerConf {
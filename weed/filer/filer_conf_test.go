@@ -47,3 +47,31 @@ func TestFilerConf(t *testing.T) {
 	assert.Equal(t, false, fc.MatchStorageRule("/buckets/other").ReadOnly)
 
 }
+
+func TestFilerConfCollectionEcScheme(t *testing.T) {
+
+	fc := NewFilerConf()
+
+	conf := &filer_pb.FilerConf{Locations: []*filer_pb.FilerConf_PathConf{
+		{
+			LocationPrefix: "/data/",
+			Collection:     "data",
+			EcScheme:       "4+2",
+		},
+		{
+			LocationPrefix: "/buckets/",
+			EcScheme:       "6+3",
+		},
+		{
+			LocationPrefix: "/buckets/large/",
+			EcScheme:       "12+4",
+		},
+	}}
+	fc.doLoadConf(conf)
+
+	assert.Equal(t, "4+2", fc.GetCollectionEcScheme("data", "/buckets"))
+	assert.Equal(t, "6+3", fc.GetCollectionEcScheme("small", "/buckets"))
+	assert.Equal(t, "12+4", fc.GetCollectionEcScheme("large", "/buckets"))
+	assert.Equal(t, "", fc.GetCollectionEcScheme("other", "/files"))
+
+}
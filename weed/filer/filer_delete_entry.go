@@ -21,6 +21,11 @@ func (f *Filer) DeleteEntryMetaAndData(ctx context.Context, p util.FullPath, isR
 	if p == "/" {
 		return nil
 	}
+	if !isFromOtherCluster {
+		if err = f.CheckSnapshotWritable(ctx, p); err != nil {
+			return err
+		}
+	}
 
 	entry, findErr := f.FindEntry(ctx, p)
 	if findErr != nil {
@@ -30,6 +35,12 @@ func (f *Filer) DeleteEntryMetaAndData(ctx context.Context, p util.FullPath, isR
 		return nil
 	}
 	isDeleteCollection := f.isBucket(entry)
+	if shouldDeleteChunks && !isDeleteCollection {
+		// keep the entry and its chunks in the trash until its retention expires
+		if moved, trashErr := f.moveToTrash(ctx, entry, isRecursive, signatures); moved || trashErr != nil {
+			return trashErr
+		}
+	}
 	if entry.IsDirectory() {
 		// delete the folder children, not including the folder itself
 		err = f.doBatchDeleteFolderMetaAndData(ctx, entry, isRecursive, ignoreRecursiveError, shouldDeleteChunks && !isDeleteCollection, isDeleteCollection, isFromOtherCluster, signatures, func(hardLinkIds []HardLinkId) error {
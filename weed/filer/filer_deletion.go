@@ -1,6 +1,7 @@
 package filer
 
 import (
+	"context"
 	"strings"
 	"time"
 
@@ -85,29 +86,20 @@ func (f *Filer) DeleteChunks(fullpath util.FullPath, chunks []*filer_pb.FileChun
 }
 
 func (f *Filer) doDeleteChunks(chunks []*filer_pb.FileChunk) {
-	for _, chunk := range chunks {
-		if !chunk.IsChunkManifest {
-			f.fileIdDeletionQueue.EnQueue(chunk.GetFileIdString())
-			continue
-		}
-		dataChunks, manifestResolveErr := ResolveOneChunkManifest(f.MasterClient.LookupFileId, chunk)
-		if manifestResolveErr != nil {
-			glog.V(0).Infof("failed to resolve manifest %s: %v", chunk.FileId, manifestResolveErr)
-		}
-		for _, dChunk := range dataChunks {
-			f.fileIdDeletionQueue.EnQueue(dChunk.GetFileIdString())
-		}
-		f.fileIdDeletionQueue.EnQueue(chunk.GetFileIdString())
+	for _, fileId := range f.releaseChunks(chunks, f.resolveManifest) {
+		f.fileIdDeletionQueue.EnQueue(fileId)
 	}
 }
 
 func (f *Filer) DeleteChunksNotRecursive(chunks []*filer_pb.FileChunk) {
 	for _, chunk := range chunks {
-		f.fileIdDeletionQueue.EnQueue(chunk.GetFileIdString())
+		if !f.releaseChunkRef(chunk.GetFileIdString()) {
+			f.fileIdDeletionQueue.EnQueue(chunk.GetFileIdString())
+		}
 	}
 }
 
-func (f *Filer) deleteChunksIfNotNew(oldEntry, newEntry *Entry) {
+func (f *Filer) deleteChunksIfNotNew(ctx context.Context, oldEntry, newEntry *Entry) {
 	var oldChunks, newChunks []*filer_pb.FileChunk
 	if oldEntry != nil {
 		oldChunks = oldEntry.GetChunks()
@@ -121,5 +113,8 @@ func (f *Filer) deleteChunksIfNotNew(oldEntry, newEntry *Entry) {
 		glog.Errorf("Failed to resolve old entry chunks when delete old entry chunks. new: %s, old: %s", newChunks, oldChunks)
 		return
 	}
+	if f.keepOverwrittenInTrash(ctx, oldEntry, toDelete) {
+		return
+	}
 	f.DeleteChunksNotRecursive(toDelete)
 }
@@ -12,6 +12,7 @@ func (f *Filer) onMetadataChangeEvent(event *filer_pb.SubscribeMetadataResponse)
 	f.maybeReloadFilerConfiguration(event)
 	f.maybeReloadRemoteStorageConfigurationAndMapping(event)
 	f.onBucketEvents(event)
+	f.onDirectoryQuotaEvent(event)
 }
 
 func (f *Filer) onBucketEvents(event *filer_pb.SubscribeMetadataResponse) {
@@ -66,6 +67,7 @@ func (f *Filer) reloadFilerConfiguration(entry *filer_pb.Entry) {
 		return
 	}
 	f.FilerConf = fc
+	f.refreshDirectoryQuotas()
 }
 
 func (f *Filer) LoadFilerConf() {
@@ -78,6 +80,7 @@ func (f *Filer) LoadFilerConf() {
 		return
 	}
 	f.FilerConf = fc
+	f.refreshDirectoryQuotas()
 }
 
 // //////////////////////////////////
@@ -0,0 +1,286 @@
+package filer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/util"
+)
+
+// Directory quotas limit the total file size and the number of entries under a directory,
+// configured by "quota_bytes" and "quota_inodes" of the location prefix in filer.conf.
+// Unlike the other path configurations, a quota is not inherited by the sub directories:
+// each quota counts all the entries under its directory, and a change is checked against all the quotas above it.
+// The usage is counted by a scan when the quota is added, and then kept up to date from the metadata events of all filers.
+// The entries kept in the trash and the snapshots are not counted.
+const (
+	MsgQuotaExceeded = "directory quota exceeded"
+)
+
+type DirectoryQuota struct {
+	Directory  util.FullPath
+	MaxBytes   int64
+	MaxInodes  int64
+	UsedBytes  int64
+	UsedInodes int64
+	IsScanned  bool
+	// the events before the scan are already counted by the scan
+	scanStartTsNs int64
+}
+
+type DirectoryQuotas struct {
+	sync.RWMutex
+	quotas map[util.FullPath]*DirectoryQuota
+}
+
+func NewDirectoryQuotas() *DirectoryQuotas {
+	return &DirectoryQuotas{
+		quotas: make(map[util.FullPath]*DirectoryQuota),
+	}
+}
+
+func quotaDirectory(locationPrefix string) util.FullPath {
+	dir := strings.TrimSuffix(locationPrefix, "/")
+	if dir == "" {
+		return "/"
+	}
+	return util.FullPath(dir)
+}
+
+// isUnderDirectory tells whether the path is a descendant of the directory
+func isUnderDirectory(p, dir util.FullPath) bool {
+	if dir == "/" {
+		return p != "/"
+	}
+	return strings.HasPrefix(string(p), string(dir)+"/")
+}
+
+func entryBytes(entry *Entry) int64 {
+	if entry == nil || entry.IsDirectory() {
+		return 0
+	}
+	return int64(entry.Size())
+}
+
+func pbEntryBytes(entry *filer_pb.Entry) int64 {
+	if entry == nil || entry.IsDirectory {
+		return 0
+	}
+	return int64(maxUint64(FileSize(entry), uint64(len(entry.Content))))
+}
+
+// refreshDirectoryQuotas follows the quotas in filer.conf, and starts counting the usage of the new quota directories
+func (f *Filer) refreshDirectoryQuotas() {
+	limits := make(map[util.FullPath]*filer_pb.FilerConf_PathConf)
+	f.FilerConf.rules.Walk(func(key []byte, value *filer_pb.FilerConf_PathConf) bool {
+		if value.QuotaBytes > 0 || value.QuotaInodes > 0 {
+			limits[quotaDirectory(string(key))] = value
+		}
+		return true
+	})
+	for _, quota := range f.DirectoryQuotas.setLimits(limits) {
+		go f.scanDirectoryQuota(quota)
+	}
+}
+
+// setLimits updates the quota limits, and returns the newly added quotas
+func (dq *DirectoryQuotas) setLimits(limits map[util.FullPath]*filer_pb.FilerConf_PathConf) (added []*DirectoryQuota) {
+	dq.Lock()
+	defer dq.Unlock()
+	for dir := range dq.quotas {
+		if _, found := limits[dir]; !found {
+			delete(dq.quotas, dir)
+		}
+	}
+	for dir, limit := range limits {
+		quota, found := dq.quotas[dir]
+		if !found {
+			quota = &DirectoryQuota{Directory: dir}
+			dq.quotas[dir] = quota
+			added = append(added, quota)
+		}
+		quota.MaxBytes, quota.MaxInodes = limit.QuotaBytes, limit.QuotaInodes
+	}
+	return
+}
+
+func (f *Filer) scanDirectoryQuota(quota *DirectoryQuota) {
+	scanStartTsNs := time.Now().UnixNano()
+	usedBytes, usedInodes, err := f.collectDirectoryUsage(context.Background(), quota.Directory)
+	if err != nil {
+		glog.Errorf("scan usage of quota directory %s: %v", quota.Directory, err)
+		return
+	}
+
+	f.DirectoryQuotas.Lock()
+	defer f.DirectoryQuotas.Unlock()
+	quota.UsedBytes, quota.UsedInodes = usedBytes, usedInodes
+	quota.IsScanned, quota.scanStartTsNs = true, scanStartTsNs
+	glog.V(0).Infof("quota directory %s uses %d bytes in %d entries", quota.Directory, usedBytes, usedInodes)
+}
+
+// collectDirectoryUsage counts the file size and the entries under the directory, not including the trash and the snapshots
+func (f *Filer) collectDirectoryUsage(ctx context.Context, dir util.FullPath) (usedBytes, usedInodes int64, err error) {
+	lastFileName := ""
+	for {
+		entries, hasMore, listErr := f.ListDirectoryEntries(ctx, dir, lastFileName, false, PaginationSize, "", "", "")
+		if listErr != nil {
+			return 0, 0, fmt.Errorf("list %s: %v", dir, listErr)
+		}
+		for _, entry := range entries {
+			lastFileName = entry.Name()
+			if f.isInTrash(entry.FullPath) || IsInSnapshot(entry.FullPath) {
+				continue
+			}
+			usedBytes += entryBytes(entry)
+			usedInodes++
+			if entry.IsDirectory() {
+				subBytes, subInodes, subErr := f.collectDirectoryUsage(ctx, entry.FullPath)
+				if subErr != nil {
+					return 0, 0, subErr
+				}
+				usedBytes += subBytes
+				usedInodes += subInodes
+			}
+		}
+		if !hasMore {
+			return
+		}
+	}
+}
+
+// onDirectoryQuotaEvent counts the change of one entry in the usage of the quota directories above it
+func (f *Filer) onDirectoryQuotaEvent(event *filer_pb.SubscribeMetadataResponse) {
+	message := event.EventNotification
+	dq := f.DirectoryQuotas
+	dq.Lock()
+	defer dq.Unlock()
+	if len(dq.quotas) == 0 {
+		return
+	}
+	if message.OldEntry != nil {
+		if oldPath := util.NewFullPath(event.Directory, message.OldEntry.Name); !f.isInTrash(oldPath) && !IsInSnapshot(oldPath) {
+			dq.addUsage(oldPath, -pbEntryBytes(message.OldEntry), -1, event.TsNs)
+		}
+	}
+	if message.NewEntry != nil {
+		newParentPath := message.NewParentPath
+		if newParentPath == "" {
+			newParentPath = event.Directory
+		}
+		if newPath := util.NewFullPath(newParentPath, message.NewEntry.Name); !f.isInTrash(newPath) && !IsInSnapshot(newPath) {
+			dq.addUsage(newPath, pbEntryBytes(message.NewEntry), 1, event.TsNs)
+		}
+	}
+}
+
+func (dq *DirectoryQuotas) addUsage(p util.FullPath, deltaBytes, deltaInodes int64, tsNs int64) {
+	for dir, quota := range dq.quotas {
+		if !quota.IsScanned || tsNs <= quota.scanStartTsNs || !isUnderDirectory(p, dir) {
+			continue
+		}
+		quota.UsedBytes += deltaBytes
+		quota.UsedInodes += deltaInodes
+	}
+}
+
+// quotaDirectories lists the scanned quota directories above the path
+func (dq *DirectoryQuotas) quotaDirectories(p util.FullPath) (dirs []util.FullPath) {
+	dq.RLock()
+	defer dq.RUnlock()
+	for dir, quota := range dq.quotas {
+		if quota.IsScanned && isUnderDirectory(p, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	return
+}
+
+func (dq *DirectoryQuotas) check(dirs []util.FullPath, deltaBytes, deltaInodes int64) error {
+	dq.RLock()
+	defer dq.RUnlock()
+	for _, dir := range dirs {
+		quota, found := dq.quotas[dir]
+		if !found {
+			continue
+		}
+		if quota.MaxBytes > 0 && deltaBytes > 0 && quota.UsedBytes+deltaBytes > quota.MaxBytes {
+			return fmt.Errorf("%s: %s", MsgQuotaExceeded, dir)
+		}
+		if quota.MaxInodes > 0 && deltaInodes > 0 && quota.UsedInodes+deltaInodes > quota.MaxInodes {
+			return fmt.Errorf("%s: %s", MsgQuotaExceeded, dir)
+		}
+	}
+	return nil
+}
+
+// CheckDirectoryQuota checks whether creating or updating the entry exceeds the quotas above it.
+// The moves are checked as a whole by CheckMoveQuota.
+func (f *Filer) CheckDirectoryQuota(ctx context.Context, oldEntry, newEntry *Entry) error {
+	if ctx.Value("OP") == "MV" || f.isInTrash(newEntry.FullPath) || IsInSnapshot(newEntry.FullPath) {
+		return nil
+	}
+	deltaBytes := entryBytes(newEntry) - entryBytes(oldEntry)
+	var deltaInodes int64
+	if oldEntry == nil {
+		deltaInodes = 1
+	}
+	if deltaBytes <= 0 && deltaInodes <= 0 {
+		return nil
+	}
+	return f.DirectoryQuotas.check(f.DirectoryQuotas.quotaDirectories(newEntry.FullPath), deltaBytes, deltaInodes)
+}
+
+// CheckMoveQuota checks whether moving the entry, with its children, exceeds the quotas above the new path,
+// not counting the quotas already including the entry
+func (f *Filer) CheckMoveQuota(ctx context.Context, entry *Entry, newPath util.FullPath) error {
+	if f.isInTrash(newPath) {
+		return nil
+	}
+	var dirs []util.FullPath
+	for _, dir := range f.DirectoryQuotas.quotaDirectories(newPath) {
+		if f.isInTrash(entry.FullPath) || !isUnderDirectory(entry.FullPath, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	if len(dirs) == 0 {
+		return nil
+	}
+
+	usedBytes, usedInodes := entryBytes(entry), int64(1)
+	if entry.IsDirectory() {
+		subBytes, subInodes, err := f.collectDirectoryUsage(ctx, entry.FullPath)
+		if err != nil {
+			return err
+		}
+		usedBytes += subBytes
+		usedInodes += subInodes
+	}
+	return f.DirectoryQuotas.check(dirs, usedBytes, usedInodes)
+}
+
+// List returns the quotas and their usage, ordered by the directory
+func (dq *DirectoryQuotas) List() (quotas []*filer_pb.DirectoryQuota) {
+	dq.RLock()
+	defer dq.RUnlock()
+	for _, quota := range dq.quotas {
+		quotas = append(quotas, &filer_pb.DirectoryQuota{
+			Directory:  string(quota.Directory),
+			MaxBytes:   quota.MaxBytes,
+			MaxInodes:  quota.MaxInodes,
+			UsedBytes:  quota.UsedBytes,
+			UsedInodes: quota.UsedInodes,
+			IsScanned:  quota.IsScanned,
+		})
+	}
+	sort.Slice(quotas, func(i, j int) bool {
+		return quotas[i].Directory < quotas[j].Directory
+	})
+	return
+}
@@ -0,0 +1,121 @@
+package filer
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDirectoryQuota(t *testing.T) {
+	f := &Filer{
+		DirectoryQuotas: NewDirectoryQuotas(),
+		FilerConf:       NewFilerConf(),
+		DirBucketsPath:  "/buckets",
+	}
+	f.FilerConf.doLoadConf(&filer_pb.FilerConf{Locations: []*filer_pb.FilerConf_PathConf{
+		{LocationPrefix: "/home/user1/", Trash: true},
+	}})
+	f.DirectoryQuotas.setLimits(map[util.FullPath]*filer_pb.FilerConf_PathConf{
+		"/home":       {QuotaInodes: 100},
+		"/home/user1": {QuotaBytes: 1000, QuotaInodes: 3},
+	})
+	for _, quota := range f.DirectoryQuotas.quotas {
+		quota.IsScanned, quota.scanStartTsNs = true, 1
+	}
+
+	newFile := func(p util.FullPath, size uint64) *Entry {
+		return &Entry{FullPath: p, Attr: Attr{FileSize: size}}
+	}
+	create := func(dir string, name string, size uint64) {
+		f.onDirectoryQuotaEvent(&filer_pb.SubscribeMetadataResponse{
+			Directory: dir,
+			EventNotification: &filer_pb.EventNotification{
+				NewEntry: newFile(util.NewFullPath(dir, name), size).ToProtoEntry(),
+			},
+			TsNs: 2,
+		})
+	}
+	ctx := context.Background()
+
+	create("/home/user1", "a.txt", 600)
+	create("/home/user1", "b.txt", 300)
+	create("/home", "user2.txt", 5000)
+	create("/other", "c.txt", 5000)
+	assert.Equal(t, []*filer_pb.DirectoryQuota{
+		{Directory: "/home", MaxInodes: 100, UsedBytes: 5900, UsedInodes: 3, IsScanned: true},
+		{Directory: "/home/user1", MaxBytes: 1000, MaxInodes: 3, UsedBytes: 900, UsedInodes: 2, IsScanned: true},
+	}, f.DirectoryQuotas.List())
+
+	// bytes
+	err := f.CheckDirectoryQuota(ctx, nil, newFile("/home/user1/d.txt", 200))
+	assert.True(t, err != nil && strings.Contains(err.Error(), MsgQuotaExceeded), "%v", err)
+	assert.Nil(t, f.CheckDirectoryQuota(ctx, nil, newFile("/home/user1/d.txt", 100)))
+	assert.Nil(t, f.CheckDirectoryQuota(ctx, newFile("/home/user1/a.txt", 600), newFile("/home/user1/a.txt", 700)))
+	assert.NotNil(t, f.CheckDirectoryQuota(ctx, newFile("/home/user1/a.txt", 600), newFile("/home/user1/a.txt", 800)))
+	assert.Nil(t, f.CheckDirectoryQuota(ctx, nil, newFile("/home/user2/d.txt", 1<<30)), "quotas are not inherited")
+	assert.Nil(t, f.CheckDirectoryQuota(context.WithValue(ctx, "OP", "MV"), nil, newFile("/home/user1/d.txt", 200)))
+
+	// inodes
+	create("/home/user1", "c.txt", 0)
+	assert.NotNil(t, f.CheckDirectoryQuota(ctx, nil, newFile("/home/user1/d.txt", 0)))
+	assert.Nil(t, f.CheckDirectoryQuota(ctx, nil, newFile("/home/user1/.trash/1/d.txt", 5000)), "the trash is not counted")
+	assert.NotNil(t, f.CheckDirectoryQuota(ctx, nil, newFile("/home/user1/docs/.trash/d.txt", 0)), "only the configured trash is not counted")
+	assert.NotNil(t, f.CheckMoveQuota(ctx, newFile("/home/user2/a.txt", 0), "/home/user1/docs/.trash/a.txt"))
+
+	// a rename out of the quota directory, and a deletion
+	f.onDirectoryQuotaEvent(&filer_pb.SubscribeMetadataResponse{
+		Directory: "/home/user1",
+		EventNotification: &filer_pb.EventNotification{
+			OldEntry:      newFile("/home/user1/a.txt", 600).ToProtoEntry(),
+			NewEntry:      newFile("/home/user2/a.txt", 600).ToProtoEntry(),
+			NewParentPath: "/home/user2",
+		},
+		TsNs: 3,
+	})
+	f.onDirectoryQuotaEvent(&filer_pb.SubscribeMetadataResponse{
+		Directory: "/home/user1",
+		EventNotification: &filer_pb.EventNotification{
+			OldEntry: newFile("/home/user1/c.txt", 0).ToProtoEntry(),
+		},
+		TsNs: 4,
+	})
+	assert.Equal(t, []*filer_pb.DirectoryQuota{
+		{Directory: "/home", MaxInodes: 100, UsedBytes: 5900, UsedInodes: 3, IsScanned: true},
+		{Directory: "/home/user1", MaxBytes: 1000, MaxInodes: 3, UsedBytes: 300, UsedInodes: 1, IsScanned: true},
+	}, f.DirectoryQuotas.List())
+
+	// moving a file into the quota directory
+	assert.NotNil(t, f.CheckMoveQuota(ctx, newFile("/home/user2/a.txt", 800), "/home/user1/a.txt"))
+	assert.Nil(t, f.CheckMoveQuota(ctx, newFile("/home/user2/a.txt", 600), "/home/user1/a.txt"))
+
+	// the events counted by the scan
+	f.onDirectoryQuotaEvent(&filer_pb.SubscribeMetadataResponse{
+		Directory: "/home/user1",
+		EventNotification: &filer_pb.EventNotification{
+			NewEntry: (&Entry{FullPath: "/home/user1/dir", Attr: Attr{Mode: os.ModeDir}}).ToProtoEntry(),
+		},
+		TsNs: 1,
+	})
+	assert.Equal(t, int64(1), f.DirectoryQuotas.quotas["/home/user1"].UsedInodes)
+
+	// removed from filer.conf
+	f.DirectoryQuotas.setLimits(map[util.FullPath]*filer_pb.FilerConf_PathConf{
+		"/home": {QuotaInodes: 100},
+	})
+	assert.Nil(t, f.CheckDirectoryQuota(ctx, nil, newFile("/home/user1/d.txt", 5000)))
+	assert.Equal(t, 1, len(f.DirectoryQuotas.List()))
+}
+
+func TestQuotaDirectory(t *testing.T) {
+	assert.Equal(t, util.FullPath("/home/user1"), quotaDirectory("/home/user1/"))
+	assert.Equal(t, util.FullPath("/"), quotaDirectory("/"))
+	assert.True(t, isUnderDirectory("/home/user1/a", "/home/user1"))
+	assert.False(t, isUnderDirectory("/home/user1", "/home/user1"))
+	assert.False(t, isUnderDirectory("/home/user10/a", "/home/user1"))
+	assert.True(t, isUnderDirectory("/a", "/"))
+}
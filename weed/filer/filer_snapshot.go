@@ -0,0 +1,397 @@
+package filer
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/seaweedfs/seaweedfs/weed/cluster"
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/util"
+)
+
+// A snapshot clones a directory tree into <dir>/.snapshots/<name>, copying only the entries.
+// The snapshot entries share the chunks of the original entries, and are read-only.
+// Each shared chunk has a reference count in the filer store, the number of the entries referencing it
+// besides the first one. Deleting an entry referencing a shared chunk only decreases the count,
+// and the chunk is deleted by the last entry referencing it.
+// The filers sharing the store update a reference count under the distributed lock of its key.
+// The snapshots, the trash folders and the nested snapshot folders are not included in a snapshot.
+// The .snapshots folders are created and removed by the snapshot commands, and marked as such,
+// so the folders named .snapshots before the snapshots were supported are left writable.
+const (
+	SnapshotFolderName  = ".snapshots"
+	MsgReadOnlySnapshot = "read-only snapshot"
+	chunkRefKeyPrefix   = "chunk.ref."
+	snapshotFolderKey   = "Seaweed-Snapshot-Folder"
+	// set once the first snapshot is created, until then the chunk deletions skip the reference counts
+	hasSnapshotsKey = "snapshot.exists"
+	// how often the filers read hasSnapshotsKey, until it is set
+	hasSnapshotsCheckInterval = 10 * time.Second
+	chunkRefLockCount         = 64
+)
+
+// IsInSnapshot tells whether the path is a .snapshots folder or inside one
+func IsInSnapshot(p util.FullPath) bool {
+	return snapshotFolderOf(p) != ""
+}
+
+// snapshotFolderOf returns the first .snapshots folder in the path, or empty
+func snapshotFolderOf(p util.FullPath) util.FullPath {
+	s := string(p)
+	for i := 0; ; {
+		t := strings.Index(s[i:], "/"+SnapshotFolderName)
+		if t < 0 {
+			return ""
+		}
+		end := i + t + 1 + len(SnapshotFolderName)
+		if end == len(s) || s[end] == '/' {
+			return util.FullPath(s[:end])
+		}
+		i = end
+	}
+}
+
+// CheckSnapshotWritable rejects changing a .snapshots folder or the path inside one,
+// except when creating or deleting a whole snapshot, or moving or trashing a directory including snapshots
+func (f *Filer) CheckSnapshotWritable(ctx context.Context, p util.FullPath) error {
+	snapshotFolder := snapshotFolderOf(p)
+	if snapshotFolder == "" || f.isInTrash(p) {
+		return nil
+	}
+	if op := ctx.Value("OP"); op == "SNAPSHOT" || op == "MV" {
+		return nil
+	}
+	entry, err := f.FindEntry(ctx, snapshotFolder)
+	if err != nil && err != filer_pb.ErrNotFound {
+		return fmt.Errorf("find %s: %v", snapshotFolder, err)
+	}
+	if entry != nil && !isSnapshotFolder(entry) {
+		return nil
+	}
+	return fmt.Errorf("%s: %s", MsgReadOnlySnapshot, p)
+}
+
+// CheckSnapshotMove rejects moving an entry into or out of a snapshot, or moving a .snapshots folder
+func (f *Filer) CheckSnapshotMove(ctx context.Context, oldPath, newPath util.FullPath) error {
+	if err := f.CheckSnapshotWritable(ctx, oldPath); err != nil {
+		return err
+	}
+	return f.CheckSnapshotWritable(ctx, newPath)
+}
+
+func isSnapshotFolder(entry *Entry) bool {
+	_, found := entry.Extended[snapshotFolderKey]
+	return found
+}
+
+func snapshotDirectory(dir util.FullPath, name string) (util.FullPath, error) {
+	if name == "" || name == "." || name == ".." || strings.Contains(name, "/") {
+		return "", fmt.Errorf("invalid snapshot name %q", name)
+	}
+	if IsInSnapshot(dir) || IsInTrash(dir) {
+		return "", fmt.Errorf("can not snapshot %s", dir)
+	}
+	return dir.Child(SnapshotFolderName).Child(name), nil
+}
+
+// CreateSnapshot clones the directory tree as a read-only snapshot, and returns the number of the copied entries
+func (f *Filer) CreateSnapshot(ctx context.Context, dir util.FullPath, name string) (snapshotDir util.FullPath, count int64, err error) {
+	if snapshotDir, err = snapshotDirectory(dir, name); err != nil {
+		return
+	}
+	entry, err := f.FindEntry(ctx, dir)
+	if err != nil {
+		return "", 0, fmt.Errorf("find %s: %v", dir, err)
+	}
+	if !entry.IsDirectory() {
+		return "", 0, fmt.Errorf("%s is not a directory", dir)
+	}
+	if _, findErr := f.FindEntry(ctx, snapshotDir); findErr == nil {
+		return "", 0, fmt.Errorf("snapshot %s already exists", snapshotDir)
+	}
+
+	ctx = context.WithValue(ctx, "OP", "SNAPSHOT")
+	if err = f.enableSnapshots(ctx); err != nil {
+		return "", 0, err
+	}
+	if err = f.ensureSnapshotFolder(ctx, entry); err != nil {
+		return "", 0, err
+	}
+	count, err = f.copyToSnapshot(ctx, entry, snapshotDir)
+	if err != nil {
+		return "", count, fmt.Errorf("snapshot %s: %v", dir, err)
+	}
+	glog.V(0).Infof("snapshot %s to %s with %d entries", dir, snapshotDir, count)
+	return
+}
+
+func (f *Filer) copyToSnapshot(ctx context.Context, entry *Entry, newPath util.FullPath) (count int64, err error) {
+	newEntry := entry.ShallowClone()
+	newEntry.FullPath = newPath
+	newEntry.Mode &^= 0222
+	// the hard links are copied as separate files, not following the later changes
+	newEntry.HardLinkId, newEntry.HardLinkCounter = nil, 0
+	if err = f.referenceChunks(ctx, newEntry.GetChunks(), f.resolveManifest); err != nil {
+		return
+	}
+	if err = f.CreateEntry(ctx, newEntry, true, false, nil, false, f.MaxFilenameLength); err != nil {
+		// the chunks are still used by the original entry
+		f.releaseChunks(newEntry.GetChunks(), f.resolveManifest)
+		return 0, fmt.Errorf("create %s: %v", newPath, err)
+	}
+	count++
+
+	if !entry.IsDirectory() {
+		return
+	}
+	lastFileName := ""
+	for {
+		entries, hasMore, listErr := f.ListDirectoryEntries(ctx, entry.FullPath, lastFileName, false, PaginationSize, "", "", "")
+		if listErr != nil {
+			return count, fmt.Errorf("list %s: %v", entry.FullPath, listErr)
+		}
+		for _, sub := range entries {
+			lastFileName = sub.Name()
+			if sub.Name() == SnapshotFolderName || sub.Name() == TrashFolderName {
+				continue
+			}
+			subCount, subErr := f.copyToSnapshot(ctx, sub, newPath.Child(sub.Name()))
+			count += subCount
+			if subErr != nil {
+				return count, subErr
+			}
+		}
+		if !hasMore {
+			return
+		}
+	}
+}
+
+// ensureSnapshotFolder creates the .snapshots folder of the directory, marked as a snapshot folder
+func (f *Filer) ensureSnapshotFolder(ctx context.Context, dirEntry *Entry) error {
+	folder := dirEntry.FullPath.Child(SnapshotFolderName)
+	entry, err := f.FindEntry(ctx, folder)
+	if err == nil {
+		if !isSnapshotFolder(entry) {
+			return fmt.Errorf("%s is not a snapshot folder", folder)
+		}
+		return nil
+	}
+	if err != filer_pb.ErrNotFound {
+		return fmt.Errorf("find %s: %v", folder, err)
+	}
+	now := time.Now()
+	return f.CreateEntry(ctx, &Entry{
+		FullPath: folder,
+		Attr: Attr{
+			Mtime:  now,
+			Crtime: now,
+			Mode:   os.ModeDir | 0555,
+			Uid:    dirEntry.Uid,
+			Gid:    dirEntry.Gid,
+		},
+		Extended: map[string][]byte{
+			snapshotFolderKey: []byte("1"),
+		},
+	}, true, false, nil, false, f.MaxFilenameLength)
+}
+
+// DeleteSnapshot deletes the snapshot, and the chunks only referenced by it.
+// The .snapshots folder is removed with the last snapshot.
+func (f *Filer) DeleteSnapshot(ctx context.Context, dir util.FullPath, name string) error {
+	snapshotDir, err := snapshotDirectory(dir, name)
+	if err != nil {
+		return err
+	}
+	ctx = context.WithValue(ctx, "OP", "SNAPSHOT")
+	if err = f.DeleteEntryMetaAndData(ctx, snapshotDir, true, false, true, false, nil, 0); err != nil {
+		return err
+	}
+	folder := dir.Child(SnapshotFolderName)
+	if entries, _, listErr := f.ListDirectoryEntries(ctx, folder, "", false, 1, "", "", ""); listErr == nil && len(entries) == 0 {
+		if err = f.DeleteEntryMetaAndData(ctx, folder, false, false, false, false, nil, 0); err != nil {
+			glog.V(0).Infof("delete empty %s: %v", folder, err)
+		}
+	}
+	return nil
+}
+
+// enableSnapshots marks the snapshots as used, for all the filers to count the chunk references.
+// The first snapshot waits until the other filers have read the mark, so no chunk can be deleted without counting its references.
+func (f *Filer) enableSnapshots(ctx context.Context) error {
+	if f.hasSnapshots.Load() {
+		return nil
+	}
+	if _, err := f.Store.KvGet(ctx, []byte(hasSnapshotsKey)); err == nil {
+		f.hasSnapshots.Store(true)
+		return nil
+	} else if err != ErrKvNotFound {
+		return fmt.Errorf("read %s: %v", hasSnapshotsKey, err)
+	}
+	if err := f.Store.KvPut(ctx, []byte(hasSnapshotsKey), []byte("1")); err != nil {
+		return fmt.Errorf("write %s: %v", hasSnapshotsKey, err)
+	}
+	f.hasSnapshots.Store(true)
+	glog.V(0).Infof("enable the chunk references for the first snapshot")
+	time.Sleep(2 * hasSnapshotsCheckInterval)
+	return nil
+}
+
+// snapshotsExist tells whether any snapshot was created, re-reading the mark from the filer store once in a while
+func (f *Filer) snapshotsExist() bool {
+	if f.hasSnapshots.Load() {
+		return true
+	}
+	now := time.Now().UnixNano()
+	lastCheck := f.hasSnapshotsCheckNs.Load()
+	if now-lastCheck < int64(hasSnapshotsCheckInterval) || !f.hasSnapshotsCheckNs.CompareAndSwap(lastCheck, now) {
+		return false
+	}
+	_, err := f.Store.KvGet(context.Background(), []byte(hasSnapshotsKey))
+	if err == nil {
+		f.hasSnapshots.Store(true)
+		return true
+	}
+	if err != ErrKvNotFound && err != ErrKvNotImplemented {
+		// count the references anyway, rather than deleting the data of a snapshot
+		glog.Errorf("read %s: %v", hasSnapshotsKey, err)
+		f.hasSnapshotsCheckNs.Store(lastCheck)
+		return true
+	}
+	return false
+}
+
+func chunkRefKey(fileId string) []byte {
+	return []byte(chunkRefKeyPrefix + fileId)
+}
+
+// lockChunkRef locks the reference count of the chunk in this filer, and then across the filers sharing the store
+func (f *Filer) lockChunkRef(fileId string) (unlock func()) {
+	h := fnv.New32a()
+	h.Write([]byte(fileId))
+	lock := &f.chunkRefLocks[h.Sum32()%chunkRefLockCount]
+	lock.Lock()
+
+	locker := f.chunkRefLocker
+	if locker == nil {
+		locker = f.lockDistributed
+	}
+	unlockDistributed := locker(string(chunkRefKey(fileId)))
+	return func() {
+		unlockDistributed()
+		lock.Unlock()
+	}
+}
+
+// lockDistributed takes the lock of the key from the filer owning it
+func (f *Filer) lockDistributed(key string) (unlock func()) {
+	if f.Dlm == nil {
+		return func() {}
+	}
+	lock := cluster.NewLockClient(f.GrpcDialOption, f.Dlm.Host).NewShortLivedLock(key, string(f.Dlm.Host))
+	return func() {
+		if err := lock.StopShortLivedLock(); err != nil {
+			glog.Warningf("unlock %s: %v", key, err)
+		}
+	}
+}
+
+// resolveManifestFunc lists the chunks in a manifest chunk
+type resolveManifestFunc func(chunk *filer_pb.FileChunk) ([]*filer_pb.FileChunk, error)
+
+func (f *Filer) resolveManifest(chunk *filer_pb.FileChunk) ([]*filer_pb.FileChunk, error) {
+	return ResolveOneChunkManifest(f.MasterClient.GetLookupFileIdFunction(), chunk)
+}
+
+// referenceChunks adds one reference to the chunks, including the chunks listed in the manifest chunks
+func (f *Filer) referenceChunks(ctx context.Context, chunks []*filer_pb.FileChunk, resolveManifest resolveManifestFunc) error {
+	for _, chunk := range chunks {
+		if chunk.IsChunkManifest {
+			dataChunks, err := resolveManifest(chunk)
+			if err != nil {
+				return fmt.Errorf("resolve manifest chunk %s: %v", chunk.GetFileIdString(), err)
+			}
+			if err = f.referenceChunks(ctx, dataChunks, resolveManifest); err != nil {
+				return err
+			}
+		}
+		if err := f.addChunkRef(ctx, chunk.GetFileIdString()); err != nil {
+			return fmt.Errorf("reference chunk %s: %v", chunk.GetFileIdString(), err)
+		}
+	}
+	return nil
+}
+
+// releaseChunks releases one reference to the chunks, including the chunks listed in the manifest chunks,
+// and returns the chunks not referenced by other entries, to be deleted
+func (f *Filer) releaseChunks(chunks []*filer_pb.FileChunk, resolveManifest resolveManifestFunc) (toDelete []string) {
+	for _, chunk := range chunks {
+		if chunk.IsChunkManifest {
+			dataChunks, err := resolveManifest(chunk)
+			if err != nil {
+				glog.V(0).Infof("failed to resolve manifest %s: %v", chunk.GetFileIdString(), err)
+			}
+			toDelete = append(toDelete, f.releaseChunks(dataChunks, resolveManifest)...)
+		}
+		// the chunks shared with a snapshot are deleted with the last entry referencing them
+		if !f.releaseChunkRef(chunk.GetFileIdString()) {
+			toDelete = append(toDelete, chunk.GetFileIdString())
+		}
+	}
+	return
+}
+
+func (f *Filer) addChunkRef(ctx context.Context, fileId string) error {
+	defer f.lockChunkRef(fileId)()
+
+	key := chunkRefKey(fileId)
+	value, err := f.Store.KvGet(ctx, key)
+	if err != nil && err != ErrKvNotFound {
+		return err
+	}
+	var refs uint64
+	if len(value) == 8 {
+		refs = util.BytesToUint64(value)
+	}
+	value = make([]byte, 8)
+	util.Uint64toBytes(value, refs+1)
+	return f.Store.KvPut(ctx, key, value)
+}
+
+// releaseChunkRef releases one reference to the chunk, and tells whether the chunk is still referenced by other entries
+func (f *Filer) releaseChunkRef(fileId string) (isShared bool) {
+	if !f.snapshotsExist() {
+		return false
+	}
+
+	defer f.lockChunkRef(fileId)()
+
+	ctx := context.Background()
+	key := chunkRefKey(fileId)
+	value, err := f.Store.KvGet(ctx, key)
+	if err == ErrKvNotFound || err == ErrKvNotImplemented || err == nil && len(value) != 8 {
+		return false
+	}
+	if err != nil {
+		// keep the chunk, rather than deleting the data of a snapshot
+		glog.Errorf("read reference count of chunk %s: %v", fileId, err)
+		return true
+	}
+
+	refs := util.BytesToUint64(value) - 1
+	if refs == 0 {
+		err = f.Store.KvDelete(ctx, key)
+	} else {
+		util.Uint64toBytes(value, refs)
+		err = f.Store.KvPut(ctx, key, value)
+	}
+	if err != nil {
+		glog.Errorf("release reference of chunk %s: %v", fileId, err)
+	}
+	return true
+}
@@ -0,0 +1,231 @@
+package filer
+
+import (
+	"context"
+	"os"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/seaweedfs/seaweedfs/weed/cluster/lock_manager"
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/util"
+	"github.com/stretchr/testify/assert"
+)
+
+// snapshotTestStore keeps the entries and the key values in memory
+type snapshotTestStore struct {
+	FilerStore
+	sync.Mutex
+	entries map[util.FullPath]*Entry
+	kv      map[string][]byte
+	// widens the window between reading and writing back a reference count
+	kvGetDelay time.Duration
+}
+
+func newSnapshotTestFiler() (*Filer, *snapshotTestStore) {
+	store := &snapshotTestStore{
+		entries: make(map[util.FullPath]*Entry),
+		kv:      make(map[string][]byte),
+	}
+	return &Filer{Store: NewFilerStoreWrapper(store)}, store
+}
+
+func (store *snapshotTestStore) GetName() string { return "memory" }
+func (store *snapshotTestStore) InsertEntry(ctx context.Context, entry *Entry) error {
+	store.Lock()
+	defer store.Unlock()
+	store.entries[entry.FullPath] = entry
+	return nil
+}
+func (store *snapshotTestStore) FindEntry(ctx context.Context, p util.FullPath) (*Entry, error) {
+	store.Lock()
+	defer store.Unlock()
+	if entry, found := store.entries[p]; found {
+		return entry, nil
+	}
+	return nil, filer_pb.ErrNotFound
+}
+func (store *snapshotTestStore) KvPut(ctx context.Context, key []byte, value []byte) error {
+	store.Lock()
+	defer store.Unlock()
+	store.kv[string(key)] = append([]byte(nil), value...)
+	return nil
+}
+func (store *snapshotTestStore) KvGet(ctx context.Context, key []byte) ([]byte, error) {
+	store.Lock()
+	value, found := store.kv[string(key)]
+	store.Unlock()
+	time.Sleep(store.kvGetDelay)
+	if found {
+		return append([]byte(nil), value...), nil
+	}
+	return nil, ErrKvNotFound
+}
+func (store *snapshotTestStore) KvDelete(ctx context.Context, key []byte) error {
+	store.Lock()
+	defer store.Unlock()
+	delete(store.kv, string(key))
+	return nil
+}
+
+func TestSnapshotWritable(t *testing.T) {
+	ctx := context.Background()
+	f, store := newSnapshotTestFiler()
+	store.InsertEntry(ctx, &Entry{FullPath: "/data/.snapshots", Attr: Attr{Mode: os.ModeDir | 0555}, Extended: map[string][]byte{snapshotFolderKey: []byte("1")}})
+
+	assert.Nil(t, f.CheckSnapshotWritable(ctx, "/data/file"))
+	assert.Nil(t, f.CheckSnapshotWritable(ctx, "/data/.snapshots.old/file"))
+	assert.NotNil(t, f.CheckSnapshotWritable(ctx, "/data/.snapshots"), "the snapshot folder is created and removed by the snapshot commands")
+	assert.NotNil(t, f.CheckSnapshotWritable(ctx, "/data/.snapshots/s1/file"))
+	assert.NotNil(t, f.CheckSnapshotWritable(ctx, "/other/.snapshots"), "a new snapshot folder is created by the snapshot commands")
+	assert.Nil(t, f.CheckSnapshotWritable(context.WithValue(ctx, "OP", "SNAPSHOT"), "/data/.snapshots/s1/file"))
+	assert.Nil(t, f.CheckSnapshotWritable(context.WithValue(ctx, "OP", "SNAPSHOT"), "/data/.snapshots"))
+	assert.NotNil(t, f.CheckSnapshotMove(ctx, "/data/file", "/data/.snapshots/s1/file"))
+
+	// the snapshots can not be made writable by moving the snapshot folder away, or replaced by moving a folder in
+	assert.NotNil(t, f.CheckSnapshotMove(ctx, "/data/.snapshots", "/data/x"))
+	assert.NotNil(t, f.CheckSnapshotMove(ctx, "/data/x", "/data/.snapshots"))
+	assert.Nil(t, f.CheckSnapshotMove(ctx, "/data", "/moved"), "a directory with snapshots can be moved")
+
+	// a folder named .snapshots before the snapshots were supported is left writable
+	store.InsertEntry(ctx, &Entry{FullPath: "/legacy/.snapshots", Attr: Attr{Mode: os.ModeDir | 0755}})
+	assert.Nil(t, f.CheckSnapshotWritable(ctx, "/legacy/.snapshots/file"))
+	assert.Nil(t, f.CheckSnapshotMove(ctx, "/legacy/.snapshots", "/legacy/x"))
+
+	assert.True(t, IsInSnapshot("/data/.snapshots"))
+	assert.True(t, IsInSnapshot("/data/.snapshots/s1/file"))
+	assert.False(t, IsInSnapshot("/data/.snapshots.old/file"))
+	assert.False(t, IsInSnapshot("/data/x.snapshots/file"))
+
+	snapshotDir, err := snapshotDirectory("/data", "s1")
+	assert.Nil(t, err)
+	assert.Equal(t, util.FullPath("/data/.snapshots/s1"), snapshotDir)
+	_, err = snapshotDirectory("/data", "a/b")
+	assert.NotNil(t, err)
+	_, err = snapshotDirectory("/data/.snapshots/s1", "s2")
+	assert.NotNil(t, err, "a snapshot can not be snapshotted")
+	_, err = snapshotDirectory("/data/.snapshots", "s2")
+	assert.NotNil(t, err, "the snapshot folder can not be snapshotted")
+}
+
+func TestSnapshotChunkRefs(t *testing.T) {
+	ctx := context.Background()
+
+	manifests := map[string][]*filer_pb.FileChunk{
+		"1,m1": {{FileId: "1,d1"}, {FileId: "1,m2", IsChunkManifest: true}},
+		"1,m2": {{FileId: "1,d2"}, {FileId: "1,d3"}},
+	}
+	resolveManifest := func(chunk *filer_pb.FileChunk) ([]*filer_pb.FileChunk, error) {
+		return manifests[chunk.GetFileIdString()], nil
+	}
+	chunks := []*filer_pb.FileChunk{
+		{FileId: "1,c1"},
+		{FileId: "1,m1", IsChunkManifest: true},
+	}
+	allChunks := []string{"1,c1", "1,d1", "1,d2", "1,d3", "1,m1", "1,m2"}
+	sorted := func(fileIds []string) []string {
+		sort.Strings(fileIds)
+		return fileIds
+	}
+
+	for _, name := range []string{"original then snapshot", "snapshot then original"} {
+		f, store := newSnapshotTestFiler()
+		f.hasSnapshots.Store(true)
+
+		assert.Nil(t, f.referenceChunks(ctx, chunks, resolveManifest), name)
+		assert.Equal(t, len(allChunks), len(store.kv), name)
+
+		// both entries have the same chunks, the first one deleted keeps all of them
+		assert.Empty(t, f.releaseChunks(chunks, resolveManifest), name)
+		assert.Empty(t, store.kv, "%s: the references of the manifest chunks and their data chunks are released", name)
+		assert.Equal(t, allChunks, sorted(f.releaseChunks(chunks, resolveManifest)), name)
+	}
+
+	// the original is overwritten, and its old chunks are released one by one after resolving the manifests
+	f, store := newSnapshotTestFiler()
+	f.hasSnapshots.Store(true)
+	assert.Nil(t, f.referenceChunks(ctx, chunks, resolveManifest))
+	for _, fileId := range allChunks {
+		assert.True(t, f.releaseChunkRef(fileId), fileId)
+	}
+	assert.Equal(t, allChunks, sorted(f.releaseChunks(chunks, resolveManifest)))
+
+	// two snapshots
+	f, store = newSnapshotTestFiler()
+	f.hasSnapshots.Store(true)
+	assert.Nil(t, f.referenceChunks(ctx, chunks, resolveManifest))
+	assert.Nil(t, f.referenceChunks(ctx, chunks, resolveManifest))
+	assert.Empty(t, f.releaseChunks(chunks, resolveManifest))
+	assert.Empty(t, f.releaseChunks(chunks, resolveManifest))
+	assert.Equal(t, allChunks, sorted(f.releaseChunks(chunks, resolveManifest)))
+	assert.Empty(t, store.kv)
+}
+
+func TestSnapshotsExist(t *testing.T) {
+	ctx := context.Background()
+	f, store := newSnapshotTestFiler()
+
+	// no snapshot, the chunk references are not read
+	store.KvPut(ctx, chunkRefKey("1,c1"), make([]byte, 8))
+	assert.Equal(t, []string{"1,c1"}, f.releaseChunks([]*filer_pb.FileChunk{{FileId: "1,c1"}}, nil))
+	assert.Len(t, store.kv, 1)
+
+	// the mark is read again after the check interval
+	store.KvPut(ctx, []byte(hasSnapshotsKey), []byte("1"))
+	assert.False(t, f.snapshotsExist())
+	f.hasSnapshotsCheckNs.Store(0)
+	assert.True(t, f.snapshotsExist())
+
+	assert.Nil(t, f.enableSnapshots(ctx))
+}
+
+func TestSnapshotChunkRefsConcurrently(t *testing.T) {
+	ctx := context.Background()
+
+	// two filers sharing the store, locking the reference counts on the same lock manager
+	f1, store := newSnapshotTestFiler()
+	f2 := &Filer{Store: f1.Store}
+	store.kvGetDelay = time.Millisecond
+	lm := lock_manager.NewLockManager()
+	locker := func(key string) func() {
+		for {
+			_, renewToken, err := lm.Lock(key, time.Now().Add(time.Minute).UnixNano(), "", "test")
+			if err == nil {
+				return func() { lm.Unlock(key, renewToken) }
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	for _, f := range []*Filer{f1, f2} {
+		f.chunkRefLocker = locker
+		f.hasSnapshots.Store(true)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		for _, f := range []*Filer{f1, f2} {
+			wg.Add(1)
+			go func(f *Filer) {
+				defer wg.Done()
+				assert.Nil(t, f.addChunkRef(ctx, "1,c1"))
+			}(f)
+		}
+	}
+	wg.Wait()
+	assert.Equal(t, uint64(100), util.BytesToUint64(store.kv[string(chunkRefKey("1,c1"))]))
+
+	for i := 0; i < 50; i++ {
+		for _, f := range []*Filer{f1, f2} {
+			wg.Add(1)
+			go func(f *Filer) {
+				defer wg.Done()
+				assert.True(t, f.releaseChunkRef("1,c1"))
+			}(f)
+		}
+	}
+	wg.Wait()
+	assert.Empty(t, store.kv)
+	assert.False(t, f1.releaseChunkRef("1,c1"), "the last entry deletes the chunk")
+}
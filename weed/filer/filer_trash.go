@@ -0,0 +1,296 @@
+package filer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/util"
+)
+
+// The trash keeps the deleted and the overwritten entries of the locations configured with "trash" in filer.conf.
+// Each entry is moved into its own folder <trash dir>/<deletion time in ns>, remembering the original path,
+// and its chunks are only deleted when the folder is purged after the retention period.
+// The trash of a path in a bucket is the .trash folder of the bucket, and otherwise the .trash folder of the location prefix.
+// Deleting a whole bucket is not kept in the trash.
+// Only the filer writes into the configured trash folders, the folders named .trash elsewhere are ordinary folders.
+const (
+	TrashFolderName           = ".trash"
+	MsgReadOnlyTrash          = "read-only trash"
+	ExtTrashPathKey           = "Seaweed-Trash-Path"
+	DefaultTrashRetentionDays = 7
+	trashPurgeInterval        = time.Hour
+)
+
+// IsInTrash tells by the name whether the path is inside a .trash folder,
+// for the shell commands to tell the trash items from the original paths
+func IsInTrash(p util.FullPath) bool {
+	return strings.Contains(string(p)+"/", "/"+TrashFolderName+"/")
+}
+
+// isInTrash tells whether the path is the trash folder configured for it, or inside it, where the deletions are final
+func (f *Filer) isInTrash(p util.FullPath) bool {
+	return isUnderTrash(p, f.trashRoot(p))
+}
+
+func isUnderTrash(p, trashDir util.FullPath) bool {
+	return trashDir != "" && (p == trashDir || strings.HasPrefix(string(p), string(trashDir)+"/"))
+}
+
+// CheckTrashWritable rejects creating or changing the entries in a trash folder, except when moving them into the trash
+func (f *Filer) CheckTrashWritable(ctx context.Context, p util.FullPath) error {
+	if ctx.Value("OP") == "TRASH" || !f.isInTrash(p) {
+		return nil
+	}
+	return fmt.Errorf("%s: %s", MsgReadOnlyTrash, p)
+}
+
+func trashRetention(rule *filer_pb.FilerConf_PathConf) time.Duration {
+	days := rule.TrashRetentionDays
+	if days == 0 {
+		days = DefaultTrashRetentionDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// trashDirectory returns the trash folder for the deleted path, or empty if the path is not kept in a trash
+func (f *Filer) trashDirectory(p util.FullPath) util.FullPath {
+	if IsInSnapshot(p) || strings.HasPrefix(string(p), DirectoryEtcRoot) || strings.HasPrefix(string(p), TopicsDir+"/") {
+		return ""
+	}
+	trashDir := f.trashRoot(p)
+	if isUnderTrash(p, trashDir) {
+		return ""
+	}
+	return trashDir
+}
+
+// trashRoot returns the trash folder configured for the path, or empty
+func (f *Filer) trashRoot(p util.FullPath) util.FullPath {
+	if f.FilerConf == nil {
+		return ""
+	}
+	var trashDir util.FullPath
+	f.FilerConf.rules.MatchPrefix([]byte(p), func(key []byte, value *filer_pb.FilerConf_PathConf) bool {
+		if value.Trash {
+			trashDir = util.FullPath(strings.TrimSuffix(string(key), "/")).Child(TrashFolderName)
+		}
+		return true
+	})
+	if trashDir == "" {
+		return ""
+	}
+	// keep the deleted objects in the same bucket, and so the same collection
+	if bucket := f.DetectBucket(p); bucket != "" {
+		return util.NewFullPath(f.DirBucketsPath, bucket).Child(TrashFolderName)
+	}
+	return trashDir
+}
+
+// moveToTrash moves the deleted entry into the trash, if configured for its path
+func (f *Filer) moveToTrash(ctx context.Context, entry *Entry, isRecursive bool, signatures []int32) (moved bool, err error) {
+	trashDir := f.trashDirectory(entry.FullPath)
+	if trashDir == "" {
+		return false, nil
+	}
+
+	if entry.IsDirectory() && !isRecursive {
+		entries, _, listErr := f.ListDirectoryEntries(ctx, entry.FullPath, "", false, 1, "", "", "")
+		if listErr != nil {
+			return false, fmt.Errorf("list folder %s: %v", entry.FullPath, listErr)
+		}
+		if len(entries) > 0 {
+			return false, fmt.Errorf("%s: %s", MsgFailDelNonEmptyFolder, entry.FullPath)
+		}
+	}
+
+	ctx, err = f.BeginTransaction(ctx)
+	if err != nil {
+		return false, err
+	}
+	ctx = context.WithValue(ctx, "OP", "TRASH")
+	holder, err := f.createTrashHolder(ctx, trashDir, entry)
+	if err == nil {
+		err = f.moveEntry(ctx, entry, holder.Child(entry.Name()), signatures)
+	}
+	if err != nil {
+		f.RollbackTransaction(ctx)
+		return false, fmt.Errorf("move %s to trash %s: %v", entry.FullPath, trashDir, err)
+	}
+	if err = f.CommitTransaction(ctx); err != nil {
+		f.RollbackTransaction(ctx)
+		return false, fmt.Errorf("move %s to trash %s: %v", entry.FullPath, trashDir, err)
+	}
+
+	glog.V(2).Infof("moved %s to trash %s", entry.FullPath, holder)
+	return true, nil
+}
+
+// keepOverwrittenInTrash copies the overwritten entry into the trash, if configured for its path,
+// instead of deleting its chunks.
+// The entry is only kept if the new entry shares none of its chunks,
+// since the chunks are deleted when the copy is purged from the trash.
+func (f *Filer) keepOverwrittenInTrash(ctx context.Context, oldEntry *Entry, toDelete []*filer_pb.FileChunk) bool {
+	if oldEntry == nil || oldEntry.IsDirectory() || len(oldEntry.HardLinkId) != 0 || len(toDelete) == 0 {
+		return false
+	}
+	trashDir := f.trashDirectory(oldEntry.FullPath)
+	if trashDir == "" {
+		return false
+	}
+	oldData, oldMeta, err := ResolveChunkManifest(f.MasterClient.GetLookupFileIdFunction(), oldEntry.GetChunks(), 0, math.MaxInt64)
+	if err != nil || len(oldData)+len(oldMeta) != len(toDelete) {
+		return false
+	}
+
+	ctx = context.WithValue(ctx, "OP", "TRASH")
+	holder, err := f.createTrashHolder(ctx, trashDir, oldEntry)
+	if err != nil {
+		glog.Errorf("keep overwritten %s in trash %s: %v", oldEntry.FullPath, trashDir, err)
+		return false
+	}
+	overwritten := oldEntry.ShallowClone()
+	overwritten.FullPath = holder.Child(oldEntry.Name())
+	if err = f.CreateEntry(ctx, overwritten, true, false, nil, false, f.MaxFilenameLength); err != nil {
+		glog.Errorf("keep overwritten %s in trash %s: %v", oldEntry.FullPath, trashDir, err)
+		return false
+	}
+
+	glog.V(2).Infof("kept overwritten %s in trash %s", oldEntry.FullPath, holder)
+	return true
+}
+
+// createTrashHolder creates the trash folder holding one deleted entry, named after the deletion time
+func (f *Filer) createTrashHolder(ctx context.Context, trashDir util.FullPath, entry *Entry) (util.FullPath, error) {
+	now := time.Now()
+	holder := &Entry{
+		FullPath: trashDir.Child(strconv.FormatInt(now.UnixNano(), 10)),
+		Attr: Attr{
+			Mtime:  now,
+			Crtime: now,
+			Mode:   os.ModeDir | 0770,
+			Uid:    entry.Uid,
+			Gid:    entry.Gid,
+		},
+		Extended: map[string][]byte{
+			ExtTrashPathKey: []byte(entry.FullPath),
+		},
+	}
+	return holder.FullPath, f.CreateEntry(ctx, holder, true, false, nil, false, f.MaxFilenameLength)
+}
+
+// moveEntry moves the entry and its children to the new path, keeping the chunks
+func (f *Filer) moveEntry(ctx context.Context, entry *Entry, newPath util.FullPath, signatures []int32) error {
+	newEntry := entry.ShallowClone()
+	newEntry.FullPath = newPath
+	if err := f.CreateEntry(ctx, newEntry, false, false, signatures, false, f.MaxFilenameLength); err != nil {
+		return err
+	}
+
+	if entry.IsDirectory() {
+		lastFileName := ""
+		for {
+			entries, hasMore, err := f.ListDirectoryEntries(ctx, entry.FullPath, lastFileName, false, PaginationSize, "", "", "")
+			if err != nil {
+				return err
+			}
+			for _, sub := range entries {
+				lastFileName = sub.Name()
+				if err = f.moveEntry(ctx, sub, newPath.Child(sub.Name()), signatures); err != nil {
+					return err
+				}
+			}
+			if !hasMore {
+				break
+			}
+		}
+	}
+
+	// keep the hard links, which are moved
+	ctx = context.WithValue(ctx, "OP", "MV")
+	return f.DeleteEntryMetaAndData(ctx, entry.FullPath, false, false, false, false, signatures, 0)
+}
+
+func (f *Filer) loopPurgingTrash() {
+	for {
+		time.Sleep(trashPurgeInterval)
+		for _, trashDir := range f.trashDirectories() {
+			f.purgeTrash(trashDir, time.Now())
+		}
+	}
+}
+
+// trashDirectories lists the trash folders of the locations configured with trash
+func (f *Filer) trashDirectories() (trashDirs []util.FullPath) {
+	var includeBuckets bool
+	f.FilerConf.rules.Walk(func(key []byte, value *filer_pb.FilerConf_PathConf) bool {
+		if !value.Trash {
+			return true
+		}
+		prefix := string(key)
+		if f.DirBucketsPath != "" && (strings.HasPrefix(prefix, f.DirBucketsPath+"/") || strings.HasPrefix(f.DirBucketsPath+"/", prefix)) {
+			includeBuckets = true
+		}
+		trashDirs = append(trashDirs, util.FullPath(strings.TrimSuffix(prefix, "/")).Child(TrashFolderName))
+		return true
+	})
+	if !includeBuckets {
+		return
+	}
+
+	lastFileName := ""
+	for {
+		buckets, hasMore, err := f.ListDirectoryEntries(context.Background(), util.FullPath(f.DirBucketsPath), lastFileName, false, PaginationSize, "", "", "")
+		if err != nil {
+			glog.Errorf("list buckets for trash: %v", err)
+			return
+		}
+		for _, bucket := range buckets {
+			lastFileName = bucket.Name()
+			if bucket.IsDirectory() {
+				trashDirs = append(trashDirs, bucket.FullPath.Child(TrashFolderName))
+			}
+		}
+		if !hasMore {
+			return
+		}
+	}
+}
+
+// purgeTrash deletes the entries kept in the trash longer than the retention of their original path
+func (f *Filer) purgeTrash(trashDir util.FullPath, now time.Time) {
+	ctx := context.Background()
+	var expired []*Entry
+	lastFileName := ""
+	for {
+		holders, hasMore, err := f.ListDirectoryEntries(ctx, trashDir, lastFileName, false, PaginationSize, "", "", "")
+		if err != nil {
+			glog.Errorf("list trash %s: %v", trashDir, err)
+			return
+		}
+		for _, holder := range holders {
+			lastFileName = holder.Name()
+			rule := f.FilerConf.MatchStorageRule(string(holder.Extended[ExtTrashPathKey]))
+			if holder.Crtime.Add(trashRetention(rule)).Before(now) {
+				expired = append(expired, holder)
+			}
+		}
+		if !hasMore {
+			break
+		}
+	}
+
+	for _, holder := range expired {
+		if err := f.DeleteEntryMetaAndData(ctx, holder.FullPath, true, true, true, false, nil, 0); err != nil {
+			glog.V(1).Infof("purge trash %s: %v", holder.FullPath, err)
+			continue
+		}
+		glog.V(2).Infof("purged %s from trash, deleted from %s", holder.FullPath, holder.Extended[ExtTrashPathKey])
+	}
+}
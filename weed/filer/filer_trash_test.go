@@ -0,0 +1,59 @@
+package filer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrashDirectory(t *testing.T) {
+	f := &Filer{
+		FilerConf:      NewFilerConf(),
+		DirBucketsPath: "/buckets",
+	}
+	f.FilerConf.doLoadConf(&filer_pb.FilerConf{Locations: []*filer_pb.FilerConf_PathConf{
+		{
+			LocationPrefix: "/buckets/",
+			Trash:          true,
+		},
+		{
+			LocationPrefix:     "/data/",
+			Trash:              true,
+			TrashRetentionDays: 30,
+		},
+		{
+			LocationPrefix: "/data/tmp/",
+			Collection:     "tmp",
+		},
+	}})
+
+	assert.Equal(t, util.FullPath("/buckets/abc/.trash"), f.trashDirectory("/buckets/abc/dir/file"))
+	assert.Equal(t, util.FullPath("/data/.trash"), f.trashDirectory("/data/tmp/file"))
+	assert.Equal(t, util.FullPath(""), f.trashDirectory("/other/file"))
+	assert.Equal(t, util.FullPath(""), f.trashDirectory("/data/.trash/1700000000000000000/file"), "deletions in the trash are final")
+	assert.Equal(t, util.FullPath("/data/.trash"), f.trashDirectory("/data/tmp/.trash/file"), "a folder named .trash is not the trash")
+
+	assert.True(t, f.isInTrash("/buckets/abc/.trash"))
+	assert.True(t, f.isInTrash("/data/.trash/1700000000000000000/file"))
+	assert.False(t, f.isInTrash("/data/tmp/.trash/file"))
+	assert.False(t, f.isInTrash("/other/.trash/file"))
+
+	ctx := context.Background()
+	assert.NotNil(t, f.CheckTrashWritable(ctx, "/data/.trash/1700000000000000000/file"))
+	assert.Nil(t, f.CheckTrashWritable(context.WithValue(ctx, "OP", "TRASH"), "/data/.trash/1700000000000000000/file"))
+	assert.Nil(t, f.CheckTrashWritable(ctx, "/data/tmp/.trash/file"))
+
+	assert.Equal(t, 30*24*time.Hour, trashRetention(f.FilerConf.MatchStorageRule("/data/tmp/file")))
+	assert.Equal(t, DefaultTrashRetentionDays*24*time.Hour, trashRetention(f.FilerConf.MatchStorageRule("/buckets/abc/file")))
+}
+
+func TestIsInTrash(t *testing.T) {
+	assert.True(t, IsInTrash("/buckets/abc/.trash"))
+	assert.True(t, IsInTrash("/buckets/abc/.trash/1700000000000000000/file"))
+	assert.False(t, IsInTrash("/buckets/abc/.trashed/file"))
+	assert.False(t, IsInTrash("/buckets/abc/file.trash"))
+}
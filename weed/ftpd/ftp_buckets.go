@@ -0,0 +1,130 @@
+package ftpd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3_constants"
+	"github.com/seaweedfs/seaweedfs/weed/util"
+)
+
+// the bucket configurations are read again after this interval, to follow the changes
+const bucketsRefreshInterval = 10 * time.Second
+
+// The ftp server changes the files directly in the filer, so it can not follow the S3 semantics
+// of the buckets with versioning, object lock, a bucket policy or default encryption.
+// The files in these buckets are changed only through S3, for all the ftp users,
+// and the buckets with a policy are not served at all, since the policy may deny the reads.
+type ftpBuckets struct {
+	sync.Mutex
+	server *FtpServer
+	rules  map[string]*bucketRules
+}
+
+type bucketRules struct {
+	readOnlyReason string
+	hasPolicy      bool
+	loadTime       time.Time
+}
+
+func newFtpBuckets(server *FtpServer) *ftpBuckets {
+	return &ftpBuckets{
+		server: server,
+		rules:  make(map[string]*bucketRules),
+	}
+}
+
+// bucketOf returns the bucket of the filer path, or empty if the path is not in a bucket
+func (b *ftpBuckets) bucketOf(fullPath util.FullPath) string {
+	bucketsPath := strings.TrimSuffix(b.server.option.BucketsPath, "/")
+	if bucketsPath == "" || !strings.HasPrefix(string(fullPath), bucketsPath+"/") {
+		return ""
+	}
+	bucket, _, _ := strings.Cut(string(fullPath)[len(bucketsPath)+1:], "/")
+	return bucket
+}
+
+// checkWritable rejects changing the path in a bucket following the S3 semantics
+func (b *ftpBuckets) checkWritable(fullPath util.FullPath) error {
+	bucket := b.bucketOf(fullPath)
+	if bucket == "" {
+		return nil
+	}
+	rules, err := b.loadRules(bucket)
+	if err != nil {
+		return err
+	}
+	if rules.readOnlyReason != "" {
+		return fmt.Errorf("%w: bucket %s has %s, change it through S3", os.ErrPermission, bucket, rules.readOnlyReason)
+	}
+	return nil
+}
+
+// checkReadable rejects reading the path in a bucket with a policy
+func (b *ftpBuckets) checkReadable(fullPath util.FullPath) error {
+	bucket := b.bucketOf(fullPath)
+	if bucket == "" {
+		return nil
+	}
+	rules, err := b.loadRules(bucket)
+	if err != nil {
+		return err
+	}
+	if rules.hasPolicy {
+		return fmt.Errorf("%w: bucket %s has a bucket policy, read it through S3", os.ErrPermission, bucket)
+	}
+	return nil
+}
+
+func (b *ftpBuckets) loadRules(bucket string) (*bucketRules, error) {
+	b.Lock()
+	defer b.Unlock()
+
+	if rules, found := b.rules[bucket]; found && time.Since(rules.loadTime) < bucketsRefreshInterval {
+		return rules, nil
+	}
+	entry, err := filer_pb.GetEntry(b.server, util.FullPath(b.server.option.BucketsPath).Child(bucket))
+	if err != nil && err != filer_pb.ErrNotFound {
+		return nil, fmt.Errorf("read bucket %s: %v", bucket, err)
+	}
+	rules := newBucketRules(entry)
+	b.rules[bucket] = rules
+	return rules, nil
+}
+
+func newBucketRules(entry *filer_pb.Entry) *bucketRules {
+	rules := &bucketRules{loadTime: time.Now()}
+	if entry == nil {
+		return rules
+	}
+	for _, rule := range []struct {
+		key    string
+		reason string
+	}{
+		{s3_constants.ExtObjectLockKey, "object lock"},
+		{s3_constants.ExtVersioningKey, "versioning"},
+		{s3_constants.ExtBucketPolicyKey, "a bucket policy"},
+		{s3_constants.ExtBucketEncryptionKey, "default encryption"},
+	} {
+		if len(entry.Extended[rule.key]) > 0 {
+			rules.readOnlyReason = rule.reason
+			break
+		}
+	}
+	rules.hasPolicy = len(entry.Extended[s3_constants.ExtBucketPolicyKey]) > 0
+	return rules
+}
+
+// isGatewayEncrypted tells whether the object is encrypted by the S3 gateway, and can only be read through S3
+func isGatewayEncrypted(entry *filer_pb.Entry) bool {
+	return len(entry.Extended[s3_constants.ExtSSEIvKey]) > 0
+}
+
+// hasObjectAcl tells whether the object has an acl, which the ftp server can not evaluate
+func hasObjectAcl(entry *filer_pb.Entry) bool {
+	return len(entry.Extended[s3_constants.ExtAmzAclKey]) > 0
+}
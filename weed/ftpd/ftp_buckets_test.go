@@ -0,0 +1,62 @@
+package ftpd
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3_constants"
+	"github.com/seaweedfs/seaweedfs/weed/util"
+)
+
+func TestFtpBucketRules(t *testing.T) {
+	b := newFtpBuckets(&FtpServer{option: &FtpServerOption{BucketsPath: "/buckets"}})
+
+	// the bucket entries, as read from the filer
+	for bucket, extended := range map[string]map[string][]byte{
+		"plain":     nil,
+		"versioned": {s3_constants.ExtVersioningKey: []byte("Enabled")},
+		"suspended": {s3_constants.ExtVersioningKey: []byte("Suspended")},
+		"locked":    {s3_constants.ExtObjectLockKey: []byte("<ObjectLockConfiguration/>"), s3_constants.ExtVersioningKey: []byte("Enabled")},
+		"policy":    {s3_constants.ExtBucketPolicyKey: []byte("{}")},
+		"encrypted": {s3_constants.ExtBucketEncryptionKey: []byte("<ServerSideEncryptionConfiguration/>")},
+	} {
+		b.rules[bucket] = newBucketRules(&filer_pb.Entry{Name: bucket, Extended: extended})
+	}
+
+	tests := []struct {
+		path               string
+		writable, readable bool
+	}{
+		{"/other/file", true, true},
+		{"/buckets", true, true},
+		{"/buckets/plain/file", true, true},
+		{"/buckets/versioned/file", false, true},
+		{"/buckets/versioned", false, true},
+		{"/buckets/suspended/file", false, true},
+		{"/buckets/locked/dir/file", false, true},
+		{"/buckets/policy", false, false},
+		{"/buckets/policy/file", false, false},
+		{"/buckets/encrypted/file", false, true},
+	}
+	for _, tt := range tests {
+		err := b.checkWritable(util.FullPath(tt.path))
+		if tt.writable != (err == nil) {
+			t.Errorf("checkWritable(%s) = %v", tt.path, err)
+		}
+		if err != nil && !errors.Is(err, os.ErrPermission) {
+			t.Errorf("checkWritable(%s) = %v, not a permission error", tt.path, err)
+		}
+		if err = b.checkReadable(util.FullPath(tt.path)); tt.readable != (err == nil) {
+			t.Errorf("checkReadable(%s) = %v", tt.path, err)
+		}
+	}
+
+	if !isGatewayEncrypted(&filer_pb.Entry{Extended: map[string][]byte{s3_constants.ExtSSEIvKey: []byte("iv")}}) {
+		t.Errorf("the objects with an iv are encrypted by the gateway")
+	}
+	if isGatewayEncrypted(&filer_pb.Entry{}) || hasObjectAcl(&filer_pb.Entry{}) {
+		t.Errorf("plain object")
+	}
+}
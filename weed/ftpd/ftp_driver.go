@@ -0,0 +1,388 @@
+package ftpd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"syscall"
+	"time"
+
+	ftpserver "github.com/fclairamb/ftpserverlib"
+	"github.com/spf13/afero"
+
+	"github.com/seaweedfs/seaweedfs/weed/filer"
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/util"
+)
+
+// the mode of the uploaded files, since the ftp clients do not send one
+const defaultFileMode = 0644
+
+// FtpFileSystem reads and writes the files of one logged in user through the filer,
+// with the home folder of the user as the root folder
+type FtpFileSystem struct {
+	server *FtpServer
+	user   *ftpUser
+}
+
+var _ = ftpserver.ClientDriver(&FtpFileSystem{})
+var _ = ftpserver.ClientDriverExtensionFileList(&FtpFileSystem{})
+var _ = ftpserver.ClientDriverExtensionRemoveDir(&FtpFileSystem{})
+
+// FileInfo is the os.FileInfo of a filer entry
+type FileInfo struct {
+	name         string
+	size         int64
+	mode         os.FileMode
+	modifiedTime time.Time
+	isDirectory  bool
+}
+
+func (fi *FileInfo) Name() string       { return fi.name }
+func (fi *FileInfo) Size() int64        { return fi.size }
+func (fi *FileInfo) Mode() os.FileMode  { return fi.mode }
+func (fi *FileInfo) ModTime() time.Time { return fi.modifiedTime }
+func (fi *FileInfo) IsDir() bool        { return fi.isDirectory }
+func (fi *FileInfo) Sys() interface{}   { return nil }
+
+func newFileInfo(name string, entry *filer_pb.Entry) *FileInfo {
+	fi := &FileInfo{
+		name:        name,
+		size:        int64(filer.FileSize(entry)),
+		mode:        entry.FileMode(),
+		isDirectory: entry.IsDirectory,
+	}
+	if entry.Attributes != nil {
+		fi.modifiedTime = time.Unix(entry.Attributes.Mtime, 0)
+	}
+	if fi.isDirectory {
+		fi.mode |= os.ModeDir
+		fi.size = 0
+	}
+	return fi
+}
+
+// toFilerError maps the errors of the filer to the errors understood by the ftp server
+func toFilerError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case err == filer_pb.ErrNotFound:
+		return os.ErrNotExist
+	case strings.Contains(err.Error(), filer.MsgQuotaExceeded):
+		return fmt.Errorf("%w: %v", ftpserver.ErrStorageExceeded, err)
+	case strings.Contains(err.Error(), filer.MsgReadOnlySnapshot):
+		return fmt.Errorf("%w: %v", os.ErrPermission, err)
+	}
+	return err
+}
+
+// toFilerPath cleans the path seen by the user, and locates it in the filer under the home folder
+func (fs *FtpFileSystem) toFilerPath(name string) (userPath string, fullPath util.FullPath) {
+	userPath = path.Clean("/" + name)
+	return userPath, util.FullPath(path.Join(fs.user.home, userPath))
+}
+
+// checkWrite checks the permissions of the user and the rules of the bucket to change the path
+func (fs *FtpFileSystem) checkWrite(userPath string, fullPath util.FullPath) error {
+	if !fs.user.canWrite(userPath) {
+		return os.ErrPermission
+	}
+	return fs.server.buckets.checkWritable(fullPath)
+}
+
+func (fs *FtpFileSystem) lookup(fullPath util.FullPath) (*filer_pb.Entry, error) {
+	entry, err := filer_pb.GetEntry(fs.server, fullPath)
+	if err != nil {
+		return nil, toFilerError(err)
+	}
+	if entry == nil {
+		return nil, os.ErrNotExist
+	}
+	return entry, nil
+}
+
+func (fs *FtpFileSystem) Name() string {
+	return "SeaweedFS"
+}
+
+func (fs *FtpFileSystem) Stat(name string) (os.FileInfo, error) {
+	glog.V(2).Infof("FtpFileSystem.Stat %s %s", fs.user.name, name)
+
+	userPath, fullPath := fs.toFilerPath(name)
+	if !fs.user.canList(userPath) {
+		return nil, os.ErrPermission
+	}
+	if fullPath == "/" {
+		return &FileInfo{name: "/", mode: os.ModeDir | 0755, modifiedTime: time.Now(), isDirectory: true}, nil
+	}
+	// the entry is part of the listing of its parent folder
+	dir, _ := fullPath.DirAndName()
+	if err := fs.server.buckets.checkReadable(util.FullPath(dir)); err != nil {
+		return nil, err
+	}
+	entry, err := fs.lookup(fullPath)
+	if err != nil {
+		return nil, err
+	}
+	return newFileInfo(path.Base(userPath), entry), nil
+}
+
+func (fs *FtpFileSystem) ReadDir(name string) ([]os.FileInfo, error) {
+	glog.V(2).Infof("FtpFileSystem.ReadDir %s %s", fs.user.name, name)
+
+	userPath, fullPath := fs.toFilerPath(name)
+	if !fs.user.canList(userPath) {
+		return nil, os.ErrPermission
+	}
+	if err := fs.server.buckets.checkReadable(fullPath); err != nil {
+		return nil, err
+	}
+
+	var infos []os.FileInfo
+	err := filer_pb.ReadDirAllEntries(fs.server, fullPath, "", func(entry *filer_pb.Entry, isLast bool) error {
+		if fs.user.canList(path.Join(userPath, entry.Name)) {
+			infos = append(infos, newFileInfo(entry.Name, entry))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, toFilerError(err)
+	}
+	return infos, nil
+}
+
+func (fs *FtpFileSystem) Mkdir(name string, perm os.FileMode) error {
+	glog.V(2).Infof("FtpFileSystem.Mkdir %s %s", fs.user.name, name)
+
+	userPath, fullPath := fs.toFilerPath(name)
+	if err := fs.checkWrite(userPath, fullPath); err != nil {
+		return err
+	}
+	if _, err := fs.lookup(fullPath); err == nil {
+		return os.ErrExist
+	}
+
+	dir, dirName := fullPath.DirAndName()
+	return toFilerError(filer_pb.Mkdir(fs.server, dir, dirName, func(entry *filer_pb.Entry) {
+		entry.Attributes.FileMode = uint32(perm.Perm() | os.ModeDir)
+		entry.Attributes.Uid = fs.server.option.Uid
+		entry.Attributes.Gid = fs.server.option.Gid
+	}))
+}
+
+func (fs *FtpFileSystem) MkdirAll(name string, perm os.FileMode) error {
+	userPath, _ := fs.toFilerPath(name)
+	current := "/"
+	for _, dirName := range strings.Split(strings.Trim(userPath, "/"), "/") {
+		if dirName == "" {
+			continue
+		}
+		current = path.Join(current, dirName)
+		if err := fs.Mkdir(current, perm); err != nil && err != os.ErrExist {
+			return err
+		}
+	}
+	return nil
+}
+
+func (fs *FtpFileSystem) Create(name string) (afero.File, error) {
+	return fs.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_TRUNC, defaultFileMode)
+}
+
+func (fs *FtpFileSystem) Open(name string) (afero.File, error) {
+	return fs.OpenFile(name, os.O_RDONLY, 0)
+}
+
+// OpenFile opens the file to read, or to write from the start, at a REST offset, or at the end to append.
+// The written file is saved to the filer when closed.
+func (fs *FtpFileSystem) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	glog.V(2).Infof("FtpFileSystem.OpenFile %s %s %x", fs.user.name, name, flag)
+
+	userPath, fullPath := fs.toFilerPath(name)
+
+	if flag&(os.O_WRONLY|os.O_RDWR) == 0 {
+		if !fs.user.canList(userPath) {
+			return nil, os.ErrPermission
+		}
+		if fullPath == "/" {
+			return newFtpDirectory(fs, userPath, fullPath), nil
+		}
+		entry, err := fs.lookup(fullPath)
+		if err != nil {
+			return nil, err
+		}
+		if entry.IsDirectory {
+			return newFtpDirectory(fs, userPath, fullPath), nil
+		}
+		if !fs.user.canRead(userPath) {
+			return nil, os.ErrPermission
+		}
+		if err = fs.server.buckets.checkReadable(fullPath); err != nil {
+			return nil, err
+		}
+		if isGatewayEncrypted(entry) {
+			return nil, fmt.Errorf("%w: %s is encrypted by S3, read it through S3", os.ErrPermission, userPath)
+		}
+		if fs.user.isS3 && !fs.user.isAdmin() && hasObjectAcl(entry) {
+			return nil, fmt.Errorf("%w: %s has an object acl, read it through S3", os.ErrPermission, userPath)
+		}
+		return newFtpFileReader(fs, userPath, fullPath, entry), nil
+	}
+
+	if err := fs.checkWrite(userPath, fullPath); err != nil {
+		return nil, err
+	}
+	existing, err := fs.lookup(fullPath)
+	if err != nil && err != os.ErrNotExist {
+		return nil, err
+	}
+	if existing != nil {
+		if existing.IsDirectory {
+			return nil, syscall.EISDIR
+		}
+		if flag&os.O_EXCL != 0 {
+			return nil, os.ErrExist
+		}
+	} else if flag&os.O_CREATE == 0 {
+		return nil, os.ErrNotExist
+	}
+	if perm.Perm() == 0 || perm.Perm() == os.ModePerm {
+		perm = defaultFileMode
+	}
+
+	file, err := newFtpFileWriter(fs, userPath, fullPath, existing, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+func (fs *FtpFileSystem) Remove(name string) error {
+	glog.V(2).Infof("FtpFileSystem.Remove %s %s", fs.user.name, name)
+
+	return fs.remove(name, false, false)
+}
+
+func (fs *FtpFileSystem) RemoveDir(name string) error {
+	glog.V(2).Infof("FtpFileSystem.RemoveDir %s %s", fs.user.name, name)
+
+	return fs.remove(name, true, false)
+}
+
+func (fs *FtpFileSystem) RemoveAll(name string) error {
+	glog.V(2).Infof("FtpFileSystem.RemoveAll %s %s", fs.user.name, name)
+
+	return fs.remove(name, true, true)
+}
+
+func (fs *FtpFileSystem) remove(name string, isDirectory, isRecursive bool) error {
+	userPath, fullPath := fs.toFilerPath(name)
+	if err := fs.checkWrite(userPath, fullPath); err != nil {
+		return err
+	}
+	entry, err := fs.lookup(fullPath)
+	if err != nil {
+		return err
+	}
+	if entry.IsDirectory && !isDirectory {
+		return syscall.EISDIR
+	}
+	if !entry.IsDirectory && isDirectory && !isRecursive {
+		return syscall.ENOTDIR
+	}
+
+	dir, entryName := fullPath.DirAndName()
+	return toFilerError(filer_pb.Remove(fs.server, dir, entryName, true, isRecursive, false, false, []int32{fs.server.signature}))
+}
+
+// Rename moves the file or the folder, replacing the existing file at the new path
+func (fs *FtpFileSystem) Rename(oldName, newName string) error {
+	glog.V(2).Infof("FtpFileSystem.Rename %s %s to %s", fs.user.name, oldName, newName)
+
+	oldUserPath, oldPath := fs.toFilerPath(oldName)
+	newUserPath, newPath := fs.toFilerPath(newName)
+	if err := fs.checkWrite(oldUserPath, oldPath); err != nil {
+		return err
+	}
+	if err := fs.checkWrite(newUserPath, newPath); err != nil {
+		return err
+	}
+	if oldPath == newPath {
+		return nil
+	}
+	if strings.HasPrefix(string(newPath), string(oldPath)+"/") {
+		return os.ErrInvalid
+	}
+	if _, err := fs.lookup(oldPath); err != nil {
+		return err
+	}
+	if existing, err := fs.lookup(newPath); err == nil {
+		if existing.IsDirectory {
+			return os.ErrExist
+		}
+		if err = fs.remove(newUserPath, false, false); err != nil {
+			return err
+		}
+	}
+
+	oldDir, oldEntryName := oldPath.DirAndName()
+	newDir, newEntryName := newPath.DirAndName()
+	return toFilerError(fs.server.WithFilerClient(false, func(client filer_pb.SeaweedFilerClient) error {
+		_, err := client.AtomicRenameEntry(context.Background(), &filer_pb.AtomicRenameEntryRequest{
+			OldDirectory: oldDir,
+			OldName:      oldEntryName,
+			NewDirectory: newDir,
+			NewName:      newEntryName,
+			Signatures:   []int32{fs.server.signature},
+		})
+		return err
+	}))
+}
+
+func (fs *FtpFileSystem) Chmod(name string, mode os.FileMode) error {
+	return fs.updateEntry(name, func(entry *filer_pb.Entry) {
+		entry.Attributes.FileMode = uint32(entry.FileMode()&^os.ModePerm | mode.Perm())
+	})
+}
+
+func (fs *FtpFileSystem) Chown(name string, uid, gid int) error {
+	return fs.updateEntry(name, func(entry *filer_pb.Entry) {
+		entry.Attributes.Uid, entry.Attributes.Gid = uint32(uid), uint32(gid)
+	})
+}
+
+func (fs *FtpFileSystem) Chtimes(name string, atime time.Time, mtime time.Time) error {
+	return fs.updateEntry(name, func(entry *filer_pb.Entry) {
+		entry.Attributes.Mtime = mtime.Unix()
+	})
+}
+
+func (fs *FtpFileSystem) updateEntry(name string, fn func(entry *filer_pb.Entry)) error {
+	glog.V(2).Infof("FtpFileSystem.updateEntry %s %s", fs.user.name, name)
+
+	userPath, fullPath := fs.toFilerPath(name)
+	if err := fs.checkWrite(userPath, fullPath); err != nil {
+		return err
+	}
+	entry, err := fs.lookup(fullPath)
+	if err != nil {
+		return err
+	}
+	if entry.Attributes == nil {
+		entry.Attributes = &filer_pb.FuseAttributes{}
+	}
+	fn(entry)
+
+	dir, _ := fullPath.DirAndName()
+	return toFilerError(fs.server.WithFilerClient(false, func(client filer_pb.SeaweedFilerClient) error {
+		return filer_pb.UpdateEntry(client, &filer_pb.UpdateEntryRequest{
+			Directory:  dir,
+			Entry:      entry,
+			Signatures: []int32{fs.server.signature},
+		})
+	}))
+}
@@ -0,0 +1,345 @@
+package ftpd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"syscall"
+	"time"
+
+	"github.com/spf13/afero"
+
+	"github.com/seaweedfs/seaweedfs/weed/filer"
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/operation"
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/util"
+	"github.com/seaweedfs/seaweedfs/weed/util/buffered_writer"
+)
+
+// FtpFile is an opened folder, a file opened to read, or a file opened to write
+type FtpFile struct {
+	fs          *FtpFileSystem
+	name        string
+	fullPath    util.FullPath
+	entry       *filer_pb.Entry
+	isDirectory bool
+	off         int64
+	reader      io.ReaderAt
+	dirInfos    []os.FileInfo
+	dirLoaded   bool
+	isClosed    bool
+
+	// writing
+	isWriting  bool
+	writeStart int64
+	written    int64
+	bufWriter  *buffered_writer.BufferedWriteCloser
+}
+
+var _ = afero.File(&FtpFile{})
+
+func newFtpDirectory(fs *FtpFileSystem, userPath string, fullPath util.FullPath) *FtpFile {
+	return &FtpFile{
+		fs:          fs,
+		name:        userPath,
+		fullPath:    fullPath,
+		isDirectory: true,
+	}
+}
+
+func newFtpFileReader(fs *FtpFileSystem, userPath string, fullPath util.FullPath, entry *filer_pb.Entry) *FtpFile {
+	return &FtpFile{
+		fs:       fs,
+		name:     userPath,
+		fullPath: fullPath,
+		entry:    entry,
+	}
+}
+
+// newFtpFileWriter prepares the entry to save when the file is closed.
+// Without O_TRUNC the existing chunks are kept, so an interrupted upload can be resumed with REST,
+// and O_APPEND starts writing at the end of the file.
+func newFtpFileWriter(fs *FtpFileSystem, userPath string, fullPath util.FullPath, existing *filer_pb.Entry, flag int, perm os.FileMode) (*FtpFile, error) {
+	f := &FtpFile{
+		fs:        fs,
+		name:      userPath,
+		fullPath:  fullPath,
+		isWriting: true,
+		bufWriter: buffered_writer.NewBufferedWriteCloser(fs.server.option.MaxMB * 1024 * 1024),
+	}
+
+	now := time.Now()
+	if existing != nil && flag&os.O_TRUNC == 0 {
+		f.entry = existing
+		if f.entry.Attributes == nil {
+			f.entry.Attributes = &filer_pb.FuseAttributes{}
+		}
+		if len(f.entry.Content) > 0 {
+			// the small files are inlined, and need to be a chunk to write over or append to
+			chunk, err := f.saveDataAsChunk(bytes.NewReader(f.entry.Content), 0, now.UnixNano())
+			if err != nil {
+				return nil, err
+			}
+			f.entry.Content = nil
+			f.entry.Chunks = []*filer_pb.FileChunk{chunk}
+		}
+		if flag&os.O_APPEND != 0 {
+			f.writeStart = int64(filer.FileSize(f.entry))
+		}
+	} else {
+		f.entry = &filer_pb.Entry{
+			Name: fullPath.Name(),
+			Attributes: &filer_pb.FuseAttributes{
+				Crtime:   now.Unix(),
+				FileMode: uint32(perm.Perm()),
+				Uid:      fs.server.option.Uid,
+				Gid:      fs.server.option.Gid,
+			},
+		}
+		if existing != nil && existing.Attributes != nil {
+			f.entry.Attributes.Crtime = existing.Attributes.Crtime
+			f.entry.Attributes.FileMode = existing.Attributes.FileMode
+			f.entry.Attributes.Uid = existing.Attributes.Uid
+			f.entry.Attributes.Gid = existing.Attributes.Gid
+		}
+	}
+
+	f.bufWriter.FlushFunc = func(data []byte, offset int64) error {
+		chunk, err := f.saveDataAsChunk(util.NewBytesReader(data), f.writeStart+offset, time.Now().UnixNano())
+		if err != nil {
+			return err
+		}
+		f.entry.Chunks = append(f.entry.GetChunks(), chunk)
+		return nil
+	}
+	f.bufWriter.CloseFunc = f.saveEntry
+
+	return f, nil
+}
+
+func (f *FtpFile) saveDataAsChunk(reader io.Reader, offset int64, tsNs int64) (*filer_pb.FileChunk, error) {
+	uploader, err := operation.NewUploader()
+	if err != nil {
+		return nil, fmt.Errorf("upload data: %v", err)
+	}
+
+	option := f.fs.server.option
+	fileId, uploadResult, err, _ := uploader.UploadWithRetry(
+		f.fs.server,
+		&filer_pb.AssignVolumeRequest{
+			Count:       1,
+			Replication: option.Replication,
+			Collection:  option.Collection,
+			DiskType:    option.DiskType,
+			Path:        string(f.fullPath),
+		},
+		&operation.UploadOption{
+			Filename: f.fullPath.Name(),
+			Cipher:   option.Cipher,
+		},
+		func(host, fileId string) string {
+			return fmt.Sprintf("http://%s/%s", host, fileId)
+		},
+		reader,
+	)
+	if err != nil {
+		glog.V(0).Infof("upload data %s: %v", f.fullPath, err)
+		return nil, fmt.Errorf("upload data: %v", err)
+	}
+	if uploadResult.Error != "" {
+		glog.V(0).Infof("upload failure %s: %v", f.fullPath, uploadResult.Error)
+		return nil, fmt.Errorf("upload result: %v", uploadResult.Error)
+	}
+	return uploadResult.ToPbFileChunk(fileId, offset, tsNs), nil
+}
+
+// saveEntry creates or replaces the entry with the uploaded chunks
+func (f *FtpFile) saveEntry() error {
+	manifestedChunks, err := filer.MaybeManifestize(func(reader io.Reader, name string, offset int64, tsNs int64) (*filer_pb.FileChunk, error) {
+		return f.saveDataAsChunk(reader, offset, tsNs)
+	}, f.entry.GetChunks())
+	if err != nil {
+		// not good, but should be ok
+		glog.V(0).Infof("file %s close MaybeManifestize: %v", f.fullPath, err)
+	} else {
+		f.entry.Chunks = manifestedChunks
+	}
+
+	f.entry.Attributes.FileSize = uint64(max(int64(f.entry.Attributes.FileSize), f.writeStart+f.written))
+	f.entry.Attributes.Mtime = time.Now().Unix()
+
+	dir, _ := f.fullPath.DirAndName()
+	return toFilerError(f.fs.server.WithFilerClient(false, func(client filer_pb.SeaweedFilerClient) error {
+		return filer_pb.CreateEntry(client, &filer_pb.CreateEntryRequest{
+			Directory:  dir,
+			Entry:      f.entry,
+			Signatures: []int32{f.fs.server.signature},
+		})
+	}))
+}
+
+func (f *FtpFile) Name() string {
+	return f.name
+}
+
+func (f *FtpFile) Close() error {
+	glog.V(2).Infof("FtpFile.Close %s %s", f.fs.user.name, f.name)
+
+	if f.isClosed {
+		return os.ErrClosed
+	}
+	f.isClosed = true
+	if !f.isWriting {
+		return nil
+	}
+	if err := f.bufWriter.Close(); err != nil {
+		glog.Errorf("ftp save %s: %v", f.fullPath, err)
+		return err
+	}
+	return nil
+}
+
+func (f *FtpFile) Read(p []byte) (int, error) {
+	n, err := f.ReadAt(p, f.off)
+	f.off += int64(n)
+	return n, err
+}
+
+func (f *FtpFile) ReadAt(p []byte, off int64) (n int, err error) {
+	if f.isDirectory {
+		return 0, syscall.EISDIR
+	}
+	if f.isWriting || f.isClosed {
+		return 0, syscall.EBADF
+	}
+
+	fileSize := int64(filer.FileSize(f.entry))
+	if off >= fileSize {
+		return 0, io.EOF
+	}
+	if f.reader == nil {
+		if len(f.entry.Content) > 0 {
+			f.reader = bytes.NewReader(f.entry.Content)
+		} else {
+			visibleIntervals, _ := filer.NonOverlappingVisibleIntervals(filer.LookupFn(f.fs.server), f.entry.GetChunks(), 0, fileSize)
+			chunkViews := filer.ViewFromVisibleIntervals(visibleIntervals, 0, fileSize)
+			f.reader = filer.NewChunkReaderAtFromClient(f.fs.server.readerCache, chunkViews, fileSize)
+		}
+	}
+
+	if remaining := fileSize - off; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+	n, err = f.reader.ReadAt(p, off)
+	if err == nil && off+int64(n) >= fileSize {
+		err = io.EOF
+	}
+	if err != nil && err != io.EOF {
+		glog.Errorf("ftp read %s: %v", f.fullPath, err)
+	}
+	return n, err
+}
+
+// Seek moves the reading position, or sets where the writing starts before anything is written
+func (f *FtpFile) Seek(offset int64, whence int) (int64, error) {
+	glog.V(2).Infof("FtpFile.Seek %s %d %d", f.name, offset, whence)
+
+	current := f.off
+	if f.isWriting {
+		if f.written > 0 {
+			return 0, fmt.Errorf("%s: can not seek after writing", f.name)
+		}
+		current = f.writeStart
+	}
+
+	switch whence {
+	case io.SeekCurrent:
+		offset += current
+	case io.SeekEnd:
+		offset += int64(filer.FileSize(f.entry))
+	}
+	if offset < 0 {
+		return 0, os.ErrInvalid
+	}
+
+	if f.isWriting {
+		f.writeStart = offset
+	} else {
+		f.off = offset
+	}
+	return offset, nil
+}
+
+func (f *FtpFile) Write(p []byte) (int, error) {
+	if !f.isWriting || f.isClosed {
+		return 0, syscall.EBADF
+	}
+	n, err := f.bufWriter.Write(p)
+	f.written += int64(n)
+	if err != nil {
+		glog.Errorf("ftp write %s: %v", f.fullPath, err)
+		return n, toFilerError(err)
+	}
+	return n, nil
+}
+
+func (f *FtpFile) WriteAt(p []byte, off int64) (int, error) {
+	return 0, syscall.ENOTSUP
+}
+
+func (f *FtpFile) WriteString(s string) (int, error) {
+	return f.Write([]byte(s))
+}
+
+func (f *FtpFile) Readdir(count int) ([]os.FileInfo, error) {
+	if !f.isDirectory {
+		return nil, syscall.ENOTDIR
+	}
+	if !f.dirLoaded {
+		infos, err := f.fs.ReadDir(f.name)
+		if err != nil {
+			return nil, err
+		}
+		f.dirInfos, f.dirLoaded = infos, true
+	}
+
+	if count <= 0 {
+		infos := f.dirInfos
+		f.dirInfos = nil
+		return infos, nil
+	}
+	if len(f.dirInfos) == 0 {
+		return nil, io.EOF
+	}
+	count = min(count, len(f.dirInfos))
+	infos := f.dirInfos[:count]
+	f.dirInfos = f.dirInfos[count:]
+	return infos, nil
+}
+
+func (f *FtpFile) Readdirnames(n int) ([]string, error) {
+	infos, err := f.Readdir(n)
+	names := make([]string, len(infos))
+	for i, info := range infos {
+		names[i] = info.Name()
+	}
+	return names, err
+}
+
+func (f *FtpFile) Stat() (os.FileInfo, error) {
+	if f.isDirectory {
+		return f.fs.Stat(f.name)
+	}
+	return newFileInfo(path.Base(f.name), f.entry), nil
+}
+
+func (f *FtpFile) Sync() error {
+	return nil
+}
+
+func (f *FtpFile) Truncate(size int64) error {
+	return syscall.ENOTSUP
+}
@@ -3,44 +3,130 @@ package ftpd
 import (
 	"crypto/tls"
 	"errors"
-	"github.com/seaweedfs/seaweedfs/weed/util"
+	"fmt"
 	"net"
+	"os"
+	"path"
+	"sync"
 
 	ftpserver "github.com/fclairamb/ftpserverlib"
 	"google.golang.org/grpc"
+
+	"github.com/seaweedfs/seaweedfs/weed/filer"
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/pb"
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/util"
+	"github.com/seaweedfs/seaweedfs/weed/util/chunk_cache"
+)
+
+// The TLS modes of the FTP server
+const (
+	TlsModeNone     = ""
+	TlsModeExplicit = "explicit"
+	TlsModeImplicit = "implicit"
 )
 
 type FtpServerOption struct {
-	Filer            string
+	Filer            pb.ServerAddress
 	IP               string
 	IpBind           string
 	Port             int
-	FilerGrpcAddress string
 	FtpRoot          string
+	BucketsPath      string
 	GrpcDialOption   grpc.DialOption
 	PassivePortStart int
 	PassivePortStop  int
+	Collection       string
+	Replication      string
+	DiskType         string
+	Uid              uint32
+	Gid              uint32
+	Cipher           bool
+	CacheDir         string
+	CacheSizeMB      int64
+	MaxMB            int
+	UsersFile        string
+	S3ConfigFile     string
+	TlsMode          string
+	TlsRequired      bool
+	TlsCertificate   string
+	TlsPrivateKey    string
 }
 
-type SftpServer struct {
+type FtpServer struct {
 	option      *FtpServerOption
 	ftpListener net.Listener
+	tlsConfig   *tls.Config
+	readerCache *filer.ReaderCache
+	signature   int32
+	users       *ftpUsers
+	buckets     *ftpBuckets
+	homeLock    sync.Mutex
 }
 
-var _ = ftpserver.MainDriver(&SftpServer{})
+var _ = ftpserver.MainDriver(&FtpServer{})
+var _ = filer_pb.FilerClient(&FtpServer{})
 
 // NewFtpServer returns a new FTP server driver
-func NewFtpServer(ftpListener net.Listener, option *FtpServerOption) (*SftpServer, error) {
-	var err error
-	server := &SftpServer{
+func NewFtpServer(ftpListener net.Listener, option *FtpServerOption) (*FtpServer, error) {
+	server := &FtpServer{
 		option:      option,
 		ftpListener: ftpListener,
+		signature:   util.RandomInt32(),
 	}
-	return server, err
+
+	if option.TlsMode != TlsModeNone {
+		if option.TlsMode != TlsModeExplicit && option.TlsMode != TlsModeImplicit {
+			return nil, fmt.Errorf("unknown tls mode %q", option.TlsMode)
+		}
+		certificate, err := tls.LoadX509KeyPair(option.TlsCertificate, option.TlsPrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("load tls certificate %s and key %s: %v", option.TlsCertificate, option.TlsPrivateKey, err)
+		}
+		server.tlsConfig = &tls.Config{
+			Certificates: []tls.Certificate{certificate},
+			MinVersion:   tls.VersionTLS12,
+		}
+		// the listener is provided, so the implicit tls is set up here instead of in the ftp server library
+		if option.TlsMode == TlsModeImplicit {
+			server.ftpListener = tls.NewListener(ftpListener, server.tlsConfig)
+		}
+	}
+
+	users, err := newFtpUsers(server)
+	if err != nil {
+		return nil, err
+	}
+	server.users = users
+	server.buckets = newFtpBuckets(server)
+
+	cacheUniqueId := util.Md5String([]byte("ftp" + string(option.Filer) + util.Version()))[0:8]
+	cacheDir := path.Join(option.CacheDir, cacheUniqueId)
+	os.MkdirAll(cacheDir, os.FileMode(0755))
+	chunkCache := chunk_cache.NewTieredChunkCache(256, cacheDir, option.CacheSizeMB, 1024*1024)
+	server.readerCache = filer.NewReaderCache(32, chunkCache, filer.LookupFn(server))
+
+	return server, nil
+}
+
+func (s *FtpServer) WithFilerClient(streamingMode bool, fn func(filer_pb.SeaweedFilerClient) error) error {
+	return pb.WithGrpcClient(streamingMode, s.signature, func(grpcConnection *grpc.ClientConn) error {
+		client := filer_pb.NewSeaweedFilerClient(grpcConnection)
+		return fn(client)
+	}, s.option.Filer.ToGrpcAddress(), false, s.option.GrpcDialOption)
+}
+
+func (s *FtpServer) AdjustedUrl(location *filer_pb.Location) string {
+	return location.Url
+}
+
+func (s *FtpServer) GetDataCenter() string {
+	return ""
 }
 
 // GetSettings returns some general settings around the server setup
-func (s *SftpServer) GetSettings() (*ftpserver.Settings, error) {
+func (s *FtpServer) GetSettings() (*ftpserver.Settings, error) {
 	var portRange *ftpserver.PortRange
 	if s.option.PassivePortStart > 0 && s.option.PassivePortStop > s.option.PassivePortStart {
 		portRange = &ftpserver.PortRange{
@@ -49,6 +135,13 @@ func (s *SftpServer) GetSettings() (*ftpserver.Settings, error) {
 		}
 	}
 
+	tlsRequired := ftpserver.ClearOrEncrypted
+	if s.option.TlsMode == TlsModeImplicit {
+		tlsRequired = ftpserver.ImplicitEncryption
+	} else if s.isTlsRequired() {
+		tlsRequired = ftpserver.MandatoryEncryption
+	}
+
 	return &ftpserver.Settings{
 		Listener:                 s.ftpListener,
 		ListenAddr:               util.JoinHostPort(s.option.IpBind, s.option.Port),
@@ -57,25 +150,76 @@ func (s *SftpServer) GetSettings() (*ftpserver.Settings, error) {
 		ActiveTransferPortNon20:  true,
 		IdleTimeout:              -1,
 		ConnectionTimeout:        20,
+		TLSRequired:              tlsRequired,
 	}, nil
 }
 
 // ClientConnected is called to send the very first welcome message
-func (s *SftpServer) ClientConnected(cc ftpserver.ClientContext) (string, error) {
+func (s *FtpServer) ClientConnected(cc ftpserver.ClientContext) (string, error) {
 	return "Welcome to SeaweedFS FTP Server", nil
 }
 
 // ClientDisconnected is called when the user disconnects, even if he never authenticated
-func (s *SftpServer) ClientDisconnected(cc ftpserver.ClientContext) {
+func (s *FtpServer) ClientDisconnected(cc ftpserver.ClientContext) {
 }
 
 // AuthUser authenticates the user and selects an handling driver
-func (s *SftpServer) AuthUser(cc ftpserver.ClientContext, username, password string) (ftpserver.ClientDriver, error) {
-	return nil, nil
+func (s *FtpServer) AuthUser(cc ftpserver.ClientContext, username, password string) (ftpserver.ClientDriver, error) {
+	user, err := s.users.authenticate(username, password)
+	if err == nil && user.isS3 && !cc.HasTLSForControl() {
+		err = errTlsRequired
+	}
+	if err != nil {
+		glog.V(0).Infof("ftp login %s from %s: %v", username, cc.RemoteAddr(), err)
+		return nil, errors.New("invalid username or password")
+	}
+	if err = s.ensureHome(user.home); err != nil {
+		glog.Errorf("ftp user %s home %s: %v", username, user.home, err)
+		return nil, fmt.Errorf("home directory is not available")
+	}
+	glog.V(1).Infof("ftp login %s from %s with home %s", username, cc.RemoteAddr(), user.home)
+	return &FtpFileSystem{
+		server: s,
+		user:   user,
+	}, nil
 }
 
 // GetTLSConfig returns a TLS Certificate to use
 // The certificate could frequently change if we use something like "let's encrypt"
-func (s *SftpServer) GetTLSConfig() (*tls.Config, error) {
-	return nil, errors.New("no TLS certificate configured")
+func (s *FtpServer) GetTLSConfig() (*tls.Config, error) {
+	if s.tlsConfig == nil {
+		return nil, errors.New("no TLS certificate configured")
+	}
+	return s.tlsConfig, nil
+}
+
+// isTlsRequired tells whether all the connections are encrypted, so the passwords are never sent in clear text
+func (s *FtpServer) isTlsRequired() bool {
+	return s.option.TlsMode == TlsModeImplicit || s.option.TlsMode == TlsModeExplicit && s.option.TlsRequired
+}
+
+// ensureHome creates the home directory of the user, if not created yet
+func (s *FtpServer) ensureHome(home string) error {
+	s.homeLock.Lock()
+	defer s.homeLock.Unlock()
+
+	if home == "/" {
+		return nil
+	}
+	entry, err := filer_pb.GetEntry(s, util.FullPath(home))
+	if err == nil && entry != nil {
+		if !entry.IsDirectory {
+			return fmt.Errorf("%s is not a directory", home)
+		}
+		return nil
+	}
+	if err != nil && err != filer_pb.ErrNotFound {
+		return err
+	}
+	dir, name := util.FullPath(home).DirAndName()
+	return filer_pb.Mkdir(s, dir, name, func(entry *filer_pb.Entry) {
+		entry.Attributes.FileMode = uint32(0755 | os.ModeDir)
+		entry.Attributes.Uid = s.option.Uid
+		entry.Attributes.Gid = s.option.Gid
+	})
 }
@@ -0,0 +1,268 @@
+package ftpd
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/seaweedfs/seaweedfs/weed/filer"
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/pb/iam_pb"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3_constants"
+)
+
+// the S3 identities are read again after this interval, to follow the changes
+const identitiesRefreshInterval = time.Minute
+
+var (
+	errLoginFailed = errors.New("unknown user or wrong password")
+	errTlsRequired = errors.New("the S3 identities log in only over TLS")
+)
+
+// FtpUsersConfig is the users file, e.g.
+//
+//	{
+//	  "users": [
+//	    {"name": "partner1", "password": "$2a$10$...", "home": "/partners/partner1"},
+//	    {"name": "auditor", "password": "secret", "home": "/partners", "readOnly": true}
+//	  ]
+//	}
+//
+// The password can be a bcrypt hash or plain text. The home is a filer path,
+// or relative to the ftp root folder, default to the ftp root folder.
+type FtpUsersConfig struct {
+	Users []*FtpUserConfig `json:"users"`
+}
+
+type FtpUserConfig struct {
+	Name     string `json:"name"`
+	Password string `json:"password"`
+	Home     string `json:"home"`
+	ReadOnly bool   `json:"readOnly"`
+}
+
+// ftpUser is the logged in user, who sees the home folder as the root folder
+type ftpUser struct {
+	name     string
+	home     string
+	readOnly bool
+	// the users of the S3 identities have the home at the buckets folder,
+	// and are allowed the same actions on the buckets as in S3
+	isS3      bool
+	s3Actions []string
+}
+
+type ftpUsers struct {
+	sync.Mutex
+	server             *FtpServer
+	fileUsers          map[string]*FtpUserConfig
+	identities         []*iam_pb.Identity
+	identitiesLoadTime time.Time
+}
+
+func newFtpUsers(server *FtpServer) (*ftpUsers, error) {
+	u := &ftpUsers{
+		server:    server,
+		fileUsers: make(map[string]*FtpUserConfig),
+	}
+	if !server.isTlsRequired() {
+		glog.V(0).Infof("the S3 identities can not log in to the ftp server without tls required")
+	}
+	if server.option.UsersFile == "" {
+		return u, nil
+	}
+
+	content, err := os.ReadFile(server.option.UsersFile)
+	if err != nil {
+		return nil, fmt.Errorf("read ftp users file %s: %v", server.option.UsersFile, err)
+	}
+	config := &FtpUsersConfig{}
+	if err = json.Unmarshal(content, config); err != nil {
+		return nil, fmt.Errorf("parse ftp users file %s: %v", server.option.UsersFile, err)
+	}
+	for _, user := range config.Users {
+		if user.Name == "" || user.Password == "" {
+			return nil, fmt.Errorf("ftp users file %s: user without name or password", server.option.UsersFile)
+		}
+		if _, found := u.fileUsers[user.Name]; found {
+			return nil, fmt.Errorf("ftp users file %s: duplicated user %s", server.option.UsersFile, user.Name)
+		}
+		if strings.HasPrefix(user.Home, "/") {
+			user.Home = path.Clean(user.Home)
+		} else {
+			user.Home = path.Join("/", server.option.FtpRoot, user.Home)
+		}
+		u.fileUsers[user.Name] = user
+	}
+	glog.V(0).Infof("loaded %d ftp users from %s", len(u.fileUsers), server.option.UsersFile)
+	return u, nil
+}
+
+// authenticate checks the users file first, and then the access keys and secret keys of the S3 identities.
+// The secret keys are only accepted when the connections are required to be encrypted.
+func (u *ftpUsers) authenticate(username, password string) (*ftpUser, error) {
+	if user, found := u.fileUsers[username]; found {
+		if !checkPassword(user.Password, password) {
+			return nil, errLoginFailed
+		}
+		return &ftpUser{
+			name:     user.Name,
+			home:     user.Home,
+			readOnly: user.ReadOnly,
+		}, nil
+	}
+
+	if !u.server.isTlsRequired() {
+		return nil, errLoginFailed
+	}
+	for _, identity := range u.loadS3Identities() {
+		for _, cred := range identity.Credentials {
+			if username != identity.Name && username != cred.AccessKey {
+				continue
+			}
+			if subtle.ConstantTimeCompare([]byte(cred.SecretKey), []byte(password)) == 1 {
+				return &ftpUser{
+					name:      identity.Name,
+					home:      u.server.option.BucketsPath,
+					isS3:      true,
+					s3Actions: identity.Actions,
+				}, nil
+			}
+		}
+	}
+
+	return nil, errLoginFailed
+}
+
+func checkPassword(stored, password string) bool {
+	if strings.HasPrefix(stored, "$2") {
+		return bcrypt.CompareHashAndPassword([]byte(stored), []byte(password)) == nil
+	}
+	return subtle.ConstantTimeCompare([]byte(stored), []byte(password)) == 1
+}
+
+// loadS3Identities reads the S3 identities from the S3 config file, or the identity.json in the filer
+func (u *ftpUsers) loadS3Identities() []*iam_pb.Identity {
+	u.Lock()
+	defer u.Unlock()
+
+	if time.Since(u.identitiesLoadTime) < identitiesRefreshInterval {
+		return u.identities
+	}
+	u.identitiesLoadTime = time.Now()
+
+	var content []byte
+	var err error
+	if u.server.option.S3ConfigFile != "" {
+		content, err = os.ReadFile(u.server.option.S3ConfigFile)
+	} else {
+		err = u.server.WithFilerClient(false, func(client filer_pb.SeaweedFilerClient) error {
+			content, err = filer.ReadInsideFiler(client, filer.IamConfigDirectory, filer.IamIdentityFile)
+			return err
+		})
+		if err == filer_pb.ErrNotFound {
+			content, err = nil, nil
+		}
+	}
+	if err != nil {
+		glog.Warningf("read s3 identities: %v", err)
+		return u.identities
+	}
+
+	s3cfg := &iam_pb.S3ApiConfiguration{}
+	if len(content) > 0 {
+		if err = filer.ParseS3ConfigurationFromBytes(content, s3cfg); err != nil {
+			glog.Warningf("parse s3 identities: %v", err)
+			return u.identities
+		}
+	}
+	u.identities = s3cfg.Identities
+	return u.identities
+}
+
+// canList tells whether the user can see the folder or the file at the path relative to the home
+func (user *ftpUser) canList(p string) bool {
+	if !user.isS3 {
+		return true
+	}
+	bucket, objectKey := splitBucketPath(p)
+	if bucket == "" {
+		// the buckets are filtered when listing
+		return true
+	}
+	return user.canDo(s3_constants.ACTION_LIST, bucket, objectKey) || user.canDo(s3_constants.ACTION_READ, bucket, objectKey)
+}
+
+// canRead tells whether the user can download the file at the path relative to the home
+func (user *ftpUser) canRead(p string) bool {
+	if !user.isS3 {
+		return true
+	}
+	bucket, objectKey := splitBucketPath(p)
+	return bucket != "" && user.canDo(s3_constants.ACTION_READ, bucket, objectKey)
+}
+
+// canWrite tells whether the user can change the folder or the file at the path relative to the home
+func (user *ftpUser) canWrite(p string) bool {
+	if p == "/" {
+		return false
+	}
+	if !user.isS3 {
+		return !user.readOnly
+	}
+	bucket, objectKey := splitBucketPath(p)
+	if objectKey == "" {
+		// creating, deleting or renaming a bucket
+		return user.canDo(s3_constants.ACTION_ADMIN, bucket, "")
+	}
+	return user.canDo(s3_constants.ACTION_WRITE, bucket, objectKey)
+}
+
+func (user *ftpUser) isAdmin() bool {
+	for _, a := range user.s3Actions {
+		if a == s3_constants.ACTION_ADMIN {
+			return true
+		}
+	}
+	return false
+}
+
+// canDo follows the same rules as the S3 identities, allowing the actions
+// on all buckets, on one bucket as "Write:bucket", or on a prefix as "Write:bucket/prefix*"
+func (user *ftpUser) canDo(action, bucket, objectKey string) bool {
+	for _, a := range user.s3Actions {
+		if a == s3_constants.ACTION_ADMIN || a == action {
+			return true
+		}
+	}
+	target := action + ":" + bucket + objectKey
+	adminTarget := s3_constants.ACTION_ADMIN + ":" + bucket + objectKey
+	for _, a := range user.s3Actions {
+		if strings.HasSuffix(a, "*") {
+			if strings.HasPrefix(target, a[:len(a)-1]) || strings.HasPrefix(adminTarget, a[:len(a)-1]) {
+				return true
+			}
+		} else if a == action+":"+bucket || a == s3_constants.ACTION_ADMIN+":"+bucket {
+			return true
+		}
+	}
+	return false
+}
+
+// splitBucketPath splits the path relative to the buckets folder into the bucket and the object key, e.g. "/b/x/y" into "b" and "/x/y"
+func splitBucketPath(p string) (bucket, objectKey string) {
+	bucket, objectKey, found := strings.Cut(strings.TrimPrefix(p, "/"), "/")
+	if found {
+		objectKey = "/" + objectKey
+	}
+	return
+}
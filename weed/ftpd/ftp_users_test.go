@@ -0,0 +1,143 @@
+package ftpd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3_constants"
+)
+
+func TestFtpUsersFile(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret1"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	usersFile := filepath.Join(t.TempDir(), "users.json")
+	content := `{"users": [
+		{"name": "partner1", "password": "` + string(hash) + `", "home": "partners/partner1"},
+		{"name": "auditor", "password": "secret2", "home": "/partners/", "readOnly": true}
+	]}`
+	if err = os.WriteFile(usersFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	users, err := newFtpUsers(&FtpServer{option: &FtpServerOption{FtpRoot: "/ftp", UsersFile: usersFile, S3ConfigFile: "/dev/null"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := users.authenticate("partner1", "secret1")
+	if err != nil {
+		t.Fatalf("partner1 login: %v", err)
+	}
+	if user.home != "/ftp/partners/partner1" || user.readOnly {
+		t.Errorf("partner1: unexpected user %+v", user)
+	}
+	if !user.canWrite("/upload.csv") || user.canWrite("/") {
+		t.Errorf("partner1: unexpected write permissions")
+	}
+
+	user, err = users.authenticate("auditor", "secret2")
+	if err != nil {
+		t.Fatalf("auditor login: %v", err)
+	}
+	if user.home != "/partners" || !user.readOnly {
+		t.Errorf("auditor: unexpected user %+v", user)
+	}
+	if user.canWrite("/partner1/upload.csv") || !user.canRead("/partner1/upload.csv") {
+		t.Errorf("auditor: unexpected permissions")
+	}
+
+	if _, err = users.authenticate("partner1", "secret2"); err != errLoginFailed {
+		t.Errorf("partner1 with wrong password: %v", err)
+	}
+	if _, err = users.authenticate("nobody", "secret1"); err != errLoginFailed {
+		t.Errorf("unknown user: %v", err)
+	}
+}
+
+func TestFtpUserS3Permissions(t *testing.T) {
+	user := &ftpUser{
+		isS3: true,
+		s3Actions: []string{
+			s3_constants.ACTION_READ + ":bucket1",
+			s3_constants.ACTION_WRITE + ":bucket1/incoming/*",
+			s3_constants.ACTION_ADMIN + ":bucket2",
+		},
+	}
+
+	tests := []struct {
+		path                       string
+		canList, canRead, canWrite bool
+	}{
+		{"/", true, false, false},
+		{"/bucket1", true, true, false},
+		{"/bucket1/report.csv", true, true, false},
+		{"/bucket1/incoming/upload.csv", true, true, true},
+		{"/bucket2", true, true, true},
+		{"/bucket2/dir/file", true, true, true},
+		{"/bucket3", false, false, false},
+		{"/bucket3/file", false, false, false},
+	}
+	for _, tt := range tests {
+		if got := user.canList(tt.path); got != tt.canList {
+			t.Errorf("canList(%s) = %v, want %v", tt.path, got, tt.canList)
+		}
+		if got := user.canRead(tt.path); got != tt.canRead {
+			t.Errorf("canRead(%s) = %v, want %v", tt.path, got, tt.canRead)
+		}
+		if got := user.canWrite(tt.path); got != tt.canWrite {
+			t.Errorf("canWrite(%s) = %v, want %v", tt.path, got, tt.canWrite)
+		}
+	}
+}
+
+func TestSplitBucketPath(t *testing.T) {
+	tests := []struct {
+		path, bucket, objectKey string
+	}{
+		{"/", "", ""},
+		{"/b", "b", ""},
+		{"/b/x/y", "b", "/x/y"},
+	}
+	for _, tt := range tests {
+		bucket, objectKey := splitBucketPath(tt.path)
+		if bucket != tt.bucket || objectKey != tt.objectKey {
+			t.Errorf("splitBucketPath(%s) = %s %s, want %s %s", tt.path, bucket, objectKey, tt.bucket, tt.objectKey)
+		}
+	}
+}
+
+func TestFtpS3IdentitiesRequireTls(t *testing.T) {
+	s3ConfigFile := filepath.Join(t.TempDir(), "s3.json")
+	content := `{"identities": [{"name": "partner", "credentials": [{"accessKey": "AK", "secretKey": "SK"}], "actions": ["Read", "Write"]}]}`
+	if err := os.WriteFile(s3ConfigFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		tlsMode     string
+		tlsRequired bool
+		canLogin    bool
+	}{
+		{TlsModeNone, false, false},
+		{TlsModeExplicit, false, false},
+		{TlsModeExplicit, true, true},
+		{TlsModeImplicit, false, true},
+	} {
+		users, err := newFtpUsers(&FtpServer{option: &FtpServerOption{BucketsPath: "/buckets", S3ConfigFile: s3ConfigFile, TlsMode: tt.tlsMode, TlsRequired: tt.tlsRequired}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		user, err := users.authenticate("AK", "SK")
+		if tt.canLogin != (err == nil) {
+			t.Errorf("tls mode %q required %v: login error %v", tt.tlsMode, tt.tlsRequired, err)
+		}
+		if err == nil && (!user.isS3 || user.home != "/buckets") {
+			t.Errorf("unexpected s3 user %+v", user)
+		}
+	}
+}
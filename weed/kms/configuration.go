@@ -0,0 +1,70 @@
+package kms
+
+import (
+	"errors"
+
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/util"
+)
+
+// KeyProvider keeps the master keys, and uses them to protect the data keys encrypting objects
+type KeyProvider interface {
+	// GetName gets the name to locate the configuration in kms.toml file
+	GetName() string
+	// Initialize initializes the key provider
+	Initialize(configuration util.Configuration, prefix string) error
+	// GenerateDataKey creates a data key, encrypted by the master key keyId, or by the default master key if keyId is empty
+	GenerateDataKey(keyId string) (*DataKey, error)
+	// Decrypt decrypts a data key encrypted by the master key keyId
+	Decrypt(keyId string, ciphertext []byte) ([]byte, error)
+}
+
+// DataKey is a data key both in plain text and encrypted by its master key
+type DataKey struct {
+	KeyId      string
+	Plaintext  []byte
+	Ciphertext []byte
+}
+
+var (
+	KeyProviders []KeyProvider
+
+	Provider KeyProvider
+
+	ErrKeyNotFound = errors.New("kms key not found")
+)
+
+func LoadConfiguration(config *util.ViperProxy, prefix string) {
+
+	if config == nil {
+		return
+	}
+
+	validateOneEnabledProvider(config, prefix)
+
+	for _, provider := range KeyProviders {
+		if config.GetBool(prefix + provider.GetName() + ".enabled") {
+			if err := provider.Initialize(config, prefix+provider.GetName()+"."); err != nil {
+				glog.Fatalf("Failed to initialize kms for %s: %+v",
+					provider.GetName(), err)
+			}
+			Provider = provider
+			glog.V(0).Infof("Configure kms key provider for %s", provider.GetName())
+			return
+		}
+	}
+
+}
+
+func validateOneEnabledProvider(config *util.ViperProxy, prefix string) {
+	enabledProvider := ""
+	for _, provider := range KeyProviders {
+		if config.GetBool(prefix + provider.GetName() + ".enabled") {
+			if enabledProvider == "" {
+				enabledProvider = provider.GetName()
+			} else {
+				glog.Fatalf("KMS key provider is enabled for both %s and %s", enabledProvider, provider.GetName())
+			}
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package local
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/kms"
+	"github.com/seaweedfs/seaweedfs/weed/util"
+)
+
+func init() {
+	kms.KeyProviders = append(kms.KeyProviders, &LocalKms{})
+}
+
+// LocalKms keeps the master keys in a local file, one "keyId base64Key" pair per line.
+// It is meant for testing, since the master keys are stored next to the data they protect.
+type LocalKms struct {
+	defaultKeyId string
+	keys         map[string]util.CipherKey
+}
+
+func (k *LocalKms) GetName() string {
+	return "local"
+}
+
+func (k *LocalKms) Initialize(configuration util.Configuration, prefix string) (err error) {
+	configuration.SetDefault(prefix+"keyFile", "./kms.keys")
+	configuration.SetDefault(prefix+"defaultKeyId", "default")
+	glog.V(0).Infof("kms.local.keyFile: %v", configuration.GetString(prefix+"keyFile"))
+	return k.initialize(
+		util.ResolvePath(configuration.GetString(prefix+"keyFile")),
+		configuration.GetString(prefix+"defaultKeyId"),
+	)
+}
+
+func (k *LocalKms) initialize(keyFile string, defaultKeyId string) error {
+	k.defaultKeyId = defaultKeyId
+	data, err := os.ReadFile(keyFile)
+	if os.IsNotExist(err) {
+		// create the default master key on first use
+		key := util.GenCipherKey()
+		data = []byte(fmt.Sprintf("%s %s\n", defaultKeyId, base64.StdEncoding.EncodeToString(key)))
+		if err = os.WriteFile(keyFile, data, 0600); err != nil {
+			return fmt.Errorf("create kms key file %s: %v", keyFile, err)
+		}
+	} else if err != nil {
+		return fmt.Errorf("read kms key file %s: %v", keyFile, err)
+	}
+	if k.keys, err = parseKeys(data); err != nil {
+		return fmt.Errorf("parse kms key file %s: %v", keyFile, err)
+	}
+	if _, found := k.keys[defaultKeyId]; !found {
+		return fmt.Errorf("default key %s not found in kms key file %s", defaultKeyId, keyFile)
+	}
+	return nil
+}
+
+func parseKeys(data []byte) (map[string]util.CipherKey, error) {
+	keys := make(map[string]util.CipherKey)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid line %q", line)
+		}
+		key, err := base64.StdEncoding.DecodeString(fields[1])
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("key %s is not a base64 encoded 256-bit key", fields[0])
+		}
+		keys[fields[0]] = key
+	}
+	return keys, scanner.Err()
+}
+
+func (k *LocalKms) GenerateDataKey(keyId string) (*kms.DataKey, error) {
+	if keyId == "" {
+		keyId = k.defaultKeyId
+	}
+	masterKey, found := k.keys[keyId]
+	if !found {
+		return nil, kms.ErrKeyNotFound
+	}
+	plaintext := util.GenCipherKey()
+	ciphertext, err := util.Encrypt(plaintext, masterKey)
+	if err != nil {
+		return nil, err
+	}
+	return &kms.DataKey{
+		KeyId:      keyId,
+		Plaintext:  plaintext,
+		Ciphertext: ciphertext,
+	}, nil
+}
+
+func (k *LocalKms) Decrypt(keyId string, ciphertext []byte) ([]byte, error) {
+	masterKey, found := k.keys[keyId]
+	if !found {
+		return nil, kms.ErrKeyNotFound
+	}
+	return util.Decrypt(ciphertext, masterKey)
+}
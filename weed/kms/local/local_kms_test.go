@@ -0,0 +1,51 @@
+package local
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/seaweedfs/seaweedfs/weed/kms"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalKms(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "kms.keys")
+
+	k := &LocalKms{}
+	assert.Nil(t, k.initialize(keyFile, "default"))
+	_, err := os.Stat(keyFile)
+	assert.Nil(t, err, "key file created")
+
+	dataKey, err := k.GenerateDataKey("")
+	assert.Nil(t, err)
+	assert.Equal(t, "default", dataKey.KeyId)
+	assert.Equal(t, 32, len(dataKey.Plaintext))
+	assert.NotEqual(t, dataKey.Plaintext, dataKey.Ciphertext)
+
+	// the keys are loaded again from the key file
+	reloaded := &LocalKms{}
+	assert.Nil(t, reloaded.initialize(keyFile, "default"))
+	plaintext, err := reloaded.Decrypt("default", dataKey.Ciphertext)
+	assert.Nil(t, err)
+	assert.Equal(t, dataKey.Plaintext, plaintext)
+
+	_, err = reloaded.GenerateDataKey("other")
+	assert.Equal(t, kms.ErrKeyNotFound, err)
+	_, err = reloaded.Decrypt("other", dataKey.Ciphertext)
+	assert.Equal(t, kms.ErrKeyNotFound, err)
+
+	assert.NotNil(t, (&LocalKms{}).initialize(keyFile, "missing"))
+}
+
+func TestParseKeys(t *testing.T) {
+	keys, err := parseKeys([]byte("# comment\n\na AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(keys))
+	assert.Equal(t, 32, len(keys["a"]))
+
+	_, err = parseKeys([]byte("a c2hvcnQ=\n"))
+	assert.NotNil(t, err)
+	_, err = parseKeys([]byte("a\n"))
+	assert.NotNil(t, err)
+}
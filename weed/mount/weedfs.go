@@ -14,6 +14,7 @@ import (
 	"github.com/hanwen/go-fuse/v2/fuse"
 	"google.golang.org/grpc"
 
+	"github.com/seaweedfs/seaweedfs/weed/cluster/lock_manager"
 	"github.com/seaweedfs/seaweedfs/weed/filer"
 	"github.com/seaweedfs/seaweedfs/weed/mount/meta_cache"
 	"github.com/seaweedfs/seaweedfs/weed/pb"
@@ -82,6 +83,8 @@ type WFS struct {
 	IsOverQuota       bool
 	fhLockTable       *util.LockTable[FileHandleId]
 	FilerConf         *filer.FilerConf
+	heldLocks         *lock_manager.RangeLockManager
+	lockClient        string
 }
 
 func NewSeaweedFileSystem(option *Option) *WFS {
@@ -93,7 +96,10 @@ func NewSeaweedFileSystem(option *Option) *WFS {
 		fhMap:         NewFileHandleToInode(),
 		dhMap:         NewDirectoryHandleToInode(),
 		fhLockTable:   util.NewLockTable[FileHandleId](),
+		heldLocks:     lock_manager.NewRangeLockManager(),
 	}
+	hostname, _ := os.Hostname()
+	wfs.lockClient = fmt.Sprintf("%s:%s:%d", hostname, option.MountDirectory, wfs.signature)
 
 	wfs.option.filerIndex = int32(rand.Intn(len(option.FilerAddresses)))
 	wfs.option.setupUniqueCacheDirectory()
@@ -152,6 +158,7 @@ func (wfs *WFS) StartBackgroundTasks() error {
 	startTime := time.Now()
 	go meta_cache.SubscribeMetaEvents(wfs.metaCache, wfs.signature, wfs, wfs.option.FilerMountRootPath, startTime.UnixNano())
 	go wfs.loopCheckQuota()
+	go wfs.loopRenewRangeLocks()
 
 	return nil
 }
@@ -0,0 +1,139 @@
+package mount
+
+import (
+	"math"
+	"syscall"
+	"time"
+
+	"github.com/hanwen/go-fuse/v2/fuse"
+
+	"github.com/seaweedfs/seaweedfs/weed/filer"
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/util"
+)
+
+// These are the fallocate modes of Linux
+const (
+	FALLOC_FL_KEEP_SIZE      uint32 = 0x01 // do not change the file size
+	FALLOC_FL_PUNCH_HOLE     uint32 = 0x02 // de-allocate the range
+	FALLOC_FL_COLLAPSE_RANGE uint32 = 0x08 // remove the range without leaving a hole
+	FALLOC_FL_ZERO_RANGE     uint32 = 0x10 // convert the range to zeros
+	FALLOC_FL_INSERT_RANGE   uint32 = 0x20 // insert a hole without overwriting any data
+)
+
+/**
+ * Allocates space for an open file
+ *
+ * This function ensures that required space is allocated for specified
+ * file.  If this function returns success then any subsequent write
+ * request to specified range is guaranteed not to fail because of lack
+ * of space on the file system media.
+ */
+func (wfs *WFS) Fallocate(cancel <-chan struct{}, in *fuse.FallocateIn) (code fuse.Status) {
+	// See https://man7.org/linux/man-pages/man2/fallocate.2.html
+	// The volumes do not reserve space, so allocating a range only extends the file size, leaving a hole.
+	// Punching a hole drops or trims the chunks in the range, and the holes are read back as zeros.
+
+	if in.Mode&(FALLOC_FL_COLLAPSE_RANGE|FALLOC_FL_INSERT_RANGE) != 0 {
+		return fuse.Status(syscall.EOPNOTSUPP)
+	}
+	if in.Mode&FALLOC_FL_PUNCH_HOLE != 0 && in.Mode&FALLOC_FL_KEEP_SIZE == 0 {
+		return fuse.Status(syscall.EOPNOTSUPP)
+	}
+	if in.Length == 0 || in.Offset > math.MaxInt64 || in.Length > math.MaxInt64-in.Offset {
+		return fuse.EINVAL
+	}
+	if wfs.IsOverQuota && in.Mode&FALLOC_FL_PUNCH_HOLE == 0 {
+		return fuse.Status(syscall.ENOSPC)
+	}
+
+	fh := wfs.GetHandle(FileHandleId(in.Fh))
+	if fh == nil {
+		return fuse.EBADF
+	}
+
+	isPunch := in.Mode&(FALLOC_FL_PUNCH_HOLE|FALLOC_FL_ZERO_RANGE) != 0
+	if isPunch {
+		// the dirty pages in the range are replaced by the hole
+		if err := fh.dirtyPages.FlushData(); err != nil {
+			glog.Errorf("fallocate %s flush: %v", fh.FullPath(), err)
+			return fuse.EIO
+		}
+	}
+
+	fhActiveLock := fh.wfs.fhLockTable.AcquireLock("Fallocate", fh.fh, util.ExclusiveLock)
+	defer fh.wfs.fhLockTable.ReleaseLock(fh.fh, fhActiveLock)
+
+	entry := fh.GetEntry()
+	if entry == nil {
+		return fuse.ENOENT
+	}
+	if entry.IsDirectory {
+		return fuse.EISDIR
+	}
+
+	start, stop := int64(in.Offset), int64(in.Offset+in.Length)
+	fileSize := int64(filer.FileSize(entry.GetEntry()))
+
+	glog.V(4).Infof("Fallocate %s fh %d mode %x [%d,%d) size %d", fh.FullPath(), fh.fh, in.Mode, start, stop, fileSize)
+
+	if isPunch {
+		// the small files are inlined in the entry
+		for i := start; i < min(stop, int64(len(entry.Content))); i++ {
+			entry.Content[i] = 0
+		}
+		chunks, _, err := filer.ResolveChunkManifest(wfs.LookupFn(), entry.GetChunks(), 0, math.MaxInt64)
+		if err != nil {
+			glog.Errorf("fallocate %s resolve chunks: %v", fh.FullPath(), err)
+			return fuse.EIO
+		}
+		chunks, zeroRanges := punchHole(chunks, start, stop)
+		entry.Chunks = chunks
+		fh.entryChunkGroup.SetChunks(chunks)
+
+		// the zeros are only needed inside the file
+		tsNs := time.Now().UnixNano()
+		for _, zeroRange := range zeroRanges {
+			zeroStart, zeroStop := zeroRange[0], min(zeroRange[1], fileSize)
+			for zeroStart < zeroStop {
+				size := min(zeroStop-zeroStart, wfs.option.ChunkSizeLimit)
+				fh.dirtyPages.AddPage(zeroStart, make([]byte, size), false, tsNs)
+				zeroStart += size
+			}
+		}
+		fh.dirtyMetadata = true
+	}
+
+	if in.Mode&FALLOC_FL_KEEP_SIZE == 0 && stop > fileSize {
+		entry.Attributes.FileSize = uint64(stop)
+		fh.dirtyMetadata = true
+	}
+	if fh.dirtyMetadata {
+		entry.Attributes.Mtime = time.Now().Unix()
+	}
+
+	return fuse.OK
+}
+
+// punchHole drops the chunks inside the range [start, stop), and trims the chunks ending inside it.
+// A chunk can not be trimmed at its head, so the ranges still covered by the chunks are returned, to be overwritten with zeros.
+func punchHole(chunks []*filer_pb.FileChunk, start, stop int64) (remaining []*filer_pb.FileChunk, zeroRanges [][2]int64) {
+	for _, chunk := range chunks {
+		chunkStart, chunkStop := chunk.Offset, chunk.Offset+int64(chunk.Size)
+		switch {
+		case chunkStop <= start || stop <= chunkStart:
+			remaining = append(remaining, chunk)
+		case start <= chunkStart && chunkStop <= stop:
+			glog.V(4).Infof("punched whole chunk %+v", chunk.GetFileIdString())
+		case chunkStart < start && chunkStop <= stop:
+			glog.V(4).Infof("punched chunk %+v from %d to %d", chunk.GetFileIdString(), chunk.Size, start-chunkStart)
+			chunk.Size = uint64(start - chunkStart)
+			remaining = append(remaining, chunk)
+		default:
+			remaining = append(remaining, chunk)
+			zeroRanges = append(zeroRanges, [2]int64{max(start, chunkStart), min(stop, chunkStop)})
+		}
+	}
+	return
+}
@@ -0,0 +1,28 @@
+package mount
+
+import (
+	"testing"
+
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPunchHole(t *testing.T) {
+	chunks := []*filer_pb.FileChunk{
+		{FileId: "1,1", Offset: 0, Size: 100},   // trimmed
+		{FileId: "1,2", Offset: 100, Size: 100}, // dropped
+		{FileId: "1,3", Offset: 200, Size: 100}, // its head is zeroed
+		{FileId: "1,4", Offset: 300, Size: 100}, // kept
+		{FileId: "1,5", Offset: 0, Size: 400},   // the hole is zeroed
+	}
+
+	remaining, zeroRanges := punchHole(chunks, 50, 250)
+
+	var fileIds []string
+	for _, chunk := range remaining {
+		fileIds = append(fileIds, chunk.FileId)
+	}
+	assert.Equal(t, []string{"1,1", "1,3", "1,4", "1,5"}, fileIds)
+	assert.Equal(t, uint64(50), remaining[0].Size)
+	assert.Equal(t, [][2]int64{{200, 250}, {50, 250}}, zeroRanges)
+}
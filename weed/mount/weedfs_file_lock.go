@@ -0,0 +1,230 @@
+package mount
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"syscall"
+	"time"
+
+	"github.com/hanwen/go-fuse/v2/fuse"
+	"github.com/seaweedfs/seaweedfs/weed/cluster/lock_manager"
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/util"
+)
+
+// The POSIX record locks (fcntl) and the BSD locks (flock) are advisory locks kept by the filers,
+// so they are visible to all the mounts. The two kinds of locks are independent of each other, as on Linux.
+// Each lock is held by a lock owner of this mount, and expires unless renewed by this mount.
+// The locks of a file are keyed by its path, and are not carried over by a rename.
+
+func rangeLockKey(fullPath util.FullPath, lkFlags uint32) string {
+	if lkFlags&fuse.FUSE_LK_FLOCK != 0 {
+		return "flock:" + string(fullPath)
+	}
+	return "posix:" + string(fullPath)
+}
+
+func (wfs *WFS) GetLk(cancel <-chan struct{}, in *fuse.LkIn, out *fuse.LkOut) (code fuse.Status) {
+	fh := wfs.GetHandle(FileHandleId(in.Fh))
+	if fh == nil {
+		return fuse.ENOENT
+	}
+
+	conflict, err := wfs.requestRangeLock(&filer_pb.RangeLockRequest{
+		Name:   rangeLockKey(fh.FullPath(), in.LkFlags),
+		Lock:   wfs.toRangeLock(in),
+		IsTest: true,
+	})
+	if err != nil {
+		glog.Errorf("test lock %s: %v", fh.FullPath(), err)
+		return fuse.EIO
+	}
+
+	if conflict == nil {
+		out.Lk = fuse.FileLock{Typ: syscall.F_UNLCK}
+		return fuse.OK
+	}
+	out.Lk = fuse.FileLock{
+		Start: uint64(conflict.Start),
+		End:   uint64(conflict.End),
+		Typ:   syscall.F_RDLCK,
+		Pid:   conflict.Pid,
+	}
+	if conflict.IsExclusive {
+		out.Lk.Typ = syscall.F_WRLCK
+	}
+	return fuse.OK
+}
+
+func (wfs *WFS) SetLk(cancel <-chan struct{}, in *fuse.LkIn) (code fuse.Status) {
+	return wfs.setLk(cancel, in, false)
+}
+
+func (wfs *WFS) SetLkw(cancel <-chan struct{}, in *fuse.LkIn) (code fuse.Status) {
+	return wfs.setLk(cancel, in, true)
+}
+
+func (wfs *WFS) setLk(cancel <-chan struct{}, in *fuse.LkIn, isWait bool) (code fuse.Status) {
+	fh := wfs.GetHandle(FileHandleId(in.Fh))
+	if fh == nil {
+		return fuse.ENOENT
+	}
+	key := rangeLockKey(fh.FullPath(), in.LkFlags)
+	lock := wfs.toRangeLock(in)
+
+	if in.Lk.Typ == syscall.F_UNLCK {
+		if err := wfs.unlockRange(key, lock); err != nil {
+			glog.Errorf("unlock %s: %v", key, err)
+			return fuse.EIO
+		}
+		return fuse.OK
+	}
+
+	backoff := 10 * time.Millisecond
+	for {
+		conflict, err := wfs.requestRangeLock(&filer_pb.RangeLockRequest{
+			Name:          key,
+			Lock:          lock,
+			SecondsToLock: int64(lock_manager.LiveLockTTL / time.Second),
+		})
+		if err != nil {
+			glog.Errorf("lock %s: %v", key, err)
+			return fuse.EIO
+		}
+		if conflict == nil {
+			wfs.heldLocks.Unlock(key, fromRangeLock(lock))
+			wfs.heldLocks.InsertLocks(key, []*lock_manager.RangeLock{fromRangeLock(lock)})
+			return fuse.OK
+		}
+		if !isWait {
+			return fuse.EAGAIN
+		}
+		glog.V(4).Infof("lock %s %v waits for %v", key, lock, conflict)
+		select {
+		case <-cancel:
+			return fuse.EINTR
+		case <-time.After(backoff):
+		}
+		if backoff < time.Second {
+			backoff *= 2
+		}
+	}
+}
+
+// unlockOwnerLocks releases all the locks of the lock owner on the file
+func (wfs *WFS) unlockOwnerLocks(fullPath util.FullPath, lkFlags uint32, owner uint64) {
+	key := rangeLockKey(fullPath, lkFlags)
+	lock := &filer_pb.RangeLock{
+		Start:  0,
+		End:    math.MaxInt64,
+		Client: wfs.lockClient,
+		Owner:  owner,
+	}
+	for _, held := range wfs.heldLocks.GetLocks(key) {
+		if held.Owner == owner {
+			if err := wfs.unlockRange(key, lock); err != nil {
+				glog.Errorf("unlock %s of owner %d: %v", key, owner, err)
+			}
+			return
+		}
+	}
+}
+
+func (wfs *WFS) unlockRange(key string, lock *filer_pb.RangeLock) error {
+	wfs.heldLocks.Unlock(key, fromRangeLock(lock))
+	_, err := wfs.requestRangeLock(&filer_pb.RangeLockRequest{
+		Name:     key,
+		Lock:     lock,
+		IsUnlock: true,
+	})
+	return err
+}
+
+func (wfs *WFS) requestRangeLock(request *filer_pb.RangeLockRequest) (conflict *filer_pb.RangeLock, err error) {
+	request.Client = wfs.lockClient
+	err = wfs.WithFilerClient(false, func(client filer_pb.SeaweedFilerClient) error {
+		resp, err := client.DistributedRangeLock(context.Background(), request)
+		if err != nil {
+			return err
+		}
+		if resp.Error != "" {
+			return fmt.Errorf("%s", resp.Error)
+		}
+		conflict = resp.Conflict
+		return nil
+	})
+	return
+}
+
+// loopRenewRangeLocks keeps the locks held by this mount alive on the filers,
+// and re-creates them on the filers taking over the lock keys
+func (wfs *WFS) loopRenewRangeLocks() {
+	for {
+		time.Sleep(lock_manager.RenewInterval)
+
+		for _, key := range wfs.heldLocks.Keys() {
+			var heldLocks []*filer_pb.RangeLock
+			for _, held := range wfs.heldLocks.GetLocks(key) {
+				heldLocks = append(heldLocks, toRangeLock(held))
+			}
+			if len(heldLocks) == 0 {
+				continue
+			}
+			conflict, err := wfs.requestRangeLock(&filer_pb.RangeLockRequest{
+				Name:          key,
+				IsRenew:       true,
+				HeldLocks:     heldLocks,
+				SecondsToLock: int64(lock_manager.LiveLockTTL / time.Second),
+			})
+			if err != nil {
+				glog.Warningf("renew locks %s: %v", key, err)
+				continue
+			}
+			if conflict != nil {
+				glog.Errorf("lost locks %s, conflicting with %v", key, conflict)
+				for _, held := range heldLocks {
+					wfs.heldLocks.Unlock(key, fromRangeLock(held))
+				}
+			}
+		}
+	}
+}
+
+func (wfs *WFS) toRangeLock(in *fuse.LkIn) *filer_pb.RangeLock {
+	end := in.Lk.End
+	if end > math.MaxInt64 {
+		end = math.MaxInt64
+	}
+	return &filer_pb.RangeLock{
+		Start:       int64(in.Lk.Start),
+		End:         int64(end),
+		IsExclusive: in.Lk.Typ == syscall.F_WRLCK,
+		Client:      wfs.lockClient,
+		Owner:       in.Owner,
+		Pid:         in.Lk.Pid,
+	}
+}
+
+func fromRangeLock(lock *filer_pb.RangeLock) *lock_manager.RangeLock {
+	return &lock_manager.RangeLock{
+		Start:       lock.Start,
+		End:         lock.End,
+		IsExclusive: lock.IsExclusive,
+		Client:      lock.Client,
+		Owner:       lock.Owner,
+		Pid:         lock.Pid,
+	}
+}
+
+func toRangeLock(lock *lock_manager.RangeLock) *filer_pb.RangeLock {
+	return &filer_pb.RangeLock{
+		Start:       lock.Start,
+		End:         lock.End,
+		IsExclusive: lock.IsExclusive,
+		Client:      lock.Client,
+		Owner:       lock.Owner,
+		Pid:         lock.Pid,
+	}
+}
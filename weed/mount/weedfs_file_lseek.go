@@ -35,6 +35,12 @@ func (wfs *WFS) Lseek(cancel <-chan struct{}, in *fuse.LseekIn, out *fuse.LseekO
 		return fuse.EBADF
 	}
 
+	// the holes are found from the chunks, so the dirty pages are saved as chunks first
+	if err := fh.dirtyPages.FlushData(); err != nil {
+		glog.Errorf("Lseek %s flush: %v", fh.FullPath(), err)
+		return fuse.EIO
+	}
+
 	// lock the file until the proper offset was calculated
 	fhActiveLock := fh.wfs.fhLockTable.AcquireLock("Lseek", fh.fh, util.SharedLock)
 	defer fh.wfs.fhLockTable.ReleaseLock(fh.fh, fhActiveLock)
@@ -54,6 +60,16 @@ func (wfs *WFS) Lseek(cancel <-chan struct{}, in *fuse.LseekIn, out *fuse.LseekO
 		return ENXIO
 	}
 
+	// the content inlined in the entry has no holes
+	if len(fh.GetEntry().GetEntry().Content) > 0 {
+		if in.Whence == SEEK_DATA {
+			out.Offset = uint64(offset)
+		} else {
+			out.Offset = uint64(fileSize)
+		}
+		return fuse.OK
+	}
+
 	// search chunks for the offset
 	found, offset := fh.entryChunkGroup.SearchChunks(offset, fileSize, in.Whence)
 	if found {
@@ -61,13 +77,11 @@ func (wfs *WFS) Lseek(cancel <-chan struct{}, in *fuse.LseekIn, out *fuse.LseekO
 		return fuse.OK
 	}
 
-	// in case we found no exact matches, we return the recommended fallbacks, that is:
-	// original offset for SEEK_DATA or end of file for an implicit hole
+	// no data after the offset, or an implicit hole at the end of the file
 	if in.Whence == SEEK_DATA {
-		out.Offset = in.Offset
-	} else {
-		out.Offset = uint64(fileSize)
+		return ENXIO
 	}
+	out.Offset = uint64(fileSize)
 
 	return fuse.OK
 }
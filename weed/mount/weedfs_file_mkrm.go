@@ -3,6 +3,7 @@ package mount
 import (
 	"context"
 	"fmt"
+	"strings"
 	"syscall"
 	"time"
 
@@ -98,7 +99,7 @@ func (wfs *WFS) Mknod(cancel <-chan struct{}, in *fuse.MknodIn, name string, out
 	glog.V(3).Infof("mknod %s: %v", entryFullPath, err)
 
 	if err != nil {
-		return fuse.EIO
+		return ioErrorStatus(err)
 	}
 
 	// this is to increase nlookup counter
@@ -140,6 +141,9 @@ func (wfs *WFS) Unlink(cancel <-chan struct{}, header *fuse.InHeader, name strin
 	err := filer_pb.Remove(wfs, string(dirFullPath), name, isDeleteData, false, false, false, []int32{wfs.signature})
 	if err != nil {
 		glog.V(0).Infof("remove %s: %v", entryFullPath, err)
+		if strings.Contains(err.Error(), filer.MsgReadOnlySnapshot) {
+			return fuse.EROFS
+		}
 		return fuse.OK
 	}
 
@@ -57,6 +57,9 @@ func (wfs *WFS) Flush(cancel <-chan struct{}, in *fuse.FlushIn) fuse.Status {
 		return fuse.ENOENT
 	}
 
+	// closing any file descriptor releases the POSIX locks of the process on the file
+	wfs.unlockOwnerLocks(fh.FullPath(), 0, in.LockOwner)
+
 	return wfs.doFlush(fh, in.Uid, in.Gid)
 }
 
@@ -173,7 +176,7 @@ func (wfs *WFS) doFlush(fh *FileHandle, uid, gid uint32) fuse.Status {
 
 	if err != nil {
 		glog.Errorf("%v fh %d flush: %v", fileFullPath, fh.fh, err)
-		return fuse.EIO
+		return ioErrorStatus(err)
 	}
 
 	if IsDebugFileReadWrite {
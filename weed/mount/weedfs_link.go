@@ -106,7 +106,7 @@ func (wfs *WFS) Link(cancel <-chan struct{}, in *fuse.LinkIn, name string, out *
 
 	if err != nil {
 		glog.V(0).Infof("Link %v -> %s: %v", oldEntryPath, newEntryPath, err)
-		return fuse.EIO
+		return ioErrorStatus(err)
 	}
 
 	wfs.inodeToPath.AddPath(oldEntry.Attributes.Inode, newEntryPath)
@@ -3,8 +3,12 @@ package mount
 import (
 	"context"
 	"fmt"
+	"github.com/hanwen/go-fuse/v2/fuse"
+	"github.com/seaweedfs/seaweedfs/weed/filer"
 	"github.com/seaweedfs/seaweedfs/weed/glog"
 	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"strings"
+	"syscall"
 	"time"
 )
 
@@ -52,3 +56,15 @@ func (wfs *WFS) loopCheckQuota() {
 	}
 
 }
+
+// ioErrorStatus returns EDQUOT if the filer rejected the change for a directory quota,
+// EROFS for a change in a snapshot, otherwise EIO
+func ioErrorStatus(err error) fuse.Status {
+	if strings.Contains(err.Error(), filer.MsgQuotaExceeded) {
+		return fuse.Status(syscall.EDQUOT)
+	}
+	if strings.Contains(err.Error(), filer.MsgReadOnlySnapshot) {
+		return fuse.EROFS
+	}
+	return fuse.EIO
+}
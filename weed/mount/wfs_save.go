@@ -40,7 +40,7 @@ func (wfs *WFS) saveEntry(path util.FullPath, entry *filer_pb.Entry) (code fuse.
 	})
 	if err != nil {
 		glog.Errorf("saveEntry %s: %v", path, err)
-		return fuse.EIO
+		return ioErrorStatus(err)
 	}
 
 	return fuse.OK
@@ -0,0 +1,87 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/seaweedfs/seaweedfs/weed/filer"
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/mq/topic"
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/pb/mq_pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// CommitOffsets Runs on any broker.
+// A read-process-write pipeline commits the consumed offsets of several partitions
+// after the messages it produced are acked.
+// The offsets are first saved together in one commit record of the consumer group,
+// so the subscribers resume from either all or none of them,
+// and then copied to the offset of each partition.
+// The commit is not a transaction with the published messages, and is not fenced against
+// a stale producer with the same id, so the pipelines get at-least-once delivery.
+// Exactly-once would need producer epochs fencing the zombie producers, and the offsets
+// committed atomically with the produced batches, which the brokers do not support.
+func (b *MessageQueueBroker) CommitOffsets(ctx context.Context, request *mq_pb.CommitOffsetsRequest) (resp *mq_pb.CommitOffsetsResponse, err error) {
+	if request.ConsumerGroup == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "missing consumer group")
+	}
+	t := topic.FromPbTopic(request.Topic)
+	for _, offset := range request.Offsets {
+		if offset.Partition == nil {
+			return nil, status.Errorf(codes.InvalidArgument, "missing partition of offset %d", offset.TsNs)
+		}
+	}
+
+	data, err := proto.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	topicDir := fmt.Sprintf("%s/%s/%s", filer.TopicsDir, t.Namespace, t.Name)
+	if err = b.WithFilerClient(false, func(client filer_pb.SeaweedFilerClient) error {
+		return filer.SaveInsideFiler(client, topicDir, toCommitFileName(request.ConsumerGroup), data)
+	}); err != nil {
+		return nil, fmt.Errorf("save commit of consumer group %s: %v", request.ConsumerGroup, err)
+	}
+	glog.V(0).Infof("topic %v consumer group %s committed %d offsets with producer %s", t, request.ConsumerGroup, len(request.Offsets), request.ProducerId)
+
+	for _, offset := range request.Offsets {
+		if err = b.saveConsumerGroupOffset(t, topic.FromPbPartition(offset.Partition), request.ConsumerGroup, offset.TsNs); err != nil {
+			// the offset is still read from the commit record
+			glog.Warningf("save consumer group %s offset of %v: %v", request.ConsumerGroup, offset.Partition, err)
+		}
+	}
+
+	return &mq_pb.CommitOffsetsResponse{}, nil
+}
+
+func toCommitFileName(consumerGroup string) string {
+	return fmt.Sprintf("%s.commit", consumerGroup)
+}
+
+// readCommittedOffset reads the offset of the partition from the commit record of the consumer group
+func (b *MessageQueueBroker) readCommittedOffset(t topic.Topic, p topic.Partition, consumerGroup string) (offset int64, found bool, err error) {
+	topicDir := fmt.Sprintf("%s/%s/%s", filer.TopicsDir, t.Namespace, t.Name)
+	err = b.WithFilerClient(false, func(client filer_pb.SeaweedFilerClient) error {
+		data, err := filer.ReadInsideFiler(client, topicDir, toCommitFileName(consumerGroup))
+		if err == filer_pb.ErrNotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		commit := &mq_pb.CommitOffsetsRequest{}
+		if err = proto.Unmarshal(data, commit); err != nil {
+			return err
+		}
+		for _, committed := range commit.Offsets {
+			if p.Equals(topic.FromPbPartition(committed.Partition)) {
+				offset, found = committed.TsNs, true
+			}
+		}
+		return nil
+	})
+	return
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/seaweedfs/seaweedfs/weed/pb/mq_pb"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 // ConfigureTopic Runs on any broker, but proxied to the balancer if not the balancer
@@ -49,8 +50,20 @@ func (b *MessageQueueBroker) ConfigureTopic(ctx context.Context, request *mq_pb.
 		// The gone assignees will die by themselves.
 	}
 
+	var currentRetention *mq_pb.TopicRetention
+	if resp != nil {
+		currentRetention = resp.Retention
+	}
+	retention := resolveTopicRetention(request.Retention, currentRetention)
+
 	if readErr == nil && assignErr == nil && len(resp.BrokerPartitionAssignments) == int(request.PartitionCount) {
 		glog.V(0).Infof("existing topic partitions %d: %+v", len(resp.BrokerPartitionAssignments), resp.BrokerPartitionAssignments)
+		if !proto.Equal(resp.Retention, retention) {
+			resp.Retention = retention
+			if err := b.fca.SaveTopicConfToFiler(request.Topic, resp); err != nil {
+				return nil, fmt.Errorf("configure topic retention: %v", err)
+			}
+		}
 		return
 	}
 
@@ -65,6 +78,7 @@ func (b *MessageQueueBroker) ConfigureTopic(ctx context.Context, request *mq_pb.
 	}
 	resp.BrokerPartitionAssignments = pub_balancer.AllocateTopicPartitions(b.PubBalancer.Brokers, request.PartitionCount)
 	resp.RecordType = request.RecordType
+	resp.Retention = retention
 
 	// save the topic configuration on filer
 	if err := b.fca.SaveTopicConfToFiler(request.Topic, resp); err != nil {
@@ -77,3 +91,14 @@ func (b *MessageQueueBroker) ConfigureTopic(ctx context.Context, request *mq_pb.
 
 	return resp, err
 }
+
+// resolveTopicRetention keeps the current retention if none is requested, and removes it if the requested one is empty
+func resolveTopicRetention(requested, current *mq_pb.TopicRetention) *mq_pb.TopicRetention {
+	if requested == nil {
+		return current
+	}
+	if proto.Equal(requested, &mq_pb.TopicRetention{}) {
+		return nil
+	}
+	return requested
+}
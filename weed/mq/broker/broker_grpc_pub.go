@@ -111,8 +111,12 @@ func (b *MessageQueueBroker) PublishMessage(stream mq_pb.SeaweedMessaging_Publis
 		}
 	}()
 
-	// send a hello message
-	stream.Send(&mq_pb.PublishMessageResponse{})
+	// send a hello message, with the last sequence of the idempotent producer
+	helloResponse := &mq_pb.PublishMessageResponse{}
+	if initMessage.ProducerId != "" {
+		helloResponse.ProducerSequence = localTopicPartition.Producers.Sequence(initMessage.ProducerId)
+	}
+	stream.Send(helloResponse)
 
 	defer func() {
 		isClosed = true
@@ -139,6 +143,9 @@ func (b *MessageQueueBroker) PublishMessage(stream mq_pb.SeaweedMessaging_Publis
 		// The control message should still be sent to the follower
 		// to avoid timing issue when ack messages.
 
+		// the followers track the producer sequences too
+		dataMessage.ProducerId = initMessage.ProducerId
+
 		// send to the local partition
 		if err = localTopicPartition.Publish(dataMessage); err != nil {
 			return fmt.Errorf("topic %v partition %v publish error: %v", initMessage.Topic, initMessage.Partition, err)
@@ -2,7 +2,6 @@ package broker
 
 import (
 	"fmt"
-	"github.com/seaweedfs/seaweedfs/weed/filer"
 	"github.com/seaweedfs/seaweedfs/weed/glog"
 	"github.com/seaweedfs/seaweedfs/weed/mq/topic"
 	"github.com/seaweedfs/seaweedfs/weed/pb/mq_pb"
@@ -33,6 +32,9 @@ func (b *MessageQueueBroker) PublishFollowMe(stream mq_pb.SeaweedMessaging_Publi
 	inMemoryBuffers := buffered_queue.NewBufferedQueue[memBuffer](4)
 	logBuffer := b.buildFollowerLogBuffer(inMemoryBuffers)
 
+	// track the producer sequences, to save them if the leader stops before flushing
+	producers := topic.NewLocalPartitionProducers()
+
 	lastFlushTsNs := time.Now().UnixNano()
 
 	// follow each published messages
@@ -54,6 +56,9 @@ func (b *MessageQueueBroker) PublishFollowMe(stream mq_pb.SeaweedMessaging_Publi
 			// TODO: change this to DataMessage
 			// log the message
 			logBuffer.AddToBuffer(dataMessage)
+			if dataMessage.ProducerId != "" && dataMessage.Sequence > 0 {
+				producers.Accept(dataMessage.ProducerId, dataMessage.Sequence, dataMessage.TsNs)
+			}
 
 			// send back the ack
 			if err := stream.Send(&mq_pb.PublishFollowMeResponse{
@@ -69,6 +74,8 @@ func (b *MessageQueueBroker) PublishFollowMe(stream mq_pb.SeaweedMessaging_Publi
 			glog.V(0).Infof("topic %v partition %v publish stream flushed: %v", initMessage.Topic, initMessage.Partition, flushMessage)
 
 			lastFlushTsNs = flushMessage.TsNs
+			// the leader has saved the sequences of the flushed messages
+			producers.NotifyFlushed(flushMessage.TsNs)
 
 			// drop already flushed messages
 			for mem, found := inMemoryBuffers.PeekHead(); found; mem, found = inMemoryBuffers.PeekHead() {
@@ -93,12 +100,11 @@ func (b *MessageQueueBroker) PublishFollowMe(stream mq_pb.SeaweedMessaging_Publi
 		time.Sleep(113 * time.Millisecond)
 	}
 
-	topicDir := fmt.Sprintf("%s/%s/%s", filer.TopicsDir, t.Namespace, t.Name)
-	partitionGeneration := time.Unix(0, p.UnixTimeNs).UTC().Format(topic.TIME_FORMAT)
-	partitionDir := fmt.Sprintf("%s/%s/%04d-%04d", topicDir, partitionGeneration, p.RangeStart, p.RangeStop)
+	partitionDir := toPartitionDir(t, p)
 
 	// flush the remaining messages
 	inMemoryBuffers.CloseInput()
+	hasRemainingData := false
 	for mem, found := inMemoryBuffers.Dequeue(); found; mem, found = inMemoryBuffers.Dequeue() {
 		if len(mem.buf) == 0 {
 			continue
@@ -124,9 +130,25 @@ func (b *MessageQueueBroker) PublishFollowMe(stream mq_pb.SeaweedMessaging_Publi
 			}
 		}
 
+		hasRemainingData = true
 		glog.V(0).Infof("flushed remaining data at %v to %s size %d", mem.stopTime.UnixNano(), targetFile, len(mem.buf))
 	}
 
+	// the remaining messages are flushed, so are their producer sequences,
+	// together with the ones saved by the leader for the other producers
+	if hasRemainingData {
+		if savedSequences, readErr := b.readProducerSequences(partitionDir); readErr != nil {
+			glog.Warningf("read producer sequences from %s: %v", partitionDir, readErr)
+		} else {
+			producers.Load(savedSequences)
+			if producerSequences, hasChanges := producers.NotifyFlushed(time.Now().UnixNano()); hasChanges {
+				if err := b.saveProducerSequences(partitionDir, producerSequences); err != nil {
+					glog.Warningf("save producer sequences to %s: %v", partitionDir, err)
+				}
+			}
+		}
+	}
+
 	glog.V(0).Infof("shut down follower for %v %v", t, p)
 
 	return err
@@ -4,13 +4,20 @@ import (
 	"context"
 	"fmt"
 	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/mq/schema"
 	"github.com/seaweedfs/seaweedfs/weed/mq/sub_coordinator"
 	"github.com/seaweedfs/seaweedfs/weed/mq/topic"
 	"github.com/seaweedfs/seaweedfs/weed/pb"
 	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
 	"github.com/seaweedfs/seaweedfs/weed/pb/mq_pb"
+	"github.com/seaweedfs/seaweedfs/weed/pb/schema_pb"
 	"github.com/seaweedfs/seaweedfs/weed/util/log_buffer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"io"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -33,6 +40,11 @@ func (b *MessageQueueBroker) SubscribeMessage(stream mq_pb.SeaweedMessaging_Subs
 
 	glog.V(0).Infof("Subscriber %s on %v %v connected", req.GetInit().ConsumerId, t, partition)
 
+	recordFilter, filterErr := b.parseSubscribeFilter(t, req.GetInit().Filter)
+	if filterErr != nil {
+		return filterErr
+	}
+
 	localTopicPartition, getOrGenErr := b.GetOrGenerateLocalPartition(t, partition)
 	if getOrGenErr != nil {
 		return getOrGenErr
@@ -40,12 +52,16 @@ func (b *MessageQueueBroker) SubscribeMessage(stream mq_pb.SeaweedMessaging_Subs
 
 	localTopicPartition.Subscribers.AddSubscriber(clientName, topic.NewLocalSubscriber())
 	glog.V(0).Infof("Subscriber %s connected on %v %v", clientName, t, partition)
-	isConnected := true
+	var isConnected atomic.Bool
+	isConnected.Store(true)
 	sleepIntervalCount := 0
 
 	var counter int64
+	// the pending redeliveries are dropped when the subscriber disconnects
+	timers := newRedeliveryTimers()
 	defer func() {
-		isConnected = false
+		isConnected.Store(false)
+		timers.stop()
 		localTopicPartition.Subscribers.RemoveSubscriber(clientName)
 		glog.V(0).Infof("Subscriber %s on %v %v disconnected, sent %d", clientName, t, partition, counter)
 		if localTopicPartition.MaybeShutdownLocalPartition() {
@@ -55,6 +71,41 @@ func (b *MessageQueueBroker) SubscribeMessage(stream mq_pb.SeaweedMessaging_Subs
 
 	startPosition := b.getRequestPosition(req.GetInit())
 	imt := sub_coordinator.NewInflightMessageTracker(int(req.GetInit().Concurrency))
+	maxDeliveryAttempts := req.GetInit().MaxDeliveryAttempts
+	failures, loadErr := b.loadDeliveryFailures(t, partition, req.GetInit().ConsumerGroup, startPosition.UnixNano())
+	if loadErr != nil {
+		glog.Warningf("load delivery failures of consumer group %s on %v %v: %v", req.GetInit().ConsumerGroup, t, partition, loadErr)
+	}
+
+	// the messages are sent by both the subscribe loop and the redeliveries
+	var sendLock sync.Mutex
+	sendResponse := func(response *mq_pb.SubscribeMessageResponse) error {
+		sendLock.Lock()
+		defer sendLock.Unlock()
+		return stream.Send(response)
+	}
+	sendLogEntry := func(logEntry *filer_pb.LogEntry) error {
+		return sendResponse(&mq_pb.SubscribeMessageResponse{Message: &mq_pb.SubscribeMessageResponse_Data{
+			Data: &mq_pb.DataMessage{
+				Key:   logEntry.Key,
+				Value: logEntry.Data,
+				TsNs:  logEntry.TsNs,
+			},
+		}})
+	}
+	// keep the inflight messages, to redeliver the negatively acked ones
+	inflightEntries := newInflightLogEntries()
+	redeliverLater := func(logEntry *filer_pb.LogEntry, deliveries int32) {
+		inflightEntries.setWaiting(logEntry, true)
+		timers.schedule(redeliveryDelay(req.GetInit().RedeliveryDelayMs, deliveries), func() {
+			if isConnected.Load() && imt.IsMessageInflight(logEntry.Key, logEntry.TsNs) {
+				inflightEntries.setWaiting(logEntry, false)
+				if err := sendLogEntry(logEntry); err != nil {
+					glog.V(0).Infof("redeliver to subscriber %s: %v", clientName, err)
+				}
+			}
+		})
+	}
 
 	// connect to the follower
 	var subscribeFollowMeStream mq_pb.SeaweedMessaging_SubscribeFollowMeClient
@@ -89,14 +140,73 @@ func (b *MessageQueueBroker) SubscribeMessage(stream mq_pb.SeaweedMessaging_Subs
 		glog.V(0).Infof("follower %s connected", follower)
 	}
 
+	// the messages are acked by both the subscriber and the dead-letter worker
+	var ackLock sync.Mutex
+	var lastOffset int64
+	acknowledge := func(key []byte, tsNs int64) error {
+		ackLock.Lock()
+		defer ackLock.Unlock()
+		imt.AcknowledgeMessage(key, tsNs)
+		inflightEntries.remove(key, tsNs)
+		failures.clear(tsNs)
+		currentLastOffset := imt.GetOldestAckedTimestamp()
+		// fmt.Printf("%+v recv (%s,%d), oldest %d\n", partition, string(key), tsNs, currentLastOffset)
+		if subscribeFollowMeStream != nil && currentLastOffset > lastOffset {
+			if err := subscribeFollowMeStream.Send(&mq_pb.SubscribeFollowMeRequest{
+				Message: &mq_pb.SubscribeFollowMeRequest_Ack{
+					Ack: &mq_pb.SubscribeFollowMeRequest_AckMessage{
+						TsNs: currentLastOffset,
+					},
+				},
+			}); err != nil {
+				glog.Errorf("Error sending ack to follower: %v", err)
+				return err
+			}
+			lastOffset = currentLastOffset
+			// fmt.Printf("%+v forwarding ack %d\n", partition, lastOffset)
+		}
+		return nil
+	}
+
+	// move the poison messages to the dead-letter topic in the background, so they do not hold back the acks.
+	// The messages left when the subscriber disconnects are moved by the next subscriber.
+	deadLetters := make(chan *deadLetter, 32)
+	moveToDeadLetter := func(logEntry *filer_pb.LogEntry, failureCount int32, failure string) {
+		select {
+		case deadLetters <- &deadLetter{logEntry: logEntry, failures: failureCount, failure: failure}:
+		case <-ctx.Done():
+		}
+	}
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case dl := <-deadLetters:
+				if err := b.publishDeadLetter(req.GetInit(), dl.logEntry, dl.failures, dl.failure); err != nil {
+					glog.Errorf("topic %v partition %v subscriber %s dead letter %d: %v", t, partition, clientName, dl.logEntry.TsNs, err)
+					redeliverLater(dl.logEntry, dl.failures)
+					continue
+				}
+				acknowledge(dl.logEntry.Key, dl.logEntry.TsNs)
+			}
+		}
+	}()
+
+	// a subscriber disconnecting while processing a message counts as a failed delivery of it
+	defer func() {
+		if maxDeliveryAttempts > 0 {
+			failures.fail(inflightEntries.delivered()...)
+		}
+	}()
+
 	go func() {
-		var lastOffset int64
 		for {
 			ack, err := stream.Recv()
 			if err != nil {
 				if err == io.EOF {
 					// the client has called CloseSend(). This is to ack the close.
-					stream.Send(&mq_pb.SubscribeMessageResponse{Message: &mq_pb.SubscribeMessageResponse_Ctrl{
+					sendResponse(&mq_pb.SubscribeMessageResponse{Message: &mq_pb.SubscribeMessageResponse_Ctrl{
 						Ctrl: &mq_pb.SubscribeMessageResponse_SubscribeCtrlMessage{
 							IsEndOfStream: true,
 						},
@@ -106,32 +216,37 @@ func (b *MessageQueueBroker) SubscribeMessage(stream mq_pb.SeaweedMessaging_Subs
 				glog.V(0).Infof("topic %v partition %v subscriber %s lastOffset %d error: %v", t, partition, clientName, lastOffset, err)
 				break
 			}
+			if nack := ack.GetNack(); nack != nil {
+				logEntry := inflightEntries.get(nack.Key, nack.Sequence)
+				if !imt.IsMessageInflight(nack.Key, nack.Sequence) || logEntry == nil {
+					continue
+				}
+				deliveries := failures.fail(logEntry.TsNs)
+				// 0 max delivery attempts redelivers forever
+				if maxDeliveryAttempts <= 0 || deliveries < maxDeliveryAttempts {
+					redeliverLater(logEntry, deliveries)
+					continue
+				}
+				// stop redelivering the poison message, so it does not block the partition
+				inflightEntries.setWaiting(logEntry, true)
+				moveToDeadLetter(logEntry, deliveries, nack.Error)
+				continue
+			}
 			if ack.GetAck().Key == nil {
 				// skip ack for control messages
 				continue
 			}
-			imt.AcknowledgeMessage(ack.GetAck().Key, ack.GetAck().Sequence)
-			currentLastOffset := imt.GetOldestAckedTimestamp()
-			// fmt.Printf("%+v recv (%s,%d), oldest %d\n", partition, string(ack.GetAck().Key), ack.GetAck().Sequence, currentLastOffset)
-			if subscribeFollowMeStream != nil && currentLastOffset > lastOffset {
-				if err := subscribeFollowMeStream.Send(&mq_pb.SubscribeFollowMeRequest{
-					Message: &mq_pb.SubscribeFollowMeRequest_Ack{
-						Ack: &mq_pb.SubscribeFollowMeRequest_AckMessage{
-							TsNs: currentLastOffset,
-						},
-					},
-				}); err != nil {
-					glog.Errorf("Error sending ack to follower: %v", err)
-					break
-				}
-				lastOffset = currentLastOffset
-				// fmt.Printf("%+v forwarding ack %d\n", partition, lastOffset)
+			if err := acknowledge(ack.GetAck().Key, ack.GetAck().Sequence); err != nil {
+				break
 			}
 		}
-		if lastOffset > 0 {
-			glog.V(0).Infof("saveConsumerGroupOffset %v %v %v %v", t, partition, req.GetInit().ConsumerGroup, lastOffset)
-			if err := b.saveConsumerGroupOffset(t, partition, req.GetInit().ConsumerGroup, lastOffset); err != nil {
-				glog.Errorf("saveConsumerGroupOffset partition %v lastOffset %d: %v", partition, lastOffset, err)
+		ackLock.Lock()
+		savedOffset := lastOffset
+		ackLock.Unlock()
+		if savedOffset > 0 {
+			glog.V(0).Infof("saveConsumerGroupOffset %v %v %v %v", t, partition, req.GetInit().ConsumerGroup, savedOffset)
+			if err := b.saveConsumerGroupOffset(t, partition, req.GetInit().ConsumerGroup, savedOffset); err != nil {
+				glog.Errorf("saveConsumerGroupOffset partition %v lastOffset %d: %v", partition, savedOffset, err)
 			}
 		}
 		if subscribeFollowMeStream != nil {
@@ -146,7 +261,7 @@ func (b *MessageQueueBroker) SubscribeMessage(stream mq_pb.SeaweedMessaging_Subs
 	}()
 
 	return localTopicPartition.Subscribe(clientName, startPosition, func() bool {
-		if !isConnected {
+		if !isConnected.Load() {
 			return false
 		}
 		sleepIntervalCount++
@@ -174,20 +289,25 @@ func (b *MessageQueueBroker) SubscribeMessage(stream mq_pb.SeaweedMessaging_Subs
 		// reset the sleep interval count
 		sleepIntervalCount = 0
 
+		if recordFilter != nil && !matchesRecordFilter(recordFilter, logEntry) {
+			return false, nil
+		}
+
 		for imt.IsInflight(logEntry.Key) {
 			time.Sleep(137 * time.Millisecond)
 		}
 		if logEntry.Key != nil {
 			imt.EnflightMessage(logEntry.Key, logEntry.TsNs)
+			inflightEntries.add(logEntry)
+			// the previous subscribers failed too many times, or disconnected while processing it
+			if deliveries := failures.count(logEntry.TsNs); maxDeliveryAttempts > 0 && deliveries >= maxDeliveryAttempts {
+				inflightEntries.setWaiting(logEntry, true)
+				moveToDeadLetter(logEntry, deliveries, "failed by the previous subscribers")
+				return false, nil
+			}
 		}
 
-		if err := stream.Send(&mq_pb.SubscribeMessageResponse{Message: &mq_pb.SubscribeMessageResponse_Data{
-			Data: &mq_pb.DataMessage{
-				Key:   logEntry.Key,
-				Value: logEntry.Data,
-				TsNs:  logEntry.TsNs,
-			},
-		}}); err != nil {
+		if err := sendLogEntry(logEntry); err != nil {
 			glog.Errorf("Error sending data: %v", err)
 			return false, err
 		}
@@ -197,6 +317,34 @@ func (b *MessageQueueBroker) SubscribeMessage(stream mq_pb.SeaweedMessaging_Subs
 	})
 }
 
+// parseSubscribeFilter parses the filter of the subscriber against the record type of the topic
+func (b *MessageQueueBroker) parseSubscribeFilter(t topic.Topic, filter string) (*schema.RecordFilter, error) {
+	if filter == "" {
+		return nil, nil
+	}
+	conf, err := b.fca.ReadTopicConfFromFiler(t)
+	if err != nil {
+		return nil, fmt.Errorf("read topic %v conf: %v", t, err)
+	}
+	if conf.RecordType == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "filter %q on topic %v without record type", filter, t)
+	}
+	recordFilter, err := schema.ParseRecordFilter(filter, conf.RecordType)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid filter %q: %v", filter, err)
+	}
+	return recordFilter, nil
+}
+
+// matchesRecordFilter checks the record value of the message, and skips the messages not being a record value
+func matchesRecordFilter(recordFilter *schema.RecordFilter, logEntry *filer_pb.LogEntry) bool {
+	recordValue := &schema_pb.RecordValue{}
+	if err := proto.Unmarshal(logEntry.Data, recordValue); err != nil {
+		return false
+	}
+	return recordFilter.Matches(recordValue)
+}
+
 func (b *MessageQueueBroker) getRequestPosition(initMessage *mq_pb.SubscribeMessageRequest_InitMessage) (startPosition log_buffer.MessagePosition) {
 	if initMessage == nil {
 		return
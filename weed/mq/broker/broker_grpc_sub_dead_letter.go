@@ -0,0 +1,268 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/mq/pub_balancer"
+	"github.com/seaweedfs/seaweedfs/weed/mq/topic"
+	"github.com/seaweedfs/seaweedfs/weed/pb"
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/pb/mq_pb"
+	"github.com/seaweedfs/seaweedfs/weed/util"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	defaultRedeliveryDelay = time.Second
+	maxRedeliveryDelay     = 5 * time.Minute
+)
+
+// inflightLogEntries keeps the messages sent to a subscriber until they are acked,
+// with the negatively acked ones waiting to be redelivered
+type inflightLogEntries struct {
+	entries map[string]*filer_pb.LogEntry
+	waiting map[string]bool
+	sync.Mutex
+}
+
+func newInflightLogEntries() *inflightLogEntries {
+	return &inflightLogEntries{
+		entries: make(map[string]*filer_pb.LogEntry),
+		waiting: make(map[string]bool),
+	}
+}
+
+func (ie *inflightLogEntries) add(logEntry *filer_pb.LogEntry) {
+	ie.Lock()
+	defer ie.Unlock()
+	ie.entries[string(logEntry.Key)] = logEntry
+	delete(ie.waiting, string(logEntry.Key))
+}
+
+func (ie *inflightLogEntries) setWaiting(logEntry *filer_pb.LogEntry, isWaiting bool) {
+	ie.Lock()
+	defer ie.Unlock()
+	if isWaiting {
+		ie.waiting[string(logEntry.Key)] = true
+	} else {
+		delete(ie.waiting, string(logEntry.Key))
+	}
+}
+
+// delivered returns the timestamps of the messages being processed by the subscriber
+func (ie *inflightLogEntries) delivered() (timestamps []int64) {
+	ie.Lock()
+	defer ie.Unlock()
+	for key, logEntry := range ie.entries {
+		if !ie.waiting[key] {
+			timestamps = append(timestamps, logEntry.TsNs)
+		}
+	}
+	return
+}
+
+func (ie *inflightLogEntries) get(key []byte, tsNs int64) *filer_pb.LogEntry {
+	ie.Lock()
+	defer ie.Unlock()
+	if logEntry, found := ie.entries[string(key)]; found && logEntry.TsNs == tsNs {
+		return logEntry
+	}
+	return nil
+}
+
+func (ie *inflightLogEntries) remove(key []byte, tsNs int64) {
+	ie.Lock()
+	defer ie.Unlock()
+	if logEntry, found := ie.entries[string(key)]; found && logEntry.TsNs == tsNs {
+		delete(ie.entries, string(key))
+		delete(ie.waiting, string(key))
+	}
+}
+
+// redeliveryTimers are the timers of the negatively acked messages waiting to be redelivered,
+// stopped when the subscriber disconnects
+type redeliveryTimers struct {
+	timers  map[*time.Timer]struct{}
+	stopped bool
+	sync.Mutex
+}
+
+func newRedeliveryTimers() *redeliveryTimers {
+	return &redeliveryTimers{
+		timers: make(map[*time.Timer]struct{}),
+	}
+}
+
+// schedule runs fn after the delay, unless the timers are stopped before
+func (rt *redeliveryTimers) schedule(delay time.Duration, fn func()) bool {
+	rt.Lock()
+	defer rt.Unlock()
+	if rt.stopped {
+		return false
+	}
+	var timer *time.Timer
+	timer = time.AfterFunc(delay, func() {
+		rt.Lock()
+		if rt.stopped {
+			rt.Unlock()
+			return
+		}
+		delete(rt.timers, timer)
+		rt.Unlock()
+		fn()
+	})
+	rt.timers[timer] = struct{}{}
+	return true
+}
+
+func (rt *redeliveryTimers) stop() {
+	rt.Lock()
+	defer rt.Unlock()
+	rt.stopped = true
+	for timer := range rt.timers {
+		timer.Stop()
+	}
+	rt.timers = make(map[*time.Timer]struct{})
+}
+
+// deadLetter is a message failed too many times, moved to the dead-letter topic in the background
+type deadLetter struct {
+	logEntry *filer_pb.LogEntry
+	failures int32
+	failure  string
+}
+
+// redeliveryDelay doubles the delay after each failed delivery
+func redeliveryDelay(redeliveryDelayMs int32, deliveries int32) time.Duration {
+	delay := defaultRedeliveryDelay
+	if redeliveryDelayMs > 0 {
+		delay = time.Duration(redeliveryDelayMs) * time.Millisecond
+	}
+	for i := int32(1); i < deliveries && delay < maxRedeliveryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRedeliveryDelay {
+		delay = maxRedeliveryDelay
+	}
+	return delay
+}
+
+// publishDeadLetter routes the message failed too many times to the dead-letter topic of the consumer group,
+// with the original key, and the original value and the failure in a mq_pb.DeadLetterMessage.
+// Without a dead-letter topic, the message is dropped.
+func (b *MessageQueueBroker) publishDeadLetter(initMessage *mq_pb.SubscribeMessageRequest_InitMessage, logEntry *filer_pb.LogEntry, deliveries int32, failure string) error {
+	if initMessage.DeadLetterTopic == nil || initMessage.DeadLetterTopic.Name == "" {
+		glog.Warningf("topic %v consumer group %s drops message %d after %d deliveries: %s",
+			initMessage.Topic, initMessage.ConsumerGroup, logEntry.TsNs, deliveries, failure)
+		return nil
+	}
+
+	value, err := proto.Marshal(&mq_pb.DeadLetterMessage{
+		Topic:            initMessage.Topic,
+		Partition:        initMessage.GetPartitionOffset().GetPartition(),
+		TsNs:             logEntry.TsNs,
+		Key:              logEntry.Key,
+		Value:            logEntry.Data,
+		ConsumerGroup:    initMessage.ConsumerGroup,
+		DeliveryAttempts: deliveries,
+		Error:            failure,
+	})
+	if err != nil {
+		return err
+	}
+
+	assignment, err := b.lookupDeadLetterPartition(initMessage.DeadLetterTopic, logEntry.Key)
+	if err != nil {
+		return err
+	}
+	if err = b.publishToPartition(initMessage.DeadLetterTopic, assignment, &mq_pb.DataMessage{
+		Key:   logEntry.Key,
+		Value: value,
+		TsNs:  time.Now().UnixNano(),
+	}, fmt.Sprintf("dead-letter/%s", initMessage.ConsumerGroup)); err != nil {
+		return fmt.Errorf("publish to dead-letter topic %v: %v", topic.FromPbTopic(initMessage.DeadLetterTopic), err)
+	}
+	glog.V(0).Infof("topic %v consumer group %s moved message %d to dead-letter topic %v after %d deliveries: %s",
+		initMessage.Topic, initMessage.ConsumerGroup, logEntry.TsNs, initMessage.DeadLetterTopic, deliveries, failure)
+	return nil
+}
+
+// lookupDeadLetterPartition finds the partition of the key in the dead-letter topic, creating the topic if missing
+func (b *MessageQueueBroker) lookupDeadLetterPartition(deadLetterTopic *mq_pb.Topic, key []byte) (*mq_pb.BrokerPartitionAssignment, error) {
+	var assignments []*mq_pb.BrokerPartitionAssignment
+	lookupResp, lookupErr := b.LookupTopicBrokers(context.Background(), &mq_pb.LookupTopicBrokersRequest{
+		Topic: deadLetterTopic,
+	})
+	if lookupErr == nil && lookupResp != nil {
+		assignments = lookupResp.BrokerPartitionAssignments
+	}
+	if len(assignments) == 0 {
+		configureResp, err := b.ConfigureTopic(context.Background(), &mq_pb.ConfigureTopicRequest{
+			Topic:          deadLetterTopic,
+			PartitionCount: 1,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("configure dead-letter topic %v: %v", topic.FromPbTopic(deadLetterTopic), err)
+		}
+		assignments = configureResp.BrokerPartitionAssignments
+	}
+
+	hashKey := util.HashToInt32(key) % pub_balancer.MaxPartitionCount
+	if hashKey < 0 {
+		hashKey = -hashKey
+	}
+	for _, assignment := range assignments {
+		if assignment.Partition.RangeStart <= hashKey && hashKey < assignment.Partition.RangeStop && assignment.LeaderBroker != "" {
+			return assignment, nil
+		}
+	}
+	return nil, fmt.Errorf("no partition of dead-letter topic %v for key hash %d", topic.FromPbTopic(deadLetterTopic), hashKey)
+}
+
+// publishToPartition publishes one message to the leader of the partition, and waits for the ack
+func (b *MessageQueueBroker) publishToPartition(t *mq_pb.Topic, assignment *mq_pb.BrokerPartitionAssignment, dataMessage *mq_pb.DataMessage, publisherName string) error {
+	return b.withBrokerClient(true, pb.ServerAddress(assignment.LeaderBroker), func(client mq_pb.SeaweedMessagingClient) error {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		stream, err := client.PublishMessage(ctx)
+		if err != nil {
+			return err
+		}
+		if err = stream.Send(&mq_pb.PublishMessageRequest{
+			Message: &mq_pb.PublishMessageRequest_Init{
+				Init: &mq_pb.PublishMessageRequest_InitMessage{
+					Topic:          t,
+					Partition:      assignment.Partition,
+					AckInterval:    1,
+					FollowerBroker: assignment.FollowerBroker,
+					PublisherName:  publisherName,
+				},
+			},
+		}); err != nil {
+			return err
+		}
+		if err = stream.Send(&mq_pb.PublishMessageRequest{
+			Message: &mq_pb.PublishMessageRequest_Data{
+				Data: dataMessage,
+			},
+		}); err != nil {
+			return err
+		}
+		for {
+			resp, err := stream.Recv()
+			if err != nil {
+				return err
+			}
+			if resp.Error != "" {
+				return fmt.Errorf("%s", resp.Error)
+			}
+			if resp.AckSequence >= dataMessage.TsNs {
+				return stream.CloseSend()
+			}
+		}
+	})
+}
@@ -0,0 +1,135 @@
+package broker
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/seaweedfs/seaweedfs/weed/filer"
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/mq/topic"
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/util"
+)
+
+// deliveryFailures counts the failed deliveries of the messages of a partition to a consumer group, by message timestamp.
+// The counts are saved in the partition directory, so they outlive the subscriber connection:
+// a message crashing its subscribers is still moved to the dead-letter topic,
+// whichever broker the subscribers reconnect to.
+type deliveryFailures struct {
+	sync.Mutex
+	b            *MessageQueueBroker
+	partitionDir string
+	fileName     string
+	failures     map[int64]int32
+}
+
+const deliveryFailureSize = 12 // 8 bytes timestamp and 4 bytes count
+
+func toDeliveriesFileName(consumerGroup string) string {
+	return fmt.Sprintf("%s.deliveries", consumerGroup)
+}
+
+// loadDeliveryFailures reads the failed deliveries of the consumer group, forgetting the messages before startTsNs
+func (b *MessageQueueBroker) loadDeliveryFailures(t topic.Topic, p topic.Partition, consumerGroup string, startTsNs int64) (*deliveryFailures, error) {
+	df := &deliveryFailures{
+		b:            b,
+		partitionDir: toPartitionDir(t, p),
+		fileName:     toDeliveriesFileName(consumerGroup),
+		failures:     make(map[int64]int32),
+	}
+	err := b.WithFilerClient(false, func(client filer_pb.SeaweedFilerClient) error {
+		data, err := filer.ReadInsideFiler(client, df.partitionDir, df.fileName)
+		if err == filer_pb.ErrNotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		df.failures, err = decodeDeliveryFailures(data)
+		return err
+	})
+	if err != nil {
+		df.failures = make(map[int64]int32)
+		return df, err
+	}
+	for tsNs := range df.failures {
+		if tsNs < startTsNs {
+			delete(df.failures, tsNs)
+		}
+	}
+	return df, nil
+}
+
+// count returns the failed deliveries of the message
+func (df *deliveryFailures) count(tsNs int64) int32 {
+	df.Lock()
+	defer df.Unlock()
+	return df.failures[tsNs]
+}
+
+// fail counts one more failed delivery of each message, and returns the failed deliveries of the last one
+func (df *deliveryFailures) fail(tsNsList ...int64) (failures int32) {
+	if len(tsNsList) == 0 {
+		return 0
+	}
+	df.Lock()
+	defer df.Unlock()
+	for _, tsNs := range tsNsList {
+		df.failures[tsNs]++
+		failures = df.failures[tsNs]
+	}
+	df.save()
+	return failures
+}
+
+// clear forgets the message once it is acked, or moved to the dead-letter topic
+func (df *deliveryFailures) clear(tsNs int64) {
+	df.Lock()
+	defer df.Unlock()
+	if _, found := df.failures[tsNs]; !found {
+		return
+	}
+	delete(df.failures, tsNs)
+	df.save()
+}
+
+func (df *deliveryFailures) save() {
+	err := df.b.WithFilerClient(false, func(client filer_pb.SeaweedFilerClient) error {
+		if len(df.failures) == 0 {
+			return filer_pb.DoRemove(client, df.partitionDir, df.fileName, true, false, false, false, nil)
+		}
+		return filer.SaveInsideFiler(client, df.partitionDir, df.fileName, encodeDeliveryFailures(df.failures))
+	})
+	if err != nil {
+		// still counted while the subscriber is connected
+		glog.Warningf("save delivery failures %s/%s: %v", df.partitionDir, df.fileName, err)
+	}
+}
+
+func encodeDeliveryFailures(failures map[int64]int32) []byte {
+	var timestamps []int64
+	for tsNs := range failures {
+		timestamps = append(timestamps, tsNs)
+	}
+	sort.Slice(timestamps, func(i, j int) bool {
+		return timestamps[i] < timestamps[j]
+	})
+	data := make([]byte, len(timestamps)*deliveryFailureSize)
+	for i, tsNs := range timestamps {
+		util.Uint64toBytes(data[i*deliveryFailureSize:], uint64(tsNs))
+		util.Uint32toBytes(data[i*deliveryFailureSize+8:], uint32(failures[tsNs]))
+	}
+	return data
+}
+
+func decodeDeliveryFailures(data []byte) (map[int64]int32, error) {
+	if len(data)%deliveryFailureSize != 0 {
+		return nil, fmt.Errorf("invalid delivery failures size %d", len(data))
+	}
+	failures := make(map[int64]int32)
+	for i := 0; i < len(data); i += deliveryFailureSize {
+		failures[int64(util.BytesToUint64(data[i:i+8]))] = int32(util.BytesToUint32(data[i+8 : i+deliveryFailureSize]))
+	}
+	return failures, nil
+}
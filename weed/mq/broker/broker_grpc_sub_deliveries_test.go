@@ -0,0 +1,74 @@
+package broker
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeliveryFailuresEncoding(t *testing.T) {
+	failures := map[int64]int32{3: 1, 1: 5, 2: 2}
+	decoded, err := decodeDeliveryFailures(encodeDeliveryFailures(failures))
+	assert.Nil(t, err)
+	assert.Equal(t, failures, decoded)
+
+	decoded, err = decodeDeliveryFailures(nil)
+	assert.Nil(t, err)
+	assert.Empty(t, decoded)
+
+	_, err = decodeDeliveryFailures(make([]byte, 13))
+	assert.NotNil(t, err)
+}
+
+func TestInflightLogEntriesDelivered(t *testing.T) {
+	ie := newInflightLogEntries()
+	first := &filer_pb.LogEntry{Key: []byte("a"), TsNs: 1}
+	second := &filer_pb.LogEntry{Key: []byte("b"), TsNs: 2}
+	ie.add(first)
+	ie.add(second)
+
+	delivered := ie.delivered()
+	sort.Slice(delivered, func(i, j int) bool { return delivered[i] < delivered[j] })
+	assert.Equal(t, []int64{1, 2}, delivered)
+
+	// the message waiting to be redelivered is not being processed by the subscriber
+	ie.setWaiting(first, true)
+	assert.Equal(t, []int64{2}, ie.delivered())
+	ie.setWaiting(first, false)
+	ie.remove([]byte("b"), 2)
+	assert.Equal(t, []int64{1}, ie.delivered())
+}
+
+func TestRedeliveryTimersStop(t *testing.T) {
+	timers := newRedeliveryTimers()
+	var isConnected atomic.Bool
+	isConnected.Store(true)
+	var redelivered atomic.Int32
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			timers.schedule(0, func() {
+				if isConnected.Load() {
+					redelivered.Add(1)
+				}
+			})
+		}()
+	}
+	// disconnect while the redeliveries are firing
+	isConnected.Store(false)
+	timers.stop()
+	wg.Wait()
+
+	assert.False(t, timers.schedule(0, func() { redelivered.Add(1000) }))
+	time.Sleep(10 * time.Millisecond)
+	assert.Less(t, redelivered.Load(), int32(1000))
+	assert.Empty(t, timers.timers)
+}
@@ -81,6 +81,14 @@ func (b *MessageQueueBroker) readConsumerGroupOffset(initMessage *mq_pb.Subscrib
 		offset = int64(util.BytesToUint64(data))
 		return nil
 	})
+
+	// the pipelines committing the offsets may have stopped before copying them to the partition
+	committedOffset, found, readCommitErr := b.readCommittedOffset(t, p, initMessage.ConsumerGroup)
+	if readCommitErr != nil {
+		glog.Warningf("read consumer group %s commit of %v: %v", initMessage.ConsumerGroup, t, readCommitErr)
+	} else if found && (err != nil || committedOffset > offset) {
+		offset, err = committedOffset, nil
+	}
 	return offset, err
 }
 
@@ -100,6 +100,8 @@ func NewMessageBroker(option *MessageQueueBrokerOption, grpcDialOption grpc.Dial
 		mqBroker.KeepConnectedToBrokerBalancer(newBrokerBalancerCh)
 	}()
 
+	go mqBroker.KeepMaintainingTopics()
+
 	return mqBroker, nil
 }
 
@@ -0,0 +1,336 @@
+package broker
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress/zstd"
+	"github.com/seaweedfs/seaweedfs/weed/filer"
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/mq/schema"
+	"github.com/seaweedfs/seaweedfs/weed/mq/topic"
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/pb/schema_pb"
+	"github.com/seaweedfs/seaweedfs/weed/util"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// The sealed segments of the topics with a record type are converted to parquet files,
+// named after the segment with the ".parquet" suffix, so they stay in the order of the segments.
+// Besides the record fields, each row has the message timestamp, key and partition key hash in the system columns.
+// The segment is only deleted after every message is read back exactly from the parquet file,
+// otherwise the segment is kept as is, and marked to not be converted again.
+// The min and max timestamps are kept in both the parquet key-value metadata and the entry extended attributes,
+// so the analytics engines and the subscribers can skip the files out of the time range.
+// The record type is kept in the parquet metadata, so the files stay readable after the topic record type changes.
+const (
+	parquetSegmentSuffix = ".parquet"
+	parquetTsNsColumn    = "_ts_ns"
+	parquetKeyColumn     = "_key"
+	parquetKeyHashColumn = "_key_hash"
+	parquetMinTsNsKey    = "mq.min_ts_ns"
+	parquetMaxTsNsKey    = "mq.max_ts_ns"
+	parquetRecordTypeKey = "mq.record_type"
+	parquetSkippedKey    = "mq.parquet_skipped"
+)
+
+func isParquetSegment(entry *filer_pb.Entry) bool {
+	return strings.HasSuffix(entry.Name, parquetSegmentSuffix)
+}
+
+func (b *MessageQueueBroker) convertTopicToParquet(t topic.Topic, recordType *schema_pb.RecordType, now time.Time) error {
+	segmentRecordType, err := toParquetSegmentRecordType(recordType)
+	if err != nil {
+		return err
+	}
+	partitionDirs, err := b.listPartitionDirs(t)
+	if err != nil {
+		return err
+	}
+	for _, partitionDir := range partitionDirs {
+		segments, err := b.listSegments(partitionDir)
+		if err != nil {
+			return err
+		}
+		for i, segment := range segments {
+			if i == len(segments)-1 || segment.Attributes.GetMtime() > now.Add(-topicSegmentSealedAge).Unix() {
+				// the segment may still be appended to
+				continue
+			}
+			if isParquetSegment(segment) || len(segment.Extended[parquetSkippedKey]) > 0 {
+				continue
+			}
+			if err = b.convertSegmentToParquet(t, partitionDir, segment, segmentRecordType); err != nil {
+				// keep the segment, and go on with the other segments
+				glog.Warningf("topic %v: keep segment %s/%s, not converted to parquet: %v", t, partitionDir, segment.Name, err)
+				if err = b.markSegmentSkipped(partitionDir, segment, err); err != nil {
+					glog.Warningf("topic %v: mark segment %s/%s: %v", t, partitionDir, segment.Name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// convertSegmentToParquet writes the parquet file before deleting the segment,
+// so the messages can always be read from one of them
+func (b *MessageQueueBroker) convertSegmentToParquet(t topic.Topic, partitionDir util.FullPath, segment *filer_pb.Entry, segmentRecordType *schema_pb.RecordType) error {
+	logEntries, err := b.readSegmentLogEntries(segment)
+	if err != nil {
+		return err
+	}
+	data, minTsNs, maxTsNs, err := encodeParquetSegment(t, segmentRecordType, logEntries)
+	if err != nil {
+		return err
+	}
+	if err = verifyParquetSegment(data, logEntries); err != nil {
+		return err
+	}
+
+	if len(logEntries) > 0 {
+		targetFile := string(partitionDir.Child(segment.Name + parquetSegmentSuffix))
+		fileId, uploadResult, err := b.assignAndUpload(targetFile, data)
+		if err != nil {
+			return err
+		}
+		entry := &filer_pb.Entry{
+			Name: segment.Name + parquetSegmentSuffix,
+			Attributes: &filer_pb.FuseAttributes{
+				Crtime: segment.Attributes.GetCrtime(),
+				// keep the segment mtime for the time based retention
+				Mtime:    segment.Attributes.GetMtime(),
+				FileMode: uint32(os.FileMode(0644)),
+				Uid:      uint32(os.Getuid()),
+				Gid:      uint32(os.Getgid()),
+				FileSize: uint64(len(data)),
+			},
+			Chunks: []*filer_pb.FileChunk{uploadResult.ToPbFileChunk(fileId, 0, time.Now().UnixNano())},
+			Extended: map[string][]byte{
+				parquetMinTsNsKey: []byte(strconv.FormatInt(minTsNs, 10)),
+				parquetMaxTsNsKey: []byte(strconv.FormatInt(maxTsNs, 10)),
+			},
+		}
+		if err = b.WithFilerClient(false, func(client filer_pb.SeaweedFilerClient) error {
+			return filer_pb.CreateEntry(client, &filer_pb.CreateEntryRequest{
+				Directory: string(partitionDir),
+				Entry:     entry,
+			})
+		}); err != nil {
+			return err
+		}
+	}
+
+	glog.V(0).Infof("topic %v: converted segment %s/%s of %d messages to parquet", t, partitionDir, segment.Name, len(logEntries))
+	return b.WithFilerClient(false, func(client filer_pb.SeaweedFilerClient) error {
+		return filer_pb.DoRemove(client, string(partitionDir), segment.Name, true, false, false, false, nil)
+	})
+}
+
+// markSegmentSkipped records why the segment is not converted, so it is not read again at each pass
+func (b *MessageQueueBroker) markSegmentSkipped(partitionDir util.FullPath, segment *filer_pb.Entry, reason error) error {
+	if segment.Extended == nil {
+		segment.Extended = make(map[string][]byte)
+	}
+	segment.Extended[parquetSkippedKey] = []byte(reason.Error())
+	return b.WithFilerClient(false, func(client filer_pb.SeaweedFilerClient) error {
+		return filer_pb.UpdateEntry(client, &filer_pb.UpdateEntryRequest{
+			Directory: string(partitionDir),
+			Entry:     segment,
+		})
+	})
+}
+
+// verifyParquetSegment checks that every message is read back exactly from the parquet file,
+// which is not the case for the messages with fields out of the record type, or not being a record value at all
+func verifyParquetSegment(data []byte, logEntries []*filer_pb.LogEntry) error {
+	decoded, err := decodeParquetSegment(data)
+	if err != nil {
+		return fmt.Errorf("read back: %v", err)
+	}
+	if len(decoded) != len(logEntries) {
+		return fmt.Errorf("read back %d messages, expected %d", len(decoded), len(logEntries))
+	}
+	for i, logEntry := range logEntries {
+		if decoded[i].TsNs != logEntry.TsNs || decoded[i].PartitionKeyHash != logEntry.PartitionKeyHash || !bytes.Equal(decoded[i].Key, logEntry.Key) {
+			return fmt.Errorf("message at %d is read back as %d", logEntry.TsNs, decoded[i].TsNs)
+		}
+		recordValue, decodedValue := &schema_pb.RecordValue{}, &schema_pb.RecordValue{}
+		if err = proto.Unmarshal(logEntry.Data, recordValue); err != nil {
+			return fmt.Errorf("unmarshal record value at %d: %v", logEntry.TsNs, err)
+		}
+		if err = proto.Unmarshal(decoded[i].Data, decodedValue); err != nil {
+			return fmt.Errorf("unmarshal read back record value at %d: %v", logEntry.TsNs, err)
+		}
+		if !proto.Equal(recordValue, decodedValue) {
+			return fmt.Errorf("message at %d does not match the record type", logEntry.TsNs)
+		}
+	}
+	return nil
+}
+
+// toParquetSegmentRecordType adds the system columns to the record type,
+// with the fields sorted by name as the parquet schema orders them
+func toParquetSegmentRecordType(recordType *schema_pb.RecordType) (*schema_pb.RecordType, error) {
+	segmentRecordType := proto.Clone(recordType).(*schema_pb.RecordType)
+	for _, field := range segmentRecordType.Fields {
+		if field.Name == parquetTsNsColumn || field.Name == parquetKeyColumn || field.Name == parquetKeyHashColumn {
+			return nil, fmt.Errorf("field %s is reserved", field.Name)
+		}
+	}
+	segmentRecordType.Fields = append(segmentRecordType.Fields,
+		&schema_pb.Field{Name: parquetTsNsColumn, Type: schema.TypeInt64},
+		&schema_pb.Field{Name: parquetKeyColumn, Type: schema.TypeBytes},
+		&schema_pb.Field{Name: parquetKeyHashColumn, Type: schema.TypeInt32},
+	)
+	sortRecordTypeFields(segmentRecordType)
+	return segmentRecordType, nil
+}
+
+func sortRecordTypeFields(recordType *schema_pb.RecordType) {
+	sort.Slice(recordType.Fields, func(i, j int) bool {
+		return recordType.Fields[i].Name < recordType.Fields[j].Name
+	})
+	for _, field := range recordType.Fields {
+		fieldType := field.Type
+		for fieldType.GetListType() != nil {
+			fieldType = fieldType.GetListType().ElementType
+		}
+		if fieldType.GetRecordType() != nil {
+			sortRecordTypeFields(fieldType.GetRecordType())
+		}
+	}
+}
+
+// encodeParquetSegment writes the messages, whose values are the serialized schema_pb.RecordValue, into a parquet file
+func encodeParquetSegment(t topic.Topic, segmentRecordType *schema_pb.RecordType, logEntries []*filer_pb.LogEntry) (data []byte, minTsNs, maxTsNs int64, err error) {
+	parquetSchema, err := schema.ToParquetSchema(t.Name, segmentRecordType)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	parquetLevels, err := schema.ToParquetLevels(segmentRecordType)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	rows := make([]parquet.Row, 0, len(logEntries))
+	rowBuilder := parquet.NewRowBuilder(parquetSchema)
+	for i, logEntry := range logEntries {
+		recordValue := &schema_pb.RecordValue{}
+		if err = proto.Unmarshal(logEntry.Data, recordValue); err != nil {
+			return nil, 0, 0, fmt.Errorf("unmarshal record value at %d: %v", logEntry.TsNs, err)
+		}
+		if recordValue.Fields == nil {
+			recordValue.Fields = make(map[string]*schema_pb.Value)
+		}
+		recordValue.Fields[parquetTsNsColumn] = &schema_pb.Value{Kind: &schema_pb.Value_Int64Value{Int64Value: logEntry.TsNs}}
+		recordValue.Fields[parquetKeyColumn] = &schema_pb.Value{Kind: &schema_pb.Value_BytesValue{BytesValue: logEntry.Key}}
+		recordValue.Fields[parquetKeyHashColumn] = &schema_pb.Value{Kind: &schema_pb.Value_Int32Value{Int32Value: logEntry.PartitionKeyHash}}
+
+		rowBuilder.Reset()
+		if err = schema.AddRecordValue(rowBuilder, segmentRecordType, parquetLevels, recordValue); err != nil {
+			return nil, 0, 0, fmt.Errorf("add record value at %d: %v", logEntry.TsNs, err)
+		}
+		rows = append(rows, rowBuilder.Row())
+
+		if i == 0 || logEntry.TsNs < minTsNs {
+			minTsNs = logEntry.TsNs
+		}
+		if logEntry.TsNs > maxTsNs {
+			maxTsNs = logEntry.TsNs
+		}
+	}
+
+	recordTypeJson, err := protojson.Marshal(segmentRecordType)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	var buf bytes.Buffer
+	writer := parquet.NewWriter(&buf, parquetSchema,
+		parquet.Compression(&zstd.Codec{Level: zstd.DefaultLevel}),
+		parquet.KeyValueMetadata(parquetMinTsNsKey, strconv.FormatInt(minTsNs, 10)),
+		parquet.KeyValueMetadata(parquetMaxTsNsKey, strconv.FormatInt(maxTsNs, 10)),
+		parquet.KeyValueMetadata(parquetRecordTypeKey, string(recordTypeJson)),
+	)
+	if _, err = writer.WriteRows(rows); err != nil {
+		return nil, 0, 0, fmt.Errorf("write rows: %v", err)
+	}
+	if err = writer.Close(); err != nil {
+		return nil, 0, 0, fmt.Errorf("close parquet writer: %v", err)
+	}
+	return buf.Bytes(), minTsNs, maxTsNs, nil
+}
+
+// decodeParquetSegment reads the messages back from the parquet file, in the order of the timestamps
+func decodeParquetSegment(data []byte) (logEntries []*filer_pb.LogEntry, err error) {
+	file, err := parquet.OpenFile(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	recordTypeJson, found := file.Lookup(parquetRecordTypeKey)
+	if !found {
+		return nil, fmt.Errorf("missing %s metadata", parquetRecordTypeKey)
+	}
+	segmentRecordType := &schema_pb.RecordType{}
+	if err = protojson.Unmarshal([]byte(recordTypeJson), segmentRecordType); err != nil {
+		return nil, fmt.Errorf("unmarshal record type: %v", err)
+	}
+	parquetLevels, err := schema.ToParquetLevels(segmentRecordType)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := parquet.NewReader(bytes.NewReader(data), file.Schema())
+	defer reader.Close()
+	rows := make([]parquet.Row, 128)
+	for {
+		rowCount, readErr := reader.ReadRows(rows)
+		for i := 0; i < rowCount; i++ {
+			recordValue, err := schema.ToRecordValue(segmentRecordType, parquetLevels, rows[i])
+			if err != nil {
+				return nil, fmt.Errorf("convert row: %v", err)
+			}
+			logEntry := &filer_pb.LogEntry{
+				TsNs:             recordValue.Fields[parquetTsNsColumn].GetInt64Value(),
+				Key:              recordValue.Fields[parquetKeyColumn].GetBytesValue(),
+				PartitionKeyHash: recordValue.Fields[parquetKeyHashColumn].GetInt32Value(),
+			}
+			delete(recordValue.Fields, parquetTsNsColumn)
+			delete(recordValue.Fields, parquetKeyColumn)
+			delete(recordValue.Fields, parquetKeyHashColumn)
+			if logEntry.Data, err = proto.Marshal(recordValue); err != nil {
+				return nil, fmt.Errorf("marshal record value at %d: %v", logEntry.TsNs, err)
+			}
+			logEntries = append(logEntries, logEntry)
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return nil, readErr
+		}
+	}
+	return logEntries, nil
+}
+
+// parquetSegmentMaxTsNs reads the max timestamp from the entry, false if unknown
+func parquetSegmentMaxTsNs(entry *filer_pb.Entry) (int64, bool) {
+	maxTsNs, err := strconv.ParseInt(string(entry.Extended[parquetMaxTsNsKey]), 10, 64)
+	return maxTsNs, err == nil
+}
+
+// readParquetSegmentLogEntries reads the messages of a parquet segment for the subscribers
+func (b *MessageQueueBroker) readParquetSegmentLogEntries(entry *filer_pb.Entry) ([]*filer_pb.LogEntry, error) {
+	data := make([]byte, filer.FileSize(entry))
+	if err := filer.ReadAll(data, b.MasterClient, entry.GetChunks()); err != nil {
+		return nil, err
+	}
+	return decodeParquetSegment(data)
+}
@@ -0,0 +1,115 @@
+package broker
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/seaweedfs/seaweedfs/weed/mq/schema"
+	"github.com/seaweedfs/seaweedfs/weed/mq/topic"
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/pb/schema_pb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestParquetSegmentRoundTrip(t *testing.T) {
+	recordType := schema.RecordTypeBegin().
+		WithField("name", schema.TypeString).
+		WithField("age", schema.TypeInt32).
+		WithField("tags", schema.ListOf(schema.TypeString)).
+		WithRecordField("Address",
+			schema.RecordTypeBegin().
+				WithField("City", schema.TypeString).
+				RecordTypeEnd()).
+		RecordTypeEnd()
+
+	var logEntries []*filer_pb.LogEntry
+	var recordValues []*schema_pb.RecordValue
+	for i := 0; i < 5; i++ {
+		builder := schema.RecordBegin().
+			SetString("name", fmt.Sprintf("user_%d", i)).
+			SetStringList("tags", "a", "b").
+			SetRecord("Address", schema.RecordBegin().SetString("City", "Rome").RecordEnd())
+		if i%2 == 0 {
+			// optional field not set on the odd records
+			builder.SetInt32("age", int32(20+i))
+		}
+		recordValue := builder.RecordEnd()
+		data, err := proto.Marshal(recordValue)
+		assert.Nil(t, err)
+		logEntries = append(logEntries, &filer_pb.LogEntry{
+			TsNs:             int64(1000 + i),
+			Key:              []byte(fmt.Sprintf("key_%d", i)),
+			PartitionKeyHash: int32(-i),
+			Data:             data,
+		})
+		recordValues = append(recordValues, recordValue)
+	}
+
+	segmentRecordType, err := toParquetSegmentRecordType(recordType)
+	assert.Nil(t, err)
+	data, minTsNs, maxTsNs, err := encodeParquetSegment(topic.NewTopic("test", "users"), segmentRecordType, logEntries)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1000), minTsNs)
+	assert.Equal(t, int64(1004), maxTsNs)
+
+	decoded, err := decodeParquetSegment(data)
+	assert.Nil(t, err)
+	assert.Equal(t, len(logEntries), len(decoded))
+	for i, logEntry := range decoded {
+		assert.Equal(t, logEntries[i].TsNs, logEntry.TsNs)
+		assert.Equal(t, logEntries[i].Key, logEntry.Key)
+		assert.Equal(t, logEntries[i].PartitionKeyHash, logEntry.PartitionKeyHash)
+		recordValue := &schema_pb.RecordValue{}
+		assert.Nil(t, proto.Unmarshal(logEntry.Data, recordValue))
+		assert.True(t, proto.Equal(recordValues[i], recordValue), "record %d: %v, want %v", i, recordValue, recordValues[i])
+	}
+	assert.Nil(t, verifyParquetSegment(data, logEntries))
+}
+
+func TestParquetSegmentLossy(t *testing.T) {
+	recordType := schema.RecordTypeBegin().
+		WithField("name", schema.TypeString).
+		RecordTypeEnd()
+	segmentRecordType, err := toParquetSegmentRecordType(recordType)
+	assert.Nil(t, err)
+	encode := func(recordValue *schema_pb.RecordValue) []byte {
+		data, err := proto.Marshal(recordValue)
+		assert.Nil(t, err)
+		return data
+	}
+
+	// a field of another record type is dropped by the parquet file
+	logEntries := []*filer_pb.LogEntry{
+		{TsNs: 1, Data: encode(schema.RecordBegin().SetString("name", "a").RecordEnd())},
+		{TsNs: 2, Data: encode(schema.RecordBegin().SetString("name", "b").SetInt32("age", 3).RecordEnd())},
+	}
+	data, _, _, err := encodeParquetSegment(topic.NewTopic("test", "users"), segmentRecordType, logEntries)
+	assert.Nil(t, err)
+	assert.NotNil(t, verifyParquetSegment(data, logEntries))
+
+	// a message not being a record value
+	logEntries = []*filer_pb.LogEntry{{TsNs: 1, Data: []byte("not a record value")}}
+	if data, _, _, err = encodeParquetSegment(topic.NewTopic("test", "users"), segmentRecordType, logEntries); err == nil {
+		assert.NotNil(t, verifyParquetSegment(data, logEntries))
+	}
+}
+
+func TestParquetSegmentRecordType(t *testing.T) {
+	recordType := schema.RecordTypeBegin().
+		WithField("b", schema.TypeString).
+		WithField("a", schema.TypeInt64).
+		RecordTypeEnd()
+
+	segmentRecordType, err := toParquetSegmentRecordType(recordType)
+	assert.Nil(t, err)
+	var names []string
+	for _, field := range segmentRecordType.Fields {
+		names = append(names, field.Name)
+	}
+	assert.Equal(t, []string{"_key", "_key_hash", "_ts_ns", "a", "b"}, names)
+	assert.Equal(t, 2, len(recordType.Fields), "the topic record type is not changed")
+
+	_, err = toParquetSegmentRecordType(schema.RecordTypeBegin().WithField("_key", schema.TypeString).RecordTypeEnd())
+	assert.NotNil(t, err)
+}
@@ -44,11 +44,21 @@ func (b *MessageQueueBroker) genLogFlushFunc(t topic.Topic, partition *mq_pb.Par
 		atomic.StoreInt64(&logBuffer.LastFlushTsNs, stopTime.UnixNano())
 
 		b.accessLock.Lock()
-		defer b.accessLock.Unlock()
 		p := topic.FromPbPartition(partition)
-		if localPartition := b.localTopicManager.GetLocalPartition(t, p); localPartition != nil {
+		localPartition := b.localTopicManager.GetLocalPartition(t, p)
+		if localPartition != nil {
 			localPartition.NotifyLogFlushed(logBuffer.LastFlushTsNs)
 		}
+		b.accessLock.Unlock()
+
+		// the producer sequences are persisted only after their messages
+		if localPartition != nil {
+			if producerSequences, hasChanges := localPartition.Producers.NotifyFlushed(stopTime.UnixNano()); hasChanges {
+				if err := b.saveProducerSequences(partitionDir, producerSequences); err != nil {
+					glog.Warningf("save producer sequences to %s: %v", partitionDir, err)
+				}
+			}
+		}
 
 		glog.V(0).Infof("flushing at %d to %s size %d", logBuffer.LastFlushTsNs, targetFile, len(buf))
 	}
@@ -102,11 +112,39 @@ func (b *MessageQueueBroker) genLogOnDiskReadFunc(t topic.Topic, partition *mq_p
 		return
 	}
 
+	eachParquetFileFn := func(entry *filer_pb.Entry, eachLogEntryFn log_buffer.EachLogEntryFuncType, starTsNs, stopTsNs int64) (processedTsNs int64, err error) {
+		if maxTsNs, found := parquetSegmentMaxTsNs(entry); found && maxTsNs < starTsNs {
+			return
+		}
+		logEntries, err := b.readParquetSegmentLogEntries(entry)
+		if err != nil {
+			err = fmt.Errorf("read %s/%s: %v", partitionDir, entry.Name, err)
+			return
+		}
+		for _, logEntry := range logEntries {
+			if logEntry.TsNs < starTsNs {
+				continue
+			}
+			if stopTsNs != 0 && logEntry.TsNs > stopTsNs {
+				return
+			}
+			if _, err = eachLogEntryFn(logEntry); err != nil {
+				err = fmt.Errorf("process log entry %v: %v", logEntry, err)
+				return
+			}
+			processedTsNs = logEntry.TsNs
+		}
+		return
+	}
+
 	eachFileFn := func(entry *filer_pb.Entry, eachLogEntryFn log_buffer.EachLogEntryFuncType, starTsNs, stopTsNs int64) (processedTsNs int64, err error) {
 		if len(entry.Content) > 0 {
 			// skip .offset files
 			return
 		}
+		if isParquetSegment(entry) {
+			return eachParquetFileFn(entry, eachLogEntryFn, starTsNs, stopTsNs)
+		}
 		var urlStrings []string
 		for _, chunk := range entry.Chunks {
 			if chunk.Size == 0 {
@@ -0,0 +1,68 @@
+package broker
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/seaweedfs/seaweedfs/weed/filer"
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/mq/topic"
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/pb/mq_pb"
+	"google.golang.org/protobuf/proto"
+)
+
+// The last sequences of the idempotent producers are saved in the partition directory after their messages are flushed,
+// by the leader or by the follower taking over the unflushed messages,
+// so the next leader of the partition keeps dropping the messages resent by the producers.
+const producerSequencesFileName = ".producers"
+
+// newLocalPartition creates the local partition, with the producer sequences saved by the previous leader
+func (b *MessageQueueBroker) newLocalPartition(t topic.Topic, partition *mq_pb.Partition) *topic.LocalPartition {
+	localPartition := topic.NewLocalPartition(topic.FromPbPartition(partition), b.genLogFlushFunc(t, partition), b.genLogOnDiskReadFunc(t, partition))
+	partitionDir := toPartitionDir(t, topic.FromPbPartition(partition))
+	producerSequences, err := b.readProducerSequences(partitionDir)
+	if err != nil {
+		glog.Warningf("read producer sequences from %s: %v", partitionDir, err)
+	} else if producerSequences != nil {
+		localPartition.Producers.Load(producerSequences)
+	}
+	return localPartition
+}
+
+func toPartitionDir(t topic.Topic, p topic.Partition) string {
+	topicDir := fmt.Sprintf("%s/%s/%s", filer.TopicsDir, t.Namespace, t.Name)
+	partitionGeneration := time.Unix(0, p.UnixTimeNs).UTC().Format(topic.TIME_FORMAT)
+	return fmt.Sprintf("%s/%s/%04d-%04d", topicDir, partitionGeneration, p.RangeStart, p.RangeStop)
+}
+
+func (b *MessageQueueBroker) readProducerSequences(partitionDir string) (producerSequences *mq_pb.ProducerSequences, err error) {
+	err = b.WithFilerClient(false, func(client filer_pb.SeaweedFilerClient) error {
+		data, err := filer.ReadInsideFiler(client, partitionDir, producerSequencesFileName)
+		if err == filer_pb.ErrNotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		producerSequences = &mq_pb.ProducerSequences{}
+		return proto.Unmarshal(data, producerSequences)
+	})
+	return
+}
+
+func (b *MessageQueueBroker) saveProducerSequences(partitionDir string, producerSequences *mq_pb.ProducerSequences) error {
+	if len(producerSequences.Producers) == 0 {
+		// an entry without content would be read as a segment
+		return b.WithFilerClient(false, func(client filer_pb.SeaweedFilerClient) error {
+			return filer_pb.DoRemove(client, partitionDir, producerSequencesFileName, true, false, false, false, nil)
+		})
+	}
+	data, err := proto.Marshal(producerSequences)
+	if err != nil {
+		return err
+	}
+	return b.WithFilerClient(false, func(client filer_pb.SeaweedFilerClient) error {
+		return filer.SaveInsideFiler(client, partitionDir, producerSequencesFileName, data)
+	})
+}
@@ -0,0 +1,274 @@
+package broker
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/seaweedfs/seaweedfs/weed/filer"
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/mq/topic"
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/pb/mq_pb"
+	"github.com/seaweedfs/seaweedfs/weed/util"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	topicRetentionCheckInterval = 5 * time.Minute
+	// a segment not appended to for this long is sealed, and can be compacted or converted to parquet
+	topicSegmentSealedAge = 10 * time.Minute
+)
+
+// KeepMaintainingTopics runs on every broker, but only the balancer deletes the expired segments,
+// compacts the topics, and converts the sealed segments of the topics with a record type to parquet.
+func (b *MessageQueueBroker) KeepMaintainingTopics() {
+	for {
+		time.Sleep(topicRetentionCheckInterval)
+		if b.lockAsBalancer == nil || !b.isLockOwner() {
+			continue
+		}
+		if err := b.maintainTopics(time.Now()); err != nil {
+			glog.Warningf("maintain topics: %v", err)
+		}
+	}
+}
+
+func (b *MessageQueueBroker) maintainTopics(now time.Time) error {
+	var topics []topic.Topic
+	err := filer_pb.ReadDirAllEntries(b, util.FullPath(filer.TopicsDir), "", func(namespaceEntry *filer_pb.Entry, isLast bool) error {
+		if !namespaceEntry.IsDirectory {
+			return nil
+		}
+		return filer_pb.ReadDirAllEntries(b, util.FullPath(filer.TopicsDir).Child(namespaceEntry.Name), "", func(topicEntry *filer_pb.Entry, isLast bool) error {
+			if topicEntry.IsDirectory {
+				topics = append(topics, topic.NewTopic(namespaceEntry.Name, topicEntry.Name))
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("list topics: %v", err)
+	}
+
+	for _, t := range topics {
+		conf, readErr := b.fca.ReadTopicConfFromFiler(t)
+		if readErr != nil {
+			continue
+		}
+		// the segments are compacted before converted to parquet
+		if conf.Retention != nil {
+			if err := b.enforceRetentionOfTopic(t, conf.Retention, now); err != nil {
+				glog.Warningf("enforce retention of topic %v: %v", t, err)
+			}
+		}
+		if conf.RecordType != nil {
+			if err := b.convertTopicToParquet(t, conf.RecordType, now); err != nil {
+				glog.Warningf("convert topic %v to parquet: %v", t, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (b *MessageQueueBroker) listPartitionDirs(t topic.Topic) (partitionDirs []util.FullPath, err error) {
+	topicDir := util.FullPath(fmt.Sprintf("%s/%s/%s", filer.TopicsDir, t.Namespace, t.Name))
+	err = filer_pb.ReadDirAllEntries(b, topicDir, "", func(generationEntry *filer_pb.Entry, isLast bool) error {
+		if !generationEntry.IsDirectory {
+			return nil
+		}
+		return filer_pb.ReadDirAllEntries(b, topicDir.Child(generationEntry.Name), "", func(partitionEntry *filer_pb.Entry, isLast bool) error {
+			if partitionEntry.IsDirectory {
+				partitionDirs = append(partitionDirs, topicDir.Child(generationEntry.Name).Child(partitionEntry.Name))
+			}
+			return nil
+		})
+	})
+	return
+}
+
+// listSegments lists the segments of the partition, sorted by name from the oldest
+func (b *MessageQueueBroker) listSegments(partitionDir util.FullPath) (segments []*filer_pb.Entry, err error) {
+	err = filer_pb.ReadDirAllEntries(b, partitionDir, "", func(entry *filer_pb.Entry, isLast bool) error {
+		// skip .offset files
+		if !entry.IsDirectory && len(entry.Content) == 0 {
+			segments = append(segments, entry)
+		}
+		return nil
+	})
+	return
+}
+
+func (b *MessageQueueBroker) enforceRetentionOfTopic(t topic.Topic, retention *mq_pb.TopicRetention, now time.Time) error {
+	partitionDirs, err := b.listPartitionDirs(t)
+	if err != nil {
+		return err
+	}
+
+	for _, partitionDir := range partitionDirs {
+		segments, err := b.listSegments(partitionDir)
+		if err != nil {
+			return err
+		}
+
+		expired, kept := selectExpiredSegments(segments, retention, now)
+		for _, segment := range expired {
+			glog.V(0).Infof("topic %v retention: delete segment %s/%s", t, partitionDir, segment.Name)
+			if err = b.WithFilerClient(false, func(client filer_pb.SeaweedFilerClient) error {
+				return filer_pb.DoRemove(client, string(partitionDir), segment.Name, true, false, false, false, nil)
+			}); err != nil {
+				return fmt.Errorf("delete segment %s/%s: %v", partitionDir, segment.Name, err)
+			}
+		}
+
+		if retention.Compacted {
+			if err = b.compactPartition(partitionDir, kept, now); err != nil {
+				return fmt.Errorf("compact %s: %v", partitionDir, err)
+			}
+		}
+	}
+	return nil
+}
+
+// selectExpiredSegments splits the segments, sorted by name from the oldest, into the ones to delete
+// because of the time or size retention, and the ones to keep.
+// The newest segment is always kept, since it may still be appended to.
+func selectExpiredSegments(segments []*filer_pb.Entry, retention *mq_pb.TopicRetention, now time.Time) (expired, kept []*filer_pb.Entry) {
+	if len(segments) == 0 {
+		return nil, nil
+	}
+	expiredCount := 0
+	if retention.RetentionSeconds > 0 {
+		cutoff := now.Unix() - retention.RetentionSeconds
+		for expiredCount < len(segments)-1 && segments[expiredCount].Attributes.GetMtime() < cutoff {
+			expiredCount++
+		}
+	}
+	if retention.RetentionBytes > 0 {
+		var totalSize int64
+		for i := len(segments) - 1; i >= expiredCount; i-- {
+			totalSize += int64(filer.FileSize(segments[i]))
+			if totalSize > retention.RetentionBytes && i < len(segments)-1 {
+				expiredCount = i + 1
+				break
+			}
+		}
+	}
+	return segments[:expiredCount], segments[expiredCount:]
+}
+
+// compactPartition keeps only the latest message of each key in the sealed segments of the partition.
+// The segments are read one at a time, first to find the latest message of each key, then to rewrite them,
+// so only one segment and the latest timestamps of the keys are in memory.
+// The parquet segments are not compacted again.
+func (b *MessageQueueBroker) compactPartition(partitionDir util.FullPath, segments []*filer_pb.Entry, now time.Time) error {
+	segments = slices.DeleteFunc(slices.Clone(segments), isParquetSegment)
+	latestTsNs := make(map[string]int64)
+	for _, segment := range segments {
+		logEntries, err := b.readSegmentLogEntries(segment)
+		if err != nil {
+			return fmt.Errorf("read %s/%s: %v", partitionDir, segment.Name, err)
+		}
+		for _, logEntry := range logEntries {
+			if len(logEntry.Key) > 0 && logEntry.TsNs > latestTsNs[string(logEntry.Key)] {
+				latestTsNs[string(logEntry.Key)] = logEntry.TsNs
+			}
+		}
+	}
+
+	for i, segment := range segments {
+		if i == len(segments)-1 || segment.Attributes.GetMtime() > now.Add(-topicSegmentSealedAge).Unix() {
+			// the segment may still be appended to
+			continue
+		}
+		logEntries, err := b.readSegmentLogEntries(segment)
+		if err != nil {
+			return fmt.Errorf("read %s/%s: %v", partitionDir, segment.Name, err)
+		}
+		compacted := compactLogEntries(logEntries, latestTsNs)
+		if len(compacted) == len(logEntries) {
+			continue
+		}
+		if err := b.rewriteSegment(partitionDir, segment, compacted); err != nil {
+			return fmt.Errorf("rewrite %s/%s: %v", partitionDir, segment.Name, err)
+		}
+	}
+	return nil
+}
+
+// compactLogEntries drops the messages overwritten by a later message of the same key.
+// Messages without a key are kept.
+func compactLogEntries(logEntries []*filer_pb.LogEntry, latestTsNs map[string]int64) (compacted []*filer_pb.LogEntry) {
+	for _, logEntry := range logEntries {
+		if len(logEntry.Key) == 0 || logEntry.TsNs >= latestTsNs[string(logEntry.Key)] {
+			compacted = append(compacted, logEntry)
+		}
+	}
+	return
+}
+
+func (b *MessageQueueBroker) readSegmentLogEntries(segment *filer_pb.Entry) ([]*filer_pb.LogEntry, error) {
+	data := make([]byte, filer.FileSize(segment))
+	if err := filer.ReadAll(data, b.MasterClient, segment.GetChunks()); err != nil {
+		return nil, err
+	}
+	return parseLogEntries(data)
+}
+
+// parseLogEntries parses the log entries in the flushed segment, each prefixed by its 4-byte size
+func parseLogEntries(buf []byte) (logEntries []*filer_pb.LogEntry, err error) {
+	for pos := 0; pos+4 < len(buf); {
+		size := int(util.BytesToUint32(buf[pos : pos+4]))
+		if pos+4+size > len(buf) {
+			return nil, fmt.Errorf("read [%d,%d) from [0,%d)", pos, pos+size+4, len(buf))
+		}
+		logEntry := &filer_pb.LogEntry{}
+		if err = proto.Unmarshal(buf[pos+4:pos+4+size], logEntry); err != nil {
+			return nil, fmt.Errorf("unexpected unmarshal filer_pb.LogEntry: %v", err)
+		}
+		logEntries = append(logEntries, logEntry)
+		pos += 4 + size
+	}
+	return
+}
+
+func encodeLogEntries(logEntries []*filer_pb.LogEntry) ([]byte, error) {
+	var buf []byte
+	sizeBuf := make([]byte, 4)
+	for _, logEntry := range logEntries {
+		data, err := proto.Marshal(logEntry)
+		if err != nil {
+			return nil, err
+		}
+		util.Uint32toBytes(sizeBuf, uint32(len(data)))
+		buf = append(buf, sizeBuf...)
+		buf = append(buf, data...)
+	}
+	return buf, nil
+}
+
+func (b *MessageQueueBroker) rewriteSegment(partitionDir util.FullPath, segment *filer_pb.Entry, logEntries []*filer_pb.LogEntry) error {
+	if len(logEntries) == 0 {
+		return b.WithFilerClient(false, func(client filer_pb.SeaweedFilerClient) error {
+			return filer_pb.DoRemove(client, string(partitionDir), segment.Name, true, false, false, false, nil)
+		})
+	}
+
+	data, err := encodeLogEntries(logEntries)
+	if err != nil {
+		return err
+	}
+	fileId, uploadResult, err := b.assignAndUpload(string(partitionDir.Child(segment.Name)), data)
+	if err != nil {
+		return err
+	}
+
+	// the replaced chunks are deleted by the filer
+	segment.Chunks = []*filer_pb.FileChunk{uploadResult.ToPbFileChunk(fileId, 0, time.Now().UnixNano())}
+	return b.WithFilerClient(false, func(client filer_pb.SeaweedFilerClient) error {
+		return filer_pb.UpdateEntry(client, &filer_pb.UpdateEntryRequest{
+			Directory: string(partitionDir),
+			Entry:     segment,
+		})
+	})
+}
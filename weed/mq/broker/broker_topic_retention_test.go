@@ -0,0 +1,98 @@
+package broker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/pb/mq_pb"
+	"github.com/stretchr/testify/assert"
+)
+
+func segmentNames(segments []*filer_pb.Entry) (names []string) {
+	for _, segment := range segments {
+		names = append(names, segment.Name)
+	}
+	return
+}
+
+func TestSelectExpiredSegments(t *testing.T) {
+	now := time.Unix(10000, 0)
+	newSegment := func(name string, mtime int64, size uint64) *filer_pb.Entry {
+		return &filer_pb.Entry{
+			Name:       name,
+			Attributes: &filer_pb.FuseAttributes{Mtime: mtime, FileSize: size},
+		}
+	}
+	segments := []*filer_pb.Entry{
+		newSegment("a", 1000, 100),
+		newSegment("b", 2000, 100),
+		newSegment("c", 9000, 100),
+		newSegment("d", 9900, 100),
+	}
+
+	tests := []struct {
+		name        string
+		retention   *mq_pb.TopicRetention
+		wantExpired []string
+		wantKept    []string
+	}{
+		{"no retention", &mq_pb.TopicRetention{}, nil, []string{"a", "b", "c", "d"}},
+		{"by time", &mq_pb.TopicRetention{RetentionSeconds: 5000}, []string{"a", "b"}, []string{"c", "d"}},
+		{"by size", &mq_pb.TopicRetention{RetentionBytes: 250}, []string{"a", "b"}, []string{"c", "d"}},
+		{"by time and size", &mq_pb.TopicRetention{RetentionSeconds: 8500, RetentionBytes: 250}, []string{"a", "b"}, []string{"c", "d"}},
+		{"keep the newest segment by time", &mq_pb.TopicRetention{RetentionSeconds: 1}, []string{"a", "b", "c"}, []string{"d"}},
+		{"keep the newest segment by size", &mq_pb.TopicRetention{RetentionBytes: 50}, []string{"a", "b", "c"}, []string{"d"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expired, kept := selectExpiredSegments(segments, tt.retention, now)
+			assert.Equal(t, tt.wantExpired, segmentNames(expired))
+			assert.Equal(t, tt.wantKept, segmentNames(kept))
+		})
+	}
+}
+
+func TestCompactLogEntries(t *testing.T) {
+	logEntries := []*filer_pb.LogEntry{
+		{TsNs: 1, Key: []byte("a"), Data: []byte("a1")},
+		{TsNs: 2, Key: []byte("b"), Data: []byte("b1")},
+		{TsNs: 3, Data: []byte("no key")},
+		{TsNs: 4, Key: []byte("a"), Data: []byte("a2")},
+		{TsNs: 5, Key: []byte("c"), Data: []byte("c1")},
+	}
+	latestTsNs := map[string]int64{"a": 4, "b": 2, "c": 6}
+
+	var data []string
+	for _, logEntry := range compactLogEntries(logEntries, latestTsNs) {
+		data = append(data, string(logEntry.Data))
+	}
+	assert.Equal(t, []string{"b1", "no key", "a2"}, data)
+}
+
+func TestEncodeLogEntries(t *testing.T) {
+	logEntries := []*filer_pb.LogEntry{
+		{TsNs: 1, Key: []byte("a"), Data: []byte("a1")},
+		{TsNs: 2, Key: []byte("b"), Data: []byte("b1")},
+	}
+	buf, err := encodeLogEntries(logEntries)
+	assert.Nil(t, err)
+	parsed, err := parseLogEntries(buf)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(parsed))
+	assert.Equal(t, int64(2), parsed[1].TsNs)
+	assert.Equal(t, "b1", string(parsed[1].Data))
+
+	_, err = parseLogEntries(buf[:len(buf)-1])
+	assert.NotNil(t, err)
+}
+
+func TestResolveTopicRetention(t *testing.T) {
+	current := &mq_pb.TopicRetention{RetentionSeconds: 3600}
+	requested := &mq_pb.TopicRetention{Compacted: true}
+
+	assert.Equal(t, current, resolveTopicRetention(nil, current), "kept if not specified")
+	assert.Equal(t, requested, resolveTopicRetention(requested, current))
+	assert.Nil(t, resolveTopicRetention(&mq_pb.TopicRetention{}, current), "removed by an empty retention")
+	assert.Nil(t, resolveTopicRetention(nil, nil))
+}
@@ -22,6 +22,7 @@ var (
 	seedBrokers             = flag.String("brokers", "localhost:17777", "seed brokers")
 	maxPartitionCount       = flag.Int("maxPartitionCount", 3, "max partition count")
 	perPartitionConcurrency = flag.Int("perPartitionConcurrency", 1, "per partition concurrency")
+	filter                  = flag.String("filter", "", "filter on the record fields, e.g. \"field3 >= 10 AND field2 IN ('a', 'b')\"")
 
 	clientId = flag.Uint("client_id", uint(util.RandomInt32()), "client id")
 )
@@ -64,7 +65,7 @@ func main() {
 
 	contentConfig := &sub_client.ContentConfiguration{
 		Topic:     topic.NewTopic(*namespace, *t),
-		Filter:    "",
+		Filter:    *filter,
 		StartTime: time.Unix(1, 1),
 	}
 
@@ -1,12 +1,16 @@
 package pub_client
 
 import (
+	"context"
 	"fmt"
 	"github.com/golang/protobuf/proto"
 	"github.com/seaweedfs/seaweedfs/weed/mq/pub_balancer"
+	"github.com/seaweedfs/seaweedfs/weed/mq/topic"
+	"github.com/seaweedfs/seaweedfs/weed/pb"
 	"github.com/seaweedfs/seaweedfs/weed/pb/mq_pb"
 	"github.com/seaweedfs/seaweedfs/weed/pb/schema_pb"
 	"github.com/seaweedfs/seaweedfs/weed/util"
+	"sync/atomic"
 	"time"
 )
 
@@ -27,6 +31,7 @@ func (p *TopicPublisher) doPublish(key, value []byte) error {
 		return fmt.Errorf("no input buffer found for key %d", hashKey)
 	}
 
+	atomic.AddInt64(&p.enqueuedCount, 1)
 	return inputBuffer.Enqueue(&mq_pb.DataMessage{
 		Key:   key,
 		Value: value,
@@ -47,6 +52,7 @@ func (p *TopicPublisher) PublishRecord(key []byte, recordValue *schema_pb.Record
 func (p *TopicPublisher) FinishPublish() error {
 	if inputBuffers, found := p.partition2Buffer.AllIntersections(0, pub_balancer.MaxPartitionCount); found {
 		for _, inputBuffer := range inputBuffers {
+			atomic.AddInt64(&p.enqueuedCount, 1)
 			inputBuffer.Enqueue(&mq_pb.DataMessage{
 				TsNs: time.Now().UnixNano(),
 				Ctrl: &mq_pb.ControlMessage{
@@ -59,3 +65,43 @@ func (p *TopicPublisher) FinishPublish() error {
 
 	return nil
 }
+
+// CommitOffsets commits the offsets consumed by a read-process-write pipeline,
+// after the messages published so far are acked, so the consumer group resumes
+// from the offsets without losing the produced messages.
+// This is at-least-once, not exactly-once: the commit is not fenced against another producer
+// with the same id, and the messages consumed since the last commit are processed again after a crash,
+// publishing their results again. Only the resends of the same messages within a producer session are deduplicated.
+// The offset of each partition is the timestamp of the next message to consume.
+func (p *TopicPublisher) CommitOffsets(consumedTopic topic.Topic, consumerGroup string, offsets []*mq_pb.ConsumerGroupOffset) error {
+	if p.config.ProducerId == "" {
+		return fmt.Errorf("committing offsets requires a producer id")
+	}
+
+	// wait until the published messages are acked
+	enqueuedCount := atomic.LoadInt64(&p.enqueuedCount)
+	for atomic.LoadInt64(&p.ackedCount) < enqueuedCount {
+		time.Sleep(113 * time.Millisecond)
+	}
+
+	var lastErr error
+	for _, brokerAddress := range p.config.Brokers {
+		err := pb.WithBrokerGrpcClient(false,
+			brokerAddress,
+			p.grpcDialOption,
+			func(client mq_pb.SeaweedMessagingClient) error {
+				_, err := client.CommitOffsets(context.Background(), &mq_pb.CommitOffsetsRequest{
+					Topic:         consumedTopic.ToPbTopic(),
+					ConsumerGroup: consumerGroup,
+					Offsets:       offsets,
+					ProducerId:    p.config.ProducerId,
+				})
+				return err
+			})
+		if err == nil {
+			return nil
+		}
+		lastErr = fmt.Errorf("%s: %v", brokerAddress, err)
+	}
+	return fmt.Errorf("commit offsets of %s consumer group %s: %v", consumedTopic, consumerGroup, lastErr)
+}
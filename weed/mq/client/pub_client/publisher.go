@@ -19,6 +19,9 @@ type PublisherConfiguration struct {
 	Brokers        []string
 	PublisherName  string // for debugging
 	RecordType     *schema_pb.RecordType
+	// with a producer id, the messages are published idempotently:
+	// the unacked messages are resent after reconnecting, and the brokers drop the duplicated ones
+	ProducerId string
 }
 
 type PublishClient struct {
@@ -32,6 +35,9 @@ type TopicPublisher struct {
 	sync.Mutex       // protects grpc
 	config           *PublisherConfiguration
 	jobs             []*EachPartitionPublishJob
+	// counters of the idempotent publisher, to wait for the acks before committing offsets
+	enqueuedCount int64
+	ackedCount    int64
 }
 
 func NewTopicPublisher(config *PublisherConfiguration) *TopicPublisher {
@@ -28,6 +28,10 @@ type EachPartitionPublishJob struct {
 	wg         sync.WaitGroup
 	generation int
 	inputQueue *buffered_queue.BufferedQueue[*mq_pb.DataMessage]
+	// for the idempotent publisher
+	sequence    int64
+	unacked     []*mq_pb.DataMessage
+	unackedLock sync.Mutex
 }
 
 func (p *TopicPublisher) startSchedulerThread(wg *sync.WaitGroup) error {
@@ -110,8 +114,21 @@ func (p *TopicPublisher) onEachAssignments(generation int, assignments []*mq_pb.
 		job.wg.Add(1)
 		go func(job *EachPartitionPublishJob) {
 			defer job.wg.Done()
-			if err := p.doPublishToPartition(job); err != nil {
+			for {
+				err := p.doPublishToPartition(job)
+				if err == nil {
+					return
+				}
 				errChan <- EachPartitionError{assignment, err, generation}
+				if p.config.ProducerId == "" {
+					return
+				}
+				// the idempotent publisher reconnects, and resends the unacked messages
+				select {
+				case <-job.stopChan:
+					return
+				case <-time.After(1337 * time.Millisecond):
+				}
 			}
 		}(job)
 		jobs = append(jobs, job)
@@ -147,6 +164,7 @@ func (p *TopicPublisher) doPublishToPartition(job *EachPartitionPublishJob) erro
 				AckInterval:    128,
 				FollowerBroker: job.FollowerBroker,
 				PublisherName:  p.config.PublisherName,
+				ProducerId:     p.config.ProducerId,
 			},
 		},
 	}); err != nil {
@@ -160,6 +178,10 @@ func (p *TopicPublisher) doPublishToPartition(job *EachPartitionPublishJob) erro
 	if resp.Error != "" {
 		return fmt.Errorf("init response error: %v", resp.Error)
 	}
+	if p.config.ProducerId != "" && resp.ProducerSequence > job.sequence {
+		// continue the sequence of a previous run of the producer
+		job.sequence = resp.ProducerSequence
+	}
 
 	var publishedTsNs int64
 	hasMoreData := int32(1)
@@ -186,6 +208,7 @@ func (p *TopicPublisher) doPublishToPartition(job *EachPartitionPublishJob) erro
 			}
 			if ackResp.AckSequence > 0 {
 				log.Printf("ack %d published %d hasMoreData:%d", ackResp.AckSequence, atomic.LoadInt64(&publishedTsNs), atomic.LoadInt32(&hasMoreData))
+				p.onAcked(job, ackResp.AckSequence)
 			}
 			if atomic.LoadInt64(&publishedTsNs) <= ackResp.AckSequence && atomic.LoadInt32(&hasMoreData) == 0 {
 				return
@@ -194,11 +217,37 @@ func (p *TopicPublisher) doPublishToPartition(job *EachPartitionPublishJob) erro
 	}()
 
 	publishCounter := 0
+	// resend the messages not acked before reconnecting
+	job.unackedLock.Lock()
+	resending := append([]*mq_pb.DataMessage(nil), job.unacked...)
+	job.unackedLock.Unlock()
+	for _, data := range resending {
+		if data.Ctrl != nil && data.Ctrl.IsClose {
+			atomic.StoreInt32(&hasMoreData, 0)
+		}
+		if err := publishClient.Send(&mq_pb.PublishMessageRequest{
+			Message: &mq_pb.PublishMessageRequest_Data{
+				Data: data,
+			},
+		}); err != nil {
+			return fmt.Errorf("resend publish data: %v", err)
+		}
+		publishCounter++
+		atomic.StoreInt64(&publishedTsNs, data.TsNs)
+	}
+
 	for data, hasData := job.inputQueue.Dequeue(); hasData; data, hasData = job.inputQueue.Dequeue() {
 		if data.Ctrl != nil && data.Ctrl.IsClose {
 			// need to set this before sending to brokers, to avoid timing issue
 			atomic.StoreInt32(&hasMoreData, 0)
 		}
+		if p.config.ProducerId != "" {
+			job.sequence++
+			data.Sequence = job.sequence
+			job.unackedLock.Lock()
+			job.unacked = append(job.unacked, data)
+			job.unackedLock.Unlock()
+		}
 		if err := publishClient.Send(&mq_pb.PublishMessageRequest{
 			Message: &mq_pb.PublishMessageRequest_Data{
 				Data: data,
@@ -223,6 +272,21 @@ func (p *TopicPublisher) doPublishToPartition(job *EachPartitionPublishJob) erro
 	return nil
 }
 
+// onAcked drops the messages acked by the broker from the ones to resend
+func (p *TopicPublisher) onAcked(job *EachPartitionPublishJob, ackTsNs int64) {
+	job.unackedLock.Lock()
+	defer job.unackedLock.Unlock()
+
+	ackedCount := 0
+	for ackedCount < len(job.unacked) && job.unacked[ackedCount].TsNs <= ackTsNs {
+		ackedCount++
+	}
+	if ackedCount > 0 {
+		job.unacked = job.unacked[ackedCount:]
+		atomic.AddInt64(&p.ackedCount, int64(ackedCount))
+	}
+}
+
 func (p *TopicPublisher) doConfigureTopic() (err error) {
 	if len(p.config.Brokers) == 0 {
 		return fmt.Errorf("no bootstrap brokers")
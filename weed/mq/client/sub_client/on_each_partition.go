@@ -8,6 +8,7 @@ import (
 	"github.com/seaweedfs/seaweedfs/weed/pb/mq_pb"
 	"github.com/seaweedfs/seaweedfs/weed/util"
 	"io"
+	"time"
 )
 
 func (sub *TopicSubscriber) onEachPartition(assigned *mq_pb.BrokerPartitionAssignment, stopCh chan struct{}) error {
@@ -24,6 +25,11 @@ func (sub *TopicSubscriber) onEachPartition(assigned *mq_pb.BrokerPartitionAssig
 			perPartitionConcurrency = 1
 		}
 
+		var deadLetterTopic *mq_pb.Topic
+		if sub.SubscriberConfig.DeadLetterTopic.Name != "" {
+			deadLetterTopic = sub.SubscriberConfig.DeadLetterTopic.ToPbTopic()
+		}
+
 		if err = subscribeClient.Send(&mq_pb.SubscribeMessageRequest{
 			Message: &mq_pb.SubscribeMessageRequest_Init{
 				Init: &mq_pb.SubscribeMessageRequest_InitMessage{
@@ -34,9 +40,12 @@ func (sub *TopicSubscriber) onEachPartition(assigned *mq_pb.BrokerPartitionAssig
 						Partition: assigned.Partition,
 						StartType: mq_pb.PartitionOffsetStartType_EARLIEST_IN_MEMORY,
 					},
-					Filter:         sub.ContentConfig.Filter,
-					FollowerBroker: assigned.FollowerBroker,
-					Concurrency:    perPartitionConcurrency,
+					Filter:              sub.ContentConfig.Filter,
+					FollowerBroker:      assigned.FollowerBroker,
+					Concurrency:         perPartitionConcurrency,
+					MaxDeliveryAttempts: sub.SubscriberConfig.MaxDeliveryAttempts,
+					RedeliveryDelayMs:   int32(sub.SubscriberConfig.RedeliveryDelay / time.Millisecond),
+					DeadLetterTopic:     deadLetterTopic,
 				},
 			},
 		}); err != nil {
@@ -52,6 +61,7 @@ func (sub *TopicSubscriber) onEachPartition(assigned *mq_pb.BrokerPartitionAssig
 		type KeyedOffset struct {
 			Key    []byte
 			Offset int64
+			Err    error // negatively acked
 		}
 
 		partitionOffsetChan := make(chan KeyedOffset, 1024)
@@ -71,6 +81,18 @@ func (sub *TopicSubscriber) onEachPartition(assigned *mq_pb.BrokerPartitionAssig
 						subscribeClient.CloseSend()
 						return
 					}
+					if ack.Err != nil {
+						subscribeClient.SendMsg(&mq_pb.SubscribeMessageRequest{
+							Message: &mq_pb.SubscribeMessageRequest_Nack{
+								Nack: &mq_pb.SubscribeMessageRequest_NackMessage{
+									Key:      ack.Key,
+									Sequence: ack.Offset,
+									Error:    ack.Err.Error(),
+								},
+							},
+						})
+						continue
+					}
 					subscribeClient.SendMsg(&mq_pb.SubscribeMessageRequest{
 						Message: &mq_pb.SubscribeMessageRequest_Ack{
 							Ack: &mq_pb.SubscribeMessageRequest_AckMessage{
@@ -108,6 +130,14 @@ func (sub *TopicSubscriber) onEachPartition(assigned *mq_pb.BrokerPartitionAssig
 							Key:    m.Data.Key,
 							Offset: m.Data.TsNs,
 						}
+					} else if sub.SubscriberConfig.MaxDeliveryAttempts > 0 && m.Data.Key != nil {
+						// let the broker redeliver the message, or move it to the dead-letter topic
+						glog.V(0).Infof("subscriber %s failed to process message %d: %v", sub.SubscriberConfig.ConsumerGroup, m.Data.TsNs, processErr)
+						partitionOffsetChan <- KeyedOffset{
+							Key:    m.Data.Key,
+							Offset: m.Data.TsNs,
+							Err:    processErr,
+						}
 					} else {
 						lastErr = processErr
 					}
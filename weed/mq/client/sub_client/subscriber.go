@@ -13,13 +13,16 @@ type SubscriberConfiguration struct {
 	ConsumerGroup           string
 	ConsumerGroupInstanceId string
 	GrpcDialOption          grpc.DialOption
-	MaxPartitionCount       int32 // how many partitions to process concurrently
-	PerPartitionConcurrency int32 // how many messages to process concurrently per partition
+	MaxPartitionCount       int32         // how many partitions to process concurrently
+	PerPartitionConcurrency int32         // how many messages to process concurrently per partition
+	MaxDeliveryAttempts     int32         // if positive, the failed messages are redelivered instead of stopping the partition
+	RedeliveryDelay         time.Duration // delay before redelivering a failed message, doubled after each failure
+	DeadLetterTopic         topic.Topic   // receives the messages failed MaxDeliveryAttempts times, which are dropped if not set
 }
 
 type ContentConfiguration struct {
 	Topic     topic.Topic
-	Filter    string
+	Filter    string // evaluated by the broker on the record values, see schema.RecordFilter
 	StartTime time.Time
 }
 
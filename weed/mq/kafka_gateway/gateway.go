@@ -0,0 +1,251 @@
+package kafka_gateway
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/mq/topic"
+	"github.com/seaweedfs/seaweedfs/weed/pb"
+	"github.com/seaweedfs/seaweedfs/weed/pb/mq_pb"
+	"google.golang.org/grpc"
+)
+
+type KafkaGatewayOption struct {
+	Brokers        []pb.ServerAddress
+	Filer          pb.ServerAddress
+	GrpcDialOption grpc.DialOption
+	// Namespace is the SeaweedMQ namespace of the kafka topics
+	Namespace string
+	// Ip and Port are advertised to the kafka clients as the only broker
+	Ip   string
+	Port int
+	// DefaultPartitionCount is the partition count of the topics created on first use, 0 to disable the auto creation
+	DefaultPartitionCount int32
+}
+
+// KafkaGateway speaks the kafka wire protocol to the kafka clients,
+// and maps the kafka topics, partitions and consumer group offsets onto SeaweedMQ.
+//
+// The kafka partitions of a topic are the SeaweedMQ partitions ordered by range,
+// and the kafka offset of a message is its SeaweedMQ timestamp in nanoseconds.
+type KafkaGateway struct {
+	option *KafkaGatewayOption
+	mq     messageQueue
+	groups *groupCoordinator
+
+	topicsLock sync.Mutex
+	topics     map[string]*cachedTopic
+}
+
+type cachedTopic struct {
+	assignments []*mq_pb.BrokerPartitionAssignment
+	lastUpdated time.Time
+}
+
+type requestHeader struct {
+	apiKey        int16
+	apiVersion    int16
+	correlationId int32
+	clientId      string
+}
+
+const (
+	maxRequestSize = 100 * 1024 * 1024
+	topicCacheTTL  = 10 * time.Second
+)
+
+var validTopicName = regexp.MustCompile(`^[a-zA-Z0-9._-]{1,249}$`)
+
+func NewKafkaGateway(option *KafkaGatewayOption) *KafkaGateway {
+	clientName := fmt.Sprintf("kafka_gateway@%s", pb.NewServerAddress(option.Ip, option.Port, 0))
+	return newKafkaGateway(option, newBrokerMessageQueue(option.Brokers, option.Filer, option.GrpcDialOption, clientName))
+}
+
+func newKafkaGateway(option *KafkaGatewayOption, mq messageQueue) *KafkaGateway {
+	return &KafkaGateway{
+		option: option,
+		mq:     mq,
+		groups: newGroupCoordinator(),
+		topics: make(map[string]*cachedTopic),
+	}
+}
+
+// Serve accepts the kafka client connections until the listener is closed
+func (g *KafkaGateway) Serve(listener net.Listener) error {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+		go g.handleConnection(conn)
+	}
+}
+
+// handleConnection processes the requests of a connection one by one, so the responses are in the order of the requests
+func (g *KafkaGateway) handleConnection(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	sizeBuf := make([]byte, 4)
+	for {
+		if _, err := io.ReadFull(reader, sizeBuf); err != nil {
+			if err != io.EOF {
+				glog.V(1).Infof("kafka client %s: %v", conn.RemoteAddr(), err)
+			}
+			return
+		}
+		size := int32(binary.BigEndian.Uint32(sizeBuf))
+		if size < 8 || size > maxRequestSize {
+			glog.V(0).Infof("kafka client %s: request of %d bytes", conn.RemoteAddr(), size)
+			return
+		}
+		data := make([]byte, size)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			glog.V(1).Infof("kafka client %s: %v", conn.RemoteAddr(), err)
+			return
+		}
+
+		d := newDecoder(data)
+		header := &requestHeader{
+			apiKey:        d.int16(),
+			apiVersion:    d.int16(),
+			correlationId: d.int32(),
+		}
+		if clientId := d.nullableString(); clientId != nil {
+			header.clientId = *clientId
+		}
+		if d.err != nil {
+			glog.V(0).Infof("kafka client %s: request header: %v", conn.RemoteAddr(), d.err)
+			return
+		}
+
+		// the response size is set after the response is encoded
+		e := &encoder{}
+		e.int32(0)
+		e.int32(header.correlationId)
+		respond, err := g.handleRequest(header, d, e)
+		if err != nil {
+			glog.V(0).Infof("kafka client %s api key %d version %d: %v", conn.RemoteAddr(), header.apiKey, header.apiVersion, err)
+			return
+		}
+		if !respond {
+			continue
+		}
+		binary.BigEndian.PutUint32(e.buf, uint32(len(e.buf)-4))
+		if _, err := conn.Write(e.buf); err != nil {
+			glog.V(1).Infof("kafka client %s: %v", conn.RemoteAddr(), err)
+			return
+		}
+	}
+}
+
+// handleRequest decodes the request and encodes the response body. An error closes the connection,
+// as the kafka brokers do for the malformed requests and the unsupported versions.
+func (g *KafkaGateway) handleRequest(header *requestHeader, d *decoder, e *encoder) (respond bool, err error) {
+	if header.apiKey == apiKeyApiVersions && !isSupportedApiVersion(header.apiKey, header.apiVersion) {
+		// the clients retry with the versions in the response
+		g.encodeApiVersions(e, 0, errUnsupportedVersion)
+		return true, nil
+	}
+	if !isSupportedApiVersion(header.apiKey, header.apiVersion) {
+		return false, fmt.Errorf("unsupported version")
+	}
+
+	switch header.apiKey {
+	case apiKeyApiVersions:
+		return true, g.handleApiVersions(header, d, e)
+	case apiKeyMetadata:
+		return true, g.handleMetadata(header, d, e)
+	case apiKeyProduce:
+		return g.handleProduce(header, d, e)
+	case apiKeyFetch:
+		return true, g.handleFetch(header, d, e)
+	case apiKeyListOffsets:
+		return true, g.handleListOffsets(header, d, e)
+	case apiKeyFindCoordinator:
+		return true, g.handleFindCoordinator(header, d, e)
+	case apiKeyJoinGroup:
+		return true, g.handleJoinGroup(header, d, e)
+	case apiKeySyncGroup:
+		return true, g.handleSyncGroup(header, d, e)
+	case apiKeyHeartbeat:
+		return true, g.handleHeartbeat(header, d, e)
+	case apiKeyLeaveGroup:
+		return true, g.handleLeaveGroup(header, d, e)
+	case apiKeyOffsetCommit:
+		return true, g.handleOffsetCommit(header, d, e)
+	case apiKeyOffsetFetch:
+		return true, g.handleOffsetFetch(header, d, e)
+	}
+	return false, fmt.Errorf("unsupported api key")
+}
+
+func (g *KafkaGateway) topic(name string) topic.Topic {
+	return topic.NewTopic(g.option.Namespace, name)
+}
+
+// lookupTopic returns the partitions of the topic ordered by kafka partition id,
+// and creates the topic with the default partition count if asked
+func (g *KafkaGateway) lookupTopic(name string, autoCreate bool) ([]*mq_pb.BrokerPartitionAssignment, errorCode) {
+	if !validTopicName.MatchString(name) || name == "." || name == ".." {
+		return nil, errInvalidTopic
+	}
+
+	g.topicsLock.Lock()
+	cached, found := g.topics[name]
+	g.topicsLock.Unlock()
+	if found && time.Since(cached.lastUpdated) < topicCacheTTL {
+		return cached.assignments, errNone
+	}
+
+	t := g.topic(name)
+	assignments, err := g.mq.LookupTopic(t)
+	if err != nil {
+		glog.V(0).Infof("lookup topic %s: %v", t, err)
+		return nil, errLeaderNotAvailable
+	}
+	if len(assignments) == 0 {
+		if !autoCreate || g.option.DefaultPartitionCount <= 0 {
+			return nil, errUnknownTopicOrPartition
+		}
+		if assignments, err = g.mq.ConfigureTopic(t, g.option.DefaultPartitionCount); err != nil {
+			glog.V(0).Infof("create topic %s: %v", t, err)
+			return nil, errLeaderNotAvailable
+		}
+		glog.V(0).Infof("created topic %s with %d partitions", t, len(assignments))
+	}
+	sortAssignments(assignments)
+
+	g.topicsLock.Lock()
+	g.topics[name] = &cachedTopic{
+		assignments: assignments,
+		lastUpdated: time.Now(),
+	}
+	g.topicsLock.Unlock()
+	return assignments, errNone
+}
+
+// lookupPartition returns the partition by kafka partition id
+func (g *KafkaGateway) lookupPartition(name string, partition int32) (*mq_pb.BrokerPartitionAssignment, errorCode) {
+	assignments, errCode := g.lookupTopic(name, false)
+	if errCode != errNone {
+		return nil, errCode
+	}
+	if partition < 0 || int(partition) >= len(assignments) {
+		return nil, errUnknownTopicOrPartition
+	}
+	return assignments[partition], errNone
+}
+
+// invalidateTopic drops the cached partitions after a failure, in case the partitions are moved to other brokers
+func (g *KafkaGateway) invalidateTopic(name string) {
+	g.topicsLock.Lock()
+	delete(g.topics, name)
+	g.topicsLock.Unlock()
+}
@@ -0,0 +1,352 @@
+package kafka_gateway
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/seaweedfs/seaweedfs/weed/mq/topic"
+	"github.com/seaweedfs/seaweedfs/weed/pb/mq_pb"
+)
+
+// memoryMessageQueue keeps the topics in memory, in place of the brokers and the filer
+type memoryMessageQueue struct {
+	sync.Mutex
+	topics  map[string][]*memoryPartition
+	offsets map[string]int64
+}
+
+type memoryPartition struct {
+	assignment *mq_pb.BrokerPartitionAssignment
+	messages   []*mq_pb.DataMessage
+	lastTsNs   int64
+}
+
+func newMemoryMessageQueue() *memoryMessageQueue {
+	return &memoryMessageQueue{
+		topics:  make(map[string][]*memoryPartition),
+		offsets: make(map[string]int64),
+	}
+}
+
+func (q *memoryMessageQueue) ListTopics(namespace string) (names []string, err error) {
+	q.Lock()
+	defer q.Unlock()
+	for name := range q.topics {
+		if strings.HasPrefix(name, namespace+".") {
+			names = append(names, strings.TrimPrefix(name, namespace+"."))
+		}
+	}
+	return
+}
+
+func (q *memoryMessageQueue) LookupTopic(t topic.Topic) ([]*mq_pb.BrokerPartitionAssignment, error) {
+	q.Lock()
+	defer q.Unlock()
+	var assignments []*mq_pb.BrokerPartitionAssignment
+	for _, p := range q.topics[t.String()] {
+		assignments = append(assignments, p.assignment)
+	}
+	return assignments, nil
+}
+
+func (q *memoryMessageQueue) ConfigureTopic(t topic.Topic, partitionCount int32) ([]*mq_pb.BrokerPartitionAssignment, error) {
+	q.Lock()
+	defer q.Unlock()
+	var assignments []*mq_pb.BrokerPartitionAssignment
+	rangeSize := topic.PartitionCount / partitionCount
+	for i := int32(0); i < partitionCount; i++ {
+		assignment := &mq_pb.BrokerPartitionAssignment{
+			Partition: &mq_pb.Partition{
+				RingSize:   topic.PartitionCount,
+				RangeStart: i * rangeSize,
+				RangeStop:  (i + 1) * rangeSize,
+				UnixTimeNs: 1,
+			},
+			LeaderBroker: "localhost:17777",
+		}
+		// reversed, for the gateway to order the partitions
+		q.topics[t.String()] = append([]*memoryPartition{{assignment: assignment}}, q.topics[t.String()]...)
+		assignments = append(assignments, assignment)
+	}
+	return assignments, nil
+}
+
+func (q *memoryMessageQueue) partition(t topic.Topic, assignment *mq_pb.BrokerPartitionAssignment) (*memoryPartition, error) {
+	for _, p := range q.topics[t.String()] {
+		if p.assignment.Partition.RangeStart == assignment.Partition.RangeStart {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("partition %v of %s not found", assignment.Partition, t)
+}
+
+func (q *memoryMessageQueue) Publish(t topic.Topic, assignment *mq_pb.BrokerPartitionAssignment, messages []*mq_pb.DataMessage, waitForAck bool) error {
+	q.Lock()
+	defer q.Unlock()
+	p, err := q.partition(t, assignment)
+	if err != nil {
+		return err
+	}
+	for _, message := range messages {
+		message.TsNs = max(time.Now().UnixNano(), p.lastTsNs+1)
+		p.lastTsNs = message.TsNs
+		p.messages = append(p.messages, message)
+	}
+	return nil
+}
+
+func (q *memoryMessageQueue) Read(t topic.Topic, assignment *mq_pb.BrokerPartitionAssignment, startTsNs int64, maxBytes int) (messages []*mq_pb.DataMessage, err error) {
+	q.Lock()
+	defer q.Unlock()
+	p, err := q.partition(t, assignment)
+	if err != nil {
+		return nil, err
+	}
+	var size int
+	for _, message := range p.messages {
+		if message.TsNs < startTsNs {
+			continue
+		}
+		if len(messages) > 0 && size+messageSize(message) > maxBytes {
+			break
+		}
+		messages = append(messages, message)
+		size += messageSize(message)
+	}
+	return messages, nil
+}
+
+func (q *memoryMessageQueue) ReadConsumerGroupOffset(t topic.Topic, partition *mq_pb.Partition, consumerGroup string) (int64, bool, error) {
+	q.Lock()
+	defer q.Unlock()
+	offset, found := q.offsets[fmt.Sprintf("%s/%d/%s", t, partition.RangeStart, consumerGroup)]
+	return offset, found, nil
+}
+
+func (q *memoryMessageQueue) SaveConsumerGroupOffset(t topic.Topic, partition *mq_pb.Partition, consumerGroup string, offset int64) error {
+	q.Lock()
+	defer q.Unlock()
+	q.offsets[fmt.Sprintf("%s/%d/%s", t, partition.RangeStart, consumerGroup)] = offset
+	return nil
+}
+
+func startTestGateway(t *testing.T) (*memoryMessageQueue, string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	mq := newMemoryMessageQueue()
+	gateway := newKafkaGateway(&KafkaGatewayOption{
+		Namespace:             "kafka",
+		Ip:                    "127.0.0.1",
+		Port:                  port,
+		DefaultPartitionCount: 2,
+	}, mq)
+	go gateway.Serve(listener)
+	return mq, listener.Addr().String()
+}
+
+func newTestConfig() *sarama.Config {
+	config := sarama.NewConfig()
+	config.Version = sarama.V2_3_0_0
+	config.Producer.Return.Successes = true
+	config.Producer.RequiredAcks = sarama.WaitForAll
+	config.Producer.Partitioner = sarama.NewManualPartitioner
+	config.Consumer.Return.Errors = true
+	config.Consumer.Offsets.Initial = sarama.OffsetOldest
+	config.Consumer.MaxWaitTime = 100 * time.Millisecond
+	config.Consumer.Group.Heartbeat.Interval = 100 * time.Millisecond
+	config.Consumer.Offsets.AutoCommit.Interval = 100 * time.Millisecond
+	return config
+}
+
+func TestGatewayProduceAndConsume(t *testing.T) {
+	_, address := startTestGateway(t)
+	config := newTestConfig()
+
+	producer, err := sarama.NewSyncProducer([]string{address}, config)
+	if err != nil {
+		t.Fatalf("producer: %v", err)
+	}
+	defer producer.Close()
+
+	var offsets []int64
+	for i := 0; i < 10; i++ {
+		_, offset, err := producer.SendMessage(&sarama.ProducerMessage{
+			Topic:     "events",
+			Partition: 1,
+			Key:       sarama.StringEncoder(fmt.Sprintf("key%d", i)),
+			Value:     sarama.StringEncoder(fmt.Sprintf("value%d", i)),
+		})
+		if err != nil {
+			t.Fatalf("send message %d: %v", i, err)
+		}
+		if len(offsets) > 0 && offset <= offsets[len(offsets)-1] {
+			t.Fatalf("offset %d after %d", offset, offsets[len(offsets)-1])
+		}
+		offsets = append(offsets, offset)
+	}
+
+	client, err := sarama.NewClient([]string{address}, config)
+	if err != nil {
+		t.Fatalf("client: %v", err)
+	}
+	defer client.Close()
+	partitions, err := client.Partitions("events")
+	if err != nil || len(partitions) != 2 {
+		t.Fatalf("partitions of events: %v %v", partitions, err)
+	}
+	newest, err := client.GetOffset("events", 1, sarama.OffsetNewest)
+	if err != nil || newest <= offsets[len(offsets)-1] {
+		t.Fatalf("newest offset %d: %v", newest, err)
+	}
+
+	consumer, err := sarama.NewConsumerFromClient(client)
+	if err != nil {
+		t.Fatalf("consumer: %v", err)
+	}
+	defer consumer.Close()
+
+	// from the offset of the fourth message
+	partitionConsumer, err := consumer.ConsumePartition("events", 1, offsets[3])
+	if err != nil {
+		t.Fatalf("consume partition: %v", err)
+	}
+	defer partitionConsumer.Close()
+	for i := 3; i < 10; i++ {
+		select {
+		case message := <-partitionConsumer.Messages():
+			if message.Offset != offsets[i] || string(message.Key) != fmt.Sprintf("key%d", i) || string(message.Value) != fmt.Sprintf("value%d", i) {
+				t.Fatalf("message %d: offset %d key %s value %s", i, message.Offset, message.Key, message.Value)
+			}
+		case err := <-partitionConsumer.Errors():
+			t.Fatalf("consume: %v", err)
+		case <-time.After(10 * time.Second):
+			t.Fatalf("timed out waiting for message %d", i)
+		}
+	}
+
+	// a message produced after the consumer is waiting
+	if _, _, err = producer.SendMessage(&sarama.ProducerMessage{Topic: "events", Partition: 1, Value: sarama.StringEncoder("late")}); err != nil {
+		t.Fatalf("send late message: %v", err)
+	}
+	select {
+	case message := <-partitionConsumer.Messages():
+		if string(message.Value) != "late" || message.Key != nil {
+			t.Fatalf("late message: key %v value %s", message.Key, message.Value)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("timed out waiting for the late message")
+	}
+}
+
+type testGroupHandler struct {
+	messages chan *sarama.ConsumerMessage
+}
+
+func (h *testGroupHandler) Setup(sarama.ConsumerGroupSession) error   { return nil }
+func (h *testGroupHandler) Cleanup(sarama.ConsumerGroupSession) error { return nil }
+func (h *testGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for message := range claim.Messages() {
+		session.MarkMessage(message, "")
+		h.messages <- message
+	}
+	return nil
+}
+
+func TestGatewayConsumerGroup(t *testing.T) {
+	mq, address := startTestGateway(t)
+	config := newTestConfig()
+
+	producer, err := sarama.NewSyncProducer([]string{address}, config)
+	if err != nil {
+		t.Fatalf("producer: %v", err)
+	}
+	defer producer.Close()
+	lastOffsets := make(map[int32]int64)
+	for i := 0; i < 20; i++ {
+		partition, offset, err := producer.SendMessage(&sarama.ProducerMessage{
+			Topic:     "orders",
+			Partition: int32(i % 2),
+			Value:     sarama.StringEncoder(fmt.Sprintf("order%d", i)),
+		})
+		if err != nil {
+			t.Fatalf("send message %d: %v", i, err)
+		}
+		lastOffsets[partition] = offset
+	}
+
+	group, err := sarama.NewConsumerGroup([]string{address}, "billing", config)
+	if err != nil {
+		t.Fatalf("consumer group: %v", err)
+	}
+	handler := &testGroupHandler{messages: make(chan *sarama.ConsumerMessage, 100)}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for ctx.Err() == nil {
+			if err := group.Consume(ctx, []string{"orders"}, handler); err != nil {
+				t.Errorf("consume: %v", err)
+				return
+			}
+		}
+	}()
+
+	received := make(map[string]bool)
+	for len(received) < 20 {
+		select {
+		case message := <-handler.messages:
+			received[string(message.Value)] = true
+		case <-time.After(10 * time.Second):
+			t.Fatalf("timed out with %d messages received", len(received))
+		}
+	}
+	cancel()
+	<-done
+	if err = group.Close(); err != nil {
+		t.Fatalf("close consumer group: %v", err)
+	}
+
+	// the committed offsets are the next offsets to consume, saved as the consumer group offsets of the partitions
+	assignments, _ := mq.LookupTopic(topic.NewTopic("kafka", "orders"))
+	sortAssignments(assignments)
+	for partition, lastOffset := range lastOffsets {
+		offset, found, _ := mq.ReadConsumerGroupOffset(topic.NewTopic("kafka", "orders"), assignments[partition].Partition, "billing")
+		if !found || offset != lastOffset+1 {
+			t.Errorf("partition %d committed offset %d found %v, want %d", partition, offset, found, lastOffset+1)
+		}
+	}
+
+	offsetManager, err := sarama.NewOffsetManagerFromClient("billing", mustNewClient(t, address, config))
+	if err != nil {
+		t.Fatalf("offset manager: %v", err)
+	}
+	defer offsetManager.Close()
+	partitionOffsetManager, err := offsetManager.ManagePartition("orders", 0)
+	if err != nil {
+		t.Fatalf("manage partition: %v", err)
+	}
+	defer partitionOffsetManager.Close()
+	if offset, _ := partitionOffsetManager.NextOffset(); offset != lastOffsets[0]+1 {
+		t.Errorf("fetched offset %d, want %d", offset, lastOffsets[0]+1)
+	}
+}
+
+func mustNewClient(t *testing.T, address string, config *sarama.Config) sarama.Client {
+	client, err := sarama.NewClient([]string{address}, config)
+	if err != nil {
+		t.Fatalf("client: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
@@ -0,0 +1,437 @@
+package kafka_gateway
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+)
+
+// groupCoordinator runs the kafka consumer group protocol for all the groups in memory.
+// The members of a group need to connect to the same gateway, while the committed offsets are saved on filer.
+//
+// A group is Empty without members. A join moves it to PreparingRebalance, until all the known members
+// have joined again or the rebalance timeout, then to CompletingRebalance until the leader syncs the
+// assignments, and then to Stable.
+type groupCoordinator struct {
+	sync.Mutex
+	groups map[string]*consumerGroup
+}
+
+type groupState int
+
+const (
+	groupEmpty groupState = iota
+	groupPreparingRebalance
+	groupCompletingRebalance
+	groupStable
+)
+
+type groupProtocol struct {
+	name     string
+	metadata []byte
+}
+
+type groupMember struct {
+	id               string
+	sessionTimeout   time.Duration
+	rebalanceTimeout time.Duration
+	protocols        []groupProtocol
+	assignment       []byte
+	lastHeartbeat    time.Time
+	joinCh           chan *joinResult
+	syncCh           chan *syncResult
+}
+
+type consumerGroup struct {
+	id             string
+	state          groupState
+	generation     int32
+	protocolType   string
+	protocol       string
+	leader         string
+	members        map[string]*groupMember
+	joinOrder      []string
+	rebalanceTimer *time.Timer
+}
+
+type joinResult struct {
+	errCode    errorCode
+	generation int32
+	protocol   string
+	leader     string
+	memberId   string
+	members    []joinResultMember
+}
+
+type joinResultMember struct {
+	memberId string
+	metadata []byte
+}
+
+type syncResult struct {
+	errCode    errorCode
+	assignment []byte
+}
+
+const (
+	minSessionTimeout = 6 * time.Second
+	maxSessionTimeout = 30 * time.Minute
+)
+
+func newGroupCoordinator() *groupCoordinator {
+	c := &groupCoordinator{
+		groups: make(map[string]*consumerGroup),
+	}
+	go c.loopExpiringMembers()
+	return c
+}
+
+func (c *groupCoordinator) getOrCreateGroup(groupId string) *consumerGroup {
+	group, found := c.groups[groupId]
+	if !found {
+		group = &consumerGroup{
+			id:      groupId,
+			members: make(map[string]*groupMember),
+		}
+		c.groups[groupId] = group
+	}
+	return group
+}
+
+// joinGroup adds the member to the group, and waits for the rebalance to complete
+func (c *groupCoordinator) joinGroup(groupId, memberId, clientId, protocolType string, sessionTimeout, rebalanceTimeout time.Duration, protocols []groupProtocol) *joinResult {
+	if groupId == "" {
+		return &joinResult{errCode: errInvalidGroupId, memberId: memberId}
+	}
+	if sessionTimeout < minSessionTimeout || sessionTimeout > maxSessionTimeout {
+		return &joinResult{errCode: errInvalidSessionTimeout, memberId: memberId}
+	}
+
+	c.Lock()
+	group := c.getOrCreateGroup(groupId)
+	if len(group.members) > 0 && (protocolType != group.protocolType || !group.supportsProtocols(protocols)) {
+		c.Unlock()
+		return &joinResult{errCode: errInconsistentGroupProtocol, memberId: memberId}
+	}
+
+	member, found := group.members[memberId]
+	if memberId == "" {
+		memberId = fmt.Sprintf("%s-%s", clientId, uuid.New().String())
+	} else if !found {
+		c.Unlock()
+		return &joinResult{errCode: errUnknownMemberId, memberId: memberId}
+	}
+	if member == nil {
+		member = &groupMember{id: memberId}
+		group.members[memberId] = member
+		group.joinOrder = append(group.joinOrder, memberId)
+	}
+	member.sessionTimeout = sessionTimeout
+	member.rebalanceTimeout = rebalanceTimeout
+	member.protocols = protocols
+	member.lastHeartbeat = time.Now()
+	joinCh := make(chan *joinResult, 1)
+	member.joinCh = joinCh
+	group.protocolType = protocolType
+
+	if group.state != groupPreparingRebalance {
+		c.prepareRebalance(group)
+	}
+	if group.allMembersJoined() {
+		c.completeJoin(group)
+	}
+	c.Unlock()
+
+	return <-joinCh
+}
+
+func (group *consumerGroup) supportsProtocols(protocols []groupProtocol) bool {
+	for _, member := range group.members {
+		if !hasCommonProtocol(member.protocols, protocols) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasCommonProtocol(a, b []groupProtocol) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x.name == y.name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (group *consumerGroup) allMembersJoined() bool {
+	for _, member := range group.members {
+		if member.joinCh == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// prepareRebalance waits for the members to join again, until the longest rebalance timeout of the members
+func (c *groupCoordinator) prepareRebalance(group *consumerGroup) {
+	group.state = groupPreparingRebalance
+	var rebalanceTimeout time.Duration
+	for _, member := range group.members {
+		rebalanceTimeout = max(rebalanceTimeout, member.rebalanceTimeout)
+		if member.syncCh != nil {
+			member.syncCh <- &syncResult{errCode: errRebalanceInProgress}
+			member.syncCh = nil
+		}
+	}
+	if group.rebalanceTimer != nil {
+		group.rebalanceTimer.Stop()
+	}
+	group.rebalanceTimer = time.AfterFunc(rebalanceTimeout, func() {
+		c.Lock()
+		defer c.Unlock()
+		if group.state == groupPreparingRebalance {
+			c.completeJoin(group)
+		}
+	})
+}
+
+// completeJoin removes the members not joined again, and starts a new generation with the joined members
+func (c *groupCoordinator) completeJoin(group *consumerGroup) {
+	if group.rebalanceTimer != nil {
+		group.rebalanceTimer.Stop()
+		group.rebalanceTimer = nil
+	}
+	for memberId, member := range group.members {
+		if member.joinCh == nil {
+			glog.V(0).Infof("consumer group %s: member %s did not join in time", group.id, memberId)
+			group.removeMember(memberId)
+		}
+	}
+	if len(group.members) == 0 {
+		group.state = groupEmpty
+		return
+	}
+
+	group.generation++
+	group.state = groupCompletingRebalance
+	if _, found := group.members[group.leader]; !found {
+		group.leader = group.joinOrder[0]
+	}
+	group.protocol = group.selectProtocol()
+
+	var members []joinResultMember
+	for _, memberId := range group.joinOrder {
+		members = append(members, joinResultMember{
+			memberId: memberId,
+			metadata: group.members[memberId].protocolMetadata(group.protocol),
+		})
+	}
+	for _, member := range group.members {
+		result := &joinResult{
+			generation: group.generation,
+			protocol:   group.protocol,
+			leader:     group.leader,
+			memberId:   member.id,
+		}
+		if member.id == group.leader {
+			result.members = members
+		}
+		member.joinCh <- result
+		member.joinCh = nil
+		member.assignment = nil
+		member.lastHeartbeat = time.Now()
+	}
+}
+
+// selectProtocol picks the protocol of the leader in its preference order, which all the members support
+func (group *consumerGroup) selectProtocol() string {
+	for _, protocol := range group.members[group.leader].protocols {
+		supported := true
+		for _, member := range group.members {
+			if member.protocolMetadata(protocol.name) == nil {
+				supported = false
+				break
+			}
+		}
+		if supported {
+			return protocol.name
+		}
+	}
+	return group.members[group.leader].protocols[0].name
+}
+
+func (member *groupMember) protocolMetadata(name string) []byte {
+	for _, protocol := range member.protocols {
+		if protocol.name == name {
+			if protocol.metadata == nil {
+				return []byte{}
+			}
+			return protocol.metadata
+		}
+	}
+	return nil
+}
+
+func (group *consumerGroup) removeMember(memberId string) {
+	delete(group.members, memberId)
+	for i, id := range group.joinOrder {
+		if id == memberId {
+			group.joinOrder = append(group.joinOrder[:i], group.joinOrder[i+1:]...)
+			break
+		}
+	}
+}
+
+// syncGroup receives the assignments from the leader, and waits for the assignment of the member
+func (c *groupCoordinator) syncGroup(groupId, memberId string, generation int32, assignments map[string][]byte) *syncResult {
+	c.Lock()
+	group, member, errCode := c.validateMember(groupId, memberId, generation)
+	if errCode != errNone {
+		c.Unlock()
+		return &syncResult{errCode: errCode}
+	}
+	switch group.state {
+	case groupPreparingRebalance:
+		c.Unlock()
+		return &syncResult{errCode: errRebalanceInProgress}
+	case groupStable:
+		c.Unlock()
+		return &syncResult{assignment: member.assignment}
+	}
+
+	syncCh := make(chan *syncResult, 1)
+	member.syncCh = syncCh
+	if memberId == group.leader {
+		for _, m := range group.members {
+			m.assignment = assignments[m.id]
+			if m.assignment == nil {
+				m.assignment = []byte{}
+			}
+			if m.syncCh != nil {
+				m.syncCh <- &syncResult{assignment: m.assignment}
+				m.syncCh = nil
+			}
+		}
+		group.state = groupStable
+	}
+	c.Unlock()
+
+	return <-syncCh
+}
+
+// heartbeat keeps the member alive, and tells the member to join again during a rebalance
+func (c *groupCoordinator) heartbeat(groupId, memberId string, generation int32) errorCode {
+	c.Lock()
+	defer c.Unlock()
+	group, member, errCode := c.validateMember(groupId, memberId, generation)
+	if errCode != errNone {
+		return errCode
+	}
+	member.lastHeartbeat = time.Now()
+	if group.state == groupPreparingRebalance {
+		return errRebalanceInProgress
+	}
+	return errNone
+}
+
+// leaveGroup removes the member, and rebalances the group for the remaining members
+func (c *groupCoordinator) leaveGroup(groupId, memberId string) errorCode {
+	c.Lock()
+	defer c.Unlock()
+	group, found := c.groups[groupId]
+	if !found {
+		return errUnknownMemberId
+	}
+	member, found := group.members[memberId]
+	if !found {
+		return errUnknownMemberId
+	}
+	c.removeMemberAndRebalance(group, member)
+	return errNone
+}
+
+func (c *groupCoordinator) removeMemberAndRebalance(group *consumerGroup, member *groupMember) {
+	if member.joinCh != nil {
+		member.joinCh <- &joinResult{errCode: errUnknownMemberId, memberId: member.id}
+	}
+	if member.syncCh != nil {
+		member.syncCh <- &syncResult{errCode: errUnknownMemberId}
+	}
+	group.removeMember(member.id)
+	if len(group.members) == 0 {
+		if group.rebalanceTimer != nil {
+			group.rebalanceTimer.Stop()
+			group.rebalanceTimer = nil
+		}
+		group.state = groupEmpty
+		return
+	}
+	if group.state != groupPreparingRebalance {
+		c.prepareRebalance(group)
+	}
+	if group.allMembersJoined() {
+		c.completeJoin(group)
+	}
+}
+
+// validateOffsetCommit checks the member can commit offsets, a commit without member and generation is from a standalone consumer
+func (c *groupCoordinator) validateOffsetCommit(groupId, memberId string, generation int32) errorCode {
+	if groupId == "" {
+		return errInvalidGroupId
+	}
+	if memberId == "" && generation < 0 {
+		return errNone
+	}
+	c.Lock()
+	defer c.Unlock()
+	group, _, errCode := c.validateMember(groupId, memberId, generation)
+	if errCode == errNone && group.state == groupPreparingRebalance {
+		return errRebalanceInProgress
+	}
+	return errCode
+}
+
+func (c *groupCoordinator) validateMember(groupId, memberId string, generation int32) (*consumerGroup, *groupMember, errorCode) {
+	group, found := c.groups[groupId]
+	if !found {
+		return nil, nil, errUnknownMemberId
+	}
+	member, found := group.members[memberId]
+	if !found {
+		return nil, nil, errUnknownMemberId
+	}
+	if generation != group.generation {
+		return nil, nil, errIllegalGeneration
+	}
+	return group, member, errNone
+}
+
+// loopExpiringMembers removes the members without heartbeats within the session timeout
+func (c *groupCoordinator) loopExpiringMembers() {
+	for {
+		time.Sleep(time.Second)
+		c.expireMembers(time.Now())
+	}
+}
+
+func (c *groupCoordinator) expireMembers(now time.Time) {
+	c.Lock()
+	defer c.Unlock()
+	for groupId, group := range c.groups {
+		for _, member := range group.members {
+			// the members waiting for the join to complete are alive
+			if member.joinCh == nil && member.lastHeartbeat.Add(member.sessionTimeout).Before(now) {
+				glog.V(0).Infof("consumer group %s: member %s session expired", group.id, member.id)
+				c.removeMemberAndRebalance(group, member)
+			}
+		}
+		if group.state == groupEmpty && len(group.members) == 0 {
+			delete(c.groups, groupId)
+		}
+	}
+}
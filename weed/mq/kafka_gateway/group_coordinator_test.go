@@ -0,0 +1,112 @@
+package kafka_gateway
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGroupCoordinatorRebalance(t *testing.T) {
+	c := &groupCoordinator{groups: make(map[string]*consumerGroup)}
+	protocols := []groupProtocol{{name: "range", metadata: []byte("m")}}
+
+	first := c.joinGroup("g", "", "c1", "consumer", 10*time.Second, time.Second, protocols)
+	if first.errCode != errNone || first.generation != 1 || first.leader != first.memberId || len(first.members) != 1 {
+		t.Fatalf("first join: %+v", first)
+	}
+	if sync := c.syncGroup("g", first.memberId, 1, map[string][]byte{first.memberId: []byte("a1")}); sync.errCode != errNone || string(sync.assignment) != "a1" {
+		t.Fatalf("first sync: %+v", sync)
+	}
+	if errCode := c.heartbeat("g", first.memberId, 1); errCode != errNone {
+		t.Fatalf("heartbeat: %v", errCode)
+	}
+
+	// a second member triggers a rebalance, and the first member joins again after the heartbeat
+	secondCh := make(chan *joinResult)
+	go func() {
+		secondCh <- c.joinGroup("g", "", "c2", "consumer", 10*time.Second, 5*time.Second, protocols)
+	}()
+	for c.heartbeat("g", first.memberId, 1) != errRebalanceInProgress {
+		time.Sleep(time.Millisecond)
+	}
+	rejoin := c.joinGroup("g", first.memberId, "c1", "consumer", 10*time.Second, time.Second, protocols)
+	second := <-secondCh
+	if rejoin.generation != 2 || second.generation != 2 || rejoin.leader != first.memberId || second.leader != first.memberId {
+		t.Fatalf("rejoin: %+v, second: %+v", rejoin, second)
+	}
+	if len(rejoin.members) != 2 || len(second.members) != 0 {
+		t.Fatalf("only the leader gets the members: %+v, %+v", rejoin.members, second.members)
+	}
+
+	if errCode := c.heartbeat("g", first.memberId, 1); errCode != errIllegalGeneration {
+		t.Errorf("heartbeat of old generation: %v", errCode)
+	}
+	if errCode := c.validateOffsetCommit("g", second.memberId, 1); errCode != errIllegalGeneration {
+		t.Errorf("commit of old generation: %v", errCode)
+	}
+
+	syncCh := make(chan *syncResult)
+	go func() {
+		syncCh <- c.syncGroup("g", second.memberId, 2, nil)
+	}()
+	leaderSync := c.syncGroup("g", first.memberId, 2, map[string][]byte{
+		first.memberId:  []byte("a1"),
+		second.memberId: []byte("a2"),
+	})
+	if string(leaderSync.assignment) != "a1" || string((<-syncCh).assignment) != "a2" {
+		t.Fatalf("sync: %+v", leaderSync)
+	}
+	if errCode := c.validateOffsetCommit("g", second.memberId, 2); errCode != errNone {
+		t.Errorf("commit: %v", errCode)
+	}
+
+	// the leader leaves, and the remaining member becomes the leader of the next generation
+	if errCode := c.leaveGroup("g", first.memberId); errCode != errNone {
+		t.Fatalf("leave: %v", errCode)
+	}
+	if errCode := c.heartbeat("g", second.memberId, 2); errCode != errRebalanceInProgress {
+		t.Fatalf("heartbeat after leave: %v", errCode)
+	}
+	last := c.joinGroup("g", second.memberId, "c2", "consumer", 10*time.Second, time.Second, protocols)
+	if last.generation != 3 || last.leader != second.memberId {
+		t.Fatalf("join after leave: %+v", last)
+	}
+}
+
+func TestGroupCoordinatorRejects(t *testing.T) {
+	c := &groupCoordinator{groups: make(map[string]*consumerGroup)}
+	protocols := []groupProtocol{{name: "range"}}
+
+	if result := c.joinGroup("", "", "c", "consumer", 10*time.Second, time.Second, protocols); result.errCode != errInvalidGroupId {
+		t.Errorf("empty group id: %v", result.errCode)
+	}
+	if result := c.joinGroup("g", "", "c", "consumer", time.Second, time.Second, protocols); result.errCode != errInvalidSessionTimeout {
+		t.Errorf("short session timeout: %v", result.errCode)
+	}
+	if result := c.joinGroup("g", "unknown", "c", "consumer", 10*time.Second, time.Second, protocols); result.errCode != errUnknownMemberId {
+		t.Errorf("unknown member: %v", result.errCode)
+	}
+	if result := c.joinGroup("g", "", "c", "consumer", 10*time.Second, time.Second, protocols); result.errCode != errNone {
+		t.Fatalf("join: %v", result.errCode)
+	}
+	if result := c.joinGroup("g", "", "c", "consumer", 10*time.Second, time.Second, []groupProtocol{{name: "roundrobin"}}); result.errCode != errInconsistentGroupProtocol {
+		t.Errorf("inconsistent protocol: %v", result.errCode)
+	}
+	if errCode := c.validateOffsetCommit("standalone", "", -1); errCode != errNone {
+		t.Errorf("standalone commit: %v", errCode)
+	}
+}
+
+func TestGroupCoordinatorExpireMembers(t *testing.T) {
+	c := &groupCoordinator{groups: make(map[string]*consumerGroup)}
+	result := c.joinGroup("g", "", "c", "consumer", 10*time.Second, time.Second, []groupProtocol{{name: "range"}})
+
+	c.expireMembers(time.Now())
+	if errCode := c.heartbeat("g", result.memberId, result.generation); errCode != errNone {
+		t.Fatalf("heartbeat: %v", errCode)
+	}
+
+	c.expireMembers(time.Now().Add(11 * time.Second))
+	if _, found := c.groups["g"]; found {
+		t.Errorf("the group without members is not removed")
+	}
+}
@@ -0,0 +1,229 @@
+package kafka_gateway
+
+import (
+	"time"
+
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/pb/mq_pb"
+)
+
+const fetchPollInterval = 20 * time.Millisecond
+
+type fetchPartition struct {
+	index       int32
+	fetchOffset int64
+	maxBytes    int32
+	errCode     errorCode
+	assignment  *mq_pb.BrokerPartitionAssignment
+	records     []record
+	size        int
+}
+
+type fetchTopic struct {
+	name       string
+	partitions []*fetchPartition
+}
+
+// handleFetch reads the messages from the fetch offsets, which are the SeaweedMQ timestamps.
+// The read polls until min bytes are received or max wait passes. Fetch sessions are not supported,
+// so the clients always send the full requests.
+func (g *KafkaGateway) handleFetch(header *requestHeader, d *decoder, e *encoder) error {
+	v := header.apiVersion
+	d.int32() // replica id
+	maxWait := time.Duration(d.int32()) * time.Millisecond
+	minBytes := int(d.int32())
+	maxBytes := int(d.int32())
+	d.int8() // isolation level
+	if v >= 7 {
+		d.int32() // session id
+		d.int32() // session epoch
+	}
+	var topics []*fetchTopic
+	topicCount := d.arrayLength()
+	for i := 0; i < topicCount && d.err == nil; i++ {
+		t := &fetchTopic{name: d.string()}
+		partitionCount := d.arrayLength()
+		for j := 0; j < partitionCount && d.err == nil; j++ {
+			p := &fetchPartition{index: d.int32()}
+			if v >= 9 {
+				d.int32() // current leader epoch
+			}
+			p.fetchOffset = d.int64()
+			if v >= 5 {
+				d.int64() // log start offset
+			}
+			p.maxBytes = d.int32()
+			t.partitions = append(t.partitions, p)
+		}
+		topics = append(topics, t)
+	}
+	if v >= 7 {
+		forgottenCount := d.arrayLength()
+		for i := 0; i < forgottenCount && d.err == nil; i++ {
+			d.string()
+			d.int32Array()
+		}
+	}
+	if v >= 11 {
+		d.string() // rack id
+	}
+	if d.err != nil {
+		return d.err
+	}
+
+	for _, t := range topics {
+		for _, p := range t.partitions {
+			p.assignment, p.errCode = g.lookupPartition(t.name, p.index)
+		}
+	}
+
+	deadline := time.Now().Add(maxWait)
+	var totalSize int
+	for {
+		totalSize += g.readFetchPartitions(topics, maxBytes-totalSize, totalSize == 0)
+		if totalSize >= max(minBytes, 1) || !time.Now().Before(deadline) {
+			break
+		}
+		time.Sleep(min(fetchPollInterval, time.Until(deadline)))
+	}
+
+	now := time.Now().UnixNano()
+	e.int32(0) // throttle time
+	if v >= 7 {
+		e.int16(int16(errNone))
+		e.int32(0) // session id
+	}
+	e.arrayLength(len(topics))
+	for _, t := range topics {
+		e.string(t.name)
+		e.arrayLength(len(t.partitions))
+		for _, p := range t.partitions {
+			highWatermark := now
+			if len(p.records) > 0 {
+				highWatermark = max(highWatermark, p.records[len(p.records)-1].offset+1)
+			}
+			e.int32(p.index)
+			e.int16(int16(p.errCode))
+			e.int64(highWatermark)
+			e.int64(highWatermark) // last stable offset
+			if v >= 5 {
+				e.int64(0) // log start offset
+			}
+			e.arrayLength(-1) // aborted transactions
+			if v >= 11 {
+				e.int32(-1) // preferred read replica
+			}
+			if len(p.records) == 0 {
+				e.bytes([]byte{})
+			} else {
+				e.bytes(encodeRecordBatches(p.records))
+			}
+		}
+	}
+	return nil
+}
+
+// readFetchPartitions reads the received messages of the partitions within the byte limits, and returns the read bytes.
+// The first message is read even if larger than the limits, so the clients can make progress.
+func (g *KafkaGateway) readFetchPartitions(topics []*fetchTopic, maxBytes int, allowOversized bool) (size int) {
+	for _, t := range topics {
+		for _, p := range t.partitions {
+			if p.errCode != errNone {
+				continue
+			}
+			limit := min(int(p.maxBytes)-p.size, maxBytes-size)
+			if limit <= 0 && !(allowOversized && size == 0 && p.size == 0) {
+				continue
+			}
+			startTsNs := max(p.fetchOffset, 1)
+			if len(p.records) > 0 {
+				startTsNs = p.records[len(p.records)-1].offset + 1
+			}
+			messages, err := g.mq.Read(g.topic(t.name), p.assignment, startTsNs, max(limit, 1))
+			if err != nil {
+				glog.V(0).Infof("read %s partition %d from %d: %v", t.name, p.index, startTsNs, err)
+				g.invalidateTopic(t.name)
+				p.errCode = errLeaderNotAvailable
+				continue
+			}
+			for _, message := range messages {
+				p.records = append(p.records, record{
+					offset:    message.TsNs,
+					timestamp: message.TsNs / int64(time.Millisecond),
+					key:       message.Key,
+					value:     message.Value,
+				})
+				p.size += messageSize(message)
+				size += messageSize(message)
+			}
+		}
+	}
+	return
+}
+
+// handleListOffsets maps the timestamps to the offsets, which are the SeaweedMQ timestamps in nanoseconds
+func (g *KafkaGateway) handleListOffsets(header *requestHeader, d *decoder, e *encoder) error {
+	v := header.apiVersion
+	d.int32() // replica id
+	if v >= 2 {
+		d.int8() // isolation level
+	}
+
+	type listOffsetsPartition struct {
+		index     int32
+		timestamp int64
+	}
+	type listOffsetsTopic struct {
+		name       string
+		partitions []listOffsetsPartition
+	}
+	var topics []listOffsetsTopic
+	topicCount := d.arrayLength()
+	for i := 0; i < topicCount && d.err == nil; i++ {
+		t := listOffsetsTopic{name: d.string()}
+		partitionCount := d.arrayLength()
+		for j := 0; j < partitionCount && d.err == nil; j++ {
+			p := listOffsetsPartition{index: d.int32()}
+			if v >= 4 {
+				d.int32() // current leader epoch
+			}
+			p.timestamp = d.int64()
+			t.partitions = append(t.partitions, p)
+		}
+		topics = append(topics, t)
+	}
+	if d.err != nil {
+		return d.err
+	}
+
+	if v >= 2 {
+		e.int32(0) // throttle time
+	}
+	e.arrayLength(len(topics))
+	for _, t := range topics {
+		e.string(t.name)
+		e.arrayLength(len(t.partitions))
+		for _, p := range t.partitions {
+			_, errCode := g.lookupPartition(t.name, p.index)
+			timestamp, offset := int64(-1), int64(-1)
+			if errCode == errNone {
+				switch p.timestamp {
+				case listOffsetsLatest:
+					offset = time.Now().UnixNano()
+				case listOffsetsEarliest:
+					offset = 0
+				default:
+					timestamp, offset = p.timestamp, p.timestamp*int64(time.Millisecond)
+				}
+			}
+			e.int32(p.index)
+			e.int16(int16(errCode))
+			e.int64(timestamp)
+			e.int64(offset)
+			if v >= 4 {
+				e.int32(0) // leader epoch
+			}
+		}
+	}
+	return nil
+}
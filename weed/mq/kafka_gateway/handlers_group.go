@@ -0,0 +1,150 @@
+package kafka_gateway
+
+import (
+	"time"
+)
+
+func (g *KafkaGateway) handleJoinGroup(header *requestHeader, d *decoder, e *encoder) error {
+	v := header.apiVersion
+	groupId := d.string()
+	sessionTimeout := time.Duration(d.int32()) * time.Millisecond
+	rebalanceTimeout := sessionTimeout
+	if v >= 1 {
+		rebalanceTimeout = time.Duration(d.int32()) * time.Millisecond
+	}
+	memberId := d.string()
+	if v >= 5 {
+		d.nullableString() // group instance id
+	}
+	protocolType := d.string()
+	var protocols []groupProtocol
+	protocolCount := d.arrayLength()
+	for i := 0; i < protocolCount && d.err == nil; i++ {
+		protocols = append(protocols, groupProtocol{
+			name:     d.string(),
+			metadata: d.bytes(),
+		})
+	}
+	if d.err != nil {
+		return d.err
+	}
+
+	var result *joinResult
+	if len(protocols) == 0 {
+		result = &joinResult{errCode: errInconsistentGroupProtocol, memberId: memberId}
+	} else {
+		result = g.groups.joinGroup(groupId, memberId, header.clientId, protocolType, sessionTimeout, rebalanceTimeout, protocols)
+	}
+	if result.errCode != errNone {
+		result.generation = -1
+	}
+
+	if v >= 2 {
+		e.int32(0) // throttle time
+	}
+	e.int16(int16(result.errCode))
+	e.int32(result.generation)
+	e.string(result.protocol)
+	e.string(result.leader)
+	e.string(result.memberId)
+	e.arrayLength(len(result.members))
+	for _, member := range result.members {
+		e.string(member.memberId)
+		if v >= 5 {
+			e.nullableString(nil) // group instance id
+		}
+		e.bytes(member.metadata)
+	}
+	return nil
+}
+
+func (g *KafkaGateway) handleSyncGroup(header *requestHeader, d *decoder, e *encoder) error {
+	v := header.apiVersion
+	groupId := d.string()
+	generation := d.int32()
+	memberId := d.string()
+	if v >= 3 {
+		d.nullableString() // group instance id
+	}
+	assignments := make(map[string][]byte)
+	assignmentCount := d.arrayLength()
+	for i := 0; i < assignmentCount && d.err == nil; i++ {
+		assignmentMemberId := d.string()
+		assignments[assignmentMemberId] = d.bytes()
+	}
+	if d.err != nil {
+		return d.err
+	}
+
+	result := g.groups.syncGroup(groupId, memberId, generation, assignments)
+	if result.assignment == nil {
+		result.assignment = []byte{}
+	}
+
+	if v >= 1 {
+		e.int32(0) // throttle time
+	}
+	e.int16(int16(result.errCode))
+	e.bytes(result.assignment)
+	return nil
+}
+
+func (g *KafkaGateway) handleHeartbeat(header *requestHeader, d *decoder, e *encoder) error {
+	v := header.apiVersion
+	groupId := d.string()
+	generation := d.int32()
+	memberId := d.string()
+	if v >= 3 {
+		d.nullableString() // group instance id
+	}
+	if d.err != nil {
+		return d.err
+	}
+
+	errCode := g.groups.heartbeat(groupId, memberId, generation)
+
+	if v >= 1 {
+		e.int32(0) // throttle time
+	}
+	e.int16(int16(errCode))
+	return nil
+}
+
+func (g *KafkaGateway) handleLeaveGroup(header *requestHeader, d *decoder, e *encoder) error {
+	v := header.apiVersion
+	groupId := d.string()
+	var memberIds []string
+	if v >= 3 {
+		memberCount := d.arrayLength()
+		for i := 0; i < memberCount && d.err == nil; i++ {
+			memberIds = append(memberIds, d.string())
+			d.nullableString() // group instance id
+		}
+	} else {
+		memberIds = append(memberIds, d.string())
+	}
+	if d.err != nil {
+		return d.err
+	}
+
+	var errCodes []errorCode
+	for _, memberId := range memberIds {
+		errCodes = append(errCodes, g.groups.leaveGroup(groupId, memberId))
+	}
+
+	if v >= 1 {
+		e.int32(0) // throttle time
+	}
+	if v < 3 {
+		e.int16(int16(errCodes[0]))
+		return nil
+	}
+	e.int16(int16(errNone))
+	e.arrayLength(len(memberIds))
+	for i, memberId := range memberIds {
+		e.string(memberId)
+		e.nullableString(nil) // group instance id
+		e.int16(int16(errCodes[i]))
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package kafka_gateway
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+)
+
+const clusterId = "seaweedfs"
+
+func (g *KafkaGateway) handleApiVersions(header *requestHeader, d *decoder, e *encoder) error {
+	g.encodeApiVersions(e, header.apiVersion, errNone)
+	return nil
+}
+
+func (g *KafkaGateway) encodeApiVersions(e *encoder, apiVersion int16, errCode errorCode) {
+	apiKeys := make([]int16, 0, len(supportedApiVersions))
+	for apiKey := range supportedApiVersions {
+		apiKeys = append(apiKeys, apiKey)
+	}
+	sort.Slice(apiKeys, func(i, j int) bool { return apiKeys[i] < apiKeys[j] })
+
+	e.int16(int16(errCode))
+	e.arrayLength(len(apiKeys))
+	for _, apiKey := range apiKeys {
+		e.int16(apiKey)
+		e.int16(supportedApiVersions[apiKey].minVersion)
+		e.int16(supportedApiVersions[apiKey].maxVersion)
+	}
+	if apiVersion >= 1 {
+		e.int32(0) // throttle time
+	}
+}
+
+func (g *KafkaGateway) handleMetadata(header *requestHeader, d *decoder, e *encoder) error {
+	v := header.apiVersion
+	topicCount := d.arrayLength()
+	var names []string
+	for i := 0; i < topicCount && d.err == nil; i++ {
+		names = append(names, d.string())
+	}
+	// the older clients expect the topics to be created on first use
+	autoCreate := v < 4
+	if v >= 4 {
+		autoCreate = d.bool()
+	}
+	if v >= 8 {
+		d.bool() // include cluster authorized operations
+		d.bool() // include topic authorized operations
+	}
+	if d.err != nil {
+		return d.err
+	}
+
+	// all topics for a null array, or an empty array before v1
+	if topicCount < 0 || (v == 0 && topicCount == 0) {
+		var err error
+		if names, err = g.mq.ListTopics(g.option.Namespace); err != nil {
+			glog.V(0).Infof("list topics in %s: %v", g.option.Namespace, err)
+		}
+		autoCreate = false
+	}
+
+	if v >= 3 {
+		e.int32(0) // throttle time
+	}
+	e.arrayLength(1)
+	e.int32(gatewayNodeId)
+	e.string(g.option.Ip)
+	e.int32(int32(g.option.Port))
+	if v >= 1 {
+		e.nullableString(nil) // rack
+	}
+	if v >= 2 {
+		id := clusterId
+		e.nullableString(&id)
+	}
+	if v >= 1 {
+		e.int32(gatewayNodeId) // controller
+	}
+
+	e.arrayLength(len(names))
+	for _, name := range names {
+		assignments, errCode := g.lookupTopic(name, autoCreate)
+		e.int16(int16(errCode))
+		e.string(name)
+		if v >= 1 {
+			e.bool(false) // is internal
+		}
+		e.arrayLength(len(assignments))
+		for i := range assignments {
+			e.int16(int16(errNone))
+			e.int32(int32(i))
+			e.int32(gatewayNodeId) // leader
+			if v >= 7 {
+				e.int32(0) // leader epoch
+			}
+			e.int32Array([]int32{gatewayNodeId}) // replicas
+			e.int32Array([]int32{gatewayNodeId}) // in-sync replicas
+			if v >= 5 {
+				e.int32Array(nil) // offline replicas
+			}
+		}
+		if v >= 8 {
+			e.int32(math.MinInt32) // topic authorized operations
+		}
+	}
+	if v >= 8 {
+		e.int32(math.MinInt32) // cluster authorized operations
+	}
+	return nil
+}
+
+// handleFindCoordinator points to the gateway itself, which coordinates all the consumer groups
+func (g *KafkaGateway) handleFindCoordinator(header *requestHeader, d *decoder, e *encoder) error {
+	v := header.apiVersion
+	d.string() // key
+	var keyType int8
+	if v >= 1 {
+		keyType = d.int8()
+	}
+	if d.err != nil {
+		return d.err
+	}
+
+	errCode := errNone
+	var errMessage *string
+	if keyType != 0 {
+		// transaction coordinators are not supported
+		errCode = errCoordinatorNotAvailable
+		message := fmt.Sprintf("unsupported coordinator key type %d", keyType)
+		errMessage = &message
+	}
+
+	if v >= 1 {
+		e.int32(0) // throttle time
+	}
+	e.int16(int16(errCode))
+	if v >= 1 {
+		e.nullableString(errMessage)
+	}
+	e.int32(gatewayNodeId)
+	e.string(g.option.Ip)
+	e.int32(int32(g.option.Port))
+	return nil
+}
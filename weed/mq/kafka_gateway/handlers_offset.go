@@ -0,0 +1,180 @@
+package kafka_gateway
+
+import (
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+)
+
+// handleOffsetCommit saves the offsets in the partition directories on filer,
+// the same place as the consumer group offsets of the SeaweedMQ subscribers
+func (g *KafkaGateway) handleOffsetCommit(header *requestHeader, d *decoder, e *encoder) error {
+	v := header.apiVersion
+	groupId := d.string()
+	generation := d.int32()
+	memberId := d.string()
+	if v >= 7 {
+		d.nullableString() // group instance id
+	}
+	if v >= 2 && v <= 4 {
+		d.int64() // retention time
+	}
+
+	type commitPartition struct {
+		index  int32
+		offset int64
+	}
+	type commitTopic struct {
+		name       string
+		partitions []commitPartition
+	}
+	var topics []commitTopic
+	topicCount := d.arrayLength()
+	for i := 0; i < topicCount && d.err == nil; i++ {
+		t := commitTopic{name: d.string()}
+		partitionCount := d.arrayLength()
+		for j := 0; j < partitionCount && d.err == nil; j++ {
+			p := commitPartition{index: d.int32(), offset: d.int64()}
+			if v == 1 {
+				d.int64() // commit timestamp
+			}
+			if v >= 6 {
+				d.int32() // committed leader epoch
+			}
+			d.nullableString() // committed metadata
+			t.partitions = append(t.partitions, p)
+		}
+		topics = append(topics, t)
+	}
+	if d.err != nil {
+		return d.err
+	}
+
+	groupErrCode := g.groups.validateOffsetCommit(groupId, memberId, generation)
+
+	if v >= 3 {
+		e.int32(0) // throttle time
+	}
+	e.arrayLength(len(topics))
+	for _, t := range topics {
+		e.string(t.name)
+		e.arrayLength(len(t.partitions))
+		for _, p := range t.partitions {
+			errCode := groupErrCode
+			if errCode == errNone {
+				errCode = g.commitOffset(groupId, t.name, p.index, p.offset)
+			}
+			e.int32(p.index)
+			e.int16(int16(errCode))
+		}
+	}
+	return nil
+}
+
+func (g *KafkaGateway) commitOffset(groupId, name string, partition int32, offset int64) errorCode {
+	assignment, errCode := g.lookupPartition(name, partition)
+	if errCode != errNone {
+		return errCode
+	}
+	if err := g.mq.SaveConsumerGroupOffset(g.topic(name), assignment.Partition, groupId, offset); err != nil {
+		glog.V(0).Infof("commit offset of group %s on %s partition %d: %v", groupId, name, partition, err)
+		return errKafkaStorageError
+	}
+	return errNone
+}
+
+func (g *KafkaGateway) handleOffsetFetch(header *requestHeader, d *decoder, e *encoder) error {
+	v := header.apiVersion
+	groupId := d.string()
+
+	type fetchOffsetTopic struct {
+		name       string
+		partitions []int32
+	}
+	var topics []fetchOffsetTopic
+	topicCount := d.arrayLength()
+	for i := 0; i < topicCount && d.err == nil; i++ {
+		topics = append(topics, fetchOffsetTopic{
+			name:       d.string(),
+			partitions: d.int32Array(),
+		})
+	}
+	if d.err != nil {
+		return d.err
+	}
+
+	// all partitions of all topics for a null array, only listing the committed offsets
+	onlyCommitted := topicCount < 0
+	if onlyCommitted {
+		names, err := g.mq.ListTopics(g.option.Namespace)
+		if err != nil {
+			glog.V(0).Infof("list topics in %s: %v", g.option.Namespace, err)
+		}
+		for _, name := range names {
+			assignments, _ := g.lookupTopic(name, false)
+			t := fetchOffsetTopic{name: name}
+			for i := range assignments {
+				t.partitions = append(t.partitions, int32(i))
+			}
+			topics = append(topics, t)
+		}
+	}
+
+	type fetchedOffset struct {
+		index   int32
+		offset  int64
+		errCode errorCode
+	}
+	if v >= 3 {
+		e.int32(0) // throttle time
+	}
+	var topicsEncoder encoder
+	var encodedTopicCount int
+	for _, t := range topics {
+		var offsets []fetchedOffset
+		for _, index := range t.partitions {
+			offset, found, errCode := g.fetchOffset(groupId, t.name, index)
+			if onlyCommitted && !found {
+				continue
+			}
+			offsets = append(offsets, fetchedOffset{index: index, offset: offset, errCode: errCode})
+		}
+		if onlyCommitted && len(offsets) == 0 {
+			continue
+		}
+		encodedTopicCount++
+		topicsEncoder.string(t.name)
+		topicsEncoder.arrayLength(len(offsets))
+		for _, o := range offsets {
+			topicsEncoder.int32(o.index)
+			topicsEncoder.int64(o.offset)
+			if v >= 5 {
+				topicsEncoder.int32(-1) // committed leader epoch
+			}
+			empty := ""
+			topicsEncoder.nullableString(&empty) // metadata
+			topicsEncoder.int16(int16(o.errCode))
+		}
+	}
+	e.arrayLength(encodedTopicCount)
+	e.buf = append(e.buf, topicsEncoder.buf...)
+	if v >= 2 {
+		e.int16(int16(errNone))
+	}
+	return nil
+}
+
+// fetchOffset reads the committed offset, which is -1 if none is committed
+func (g *KafkaGateway) fetchOffset(groupId, name string, partition int32) (offset int64, found bool, errCode errorCode) {
+	assignment, errCode := g.lookupPartition(name, partition)
+	if errCode != errNone {
+		return -1, false, errCode
+	}
+	offset, found, err := g.mq.ReadConsumerGroupOffset(g.topic(name), assignment.Partition, groupId)
+	if err != nil {
+		glog.V(0).Infof("fetch offset of group %s on %s partition %d: %v", groupId, name, partition, err)
+		return -1, false, errKafkaStorageError
+	}
+	if !found {
+		return -1, false, errNone
+	}
+	return offset, true, errNone
+}
@@ -0,0 +1,104 @@
+package kafka_gateway
+
+import (
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/pb/mq_pb"
+)
+
+type producePartitionResult struct {
+	index      int32
+	errCode    errorCode
+	baseOffset int64
+}
+
+type produceTopicResult struct {
+	name       string
+	partitions []producePartitionResult
+}
+
+// handleProduce publishes the records to the partitions.
+// With acks=-1 the response waits for the broker acks, with acks=1 only for sending, and with acks=0 there is no response.
+func (g *KafkaGateway) handleProduce(header *requestHeader, d *decoder, e *encoder) (respond bool, err error) {
+	v := header.apiVersion
+	d.nullableString() // transactional id
+	acks := d.int16()
+	d.int32() // timeout
+
+	var results []produceTopicResult
+	topicCount := d.arrayLength()
+	for i := 0; i < topicCount && d.err == nil; i++ {
+		topicResult := produceTopicResult{name: d.string()}
+		partitionCount := d.arrayLength()
+		for j := 0; j < partitionCount && d.err == nil; j++ {
+			index := d.int32()
+			data := d.bytes()
+			if d.err != nil {
+				break
+			}
+			result := producePartitionResult{index: index, baseOffset: -1}
+			if acks != 0 && acks != 1 && acks != -1 {
+				result.errCode = errInvalidRequiredAcks
+			} else {
+				result.baseOffset, result.errCode = g.produce(topicResult.name, index, data, acks == -1)
+			}
+			topicResult.partitions = append(topicResult.partitions, result)
+		}
+		results = append(results, topicResult)
+	}
+	if d.err != nil {
+		return false, d.err
+	}
+	if acks == 0 {
+		return false, nil
+	}
+
+	e.arrayLength(len(results))
+	for _, topicResult := range results {
+		e.string(topicResult.name)
+		e.arrayLength(len(topicResult.partitions))
+		for _, result := range topicResult.partitions {
+			e.int32(result.index)
+			e.int16(int16(result.errCode))
+			e.int64(result.baseOffset)
+			e.int64(-1) // log append time
+			if v >= 5 {
+				e.int64(0) // log start offset
+			}
+			if v >= 8 {
+				e.arrayLength(0)      // record errors
+				e.nullableString(nil) // error message
+			}
+		}
+	}
+	e.int32(0) // throttle time
+	return true, nil
+}
+
+func (g *KafkaGateway) produce(name string, partition int32, data []byte, waitForAck bool) (baseOffset int64, errCode errorCode) {
+	assignment, errCode := g.lookupPartition(name, partition)
+	if errCode != errNone {
+		return -1, errCode
+	}
+	records, errCode, err := decodeRecordBatches(data)
+	if err != nil {
+		glog.V(0).Infof("produce to %s partition %d: %v", name, partition, err)
+		return -1, errCode
+	}
+	if len(records) == 0 {
+		return -1, errNone
+	}
+
+	messages := make([]*mq_pb.DataMessage, 0, len(records))
+	for _, r := range records {
+		messages = append(messages, &mq_pb.DataMessage{
+			Key:   r.key,
+			Value: r.value,
+		})
+	}
+	if err = g.mq.Publish(g.topic(name), assignment, messages, waitForAck); err != nil {
+		glog.V(0).Infof("publish to %s partition %d: %v", name, partition, err)
+		g.invalidateTopic(name)
+		return -1, errKafkaStorageError
+	}
+	return messages[0].TsNs, errNone
+}
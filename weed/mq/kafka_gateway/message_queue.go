@@ -0,0 +1,530 @@
+package kafka_gateway
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/seaweedfs/seaweedfs/weed/filer"
+	"github.com/seaweedfs/seaweedfs/weed/filer_client"
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/mq/topic"
+	"github.com/seaweedfs/seaweedfs/weed/pb"
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/pb/mq_pb"
+	"github.com/seaweedfs/seaweedfs/weed/util"
+	"google.golang.org/grpc"
+)
+
+// messageQueue is how the gateway reaches the topics, partitions and consumer group offsets of SeaweedMQ
+type messageQueue interface {
+	// ListTopics lists the topic names in the namespace
+	ListTopics(namespace string) ([]string, error)
+	// LookupTopic returns the partition assignments of the topic, or nil if the topic does not exist
+	LookupTopic(t topic.Topic) ([]*mq_pb.BrokerPartitionAssignment, error)
+	// ConfigureTopic creates the topic with the partition count
+	ConfigureTopic(t topic.Topic, partitionCount int32) ([]*mq_pb.BrokerPartitionAssignment, error)
+	// Publish sends the messages to the leader broker of the partition, and waits for the broker ack if asked.
+	// The message timestamps are assigned in the order of sending, and are the kafka offsets of the messages.
+	Publish(t topic.Topic, assignment *mq_pb.BrokerPartitionAssignment, messages []*mq_pb.DataMessage, waitForAck bool) error
+	// Read returns the messages of the partition from startTsNs which are already received, without blocking.
+	// The messages are received in the background since the first read, and the next read continues after the returned messages.
+	Read(t topic.Topic, assignment *mq_pb.BrokerPartitionAssignment, startTsNs int64, maxBytes int) ([]*mq_pb.DataMessage, error)
+	// ReadConsumerGroupOffset reads the offset saved for the consumer group, found is false if none is saved
+	ReadConsumerGroupOffset(t topic.Topic, partition *mq_pb.Partition, consumerGroup string) (offset int64, found bool, err error)
+	// SaveConsumerGroupOffset saves the offset of the consumer group
+	SaveConsumerGroupOffset(t topic.Topic, partition *mq_pb.Partition, consumerGroup string, offset int64) error
+}
+
+const (
+	// the consumer group of the subscriptions reading for the kafka fetch requests
+	gatewayConsumerGroup = "kafka_gateway"
+	publishAckTimeout    = 30 * time.Second
+	idleStreamTimeout    = 2 * time.Minute
+)
+
+// brokerMessageQueue reaches the brokers by gRPC, and the consumer group offsets on the filer
+type brokerMessageQueue struct {
+	brokers        []pb.ServerAddress
+	grpcDialOption grpc.DialOption
+	fca            *filer_client.FilerClientAccessor
+	clientName     string
+
+	publishersLock sync.Mutex
+	publishers     map[string]*partitionPublisher
+	readersLock    sync.Mutex
+	readers        map[string][]*partitionReader
+}
+
+func newBrokerMessageQueue(brokers []pb.ServerAddress, filerAddress pb.ServerAddress, grpcDialOption grpc.DialOption, clientName string) *brokerMessageQueue {
+	q := &brokerMessageQueue{
+		brokers:        brokers,
+		grpcDialOption: grpcDialOption,
+		fca: &filer_client.FilerClientAccessor{
+			GetFiler:          func() pb.ServerAddress { return filerAddress },
+			GetGrpcDialOption: func() grpc.DialOption { return grpcDialOption },
+		},
+		clientName: clientName,
+		publishers: make(map[string]*partitionPublisher),
+		readers:    make(map[string][]*partitionReader),
+	}
+	go q.loopClosingIdleStreams()
+	return q
+}
+
+// withBrokerClient tries the brokers one by one, since any broker can serve the topic configuration
+func (q *brokerMessageQueue) withBrokerClient(fn func(client mq_pb.SeaweedMessagingClient) error) (err error) {
+	if len(q.brokers) == 0 {
+		return fmt.Errorf("no brokers")
+	}
+	for _, broker := range q.brokers {
+		if err = pb.WithBrokerGrpcClient(false, broker.String(), q.grpcDialOption, fn); err == nil {
+			return nil
+		}
+		glog.V(1).Infof("broker %s: %v", broker, err)
+	}
+	return err
+}
+
+func (q *brokerMessageQueue) ListTopics(namespace string) (names []string, err error) {
+	err = q.fca.WithFilerClient(false, func(client filer_pb.SeaweedFilerClient) error {
+		return filer_pb.SeaweedList(client, fmt.Sprintf("%s/%s", filer.TopicsDir, namespace), "", func(entry *filer_pb.Entry, isLast bool) error {
+			if entry.IsDirectory {
+				names = append(names, entry.Name)
+			}
+			return nil
+		}, "", false, 0)
+	})
+	if err == filer_pb.ErrNotFound {
+		return nil, nil
+	}
+	return
+}
+
+func (q *brokerMessageQueue) LookupTopic(t topic.Topic) ([]*mq_pb.BrokerPartitionAssignment, error) {
+	if _, err := q.fca.ReadTopicConfFromFiler(t); err == filer_pb.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var assignments []*mq_pb.BrokerPartitionAssignment
+	err := q.withBrokerClient(func(client mq_pb.SeaweedMessagingClient) error {
+		resp, err := client.LookupTopicBrokers(context.Background(), &mq_pb.LookupTopicBrokersRequest{
+			Topic: t.ToPbTopic(),
+		})
+		if err != nil {
+			return err
+		}
+		assignments = resp.BrokerPartitionAssignments
+		return nil
+	})
+	return assignments, err
+}
+
+func (q *brokerMessageQueue) ConfigureTopic(t topic.Topic, partitionCount int32) ([]*mq_pb.BrokerPartitionAssignment, error) {
+	var assignments []*mq_pb.BrokerPartitionAssignment
+	err := q.withBrokerClient(func(client mq_pb.SeaweedMessagingClient) error {
+		resp, err := client.ConfigureTopic(context.Background(), &mq_pb.ConfigureTopicRequest{
+			Topic:          t.ToPbTopic(),
+			PartitionCount: partitionCount,
+		})
+		if err != nil {
+			return err
+		}
+		assignments = resp.BrokerPartitionAssignments
+		return nil
+	})
+	return assignments, err
+}
+
+func (q *brokerMessageQueue) ReadConsumerGroupOffset(t topic.Topic, partition *mq_pb.Partition, consumerGroup string) (offset int64, found bool, err error) {
+	err = q.fca.WithFilerClient(false, func(client filer_pb.SeaweedFilerClient) error {
+		data, err := filer.ReadInsideFiler(client, partitionDir(t, partition), consumerGroup+".offset")
+		if err != nil {
+			return err
+		}
+		if len(data) != 8 {
+			return fmt.Errorf("invalid offset of %d bytes", len(data))
+		}
+		offset, found = int64(util.BytesToUint64(data)), true
+		return nil
+	})
+	if err == filer_pb.ErrNotFound {
+		return 0, false, nil
+	}
+	return
+}
+
+func (q *brokerMessageQueue) SaveConsumerGroupOffset(t topic.Topic, partition *mq_pb.Partition, consumerGroup string, offset int64) error {
+	offsetBytes := make([]byte, 8)
+	util.Uint64toBytes(offsetBytes, uint64(offset))
+	return q.fca.WithFilerClient(false, func(client filer_pb.SeaweedFilerClient) error {
+		return filer.SaveInsideFiler(client, partitionDir(t, partition), consumerGroup+".offset", offsetBytes)
+	})
+}
+
+// partitionDir is the directory of the partition on filer, with the consumer group offsets as used by the brokers
+func partitionDir(t topic.Topic, partition *mq_pb.Partition) string {
+	partitionGeneration := time.Unix(0, partition.UnixTimeNs).UTC().Format(topic.TIME_FORMAT)
+	return fmt.Sprintf("%s/%s/%s/%s/%04d-%04d", filer.TopicsDir, t.Namespace, t.Name, partitionGeneration, partition.RangeStart, partition.RangeStop)
+}
+
+func partitionKey(t topic.Topic, assignment *mq_pb.BrokerPartitionAssignment) string {
+	p := assignment.Partition
+	return fmt.Sprintf("%s/%d/%04d-%04d@%s", t, p.UnixTimeNs, p.RangeStart, p.RangeStop, assignment.LeaderBroker)
+}
+
+// partitionPublisher keeps a publish stream open to the leader broker of a partition
+type partitionPublisher struct {
+	sendLock  sync.Mutex
+	lastTsNs  int64
+	stream    mq_pb.SeaweedMessaging_PublishMessageClient
+	cancel    context.CancelFunc
+	ackLock   sync.Mutex
+	ackCond   *sync.Cond
+	ackedTsNs int64
+	err       error
+	lastUsed  time.Time
+}
+
+func (q *brokerMessageQueue) Publish(t topic.Topic, assignment *mq_pb.BrokerPartitionAssignment, messages []*mq_pb.DataMessage, waitForAck bool) error {
+	key := partitionKey(t, assignment)
+	q.publishersLock.Lock()
+	publisher, found := q.publishers[key]
+	if !found {
+		var err error
+		if publisher, err = q.newPartitionPublisher(t, assignment); err != nil {
+			q.publishersLock.Unlock()
+			return err
+		}
+		q.publishers[key] = publisher
+	}
+	publisher.lastUsed = time.Now()
+	q.publishersLock.Unlock()
+
+	err := publisher.publish(messages, waitForAck)
+	if err != nil {
+		q.publishersLock.Lock()
+		if q.publishers[key] == publisher {
+			delete(q.publishers, key)
+		}
+		q.publishersLock.Unlock()
+		publisher.cancel()
+	}
+	return err
+}
+
+func (q *brokerMessageQueue) newPartitionPublisher(t topic.Topic, assignment *mq_pb.BrokerPartitionAssignment) (*partitionPublisher, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	grpcConnection, err := pb.GrpcDial(ctx, assignment.LeaderBroker, true, q.grpcDialOption)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("dial broker %s: %v", assignment.LeaderBroker, err)
+	}
+	stream, err := mq_pb.NewSeaweedMessagingClient(grpcConnection).PublishMessage(ctx)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("publish to %s: %v", assignment.LeaderBroker, err)
+	}
+	if err = stream.Send(&mq_pb.PublishMessageRequest{
+		Message: &mq_pb.PublishMessageRequest_Init{
+			Init: &mq_pb.PublishMessageRequest_InitMessage{
+				Topic:          t.ToPbTopic(),
+				Partition:      assignment.Partition,
+				AckInterval:    1,
+				FollowerBroker: assignment.FollowerBroker,
+				PublisherName:  q.clientName,
+			},
+		},
+	}); err != nil {
+		cancel()
+		return nil, fmt.Errorf("send init to %s: %v", assignment.LeaderBroker, err)
+	}
+	// the hello message
+	resp, err := stream.Recv()
+	if err == nil && resp.Error != "" {
+		err = fmt.Errorf("%s", resp.Error)
+	}
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("init publish to %s: %v", assignment.LeaderBroker, err)
+	}
+
+	publisher := &partitionPublisher{
+		stream: stream,
+		cancel: cancel,
+	}
+	publisher.ackCond = sync.NewCond(&publisher.ackLock)
+	go publisher.receiveAcks()
+	return publisher, nil
+}
+
+func (p *partitionPublisher) receiveAcks() {
+	for {
+		resp, err := p.stream.Recv()
+		if err == nil && resp.Error != "" {
+			err = fmt.Errorf("%s", resp.Error)
+		}
+		p.ackLock.Lock()
+		if err != nil {
+			p.err = err
+		} else if resp.AckSequence > p.ackedTsNs {
+			p.ackedTsNs = resp.AckSequence
+		}
+		p.ackCond.Broadcast()
+		p.ackLock.Unlock()
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (p *partitionPublisher) publish(messages []*mq_pb.DataMessage, waitForAck bool) error {
+	p.sendLock.Lock()
+	for _, message := range messages {
+		message.TsNs = max(time.Now().UnixNano(), p.lastTsNs+1)
+		p.lastTsNs = message.TsNs
+		if err := p.stream.Send(&mq_pb.PublishMessageRequest{
+			Message: &mq_pb.PublishMessageRequest_Data{
+				Data: message,
+			},
+		}); err != nil {
+			p.sendLock.Unlock()
+			return fmt.Errorf("send message: %v", err)
+		}
+	}
+	p.sendLock.Unlock()
+	if !waitForAck || len(messages) == 0 {
+		return nil
+	}
+
+	lastTsNs := messages[len(messages)-1].TsNs
+	var timedOut bool
+	timer := time.AfterFunc(publishAckTimeout, func() {
+		p.ackLock.Lock()
+		timedOut = true
+		p.ackCond.Broadcast()
+		p.ackLock.Unlock()
+	})
+	defer timer.Stop()
+
+	p.ackLock.Lock()
+	defer p.ackLock.Unlock()
+	for p.ackedTsNs < lastTsNs && p.err == nil && !timedOut {
+		p.ackCond.Wait()
+	}
+	if p.err != nil {
+		return p.err
+	}
+	if timedOut {
+		return fmt.Errorf("wait for ack: timed out")
+	}
+	return nil
+}
+
+// partitionReader subscribes to the leader broker of a partition, and buffers the received messages
+type partitionReader struct {
+	nextTsNs int64
+	messages chan *mq_pb.DataMessage
+	pending  *mq_pb.DataMessage
+	cancel   context.CancelFunc
+	errLock  sync.Mutex
+	err      error
+	lastUsed time.Time
+}
+
+func (q *brokerMessageQueue) Read(t topic.Topic, assignment *mq_pb.BrokerPartitionAssignment, startTsNs int64, maxBytes int) (messages []*mq_pb.DataMessage, err error) {
+	key := partitionKey(t, assignment)
+
+	q.readersLock.Lock()
+	defer q.readersLock.Unlock()
+
+	var reader *partitionReader
+	for _, r := range q.readers[key] {
+		if r.nextTsNs == startTsNs {
+			reader = r
+			break
+		}
+	}
+	if reader == nil {
+		reader = q.newPartitionReader(t, assignment, startTsNs)
+		q.readers[key] = append(q.readers[key], reader)
+	}
+	reader.lastUsed = time.Now()
+
+	var size int
+	for {
+		message := reader.pending
+		reader.pending = nil
+		if message == nil {
+			select {
+			case message = <-reader.messages:
+			default:
+			}
+		}
+		if message == nil {
+			break
+		}
+		if message.TsNs < reader.nextTsNs {
+			continue
+		}
+		if len(messages) > 0 && size+messageSize(message) > maxBytes {
+			reader.pending = message
+			break
+		}
+		messages = append(messages, message)
+		size += messageSize(message)
+		reader.nextTsNs = message.TsNs + 1
+	}
+
+	if len(messages) == 0 {
+		reader.errLock.Lock()
+		err = reader.err
+		reader.errLock.Unlock()
+		if err != nil {
+			q.removeReader(key, reader)
+		}
+	}
+	return messages, err
+}
+
+func messageSize(message *mq_pb.DataMessage) int {
+	// roughly the size of the record in the record batch
+	return len(message.Key) + len(message.Value) + 16
+}
+
+func (q *brokerMessageQueue) newPartitionReader(t topic.Topic, assignment *mq_pb.BrokerPartitionAssignment, startTsNs int64) *partitionReader {
+	ctx, cancel := context.WithCancel(context.Background())
+	reader := &partitionReader{
+		nextTsNs: startTsNs,
+		messages: make(chan *mq_pb.DataMessage, 1024),
+		cancel:   cancel,
+	}
+	go func() {
+		err := q.subscribe(ctx, t, assignment, startTsNs, reader.messages)
+		if err == nil {
+			err = fmt.Errorf("subscription closed")
+		}
+		if ctx.Err() == nil {
+			glog.V(0).Infof("read %s from %d: %v", partitionKey(t, assignment), startTsNs, err)
+		}
+		reader.errLock.Lock()
+		reader.err = err
+		reader.errLock.Unlock()
+	}()
+	return reader
+}
+
+func (q *brokerMessageQueue) subscribe(ctx context.Context, t topic.Topic, assignment *mq_pb.BrokerPartitionAssignment, startTsNs int64, messages chan *mq_pb.DataMessage) error {
+	grpcConnection, err := pb.GrpcDial(ctx, assignment.LeaderBroker, true, q.grpcDialOption)
+	if err != nil {
+		return fmt.Errorf("dial broker %s: %v", assignment.LeaderBroker, err)
+	}
+	stream, err := mq_pb.NewSeaweedMessagingClient(grpcConnection).SubscribeMessage(ctx)
+	if err != nil {
+		return fmt.Errorf("subscribe to %s: %v", assignment.LeaderBroker, err)
+	}
+	if err = stream.Send(&mq_pb.SubscribeMessageRequest{
+		Message: &mq_pb.SubscribeMessageRequest_Init{
+			Init: &mq_pb.SubscribeMessageRequest_InitMessage{
+				ConsumerGroup: gatewayConsumerGroup,
+				ConsumerId:    q.clientName,
+				ClientId:      q.clientName,
+				Topic:         t.ToPbTopic(),
+				PartitionOffset: &mq_pb.PartitionOffset{
+					Partition: assignment.Partition,
+					StartTsNs: startTsNs,
+				},
+				Concurrency: 1024,
+			},
+		},
+	}); err != nil {
+		return fmt.Errorf("send init to %s: %v", assignment.LeaderBroker, err)
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		if ctrl := resp.GetCtrl(); ctrl != nil {
+			if ctrl.Error != "" {
+				return fmt.Errorf("%s", ctrl.Error)
+			}
+			if ctrl.IsEndOfStream || ctrl.IsEndOfTopic {
+				return nil
+			}
+			continue
+		}
+		data := resp.GetData()
+		if data == nil {
+			continue
+		}
+		// the broker holds back the next message of the same key until acknowledged
+		if err = stream.Send(&mq_pb.SubscribeMessageRequest{
+			Message: &mq_pb.SubscribeMessageRequest_Ack{
+				Ack: &mq_pb.SubscribeMessageRequest_AckMessage{
+					Key:      data.Key,
+					Sequence: data.TsNs,
+				},
+			},
+		}); err != nil {
+			return fmt.Errorf("ack: %v", err)
+		}
+		select {
+		case messages <- data:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (q *brokerMessageQueue) removeReader(key string, reader *partitionReader) {
+	readers := q.readers[key]
+	for i, r := range readers {
+		if r == reader {
+			q.readers[key] = append(readers[:i], readers[i+1:]...)
+			break
+		}
+	}
+	if len(q.readers[key]) == 0 {
+		delete(q.readers, key)
+	}
+	reader.cancel()
+}
+
+// loopClosingIdleStreams closes the publish and subscribe streams not used recently
+func (q *brokerMessageQueue) loopClosingIdleStreams() {
+	for {
+		time.Sleep(idleStreamTimeout / 2)
+		cutoff := time.Now().Add(-idleStreamTimeout)
+
+		q.readersLock.Lock()
+		for key, readers := range q.readers {
+			for _, reader := range append([]*partitionReader(nil), readers...) {
+				if reader.lastUsed.Before(cutoff) {
+					q.removeReader(key, reader)
+				}
+			}
+		}
+		q.readersLock.Unlock()
+
+		q.publishersLock.Lock()
+		for key, publisher := range q.publishers {
+			if publisher.lastUsed.Before(cutoff) {
+				delete(q.publishers, key)
+				publisher.cancel()
+			}
+		}
+		q.publishersLock.Unlock()
+	}
+}
+
+// sortAssignments orders the partitions by range, the index of a partition is its kafka partition id
+func sortAssignments(assignments []*mq_pb.BrokerPartitionAssignment) {
+	sort.Slice(assignments, func(i, j int) bool {
+		return assignments[i].Partition.RangeStart < assignments[j].Partition.RangeStart
+	})
+}
@@ -0,0 +1,82 @@
+package kafka_gateway
+
+// the kafka api keys served by the gateway
+// https://kafka.apache.org/protocol#protocol_api_keys
+const (
+	apiKeyProduce         int16 = 0
+	apiKeyFetch           int16 = 1
+	apiKeyListOffsets     int16 = 2
+	apiKeyMetadata        int16 = 3
+	apiKeyOffsetCommit    int16 = 8
+	apiKeyOffsetFetch     int16 = 9
+	apiKeyFindCoordinator int16 = 10
+	apiKeyJoinGroup       int16 = 11
+	apiKeyHeartbeat       int16 = 12
+	apiKeyLeaveGroup      int16 = 13
+	apiKeySyncGroup       int16 = 14
+	apiKeyApiVersions     int16 = 18
+)
+
+type apiVersionRange struct {
+	minVersion int16
+	maxVersion int16
+}
+
+// supportedApiVersions are the versions before the flexible versions of KIP-482,
+// which the clients negotiate down to by the ApiVersions response.
+// Produce and Fetch start from the versions using the v2 record batches.
+var supportedApiVersions = map[int16]apiVersionRange{
+	apiKeyProduce:         {3, 8},
+	apiKeyFetch:           {4, 11},
+	apiKeyListOffsets:     {1, 5},
+	apiKeyMetadata:        {0, 8},
+	apiKeyOffsetCommit:    {1, 7},
+	apiKeyOffsetFetch:     {1, 5},
+	apiKeyFindCoordinator: {0, 2},
+	apiKeyJoinGroup:       {0, 5},
+	apiKeyHeartbeat:       {0, 3},
+	apiKeyLeaveGroup:      {0, 3},
+	apiKeySyncGroup:       {0, 3},
+	apiKeyApiVersions:     {0, 2},
+}
+
+func isSupportedApiVersion(apiKey, apiVersion int16) bool {
+	versionRange, found := supportedApiVersions[apiKey]
+	return found && versionRange.minVersion <= apiVersion && apiVersion <= versionRange.maxVersion
+}
+
+// kafka error codes
+// https://kafka.apache.org/protocol#protocol_error_codes
+type errorCode int16
+
+const (
+	errNone                        errorCode = 0
+	errUnknownServerError          errorCode = -1
+	errCorruptMessage              errorCode = 2
+	errUnknownTopicOrPartition     errorCode = 3
+	errLeaderNotAvailable          errorCode = 5
+	errRequestTimedOut             errorCode = 7
+	errMessageTooLarge             errorCode = 10
+	errCoordinatorNotAvailable     errorCode = 15
+	errInvalidTopic                errorCode = 17
+	errInvalidRequiredAcks         errorCode = 21
+	errIllegalGeneration           errorCode = 22
+	errInconsistentGroupProtocol   errorCode = 23
+	errInvalidGroupId              errorCode = 24
+	errUnknownMemberId             errorCode = 25
+	errInvalidSessionTimeout       errorCode = 26
+	errRebalanceInProgress         errorCode = 27
+	errUnsupportedVersion          errorCode = 35
+	errUnsupportedForMessageFormat errorCode = 43
+	errKafkaStorageError           errorCode = 56
+	errUnsupportedCompression      errorCode = 76
+)
+
+// the special timestamps of ListOffsets
+const (
+	listOffsetsLatest   int64 = -1
+	listOffsetsEarliest int64 = -2
+)
+
+// the gateway is the only kafka broker the clients see
+const gatewayNodeId int32 = 0
@@ -0,0 +1,259 @@
+package kafka_gateway
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+
+	snappy "github.com/eapache/go-xerial-snappy"
+	rawsnappy "github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+)
+
+// record is a kafka record, decoded from or encoded into the v2 record batches
+// https://kafka.apache.org/documentation/#recordbatch
+type record struct {
+	offset    int64
+	timestamp int64 // in milliseconds
+	key       []byte
+	value     []byte
+}
+
+const (
+	recordBatchMagic       = 2
+	recordBatchHeaderSize  = 61
+	recordBatchCrcOffset   = 17
+	recordBatchCodecMask   = 0x07
+	recordBatchControlFlag = 0x20
+	// the records are compressed by the clients, so their decompressed size in the batches of a produce request is limited
+	maxRecordBatchSize = maxRequestSize
+
+	codecNone   = 0
+	codecGzip   = 1
+	codecSnappy = 2
+	codecLz4    = 3
+	codecZstd   = 4
+)
+
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
+var errRecordBatchTooLarge = errors.New("record batch too large")
+
+// decodeRecordBatches decodes the records in the record batches of a produce request.
+// The record headers are dropped, since the messages of SeaweedMQ only have a key and a value.
+func decodeRecordBatches(data []byte) (records []record, errCode errorCode, err error) {
+	decompressBudget := maxRecordBatchSize
+	for len(data) > 0 {
+		if len(data) < recordBatchHeaderSize {
+			return nil, errCorruptMessage, fmt.Errorf("record batch of %d bytes", len(data))
+		}
+		batchLength := int(int32(binary.BigEndian.Uint32(data[8:12])))
+		if batchLength < recordBatchHeaderSize-12 || 12+batchLength > len(data) {
+			return nil, errCorruptMessage, fmt.Errorf("record batch length %d of %d bytes", batchLength, len(data))
+		}
+		batch := data[:12+batchLength]
+		data = data[12+batchLength:]
+
+		if magic := int8(batch[16]); magic != recordBatchMagic {
+			return nil, errUnsupportedForMessageFormat, fmt.Errorf("record batch magic %d", magic)
+		}
+		if crc := binary.BigEndian.Uint32(batch[recordBatchCrcOffset:]); crc != crc32.Checksum(batch[recordBatchCrcOffset+4:], crc32cTable) {
+			return nil, errCorruptMessage, fmt.Errorf("record batch crc mismatch")
+		}
+
+		d := newDecoder(batch)
+		baseOffset := d.int64()
+		d.next(4 + 4 + 1 + 4) // batch length, partition leader epoch, magic, crc
+		attributes := d.int16()
+		d.int32() // last offset delta
+		baseTimestamp := d.int64()
+		d.next(8 + 8 + 2 + 4) // max timestamp, producer id, producer epoch, base sequence
+		recordCount := int(d.int32())
+
+		if attributes&recordBatchControlFlag != 0 {
+			// transaction markers
+			continue
+		}
+
+		codec := int(attributes & recordBatchCodecMask)
+		recordsData, decompressErr := decompressRecords(codec, batch[recordBatchHeaderSize:], decompressBudget)
+		if errors.Is(decompressErr, errRecordBatchTooLarge) {
+			return nil, errMessageTooLarge, decompressErr
+		}
+		if decompressErr != nil {
+			return nil, errUnsupportedCompression, decompressErr
+		}
+		if codec != codecNone {
+			decompressBudget -= len(recordsData)
+		}
+
+		rd := newDecoder(recordsData)
+		for i := 0; i < recordCount; i++ {
+			length := rd.varint()
+			if rd.err != nil || length < 0 || int(length) > rd.remaining() {
+				return nil, errCorruptMessage, fmt.Errorf("record %d length %d: %v", i, length, rd.err)
+			}
+			r := newDecoder(rd.next(int(length)))
+			r.int8() // attributes
+			timestampDelta := r.varint()
+			offsetDelta := r.varint()
+			key := r.varintBytes()
+			value := r.varintBytes()
+			headerCount := r.varint()
+			for j := int64(0); j < headerCount && r.err == nil; j++ {
+				r.varintBytes()
+				r.varintBytes()
+			}
+			if r.err != nil {
+				return nil, errCorruptMessage, fmt.Errorf("record %d: %v", i, r.err)
+			}
+			records = append(records, record{
+				offset:    baseOffset + offsetDelta,
+				timestamp: baseTimestamp + timestampDelta,
+				key:       key,
+				value:     value,
+			})
+		}
+	}
+	return records, errNone, nil
+}
+
+// decompressRecords decompresses the records of a batch, up to maxSize bytes
+func decompressRecords(codec int, data []byte, maxSize int) ([]byte, error) {
+	switch codec {
+	case codecNone:
+		return data, nil
+	case codecGzip:
+		reader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("gzip: %v", err)
+		}
+		defer reader.Close()
+		return readAllLimited(reader, maxSize)
+	case codecSnappy:
+		size, err := snappyDecodedLen(data)
+		if err != nil {
+			return nil, fmt.Errorf("snappy: %v", err)
+		}
+		if size > maxSize {
+			return nil, fmt.Errorf("snappy %d bytes: %w", size, errRecordBatchTooLarge)
+		}
+		return snappy.Decode(data)
+	case codecLz4:
+		return readAllLimited(lz4.NewReader(bytes.NewReader(data)), maxSize)
+	case codecZstd:
+		reader, err := zstd.NewReader(nil, zstd.WithDecoderMaxMemory(uint64(maxSize)))
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+		decoded, err := reader.DecodeAll(data, nil)
+		if errors.Is(err, zstd.ErrDecoderSizeExceeded) || errors.Is(err, zstd.ErrWindowSizeExceeded) {
+			return nil, fmt.Errorf("zstd: %w", errRecordBatchTooLarge)
+		}
+		return decoded, err
+	}
+	return nil, fmt.Errorf("unsupported compression codec %d", codec)
+}
+
+func readAllLimited(reader io.Reader, maxSize int) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(reader, int64(maxSize)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxSize {
+		return nil, errRecordBatchTooLarge
+	}
+	return data, nil
+}
+
+// xerialHeader starts the snappy data framed by the java clients, followed by the version and the chunks
+var xerialHeader = []byte{130, 83, 78, 65, 80, 80, 89, 0}
+
+// snappyDecodedLen adds up the decoded lengths recorded in the snappy data, before anything is allocated for it
+func snappyDecodedLen(data []byte) (int, error) {
+	if !bytes.HasPrefix(data, xerialHeader) {
+		return rawsnappy.DecodedLen(data)
+	}
+	total := 0
+	for pos := 16; pos < len(data); {
+		if pos+4 > len(data) {
+			return 0, fmt.Errorf("truncated xerial chunk at %d", pos)
+		}
+		size := int(binary.BigEndian.Uint32(data[pos:]))
+		pos += 4
+		if size < 0 || size > len(data)-pos {
+			return 0, fmt.Errorf("xerial chunk of %d bytes at %d", size, pos)
+		}
+		n, err := rawsnappy.DecodedLen(data[pos : pos+size])
+		if err != nil {
+			return 0, err
+		}
+		total += n
+		pos += size
+	}
+	return total, nil
+}
+
+// encodeRecordBatches encodes the records, sorted by offset, into uncompressed record batches.
+// A new batch is started when the offset delta does not fit in the batch.
+func encodeRecordBatches(records []record) []byte {
+	var buf []byte
+	for len(records) > 0 {
+		n := 1
+		for n < len(records) && records[n].offset-records[0].offset <= math.MaxInt32 {
+			n++
+		}
+		buf = appendRecordBatch(buf, records[:n])
+		records = records[n:]
+	}
+	return buf
+}
+
+func appendRecordBatch(buf []byte, records []record) []byte {
+	first, last := records[0], records[len(records)-1]
+	maxTimestamp := first.timestamp
+	for _, r := range records {
+		maxTimestamp = max(maxTimestamp, r.timestamp)
+	}
+
+	e := &encoder{buf: buf}
+	start := len(e.buf)
+	e.int64(first.offset)
+	e.int32(0) // batch length, set below
+	e.int32(0) // partition leader epoch
+	e.int8(recordBatchMagic)
+	e.int32(0) // crc, set below
+	e.int16(codecNone)
+	e.int32(int32(last.offset - first.offset))
+	e.int64(first.timestamp)
+	e.int64(maxTimestamp)
+	e.int64(-1) // producer id
+	e.int16(-1) // producer epoch
+	e.int32(-1) // base sequence
+	e.int32(int32(len(records)))
+
+	body := &encoder{}
+	for _, r := range records {
+		body.buf = body.buf[:0]
+		body.int8(0) // attributes
+		body.varint(r.timestamp - first.timestamp)
+		body.varint(r.offset - first.offset)
+		body.varintBytes(r.key)
+		body.varintBytes(r.value)
+		body.varint(0) // headers
+		e.varint(int64(len(body.buf)))
+		e.buf = append(e.buf, body.buf...)
+	}
+
+	batch := e.buf[start:]
+	binary.BigEndian.PutUint32(batch[8:12], uint32(len(batch)-12))
+	binary.BigEndian.PutUint32(batch[recordBatchCrcOffset:], crc32.Checksum(batch[recordBatchCrcOffset+4:], crc32cTable))
+	return e.buf
+}
@@ -0,0 +1,124 @@
+package kafka_gateway
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"math"
+	"testing"
+
+	snappy "github.com/eapache/go-xerial-snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+)
+
+func TestRecordBatchRoundTrip(t *testing.T) {
+	records := []record{
+		{offset: 1000, timestamp: 1, key: []byte("k1"), value: []byte("v1")},
+		{offset: 1001, timestamp: 3, key: nil, value: []byte("v2")},
+		{offset: 2000, timestamp: 2, key: []byte("k3"), value: nil},
+		// does not fit the offset delta of the first batch
+		{offset: 2000 + math.MaxInt32 + 1, timestamp: 4, key: []byte("k4"), value: []byte("v4")},
+	}
+	data := encodeRecordBatches(records)
+
+	decoded, errCode, err := decodeRecordBatches(data)
+	if err != nil || errCode != errNone {
+		t.Fatalf("decode: %v %v", errCode, err)
+	}
+	if len(decoded) != len(records) {
+		t.Fatalf("decoded %d records, want %d", len(decoded), len(records))
+	}
+	for i, r := range decoded {
+		want := records[i]
+		if r.offset != want.offset || r.timestamp != want.timestamp || !bytes.Equal(r.key, want.key) || !bytes.Equal(r.value, want.value) {
+			t.Errorf("record %d: got %+v, want %+v", i, r, want)
+		}
+		if (r.key == nil) != (want.key == nil) || (r.value == nil) != (want.value == nil) {
+			t.Errorf("record %d: null key or value is not kept", i)
+		}
+	}
+}
+
+func TestRecordBatchGzip(t *testing.T) {
+	batch := encodeRecordBatches([]record{
+		{offset: 0, timestamp: 10, key: []byte("k"), value: []byte("hello")},
+		{offset: 1, timestamp: 11, key: []byte("k"), value: []byte("world")},
+	})
+
+	// compress the records of the batch, and fix up the attributes, length and crc
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	writer.Write(batch[recordBatchHeaderSize:])
+	writer.Close()
+	batch = append(batch[:recordBatchHeaderSize:recordBatchHeaderSize], compressed.Bytes()...)
+	binary.BigEndian.PutUint16(batch[21:23], codecGzip)
+	binary.BigEndian.PutUint32(batch[8:12], uint32(len(batch)-12))
+	binary.BigEndian.PutUint32(batch[recordBatchCrcOffset:], crc32.Checksum(batch[recordBatchCrcOffset+4:], crc32cTable))
+
+	decoded, _, err := decodeRecordBatches(batch)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(decoded) != 2 || string(decoded[0].value) != "hello" || string(decoded[1].value) != "world" || decoded[1].offset != 1 {
+		t.Errorf("decoded %+v", decoded)
+	}
+}
+
+func TestRecordBatchCorrupted(t *testing.T) {
+	batch := encodeRecordBatches([]record{{offset: 0, value: []byte("v")}})
+
+	corrupted := append([]byte(nil), batch...)
+	corrupted[len(corrupted)-1] ^= 0xff
+	if _, errCode, err := decodeRecordBatches(corrupted); err == nil || errCode != errCorruptMessage {
+		t.Errorf("crc mismatch: %v %v", errCode, err)
+	}
+
+	if _, errCode, err := decodeRecordBatches(batch[:len(batch)-1]); err == nil || errCode != errCorruptMessage {
+		t.Errorf("truncated: %v %v", errCode, err)
+	}
+
+	oldMagic := append([]byte(nil), batch...)
+	oldMagic[16] = 1
+	if _, errCode, err := decodeRecordBatches(oldMagic); err == nil || errCode != errUnsupportedForMessageFormat {
+		t.Errorf("magic 1: %v %v", errCode, err)
+	}
+}
+
+func TestDecompressRecordsLimit(t *testing.T) {
+	data := bytes.Repeat([]byte("a"), 4096)
+
+	var gzipped bytes.Buffer
+	gzipWriter := gzip.NewWriter(&gzipped)
+	gzipWriter.Write(data)
+	gzipWriter.Close()
+
+	var lz4ed bytes.Buffer
+	lz4Writer := lz4.NewWriter(&lz4ed)
+	lz4Writer.Write(data)
+	lz4Writer.Close()
+
+	zstdEncoder, _ := zstd.NewWriter(nil)
+	zstded := zstdEncoder.EncodeAll(data, nil)
+	zstdEncoder.Close()
+
+	compressed := map[int][]byte{
+		codecGzip:   gzipped.Bytes(),
+		codecSnappy: snappy.EncodeStream(nil, data),
+		codecLz4:    lz4ed.Bytes(),
+		codecZstd:   zstded,
+	}
+	for codec, c := range compressed {
+		if decoded, err := decompressRecords(codec, c, len(data)); err != nil || !bytes.Equal(decoded, data) {
+			t.Errorf("codec %d: %d bytes decoded, %v", codec, len(decoded), err)
+		}
+		if _, err := decompressRecords(codec, c, len(data)-1); !errors.Is(err, errRecordBatchTooLarge) {
+			t.Errorf("codec %d over the limit: %v", codec, err)
+		}
+	}
+	if _, err := decompressRecords(codecSnappy, snappy.Encode(data), len(data)-1); !errors.Is(err, errRecordBatchTooLarge) {
+		t.Errorf("unframed snappy over the limit: %v", err)
+	}
+}
@@ -0,0 +1,513 @@
+package schema
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/seaweedfs/seaweedfs/weed/pb/schema_pb"
+)
+
+// RecordFilter is a filter expression over the fields of a record type, evaluated on the record values.
+//
+//	expression := term { OR term }
+//	term       := factor { AND factor }
+//	factor     := NOT factor | ( expression ) | predicate
+//	predicate  := field op value
+//	            | field [NOT] IN ( value { , value } )
+//	            | field [NOT] BETWEEN value AND value
+//	op         := = | != | <> | < | <= | > | >=
+//
+// A field is the name of a scalar field, with the names of the nested records joined by ".".
+// A value is a number, a 'quoted' or "quoted" string, true or false.
+// The keywords are case insensitive. A predicate on a field missing from the record value is false.
+//
+// Example:
+//
+//	country IN ('US', 'CA') AND amount BETWEEN 10 AND 100 AND NOT user.is_test = true
+type RecordFilter struct {
+	expression string
+	root       filterNode
+}
+
+type filterNode interface {
+	matches(record *schema_pb.RecordValue) bool
+}
+
+// ParseRecordFilter parses the filter expression, checking the fields and values against the record type
+func ParseRecordFilter(expression string, recordType *schema_pb.RecordType) (*RecordFilter, error) {
+	tokens, err := tokenizeFilter(expression)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{tokens: tokens, recordType: recordType}
+	root, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	if !p.atEnd() {
+		return nil, fmt.Errorf("unexpected %q at position %d", p.peek().text, p.peek().pos)
+	}
+	return &RecordFilter{expression: expression, root: root}, nil
+}
+
+// Matches returns true if the record value satisfies the filter
+func (f *RecordFilter) Matches(record *schema_pb.RecordValue) bool {
+	return f.root.matches(record)
+}
+
+func (f *RecordFilter) String() string {
+	return f.expression
+}
+
+type filterAnd struct {
+	left, right filterNode
+}
+
+func (n *filterAnd) matches(record *schema_pb.RecordValue) bool {
+	return n.left.matches(record) && n.right.matches(record)
+}
+
+type filterOr struct {
+	left, right filterNode
+}
+
+func (n *filterOr) matches(record *schema_pb.RecordValue) bool {
+	return n.left.matches(record) || n.right.matches(record)
+}
+
+type filterNot struct {
+	node filterNode
+}
+
+func (n *filterNot) matches(record *schema_pb.RecordValue) bool {
+	return !n.node.matches(record)
+}
+
+type filterPredicate struct {
+	path   []string
+	op     string
+	values []*schema_pb.Value
+}
+
+func (n *filterPredicate) matches(record *schema_pb.RecordValue) bool {
+	value := lookupFieldValue(record, n.path)
+	if value == nil {
+		return false
+	}
+	switch n.op {
+	case "IN":
+		for _, v := range n.values {
+			if c, ok := compareValues(value, v); ok && c == 0 {
+				return true
+			}
+		}
+		return false
+	case "BETWEEN":
+		low, lowOk := compareValues(value, n.values[0])
+		high, highOk := compareValues(value, n.values[1])
+		return lowOk && highOk && low >= 0 && high <= 0
+	}
+	c, ok := compareValues(value, n.values[0])
+	if !ok {
+		return false
+	}
+	switch n.op {
+	case "=":
+		return c == 0
+	case "!=":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+	return false
+}
+
+func lookupFieldValue(record *schema_pb.RecordValue, path []string) *schema_pb.Value {
+	for i, name := range path {
+		if record == nil {
+			return nil
+		}
+		value, found := record.Fields[name]
+		if !found {
+			return nil
+		}
+		if i == len(path)-1 {
+			return value
+		}
+		record = value.GetRecordValue()
+	}
+	return nil
+}
+
+// compareValues compares two values of the same kind, returning false if the kinds differ
+func compareValues(a, b *schema_pb.Value) (int, bool) {
+	switch av := a.Kind.(type) {
+	case *schema_pb.Value_BoolValue:
+		if bv, ok := b.Kind.(*schema_pb.Value_BoolValue); ok {
+			if av.BoolValue == bv.BoolValue {
+				return 0, true
+			}
+			if bv.BoolValue {
+				return -1, true
+			}
+			return 1, true
+		}
+	case *schema_pb.Value_Int32Value:
+		if bv, ok := b.Kind.(*schema_pb.Value_Int32Value); ok {
+			return cmp.Compare(av.Int32Value, bv.Int32Value), true
+		}
+	case *schema_pb.Value_Int64Value:
+		if bv, ok := b.Kind.(*schema_pb.Value_Int64Value); ok {
+			return cmp.Compare(av.Int64Value, bv.Int64Value), true
+		}
+	case *schema_pb.Value_FloatValue:
+		if bv, ok := b.Kind.(*schema_pb.Value_FloatValue); ok {
+			return cmp.Compare(av.FloatValue, bv.FloatValue), true
+		}
+	case *schema_pb.Value_DoubleValue:
+		if bv, ok := b.Kind.(*schema_pb.Value_DoubleValue); ok {
+			return cmp.Compare(av.DoubleValue, bv.DoubleValue), true
+		}
+	case *schema_pb.Value_BytesValue:
+		if bv, ok := b.Kind.(*schema_pb.Value_BytesValue); ok {
+			return bytes.Compare(av.BytesValue, bv.BytesValue), true
+		}
+	case *schema_pb.Value_StringValue:
+		if bv, ok := b.Kind.(*schema_pb.Value_StringValue); ok {
+			return strings.Compare(av.StringValue, bv.StringValue), true
+		}
+	}
+	return 0, false
+}
+
+type filterTokenKind int
+
+const (
+	filterTokenIdentifier filterTokenKind = iota
+	filterTokenNumber
+	filterTokenString
+	filterTokenSymbol
+)
+
+type filterToken struct {
+	kind filterTokenKind
+	text string
+	pos  int
+}
+
+func tokenizeFilter(expression string) (tokens []filterToken, err error) {
+	isIdentifierStart := func(c byte) bool {
+		return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+	}
+	isDigit := func(c byte) bool {
+		return '0' <= c && c <= '9'
+	}
+	for i := 0; i < len(expression); {
+		c := expression[i]
+		start := i
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case isIdentifierStart(c):
+			for i < len(expression) && (isIdentifierStart(expression[i]) || isDigit(expression[i]) || expression[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, filterToken{filterTokenIdentifier, expression[start:i], start})
+		case isDigit(c) || (c == '-' || c == '+' || c == '.') && i+1 < len(expression) && (isDigit(expression[i+1]) || expression[i+1] == '.'):
+			i++
+			for i < len(expression) && (isDigit(expression[i]) || strings.IndexByte(".eE", expression[i]) >= 0 ||
+				(expression[i] == '-' || expression[i] == '+') && (expression[i-1] == 'e' || expression[i-1] == 'E')) {
+				i++
+			}
+			tokens = append(tokens, filterToken{filterTokenNumber, expression[start:i], start})
+		case c == '\'' || c == '"':
+			var sb strings.Builder
+			for i++; ; i++ {
+				if i >= len(expression) {
+					return nil, fmt.Errorf("unterminated string at position %d", start)
+				}
+				if expression[i] == c {
+					// a doubled quote is an escaped quote
+					if i+1 < len(expression) && expression[i+1] == c {
+						sb.WriteByte(c)
+						i++
+						continue
+					}
+					i++
+					break
+				}
+				sb.WriteByte(expression[i])
+			}
+			tokens = append(tokens, filterToken{filterTokenString, sb.String(), start})
+		default:
+			symbol := string(c)
+			if i+1 < len(expression) {
+				switch two := expression[i : i+2]; two {
+				case "!=", "<>", "<=", ">=", "==":
+					symbol = two
+				}
+			}
+			switch symbol {
+			case "=", "==", "!=", "<>", "<", "<=", ">", ">=", "(", ")", ",":
+			default:
+				return nil, fmt.Errorf("unexpected %q at position %d", symbol, start)
+			}
+			i += len(symbol)
+			tokens = append(tokens, filterToken{filterTokenSymbol, symbol, start})
+		}
+	}
+	return tokens, nil
+}
+
+type filterParser struct {
+	tokens     []filterToken
+	index      int
+	recordType *schema_pb.RecordType
+}
+
+func (p *filterParser) atEnd() bool {
+	return p.index >= len(p.tokens)
+}
+
+func (p *filterParser) peek() filterToken {
+	if p.atEnd() {
+		return filterToken{kind: filterTokenSymbol, text: "end of filter", pos: -1}
+	}
+	return p.tokens[p.index]
+}
+
+// isKeyword checks the next token is the keyword, and consumes it if so
+func (p *filterParser) isKeyword(keyword string) bool {
+	token := p.peek()
+	if !p.atEnd() && token.kind == filterTokenIdentifier && strings.EqualFold(token.text, keyword) {
+		p.index++
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) isSymbol(symbol string) bool {
+	token := p.peek()
+	if !p.atEnd() && token.kind == filterTokenSymbol && token.text == symbol {
+		p.index++
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) expectSymbol(symbol string) error {
+	if !p.isSymbol(symbol) {
+		return fmt.Errorf("expect %q but found %q at position %d", symbol, p.peek().text, p.peek().pos)
+	}
+	return nil
+}
+
+func (p *filterParser) parseExpression() (filterNode, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("OR") {
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = &filterOr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseTerm() (filterNode, error) {
+	left, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("AND") {
+		right, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		left = &filterAnd{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseFactor() (filterNode, error) {
+	if p.isKeyword("NOT") {
+		node, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		return &filterNot{node: node}, nil
+	}
+	if p.isSymbol("(") {
+		node, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		if err = p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+		return node, nil
+	}
+	return p.parsePredicate()
+}
+
+func (p *filterParser) parsePredicate() (filterNode, error) {
+	token := p.peek()
+	if p.atEnd() || token.kind != filterTokenIdentifier {
+		return nil, fmt.Errorf("expect a field name but found %q at position %d", token.text, token.pos)
+	}
+	p.index++
+	path := strings.Split(token.text, ".")
+	scalarType, err := lookupScalarType(p.recordType, path)
+	if err != nil {
+		return nil, err
+	}
+	predicate := &filterPredicate{path: path}
+
+	negated := p.isKeyword("NOT")
+	switch {
+	case p.isKeyword("IN"):
+		predicate.op = "IN"
+		if err = p.expectSymbol("("); err != nil {
+			return nil, err
+		}
+		for {
+			value, err := p.parseValue(token.text, scalarType)
+			if err != nil {
+				return nil, err
+			}
+			predicate.values = append(predicate.values, value)
+			if !p.isSymbol(",") {
+				break
+			}
+		}
+		if err = p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+	case p.isKeyword("BETWEEN"):
+		predicate.op = "BETWEEN"
+		low, err := p.parseValue(token.text, scalarType)
+		if err != nil {
+			return nil, err
+		}
+		if !p.isKeyword("AND") {
+			return nil, fmt.Errorf("expect AND but found %q at position %d", p.peek().text, p.peek().pos)
+		}
+		high, err := p.parseValue(token.text, scalarType)
+		if err != nil {
+			return nil, err
+		}
+		predicate.values = []*schema_pb.Value{low, high}
+	case negated:
+		return nil, fmt.Errorf("expect IN or BETWEEN but found %q at position %d", p.peek().text, p.peek().pos)
+	default:
+		op := p.peek()
+		switch op.text {
+		case "=", "==":
+			predicate.op = "="
+		case "!=", "<>":
+			predicate.op = "!="
+		case "<", "<=", ">", ">=":
+			predicate.op = op.text
+		}
+		if p.atEnd() || op.kind != filterTokenSymbol || predicate.op == "" {
+			return nil, fmt.Errorf("expect a comparison operator but found %q at position %d", op.text, op.pos)
+		}
+		p.index++
+		value, err := p.parseValue(token.text, scalarType)
+		if err != nil {
+			return nil, err
+		}
+		if scalarType == schema_pb.ScalarType_BOOL && predicate.op != "=" && predicate.op != "!=" {
+			return nil, fmt.Errorf("field %s of type bool only supports = and !=", token.text)
+		}
+		predicate.values = []*schema_pb.Value{value}
+	}
+
+	if negated {
+		return &filterNot{node: predicate}, nil
+	}
+	return predicate, nil
+}
+
+// parseValue parses the next token as a value of the scalar type of the field
+func (p *filterParser) parseValue(fieldName string, scalarType schema_pb.ScalarType) (*schema_pb.Value, error) {
+	token := p.peek()
+	if p.atEnd() || token.kind == filterTokenSymbol {
+		return nil, fmt.Errorf("expect a value for %s but found %q at position %d", fieldName, token.text, token.pos)
+	}
+	p.index++
+	switch scalarType {
+	case schema_pb.ScalarType_BOOL:
+		if token.kind == filterTokenIdentifier && (strings.EqualFold(token.text, "true") || strings.EqualFold(token.text, "false")) {
+			return &schema_pb.Value{Kind: &schema_pb.Value_BoolValue{BoolValue: strings.EqualFold(token.text, "true")}}, nil
+		}
+	case schema_pb.ScalarType_INT32:
+		if v, err := strconv.ParseInt(token.text, 10, 32); token.kind == filterTokenNumber && err == nil {
+			return &schema_pb.Value{Kind: &schema_pb.Value_Int32Value{Int32Value: int32(v)}}, nil
+		}
+	case schema_pb.ScalarType_INT64:
+		if v, err := strconv.ParseInt(token.text, 10, 64); token.kind == filterTokenNumber && err == nil {
+			return &schema_pb.Value{Kind: &schema_pb.Value_Int64Value{Int64Value: v}}, nil
+		}
+	case schema_pb.ScalarType_FLOAT:
+		if v, err := strconv.ParseFloat(token.text, 32); token.kind == filterTokenNumber && err == nil {
+			return &schema_pb.Value{Kind: &schema_pb.Value_FloatValue{FloatValue: float32(v)}}, nil
+		}
+	case schema_pb.ScalarType_DOUBLE:
+		if v, err := strconv.ParseFloat(token.text, 64); token.kind == filterTokenNumber && err == nil {
+			return &schema_pb.Value{Kind: &schema_pb.Value_DoubleValue{DoubleValue: v}}, nil
+		}
+	case schema_pb.ScalarType_BYTES:
+		if token.kind == filterTokenString {
+			return &schema_pb.Value{Kind: &schema_pb.Value_BytesValue{BytesValue: []byte(token.text)}}, nil
+		}
+	case schema_pb.ScalarType_STRING:
+		if token.kind == filterTokenString {
+			return &schema_pb.Value{Kind: &schema_pb.Value_StringValue{StringValue: token.text}}, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid %s value %q for %s at position %d", strings.ToLower(scalarType.String()), token.text, fieldName, token.pos)
+}
+
+// lookupScalarType finds the scalar type of the field, following the nested records on the path
+func lookupScalarType(recordType *schema_pb.RecordType, path []string) (schema_pb.ScalarType, error) {
+	fieldName := strings.Join(path, ".")
+	for i, name := range path {
+		var field *schema_pb.Field
+		for _, f := range recordType.GetFields() {
+			if f.Name == name {
+				field = f
+				break
+			}
+		}
+		if field == nil {
+			return 0, fmt.Errorf("unknown field %s", fieldName)
+		}
+		if field.IsRepeated {
+			return 0, fmt.Errorf("filter on repeated field %s is not supported", fieldName)
+		}
+		if i == len(path)-1 {
+			scalarType, ok := field.Type.GetKind().(*schema_pb.Type_ScalarType)
+			if !ok {
+				return 0, fmt.Errorf("field %s is not a scalar", fieldName)
+			}
+			return scalarType.ScalarType, nil
+		}
+		if recordType = field.Type.GetRecordType(); recordType == nil {
+			return 0, fmt.Errorf("field %s is not a record", strings.Join(path[:i+1], "."))
+		}
+	}
+	return 0, fmt.Errorf("unknown field %s", fieldName)
+}
@@ -0,0 +1,101 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordFilter(t *testing.T) {
+	recordType := RecordTypeBegin().
+		WithField("country", TypeString).
+		WithField("amount", TypeInt64).
+		WithField("count", TypeInt32).
+		WithField("score", TypeDouble).
+		WithField("active", TypeBoolean).
+		WithField("missing_in_value", TypeString).
+		WithRecordField("user",
+			RecordTypeBegin().
+				WithField("name", TypeString).
+				WithField("id", TypeBytes).
+				RecordTypeEnd(),
+		).
+		RecordTypeEnd()
+
+	record := RecordBegin().
+		SetString("country", "US").
+		SetInt64("amount", 42).
+		SetInt32("count", 3).
+		SetDouble("score", 0.5).
+		SetBool("active", true).
+		SetRecord("user",
+			RecordBegin().
+				SetString("name", "it's me").
+				SetBytes("id", []byte("u1")).
+				RecordEnd(),
+		).
+		RecordEnd()
+
+	tests := []struct {
+		filter  string
+		matches bool
+	}{
+		{"country = 'US'", true},
+		{"country == \"US\"", true},
+		{"country != 'US'", false},
+		{"country <> 'CA'", true},
+		{"amount > 41 AND amount <= 42", true},
+		{"amount < 42", false},
+		{"amount BETWEEN 10 AND 100", true},
+		{"amount NOT BETWEEN 10 AND 100", false},
+		{"count >= -1 and score < 1e1", true},
+		{"country IN ('CA', 'US')", true},
+		{"country not in ('CA', 'US')", false},
+		{"active = true AND NOT active = false", true},
+		{"user.name = 'it''s me'", true},
+		{"user.id = 'u1'", true},
+		{"country = 'CA' OR amount = 42", true},
+		{"country = 'CA' OR amount = 42 AND count = 4", false},
+		{"(country = 'CA' OR amount = 42) AND count = 3", true},
+		{"NOT (country = 'CA' OR amount = 1)", true},
+		{"missing_in_value = 'x' OR score > 0.4", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			filter, err := ParseRecordFilter(tt.filter, recordType)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.matches, filter.Matches(record))
+		})
+	}
+}
+
+func TestRecordFilterErrors(t *testing.T) {
+	recordType := RecordTypeBegin().
+		WithField("country", TypeString).
+		WithField("amount", TypeInt32).
+		WithField("active", TypeBoolean).
+		WithField("tags", ListOf(TypeString)).
+		RecordTypeEnd()
+
+	for _, filter := range []string{
+		"",
+		"unknown = 1",
+		"country = 1",
+		"amount = 'a'",
+		"amount = 3000000000",
+		"active > true",
+		"tags = 'a'",
+		"country.name = 'a'",
+		"country = 'US",
+		"country = 'US' AND",
+		"country IN ('US'",
+		"amount BETWEEN 1 OR 2",
+		"amount NOT = 1",
+		"(amount = 1",
+		"amount = 1)",
+		"amount ~ 1",
+	} {
+		_, err := ParseRecordFilter(filter, recordType)
+		assert.NotNil(t, err, filter)
+	}
+}
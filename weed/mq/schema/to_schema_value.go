@@ -39,7 +39,9 @@ func toRecordValue(recordType *schema_pb.RecordType, levels *ParquetLevels, valu
 			return nil, 0, err
 		}
 		valueIndex = endValueIndex
-		recordValue.Fields[field.Name] = fieldValue
+		if fieldValue != nil {
+			recordValue.Fields[field.Name] = fieldValue
+		}
 	}
 	return &schema_pb.Value{Kind: &schema_pb.Value_RecordValue{RecordValue: &recordValue}}, valueIndex, nil
 }
@@ -55,7 +57,9 @@ func toListValue(listType *schema_pb.ListType, levels *ParquetLevels, values []p
 		if err != nil {
 			return nil, valueIndex, err
 		}
-		listValues = append(listValues, value)
+		if value != nil {
+			listValues = append(listValues, value)
+		}
 	}
 	return &schema_pb.Value{Kind: &schema_pb.Value_ListValue{ListValue: &schema_pb.ListValue{Values: listValues}}}, valueIndex, nil
 }
@@ -65,6 +69,10 @@ func toScalarValue(scalarType schema_pb.ScalarType, levels *ParquetLevels, value
 	if value.Column() != levels.startColumnIndex {
 		return nil, valueIndex, nil
 	}
+	if value.IsNull() {
+		// the optional field is not set
+		return nil, valueIndex + 1, nil
+	}
 	switch scalarType {
 	case schema_pb.ScalarType_BOOL:
 		return &schema_pb.Value{Kind: &schema_pb.Value_BoolValue{BoolValue: value.Boolean()}}, valueIndex + 1, nil
@@ -65,6 +65,16 @@ func (imt *InflightMessageTracker) AcknowledgeMessage(key []byte, tsNs int64) bo
 	return true
 }
 
+// IsMessageInflight returns true if the message with the key and timestamp is inflight.
+// A negatively acked message stays inflight until it is redelivered and acknowledged.
+// The failed deliveries are counted by the broker, since they outlive the subscriber connection.
+func (imt *InflightMessageTracker) IsMessageInflight(key []byte, tsNs int64) bool {
+	imt.mu.Lock()
+	defer imt.mu.Unlock()
+	timestamp, exists := imt.messages[string(key)]
+	return exists && timestamp == tsNs
+}
+
 func (imt *InflightMessageTracker) GetOldestAckedTimestamp() int64 {
 	return imt.timestamps.OldestAckedTimestamp()
 }
@@ -131,3 +131,22 @@ func TestInflightMessageTracker4(t *testing.T) {
 	assert.Equal(t, int64(3), tracker.GetOldestAckedTimestamp())
 
 }
+
+func TestInflightMessageTrackerNack(t *testing.T) {
+	tracker := NewInflightMessageTracker(5)
+	tracker.EnflightMessage([]byte("1"), int64(1))
+	tracker.EnflightMessage([]byte("2"), int64(2))
+
+	assert.True(t, tracker.IsMessageInflight([]byte("1"), int64(1)))
+	assert.False(t, tracker.IsMessageInflight([]byte("1"), int64(2)))
+	assert.True(t, tracker.IsInflight([]byte("1")))
+
+	// the nacked message holds back the acked timestamp
+	assert.True(t, tracker.AcknowledgeMessage([]byte("2"), int64(2)))
+	assert.Equal(t, int64(0), tracker.GetOldestAckedTimestamp())
+	assert.True(t, tracker.AcknowledgeMessage([]byte("1"), int64(1)))
+	assert.Equal(t, int64(2), tracker.GetOldestAckedTimestamp())
+
+	assert.False(t, tracker.IsMessageInflight([]byte("1"), int64(1)))
+	assert.False(t, tracker.IsMessageInflight([]byte("3"), int64(3)))
+}
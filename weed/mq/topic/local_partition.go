@@ -27,6 +27,7 @@ type LocalPartition struct {
 	LogBuffer   *log_buffer.LogBuffer
 	Publishers  *LocalPartitionPublishers
 	Subscribers *LocalPartitionSubscribers
+	Producers   *LocalPartitionProducers
 
 	publishFolloweMeStream mq_pb.SeaweedMessaging_PublishFollowMeClient
 	followerGrpcConnection *grpc.ClientConn
@@ -40,6 +41,7 @@ func NewLocalPartition(partition Partition, logFlushFn log_buffer.LogFlushFuncTy
 		Partition:   partition,
 		Publishers:  NewLocalPartitionPublishers(),
 		Subscribers: NewLocalPartitionSubscribers(),
+		Producers:   NewLocalPartitionProducers(),
 	}
 	lp.ListenersCond = sync.NewCond(&lp.ListenersLock)
 	lp.LogBuffer = log_buffer.NewLogBuffer(fmt.Sprintf("%d/%04d-%04d", partition.UnixTimeNs, partition.RangeStart, partition.RangeStop),
@@ -52,6 +54,18 @@ func NewLocalPartition(partition Partition, logFlushFn log_buffer.LogFlushFuncTy
 }
 
 func (p *LocalPartition) Publish(message *mq_pb.DataMessage) error {
+	if message.ProducerId != "" && message.Sequence > 0 && !p.Producers.Accept(message.ProducerId, message.Sequence, message.TsNs) {
+		// resent after a lost ack, already written
+		glog.V(4).Infof("local partition %v drops duplicated message %d from producer %s", p.Partition, message.Sequence, message.ProducerId)
+		// ack it still, since the publisher waits for the ack of every resent message
+		for ackTsNs := atomic.LoadInt64(&p.AckTsNs); ackTsNs < message.TsNs; ackTsNs = atomic.LoadInt64(&p.AckTsNs) {
+			if atomic.CompareAndSwapInt64(&p.AckTsNs, ackTsNs, message.TsNs) {
+				break
+			}
+		}
+		return nil
+	}
+
 	p.LogBuffer.AddToBuffer(message)
 
 	// maybe send to the follower
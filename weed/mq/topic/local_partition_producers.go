@@ -0,0 +1,120 @@
+package topic
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/seaweedfs/seaweedfs/weed/pb/mq_pb"
+)
+
+// producers not publishing for this long are forgotten, and their resent messages are no longer dropped
+const producerSequenceRetention = 7 * 24 * time.Hour
+
+// LocalPartitionProducers tracks the last sequence of each idempotent producer publishing to the partition,
+// so the messages resent after a lost ack are dropped instead of being written twice.
+type LocalPartitionProducers struct {
+	producers     map[string]*localProducer
+	producersLock sync.Mutex
+}
+
+type localProducer struct {
+	sequence int64
+	// the last sequence already flushed to the filer
+	flushed *mq_pb.ProducerSequence
+	// the sequences accepted but not flushed yet, in the order of the timestamps
+	pending []*mq_pb.ProducerSequence
+}
+
+func NewLocalPartitionProducers() *LocalPartitionProducers {
+	return &LocalPartitionProducers{
+		producers: make(map[string]*localProducer),
+	}
+}
+
+// Accept records the sequence of the producer, and returns false if the message was already accepted
+func (p *LocalPartitionProducers) Accept(producerId string, sequence int64, tsNs int64) bool {
+	p.producersLock.Lock()
+	defer p.producersLock.Unlock()
+
+	producer, found := p.producers[producerId]
+	if !found {
+		producer = &localProducer{}
+		p.producers[producerId] = producer
+	}
+	if sequence <= producer.sequence {
+		return false
+	}
+	producer.sequence = sequence
+	if tsNs == 0 {
+		tsNs = time.Now().UnixNano()
+	}
+	producer.pending = append(producer.pending, &mq_pb.ProducerSequence{
+		ProducerId: producerId,
+		Sequence:   sequence,
+		TsNs:       tsNs,
+	})
+	return true
+}
+
+// Sequence returns the last accepted sequence of the producer, 0 if unknown
+func (p *LocalPartitionProducers) Sequence(producerId string) int64 {
+	p.producersLock.Lock()
+	defer p.producersLock.Unlock()
+
+	if producer, found := p.producers[producerId]; found {
+		return producer.sequence
+	}
+	return 0
+}
+
+// NotifyFlushed marks the sequences of the messages up to flushTsNs as flushed.
+// It returns the flushed sequences of all producers to persist, and false if nothing changed.
+func (p *LocalPartitionProducers) NotifyFlushed(flushTsNs int64) (*mq_pb.ProducerSequences, bool) {
+	p.producersLock.Lock()
+	defer p.producersLock.Unlock()
+
+	hasChanges := false
+	expireTsNs := flushTsNs - int64(producerSequenceRetention)
+	sequences := &mq_pb.ProducerSequences{}
+	for producerId, producer := range p.producers {
+		flushedCount := 0
+		for flushedCount < len(producer.pending) && producer.pending[flushedCount].TsNs <= flushTsNs {
+			flushedCount++
+		}
+		if flushedCount > 0 {
+			producer.flushed = producer.pending[flushedCount-1]
+			producer.pending = producer.pending[flushedCount:]
+			hasChanges = true
+		}
+		if producer.flushed == nil {
+			continue
+		}
+		if len(producer.pending) == 0 && producer.flushed.TsNs < expireTsNs {
+			delete(p.producers, producerId)
+			hasChanges = true
+			continue
+		}
+		sequences.Producers = append(sequences.Producers, producer.flushed)
+	}
+	sort.Slice(sequences.Producers, func(i, j int) bool {
+		return sequences.Producers[i].ProducerId < sequences.Producers[j].ProducerId
+	})
+	return sequences, hasChanges
+}
+
+// Load restores the persisted sequences, when the partition is assigned to this broker
+func (p *LocalPartitionProducers) Load(sequences *mq_pb.ProducerSequences) {
+	p.producersLock.Lock()
+	defer p.producersLock.Unlock()
+
+	for _, flushed := range sequences.GetProducers() {
+		if producer, found := p.producers[flushed.ProducerId]; found && producer.sequence >= flushed.Sequence {
+			continue
+		}
+		p.producers[flushed.ProducerId] = &localProducer{
+			sequence: flushed.Sequence,
+			flushed:  flushed,
+		}
+	}
+}
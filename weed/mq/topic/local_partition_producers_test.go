@@ -0,0 +1,81 @@
+package topic
+
+import (
+	"testing"
+	"time"
+
+	"github.com/seaweedfs/seaweedfs/weed/pb/mq_pb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalPartitionProducersDropDuplicates(t *testing.T) {
+	producers := NewLocalPartitionProducers()
+
+	assert.True(t, producers.Accept("p1", 1, 100))
+	assert.True(t, producers.Accept("p1", 2, 200))
+	assert.True(t, producers.Accept("p2", 1, 300))
+
+	// resent after a lost ack
+	assert.False(t, producers.Accept("p1", 1, 100))
+	assert.False(t, producers.Accept("p1", 2, 200))
+
+	assert.True(t, producers.Accept("p1", 3, 400))
+	assert.Equal(t, int64(3), producers.Sequence("p1"))
+	assert.Equal(t, int64(1), producers.Sequence("p2"))
+	assert.Equal(t, int64(0), producers.Sequence("p3"))
+}
+
+func TestLocalPartitionProducersNotifyFlushed(t *testing.T) {
+	producers := NewLocalPartitionProducers()
+	producers.Accept("p1", 1, 100)
+	producers.Accept("p1", 2, 200)
+	producers.Accept("p2", 1, 300)
+
+	// only the sequences of the flushed messages are persisted
+	sequences, hasChanges := producers.NotifyFlushed(250)
+	assert.True(t, hasChanges)
+	assert.Equal(t, 1, len(sequences.Producers))
+	assert.Equal(t, "p1", sequences.Producers[0].ProducerId)
+	assert.Equal(t, int64(2), sequences.Producers[0].Sequence)
+
+	_, hasChanges = producers.NotifyFlushed(260)
+	assert.False(t, hasChanges)
+
+	sequences, hasChanges = producers.NotifyFlushed(300)
+	assert.True(t, hasChanges)
+	assert.Equal(t, 2, len(sequences.Producers))
+	assert.Equal(t, "p2", sequences.Producers[1].ProducerId)
+
+	// the idle producers expire
+	sequences, hasChanges = producers.NotifyFlushed(301 + int64(producerSequenceRetention))
+	assert.True(t, hasChanges)
+	assert.Equal(t, 0, len(sequences.Producers))
+	assert.True(t, producers.Accept("p1", 1, time.Now().UnixNano()))
+}
+
+func TestLocalPartitionProducersLoad(t *testing.T) {
+	producers := NewLocalPartitionProducers()
+	producers.Accept("p2", 5, 100)
+	producers.Load(&mq_pb.ProducerSequences{
+		Producers: []*mq_pb.ProducerSequence{
+			{ProducerId: "p1", Sequence: 7, TsNs: 50},
+			{ProducerId: "p2", Sequence: 3, TsNs: 50},
+		},
+	})
+
+	assert.False(t, producers.Accept("p1", 7, 200))
+	assert.True(t, producers.Accept("p1", 8, 200))
+	assert.Equal(t, int64(5), producers.Sequence("p2"), "the newer sequence is kept")
+}
+
+func TestLocalPartitionAcksDuplicates(t *testing.T) {
+	p := NewLocalPartition(Partition{RangeStop: 1024}, nil, nil)
+	defer p.LogBuffer.ShutdownLogBuffer()
+
+	assert.Nil(t, p.Publish(&mq_pb.DataMessage{ProducerId: "p1", Sequence: 1, TsNs: 100}))
+	assert.Equal(t, int64(100), p.AckTsNs)
+
+	// the message resent after a lost ack is dropped, but acked
+	assert.Nil(t, p.Publish(&mq_pb.DataMessage{ProducerId: "p1", Sequence: 1, TsNs: 200}))
+	assert.Equal(t, int64(200), p.AckTsNs)
+}
@@ -75,6 +75,11 @@ func (k *AwsSqsPub) SendMessage(key string, message proto.Message) (err error) {
 		return fmt.Errorf("send message marshal %+v: %v", message, err)
 	}
 
+	return k.SendRawMessage(key, text)
+}
+
+func (k *AwsSqsPub) SendRawMessage(key string, text []byte) (err error) {
+
 	_, err = k.svc.SendMessage(&sqs.SendMessageInput{
 		DelaySeconds: aws.Int64(10),
 		MessageAttributes: map[string]*sqs.MessageAttributeValue{
@@ -1,6 +1,8 @@
 package notification
 
 import (
+	"reflect"
+
 	"github.com/seaweedfs/seaweedfs/weed/glog"
 	"github.com/seaweedfs/seaweedfs/weed/util"
 	"google.golang.org/protobuf/proto"
@@ -12,6 +14,8 @@ type MessageQueue interface {
 	// Initialize initializes the file store
 	Initialize(configuration util.Configuration, prefix string) error
 	SendMessage(key string, message proto.Message) error
+	// SendRawMessage sends an already serialized message, e.g. the json of S3 event notifications
+	SendRawMessage(key string, message []byte) error
 }
 
 var (
@@ -54,3 +58,28 @@ func validateOneEnabledQueue(config *util.ViperProxy) {
 		}
 	}
 }
+
+// LoadQueues initializes a new instance of each enabled message queue under the prefix.
+// Unlike LoadConfiguration, several queues can be enabled, and the filer Queue is not changed.
+func LoadQueues(config util.Configuration, prefix string) (queues map[string]MessageQueue) {
+
+	queues = make(map[string]MessageQueue)
+	if config == nil {
+		return
+	}
+
+	for _, queue := range MessageQueues {
+		if !config.GetBool(prefix + queue.GetName() + ".enabled") {
+			continue
+		}
+		instance := reflect.New(reflect.TypeOf(queue).Elem()).Interface().(MessageQueue)
+		if err := instance.Initialize(config, prefix+queue.GetName()+"."); err != nil {
+			glog.Errorf("Failed to initialize notification for %s%s: %+v", prefix, queue.GetName(), err)
+			continue
+		}
+		queues[queue.GetName()] = instance
+		glog.V(0).Infof("Configure notification message queue %s%s", prefix, queue.GetName())
+	}
+
+	return
+}
@@ -27,3 +27,9 @@ func (k *LogQueue) SendMessage(key string, message proto.Message) (err error) {
 	glog.V(0).Infof("%v: %+v", key, message)
 	return nil
 }
+
+func (k *LogQueue) SendRawMessage(key string, message []byte) (err error) {
+
+	glog.V(0).Infof("%v: %s", key, message)
+	return nil
+}
@@ -0,0 +1,88 @@
+package webhook
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/notification"
+	"github.com/seaweedfs/seaweedfs/weed/util"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func init() {
+	notification.MessageQueues = append(notification.MessageQueues, &WebhookQueue{})
+}
+
+// WebhookQueue posts each message as the body of a http request to an endpoint
+type WebhookQueue struct {
+	endpoint    string
+	bearerToken string
+	client      *http.Client
+}
+
+func (k *WebhookQueue) GetName() string {
+	return "webhook"
+}
+
+func (k *WebhookQueue) Initialize(configuration util.Configuration, prefix string) (err error) {
+	glog.V(0).Infof("notification.webhook.endpoint: %v", configuration.GetString(prefix+"endpoint"))
+	configuration.SetDefault(prefix+"timeout_seconds", 10)
+	return k.initialize(
+		configuration.GetString(prefix+"endpoint"),
+		configuration.GetString(prefix+"bearer_token"),
+		configuration.GetInt(prefix+"timeout_seconds"),
+	)
+}
+
+func (k *WebhookQueue) initialize(endpoint, bearerToken string, timeoutSeconds int) (err error) {
+	if endpoint == "" {
+		return fmt.Errorf("webhook endpoint is not configured")
+	}
+	k.endpoint = endpoint
+	k.bearerToken = bearerToken
+	k.client = &http.Client{
+		Timeout: time.Duration(timeoutSeconds) * time.Second,
+	}
+	return nil
+}
+
+func (k *WebhookQueue) SendMessage(key string, message proto.Message) (err error) {
+
+	data, err := protojson.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("send message marshal %+v: %v", message, err)
+	}
+
+	return k.SendRawMessage(key, data)
+}
+
+func (k *WebhookQueue) SendRawMessage(key string, message []byte) (err error) {
+
+	req, err := http.NewRequest(http.MethodPost, k.endpoint, bytes.NewReader(message))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Seaweedfs-Key", key)
+	if k.bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+k.bearerToken)
+	}
+
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send message to webhook %s: %v", k.endpoint, err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("send message to webhook %s: %s", k.endpoint, resp.Status)
+	}
+
+	return nil
+}
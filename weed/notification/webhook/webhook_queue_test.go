@@ -0,0 +1,35 @@
+package webhook
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendRawMessage(t *testing.T) {
+	var body, key, authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		key = r.Header.Get("X-Seaweedfs-Key")
+		authorization = r.Header.Get("Authorization")
+		if key == "fail" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	queue := &WebhookQueue{}
+	assert.NotNil(t, queue.initialize("", "", 1))
+	assert.Nil(t, queue.initialize(server.URL, "token", 1))
+
+	assert.Nil(t, queue.SendRawMessage("/buckets/b/k", []byte(`{"Records":[]}`)))
+	assert.Equal(t, `{"Records":[]}`, body)
+	assert.Equal(t, "/buckets/b/k", key)
+	assert.Equal(t, "Bearer token", authorization)
+
+	assert.NotNil(t, queue.SendRawMessage("fail", nil))
+}
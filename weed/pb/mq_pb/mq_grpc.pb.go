@@ -33,6 +33,7 @@ const (
 	SeaweedMessaging_SubscribeMessage_FullMethodName           = "/messaging_pb.SeaweedMessaging/SubscribeMessage"
 	SeaweedMessaging_PublishFollowMe_FullMethodName            = "/messaging_pb.SeaweedMessaging/PublishFollowMe"
 	SeaweedMessaging_SubscribeFollowMe_FullMethodName          = "/messaging_pb.SeaweedMessaging/SubscribeFollowMe"
+	SeaweedMessaging_CommitOffsets_FullMethodName              = "/messaging_pb.SeaweedMessaging/CommitOffsets"
 )
 
 // SeaweedMessagingClient is the client API for SeaweedMessaging service.
@@ -60,6 +61,9 @@ type SeaweedMessagingClient interface {
 	// The lead broker asks a follower broker to follow itself
 	PublishFollowMe(ctx context.Context, opts ...grpc.CallOption) (SeaweedMessaging_PublishFollowMeClient, error)
 	SubscribeFollowMe(ctx context.Context, opts ...grpc.CallOption) (SeaweedMessaging_SubscribeFollowMeClient, error)
+	// commit the consumed offsets of several partitions together, after the produced messages are acked.
+	// the commit is not transactional with the produced messages: the pipelines get at-least-once delivery
+	CommitOffsets(ctx context.Context, in *CommitOffsetsRequest, opts ...grpc.CallOption) (*CommitOffsetsResponse, error)
 }
 
 type seaweedMessagingClient struct {
@@ -331,6 +335,15 @@ func (x *seaweedMessagingSubscribeFollowMeClient) CloseAndRecv() (*SubscribeFoll
 	return m, nil
 }
 
+func (c *seaweedMessagingClient) CommitOffsets(ctx context.Context, in *CommitOffsetsRequest, opts ...grpc.CallOption) (*CommitOffsetsResponse, error) {
+	out := new(CommitOffsetsResponse)
+	err := c.cc.Invoke(ctx, SeaweedMessaging_CommitOffsets_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SeaweedMessagingServer is the server API for SeaweedMessaging service.
 // All implementations must embed UnimplementedSeaweedMessagingServer
 // for forward compatibility
@@ -356,6 +369,9 @@ type SeaweedMessagingServer interface {
 	// The lead broker asks a follower broker to follow itself
 	PublishFollowMe(SeaweedMessaging_PublishFollowMeServer) error
 	SubscribeFollowMe(SeaweedMessaging_SubscribeFollowMeServer) error
+	// commit the consumed offsets of several partitions together, after the produced messages are acked.
+	// the commit is not transactional with the produced messages: the pipelines get at-least-once delivery
+	CommitOffsets(context.Context, *CommitOffsetsRequest) (*CommitOffsetsResponse, error)
 	mustEmbedUnimplementedSeaweedMessagingServer()
 }
 
@@ -405,6 +421,9 @@ func (UnimplementedSeaweedMessagingServer) PublishFollowMe(SeaweedMessaging_Publ
 func (UnimplementedSeaweedMessagingServer) SubscribeFollowMe(SeaweedMessaging_SubscribeFollowMeServer) error {
 	return status.Errorf(codes.Unimplemented, "method SubscribeFollowMe not implemented")
 }
+func (UnimplementedSeaweedMessagingServer) CommitOffsets(context.Context, *CommitOffsetsRequest) (*CommitOffsetsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CommitOffsets not implemented")
+}
 func (UnimplementedSeaweedMessagingServer) mustEmbedUnimplementedSeaweedMessagingServer() {}
 
 // UnsafeSeaweedMessagingServer may be embedded to opt out of forward compatibility for this service.
@@ -718,6 +737,24 @@ func (x *seaweedMessagingSubscribeFollowMeServer) Recv() (*SubscribeFollowMeRequ
 	return m, nil
 }
 
+func _SeaweedMessaging_CommitOffsets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommitOffsetsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SeaweedMessagingServer).CommitOffsets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SeaweedMessaging_CommitOffsets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SeaweedMessagingServer).CommitOffsets(ctx, req.(*CommitOffsetsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // SeaweedMessaging_ServiceDesc is the grpc.ServiceDesc for SeaweedMessaging service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -757,6 +794,10 @@ var SeaweedMessaging_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CloseSubscribers",
 			Handler:    _SeaweedMessaging_CloseSubscribers_Handler,
 		},
+		{
+			MethodName: "CommitOffsets",
+			Handler:    _SeaweedMessaging_CommitOffsets_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -0,0 +1,114 @@
+package csv
+
+import (
+	gocsv "encoding/csv"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/match"
+)
+
+type Query struct {
+	Field string
+	Op    string
+	Value string
+}
+
+// ParseRecord splits one csv record into its fields.
+func ParseRecord(line string, fieldDelimiter rune) ([]string, error) {
+	reader := gocsv.NewReader(strings.NewReader(line))
+	reader.Comma = fieldDelimiter
+	reader.LazyQuotes = true
+	reader.FieldsPerRecord = -1
+	return reader.Read()
+}
+
+// ParseHeader maps the column names of a header record to their indexes.
+func ParseHeader(fields []string) map[string]int {
+	header := make(map[string]int, len(fields))
+	for i, name := range fields {
+		if _, found := header[name]; !found {
+			header[name] = i
+		}
+	}
+	return header
+}
+
+// ColumnIndex finds a column by its header name, or by its position such as _1 for the first column.
+func ColumnIndex(header map[string]int, name string) (int, bool) {
+	if i, found := header[name]; found {
+		return i, true
+	}
+	if strings.HasPrefix(name, "_") {
+		if position, err := strconv.Atoi(name[1:]); err == nil && position > 0 {
+			return position - 1, true
+		}
+	}
+	return 0, false
+}
+
+// GetField returns the value of a column, and whether the record has the column.
+func GetField(fields []string, header map[string]int, name string) (string, bool) {
+	i, found := ColumnIndex(header, name)
+	if !found || i >= len(fields) {
+		return "", false
+	}
+	return fields[i], true
+}
+
+func QueryCsv(fields []string, header map[string]int, projections []string, query Query) (passedFilter bool, values []string) {
+	if filterCsv(fields, header, query) {
+		passedFilter = true
+		for _, projection := range projections {
+			value, _ := GetField(fields, header, projection)
+			values = append(values, value)
+		}
+		return
+	}
+	return false, nil
+}
+
+func filterCsv(fields []string, header map[string]int, query Query) bool {
+
+	value, found := GetField(fields, header, query.Field)
+	if !found {
+		return false
+	}
+	if query.Op == "" {
+		return true
+	}
+
+	rpv := query.Value
+	switch query.Op {
+	case "%":
+		return match.Match(value, rpv)
+	case "!%":
+		return !match.Match(value, rpv)
+	}
+
+	// csv values have no types, so compare as numbers when both sides look like numbers
+	if valueNum, err := strconv.ParseFloat(value, 64); err == nil {
+		if rpvn, err := strconv.ParseFloat(rpv, 64); err == nil {
+			return compare(valueNum < rpvn, valueNum == rpvn, query.Op)
+		}
+	}
+	return compare(value < rpv, value == rpv, query.Op)
+}
+
+func compare(less, equal bool, op string) bool {
+	switch op {
+	case "=":
+		return equal
+	case "!=":
+		return !equal
+	case "<":
+		return less
+	case "<=":
+		return less || equal
+	case ">":
+		return !less && !equal
+	case ">=":
+		return !less
+	}
+	return false
+}
@@ -0,0 +1,32 @@
+package csv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryCsv(t *testing.T) {
+	header := ParseHeader([]string{"name", "city", "age"})
+
+	fields, err := ParseRecord(`"Doe, John",Paris,42`, ',')
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Doe, John", "Paris", "42"}, fields)
+
+	passed, values := QueryCsv(fields, header, []string{"name", "_3"}, Query{Field: "age", Op: ">", Value: "9"})
+	assert.True(t, passed, "numbers are compared as numbers")
+	assert.Equal(t, []string{"Doe, John", "42"}, values)
+
+	passed, _ = QueryCsv(fields, header, nil, Query{Field: "_2", Op: "=", Value: "Paris"})
+	assert.True(t, passed)
+	passed, _ = QueryCsv(fields, header, nil, Query{Field: "city", Op: "<", Value: "London"})
+	assert.False(t, passed)
+	passed, _ = QueryCsv(fields, header, nil, Query{Field: "name", Op: "%", Value: "Doe*"})
+	assert.True(t, passed)
+	passed, _ = QueryCsv(fields, header, nil, Query{Field: "name", Op: "!%", Value: "Doe*"})
+	assert.False(t, passed)
+	passed, _ = QueryCsv(fields, header, nil, Query{Field: "_4", Op: "=", Value: ""})
+	assert.False(t, passed, "missing column")
+	passed, _ = QueryCsv(fields, nil, nil, Query{Field: "age", Op: ""})
+	assert.False(t, passed, "no header")
+}
@@ -16,16 +16,19 @@ type Query struct {
 
 func QueryJson(jsonLine string, projections []string, query Query) (passedFilter bool, values []sqltypes.Value) {
 	if filterJson(jsonLine, query) {
-		passedFilter = true
-		fields := gjson.GetMany(jsonLine, projections...)
-		for _, f := range fields {
-			values = append(values, sqltypes.MakeTrusted(sqltypes.Type(f.Type), sqltypes.StringToBytes(f.Raw)))
-		}
-		return
+		return true, GetValues(jsonLine, projections)
 	}
 	return false, nil
 }
 
+func GetValues(jsonLine string, projections []string) (values []sqltypes.Value) {
+	fields := gjson.GetMany(jsonLine, projections...)
+	for _, f := range fields {
+		values = append(values, sqltypes.MakeTrusted(sqltypes.Type(f.Type), sqltypes.StringToBytes(f.Raw)))
+	}
+	return
+}
+
 func filterJson(jsonLine string, query Query) bool {
 
 	value := gjson.Get(jsonLine, query.Field)
@@ -1,6 +1,10 @@
 package json
 
-import "github.com/seaweedfs/seaweedfs/weed/query/sqltypes"
+import (
+	gojson "encoding/json"
+
+	"github.com/seaweedfs/seaweedfs/weed/query/sqltypes"
+)
 
 func ToJson(buf []byte, selections []string, values []sqltypes.Value) []byte {
 	buf = append(buf, '{')
@@ -8,9 +12,15 @@ func ToJson(buf []byte, selections []string, values []sqltypes.Value) []byte {
 		if i > 0 {
 			buf = append(buf, ',')
 		}
-		buf = append(buf, selections[i]...)
+		key, _ := gojson.Marshal(selections[i])
+		buf = append(buf, key...)
 		buf = append(buf, ':')
-		buf = append(buf, value.Raw()...)
+		if raw := value.Raw(); len(raw) > 0 {
+			buf = append(buf, raw...)
+		} else {
+			// the field does not exist
+			buf = append(buf, "null"...)
+		}
 	}
 	buf = append(buf, '}')
 	return buf
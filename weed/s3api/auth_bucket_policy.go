@@ -0,0 +1,521 @@
+package s3api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3_constants"
+)
+
+// BucketPolicy is a bucket policy document, in the json format of AWS IAM policies.
+// https://docs.aws.amazon.com/AmazonS3/latest/userguide/access-policy-language-overview.html
+type BucketPolicy struct {
+	Version   string                   `json:"Version"`
+	Id        string                   `json:"Id,omitempty"`
+	Statement []*BucketPolicyStatement `json:"Statement"`
+}
+
+type BucketPolicyStatement struct {
+	Sid       string                              `json:"Sid,omitempty"`
+	Effect    string                              `json:"Effect"`
+	Principal *PolicyPrincipal                    `json:"Principal,omitempty"`
+	Action    StringOrSlice                       `json:"Action"`
+	Resource  StringOrSlice                       `json:"Resource"`
+	Condition map[string]map[string]StringOrSlice `json:"Condition,omitempty"`
+}
+
+// PolicyPrincipal is either "*" or {"AWS": "..." | ["...", ...]}
+type PolicyPrincipal struct {
+	AWS StringOrSlice `json:"AWS,omitempty"`
+}
+
+// StringOrSlice accepts a json string, number, bool, or an array of them
+type StringOrSlice []string
+
+const (
+	PolicyEffectAllow = "Allow"
+	PolicyEffectDeny  = "Deny"
+
+	bucketPolicyMaxSize = 20 * 1024
+)
+
+type policyDecision int
+
+const (
+	policyNotApplicable policyDecision = iota
+	policyAllow
+	policyDeny
+)
+
+func (p *PolicyPrincipal) UnmarshalJSON(data []byte) error {
+	var any string
+	if err := json.Unmarshal(data, &any); err == nil {
+		if any != "*" {
+			return fmt.Errorf("invalid principal %q", any)
+		}
+		p.AWS = StringOrSlice{"*"}
+		return nil
+	}
+	type principal PolicyPrincipal
+	return json.Unmarshal(data, (*principal)(p))
+}
+
+func (s *StringOrSlice) UnmarshalJSON(data []byte) error {
+	var values []interface{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		var value interface{}
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+		values = []interface{}{value}
+	}
+	*s = nil
+	for _, v := range values {
+		switch t := v.(type) {
+		case string:
+			*s = append(*s, t)
+		case bool, float64:
+			*s = append(*s, fmt.Sprint(t))
+		default:
+			return fmt.Errorf("unexpected value %v", v)
+		}
+	}
+	return nil
+}
+
+// ParseBucketPolicy parses and validates the policy of the bucket
+func ParseBucketPolicy(bucket string, data []byte) (*BucketPolicy, error) {
+	if len(data) > bucketPolicyMaxSize {
+		return nil, fmt.Errorf("policy exceeds %d bytes", bucketPolicyMaxSize)
+	}
+	policy := &BucketPolicy{}
+	if err := json.Unmarshal(data, policy); err != nil {
+		return nil, err
+	}
+	if len(policy.Statement) == 0 {
+		return nil, fmt.Errorf("policy has no statement")
+	}
+	bucketArn := "arn:aws:s3:::" + bucket
+	for i, statement := range policy.Statement {
+		if statement.Effect != PolicyEffectAllow && statement.Effect != PolicyEffectDeny {
+			return nil, fmt.Errorf("statement %d: invalid effect %q", i, statement.Effect)
+		}
+		if statement.Principal == nil || len(statement.Principal.AWS) == 0 {
+			return nil, fmt.Errorf("statement %d: missing principal", i)
+		}
+		if len(statement.Action) == 0 {
+			return nil, fmt.Errorf("statement %d: missing action", i)
+		}
+		for _, action := range statement.Action {
+			if action != "*" && !strings.HasPrefix(strings.ToLower(action), "s3:") {
+				return nil, fmt.Errorf("statement %d: invalid action %q", i, action)
+			}
+		}
+		if len(statement.Resource) == 0 {
+			return nil, fmt.Errorf("statement %d: missing resource", i)
+		}
+		for _, resource := range statement.Resource {
+			if resource != bucketArn && !strings.HasPrefix(resource, bucketArn+"/") {
+				return nil, fmt.Errorf("statement %d: resource %q is outside of bucket %s", i, resource, bucket)
+			}
+		}
+		for operator := range statement.Condition {
+			if _, found := conditionOperators[strings.TrimSuffix(operator, "IfExists")]; !found {
+				return nil, fmt.Errorf("statement %d: unsupported condition operator %q", i, operator)
+			}
+		}
+	}
+	return policy, nil
+}
+
+// policyRequest is what a bucket policy is evaluated against
+type policyRequest struct {
+	// names the requester is known as, empty for anonymous requests
+	principals []string
+	// the s3 action, e.g. s3:GetObject
+	action string
+	// the arn of the bucket or object
+	resource string
+	// condition keys, in lower case
+	conditions map[string]string
+}
+
+// evaluate returns policyDeny if any matching statement denies the request,
+// policyAllow if at least one matching statement allows it.
+func (p *BucketPolicy) evaluate(req *policyRequest) policyDecision {
+	decision := policyNotApplicable
+	if req.action == "" {
+		// the apis not named in policies are only granted by the identity permissions
+		return decision
+	}
+	for _, statement := range p.Statement {
+		if !statement.matches(req) {
+			continue
+		}
+		if statement.Effect == PolicyEffectDeny {
+			return policyDeny
+		}
+		decision = policyAllow
+	}
+	return decision
+}
+
+func (s *BucketPolicyStatement) matches(req *policyRequest) bool {
+	return s.matchesPrincipal(req.principals) &&
+		matchesAnyPattern(s.Action, req.action, true) &&
+		matchesAnyPattern(s.Resource, req.resource, false) &&
+		s.matchesConditions(req.conditions)
+}
+
+func (s *BucketPolicyStatement) matchesPrincipal(principals []string) bool {
+	for _, p := range s.Principal.AWS {
+		if p == "*" {
+			return true
+		}
+		for _, principal := range principals {
+			if p == principal {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchesAnyPattern(patterns []string, value string, ignoreCase bool) bool {
+	if ignoreCase {
+		value = strings.ToLower(value)
+	}
+	for _, pattern := range patterns {
+		if ignoreCase {
+			pattern = strings.ToLower(pattern)
+		}
+		if pattern == "*" || wildcardMatch(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// wildcardMatch matches '*' with any sequence of characters, including '/', and '?' with any single character
+func wildcardMatch(pattern, value string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			pattern = strings.TrimLeft(pattern, "*")
+			if pattern == "" {
+				return true
+			}
+			for i := 0; i <= len(value); i++ {
+				if wildcardMatch(pattern, value[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if value == "" {
+				return false
+			}
+		default:
+			if value == "" || pattern[0] != value[0] {
+				return false
+			}
+		}
+		pattern, value = pattern[1:], value[1:]
+	}
+	return value == ""
+}
+
+type conditionOperator func(values []string, value string) bool
+
+var conditionOperators = map[string]conditionOperator{
+	"StringEquals": func(values []string, value string) bool {
+		return containsString(values, value, false)
+	},
+	"StringNotEquals": func(values []string, value string) bool {
+		return !containsString(values, value, false)
+	},
+	"StringEqualsIgnoreCase": func(values []string, value string) bool {
+		return containsString(values, value, true)
+	},
+	"StringNotEqualsIgnoreCase": func(values []string, value string) bool {
+		return !containsString(values, value, true)
+	},
+	"StringLike": func(values []string, value string) bool {
+		return matchesAnyPattern(values, value, false)
+	},
+	"StringNotLike": func(values []string, value string) bool {
+		return !matchesAnyPattern(values, value, false)
+	},
+	"Bool": func(values []string, value string) bool {
+		return containsString(values, value, true)
+	},
+	"IpAddress": func(values []string, value string) bool {
+		return matchesAnyCidr(values, value)
+	},
+	"NotIpAddress": func(values []string, value string) bool {
+		return !matchesAnyCidr(values, value)
+	},
+}
+
+// matchesConditions requires all conditions to be met. A missing condition key fails
+// the condition, unless the operator is negated or ends with IfExists.
+func (s *BucketPolicyStatement) matchesConditions(conditions map[string]string) bool {
+	for operatorName, keyValues := range s.Condition {
+		operator := conditionOperators[strings.TrimSuffix(operatorName, "IfExists")]
+		ifExists := strings.HasSuffix(operatorName, "IfExists")
+		for key, values := range keyValues {
+			value, found := conditions[strings.ToLower(key)]
+			if !found {
+				if ifExists || strings.Contains(operatorName, "Not") {
+					continue
+				}
+				return false
+			}
+			if operator == nil || !operator(values, value) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func containsString(values []string, value string, ignoreCase bool) bool {
+	for _, v := range values {
+		if v == value || ignoreCase && strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAnyCidr(cidrs []string, value string) bool {
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return false
+	}
+	for _, cidr := range cidrs {
+		if !strings.Contains(cidr, "/") {
+			if net.ParseIP(cidr).Equal(ip) {
+				return true
+			}
+			continue
+		}
+		if _, ipNet, err := net.ParseCIDR(cidr); err == nil && ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// requestSource returns the client address and whether the request is over TLS.
+// The X-Forwarded-For and X-Forwarded-Proto headers can be set by any client,
+// so they are only used when the request comes from one of the trusted proxies.
+func requestSource(r *http.Request, trustedProxies []string) (sourceIp string, isSecureTransport bool) {
+	sourceIp, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		sourceIp = r.RemoteAddr
+	}
+	isSecureTransport = r.TLS != nil
+	if !matchesAnyCidr(trustedProxies, sourceIp) {
+		return
+	}
+	// the proxies append the address they receive from, so the client is the last address not of a trusted proxy
+	if forwardedFor := r.Header.Get("X-Forwarded-For"); forwardedFor != "" {
+		addresses := strings.Split(forwardedFor, ",")
+		for i := len(addresses) - 1; i >= 0; i-- {
+			sourceIp = strings.TrimSpace(addresses[i])
+			if !matchesAnyCidr(trustedProxies, sourceIp) {
+				break
+			}
+		}
+	}
+	if forwardedProto := r.Header.Get("X-Forwarded-Proto"); forwardedProto != "" {
+		isSecureTransport = strings.EqualFold(forwardedProto, "https")
+	}
+	return
+}
+
+// newPolicyRequest collects what the bucket policy is evaluated against from the http request
+func newPolicyRequest(r *http.Request, identity *Identity, bucket, object string, trustedProxies []string) *policyRequest {
+	req := &policyRequest{
+		action:     s3ActionOfRequest(r, object),
+		resource:   "arn:aws:s3:::" + bucket,
+		conditions: make(map[string]string),
+	}
+	if object != "" && object != "/" {
+		req.resource += object
+	}
+
+	if identity != nil && !identity.isAnonymous() {
+		req.principals = append(req.principals, identity.Name)
+		if identity.Account != nil {
+			req.principals = append(req.principals,
+				identity.Account.Id,
+				"arn:aws:iam::"+identity.Account.Id+":root",
+				"arn:aws:iam::"+identity.Account.Id+":user/"+identity.Name)
+		}
+		req.conditions["aws:username"] = identity.Name
+	}
+
+	sourceIp, isSecureTransport := requestSource(r, trustedProxies)
+	if sourceIp != "" {
+		req.conditions["aws:sourceip"] = sourceIp
+	}
+	req.conditions["aws:securetransport"] = fmt.Sprint(isSecureTransport)
+	if referer := r.Header.Get("Referer"); referer != "" {
+		req.conditions["aws:referer"] = referer
+	}
+	if userAgent := r.Header.Get("User-Agent"); userAgent != "" {
+		req.conditions["aws:useragent"] = userAgent
+	}
+
+	query := r.URL.Query()
+	for _, key := range []string{"prefix", "delimiter", "max-keys"} {
+		if query.Has(key) {
+			req.conditions["s3:"+key] = query.Get(key)
+		}
+	}
+	if query.Has("versionId") {
+		req.conditions["s3:versionid"] = query.Get("versionId")
+	}
+	for _, header := range []string{s3_constants.AmzCannedAcl, s3_constants.AmzStorageClass, "X-Amz-Server-Side-Encryption", "X-Amz-Copy-Source"} {
+		if v := r.Header.Get(header); v != "" {
+			req.conditions["s3:"+strings.ToLower(header)] = v
+		}
+	}
+
+	return req
+}
+
+// s3ActionOfRequest names the S3 api action of the request, as used in policies, e.g. s3:GetObject.
+// The first matching query parameter decides, the last entry of each list is the default.
+// It is empty for the subresources not mapped to an action, which no policy statement matches.
+func s3ActionOfRequest(r *http.Request, object string) string {
+	query := r.URL.Query()
+	actions := bucketActions
+	if object != "" && object != "/" {
+		actions = objectActions
+	}
+	for _, candidate := range actions[r.Method] {
+		if candidate.query != "" && query.Has(candidate.query) {
+			return "s3:" + candidate.action
+		}
+		if candidate.query == "" {
+			for _, subresource := range s3Subresources {
+				if query.Has(subresource) {
+					return ""
+				}
+			}
+			return "s3:" + candidate.action
+		}
+	}
+	return ""
+}
+
+// s3Subresources are the query parameters selecting another api than the plain bucket or object one
+var s3Subresources = []string{
+	"accelerate", "acl", "analytics", "attributes", "cors", "delete", "encryption", "intelligent-tiering",
+	"inventory", "legal-hold", "lifecycle", "location", "logging", "metrics", "notification", "object-lock",
+	"ownershipControls", "policy", "policyStatus", "publicAccessBlock", "replication", "requestPayment",
+	"restore", "retention", "select", "tagging", "torrent", "uploadId", "uploads", "versioning", "versions", "website",
+}
+
+type s3ActionCandidate struct {
+	query  string
+	action string
+}
+
+var objectActions = map[string][]s3ActionCandidate{
+	http.MethodGet: {
+		{"tagging", "GetObjectTagging"},
+		{"acl", "GetObjectAcl"},
+		{"retention", "GetObjectRetention"},
+		{"legal-hold", "GetObjectLegalHold"},
+		{"uploadId", "ListMultipartUploadParts"},
+		{"attributes", "GetObjectAttributes"},
+		{"torrent", "GetObjectTorrent"},
+		{"versionId", "GetObjectVersion"},
+		{"", "GetObject"},
+	},
+	http.MethodHead: {
+		{"versionId", "GetObjectVersion"},
+		{"", "GetObject"},
+	},
+	http.MethodPut: {
+		{"tagging", "PutObjectTagging"},
+		{"acl", "PutObjectAcl"},
+		{"retention", "PutObjectRetention"},
+		{"legal-hold", "PutObjectLegalHold"},
+		{"uploadId", "PutObject"},
+		{"", "PutObject"},
+	},
+	http.MethodPost: {
+		{"select", "GetObject"},
+		{"uploads", "PutObject"},
+		{"uploadId", "PutObject"},
+		{"restore", "RestoreObject"},
+		{"", "PutObject"},
+	},
+	http.MethodDelete: {
+		{"tagging", "DeleteObjectTagging"},
+		{"uploadId", "AbortMultipartUpload"},
+		{"versionId", "DeleteObjectVersion"},
+		{"", "DeleteObject"},
+	},
+}
+
+var bucketActions = map[string][]s3ActionCandidate{
+	http.MethodGet: {
+		{"acl", "GetBucketAcl"},
+		{"policy", "GetBucketPolicy"},
+		{"cors", "GetBucketCORS"},
+		{"lifecycle", "GetLifecycleConfiguration"},
+		{"location", "GetBucketLocation"},
+		{"versioning", "GetBucketVersioning"},
+		{"versions", "ListBucketVersions"},
+		{"uploads", "ListBucketMultipartUploads"},
+		{"object-lock", "GetBucketObjectLockConfiguration"},
+		{"ownershipControls", "GetBucketOwnershipControls"},
+		{"requestPayment", "GetBucketRequestPayment"},
+		{"notification", "GetBucketNotification"},
+		{"website", "GetBucketWebsite"},
+		{"encryption", "GetEncryptionConfiguration"},
+		{"tagging", "GetBucketTagging"},
+		{"policyStatus", "GetBucketPolicyStatus"},
+		{"", "ListBucket"},
+	},
+	http.MethodHead: {
+		{"", "ListBucket"},
+	},
+	http.MethodPut: {
+		{"acl", "PutBucketAcl"},
+		{"policy", "PutBucketPolicy"},
+		{"cors", "PutBucketCORS"},
+		{"lifecycle", "PutLifecycleConfiguration"},
+		{"versioning", "PutBucketVersioning"},
+		{"object-lock", "PutBucketObjectLockConfiguration"},
+		{"ownershipControls", "PutBucketOwnershipControls"},
+		{"notification", "PutBucketNotification"},
+		{"website", "PutBucketWebsite"},
+		{"encryption", "PutEncryptionConfiguration"},
+		{"tagging", "PutBucketTagging"},
+		{"", "CreateBucket"},
+	},
+	http.MethodPost: {
+		{"delete", "DeleteObject"},
+		{"", "PutObject"},
+	},
+	http.MethodDelete: {
+		{"policy", "DeleteBucketPolicy"},
+		{"cors", "PutBucketCORS"},
+		{"lifecycle", "PutLifecycleConfiguration"},
+		{"ownershipControls", "PutBucketOwnershipControls"},
+		{"website", "DeleteBucketWebsite"},
+		{"encryption", "PutEncryptionConfiguration"},
+		{"tagging", "PutBucketTagging"},
+		{"", "DeleteBucket"},
+	},
+}
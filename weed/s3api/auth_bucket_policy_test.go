@@ -0,0 +1,219 @@
+package s3api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/seaweedfs/seaweedfs/weed/pb/iam_pb"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3_constants"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3err"
+	"github.com/stretchr/testify/assert"
+)
+
+const testBucketPolicy = `{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Sid": "PublicRead",
+      "Effect": "Allow",
+      "Principal": "*",
+      "Action": ["s3:GetObject"],
+      "Resource": "arn:aws:s3:::bucket/public/*"
+    },
+    {
+      "Sid": "TenantList",
+      "Effect": "Allow",
+      "Principal": {"AWS": "arn:aws:iam::tenant:root"},
+      "Action": "s3:ListBucket",
+      "Resource": "arn:aws:s3:::bucket",
+      "Condition": {"StringLike": {"s3:prefix": "shared/*"}}
+    },
+    {
+      "Sid": "DenyPlainHttpWrites",
+      "Effect": "Deny",
+      "Principal": {"AWS": "*"},
+      "Action": "s3:Put*",
+      "Resource": "arn:aws:s3:::bucket/*",
+      "Condition": {"Bool": {"aws:SecureTransport": false}}
+    },
+    {
+      "Sid": "OfficeOnly",
+      "Effect": "Deny",
+      "Principal": "*",
+      "Action": "s3:*",
+      "Resource": "arn:aws:s3:::bucket/office/*",
+      "Condition": {"NotIpAddress": {"aws:SourceIp": ["10.0.0.0/8"]}}
+    }
+  ]
+}`
+
+func TestParseBucketPolicy(t *testing.T) {
+	policy, err := ParseBucketPolicy("bucket", []byte(testBucketPolicy))
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(policy.Statement))
+	assert.Equal(t, StringOrSlice{"*"}, policy.Statement[0].Principal.AWS)
+	assert.Equal(t, StringOrSlice{"false"}, policy.Statement[2].Condition["Bool"]["aws:SecureTransport"])
+
+	_, err = ParseBucketPolicy("other", []byte(testBucketPolicy))
+	assert.NotNil(t, err, "resources must belong to the bucket")
+
+	_, err = ParseBucketPolicy("bucket", []byte(`{"Statement":[{"Effect":"Maybe","Principal":"*","Action":"s3:*","Resource":"arn:aws:s3:::bucket"}]}`))
+	assert.NotNil(t, err)
+
+	_, err = ParseBucketPolicy("bucket", []byte(`not json`))
+	assert.NotNil(t, err)
+}
+
+func TestEvaluateBucketPolicy(t *testing.T) {
+	policy, err := ParseBucketPolicy("bucket", []byte(testBucketPolicy))
+	assert.Nil(t, err)
+
+	anonymous := &Identity{Account: &AccountAnonymous}
+	tenant := &Identity{Name: "someone", Account: &Account{Id: "tenant"}}
+
+	tests := []struct {
+		name     string
+		method   string
+		url      string
+		identity *Identity
+		remote   string
+		want     policyDecision
+	}{
+		{"public read", http.MethodGet, "/bucket/public/a/b.txt", anonymous, "1.2.3.4:80", policyAllow},
+		{"private read", http.MethodGet, "/bucket/private/a.txt", anonymous, "1.2.3.4:80", policyNotApplicable},
+		{"tenant list shared", http.MethodGet, "/bucket?prefix=shared/x", tenant, "1.2.3.4:80", policyAllow},
+		{"tenant list other", http.MethodGet, "/bucket?prefix=other/", tenant, "1.2.3.4:80", policyNotApplicable},
+		{"anonymous list shared", http.MethodGet, "/bucket?prefix=shared/x", anonymous, "1.2.3.4:80", policyNotApplicable},
+		{"tenant notification is not a list", http.MethodGet, "/bucket?notification&prefix=shared/x", tenant, "1.2.3.4:80", policyNotApplicable},
+		{"plain http write", http.MethodPut, "/bucket/public/a.txt", tenant, "1.2.3.4:80", policyDeny},
+		{"office from outside", http.MethodGet, "/bucket/office/a.txt", tenant, "1.2.3.4:80", policyDeny},
+		{"office from inside", http.MethodGet, "/bucket/office/a.txt", tenant, "10.1.2.3:80", policyNotApplicable},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(tt.method, tt.url, nil)
+		r.RemoteAddr = tt.remote
+		bucket, object := routeBucketAndObject(r)
+		assert.Equal(t, tt.want, policy.evaluate(newPolicyRequest(r, tt.identity, bucket, object, nil)), tt.name)
+	}
+}
+
+func TestAuthWithBucketPolicy(t *testing.T) {
+	iam := &IdentityAccessManagement{}
+	assert.Nil(t, iam.loadS3ApiConfiguration(&iam_pb.S3ApiConfiguration{}))
+	policy, _ := ParseBucketPolicy("bucket", []byte(testBucketPolicy))
+	iam.bucketPolicyLookup = func(bucket string) *BucketPolicy {
+		if bucket == "bucket" {
+			return policy
+		}
+		return nil
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/bucket/public/a.txt", nil)
+	r = mux.SetURLVars(r, map[string]string{"bucket": "bucket", "object": "public/a.txt"})
+	_, errCode := iam.authRequest(r, s3_constants.ACTION_READ)
+	assert.Equal(t, s3err.ErrNone, errCode)
+
+	r = httptest.NewRequest(http.MethodGet, "/bucket/private/a.txt", nil)
+	r = mux.SetURLVars(r, map[string]string{"bucket": "bucket", "object": "private/a.txt"})
+	_, errCode = iam.authRequest(r, s3_constants.ACTION_READ)
+	assert.Equal(t, s3err.ErrAccessDenied, errCode)
+}
+
+func TestAuthAdminWithBucketPolicy(t *testing.T) {
+	iam := &IdentityAccessManagement{}
+	assert.Nil(t, iam.loadS3ApiConfiguration(&iam_pb.S3ApiConfiguration{}))
+	policy, err := ParseBucketPolicy("bucket", []byte(`{"Statement":[{"Effect":"Allow","Principal":"*","Action":"s3:*","Resource":["arn:aws:s3:::bucket","arn:aws:s3:::bucket/*"]}]}`))
+	assert.Nil(t, err)
+	iam.bucketPolicyLookup = func(bucket string) *BucketPolicy {
+		return policy
+	}
+
+	r := httptest.NewRequest(http.MethodPut, "/bucket?policy", nil)
+	r = mux.SetURLVars(r, map[string]string{"bucket": "bucket"})
+	_, errCode := iam.authRequest(r, s3_constants.ACTION_ADMIN)
+	assert.Equal(t, s3err.ErrAccessDenied, errCode, "the bucket policy can not grant the admin apis")
+
+	r = httptest.NewRequest(http.MethodPut, "/bucket?tagging", nil)
+	r = mux.SetURLVars(r, map[string]string{"bucket": "bucket"})
+	_, errCode = iam.authRequest(r, s3_constants.ACTION_TAGGING)
+	assert.Equal(t, s3err.ErrNone, errCode)
+}
+
+func TestS3ActionOfRequest(t *testing.T) {
+	tests := []struct {
+		method string
+		url    string
+		want   string
+	}{
+		{http.MethodGet, "/bucket?prefix=a", "s3:ListBucket"},
+		{http.MethodGet, "/bucket?notification", "s3:GetBucketNotification"},
+		{http.MethodPut, "/bucket?website", "s3:PutBucketWebsite"},
+		{http.MethodDelete, "/bucket?encryption", "s3:PutEncryptionConfiguration"},
+		{http.MethodGet, "/bucket?replication", ""},
+		{http.MethodPut, "/bucket?logging", ""},
+		{http.MethodDelete, "/bucket?metrics", ""},
+		{http.MethodGet, "/bucket/a.txt?versionId=1", "s3:GetObjectVersion"},
+		{http.MethodPut, "/bucket/a.txt?partNumber=1&uploadId=2", "s3:PutObject"},
+		{http.MethodPost, "/bucket/a.txt?uploads", "s3:PutObject"},
+		{http.MethodGet, "/bucket/a.txt?restore", ""},
+		{http.MethodPatch, "/bucket/a.txt", ""},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(tt.method, tt.url, nil)
+		_, object := routeBucketAndObject(r)
+		assert.Equal(t, tt.want, s3ActionOfRequest(r, object), tt.method+" "+tt.url)
+	}
+}
+
+func TestWildcardMatch(t *testing.T) {
+	assert.True(t, wildcardMatch("arn:aws:s3:::bucket/*", "arn:aws:s3:::bucket/a/b/c"))
+	assert.True(t, wildcardMatch("a?c*", "abcdef"))
+	assert.False(t, wildcardMatch("a?c", "ac"))
+	assert.False(t, wildcardMatch("arn:aws:s3:::bucket/*", "arn:aws:s3:::bucket2/a"))
+}
+
+func routeBucketAndObject(r *http.Request) (bucket, object string) {
+	router := mux.NewRouter()
+	router.Path("/{bucket}/{object:.+}").HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+	router.Path("/{bucket}").HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+	var match mux.RouteMatch
+	if router.Match(r, &match) {
+		r = mux.SetURLVars(r, match.Vars)
+	}
+	return s3_constants.GetBucketAndObject(r)
+}
+
+func TestPolicyRequestSource(t *testing.T) {
+	trustedProxies := []string{"10.0.0.1", "192.168.0.0/16"}
+	tests := []struct {
+		name           string
+		remote         string
+		forwardedFor   string
+		forwardedProto string
+		trustedProxies []string
+		wantIp         string
+		wantSecure     bool
+	}{
+		{"direct", "1.2.3.4:80", "", "", nil, "1.2.3.4", false},
+		{"forged headers without trusted proxies", "1.2.3.4:80", "10.1.2.3", "https", nil, "1.2.3.4", false},
+		{"forged headers from an untrusted client", "1.2.3.4:80", "10.1.2.3", "https", trustedProxies, "1.2.3.4", false},
+		{"trusted proxy", "10.0.0.1:80", "5.6.7.8", "https", trustedProxies, "5.6.7.8", true},
+		{"trusted proxies chain", "10.0.0.1:80", "10.1.2.3, 5.6.7.8, 192.168.1.1", "http", trustedProxies, "5.6.7.8", false},
+		{"trusted proxy without headers", "192.168.1.1:80", "", "", trustedProxies, "192.168.1.1", false},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/bucket/a", nil)
+		r.RemoteAddr = tt.remote
+		if tt.forwardedFor != "" {
+			r.Header.Set("X-Forwarded-For", tt.forwardedFor)
+		}
+		if tt.forwardedProto != "" {
+			r.Header.Set("X-Forwarded-Proto", tt.forwardedProto)
+		}
+		sourceIp, isSecureTransport := requestSource(r, tt.trustedProxies)
+		assert.Equal(t, tt.wantIp, sourceIp, tt.name)
+		assert.Equal(t, tt.wantSecure, isSecureTransport, tt.name)
+	}
+}
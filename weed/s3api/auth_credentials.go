@@ -1,12 +1,14 @@
 package s3api
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"os"
 	"strings"
 	"sync"
 
+	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/seaweedfs/seaweedfs/weed/filer"
 	"github.com/seaweedfs/seaweedfs/weed/glog"
 	"github.com/seaweedfs/seaweedfs/weed/pb"
@@ -35,6 +37,13 @@ type IdentityAccessManagement struct {
 	hashMu            sync.RWMutex
 	domain            string
 	isAuthEnabled     bool
+	trustedProxies    []string
+
+	// looks up the policy of a bucket, nil if the bucket has no policy
+	bucketPolicyLookup func(bucket string) *BucketPolicy
+
+	// looks up the owner and grants of an object version, no grants if the object has no acl
+	objectAclLookup func(r *http.Request, bucket, object, versionId string) (ownerId string, grants []*s3.Grant)
 }
 
 type Identity struct {
@@ -115,9 +124,10 @@ func (action Action) getPermission() Permission {
 
 func NewIdentityAccessManagement(option *S3ApiServerOption) *IdentityAccessManagement {
 	iam := &IdentityAccessManagement{
-		domain:       option.DomainName,
-		hashes:       make(map[string]*sync.Pool),
-		hashCounters: make(map[string]*int32),
+		domain:         option.DomainName,
+		hashes:         make(map[string]*sync.Pool),
+		hashCounters:   make(map[string]*int32),
+		trustedProxies: option.TrustedProxies,
 	}
 	if option.Config != "" {
 		if err := iam.loadS3ApiConfigurationFromFile(option.Config); err != nil {
@@ -316,16 +326,21 @@ func (iam *IdentityAccessManagement) Auth(f http.HandlerFunc, action Action) htt
 			return
 		}
 
+		if iam.objectAclLookup != nil && (r.Method == http.MethodGet || r.Method == http.MethodHead) {
+			r = withObjectLocationCache(r)
+		}
 		identity, errCode := iam.authRequest(r, action)
 		glog.V(3).Infof("auth error: %v", errCode)
 		if errCode == s3err.ErrNone {
+			// the identity headers sent by the client are never trusted
+			r.Header.Del(s3_constants.AmzIdentityId)
+			r.Header.Del(s3_constants.AmzIsAdmin)
 			if identity != nil && identity.Name != "" {
 				r.Header.Set(s3_constants.AmzIdentityId, identity.Name)
 				if identity.isAdmin() {
 					r.Header.Set(s3_constants.AmzIsAdmin, "true")
-				} else if _, ok := r.Header[s3_constants.AmzIsAdmin]; ok {
-					r.Header.Del(s3_constants.AmzIsAdmin)
 				}
+				r = r.WithContext(context.WithValue(r.Context(), authenticatedIdentityKey{}, identity))
 			}
 			f(w, r)
 			return
@@ -334,12 +349,22 @@ func (iam *IdentityAccessManagement) Auth(f http.HandlerFunc, action Action) htt
 	}
 }
 
+type authenticatedIdentityKey struct{}
+
+// getAuthenticatedIdentity returns the identity authenticated by Auth, or nil for anonymous requests
+func getAuthenticatedIdentity(r *http.Request) *Identity {
+	identity, _ := r.Context().Value(authenticatedIdentityKey{}).(*Identity)
+	return identity
+}
+
 // check whether the request has valid access keys
 func (iam *IdentityAccessManagement) authRequest(r *http.Request, action Action) (*Identity, s3err.ErrorCode) {
 	var identity *Identity
 	var s3Err s3err.ErrorCode
 	var found bool
 	var authType string
+	// the account id is only set after the request is authenticated
+	r.Header.Del(s3_constants.AmzAccountId)
 	switch getRequestAuthType(r) {
 	case authTypeStreamingSigned:
 		return identity, s3err.ErrNone
@@ -366,8 +391,8 @@ func (iam *IdentityAccessManagement) authRequest(r *http.Request, action Action)
 	case authTypeAnonymous:
 		authType = "Anonymous"
 		if identity, found = iam.lookupAnonymous(); !found {
-			r.Header.Set(s3_constants.AmzAuthType, authType)
-			return identity, s3err.ErrAccessDenied
+			// anonymous requests can still be allowed by bucket policies
+			identity = &Identity{Account: &AccountAnonymous}
 		}
 	default:
 		return identity, s3err.ErrNotImplemented
@@ -384,8 +409,19 @@ func (iam *IdentityAccessManagement) authRequest(r *http.Request, action Action)
 
 	bucket, object := s3_constants.GetBucketAndObject(r)
 
-	if !identity.canDo(action, bucket, object) {
+	switch iam.evaluateBucketPolicy(r, identity, bucket, object) {
+	case policyDeny:
+		glog.V(3).Infof("identity %s is denied by the policy of bucket %s", identity.Name, bucket)
 		return identity, s3err.ErrAccessDenied
+	case policyAllow:
+		// granted by the bucket policy, except the admin apis which could widen the access of the identity
+		if action == s3_constants.ACTION_ADMIN && !identity.canDo(action, bucket, object) {
+			return identity, s3err.ErrAccessDenied
+		}
+	default:
+		if !iam.canDoWithObjectAcl(r, identity, action, bucket, object) {
+			return identity, s3err.ErrAccessDenied
+		}
 	}
 
 	r.Header.Set(s3_constants.AmzAccountId, identity.Account.Id)
@@ -484,6 +520,61 @@ func (identity *Identity) canDo(action Action, bucket string, objectKey string)
 	return false
 }
 
+// evaluateBucketPolicy checks the request against the policy of the bucket, if any
+func (iam *IdentityAccessManagement) evaluateBucketPolicy(r *http.Request, identity *Identity, bucket, object string) policyDecision {
+	if bucket == "" || iam.bucketPolicyLookup == nil {
+		return policyNotApplicable
+	}
+	policy := iam.bucketPolicyLookup(bucket)
+	if policy == nil {
+		return policyNotApplicable
+	}
+	return policy.evaluate(newPolicyRequest(r, identity, bucket, object, iam.trustedProxies))
+}
+
+// canDoWithObjectAcl checks the permissions of the identity, which the object acl can only add to
+func (iam *IdentityAccessManagement) canDoWithObjectAcl(r *http.Request, identity *Identity, action Action, bucket, object string) bool {
+	if identity.canDo(action, bucket, object) {
+		return true
+	}
+	granted, _ := iam.evaluateObjectAcl(r, identity, action, bucket, object)
+	return granted
+}
+
+// evaluateObjectAcl checks the reads of anonymous and other accounts against the acl of the object.
+// found is false if the object has no acl, and the permissions of the identity apply.
+func (iam *IdentityAccessManagement) evaluateObjectAcl(r *http.Request, identity *Identity, action Action, bucket, object string) (granted, found bool) {
+	if iam.objectAclLookup == nil || identity.isAdmin() || bucket == "" || object == "/" {
+		return false, false
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false, false
+	}
+	var permission string
+	switch action {
+	case s3_constants.ACTION_READ:
+		permission = s3_constants.PermissionRead
+	case s3_constants.ACTION_READ_ACP:
+		permission = s3_constants.PermissionReadAcp
+	default:
+		return false, false
+	}
+
+	ownerId, grants := iam.objectAclLookup(r, bucket, object, r.URL.Query().Get("versionId"))
+	if len(grants) == 0 || identity.Account.Id == ownerId {
+		return false, false
+	}
+	for _, reqGrant := range DetermineReqGrants(identity.Account.Id, permission) {
+		for _, grant := range grants {
+			if GrantEquals(reqGrant, grant) {
+				return true, true
+			}
+		}
+	}
+	glog.V(3).Infof("identity %s is not granted %s on %s by the object acl", identity.Name, permission, bucket+object)
+	return false, true
+}
+
 func (identity *Identity) isAdmin() bool {
 	for _, a := range identity.Actions {
 		if a == "Admin" {
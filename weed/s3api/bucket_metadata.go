@@ -2,6 +2,7 @@ package s3api
 
 import (
 	"encoding/json"
+	"encoding/xml"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/seaweedfs/seaweedfs/weed/glog"
 	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
@@ -38,6 +39,31 @@ type BucketMetaData struct {
 
 	// A list of grants for access controls.
 	Acl []*s3.Grant `locationName:"AccessControlList" locationNameList:"Grant" type:"list"`
+
+	// Versioning state of the bucket, "Enabled" or "Suspended".
+	// Empty if versioning has never been configured.
+	Versioning string
+
+	// Object Lock configuration of the bucket, nil if Object Lock is not enabled.
+	ObjectLock *ObjectLockConfiguration
+
+	// Bucket policy, nil if the bucket has no policy.
+	Policy *BucketPolicy
+
+	// CORS configuration of the bucket, nil if the bucket has no CORS rules.
+	Cors *CORSConfiguration
+
+	// Lifecycle configuration of the bucket, nil if the bucket has no lifecycle rules.
+	Lifecycle *Lifecycle
+
+	// Default encryption of the new objects, nil if the bucket has no default encryption.
+	Encryption *ServerSideEncryptionConfiguration
+
+	// Event notification configuration of the bucket, nil if the bucket sends no notifications.
+	Notification *NotificationConfiguration
+
+	// Static website configuration of the bucket, nil if the bucket is not a website.
+	Website *WebsiteConfiguration
 }
 
 type BucketRegistry struct {
@@ -106,6 +132,80 @@ func buildBucketMetadata(accountManager AccountManager, entry *filer_pb.Entry) *
 			}
 		}
 
+		//versioning
+		if versioning, ok := entry.Extended[s3_constants.ExtVersioningKey]; ok {
+			bucketMetadata.Versioning = string(versioning)
+		}
+
+		//object lock
+		if lockConfigBytes, ok := entry.Extended[s3_constants.ExtObjectLockKey]; ok && len(lockConfigBytes) > 0 {
+			lockConfig := &ObjectLockConfiguration{}
+			if err := xml.Unmarshal(lockConfigBytes, lockConfig); err == nil {
+				bucketMetadata.ObjectLock = lockConfig
+			} else {
+				glog.Warningf("Unmarshal object lock configuration: %s, bucket: %s, err: %v", string(lockConfigBytes), bucketMetadata.Name, err)
+			}
+		}
+
+		//bucket policy
+		if policyBytes, ok := entry.Extended[s3_constants.ExtBucketPolicyKey]; ok && len(policyBytes) > 0 {
+			if policy, err := ParseBucketPolicy(entry.Name, policyBytes); err == nil {
+				bucketMetadata.Policy = policy
+			} else {
+				glog.Warningf("Unmarshal bucket policy: %s, bucket: %s, err: %v", string(policyBytes), bucketMetadata.Name, err)
+			}
+		}
+
+		//cors
+		if corsBytes, ok := entry.Extended[s3_constants.ExtCorsKey]; ok && len(corsBytes) > 0 {
+			corsConfig := &CORSConfiguration{}
+			if err := xml.Unmarshal(corsBytes, corsConfig); err == nil {
+				bucketMetadata.Cors = corsConfig
+			} else {
+				glog.Warningf("Unmarshal cors configuration: %s, bucket: %s, err: %v", string(corsBytes), bucketMetadata.Name, err)
+			}
+		}
+
+		//lifecycle
+		if lifecycleBytes, ok := entry.Extended[s3_constants.ExtLifecycleKey]; ok && len(lifecycleBytes) > 0 {
+			lifecycle := &Lifecycle{}
+			if err := xml.Unmarshal(lifecycleBytes, lifecycle); err == nil {
+				bucketMetadata.Lifecycle = lifecycle
+			} else {
+				glog.Warningf("Unmarshal lifecycle configuration: %s, bucket: %s, err: %v", string(lifecycleBytes), bucketMetadata.Name, err)
+			}
+		}
+
+		//default encryption
+		if encryptionBytes, ok := entry.Extended[s3_constants.ExtBucketEncryptionKey]; ok && len(encryptionBytes) > 0 {
+			encryptionConfig := &ServerSideEncryptionConfiguration{}
+			if err := xml.Unmarshal(encryptionBytes, encryptionConfig); err == nil {
+				bucketMetadata.Encryption = encryptionConfig
+			} else {
+				glog.Warningf("Unmarshal encryption configuration: %s, bucket: %s, err: %v", string(encryptionBytes), bucketMetadata.Name, err)
+			}
+		}
+
+		//event notifications
+		if notificationBytes, ok := entry.Extended[s3_constants.ExtNotificationKey]; ok && len(notificationBytes) > 0 {
+			notificationConfig := &NotificationConfiguration{}
+			if err := xml.Unmarshal(notificationBytes, notificationConfig); err == nil {
+				bucketMetadata.Notification = notificationConfig
+			} else {
+				glog.Warningf("Unmarshal notification configuration: %s, bucket: %s, err: %v", string(notificationBytes), bucketMetadata.Name, err)
+			}
+		}
+
+		//static website
+		if websiteBytes, ok := entry.Extended[s3_constants.ExtWebsiteKey]; ok && len(websiteBytes) > 0 {
+			websiteConfig := &WebsiteConfiguration{}
+			if err := xml.Unmarshal(websiteBytes, websiteConfig); err == nil {
+				bucketMetadata.Website = websiteConfig
+			} else {
+				glog.Warningf("Unmarshal website configuration: %s, bucket: %s, err: %v", string(websiteBytes), bucketMetadata.Name, err)
+			}
+		}
+
 		//access control policy
 		//owner
 		acpOwnerBytes, ok := entry.Extended[s3_constants.ExtAmzOwnerKey]
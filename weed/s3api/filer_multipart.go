@@ -23,6 +23,7 @@ import (
 	"github.com/seaweedfs/seaweedfs/weed/filer"
 	"github.com/seaweedfs/seaweedfs/weed/glog"
 	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/util"
 )
 
 const (
@@ -182,6 +183,7 @@ func (s3a *S3ApiServer) completeMultipartUpload(input *s3.CompleteMultipartUploa
 	mime := pentry.Attributes.Mime
 	var finalParts []*filer_pb.FileChunk
 	var offset int64
+	var encryptedParts []ssePart
 	for _, partNumber := range completedPartNumbers {
 		partEntriesByNumber, ok := partEntries[partNumber]
 		if !ok {
@@ -201,6 +203,7 @@ func (s3a *S3ApiServer) completeMultipartUpload(input *s3.CompleteMultipartUploa
 				stats.S3HandlerCounter.WithLabelValues(stats.ErrorCompletedPartEntryMismatch).Inc()
 				continue
 			}
+			partOffset := offset
 			for _, chunk := range entry.GetChunks() {
 				p := &filer_pb.FileChunk{
 					FileId:       chunk.GetFileIdString(),
@@ -213,12 +216,23 @@ func (s3a *S3ApiServer) completeMultipartUpload(input *s3.CompleteMultipartUploa
 				finalParts = append(finalParts, p)
 				offset += int64(chunk.Size)
 			}
+			encryptedParts = append(encryptedParts, newSSEPart(partNumber, offset-partOffset, entry))
 			found = true
 		}
 	}
 
+	version, errCode := s3a.prepareObjectVersion(*input.Bucket, "/"+*input.Key)
+	if errCode != s3err.ErrNone {
+		return nil, errCode
+	}
+	versionId := version.versionId
+
 	entryName, dirName := s3a.getEntryNameAndDir(input)
-	err = s3a.mkFile(dirName, entryName, finalParts, func(entry *filer_pb.Entry) {
+	writeDir, writeName := dirName, entryName
+	if version.stagedPath != "" {
+		writeDir, writeName = util.FullPath(version.stagedPath).DirAndName()
+	}
+	err = s3a.mkFile(writeDir, writeName, finalParts, func(entry *filer_pb.Entry) {
 		if entry.Extended == nil {
 			entry.Extended = make(map[string][]byte)
 		}
@@ -228,6 +242,13 @@ func (s3a *S3ApiServer) completeMultipartUpload(input *s3.CompleteMultipartUploa
 				entry.Extended[k] = v
 			}
 		}
+		// the parts encrypted by the gateway each have their own key stream
+		if _, found := pentry.Extended[s3_constants.ExtSSEIvKey]; found {
+			entry.Extended[s3_constants.ExtSSEPartsKey] = []byte(formatSSEParts(encryptedParts))
+		}
+		if versionId != "" {
+			entry.Extended[s3_constants.AmzVersionId] = []byte(versionId)
+		}
 		if pentry.Attributes.Mime != "" {
 			entry.Attributes.Mime = pentry.Attributes.Mime
 		} else if mime != "" {
@@ -238,8 +259,12 @@ func (s3a *S3ApiServer) completeMultipartUpload(input *s3.CompleteMultipartUploa
 
 	if err != nil {
 		glog.Errorf("completeMultipartUpload %s/%s error: %v", dirName, entryName, err)
+		version.done(s3err.ErrInternalError)
 		return nil, s3err.ErrInternalError
 	}
+	if errCode = version.done(s3err.ErrNone); errCode != s3err.ErrNone {
+		return nil, errCode
+	}
 
 	output = &CompleteMultipartUploadResult{
 		CompleteMultipartUploadOutput: s3.CompleteMultipartUploadOutput{
@@ -249,6 +274,9 @@ func (s3a *S3ApiServer) completeMultipartUpload(input *s3.CompleteMultipartUploa
 			Key:      objectKey(input.Key),
 		},
 	}
+	if versionId != "" {
+		output.VersionId = aws.String(versionId)
+	}
 
 	for _, deleteEntry := range deleteEntries {
 		//delete unused part data
@@ -4,4 +4,30 @@ const (
 	ExtAmzOwnerKey  = "Seaweed-X-Amz-Owner"
 	ExtAmzAclKey    = "Seaweed-X-Amz-Acl"
 	ExtOwnershipKey = "Seaweed-X-Amz-Ownership"
+
+	ExtVersioningKey       = "Seaweed-X-Amz-Versioning"
+	ExtDeleteMarkerKey     = "Seaweed-X-Amz-Delete-Marker"
+	ExtObjectLockKey       = "Seaweed-X-Amz-Object-Lock"
+	ExtBucketPolicyKey     = "Seaweed-X-Amz-Bucket-Policy"
+	ExtCorsKey             = "Seaweed-X-Amz-Cors"
+	ExtLifecycleKey        = "Seaweed-X-Amz-Lifecycle"
+	ExtBucketEncryptionKey = "Seaweed-X-Amz-Bucket-Encryption"
+	ExtNotificationKey     = "Seaweed-X-Amz-Notification"
+	ExtWebsiteKey          = "Seaweed-X-Amz-Website"
+
+	// the internal state of objects encrypted by the gateway, also passed as request headers to the filer
+	ExtSSEIvKey         = "Seaweed-X-Amz-Sse-Iv"
+	ExtSSEKmsDataKeyKey = "Seaweed-X-Amz-Sse-Kms-Data-Key"
+	ExtSSEPartsKey      = "Seaweed-X-Amz-Sse-Parts"
 )
+
+// SSEMetadataKeys are the object metadata describing its server side encryption
+var SSEMetadataKeys = []string{
+	AmzServerSideEncryption,
+	AmzServerSideEncryptionAwsKmsKeyId,
+	AmzServerSideEncryptionCustomerAlgorithm,
+	AmzServerSideEncryptionCustomerKeyMD5,
+	ExtSSEIvKey,
+	ExtSSEKmsDataKeyKey,
+	ExtSSEPartsKey,
+}
@@ -51,6 +51,32 @@ const (
 	AmzAclWriteAcp    = "X-Amz-Grant-Write-Acp"
 
 	AmzMpPartsCount = "X-Amz-Mp-Parts-Count"
+
+	// S3 object versioning
+	AmzVersionId    = "X-Amz-Version-Id"
+	AmzDeleteMarker = "X-Amz-Delete-Marker"
+
+	AmzCopySourceVersionId = "X-Amz-Copy-Source-Version-Id"
+
+	// S3 object lock
+	AmzObjectLockMode            = "X-Amz-Object-Lock-Mode"
+	AmzObjectLockRetainUntilDate = "X-Amz-Object-Lock-Retain-Until-Date"
+	AmzObjectLockLegalHold       = "X-Amz-Object-Lock-Legal-Hold"
+	AmzBypassGovernanceRetention = "X-Amz-Bypass-Governance-Retention"
+	AmzBucketObjectLockEnabled   = "X-Amz-Bucket-Object-Lock-Enabled"
+
+	// S3 server side encryption
+	AmzServerSideEncryption                            = "X-Amz-Server-Side-Encryption"
+	AmzServerSideEncryptionAwsKmsKeyId                 = "X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id"
+	AmzServerSideEncryptionCustomerAlgorithm           = "X-Amz-Server-Side-Encryption-Customer-Algorithm"
+	AmzServerSideEncryptionCustomerKey                 = "X-Amz-Server-Side-Encryption-Customer-Key"
+	AmzServerSideEncryptionCustomerKeyMD5              = "X-Amz-Server-Side-Encryption-Customer-Key-Md5"
+	AmzCopySourceServerSideEncryptionCustomerAlgorithm = "X-Amz-Copy-Source-Server-Side-Encryption-Customer-Algorithm"
+	AmzCopySourceServerSideEncryptionCustomerKey       = "X-Amz-Copy-Source-Server-Side-Encryption-Customer-Key"
+	AmzCopySourceServerSideEncryptionCustomerKeyMD5    = "X-Amz-Copy-Source-Server-Side-Encryption-Customer-Key-Md5"
+
+	SSEAlgorithmAES256 = "AES256"
+	SSEAlgorithmKMS    = "aws:kms"
 )
 
 // Non-Standard S3 HTTP request constants
@@ -12,5 +12,7 @@ const (
 
 	SeaweedStorageDestinationHeader = "x-seaweedfs-destination"
 	MultipartUploadsFolder          = ".uploads"
+	VersionsFolder                  = ".versions"
+	TrashFolder                     = ".trash" // the filer trash of the bucket, see filer.TrashFolderName
 	FolderMimeType                  = "httpd/unix-directory"
 )
@@ -0,0 +1,311 @@
+package s3api
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3_constants"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3err"
+)
+
+// CORSConfiguration is the bucket CORS configuration, stored as xml in the bucket entry Extended[ExtCorsKey].
+// Buckets without a CORS configuration keep the gateway wide allowed origins.
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_CORSConfiguration.html
+type CORSConfiguration struct {
+	XMLName   xml.Name   `xml:"CORSConfiguration"`
+	CORSRules []CORSRule `xml:"CORSRule"`
+}
+
+type CORSRule struct {
+	ID             string   `xml:"ID,omitempty"`
+	AllowedHeaders []string `xml:"AllowedHeader,omitempty"`
+	AllowedMethods []string `xml:"AllowedMethod"`
+	AllowedOrigins []string `xml:"AllowedOrigin"`
+	ExposeHeaders  []string `xml:"ExposeHeader,omitempty"`
+	MaxAgeSeconds  *int     `xml:"MaxAgeSeconds,omitempty"`
+}
+
+const maxCORSRules = 100
+
+var corsAllowedMethods = map[string]struct{}{
+	http.MethodGet:    {},
+	http.MethodPut:    {},
+	http.MethodHead:   {},
+	http.MethodPost:   {},
+	http.MethodDelete: {},
+}
+
+func (c *CORSConfiguration) validate() bool {
+	if len(c.CORSRules) == 0 || len(c.CORSRules) > maxCORSRules {
+		return false
+	}
+	for _, rule := range c.CORSRules {
+		if len(rule.AllowedMethods) == 0 || len(rule.AllowedOrigins) == 0 {
+			return false
+		}
+		for _, method := range rule.AllowedMethods {
+			if _, found := corsAllowedMethods[method]; !found {
+				return false
+			}
+		}
+		for _, origin := range rule.AllowedOrigins {
+			if strings.Count(origin, "*") > 1 {
+				return false
+			}
+		}
+		for _, header := range rule.AllowedHeaders {
+			if strings.Count(header, "*") > 1 {
+				return false
+			}
+		}
+		if rule.MaxAgeSeconds != nil && *rule.MaxAgeSeconds < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// findRule returns the first rule allowing the origin, method and request headers
+func (c *CORSConfiguration) findRule(origin, method string, requestHeaders []string) *CORSRule {
+	for i := range c.CORSRules {
+		rule := &c.CORSRules[i]
+		if rule.allowsOrigin(origin) && containsString(rule.AllowedMethods, method, false) && rule.allowsHeaders(requestHeaders) {
+			return rule
+		}
+	}
+	return nil
+}
+
+func (rule *CORSRule) allowsOrigin(origin string) bool {
+	for _, allowed := range rule.AllowedOrigins {
+		if allowed == "*" || wildcardMatch(strings.ToLower(allowed), strings.ToLower(origin)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (rule *CORSRule) allowsHeaders(requestHeaders []string) bool {
+	for _, header := range requestHeaders {
+		if !matchesAnyPattern(rule.AllowedHeaders, header, true) {
+			return false
+		}
+	}
+	return true
+}
+
+// setHeaders sets the CORS response headers for an allowed request
+func (rule *CORSRule) setHeaders(h http.Header, origin, requestHeaders string, isPreflight bool) {
+	if containsString(rule.AllowedOrigins, "*", false) {
+		h.Set("Access-Control-Allow-Origin", "*")
+	} else {
+		h.Set("Access-Control-Allow-Origin", origin)
+		h.Set("Access-Control-Allow-Credentials", "true")
+	}
+	h.Add("Vary", "Origin")
+	if len(rule.ExposeHeaders) > 0 {
+		h.Set("Access-Control-Expose-Headers", strings.Join(rule.ExposeHeaders, ", "))
+	}
+	if !isPreflight {
+		return
+	}
+	h.Set("Access-Control-Allow-Methods", strings.Join(rule.AllowedMethods, ", "))
+	if requestHeaders != "" {
+		h.Set("Access-Control-Allow-Headers", requestHeaders)
+	}
+	if rule.MaxAgeSeconds != nil {
+		h.Set("Access-Control-Max-Age", strconv.Itoa(*rule.MaxAgeSeconds))
+	}
+}
+
+func parseCORSRequestHeaders(value string) (headers []string) {
+	for _, header := range strings.Split(value, ",") {
+		if header = strings.TrimSpace(header); header != "" {
+			headers = append(headers, header)
+		}
+	}
+	return
+}
+
+func (s3a *S3ApiServer) getCORSConfiguration(bucket string) *CORSConfiguration {
+	bucketMetadata, errCode := s3a.bucketRegistry.GetBucketMetadata(bucket)
+	if errCode != s3err.ErrNone {
+		return nil
+	}
+	return bucketMetadata.Cors
+}
+
+// corsResponseWriter replaces the CORS headers set by the handlers or the filer
+// with the ones from the bucket CORS rules, just before the headers are sent
+type corsResponseWriter struct {
+	http.ResponseWriter
+	rule           *CORSRule
+	origin         string
+	requestHeaders string
+	isPreflight    bool
+	wroteHeader    bool
+}
+
+func (w *corsResponseWriter) WriteHeader(statusCode int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		h := w.ResponseWriter.Header()
+		for key := range h {
+			if strings.HasPrefix(key, "Access-Control-") {
+				delete(h, key)
+			}
+		}
+		if w.rule != nil {
+			w.rule.setHeaders(h, w.origin, w.requestHeaders, w.isPreflight)
+		}
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *corsResponseWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *corsResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// corsMiddleware applies the bucket CORS rules to cross-origin requests
+func (s3a *S3ApiServer) corsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		bucket := mux.Vars(r)["bucket"]
+		if origin == "" || bucket == "" || r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+		corsConfig := s3a.getCORSConfiguration(bucket)
+		if corsConfig == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(&corsResponseWriter{
+			ResponseWriter: w,
+			rule:           corsConfig.findRule(origin, r.Method, nil),
+			origin:         origin,
+		}, r)
+	})
+}
+
+// CorsPreflightHandler answers the OPTIONS preflight requests on a bucket or its objects
+// https://docs.aws.amazon.com/AmazonS3/latest/API/RESTOPTIONSobject.html
+func (s3a *S3ApiServer) CorsPreflightHandler(w http.ResponseWriter, r *http.Request) {
+	bucket, _ := s3_constants.GetBucketAndObject(r)
+
+	corsConfig := s3a.getCORSConfiguration(bucket)
+	if corsConfig == nil {
+		s3a.defaultOptionsHandler(w, r)
+		return
+	}
+
+	origin := r.Header.Get("Origin")
+	method := r.Header.Get("Access-Control-Request-Method")
+	requestHeaders := r.Header.Get("Access-Control-Request-Headers")
+	cw := &corsResponseWriter{
+		ResponseWriter: w,
+		origin:         origin,
+		requestHeaders: requestHeaders,
+		isPreflight:    true,
+	}
+	if origin == "" || method == "" {
+		s3err.WriteErrorResponse(cw, r, s3err.ErrCORSForbidden)
+		return
+	}
+
+	cw.rule = corsConfig.findRule(origin, method, parseCORSRequestHeaders(requestHeaders))
+	if cw.rule == nil {
+		glog.V(3).Infof("CORS preflight on %s from %s %s is not allowed", bucket, origin, method)
+		s3err.WriteErrorResponse(cw, r, s3err.ErrCORSForbidden)
+		return
+	}
+
+	writeSuccessResponseEmpty(cw, r)
+}
+
+// GetBucketCorsHandler Get bucket CORS
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_GetBucketCors.html
+func (s3a *S3ApiServer) GetBucketCorsHandler(w http.ResponseWriter, r *http.Request) {
+	bucket, _ := s3_constants.GetBucketAndObject(r)
+	glog.V(3).Infof("GetBucketCorsHandler %s", bucket)
+
+	if err := s3a.checkBucket(r, bucket); err != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, err)
+		return
+	}
+
+	corsConfig := s3a.getCORSConfiguration(bucket)
+	if corsConfig == nil {
+		s3err.WriteErrorResponse(w, r, s3err.ErrNoSuchCORSConfiguration)
+		return
+	}
+
+	writeSuccessResponseXML(w, r, corsConfig)
+}
+
+// PutBucketCorsHandler Put bucket CORS
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutBucketCors.html
+func (s3a *S3ApiServer) PutBucketCorsHandler(w http.ResponseWriter, r *http.Request) {
+	bucket, _ := s3_constants.GetBucketAndObject(r)
+	glog.V(3).Infof("PutBucketCorsHandler %s", bucket)
+
+	if err := s3a.checkBucket(r, bucket); err != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, err)
+		return
+	}
+
+	corsConfig := CORSConfiguration{}
+	if err := xmlDecoder(r.Body, &corsConfig, r.ContentLength); err != nil {
+		glog.Warningf("PutBucketCorsHandler xml decode: %s", err)
+		s3err.WriteErrorResponse(w, r, s3err.ErrMalformedXML)
+		return
+	}
+	if !corsConfig.validate() {
+		s3err.WriteErrorResponse(w, r, s3err.ErrMalformedXML)
+		return
+	}
+
+	corsConfigBytes, err := xml.Marshal(corsConfig)
+	if err != nil {
+		s3err.WriteErrorResponse(w, r, s3err.ErrInternalError)
+		return
+	}
+	if errCode := s3a.updateBucketExtended(bucket, s3_constants.ExtCorsKey, corsConfigBytes); errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+
+	writeSuccessResponseEmpty(w, r)
+}
+
+// DeleteBucketCorsHandler Delete bucket CORS
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_DeleteBucketCors.html
+func (s3a *S3ApiServer) DeleteBucketCorsHandler(w http.ResponseWriter, r *http.Request) {
+	bucket, _ := s3_constants.GetBucketAndObject(r)
+	glog.V(3).Infof("DeleteBucketCorsHandler %s", bucket)
+
+	if err := s3a.checkBucket(r, bucket); err != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, err)
+		return
+	}
+
+	if errCode := s3a.updateBucketExtended(bucket, s3_constants.ExtCorsKey, nil); errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+
+	s3err.WriteEmptyResponse(w, r, http.StatusNoContent)
+}
@@ -0,0 +1,116 @@
+package s3api
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+const testCorsConfig = `<CORSConfiguration>
+  <CORSRule>
+    <AllowedOrigin>https://*.example.com</AllowedOrigin>
+    <AllowedMethod>GET</AllowedMethod>
+    <AllowedMethod>PUT</AllowedMethod>
+    <AllowedHeader>x-amz-*</AllowedHeader>
+    <AllowedHeader>Content-Type</AllowedHeader>
+    <ExposeHeader>ETag</ExposeHeader>
+    <MaxAgeSeconds>3000</MaxAgeSeconds>
+  </CORSRule>
+  <CORSRule>
+    <AllowedOrigin>*</AllowedOrigin>
+    <AllowedMethod>HEAD</AllowedMethod>
+  </CORSRule>
+</CORSConfiguration>`
+
+func TestCORSConfigurationValidate(t *testing.T) {
+	corsConfig := &CORSConfiguration{}
+	assert.Nil(t, xml.Unmarshal([]byte(testCorsConfig), corsConfig))
+	assert.True(t, corsConfig.validate())
+	assert.Equal(t, 3000, *corsConfig.CORSRules[0].MaxAgeSeconds)
+
+	tests := []struct {
+		name string
+		rule CORSRule
+	}{
+		{"no origin", CORSRule{AllowedMethods: []string{"GET"}}},
+		{"no method", CORSRule{AllowedOrigins: []string{"*"}}},
+		{"bad method", CORSRule{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"PATCH"}}},
+		{"two wildcards", CORSRule{AllowedOrigins: []string{"https://*.*.com"}, AllowedMethods: []string{"GET"}}},
+	}
+	for _, tt := range tests {
+		invalid := &CORSConfiguration{CORSRules: []CORSRule{tt.rule}}
+		assert.False(t, invalid.validate(), tt.name)
+	}
+	assert.False(t, (&CORSConfiguration{}).validate(), "no rules")
+}
+
+func TestCORSConfigurationFindRule(t *testing.T) {
+	corsConfig := &CORSConfiguration{}
+	assert.Nil(t, xml.Unmarshal([]byte(testCorsConfig), corsConfig))
+
+	tests := []struct {
+		origin  string
+		method  string
+		headers []string
+		rule    int
+	}{
+		{"https://www.example.com", "GET", nil, 0},
+		{"https://WWW.example.com", "PUT", []string{"X-Amz-Meta-A", "content-type"}, 0},
+		{"https://www.example.com", "PUT", []string{"Authorization"}, -1},
+		{"https://www.example.com", "DELETE", nil, -1},
+		{"https://www.other.com", "GET", nil, -1},
+		{"https://www.other.com", "HEAD", nil, 1},
+	}
+	for _, tt := range tests {
+		rule := corsConfig.findRule(tt.origin, tt.method, tt.headers)
+		if tt.rule < 0 {
+			assert.Nil(t, rule, "%s %s %v", tt.origin, tt.method, tt.headers)
+		} else {
+			assert.Equal(t, &corsConfig.CORSRules[tt.rule], rule, "%s %s %v", tt.origin, tt.method, tt.headers)
+		}
+	}
+}
+
+func TestCorsPreflightHandler(t *testing.T) {
+	corsConfig := &CORSConfiguration{}
+	assert.Nil(t, xml.Unmarshal([]byte(testCorsConfig), corsConfig))
+	s3a := &S3ApiServer{option: &S3ApiServerOption{}}
+	s3a.bucketRegistry = &BucketRegistry{
+		metadataCache: map[string]*BucketMetaData{"bucket": {Name: "bucket", Cors: corsConfig}},
+		notFound:      map[string]struct{}{},
+		s3a:           s3a,
+	}
+
+	preflight := func(origin, method, headers string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodOptions, "/bucket/a.txt", nil)
+		r.Header.Set("Origin", origin)
+		r.Header.Set("Access-Control-Request-Method", method)
+		if headers != "" {
+			r.Header.Set("Access-Control-Request-Headers", headers)
+		}
+		r = mux.SetURLVars(r, map[string]string{"bucket": "bucket", "object": "a.txt"})
+		w := httptest.NewRecorder()
+		s3a.CorsPreflightHandler(w, r)
+		return w
+	}
+
+	w := preflight("https://www.example.com", "PUT", "x-amz-meta-a, content-type")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "https://www.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "GET, PUT", w.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "x-amz-meta-a, content-type", w.Header().Get("Access-Control-Allow-Headers"))
+	assert.Equal(t, "ETag", w.Header().Get("Access-Control-Expose-Headers"))
+	assert.Equal(t, "3000", w.Header().Get("Access-Control-Max-Age"))
+
+	w = preflight("https://www.other.com", "HEAD", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+
+	w = preflight("https://www.other.com", "GET", "")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+}
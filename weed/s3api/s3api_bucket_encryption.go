@@ -0,0 +1,136 @@
+package s3api
+
+import (
+	"encoding/xml"
+	"net/http"
+
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3_constants"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3err"
+)
+
+// ServerSideEncryptionConfiguration is the default encryption of the new objects in a bucket,
+// stored as xml in the bucket entry Extended[ExtBucketEncryptionKey].
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_ServerSideEncryptionConfiguration.html
+type ServerSideEncryptionConfiguration struct {
+	XMLName xml.Name                   `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ServerSideEncryptionConfiguration"`
+	Rules   []ServerSideEncryptionRule `xml:"Rule"`
+}
+
+type ServerSideEncryptionRule struct {
+	ApplyServerSideEncryptionByDefault ServerSideEncryptionByDefault `xml:"ApplyServerSideEncryptionByDefault"`
+	BucketKeyEnabled                   bool                          `xml:"BucketKeyEnabled,omitempty"`
+}
+
+type ServerSideEncryptionByDefault struct {
+	SSEAlgorithm   string `xml:"SSEAlgorithm"`
+	KMSMasterKeyID string `xml:"KMSMasterKeyID,omitempty"`
+}
+
+func (config *ServerSideEncryptionConfiguration) validate() s3err.ErrorCode {
+	if len(config.Rules) != 1 {
+		return s3err.ErrMalformedXML
+	}
+	byDefault := config.Rules[0].ApplyServerSideEncryptionByDefault
+	switch byDefault.SSEAlgorithm {
+	case s3_constants.SSEAlgorithmAES256:
+		if byDefault.KMSMasterKeyID != "" {
+			return s3err.ErrInvalidRequest
+		}
+	case s3_constants.SSEAlgorithmKMS:
+	default:
+		return s3err.ErrInvalidEncryptionAlgorithm
+	}
+	return s3err.ErrNone
+}
+
+// defaultEncryption returns the algorithm and the kms key id to encrypt new objects with, if the bucket has a default encryption
+func (config *ServerSideEncryptionConfiguration) defaultEncryption() (algorithm, kmsKeyId string) {
+	if config == nil || len(config.Rules) == 0 {
+		return "", ""
+	}
+	byDefault := config.Rules[0].ApplyServerSideEncryptionByDefault
+	return byDefault.SSEAlgorithm, byDefault.KMSMasterKeyID
+}
+
+func (s3a *S3ApiServer) getBucketEncryption(bucket string) *ServerSideEncryptionConfiguration {
+	bucketMetadata, errCode := s3a.bucketRegistry.GetBucketMetadata(bucket)
+	if errCode != s3err.ErrNone {
+		return nil
+	}
+	return bucketMetadata.Encryption
+}
+
+// GetBucketEncryptionHandler Get bucket default encryption
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_GetBucketEncryption.html
+func (s3a *S3ApiServer) GetBucketEncryptionHandler(w http.ResponseWriter, r *http.Request) {
+	bucket, _ := s3_constants.GetBucketAndObject(r)
+	glog.V(3).Infof("GetBucketEncryptionHandler %s", bucket)
+
+	if err := s3a.checkBucket(r, bucket); err != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, err)
+		return
+	}
+
+	encryptionConfig := s3a.getBucketEncryption(bucket)
+	if encryptionConfig == nil {
+		s3err.WriteErrorResponse(w, r, s3err.ErrNoSuchBucketEncryptionConfiguration)
+		return
+	}
+
+	writeSuccessResponseXML(w, r, encryptionConfig)
+}
+
+// PutBucketEncryptionHandler Put bucket default encryption
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutBucketEncryption.html
+func (s3a *S3ApiServer) PutBucketEncryptionHandler(w http.ResponseWriter, r *http.Request) {
+	bucket, _ := s3_constants.GetBucketAndObject(r)
+	glog.V(3).Infof("PutBucketEncryptionHandler %s", bucket)
+
+	if err := s3a.checkBucket(r, bucket); err != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, err)
+		return
+	}
+
+	encryptionConfig := ServerSideEncryptionConfiguration{}
+	if err := xmlDecoder(r.Body, &encryptionConfig, r.ContentLength); err != nil {
+		glog.Warningf("PutBucketEncryptionHandler xml decode: %s", err)
+		s3err.WriteErrorResponse(w, r, s3err.ErrMalformedXML)
+		return
+	}
+	if errCode := encryptionConfig.validate(); errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+
+	encryptionConfigBytes, err := xml.Marshal(encryptionConfig)
+	if err != nil {
+		s3err.WriteErrorResponse(w, r, s3err.ErrInternalError)
+		return
+	}
+	if errCode := s3a.updateBucketExtended(bucket, s3_constants.ExtBucketEncryptionKey, encryptionConfigBytes); errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+
+	writeSuccessResponseEmpty(w, r)
+}
+
+// DeleteBucketEncryptionHandler Delete bucket default encryption
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_DeleteBucketEncryption.html
+func (s3a *S3ApiServer) DeleteBucketEncryptionHandler(w http.ResponseWriter, r *http.Request) {
+	bucket, _ := s3_constants.GetBucketAndObject(r)
+	glog.V(3).Infof("DeleteBucketEncryptionHandler %s", bucket)
+
+	if err := s3a.checkBucket(r, bucket); err != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, err)
+		return
+	}
+
+	if errCode := s3a.updateBucketExtended(bucket, s3_constants.ExtBucketEncryptionKey, nil); errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+
+	s3err.WriteEmptyResponse(w, r, http.StatusNoContent)
+}
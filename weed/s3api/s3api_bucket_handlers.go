@@ -6,6 +6,7 @@ import (
 	"encoding/xml"
 	"errors"
 	"fmt"
+	"io"
 	"math"
 	"net/http"
 	"strings"
@@ -120,6 +121,11 @@ func (s3a *S3ApiServer) PutBucketHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	var lockConfigBytes []byte
+	if strings.EqualFold(r.Header.Get(s3_constants.AmzBucketObjectLockEnabled), "true") {
+		lockConfigBytes, _ = xml.Marshal(ObjectLockConfiguration{ObjectLockEnabled: s3.ObjectLockEnabledEnabled})
+	}
+
 	fn := func(entry *filer_pb.Entry) {
 		if identityId := r.Header.Get(s3_constants.AmzIdentityId); identityId != "" {
 			if entry.Extended == nil {
@@ -127,6 +133,14 @@ func (s3a *S3ApiServer) PutBucketHandler(w http.ResponseWriter, r *http.Request)
 			}
 			entry.Extended[s3_constants.AmzIdentityId] = []byte(identityId)
 		}
+		// object lock requires versioning
+		if lockConfigBytes != nil {
+			if entry.Extended == nil {
+				entry.Extended = make(map[string][]byte)
+			}
+			entry.Extended[s3_constants.ExtVersioningKey] = []byte(s3.BucketVersioningStatusEnabled)
+			entry.Extended[s3_constants.ExtObjectLockKey] = lockConfigBytes
+		}
 	}
 
 	// create the folder for bucket, but lazily create actual collection
@@ -150,13 +164,15 @@ func (s3a *S3ApiServer) DeleteBucketHandler(w http.ResponseWriter, r *http.Reque
 	}
 
 	err := s3a.WithFilerClient(false, func(client filer_pb.SeaweedFilerClient) error {
-		if !s3a.option.AllowDeleteBucketNotEmpty {
-			entries, _, err := s3a.list(s3a.option.BucketsPath+"/"+bucket, "", "", false, 2)
+		// buckets with object lock are never deleted with content
+		lockConfig, _ := s3a.getObjectLockConfiguration(bucket)
+		if !s3a.option.AllowDeleteBucketNotEmpty || lockConfig.isEnabled() {
+			entries, _, err := s3a.list(s3a.option.BucketsPath+"/"+bucket, "", "", false, 3)
 			if err != nil {
 				return fmt.Errorf("failed to list bucket %s: %v", bucket, err)
 			}
 			for _, entry := range entries {
-				if entry.Name != s3_constants.MultipartUploadsFolder {
+				if entry.Name != s3_constants.MultipartUploadsFolder && entry.Name != s3_constants.TrashFolder {
 					return errors.New(s3err.GetAPIError(s3err.ErrBucketNotEmpty).Code)
 				}
 			}
@@ -316,6 +332,17 @@ func (s3a *S3ApiServer) GetBucketLifecycleConfigurationHandler(w http.ResponseWr
 		s3err.WriteErrorResponse(w, r, err)
 		return
 	}
+	bucketMetadata, errCode := s3a.bucketRegistry.GetBucketMetadata(bucket)
+	if errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+	if bucketMetadata.Lifecycle != nil {
+		writeSuccessResponseXML(w, r, bucketMetadata.Lifecycle)
+		return
+	}
+
+	// lifecycle set as filer.conf TTLs only
 	fc, err := filer.ReadFilerConf(s3a.option.Filer, s3a.option.GrpcDialOption, nil)
 	if err != nil {
 		glog.Errorf("GetBucketLifecycleConfigurationHandler: %s", err)
@@ -368,6 +395,33 @@ func (s3a *S3ApiServer) PutBucketLifecycleConfigurationHandler(w http.ResponseWr
 		s3err.WriteErrorResponse(w, r, s3err.ErrMalformedXML)
 		return
 	}
+	if errCode := lifeCycleConfig.validate(s3a.storageClassDiskTypes); errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+
+	versioning, errCode := s3a.getVersioningState(bucket)
+	if errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+
+	// all rules are applied by the lifecycle worker
+	lifeCycleConfigBytes, err := xml.Marshal(lifeCycleConfig)
+	if err != nil {
+		s3err.WriteErrorResponse(w, r, s3err.ErrInternalError)
+		return
+	}
+	if errCode := s3a.updateBucketExtended(bucket, s3_constants.ExtLifecycleKey, lifeCycleConfigBytes); errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+
+	// expirations by key prefix are also set as filer.conf TTLs, unless the bucket keeps versions
+	if versioning != "" {
+		writeSuccessResponseEmpty(w, r)
+		return
+	}
 
 	fc, err := filer.ReadFilerConf(s3a.option.Filer, s3a.option.GrpcDialOption, nil)
 	if err != nil {
@@ -380,23 +434,10 @@ func (s3a *S3ApiServer) PutBucketLifecycleConfigurationHandler(w http.ResponseWr
 	changed := false
 
 	for _, rule := range lifeCycleConfig.Rules {
-		if rule.Status != Enabled {
-			continue
-		}
-		var rulePrefix string
-		switch {
-		case rule.Filter.Prefix.set:
-			rulePrefix = rule.Filter.Prefix.val
-		case rule.Prefix.set:
-			rulePrefix = rule.Prefix.val
-		case !rule.Expiration.Date.IsZero() || rule.Transition.Days > 0 || !rule.Transition.Date.IsZero():
-			s3err.WriteErrorResponse(w, r, s3err.ErrNotImplemented)
-			return
-		}
-
-		if rule.Expiration.Days == 0 {
+		if rule.Status != Enabled || !rule.isSimplePrefixRule() || rule.Expiration.Days == 0 {
 			continue
 		}
+		rulePrefix := rule.prefix()
 
 		locConf := &filer_pb.FilerConf_PathConf{
 			LocationPrefix: fmt.Sprintf("%s/%s/%s", s3a.option.BucketsPath, bucket, rulePrefix),
@@ -444,6 +485,11 @@ func (s3a *S3ApiServer) DeleteBucketLifecycleHandler(w http.ResponseWriter, r *h
 		return
 	}
 
+	if errCode := s3a.updateBucketExtended(bucket, s3_constants.ExtLifecycleKey, nil); errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+
 	fc, err := filer.ReadFilerConf(s3a.option.Filer, s3a.option.GrpcDialOption, nil)
 	if err != nil {
 		glog.Errorf("DeleteBucketLifecycleHandler read filer config: %s", err)
@@ -661,9 +707,181 @@ func (s3a *S3ApiServer) GetBucketVersioningHandler(w http.ResponseWriter, r *htt
 		return
 	}
 
+	versioning, errCode := s3a.getVersioningState(bucket)
+	if errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+
+	// a bucket that never had versioning configured reports no status at all
+	versioningConfiguration := &s3.VersioningConfiguration{}
+	if versioning != "" {
+		versioningConfiguration.Status = aws.String(versioning)
+	}
+
 	s3err.WriteAwsXMLResponse(w, r, http.StatusOK, &s3.PutBucketVersioningInput{
-		VersioningConfiguration: &s3.VersioningConfiguration{
-			Status: aws.String(s3.BucketVersioningStatusSuspended),
-		},
+		VersioningConfiguration: versioningConfiguration,
 	})
 }
+
+// PutBucketVersioningHandler Put bucket Versioning
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutBucketVersioning.html
+func (s3a *S3ApiServer) PutBucketVersioningHandler(w http.ResponseWriter, r *http.Request) {
+	bucket, _ := s3_constants.GetBucketAndObject(r)
+	glog.V(3).Infof("PutBucketVersioning %s", bucket)
+
+	if err := s3a.checkBucket(r, bucket); err != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, err)
+		return
+	}
+
+	versioningConfig := VersioningConfiguration{}
+	if err := xmlDecoder(r.Body, &versioningConfig, r.ContentLength); err != nil {
+		glog.Warningf("PutBucketVersioningHandler xml decode: %s", err)
+		s3err.WriteErrorResponse(w, r, s3err.ErrMalformedXML)
+		return
+	}
+
+	status := string(versioningConfig.Status)
+	if status != s3.BucketVersioningStatusEnabled && status != s3.BucketVersioningStatusSuspended {
+		s3err.WriteErrorResponse(w, r, s3err.ErrIllegalVersioningConfiguration)
+		return
+	}
+
+	bucketEntry, err := s3a.getEntry(s3a.option.BucketsPath, bucket)
+	if err != nil {
+		if err == filer_pb.ErrNotFound {
+			s3err.WriteErrorResponse(w, r, s3err.ErrNoSuchBucket)
+			return
+		}
+		s3err.WriteErrorResponse(w, r, s3err.ErrInternalError)
+		return
+	}
+
+	if bucketEntry.Extended == nil {
+		bucketEntry.Extended = make(map[string][]byte)
+	}
+	if _, locked := bucketEntry.Extended[s3_constants.ExtObjectLockKey]; locked && status != s3.BucketVersioningStatusEnabled {
+		s3err.WriteErrorResponse(w, r, s3err.ErrInvalidBucketState)
+		return
+	}
+	bucketEntry.Extended[s3_constants.ExtVersioningKey] = []byte(status)
+	if err = s3a.updateEntry(s3a.option.BucketsPath, bucketEntry); err != nil {
+		glog.Errorf("PutBucketVersioningHandler update bucket %s: %v", bucket, err)
+		s3err.WriteErrorResponse(w, r, s3err.ErrInternalError)
+		return
+	}
+	// do not wait for the metadata subscription to pick up the change
+	s3a.bucketRegistry.LoadBucketMetadata(bucketEntry)
+
+	writeSuccessResponseEmpty(w, r)
+}
+
+func (s3a *S3ApiServer) getBucketPolicy(bucket string) *BucketPolicy {
+	bucketMetadata, errCode := s3a.bucketRegistry.GetBucketMetadata(bucket)
+	if errCode != s3err.ErrNone {
+		return nil
+	}
+	return bucketMetadata.Policy
+}
+
+// GetBucketPolicyHandler Get bucket Policy
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_GetBucketPolicy.html
+func (s3a *S3ApiServer) GetBucketPolicyHandler(w http.ResponseWriter, r *http.Request) {
+	bucket, _ := s3_constants.GetBucketAndObject(r)
+	glog.V(3).Infof("GetBucketPolicyHandler %s", bucket)
+
+	if err := s3a.checkBucket(r, bucket); err != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, err)
+		return
+	}
+
+	bucketEntry, err := s3a.getEntry(s3a.option.BucketsPath, bucket)
+	if err != nil {
+		s3err.WriteErrorResponse(w, r, s3err.ErrInternalError)
+		return
+	}
+	policyBytes, found := bucketEntry.Extended[s3_constants.ExtBucketPolicyKey]
+	if !found || len(policyBytes) == 0 {
+		s3err.WriteErrorResponse(w, r, s3err.ErrNoSuchBucketPolicy)
+		return
+	}
+
+	s3err.WriteResponse(w, r, http.StatusOK, policyBytes, s3err.MimeJSON)
+}
+
+// PutBucketPolicyHandler Put bucket Policy
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutBucketPolicy.html
+func (s3a *S3ApiServer) PutBucketPolicyHandler(w http.ResponseWriter, r *http.Request) {
+	bucket, _ := s3_constants.GetBucketAndObject(r)
+	glog.V(3).Infof("PutBucketPolicyHandler %s", bucket)
+
+	if err := s3a.checkBucket(r, bucket); err != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, err)
+		return
+	}
+
+	policyBytes, err := io.ReadAll(io.LimitReader(r.Body, bucketPolicyMaxSize+1))
+	if err != nil {
+		s3err.WriteErrorResponse(w, r, s3err.ErrInternalError)
+		return
+	}
+	if _, err := ParseBucketPolicy(bucket, policyBytes); err != nil {
+		glog.Warningf("PutBucketPolicyHandler %s: %v", bucket, err)
+		s3err.WriteErrorResponse(w, r, s3err.ErrMalformedPolicy)
+		return
+	}
+
+	if errCode := s3a.updateBucketExtended(bucket, s3_constants.ExtBucketPolicyKey, policyBytes); errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+
+	s3err.WriteEmptyResponse(w, r, http.StatusNoContent)
+}
+
+// DeleteBucketPolicyHandler Delete bucket Policy
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_DeleteBucketPolicy.html
+func (s3a *S3ApiServer) DeleteBucketPolicyHandler(w http.ResponseWriter, r *http.Request) {
+	bucket, _ := s3_constants.GetBucketAndObject(r)
+	glog.V(3).Infof("DeleteBucketPolicyHandler %s", bucket)
+
+	if err := s3a.checkBucket(r, bucket); err != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, err)
+		return
+	}
+
+	if errCode := s3a.updateBucketExtended(bucket, s3_constants.ExtBucketPolicyKey, nil); errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+
+	s3err.WriteEmptyResponse(w, r, http.StatusNoContent)
+}
+
+// updateBucketExtended sets one configuration of the bucket, or removes it if value is nil
+func (s3a *S3ApiServer) updateBucketExtended(bucket, key string, value []byte) s3err.ErrorCode {
+	bucketEntry, err := s3a.getEntry(s3a.option.BucketsPath, bucket)
+	if err != nil {
+		if err == filer_pb.ErrNotFound {
+			return s3err.ErrNoSuchBucket
+		}
+		return s3err.ErrInternalError
+	}
+
+	if value == nil {
+		delete(bucketEntry.Extended, key)
+	} else {
+		if bucketEntry.Extended == nil {
+			bucketEntry.Extended = make(map[string][]byte)
+		}
+		bucketEntry.Extended[key] = value
+	}
+	if err = s3a.updateEntry(s3a.option.BucketsPath, bucketEntry); err != nil {
+		glog.Errorf("update bucket %s %s: %v", bucket, key, err)
+		return s3err.ErrInternalError
+	}
+	// do not wait for the metadata subscription to pick up the change
+	s3a.bucketRegistry.LoadBucketMetadata(bucketEntry)
+	return s3err.ErrNone
+}
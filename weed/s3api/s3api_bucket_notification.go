@@ -0,0 +1,264 @@
+package s3api
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3_constants"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3err"
+)
+
+// NotificationConfiguration is the bucket notification configuration, stored as xml in the bucket entry Extended[ExtNotificationKey].
+// The destination ARNs name the notification targets configured in the "s3.notification" section
+// of notification.toml by their last element, e.g. "arn:seaweedfs:sqs::kafka" or "arn:aws:sns:us-east-1:000000000000:webhook".
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_NotificationConfiguration.html
+type NotificationConfiguration struct {
+	XMLName                     xml.Name                     `xml:"NotificationConfiguration"`
+	TopicConfigurations         []TopicConfiguration         `xml:"TopicConfiguration,omitempty"`
+	QueueConfigurations         []QueueConfiguration         `xml:"QueueConfiguration,omitempty"`
+	CloudFunctionConfigurations []CloudFunctionConfiguration `xml:"CloudFunctionConfiguration,omitempty"`
+}
+
+type TopicConfiguration struct {
+	Id     string              `xml:"Id,omitempty"`
+	Topic  string              `xml:"Topic"`
+	Events []string            `xml:"Event"`
+	Filter *NotificationFilter `xml:"Filter,omitempty"`
+}
+
+type QueueConfiguration struct {
+	Id     string              `xml:"Id,omitempty"`
+	Queue  string              `xml:"Queue"`
+	Events []string            `xml:"Event"`
+	Filter *NotificationFilter `xml:"Filter,omitempty"`
+}
+
+type CloudFunctionConfiguration struct {
+	Id            string              `xml:"Id,omitempty"`
+	CloudFunction string              `xml:"CloudFunction"`
+	Events        []string            `xml:"Event"`
+	Filter        *NotificationFilter `xml:"Filter,omitempty"`
+}
+
+type NotificationFilter struct {
+	S3Key struct {
+		FilterRules []FilterRule `xml:"FilterRule"`
+	} `xml:"S3Key"`
+}
+
+type FilterRule struct {
+	Name  string `xml:"Name"`
+	Value string `xml:"Value"`
+}
+
+const maxNotificationRuleIdLen = 255
+
+// notificationEventTypes are the event types that can be configured, and are sent by the gateway
+var notificationEventTypes = map[string]struct{}{
+	"s3:ObjectCreated:*":                       {},
+	"s3:ObjectCreated:Put":                     {},
+	"s3:ObjectCreated:CompleteMultipartUpload": {},
+	"s3:ObjectRemoved:*":                       {},
+	"s3:ObjectRemoved:Delete":                  {},
+	"s3:ObjectRemoved:DeleteMarkerCreated":     {},
+	"s3:ObjectTagging:*":                       {},
+	"s3:ObjectTagging:Put":                     {},
+	"s3:ObjectTagging:Delete":                  {},
+}
+
+// notificationRule is one topic, queue or cloud function configuration
+type notificationRule struct {
+	id     string
+	arn    string
+	events []string
+	prefix string
+	suffix string
+}
+
+func (c *NotificationConfiguration) rules() (rules []*notificationRule) {
+	for _, t := range c.TopicConfigurations {
+		rules = append(rules, newNotificationRule(t.Id, t.Topic, t.Events, t.Filter))
+	}
+	for _, q := range c.QueueConfigurations {
+		rules = append(rules, newNotificationRule(q.Id, q.Queue, q.Events, q.Filter))
+	}
+	for _, f := range c.CloudFunctionConfigurations {
+		rules = append(rules, newNotificationRule(f.Id, f.CloudFunction, f.Events, f.Filter))
+	}
+	return
+}
+
+func newNotificationRule(id, arn string, events []string, filter *NotificationFilter) *notificationRule {
+	rule := &notificationRule{id: id, arn: arn, events: events}
+	if filter != nil {
+		for _, filterRule := range filter.S3Key.FilterRules {
+			switch strings.ToLower(filterRule.Name) {
+			case "prefix":
+				rule.prefix = filterRule.Value
+			case "suffix":
+				rule.suffix = filterRule.Value
+			}
+		}
+	}
+	return rule
+}
+
+// validate checks the notification configuration, and that the destinations are configured targets.
+// The rules without an id are given one.
+func (c *NotificationConfiguration) validate(hasTarget func(name string) bool) s3err.ErrorCode {
+	ids := make(map[string]struct{})
+	checkRule := func(id *string, arn string, events []string, filter *NotificationFilter) s3err.ErrorCode {
+		if *id == "" {
+			*id = uuid.NewString()
+		}
+		if len(*id) > maxNotificationRuleIdLen {
+			return s3err.ErrInvalidRequest
+		}
+		if _, found := ids[*id]; found {
+			return s3err.ErrInvalidRequest
+		}
+		ids[*id] = struct{}{}
+		if len(events) == 0 {
+			return s3err.ErrMalformedXML
+		}
+		for _, event := range events {
+			if _, found := notificationEventTypes[event]; !found {
+				return s3err.ErrInvalidRequest
+			}
+		}
+		if filter != nil {
+			names := make(map[string]struct{})
+			for _, filterRule := range filter.S3Key.FilterRules {
+				name := strings.ToLower(filterRule.Name)
+				if name != "prefix" && name != "suffix" {
+					return s3err.ErrInvalidRequest
+				}
+				if _, found := names[name]; found {
+					return s3err.ErrInvalidRequest
+				}
+				names[name] = struct{}{}
+			}
+		}
+		if !hasTarget(notificationTargetName(arn)) {
+			return s3err.ErrInvalidNotificationDestination
+		}
+		return s3err.ErrNone
+	}
+
+	for i := range c.TopicConfigurations {
+		t := &c.TopicConfigurations[i]
+		if errCode := checkRule(&t.Id, t.Topic, t.Events, t.Filter); errCode != s3err.ErrNone {
+			return errCode
+		}
+	}
+	for i := range c.QueueConfigurations {
+		q := &c.QueueConfigurations[i]
+		if errCode := checkRule(&q.Id, q.Queue, q.Events, q.Filter); errCode != s3err.ErrNone {
+			return errCode
+		}
+	}
+	for i := range c.CloudFunctionConfigurations {
+		f := &c.CloudFunctionConfigurations[i]
+		if errCode := checkRule(&f.Id, f.CloudFunction, f.Events, f.Filter); errCode != s3err.ErrNone {
+			return errCode
+		}
+	}
+	return s3err.ErrNone
+}
+
+// notificationTargetName is the name of the notification target, the last element of the arn
+func notificationTargetName(arn string) string {
+	if i := strings.LastIndex(arn, ":"); i >= 0 {
+		return arn[i+1:]
+	}
+	return arn
+}
+
+// matches checks the event name, e.g. "ObjectCreated:Put", and the object key against the rule
+func (rule *notificationRule) matches(eventName, key string) bool {
+	if !strings.HasPrefix(key, rule.prefix) || !strings.HasSuffix(key, rule.suffix) {
+		return false
+	}
+	eventType := "s3:" + eventName
+	for _, event := range rule.events {
+		if event == eventType || strings.HasSuffix(event, "*") && strings.HasPrefix(eventType, strings.TrimSuffix(event, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s3a *S3ApiServer) hasNotificationTarget(name string) bool {
+	_, found := s3a.notificationQueues[name]
+	return found
+}
+
+func (s3a *S3ApiServer) getNotificationConfiguration(bucket string) *NotificationConfiguration {
+	bucketMetadata, errCode := s3a.bucketRegistry.GetBucketMetadata(bucket)
+	if errCode != s3err.ErrNone {
+		return nil
+	}
+	return bucketMetadata.Notification
+}
+
+// GetBucketNotificationConfigurationHandler Get bucket notification configuration
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_GetBucketNotificationConfiguration.html
+func (s3a *S3ApiServer) GetBucketNotificationConfigurationHandler(w http.ResponseWriter, r *http.Request) {
+	bucket, _ := s3_constants.GetBucketAndObject(r)
+	glog.V(3).Infof("GetBucketNotificationConfigurationHandler %s", bucket)
+
+	if err := s3a.checkBucket(r, bucket); err != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, err)
+		return
+	}
+
+	notificationConfig := s3a.getNotificationConfiguration(bucket)
+	if notificationConfig == nil {
+		// buckets without notifications have an empty configuration
+		notificationConfig = &NotificationConfiguration{}
+	}
+
+	writeSuccessResponseXML(w, r, notificationConfig)
+}
+
+// PutBucketNotificationConfigurationHandler Put bucket notification configuration
+// An empty configuration disables the notifications of the bucket.
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutBucketNotificationConfiguration.html
+func (s3a *S3ApiServer) PutBucketNotificationConfigurationHandler(w http.ResponseWriter, r *http.Request) {
+	bucket, _ := s3_constants.GetBucketAndObject(r)
+	glog.V(3).Infof("PutBucketNotificationConfigurationHandler %s", bucket)
+
+	if err := s3a.checkBucket(r, bucket); err != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, err)
+		return
+	}
+
+	notificationConfig := NotificationConfiguration{}
+	if err := xmlDecoder(r.Body, &notificationConfig, r.ContentLength); err != nil {
+		glog.Warningf("PutBucketNotificationConfigurationHandler xml decode: %s", err)
+		s3err.WriteErrorResponse(w, r, s3err.ErrMalformedXML)
+		return
+	}
+	if errCode := notificationConfig.validate(s3a.hasNotificationTarget); errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+
+	var notificationConfigBytes []byte
+	if len(notificationConfig.rules()) > 0 {
+		var err error
+		if notificationConfigBytes, err = xml.Marshal(notificationConfig); err != nil {
+			s3err.WriteErrorResponse(w, r, s3err.ErrInternalError)
+			return
+		}
+	}
+	if errCode := s3a.updateBucketExtended(bucket, s3_constants.ExtNotificationKey, notificationConfigBytes); errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+
+	writeSuccessResponseEmpty(w, r)
+}
@@ -0,0 +1,262 @@
+package s3api
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/seaweedfs/seaweedfs/weed/notification"
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3_constants"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3err"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationConfigurationValidate(t *testing.T) {
+	hasTarget := func(name string) bool {
+		return name == "kafka" || name == "webhook"
+	}
+	parse := func(body string) *NotificationConfiguration {
+		c := &NotificationConfiguration{}
+		assert.Nil(t, xml.Unmarshal([]byte(body), c))
+		return c
+	}
+
+	c := parse(`<NotificationConfiguration>
+  <QueueConfiguration>
+    <Id>images</Id>
+    <Queue>arn:seaweedfs:sqs::kafka</Queue>
+    <Event>s3:ObjectCreated:*</Event>
+    <Filter><S3Key>
+      <FilterRule><Name>prefix</Name><Value>images/</Value></FilterRule>
+      <FilterRule><Name>Suffix</Name><Value>.jpg</Value></FilterRule>
+    </S3Key></Filter>
+  </QueueConfiguration>
+  <CloudFunctionConfiguration>
+    <CloudFunction>arn:aws:lambda:us-east-1:000000000000:webhook</CloudFunction>
+    <Event>s3:ObjectRemoved:Delete</Event>
+  </CloudFunctionConfiguration>
+</NotificationConfiguration>`)
+	assert.Equal(t, s3err.ErrNone, c.validate(hasTarget))
+	assert.NotEmpty(t, c.CloudFunctionConfigurations[0].Id, "an id is generated")
+	rules := c.rules()
+	assert.Equal(t, 2, len(rules))
+	assert.Equal(t, "images/", rules[0].prefix)
+	assert.Equal(t, ".jpg", rules[0].suffix)
+
+	for _, c := range []struct {
+		body    string
+		errCode s3err.ErrorCode
+	}{
+		{`<NotificationConfiguration><TopicConfiguration><Topic>arn:seaweedfs:sns::kafka</Topic></TopicConfiguration></NotificationConfiguration>`,
+			s3err.ErrMalformedXML},
+		{`<NotificationConfiguration><TopicConfiguration><Topic>arn:seaweedfs:sns::kafka</Topic><Event>s3:Replication:*</Event></TopicConfiguration></NotificationConfiguration>`,
+			s3err.ErrInvalidRequest},
+		{`<NotificationConfiguration><TopicConfiguration><Topic>arn:seaweedfs:sns::aws_sqs</Topic><Event>s3:ObjectCreated:Put</Event></TopicConfiguration></NotificationConfiguration>`,
+			s3err.ErrInvalidNotificationDestination},
+		{`<NotificationConfiguration><QueueConfiguration><Id>a</Id><Queue>kafka</Queue><Event>s3:ObjectCreated:Put</Event></QueueConfiguration><QueueConfiguration><Id>a</Id><Queue>kafka</Queue><Event>s3:ObjectCreated:Put</Event></QueueConfiguration></NotificationConfiguration>`,
+			s3err.ErrInvalidRequest},
+		{`<NotificationConfiguration><QueueConfiguration><Queue>kafka</Queue><Event>s3:ObjectCreated:Put</Event><Filter><S3Key><FilterRule><Name>prefix</Name><Value>a</Value></FilterRule><FilterRule><Name>prefix</Name><Value>b</Value></FilterRule></S3Key></Filter></QueueConfiguration></NotificationConfiguration>`,
+			s3err.ErrInvalidRequest},
+	} {
+		assert.Equal(t, c.errCode, parse(c.body).validate(hasTarget), c.body)
+	}
+
+	assert.Equal(t, s3err.ErrNone, parse(`<NotificationConfiguration/>`).validate(hasTarget), "an empty configuration disables the notifications")
+}
+
+func TestNotificationRuleMatches(t *testing.T) {
+	rule := &notificationRule{events: []string{"s3:ObjectCreated:*", "s3:ObjectRemoved:DeleteMarkerCreated"}, prefix: "logs/", suffix: ".gz"}
+	assert.True(t, rule.matches("ObjectCreated:Put", "logs/a.gz"))
+	assert.True(t, rule.matches("ObjectCreated:CompleteMultipartUpload", "logs/b/c.gz"))
+	assert.True(t, rule.matches("ObjectRemoved:DeleteMarkerCreated", "logs/a.gz"))
+	assert.False(t, rule.matches("ObjectRemoved:Delete", "logs/a.gz"))
+	assert.False(t, rule.matches("ObjectCreated:Put", "data/a.gz"))
+	assert.False(t, rule.matches("ObjectCreated:Put", "logs/a.txt"))
+}
+
+func TestToBucketEvents(t *testing.T) {
+	s3a := &S3ApiServer{option: &S3ApiServerOption{BucketsPath: "/buckets"}}
+	object := func(name string, extended map[string][]byte, fileIds ...string) *filer_pb.Entry {
+		entry := &filer_pb.Entry{Name: name, Extended: extended, Attributes: &filer_pb.FuseAttributes{Mtime: 1, FileSize: 10, Md5: []byte{0xab, 0xcd}}}
+		for _, fileId := range fileIds {
+			entry.Chunks = append(entry.Chunks, &filer_pb.FileChunk{FileId: fileId, Size: 10})
+		}
+		return entry
+	}
+	toEvents := func(directory string, oldEntry, newEntry *filer_pb.Entry, newParentPath string) (names []string) {
+		events := s3a.toBucketEvents(&filer_pb.SubscribeMetadataResponse{
+			Directory: directory,
+			EventNotification: &filer_pb.EventNotification{
+				OldEntry:      oldEntry,
+				NewEntry:      newEntry,
+				NewParentPath: newParentPath,
+			},
+			TsNs: 1,
+		})
+		for _, event := range events {
+			names = append(names, event.bucket+" "+event.key+" "+event.eventName+" "+event.versionId)
+		}
+		return
+	}
+
+	assert.Equal(t, []string{"b dir/k ObjectCreated:Put "}, toEvents("/buckets/b/dir", nil, object("k", nil, "1,01"), "/buckets/b/dir"))
+	assert.Equal(t, []string{"b k ObjectCreated:CompleteMultipartUpload "},
+		toEvents("/buckets/b", nil, object("k", map[string][]byte{s3_constants.SeaweedFSUploadId: []byte("u")}, "1,01"), "/buckets/b"))
+	assert.Equal(t, []string{"b k ObjectCreated:Put "}, toEvents("/buckets/b", object("k", nil, "1,01"), object("k", nil, "1,02"), ""), "overwritten")
+	assert.Equal(t, []string{"b k ObjectRemoved:Delete v1"}, toEvents("/buckets/b", object("k", map[string][]byte{s3_constants.AmzVersionId: []byte("v1")}, "1,01"), nil, ""))
+
+	// tagging only changes the metadata
+	tagged := object("k", map[string][]byte{S3TAG_PREFIX + "a": []byte("1")}, "1,01")
+	assert.Equal(t, []string{"b k ObjectTagging:Put "}, toEvents("/buckets/b", object("k", nil, "1,01"), tagged, ""))
+	assert.Equal(t, []string{"b k ObjectTagging:Delete "}, toEvents("/buckets/b", tagged, object("k", nil, "1,01"), ""))
+	assert.Nil(t, toEvents("/buckets/b", object("k", nil, "1,01"), object("k", map[string][]byte{s3_constants.ExtAmzOwnerKey: []byte("o")}, "1,01"), ""))
+
+	// versions moved into and out of the versions folder
+	versioned := object("k", map[string][]byte{s3_constants.AmzVersionId: []byte("v1")}, "1,01")
+	archived := object("v1", versioned.Extended, "1,01")
+	assert.Nil(t, toEvents("/buckets/b", versioned, archived, "/buckets/b/.versions/k"))
+	assert.Nil(t, toEvents("/buckets/b/.versions/k", archived, versioned, "/buckets/b"))
+	assert.Equal(t, []string{"b k ObjectRemoved:Delete v1"}, toEvents("/buckets/b/.versions/k", archived, nil, ""))
+	marker := object("v2", map[string][]byte{s3_constants.AmzVersionId: []byte("v2"), s3_constants.ExtDeleteMarkerKey: []byte("true")})
+	assert.Equal(t, []string{"b k ObjectRemoved:DeleteMarkerCreated v2"}, toEvents("/buckets/b/.versions/k", nil, marker, "/buckets/b/.versions/k"))
+
+	// a new version written aside, and then moved to the object location
+	staged := object("v3"+stagedVersionSuffix, map[string][]byte{s3_constants.AmzVersionId: []byte("v3")}, "1,03")
+	current := object("k", staged.Extended, "1,03")
+	assert.Nil(t, toEvents("/buckets/b/.versions/k", nil, staged, "/buckets/b/.versions/k"))
+	assert.Equal(t, []string{"b k ObjectCreated:Put v3"}, toEvents("/buckets/b/.versions/k", staged, current, "/buckets/b"))
+	assert.Nil(t, toEvents("/buckets/b/.versions/k", staged, nil, ""))
+
+	// renamed by the filer
+	assert.Equal(t, []string{"b a ObjectRemoved:Delete ", "b c/a ObjectCreated:Put "}, toEvents("/buckets/b", object("a", nil, "1,01"), object("a", nil, "1,01"), "/buckets/b/c"))
+
+	// ignored
+	assert.Nil(t, toEvents("/buckets/b/.uploads/u", nil, object("0001_x.part", nil, "1,01"), "/buckets/b/.uploads/u"))
+	assert.Nil(t, toEvents("/buckets/b", nil, &filer_pb.Entry{Name: "dir", IsDirectory: true}, "/buckets/b"))
+	assert.Nil(t, toEvents("/buckets", nil, &filer_pb.Entry{Name: "b", IsDirectory: true}, "/buckets"))
+	assert.Nil(t, toEvents("/etc", nil, object("k", nil, "1,01"), "/etc"))
+}
+
+func TestBucketEventRecords(t *testing.T) {
+	event := &bucketEvent{
+		bucket:    "photos",
+		key:       "2024/a b+c.jpg",
+		eventName: "ObjectCreated:Put",
+		size:      1024,
+		eTag:      "d41d8cd98f00b204e9800998ecf8427e",
+		versionId: "v1",
+		tsNs:      1700000000123000000,
+	}
+	data, err := json.Marshal(event.toRecords("rule1"))
+	assert.Nil(t, err)
+
+	records := &s3EventRecords{}
+	assert.Nil(t, json.Unmarshal(data, records))
+	assert.Equal(t, 1, len(records.Records))
+	record := records.Records[0]
+	assert.Equal(t, "2.1", record.EventVersion)
+	assert.Equal(t, "aws:s3", record.EventSource)
+	assert.Equal(t, "2023-11-14T22:13:20.123Z", record.EventTime)
+	assert.Equal(t, "ObjectCreated:Put", record.EventName)
+	assert.Equal(t, "rule1", record.S3.ConfigurationId)
+	assert.Equal(t, "arn:aws:s3:::photos", record.S3.Bucket.Arn)
+	assert.Equal(t, "2024/a+b%2Bc.jpg", record.S3.Object.Key)
+	assert.Equal(t, int64(1024), *record.S3.Object.Size)
+	assert.Equal(t, "v1", record.S3.Object.VersionId)
+
+	event.eventName, event.size, event.eTag = "ObjectRemoved:Delete", -1, ""
+	data, err = json.Marshal(event.toRecords("rule1"))
+	assert.Nil(t, err)
+	assert.NotContains(t, string(data), `"size"`)
+	assert.NotContains(t, string(data), `"eTag"`)
+}
+
+type testMessageQueue struct {
+	notification.MessageQueue
+	sync.Mutex
+	failures int
+	keys     []string
+}
+
+func (q *testMessageQueue) SendRawMessage(key string, message []byte) error {
+	q.Lock()
+	defer q.Unlock()
+	if q.failures > 0 {
+		q.failures--
+		return errors.New("unavailable")
+	}
+	q.keys = append(q.keys, key)
+	return nil
+}
+
+func (q *testMessageQueue) sentKeys() []string {
+	q.Lock()
+	defer q.Unlock()
+	return append([]string(nil), q.keys...)
+}
+
+func TestBucketNotificationSenders(t *testing.T) {
+	senders := newBucketNotificationSenders()
+	senders.retryInterval = 100 * time.Millisecond
+	failing := &testMessageQueue{failures: 2}
+	working := &testMessageQueue{}
+
+	first := &bucketEvent{bucket: "b", key: "first", tsNs: 100}
+	second := &bucketEvent{bucket: "b", key: "second", tsNs: 200}
+	senders.send("failing", failing, first, nil)
+	senders.send("failing", failing, second, nil)
+	senders.send("working", working, first, nil)
+	senders.send("working", working, second, nil)
+
+	// the retries of the failing target do not hold up the other target
+	assert.Eventually(t, func() bool {
+		return len(working.sentKeys()) == 2
+	}, time.Second, 10*time.Millisecond)
+	assert.Empty(t, failing.sentKeys())
+	assert.Equal(t, int64(99), senders.safeOffset(200))
+
+	// the notifications of a target are sent in order once it recovers
+	assert.Eventually(t, func() bool {
+		return len(failing.sentKeys()) == 2
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"b/first", "b/second"}, failing.sentKeys())
+	assert.Equal(t, int64(200), senders.safeOffset(200))
+}
+
+func TestBucketNotificationSendersBacklog(t *testing.T) {
+	senders := newBucketNotificationSenders()
+	senders.retryInterval = 100 * time.Millisecond
+	senders.backlog = 1
+	failing := &testMessageQueue{failures: 2}
+
+	// the first one is being retried, and the second one fills the backlog
+	senders.send("failing", failing, &bucketEvent{bucket: "b", key: "first", tsNs: 100}, nil)
+	senders.send("failing", failing, &bucketEvent{bucket: "b", key: "second", tsNs: 200}, nil)
+	sent := make(chan struct{})
+	go func() {
+		senders.send("failing", failing, &bucketEvent{bucket: "b", key: "third", tsNs: 300}, nil)
+		close(sent)
+	}()
+
+	// the third one waits, instead of being dropped past the saved offset
+	assert.Never(t, func() bool {
+		select {
+		case <-sent:
+			return true
+		default:
+			return false
+		}
+	}, 100*time.Millisecond, 10*time.Millisecond)
+	assert.Equal(t, int64(99), senders.safeOffset(300))
+
+	assert.Eventually(t, func() bool {
+		return len(failing.sentKeys()) == 3
+	}, 2*time.Second, 10*time.Millisecond)
+	<-sent
+	assert.Equal(t, []string{"b/first", "b/second", "b/third"}, failing.sentKeys())
+	assert.Equal(t, int64(300), senders.safeOffset(300))
+}
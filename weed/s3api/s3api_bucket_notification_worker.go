@@ -0,0 +1,517 @@
+package s3api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/seaweedfs/seaweedfs/weed/cluster"
+	"github.com/seaweedfs/seaweedfs/weed/filer"
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/notification"
+	"github.com/seaweedfs/seaweedfs/weed/pb"
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3_constants"
+	"github.com/seaweedfs/seaweedfs/weed/util"
+)
+
+const (
+	// BucketNotificationLockName makes sure only one s3 gateway sends the bucket event notifications
+	BucketNotificationLockName = "s3.notification"
+
+	bucketNotificationOffsetKey      = "s3.notification.offset"
+	bucketNotificationOffsetInterval = 3 * time.Second
+	bucketNotificationSendAttempts   = 3
+	bucketNotificationRetryInterval  = time.Second
+	// the notifications waiting to be sent to one target, the metadata subscription waits beyond it
+	bucketNotificationBacklog = 1024
+
+	// the buckets have no region, and are reported in the default one
+	bucketNotificationRegion = "us-east-1"
+)
+
+var errNotBucketNotificationLockOwner = errors.New("not the bucket notification lock owner")
+
+// bucketEvent is an object change, before it is matched against the bucket notification rules
+type bucketEvent struct {
+	bucket    string
+	key       string
+	eventName string
+	versionId string
+	size      int64
+	eTag      string
+	owner     string
+	tsNs      int64
+}
+
+// s3EventRecords is the AWS event message structure
+// https://docs.aws.amazon.com/AmazonS3/latest/userguide/notification-content-structure.html
+type s3EventRecords struct {
+	Records []s3EventRecord `json:"Records"`
+}
+
+type s3EventRecord struct {
+	EventVersion      string            `json:"eventVersion"`
+	EventSource       string            `json:"eventSource"`
+	AwsRegion         string            `json:"awsRegion"`
+	EventTime         string            `json:"eventTime"`
+	EventName         string            `json:"eventName"`
+	UserIdentity      s3EventIdentity   `json:"userIdentity"`
+	RequestParameters map[string]string `json:"requestParameters"`
+	ResponseElements  map[string]string `json:"responseElements"`
+	S3                s3EventEntity     `json:"s3"`
+}
+
+type s3EventIdentity struct {
+	PrincipalId string `json:"principalId"`
+}
+
+type s3EventEntity struct {
+	SchemaVersion   string        `json:"s3SchemaVersion"`
+	ConfigurationId string        `json:"configurationId"`
+	Bucket          s3EventBucket `json:"bucket"`
+	Object          s3EventObject `json:"object"`
+}
+
+type s3EventBucket struct {
+	Name          string          `json:"name"`
+	OwnerIdentity s3EventIdentity `json:"ownerIdentity"`
+	Arn           string          `json:"arn"`
+}
+
+type s3EventObject struct {
+	Key       string `json:"key"`
+	Size      *int64 `json:"size,omitempty"`
+	ETag      string `json:"eTag,omitempty"`
+	VersionId string `json:"versionId,omitempty"`
+	Sequencer string `json:"sequencer"`
+}
+
+// loopSendBucketNotifications follows the object changes in all buckets, and sends the
+// notifications configured on the buckets while this s3 gateway holds the notification lock
+func (s3a *S3ApiServer) loopSendBucketNotifications() {
+	self := fmt.Sprintf("s3-%d-%d", s3a.option.Port, s3a.randomClientId)
+	lockClient := cluster.NewLockClient(s3a.option.GrpcDialOption, s3a.option.Filer)
+	lock := lockClient.StartLongLivedLock(BucketNotificationLockName, self, func(newLockOwner string) {
+		glog.V(0).Infof("s3 bucket notifications are sent by %s", newLockOwner)
+	})
+
+	var clientEpoch int32
+	for {
+		if lock.LockOwner() != self {
+			time.Sleep(time.Second)
+			continue
+		}
+		clientEpoch++
+		err := s3a.followBucketEvents(clientEpoch, func() bool {
+			return lock.LockOwner() == self
+		})
+		if err != nil && err != errNotBucketNotificationLockOwner {
+			glog.V(0).Infof("s3 bucket notifications: %v", err)
+			time.Sleep(time.Second)
+		}
+	}
+}
+
+// followBucketEvents sends the notifications from the last saved offset, until the lock is lost
+func (s3a *S3ApiServer) followBucketEvents(clientEpoch int32, isLockOwner func() bool) error {
+	startTsNs, err := s3a.getBucketNotificationOffset()
+	if err != nil {
+		return fmt.Errorf("read offset: %v", err)
+	}
+	if startTsNs == 0 {
+		startTsNs = time.Now().UnixNano()
+	}
+	glog.V(0).Infof("send s3 bucket notifications since %v", time.Unix(0, startTsNs))
+
+	processEventFn := pb.AddOffsetFunc(func(resp *filer_pb.SubscribeMetadataResponse) error {
+		for _, event := range s3a.toBucketEvents(resp) {
+			s3a.sendBucketNotifications(event)
+		}
+		return nil
+	}, bucketNotificationOffsetInterval, func(counter int64, offset int64) error {
+		glog.V(4).Infof("s3 bucket notifications processed %d events, offset %v", counter, time.Unix(0, offset))
+		// resume before the notifications still waiting to be sent
+		return s3a.setBucketNotificationOffset(s3a.notificationSenders.safeOffset(offset))
+	})
+
+	return s3a.WithFilerClient(true, func(client filer_pb.SeaweedFilerClient) error {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		stream, err := client.SubscribeMetadata(ctx, &filer_pb.SubscribeMetadataRequest{
+			ClientName:  BucketNotificationLockName,
+			PathPrefix:  s3a.option.BucketsPath + "/",
+			SinceNs:     startTsNs,
+			ClientId:    s3a.randomClientId,
+			ClientEpoch: clientEpoch,
+		})
+		if err != nil {
+			return fmt.Errorf("subscribe: %v", err)
+		}
+		for {
+			resp, err := stream.Recv()
+			if err != nil {
+				return err
+			}
+			// the new lock owner continues from the last saved offset
+			if !isLockOwner() {
+				return errNotBucketNotificationLockOwner
+			}
+			if err = processEventFn(resp); err != nil {
+				return err
+			}
+		}
+	})
+}
+
+func (s3a *S3ApiServer) getBucketNotificationOffset() (offsetTsNs int64, err error) {
+	err = s3a.WithFilerClient(false, func(client filer_pb.SeaweedFilerClient) error {
+		resp, err := client.KvGet(context.Background(), &filer_pb.KvGetRequest{Key: []byte(bucketNotificationOffsetKey)})
+		if err != nil {
+			return err
+		}
+		if len(resp.Error) != 0 {
+			return errors.New(resp.Error)
+		}
+		if len(resp.Value) < 8 {
+			return nil
+		}
+		offsetTsNs = int64(util.BytesToUint64(resp.Value))
+		return nil
+	})
+	return
+}
+
+func (s3a *S3ApiServer) setBucketNotificationOffset(offsetTsNs int64) error {
+	return s3a.WithFilerClient(false, func(client filer_pb.SeaweedFilerClient) error {
+		value := make([]byte, 8)
+		util.Uint64toBytes(value, uint64(offsetTsNs))
+		resp, err := client.KvPut(context.Background(), &filer_pb.KvPutRequest{
+			Key:   []byte(bucketNotificationOffsetKey),
+			Value: value,
+		})
+		if err != nil {
+			return err
+		}
+		if len(resp.Error) != 0 {
+			return errors.New(resp.Error)
+		}
+		return nil
+	})
+}
+
+// toBucketEvents translates a filer metadata event into object events.
+// The moves of versions into and out of the versions folder are not object changes,
+// and the multipart upload parts are ignored.
+func (s3a *S3ApiServer) toBucketEvents(resp *filer_pb.SubscribeMetadataResponse) (events []*bucketEvent) {
+	message := resp.EventNotification
+	oldEntry, newEntry := message.OldEntry, message.NewEntry
+	var oldPath, newPath string
+	if oldEntry != nil {
+		oldPath = string(util.NewFullPath(resp.Directory, oldEntry.Name))
+	}
+	if newEntry != nil {
+		newPath = string(util.NewFullPath(util.Nvl(message.NewParentPath, resp.Directory), newEntry.Name))
+	}
+
+	switch {
+	case oldEntry == nil && newEntry != nil:
+		events = s3a.appendCreatedEvent(events, newPath, newEntry, resp.TsNs)
+	case oldEntry != nil && newEntry == nil:
+		events = s3a.appendRemovedEvent(events, oldPath, oldEntry, resp.TsNs)
+	case oldEntry != nil && newEntry != nil && oldPath == newPath:
+		bucket, key, versionId, ok := s3a.parseObjectPath(newPath)
+		if !ok || versionId != "" || newEntry.IsDirectory {
+			return
+		}
+		if !isObjectMetadataUpdate(oldEntry, newEntry) {
+			return s3a.appendCreatedEvent(events, newPath, newEntry, resp.TsNs)
+		}
+		oldTags, newTags := getObjectTags(oldEntry), getObjectTags(newEntry)
+		if len(newTags) > 0 && !tagsEqual(oldTags, newTags) {
+			events = append(events, newBucketEvent(bucket, key, "ObjectTagging:Put", newEntry, resp.TsNs))
+		} else if len(newTags) == 0 && len(oldTags) > 0 {
+			events = append(events, newBucketEvent(bucket, key, "ObjectTagging:Delete", newEntry, resp.TsNs))
+		}
+	case oldEntry != nil && newEntry != nil:
+		_, _, oldVersionId, _ := s3a.parseObjectPath(oldPath)
+		_, _, newVersionId, _ := s3a.parseObjectPath(newPath)
+		if oldVersionId != "" || newVersionId != "" {
+			return
+		}
+		events = s3a.appendRemovedEvent(events, oldPath, oldEntry, resp.TsNs)
+		events = s3a.appendCreatedEvent(events, newPath, newEntry, resp.TsNs)
+	}
+	return
+}
+
+func (s3a *S3ApiServer) appendCreatedEvent(events []*bucketEvent, fullPath string, entry *filer_pb.Entry, tsNs int64) []*bucketEvent {
+	bucket, key, versionId, ok := s3a.parseObjectPath(fullPath)
+	if !ok || entry.IsDirectory {
+		return events
+	}
+	if versionId != "" {
+		// only the delete markers are created in the versions folder, the other versions are moved there
+		if !isDeleteMarker(entry) {
+			return events
+		}
+		event := newBucketEvent(bucket, key, "ObjectRemoved:DeleteMarkerCreated", entry, tsNs)
+		event.size, event.eTag = -1, ""
+		return append(events, event)
+	}
+	eventName := "ObjectCreated:Put"
+	if _, found := entry.Extended[s3_constants.SeaweedFSUploadId]; found {
+		eventName = "ObjectCreated:CompleteMultipartUpload"
+	}
+	return append(events, newBucketEvent(bucket, key, eventName, entry, tsNs))
+}
+
+func (s3a *S3ApiServer) appendRemovedEvent(events []*bucketEvent, fullPath string, entry *filer_pb.Entry, tsNs int64) []*bucketEvent {
+	bucket, key, _, ok := s3a.parseObjectPath(fullPath)
+	if !ok || entry.IsDirectory {
+		return events
+	}
+	event := newBucketEvent(bucket, key, "ObjectRemoved:Delete", entry, tsNs)
+	event.size, event.eTag = -1, ""
+	return append(events, event)
+}
+
+func newBucketEvent(bucket, key, eventName string, entry *filer_pb.Entry, tsNs int64) *bucketEvent {
+	event := &bucketEvent{
+		bucket:    bucket,
+		key:       key,
+		eventName: eventName,
+		size:      int64(filer.FileSize(entry)),
+		eTag:      filer.ETag(entry),
+		owner:     string(entry.Extended[s3_constants.ExtAmzOwnerKey]),
+		tsNs:      tsNs,
+	}
+	if versionId, found := entry.Extended[s3_constants.AmzVersionId]; found {
+		event.versionId = string(versionId)
+	}
+	return event
+}
+
+// parseObjectPath splits the full path of an object, or of an object version in the versions folder.
+// The version id is only set for the versions folder.
+func (s3a *S3ApiServer) parseObjectPath(fullPath string) (bucket, key, versionId string, ok bool) {
+	bucketAndKey, found := strings.CutPrefix(fullPath, s3a.option.BucketsPath+"/")
+	if !found {
+		return
+	}
+	bucket, key, found = strings.Cut(bucketAndKey, "/")
+	if !found || key == "" {
+		return
+	}
+	if key == s3_constants.MultipartUploadsFolder || strings.HasPrefix(key, s3_constants.MultipartUploadsFolder+"/") {
+		return
+	}
+	if key == s3_constants.TrashFolder || strings.HasPrefix(key, s3_constants.TrashFolder+"/") {
+		return
+	}
+	if versionedKey, isVersion := strings.CutPrefix(key, s3_constants.VersionsFolder+"/"); isVersion {
+		i := strings.LastIndex(versionedKey, "/")
+		if i <= 0 {
+			return
+		}
+		key, versionId = versionedKey[:i], versionedKey[i+1:]
+		if strings.HasSuffix(versionId, stagedVersionSuffix) {
+			// a new object body being written, it is an object change once moved to the object location
+			return "", "", "", false
+		}
+	} else if key == s3_constants.VersionsFolder {
+		return
+	}
+	return bucket, key, versionId, true
+}
+
+// isObjectMetadataUpdate checks whether only the metadata of the object is changed, e.g. the tags
+func isObjectMetadataUpdate(oldEntry, newEntry *filer_pb.Entry) bool {
+	if oldEntry.Attributes.GetMtime() != newEntry.Attributes.GetMtime() || string(oldEntry.Content) != string(newEntry.Content) {
+		return false
+	}
+	oldChunks, newChunks := oldEntry.GetChunks(), newEntry.GetChunks()
+	if len(oldChunks) != len(newChunks) {
+		return false
+	}
+	for i := range oldChunks {
+		if oldChunks[i].GetFileIdString() != newChunks[i].GetFileIdString() {
+			return false
+		}
+	}
+	return true
+}
+
+func getObjectTags(entry *filer_pb.Entry) map[string]string {
+	tags := make(map[string]string)
+	for k, v := range entry.Extended {
+		if strings.HasPrefix(k, S3TAG_PREFIX) {
+			tags[k[len(S3TAG_PREFIX):]] = string(v)
+		}
+	}
+	return tags
+}
+
+func tagsEqual(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if value, found := b[k]; !found || value != v {
+			return false
+		}
+	}
+	return true
+}
+
+// sendBucketNotifications sends the event to the targets of the matching bucket notification rules
+func (s3a *S3ApiServer) sendBucketNotifications(event *bucketEvent) {
+	notificationConfig := s3a.getNotificationConfiguration(event.bucket)
+	if notificationConfig == nil {
+		return
+	}
+	for _, rule := range notificationConfig.rules() {
+		if !rule.matches(event.eventName, event.key) {
+			continue
+		}
+		targetName := notificationTargetName(rule.arn)
+		queue, found := s3a.notificationQueues[targetName]
+		if !found {
+			glog.Warningf("bucket %s notification %s: target %s is not configured", event.bucket, rule.id, targetName)
+			continue
+		}
+		message, err := json.Marshal(event.toRecords(rule.id))
+		if err != nil {
+			glog.Errorf("marshal bucket %s notification: %v", event.bucket, err)
+			continue
+		}
+		s3a.notificationSenders.send(targetName, queue, event, message)
+	}
+}
+
+// bucketNotificationSenders sends the notifications of each target in order, in its own goroutine,
+// so that a failing target holds up the metadata subscription, and so the other targets, only once its backlog is full
+type bucketNotificationSenders struct {
+	sync.Mutex
+	senders       map[string]chan *bucketNotification
+	pending       map[int64]int // event tsNs -> the number of its notifications not sent yet
+	retryInterval time.Duration
+	backlog       int
+}
+
+type bucketNotification struct {
+	event   *bucketEvent
+	message []byte
+}
+
+func newBucketNotificationSenders() *bucketNotificationSenders {
+	return &bucketNotificationSenders{
+		senders:       make(map[string]chan *bucketNotification),
+		pending:       make(map[int64]int),
+		retryInterval: bucketNotificationRetryInterval,
+		backlog:       bucketNotificationBacklog,
+	}
+}
+
+// send queues the notification for the target, starting the sender of the target if needed.
+// It waits while the backlog of the target is full, instead of dropping the notification,
+// and the notification is counted as pending first, so the saved offset does not move past it meanwhile.
+func (s *bucketNotificationSenders) send(targetName string, queue notification.MessageQueue, event *bucketEvent, message []byte) {
+	s.Lock()
+	notifications, found := s.senders[targetName]
+	if !found {
+		notifications = make(chan *bucketNotification, s.backlog)
+		s.senders[targetName] = notifications
+		go s.loopSend(targetName, queue, notifications)
+	}
+	s.pending[event.tsNs]++
+	s.Unlock()
+
+	select {
+	case notifications <- &bucketNotification{event: event, message: message}:
+	default:
+		glog.Warningf("bucket %s notification %s %s waits for %d notifications to %s", event.bucket, event.eventName, event.key, s.backlog, targetName)
+		notifications <- &bucketNotification{event: event, message: message}
+	}
+}
+
+func (s *bucketNotificationSenders) loopSend(targetName string, queue notification.MessageQueue, notifications chan *bucketNotification) {
+	for n := range notifications {
+		event := n.event
+		var err error
+		for attempt := 1; attempt <= bucketNotificationSendAttempts; attempt++ {
+			if err = queue.SendRawMessage(event.bucket+"/"+event.key, n.message); err == nil {
+				break
+			}
+			glog.Warningf("send bucket %s notification %s %s to %s, attempt %d: %v", event.bucket, event.eventName, event.key, targetName, attempt, err)
+			if attempt < bucketNotificationSendAttempts {
+				time.Sleep(time.Duration(attempt) * s.retryInterval)
+			}
+		}
+		if err != nil {
+			glog.Errorf("drop bucket %s notification %s %s to %s: %v", event.bucket, event.eventName, event.key, targetName, err)
+		}
+		s.done(event.tsNs)
+	}
+}
+
+func (s *bucketNotificationSenders) done(tsNs int64) {
+	s.Lock()
+	defer s.Unlock()
+	if s.pending[tsNs]--; s.pending[tsNs] <= 0 {
+		delete(s.pending, tsNs)
+	}
+}
+
+// safeOffset moves the offset before the oldest notification not sent yet,
+// so that it is sent again if the notifications are resumed from the offset
+func (s *bucketNotificationSenders) safeOffset(offset int64) int64 {
+	s.Lock()
+	defer s.Unlock()
+	for tsNs := range s.pending {
+		if tsNs <= offset {
+			offset = tsNs - 1
+		}
+	}
+	return offset
+}
+
+// toRecords builds the AWS event message of the bucket notification rule
+func (event *bucketEvent) toRecords(configurationId string) *s3EventRecords {
+	object := s3EventObject{
+		Key:       strings.ReplaceAll(url.QueryEscape(event.key), "%2F", "/"),
+		ETag:      event.eTag,
+		VersionId: event.versionId,
+		Sequencer: fmt.Sprintf("%016X", event.tsNs),
+	}
+	if event.size >= 0 {
+		size := event.size
+		object.Size = &size
+	}
+	return &s3EventRecords{
+		Records: []s3EventRecord{{
+			EventVersion:      "2.1",
+			EventSource:       "aws:s3",
+			AwsRegion:         bucketNotificationRegion,
+			EventTime:         time.Unix(0, event.tsNs).UTC().Format("2006-01-02T15:04:05.000Z"),
+			EventName:         event.eventName,
+			UserIdentity:      s3EventIdentity{PrincipalId: event.owner},
+			RequestParameters: map[string]string{"sourceIPAddress": ""},
+			ResponseElements:  map[string]string{},
+			S3: s3EventEntity{
+				SchemaVersion:   "1.0",
+				ConfigurationId: configurationId,
+				Bucket: s3EventBucket{
+					Name: event.bucket,
+					Arn:  "arn:aws:s3:::" + event.bucket,
+				},
+				Object: object,
+			},
+		}},
+	}
+}
@@ -0,0 +1,245 @@
+package s3api
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3_constants"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3err"
+)
+
+// WebsiteConfiguration is the bucket static website configuration, stored as xml in the bucket entry Extended[ExtWebsiteKey].
+// The website is served on the website listener of the gateway.
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_WebsiteConfiguration.html
+type WebsiteConfiguration struct {
+	XMLName               xml.Name               `xml:"WebsiteConfiguration"`
+	ErrorDocument         *ErrorDocument         `xml:"ErrorDocument,omitempty"`
+	IndexDocument         *IndexDocument         `xml:"IndexDocument,omitempty"`
+	RedirectAllRequestsTo *RedirectAllRequestsTo `xml:"RedirectAllRequestsTo,omitempty"`
+	RoutingRules          []RoutingRule          `xml:"RoutingRules>RoutingRule,omitempty"`
+}
+
+type ErrorDocument struct {
+	Key string `xml:"Key"`
+}
+
+type IndexDocument struct {
+	Suffix string `xml:"Suffix"`
+}
+
+type RedirectAllRequestsTo struct {
+	HostName string `xml:"HostName"`
+	Protocol string `xml:"Protocol,omitempty"`
+}
+
+type RoutingRule struct {
+	Condition *RoutingRuleCondition `xml:"Condition,omitempty"`
+	Redirect  *RoutingRuleRedirect  `xml:"Redirect"`
+}
+
+type RoutingRuleCondition struct {
+	HttpErrorCodeReturnedEquals string `xml:"HttpErrorCodeReturnedEquals,omitempty"`
+	KeyPrefixEquals             string `xml:"KeyPrefixEquals,omitempty"`
+}
+
+type RoutingRuleRedirect struct {
+	HostName             string  `xml:"HostName,omitempty"`
+	HttpRedirectCode     string  `xml:"HttpRedirectCode,omitempty"`
+	Protocol             string  `xml:"Protocol,omitempty"`
+	ReplaceKeyPrefixWith *string `xml:"ReplaceKeyPrefixWith,omitempty"`
+	ReplaceKeyWith       string  `xml:"ReplaceKeyWith,omitempty"`
+}
+
+const maxWebsiteRoutingRules = 50
+
+func (c *WebsiteConfiguration) validate() s3err.ErrorCode {
+	if c.RedirectAllRequestsTo != nil {
+		if c.IndexDocument != nil || c.ErrorDocument != nil || len(c.RoutingRules) > 0 {
+			return s3err.ErrInvalidRequest
+		}
+		if c.RedirectAllRequestsTo.HostName == "" || !isValidRedirectProtocol(c.RedirectAllRequestsTo.Protocol) {
+			return s3err.ErrInvalidRequest
+		}
+		return s3err.ErrNone
+	}
+
+	if c.IndexDocument == nil || c.IndexDocument.Suffix == "" || strings.Contains(c.IndexDocument.Suffix, "/") {
+		return s3err.ErrInvalidRequest
+	}
+	if c.ErrorDocument != nil && c.ErrorDocument.Key == "" {
+		return s3err.ErrInvalidRequest
+	}
+	if len(c.RoutingRules) > maxWebsiteRoutingRules {
+		return s3err.ErrInvalidRequest
+	}
+	for _, rule := range c.RoutingRules {
+		if rule.Redirect == nil {
+			return s3err.ErrMalformedXML
+		}
+		if condition := rule.Condition; condition != nil {
+			if condition.KeyPrefixEquals == "" && condition.HttpErrorCodeReturnedEquals == "" {
+				return s3err.ErrInvalidRequest
+			}
+			if condition.HttpErrorCodeReturnedEquals != "" {
+				if code, err := strconv.Atoi(condition.HttpErrorCodeReturnedEquals); err != nil || code < 400 || code > 599 {
+					return s3err.ErrInvalidRequest
+				}
+			}
+		}
+		redirect := rule.Redirect
+		if redirect.ReplaceKeyWith != "" && redirect.ReplaceKeyPrefixWith != nil {
+			return s3err.ErrInvalidRequest
+		}
+		if redirect.HttpRedirectCode != "" {
+			if code, err := strconv.Atoi(redirect.HttpRedirectCode); err != nil || code < 300 || code > 399 {
+				return s3err.ErrInvalidRequest
+			}
+		}
+		if !isValidRedirectProtocol(redirect.Protocol) {
+			return s3err.ErrInvalidRequest
+		}
+	}
+	return s3err.ErrNone
+}
+
+func isValidRedirectProtocol(protocol string) bool {
+	return protocol == "" || protocol == "http" || protocol == "https"
+}
+
+// findRoutingRule returns the first routing rule matching the key, before the object is looked up if
+// httpErrorCode is 0, or after the lookup failed with the http error code
+func (c *WebsiteConfiguration) findRoutingRule(key string, httpErrorCode int) *RoutingRule {
+	for i := range c.RoutingRules {
+		rule := &c.RoutingRules[i]
+		condition := rule.Condition
+		if condition == nil {
+			if httpErrorCode == 0 {
+				return rule
+			}
+			continue
+		}
+		if !strings.HasPrefix(key, condition.KeyPrefixEquals) {
+			continue
+		}
+		if condition.HttpErrorCodeReturnedEquals == "" && httpErrorCode == 0 ||
+			condition.HttpErrorCodeReturnedEquals != "" && condition.HttpErrorCodeReturnedEquals == strconv.Itoa(httpErrorCode) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// redirectLocation is the location and status code of the redirect of the key by the rule
+func (rule *RoutingRule) redirectLocation(r *http.Request, key string) (location string, statusCode int) {
+	redirect := rule.Redirect
+	if redirect.ReplaceKeyWith != "" {
+		key = redirect.ReplaceKeyWith
+	} else if redirect.ReplaceKeyPrefixWith != nil {
+		prefix := ""
+		if rule.Condition != nil {
+			prefix = rule.Condition.KeyPrefixEquals
+		}
+		key = *redirect.ReplaceKeyPrefixWith + strings.TrimPrefix(key, prefix)
+	}
+
+	statusCode = http.StatusMovedPermanently
+	if redirect.HttpRedirectCode != "" {
+		statusCode, _ = strconv.Atoi(redirect.HttpRedirectCode)
+	}
+	return websiteUrl(r, redirect.Protocol, redirect.HostName, (&url.URL{Path: "/" + key}).EscapedPath()), statusCode
+}
+
+// websiteUrl builds the url on the host, the host of the request by default
+func websiteUrl(r *http.Request, protocol, hostName, requestUri string) string {
+	if protocol == "" {
+		protocol = "http"
+		if r.TLS != nil {
+			protocol = "https"
+		}
+	}
+	if hostName == "" {
+		hostName = r.Host
+	}
+	return protocol + "://" + hostName + requestUri
+}
+
+// GetBucketWebsiteHandler Get bucket website configuration
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_GetBucketWebsite.html
+func (s3a *S3ApiServer) GetBucketWebsiteHandler(w http.ResponseWriter, r *http.Request) {
+	bucket, _ := s3_constants.GetBucketAndObject(r)
+	glog.V(3).Infof("GetBucketWebsiteHandler %s", bucket)
+
+	if err := s3a.checkBucket(r, bucket); err != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, err)
+		return
+	}
+	bucketMetadata, errCode := s3a.bucketRegistry.GetBucketMetadata(bucket)
+	if errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+	if bucketMetadata.Website == nil {
+		s3err.WriteErrorResponse(w, r, s3err.ErrNoSuchWebsiteConfiguration)
+		return
+	}
+
+	writeSuccessResponseXML(w, r, bucketMetadata.Website)
+}
+
+// PutBucketWebsiteHandler Put bucket website configuration
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutBucketWebsite.html
+func (s3a *S3ApiServer) PutBucketWebsiteHandler(w http.ResponseWriter, r *http.Request) {
+	bucket, _ := s3_constants.GetBucketAndObject(r)
+	glog.V(3).Infof("PutBucketWebsiteHandler %s", bucket)
+
+	if err := s3a.checkBucket(r, bucket); err != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, err)
+		return
+	}
+
+	websiteConfig := WebsiteConfiguration{}
+	if err := xmlDecoder(r.Body, &websiteConfig, r.ContentLength); err != nil {
+		glog.Warningf("PutBucketWebsiteHandler xml decode: %s", err)
+		s3err.WriteErrorResponse(w, r, s3err.ErrMalformedXML)
+		return
+	}
+	if errCode := websiteConfig.validate(); errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+
+	websiteConfigBytes, err := xml.Marshal(websiteConfig)
+	if err != nil {
+		s3err.WriteErrorResponse(w, r, s3err.ErrInternalError)
+		return
+	}
+	if errCode := s3a.updateBucketExtended(bucket, s3_constants.ExtWebsiteKey, websiteConfigBytes); errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+
+	writeSuccessResponseEmpty(w, r)
+}
+
+// DeleteBucketWebsiteHandler Delete bucket website configuration
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_DeleteBucketWebsite.html
+func (s3a *S3ApiServer) DeleteBucketWebsiteHandler(w http.ResponseWriter, r *http.Request) {
+	bucket, _ := s3_constants.GetBucketAndObject(r)
+	glog.V(3).Infof("DeleteBucketWebsiteHandler %s", bucket)
+
+	if err := s3a.checkBucket(r, bucket); err != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, err)
+		return
+	}
+
+	if errCode := s3a.updateBucketExtended(bucket, s3_constants.ExtWebsiteKey, nil); errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+
+	s3err.WriteEmptyResponse(w, r, http.StatusNoContent)
+}
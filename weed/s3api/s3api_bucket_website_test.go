@@ -0,0 +1,143 @@
+package s3api
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3err"
+	"github.com/stretchr/testify/assert"
+)
+
+const testWebsiteConfig = `<WebsiteConfiguration>
+  <IndexDocument><Suffix>index.html</Suffix></IndexDocument>
+  <ErrorDocument><Key>error.html</Key></ErrorDocument>
+  <RoutingRules>
+    <RoutingRule>
+      <Condition><KeyPrefixEquals>docs/</KeyPrefixEquals></Condition>
+      <Redirect><ReplaceKeyPrefixWith>documents/</ReplaceKeyPrefixWith></Redirect>
+    </RoutingRule>
+    <RoutingRule>
+      <Condition><KeyPrefixEquals>old/</KeyPrefixEquals></Condition>
+      <Redirect><ReplaceKeyPrefixWith></ReplaceKeyPrefixWith><HttpRedirectCode>302</HttpRedirectCode></Redirect>
+    </RoutingRule>
+    <RoutingRule>
+      <Condition><HttpErrorCodeReturnedEquals>404</HttpErrorCodeReturnedEquals></Condition>
+      <Redirect><HostName>fallback.example.com</HostName><Protocol>https</Protocol><ReplaceKeyWith>404.html</ReplaceKeyWith></Redirect>
+    </RoutingRule>
+  </RoutingRules>
+</WebsiteConfiguration>`
+
+func TestWebsiteConfigurationValidate(t *testing.T) {
+	parse := func(body string) *WebsiteConfiguration {
+		c := &WebsiteConfiguration{}
+		assert.Nil(t, xml.Unmarshal([]byte(body), c))
+		return c
+	}
+
+	c := parse(testWebsiteConfig)
+	assert.Equal(t, s3err.ErrNone, c.validate())
+	assert.Equal(t, 3, len(c.RoutingRules))
+	assert.Equal(t, "", *c.RoutingRules[1].Redirect.ReplaceKeyPrefixWith, "an empty prefix replacement removes the prefix")
+
+	for _, tt := range []struct {
+		body    string
+		errCode s3err.ErrorCode
+	}{
+		{`<WebsiteConfiguration><RedirectAllRequestsTo><HostName>example.com</HostName></RedirectAllRequestsTo></WebsiteConfiguration>`, s3err.ErrNone},
+		{`<WebsiteConfiguration><RedirectAllRequestsTo><HostName>example.com</HostName><Protocol>ftp</Protocol></RedirectAllRequestsTo></WebsiteConfiguration>`, s3err.ErrInvalidRequest},
+		{`<WebsiteConfiguration><RedirectAllRequestsTo><HostName>example.com</HostName></RedirectAllRequestsTo><IndexDocument><Suffix>index.html</Suffix></IndexDocument></WebsiteConfiguration>`, s3err.ErrInvalidRequest},
+		{`<WebsiteConfiguration><ErrorDocument><Key>error.html</Key></ErrorDocument></WebsiteConfiguration>`, s3err.ErrInvalidRequest},
+		{`<WebsiteConfiguration><IndexDocument><Suffix>a/index.html</Suffix></IndexDocument></WebsiteConfiguration>`, s3err.ErrInvalidRequest},
+		{`<WebsiteConfiguration><IndexDocument><Suffix>index.html</Suffix></IndexDocument><RoutingRules><RoutingRule><Condition><KeyPrefixEquals>a</KeyPrefixEquals></Condition></RoutingRule></RoutingRules></WebsiteConfiguration>`, s3err.ErrMalformedXML},
+		{`<WebsiteConfiguration><IndexDocument><Suffix>index.html</Suffix></IndexDocument><RoutingRules><RoutingRule><Redirect><HttpRedirectCode>200</HttpRedirectCode></Redirect></RoutingRule></RoutingRules></WebsiteConfiguration>`, s3err.ErrInvalidRequest},
+		{`<WebsiteConfiguration><IndexDocument><Suffix>index.html</Suffix></IndexDocument><RoutingRules><RoutingRule><Condition><HttpErrorCodeReturnedEquals>200</HttpErrorCodeReturnedEquals></Condition><Redirect><HostName>a</HostName></Redirect></RoutingRule></RoutingRules></WebsiteConfiguration>`, s3err.ErrInvalidRequest},
+		{`<WebsiteConfiguration><IndexDocument><Suffix>index.html</Suffix></IndexDocument><RoutingRules><RoutingRule><Redirect><ReplaceKeyWith>a</ReplaceKeyWith><ReplaceKeyPrefixWith>b</ReplaceKeyPrefixWith></Redirect></RoutingRule></RoutingRules></WebsiteConfiguration>`, s3err.ErrInvalidRequest},
+	} {
+		assert.Equal(t, tt.errCode, parse(tt.body).validate(), tt.body)
+	}
+}
+
+func TestWebsiteRoutingRules(t *testing.T) {
+	c := &WebsiteConfiguration{}
+	assert.Nil(t, xml.Unmarshal([]byte(testWebsiteConfig), c))
+	r := httptest.NewRequest(http.MethodGet, "http://site.example.com/", nil)
+
+	tests := []struct {
+		key           string
+		httpErrorCode int
+		location      string
+		statusCode    int
+	}{
+		{"docs/a b.html", 0, "http://site.example.com/documents/a%20b.html", http.StatusMovedPermanently},
+		{"old/page.html", 0, "http://site.example.com/page.html", http.StatusFound},
+		{"missing.html", 404, "https://fallback.example.com/404.html", http.StatusMovedPermanently},
+		{"missing.html", 0, "", 0},
+		{"private.html", 403, "", 0},
+	}
+	for _, tt := range tests {
+		rule := c.findRoutingRule(tt.key, tt.httpErrorCode)
+		if tt.location == "" {
+			assert.Nil(t, rule, tt.key)
+			continue
+		}
+		location, statusCode := rule.redirectLocation(r, tt.key)
+		assert.Equal(t, tt.location, location, tt.key)
+		assert.Equal(t, tt.statusCode, statusCode, tt.key)
+	}
+}
+
+func TestWebsiteHandler(t *testing.T) {
+	c := &WebsiteConfiguration{}
+	assert.Nil(t, xml.Unmarshal([]byte(testWebsiteConfig), c))
+	s3a := &S3ApiServer{option: &S3ApiServerOption{WebsiteDomainName: "web.example.com"}}
+	s3a.bucketRegistry = &BucketRegistry{
+		metadataCache: map[string]*BucketMetaData{
+			"site":        {Name: "site", Website: c},
+			"www.a.com":   {Name: "www.a.com", Website: &WebsiteConfiguration{RedirectAllRequestsTo: &RedirectAllRequestsTo{HostName: "a.com", Protocol: "https"}}},
+			"not-website": {Name: "not-website"},
+		},
+		notFound: map[string]struct{}{"missing": {}},
+		s3a:      s3a,
+	}
+
+	assert.Equal(t, "site", s3a.websiteBucket("site.web.example.com:8080"))
+	assert.Equal(t, "www.a.com", s3a.websiteBucket("www.a.com"))
+
+	serve := func(method, url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		s3a.WebsiteHandler(w, httptest.NewRequest(method, url, nil))
+		return w
+	}
+
+	w := serve(http.MethodGet, "http://www.a.com/a/b.html?x=1")
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "https://a.com/a/b.html?x=1", w.Header().Get("Location"))
+
+	w = serve(http.MethodGet, "http://site.web.example.com/docs/a.html")
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "http://site.web.example.com/documents/a.html", w.Header().Get("Location"))
+
+	w = serve(http.MethodGet, "http://not-website/a.html")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "NoSuchWebsiteConfiguration")
+
+	w = serve(http.MethodGet, "http://missing/a.html")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "NoSuchBucket")
+
+	w = serve(http.MethodPut, "http://site.web.example.com/a.html")
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+}
+
+func TestWebsiteStatusWriter(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	w := &websiteStatusWriter{ResponseWriter: recorder, statusCode: http.StatusNotFound}
+	w.WriteHeader(http.StatusOK)
+	w.WriteHeader(http.StatusInternalServerError)
+	_, _ = w.Write([]byte("not found"))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Equal(t, "not found", recorder.Body.String())
+}
@@ -0,0 +1,250 @@
+package s3api
+
+import (
+	"strings"
+	"time"
+
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3err"
+)
+
+const (
+	maxLifecycleRules     = 1000
+	maxLifecycleRuleIdLen = 255
+)
+
+// validate checks the lifecycle configuration, with storageClasses listing the storage classes objects can transition to
+func (lc *Lifecycle) validate(storageClasses map[string]string) s3err.ErrorCode {
+	if len(lc.Rules) == 0 || len(lc.Rules) > maxLifecycleRules {
+		return s3err.ErrMalformedXML
+	}
+	ids := make(map[string]struct{})
+	for i := range lc.Rules {
+		rule := &lc.Rules[i]
+		if len(rule.ID) > maxLifecycleRuleIdLen {
+			return s3err.ErrInvalidRequest
+		}
+		if rule.ID != "" {
+			if _, found := ids[rule.ID]; found {
+				return s3err.ErrInvalidRequest
+			}
+			ids[rule.ID] = struct{}{}
+		}
+		if errCode := rule.validate(storageClasses); errCode != s3err.ErrNone {
+			return errCode
+		}
+	}
+	return s3err.ErrNone
+}
+
+func (rule *Rule) validate(storageClasses map[string]string) s3err.ErrorCode {
+	if rule.Status != Enabled && rule.Status != Disabled {
+		return s3err.ErrMalformedXML
+	}
+	if rule.Filter.set && rule.Prefix.set {
+		return s3err.ErrMalformedXML
+	}
+	if rule.Filter.andSet && rule.Filter.tagSet {
+		return s3err.ErrMalformedXML
+	}
+	if gt, lt := rule.objectSizeRange(); gt < 0 || lt < 0 || lt > 0 && gt >= lt {
+		return s3err.ErrInvalidRequest
+	}
+
+	hasAction := false
+	if rule.Expiration.set {
+		actions := 0
+		if rule.Expiration.Days != 0 {
+			actions++
+		}
+		if !rule.Expiration.Date.IsZero() {
+			actions++
+		}
+		if rule.Expiration.DeleteMarker.set {
+			actions++
+		}
+		if actions != 1 || rule.Expiration.Days < 0 {
+			return s3err.ErrMalformedXML
+		}
+		if !isMidnightUTC(rule.Expiration.Date.Time) {
+			return s3err.ErrInvalidRequest
+		}
+		if rule.Expiration.DeleteMarker.set && len(rule.tags()) > 0 {
+			return s3err.ErrInvalidRequest
+		}
+		hasAction = true
+	}
+	for _, transition := range rule.Transitions {
+		if (transition.Days > 0) == !transition.Date.IsZero() || transition.Days < 0 {
+			return s3err.ErrMalformedXML
+		}
+		if !isMidnightUTC(transition.Date.Time) {
+			return s3err.ErrInvalidRequest
+		}
+		if _, found := storageClasses[transition.StorageClass]; !found {
+			return s3err.ErrInvalidStorageClass
+		}
+		hasAction = true
+	}
+	if rule.NoncurrentVersionExpiration != nil {
+		if rule.NoncurrentVersionExpiration.NoncurrentDays <= 0 || rule.NoncurrentVersionExpiration.NewerNoncurrentVersions < 0 {
+			return s3err.ErrInvalidRequest
+		}
+		hasAction = true
+	}
+	if rule.AbortIncompleteMultipartUpload != nil {
+		if rule.AbortIncompleteMultipartUpload.DaysAfterInitiation <= 0 || len(rule.tags()) > 0 {
+			return s3err.ErrInvalidRequest
+		}
+		hasAction = true
+	}
+	if !hasAction {
+		return s3err.ErrInvalidRequest
+	}
+	return s3err.ErrNone
+}
+
+func isMidnightUTC(t time.Time) bool {
+	return t.IsZero() || t.Equal(t.UTC().Truncate(24*time.Hour))
+}
+
+// prefix returns the object key prefix the rule applies to
+func (rule *Rule) prefix() string {
+	switch {
+	case rule.Filter.andSet:
+		return rule.Filter.And.Prefix.val
+	case rule.Filter.Prefix.set:
+		return rule.Filter.Prefix.val
+	}
+	return rule.Prefix.val
+}
+
+func (rule *Rule) tags() []Tag {
+	switch {
+	case rule.Filter.andSet:
+		return rule.Filter.And.Tags
+	case rule.Filter.tagSet:
+		return []Tag{rule.Filter.Tag}
+	}
+	return nil
+}
+
+func (rule *Rule) objectSizeRange() (greaterThan, lessThan int64) {
+	if rule.Filter.andSet {
+		return rule.Filter.And.ObjectSizeGreaterThan, rule.Filter.And.ObjectSizeLessThan
+	}
+	return rule.Filter.ObjectSizeGreaterThan, rule.Filter.ObjectSizeLessThan
+}
+
+// isSimplePrefixRule tells whether the rule only selects objects by key prefix
+func (rule *Rule) isSimplePrefixRule() bool {
+	greaterThan, lessThan := rule.objectSizeRange()
+	return len(rule.tags()) == 0 && greaterThan == 0 && lessThan == 0
+}
+
+// matches tells whether the enabled rule applies to an object with the given key, tags and size
+func (rule *Rule) matches(key string, tags map[string]string, size int64) bool {
+	if rule.Status != Enabled || !strings.HasPrefix(key, rule.prefix()) {
+		return false
+	}
+	for _, tag := range rule.tags() {
+		if value, found := tags[tag.Key]; !found || value != tag.Value {
+			return false
+		}
+	}
+	greaterThan, lessThan := rule.objectSizeRange()
+	if greaterThan > 0 && size <= greaterThan {
+		return false
+	}
+	if lessThan > 0 && size >= lessThan {
+		return false
+	}
+	return true
+}
+
+// lifecycleDueTime is when an action set to run some days after t is due.
+// As with S3, the result is rounded up to the next midnight UTC.
+func lifecycleDueTime(t time.Time, days int) time.Time {
+	return t.UTC().AddDate(0, 0, days).Truncate(24 * time.Hour).Add(24 * time.Hour)
+}
+
+// isExpired tells whether the rule expires a current object last modified at modTime
+func (rule *Rule) isExpired(modTime, now time.Time) bool {
+	switch {
+	case !rule.Expiration.set:
+		return false
+	case rule.Expiration.Days > 0:
+		return !now.Before(lifecycleDueTime(modTime, rule.Expiration.Days))
+	case !rule.Expiration.Date.IsZero():
+		return !now.Before(rule.Expiration.Date.Time)
+	}
+	return false
+}
+
+// expiresDeleteMarker tells whether the rule removes a delete marker created at modTime,
+// once the delete marker is the only version left of the object
+func (rule *Rule) expiresDeleteMarker(modTime, now time.Time) bool {
+	if rule.Expiration.DeleteMarker.set {
+		return rule.Expiration.DeleteMarker.val
+	}
+	return rule.isExpired(modTime, now)
+}
+
+// isNoncurrentExpired tells whether the rule expires a version that became noncurrent at noncurrentTime,
+// with newerNoncurrentVersions noncurrent versions newer than itself
+func (rule *Rule) isNoncurrentExpired(noncurrentTime time.Time, newerNoncurrentVersions int, now time.Time) bool {
+	expiration := rule.NoncurrentVersionExpiration
+	if expiration == nil || newerNoncurrentVersions < expiration.NewerNoncurrentVersions {
+		return false
+	}
+	return !now.Before(lifecycleDueTime(noncurrentTime, expiration.NoncurrentDays))
+}
+
+// isUploadAborted tells whether the rule aborts a multipart upload initiated at initiated
+func (rule *Rule) isUploadAborted(initiated, now time.Time) bool {
+	if rule.AbortIncompleteMultipartUpload == nil {
+		return false
+	}
+	return !now.Before(lifecycleDueTime(initiated, rule.AbortIncompleteMultipartUpload.DaysAfterInitiation))
+}
+
+// dueTransition returns the storage class of the latest transition of the rule due for an object last modified at modTime
+func (rule *Rule) dueTransition(modTime, now time.Time) (storageClass string, dueTime time.Time) {
+	for _, transition := range rule.Transitions {
+		transitionTime := transition.Date.Time
+		if transition.Days > 0 {
+			transitionTime = lifecycleDueTime(modTime, transition.Days)
+		}
+		if !now.Before(transitionTime) && !transitionTime.Before(dueTime) {
+			storageClass, dueTime = transition.StorageClass, transitionTime
+		}
+	}
+	return
+}
+
+// dueTransition returns the storage class an object should be in, among all the rules of the lifecycle
+func (lc *Lifecycle) dueTransition(key string, tags map[string]string, size int64, modTime, now time.Time) (storageClass string) {
+	var latest time.Time
+	for i := range lc.Rules {
+		rule := &lc.Rules[i]
+		if !rule.matches(key, tags, size) {
+			continue
+		}
+		if class, dueTime := rule.dueTransition(modTime, now); class != "" && !dueTime.Before(latest) {
+			storageClass, latest = class, dueTime
+		}
+	}
+	return
+}
+
+// parseStorageClassDiskTypes parses the storage class to disk type mapping, in the form of "STANDARD_IA=hdd,GLACIER=archive"
+func parseStorageClassDiskTypes(value string) map[string]string {
+	storageClasses := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		storageClass, diskType, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found || storageClass == "" {
+			continue
+		}
+		storageClasses[storageClass] = diskType
+	}
+	return storageClasses
+}
@@ -0,0 +1,127 @@
+package s3api
+
+import (
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3err"
+	"github.com/stretchr/testify/assert"
+)
+
+const testLifecycleConfig = `<LifecycleConfiguration>
+  <Rule>
+    <ID>logs</ID>
+    <Filter>
+      <And>
+        <Prefix>logs/</Prefix>
+        <Tag><Key>type</Key><Value>debug</Value></Tag>
+        <ObjectSizeGreaterThan>1024</ObjectSizeGreaterThan>
+      </And>
+    </Filter>
+    <Status>Enabled</Status>
+    <Transition><Days>30</Days><StorageClass>STANDARD_IA</StorageClass></Transition>
+    <Transition><Days>90</Days><StorageClass>GLACIER</StorageClass></Transition>
+    <Expiration><Days>365</Days></Expiration>
+  </Rule>
+  <Rule>
+    <ID>versions</ID>
+    <Filter><Prefix></Prefix></Filter>
+    <Status>Enabled</Status>
+    <NoncurrentVersionExpiration><NoncurrentDays>7</NoncurrentDays><NewerNoncurrentVersions>2</NewerNoncurrentVersions></NoncurrentVersionExpiration>
+    <AbortIncompleteMultipartUpload><DaysAfterInitiation>3</DaysAfterInitiation></AbortIncompleteMultipartUpload>
+  </Rule>
+  <Rule>
+    <ID>tmp</ID>
+    <Prefix>tmp/</Prefix>
+    <Status>Disabled</Status>
+    <Expiration><Date>2024-01-01T00:00:00Z</Date></Expiration>
+  </Rule>
+</LifecycleConfiguration>`
+
+var testStorageClasses = map[string]string{"STANDARD_IA": "hdd", "GLACIER": "archive"}
+
+func TestLifecycleXml(t *testing.T) {
+	lifecycle := &Lifecycle{}
+	assert.Nil(t, xml.Unmarshal([]byte(testLifecycleConfig), lifecycle))
+	assert.Equal(t, s3err.ErrNone, lifecycle.validate(testStorageClasses))
+
+	data, err := xml.Marshal(lifecycle)
+	assert.Nil(t, err)
+	reloaded := &Lifecycle{}
+	assert.Nil(t, xml.Unmarshal(data, reloaded))
+	assert.Equal(t, lifecycle, reloaded)
+
+	assert.Equal(t, "logs/", reloaded.Rules[0].prefix())
+	assert.Equal(t, []Tag{{Key: "type", Value: "debug"}}, reloaded.Rules[0].tags())
+	assert.Equal(t, 2, len(reloaded.Rules[0].Transitions))
+	assert.Equal(t, "", reloaded.Rules[1].prefix())
+	assert.Equal(t, 2, reloaded.Rules[1].NoncurrentVersionExpiration.NewerNoncurrentVersions)
+	assert.Equal(t, "tmp/", reloaded.Rules[2].prefix())
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), reloaded.Rules[2].Expiration.Date.UTC())
+}
+
+func TestLifecycleValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		errCode s3err.ErrorCode
+	}{
+		{"no action", `<Rule><Status>Enabled</Status><Filter><Prefix>a</Prefix></Filter></Rule>`, s3err.ErrInvalidRequest},
+		{"bad status", `<Rule><Status>On</Status><Expiration><Days>1</Days></Expiration></Rule>`, s3err.ErrMalformedXML},
+		{"days and date", `<Rule><Status>Enabled</Status><Expiration><Days>1</Days><Date>2024-01-01T00:00:00Z</Date></Expiration></Rule>`, s3err.ErrMalformedXML},
+		{"date not at midnight", `<Rule><Status>Enabled</Status><Expiration><Date>2024-01-01T10:00:00Z</Date></Expiration></Rule>`, s3err.ErrInvalidRequest},
+		{"unknown storage class", `<Rule><Status>Enabled</Status><Transition><Days>1</Days><StorageClass>DEEP_ARCHIVE</StorageClass></Transition></Rule>`, s3err.ErrInvalidStorageClass},
+		{"delete marker with tags", `<Rule><Status>Enabled</Status><Filter><Tag><Key>a</Key><Value>b</Value></Tag></Filter><Expiration><ExpiredObjectDeleteMarker>true</ExpiredObjectDeleteMarker></Expiration></Rule>`, s3err.ErrInvalidRequest},
+		{"abort with tags", `<Rule><Status>Enabled</Status><Filter><Tag><Key>a</Key><Value>b</Value></Tag></Filter><AbortIncompleteMultipartUpload><DaysAfterInitiation>1</DaysAfterInitiation></AbortIncompleteMultipartUpload></Rule>`, s3err.ErrInvalidRequest},
+		{"bad size range", `<Rule><Status>Enabled</Status><Filter><And><ObjectSizeGreaterThan>10</ObjectSizeGreaterThan><ObjectSizeLessThan>5</ObjectSizeLessThan></And></Filter><Expiration><Days>1</Days></Expiration></Rule>`, s3err.ErrInvalidRequest},
+		{"valid delete marker", `<Rule><Status>Enabled</Status><Expiration><ExpiredObjectDeleteMarker>true</ExpiredObjectDeleteMarker></Expiration></Rule>`, s3err.ErrNone},
+	}
+	for _, tt := range tests {
+		lifecycle := &Lifecycle{}
+		assert.Nil(t, xml.Unmarshal([]byte("<LifecycleConfiguration>"+tt.rule+"</LifecycleConfiguration>"), lifecycle), tt.name)
+		assert.Equal(t, tt.errCode, lifecycle.validate(testStorageClasses), tt.name)
+	}
+
+	duplicated := &Lifecycle{}
+	rule := `<Rule><ID>a</ID><Status>Enabled</Status><Expiration><Days>1</Days></Expiration></Rule>`
+	assert.Nil(t, xml.Unmarshal([]byte("<LifecycleConfiguration>"+rule+rule+"</LifecycleConfiguration>"), duplicated))
+	assert.Equal(t, s3err.ErrInvalidRequest, duplicated.validate(testStorageClasses))
+}
+
+func TestLifecycleRuleEvaluation(t *testing.T) {
+	lifecycle := &Lifecycle{}
+	assert.Nil(t, xml.Unmarshal([]byte(testLifecycleConfig), lifecycle))
+	logs, versions, tmp := &lifecycle.Rules[0], &lifecycle.Rules[1], &lifecycle.Rules[2]
+	debugTags := map[string]string{"type": "debug"}
+
+	assert.True(t, logs.matches("logs/a.log", debugTags, 2048))
+	assert.False(t, logs.matches("logs/a.log", debugTags, 1024))
+	assert.False(t, logs.matches("logs/a.log", map[string]string{"type": "info"}, 2048))
+	assert.False(t, logs.matches("data/a.log", debugTags, 2048))
+	assert.False(t, tmp.matches("tmp/a", nil, 0), "disabled rule")
+
+	modTime := time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC), lifecycleDueTime(modTime, 1))
+
+	assert.False(t, logs.isExpired(modTime, modTime.AddDate(0, 0, 365)))
+	assert.True(t, logs.isExpired(modTime, modTime.AddDate(0, 0, 366)))
+
+	assert.Equal(t, "", lifecycle.dueTransition("logs/a.log", debugTags, 2048, modTime, modTime.AddDate(0, 0, 20)))
+	assert.Equal(t, "STANDARD_IA", lifecycle.dueTransition("logs/a.log", debugTags, 2048, modTime, modTime.AddDate(0, 0, 40)))
+	assert.Equal(t, "GLACIER", lifecycle.dueTransition("logs/a.log", debugTags, 2048, modTime, modTime.AddDate(0, 0, 100)))
+	assert.Equal(t, "", lifecycle.dueTransition("logs/a.log", nil, 2048, modTime, modTime.AddDate(0, 0, 100)))
+
+	assert.False(t, versions.isNoncurrentExpired(modTime, 2, modTime.AddDate(0, 0, 7)))
+	assert.True(t, versions.isNoncurrentExpired(modTime, 2, modTime.AddDate(0, 0, 8)))
+	assert.False(t, versions.isNoncurrentExpired(modTime, 1, modTime.AddDate(0, 0, 8)), "kept as one of the newer noncurrent versions")
+
+	assert.False(t, versions.isUploadAborted(modTime, modTime.AddDate(0, 0, 3)))
+	assert.True(t, versions.isUploadAborted(modTime, modTime.AddDate(0, 0, 4)))
+	assert.False(t, logs.isUploadAborted(modTime, modTime.AddDate(0, 0, 4)))
+}
+
+func TestParseStorageClassDiskTypes(t *testing.T) {
+	assert.Equal(t, testStorageClasses, parseStorageClassDiskTypes("STANDARD_IA=hdd, GLACIER=archive"))
+	assert.Equal(t, map[string]string{}, parseStorageClassDiskTypes(""))
+}
@@ -0,0 +1,480 @@
+package s3api
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/seaweedfs/seaweedfs/weed/cluster"
+	"github.com/seaweedfs/seaweedfs/weed/filer"
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/operation"
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3_constants"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3err"
+)
+
+const (
+	// LifecycleLockName makes sure only one s3 gateway applies the lifecycle rules at a time
+	LifecycleLockName = "s3.lifecycle"
+
+	lifecycleTransitionChunkSize = 8 * 1024 * 1024
+	lifecycleListPageSize        = 1024
+)
+
+// loopApplyLifecycle periodically scans all buckets, and applies their lifecycle rules
+// while this s3 gateway holds the lifecycle lock
+func (s3a *S3ApiServer) loopApplyLifecycle(interval time.Duration) {
+	self := fmt.Sprintf("s3-%d-%d", s3a.option.Port, s3a.randomClientId)
+	lockClient := cluster.NewLockClient(s3a.option.GrpcDialOption, s3a.option.Filer)
+	lock := lockClient.StartLongLivedLock(LifecycleLockName, self, func(newLockOwner string) {
+		glog.V(0).Infof("s3 lifecycle is applied by %s", newLockOwner)
+	})
+
+	for {
+		time.Sleep(interval)
+		if lock.LockOwner() != self {
+			continue
+		}
+		if err := s3a.applyLifecycle(time.Now()); err != nil {
+			glog.Errorf("apply s3 lifecycle: %v", err)
+		}
+	}
+}
+
+func (s3a *S3ApiServer) applyLifecycle(now time.Time) error {
+	err := s3a.listEntryPages(s3a.option.BucketsPath, func(entries []*filer_pb.Entry) error {
+		for _, bucketEntry := range entries {
+			if !bucketEntry.IsDirectory {
+				continue
+			}
+			lifecycleBytes, found := bucketEntry.Extended[s3_constants.ExtLifecycleKey]
+			if !found || len(lifecycleBytes) == 0 {
+				continue
+			}
+			lifecycle := &Lifecycle{}
+			if err := xml.Unmarshal(lifecycleBytes, lifecycle); err != nil {
+				glog.Warningf("Unmarshal lifecycle configuration of bucket %s: %v", bucketEntry.Name, err)
+				continue
+			}
+			worker := &lifecycleWorker{
+				s3a:        s3a,
+				bucket:     bucketEntry.Name,
+				bucketDir:  fmt.Sprintf("%s/%s", s3a.option.BucketsPath, bucketEntry.Name),
+				versioning: string(bucketEntry.Extended[s3_constants.ExtVersioningKey]),
+				lifecycle:  lifecycle,
+				now:        now,
+			}
+			if err := worker.apply(); err != nil {
+				glog.Errorf("apply lifecycle of bucket %s: %v", bucketEntry.Name, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("list buckets: %v", err)
+	}
+	return nil
+}
+
+// listEntryPages lists the directory by pages of lifecycleListPageSize entries,
+// so that only one page of a large directory is kept in memory.
+// A missing directory has no entries.
+func (s3a *S3ApiServer) listEntryPages(dir string, fn func(entries []*filer_pb.Entry) error) error {
+	startFrom := ""
+	for {
+		var entries []*filer_pb.Entry
+		err := filer_pb.List(s3a, dir, "", func(entry *filer_pb.Entry, isLast bool) error {
+			entries = append(entries, entry)
+			return nil
+		}, startFrom, false, lifecycleListPageSize)
+		if err == filer_pb.ErrNotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+		if err = fn(entries); err != nil {
+			return err
+		}
+		if len(entries) < lifecycleListPageSize {
+			return nil
+		}
+		startFrom = entries[len(entries)-1].Name
+	}
+}
+
+// lifecycleWorker applies the lifecycle rules of one bucket
+type lifecycleWorker struct {
+	s3a        *S3ApiServer
+	bucket     string
+	bucketDir  string
+	versioning string
+	lifecycle  *Lifecycle
+	now        time.Time
+}
+
+func (w *lifecycleWorker) apply() error {
+	if err := w.applyToObjects(""); err != nil {
+		return err
+	}
+	if w.versioning != "" {
+		if err := w.applyToVersions(""); err != nil {
+			return err
+		}
+	}
+	return w.abortIncompleteUploads()
+}
+
+// applyToObjects expires or transitions the current objects under the folder relDir
+func (w *lifecycleWorker) applyToObjects(relDir string) error {
+	return w.s3a.listEntryPages(w.dir(relDir), func(entries []*filer_pb.Entry) error {
+		for _, entry := range entries {
+			if relDir == "" && (entry.Name == s3_constants.MultipartUploadsFolder || entry.Name == s3_constants.VersionsFolder || entry.Name == s3_constants.TrashFolder) {
+				continue
+			}
+			key := w.key(relDir, entry.Name)
+			if entry.IsDirectory {
+				if err := w.applyToObjects(key); err != nil {
+					return err
+				}
+				continue
+			}
+			w.applyToObject(relDir, key, entry)
+		}
+		return nil
+	})
+}
+
+func (w *lifecycleWorker) applyToObject(relDir, key string, entry *filer_pb.Entry) {
+	tags, size, modTime := lifecycleEntryTags(entry), int64(filer.FileSize(entry)), time.Unix(entry.Attributes.Mtime, 0)
+
+	for i := range w.lifecycle.Rules {
+		rule := &w.lifecycle.Rules[i]
+		if rule.matches(key, tags, size) && rule.isExpired(modTime, w.now) {
+			w.expireObject(key)
+			return
+		}
+	}
+
+	storageClass := w.lifecycle.dueTransition(key, tags, size, modTime, w.now)
+	if storageClass != "" && storageClass != lifecycleEntryStorageClass(entry) {
+		if err := w.s3a.transitionObject(w.bucket, w.dir(relDir), entry, storageClass); err != nil {
+			glog.Errorf("lifecycle transition %s/%s to %s: %v", w.bucket, key, storageClass, err)
+		}
+	}
+}
+
+func (w *lifecycleWorker) expireObject(key string) {
+	object := "/" + key
+	glog.V(1).Infof("lifecycle expire %s%s", w.bucket, object)
+	if w.versioning != "" {
+		if _, _, errCode := w.s3a.deleteObjectWithVersioning(w.bucket, object, "", w.versioning, false); errCode != s3err.ErrNone {
+			glog.Errorf("lifecycle expire %s%s: %v", w.bucket, object, errCode)
+		}
+		return
+	}
+	dir, name := w.s3a.genObjectDirAndName(w.bucket, object)
+	if err := w.s3a.rm(dir, name, true, false); err != nil {
+		glog.Errorf("lifecycle expire %s%s: %v", w.bucket, object, err)
+		return
+	}
+	w.s3a.deleteEmptyParentDirectories(w.bucket, object)
+}
+
+// applyToVersions expires the noncurrent versions and the delete markers kept under the versions folder relDir.
+// The sub folders are visited page by page, while all the versions of the object are collected.
+func (w *lifecycleWorker) applyToVersions(relDir string) error {
+	var versions []*filer_pb.Entry
+	err := w.s3a.listEntryPages(w.dir(s3_constants.VersionsFolder+"/"+relDir), func(entries []*filer_pb.Entry) error {
+		for _, entry := range entries {
+			if isStagedVersion(entry) {
+				continue
+			}
+			if !entry.IsDirectory {
+				versions = append(versions, entry)
+				continue
+			}
+			if err := w.applyToVersions(w.key(relDir, entry.Name)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(versions) > 0 && relDir != "" {
+		// the files in a versions folder are the versions of the object with the folder key
+		w.expireVersions(relDir, versions)
+	}
+	return nil
+}
+
+func (w *lifecycleWorker) expireVersions(key string, versions []*filer_pb.Entry) {
+	object := "/" + key
+	sortVersionsNewestFirst(versions)
+	current, err := w.s3a.getCurrentObjectEntry(w.bucket, object)
+	if err != nil {
+		glog.Errorf("lifecycle versions of %s%s: %v", w.bucket, object, err)
+		return
+	}
+
+	// all versions newest first, the latest one being the current object or else the latest delete marker
+	all := versions
+	if current != nil {
+		all = append([]*filer_pb.Entry{current}, versions...)
+	}
+
+	remaining := len(versions)
+	for i := 1; i < len(all); i++ {
+		// a version becomes noncurrent when the next version is created
+		version, noncurrentTime := all[i], time.Unix(0, versionTsNs(all[i-1]))
+		tags, size := lifecycleEntryTags(version), int64(filer.FileSize(version))
+		for j := range w.lifecycle.Rules {
+			rule := &w.lifecycle.Rules[j]
+			if rule.matches(key, tags, size) && rule.isNoncurrentExpired(noncurrentTime, i-1, w.now) {
+				if w.deleteVersion(object, getEntryVersionId(version)) {
+					remaining--
+				}
+				break
+			}
+		}
+	}
+
+	// a delete marker without any other version left is expired
+	if current == nil && remaining == 1 && isDeleteMarker(versions[0]) {
+		modTime := time.Unix(0, versionTsNs(versions[0]))
+		for j := range w.lifecycle.Rules {
+			rule := &w.lifecycle.Rules[j]
+			if rule.matches(key, nil, 0) && rule.expiresDeleteMarker(modTime, w.now) {
+				w.deleteVersion(object, getEntryVersionId(versions[0]))
+				break
+			}
+		}
+	}
+}
+
+func (w *lifecycleWorker) deleteVersion(object, versionId string) bool {
+	glog.V(1).Infof("lifecycle expire %s%s version %s", w.bucket, object, versionId)
+	_, _, errCode := w.s3a.deleteObjectVersion(w.bucket, object, versionId, false)
+	switch errCode {
+	case s3err.ErrNone:
+		return true
+	case s3err.ErrObjectLocked:
+		glog.V(1).Infof("lifecycle skip locked %s%s version %s", w.bucket, object, versionId)
+	default:
+		glog.Errorf("lifecycle expire %s%s version %s: %v", w.bucket, object, versionId, errCode)
+	}
+	return false
+}
+
+func (w *lifecycleWorker) abortIncompleteUploads() error {
+	uploadsDir := w.s3a.genUploadsFolder(w.bucket)
+	return w.s3a.listEntryPages(uploadsDir, func(entries []*filer_pb.Entry) error {
+		for _, upload := range entries {
+			if !upload.IsDirectory {
+				continue
+			}
+			key := strings.TrimPrefix(string(upload.Extended["key"]), "/")
+			initiated := time.Unix(upload.Attributes.Crtime, 0)
+			for i := range w.lifecycle.Rules {
+				rule := &w.lifecycle.Rules[i]
+				if rule.matches(key, nil, 0) && rule.isUploadAborted(initiated, w.now) {
+					glog.V(1).Infof("lifecycle abort upload %s of %s/%s", upload.Name, w.bucket, key)
+					if err := w.s3a.rm(uploadsDir, upload.Name, true, true); err != nil {
+						glog.Errorf("lifecycle abort upload %s of %s/%s: %v", upload.Name, w.bucket, key, err)
+					}
+					break
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func (w *lifecycleWorker) dir(relDir string) string {
+	return strings.TrimSuffix(w.bucketDir+"/"+relDir, "/")
+}
+
+func (w *lifecycleWorker) key(relDir, name string) string {
+	if relDir == "" {
+		return name
+	}
+	return relDir + "/" + name
+}
+
+func lifecycleEntryTags(entry *filer_pb.Entry) map[string]string {
+	tags := make(map[string]string)
+	for k, v := range entry.Extended {
+		if strings.HasPrefix(k, S3TAG_PREFIX) {
+			tags[k[len(S3TAG_PREFIX):]] = string(v)
+		}
+	}
+	return tags
+}
+
+func lifecycleEntryStorageClass(entry *filer_pb.Entry) string {
+	if storageClass, found := entry.Extended[s3_constants.AmzStorageClass]; found && len(storageClass) > 0 {
+		return string(storageClass)
+	}
+	return "STANDARD"
+}
+
+// transitionObject moves the object data into volumes of the disk type mapped to the storage class.
+// Volumes of that disk type can be further moved to a remote tier with volume.tier.upload.
+func (s3a *S3ApiServer) transitionObject(bucket, dir string, entry *filer_pb.Entry, storageClass string) (err error) {
+	diskType, found := s3a.storageClassDiskTypes[storageClass]
+	if !found {
+		return fmt.Errorf("unknown storage class %s", storageClass)
+	}
+	glog.V(1).Infof("lifecycle transition %s/%s to %s", dir, entry.Name, storageClass)
+
+	if len(entry.GetChunks()) > 0 {
+		isCipher := false
+		for _, chunk := range entry.GetChunks() {
+			isCipher = isCipher || len(chunk.CipherKey) > 0
+		}
+		// all the copied chunks, including the manifest chunks, are deleted if the transition fails
+		var savedChunks []*filer_pb.FileChunk
+		defer func() {
+			if err != nil {
+				s3a.deleteChunks(savedChunks)
+			}
+		}()
+		saveDataAsChunk := s3a.saveDataAsChunk(bucket, fmt.Sprintf("%s/%s", dir, entry.Name), diskType, isCipher)
+		saveFunc := func(reader io.Reader, name string, offset int64, tsNs int64) (*filer_pb.FileChunk, error) {
+			chunk, saveErr := saveDataAsChunk(reader, name, offset, tsNs)
+			if saveErr == nil {
+				savedChunks = append(savedChunks, chunk)
+			}
+			return chunk, saveErr
+		}
+
+		var chunks []*filer_pb.FileChunk
+		reader := filer.NewFileReader(s3a, entry)
+		buffer := make([]byte, lifecycleTransitionChunkSize)
+		for offset := int64(0); ; {
+			n, readErr := io.ReadFull(reader, buffer)
+			if n > 0 {
+				chunk, saveErr := saveFunc(bytes.NewReader(buffer[:n]), entry.Name, offset, time.Now().UnixNano())
+				if saveErr != nil {
+					return saveErr
+				}
+				chunks = append(chunks, chunk)
+				offset += int64(n)
+			}
+			if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+				break
+			}
+			if readErr != nil {
+				return fmt.Errorf("read %s/%s: %v", dir, entry.Name, readErr)
+			}
+		}
+		manifestizedChunks, manifestErr := filer.MaybeManifestize(saveFunc, chunks)
+		if manifestErr != nil {
+			return fmt.Errorf("manifestize %s/%s: %v", dir, entry.Name, manifestErr)
+		}
+
+		// the object may have changed while its data was copied
+		latest, getErr := s3a.getEntry(dir, entry.Name)
+		if getErr != nil {
+			return getErr
+		}
+		if !filer.IsSameData(latest, entry) {
+			return fmt.Errorf("%s/%s changed during transition", dir, entry.Name)
+		}
+		entry.Chunks = manifestizedChunks
+	}
+
+	if entry.Extended == nil {
+		entry.Extended = make(map[string][]byte)
+	}
+	entry.Extended[s3_constants.AmzStorageClass] = []byte(storageClass)
+	return s3a.updateEntry(dir, entry)
+}
+
+// deleteChunks deletes the chunks uploaded for an entry that was not saved
+func (s3a *S3ApiServer) deleteChunks(chunks []*filer_pb.FileChunk) {
+	if len(chunks) == 0 {
+		return
+	}
+	var fileIds []string
+	for _, chunk := range chunks {
+		fileIds = append(fileIds, chunk.GetFileIdString())
+	}
+	lookupFunc := func(vids []string) (map[string]*operation.LookupResult, error) {
+		results := make(map[string]*operation.LookupResult)
+		err := s3a.WithFilerClient(false, func(client filer_pb.SeaweedFilerClient) error {
+			resp, err := client.LookupVolume(context.Background(), &filer_pb.LookupVolumeRequest{
+				VolumeIds: vids,
+			})
+			if err != nil {
+				return err
+			}
+			for vid, locations := range resp.LocationsMap {
+				result := &operation.LookupResult{VolumeOrFileId: vid}
+				for _, location := range locations.Locations {
+					result.Locations = append(result.Locations, operation.Location{
+						Url:        location.Url,
+						PublicUrl:  location.PublicUrl,
+						DataCenter: location.DataCenter,
+						GrpcPort:   int(location.GrpcPort),
+					})
+				}
+				results[vid] = result
+			}
+			return nil
+		})
+		return results, err
+	}
+	if _, err := operation.DeleteFileIdsWithLookupVolumeId(s3a.option.GrpcDialOption, fileIds, lookupFunc); err != nil {
+		glog.Warningf("delete chunks %v: %v", fileIds, err)
+	}
+}
+
+func (s3a *S3ApiServer) saveDataAsChunk(bucket, fullPath, diskType string, isCipher bool) filer.SaveDataAsChunkFunctionType {
+
+	return func(reader io.Reader, filename string, offset int64, tsNs int64) (chunk *filer_pb.FileChunk, err error) {
+		uploader, err := operation.NewUploader()
+		if err != nil {
+			return
+		}
+
+		fileId, uploadResult, err, _ := uploader.UploadWithRetry(
+			s3a,
+			&filer_pb.AssignVolumeRequest{
+				Count:      1,
+				Collection: s3a.getCollectionName(bucket),
+				DiskType:   diskType,
+				DataCenter: s3a.option.DataCenter,
+				Path:       fullPath,
+			},
+			&operation.UploadOption{
+				Filename: filename,
+				Cipher:   isCipher,
+			},
+			func(host, fileId string) string {
+				return fmt.Sprintf("http://%s/%s", host, fileId)
+			},
+			reader,
+		)
+
+		if err != nil {
+			return nil, fmt.Errorf("upload data: %v", err)
+		}
+		if uploadResult.Error != "" {
+			return nil, fmt.Errorf("upload result: %v", uploadResult.Error)
+		}
+
+		return uploadResult.ToPbFileChunk(fileId, offset, tsNs), nil
+	}
+}
@@ -0,0 +1,200 @@
+package s3api
+
+import (
+	"net/http"
+
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3_constants"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3err"
+)
+
+// objectWriterAccountId is the account of the request, the admin account if the gateway has no identities
+func (s3a *S3ApiServer) objectWriterAccountId(r *http.Request) string {
+	if !s3a.iam.isEnabled() {
+		return AccountAdmin.Id
+	}
+	return getAccountId(r)
+}
+
+// setObjectAclHeaders validates the x-amz-acl and x-amz-grant-* headers of a write request, and sets
+// the object owner and grants to be saved along with the object.
+// Buckets with BucketOwnerEnforced ownership have ACLs disabled, and the objects keep no acl.
+func (s3a *S3ApiServer) setObjectAclHeaders(r *http.Request, bucket string) s3err.ErrorCode {
+	// the acp is only set by the gateway
+	r.Header.Del(s3_constants.ExtAmzOwnerKey)
+	r.Header.Del(s3_constants.ExtAmzAclKey)
+
+	bucketMetadata, errCode := s3a.bucketRegistry.GetBucketMetadata(bucket)
+	if errCode != s3err.ErrNone {
+		return errCode
+	}
+	if bucketMetadata.ObjectOwnership == s3_constants.OwnershipBucketOwnerEnforced {
+		return checkAclHeadersWithAclDisabled(r)
+	}
+
+	ownerId, grants, errCode := ParseAndValidateAclHeadersOrElseDefault(r, s3a.iam, bucketMetadata.ObjectOwnership, *bucketMetadata.Owner.ID, s3a.objectWriterAccountId(r), false)
+	if errCode != s3err.ErrNone {
+		return errCode
+	}
+	SetAcpOwnerHeader(r, ownerId)
+	SetAcpGrantsHeader(r, grants)
+	return s3err.ErrNone
+}
+
+// checkAclHeadersWithAclDisabled only accepts the canned acls that grant the bucket owner full control
+func checkAclHeadersWithAclDisabled(r *http.Request) s3err.ErrorCode {
+	for _, header := range []string{s3_constants.AmzAclFullControl, s3_constants.AmzAclRead, s3_constants.AmzAclReadAcp, s3_constants.AmzAclWrite, s3_constants.AmzAclWriteAcp} {
+		if r.Header.Get(header) != "" {
+			return s3err.ErrAccessControlListNotSupported
+		}
+	}
+	switch r.Header.Get(s3_constants.AmzCannedAcl) {
+	case "", s3_constants.CannedAclPrivate, s3_constants.CannedAclBucketOwnerFullControl:
+		return s3err.ErrNone
+	}
+	return s3err.ErrAccessControlListNotSupported
+}
+
+// getObjectAclTarget finds the object version addressed by an acl request
+func (s3a *S3ApiServer) getObjectAclTarget(r *http.Request, bucket, object, versionId string) (dir string, entry *filer_pb.Entry, errCode s3err.ErrorCode) {
+	dir, _, entry, errCode = s3a.locateObjectVersion(r, bucket, object, versionId)
+	if errCode != s3err.ErrNone {
+		return "", nil, errCode
+	}
+	if isDeleteMarker(entry) {
+		return "", nil, s3err.ErrMethodNotAllowed
+	}
+	return dir, entry, s3err.ErrNone
+}
+
+// getObjectAcl returns the owner and grants stored with an object version,
+// no grants if the object has no acl or the bucket has ACLs disabled
+func (s3a *S3ApiServer) getObjectAcl(r *http.Request, bucket, object, versionId string) (ownerId string, grants []*s3.Grant) {
+	bucketMetadata, errCode := s3a.bucketRegistry.GetBucketMetadata(bucket)
+	if errCode != s3err.ErrNone || bucketMetadata.ObjectOwnership == s3_constants.OwnershipBucketOwnerEnforced {
+		return "", nil
+	}
+	_, entry, errCode := s3a.getObjectAclTarget(r, bucket, object, versionId)
+	if errCode != s3err.ErrNone {
+		return "", nil
+	}
+	return GetAcpOwner(entry.Extended, *bucketMetadata.Owner.ID), GetAcpGrants(entry.Extended)
+}
+
+// GetObjectAclHandler Get object ACL
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_GetObjectAcl.html
+func (s3a *S3ApiServer) GetObjectAclHandler(w http.ResponseWriter, r *http.Request) {
+
+	bucket, object := s3_constants.GetBucketAndObject(r)
+	glog.V(3).Infof("GetObjectAclHandler %s %s", bucket, object)
+
+	bucketMetadata, errCode := s3a.bucketRegistry.GetBucketMetadata(bucket)
+	if errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+	versionId := r.URL.Query().Get("versionId")
+	_, entry, errCode := s3a.getObjectAclTarget(r, bucket, object, versionId)
+	if errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+
+	// the bucket owner owns all objects if ACLs are disabled
+	ownerId := *bucketMetadata.Owner.ID
+	var grants []*s3.Grant
+	if bucketMetadata.ObjectOwnership != s3_constants.OwnershipBucketOwnerEnforced {
+		ownerId = GetAcpOwner(entry.Extended, ownerId)
+		grants = GetAcpGrants(entry.Extended)
+	}
+	if len(grants) == 0 {
+		grants = []*s3.Grant{{
+			Grantee: &s3.Grantee{
+				Type: &s3_constants.GrantTypeCanonicalUser,
+				ID:   &ownerId,
+			},
+			Permission: &s3_constants.PermissionFullControl,
+		}}
+	}
+
+	setVersionIdHeader(w, versionId)
+	writeSuccessResponseXML(w, r, s3a.toAccessControlPolicy(ownerId, grants))
+}
+
+// PutObjectAclHandler Put object ACL
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutObjectAcl.html
+func (s3a *S3ApiServer) PutObjectAclHandler(w http.ResponseWriter, r *http.Request) {
+
+	bucket, object := s3_constants.GetBucketAndObject(r)
+	glog.V(3).Infof("PutObjectAclHandler %s %s", bucket, object)
+
+	bucketMetadata, errCode := s3a.bucketRegistry.GetBucketMetadata(bucket)
+	if errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+	if bucketMetadata.ObjectOwnership == s3_constants.OwnershipBucketOwnerEnforced {
+		s3err.WriteErrorResponse(w, r, s3err.ErrAccessControlListNotSupported)
+		return
+	}
+	versionId := r.URL.Query().Get("versionId")
+	dir, entry, errCode := s3a.getObjectAclTarget(r, bucket, object, versionId)
+	if errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+
+	// the object owner does not change with its acl
+	ownerId := GetAcpOwner(entry.Extended, *bucketMetadata.Owner.ID)
+	grants, errCode := ExtractAcl(r, s3a.iam, bucketMetadata.ObjectOwnership, *bucketMetadata.Owner.ID, ownerId, s3a.objectWriterAccountId(r))
+	if errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+	if errCode = AssembleEntryWithAcp(entry, ownerId, grants); errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+	if err := s3a.updateEntry(dir, entry); err != nil {
+		glog.Errorf("PutObjectAclHandler %s%s: %v", bucket, object, err)
+		s3err.WriteErrorResponse(w, r, s3err.ErrInternalError)
+		return
+	}
+
+	setVersionIdHeader(w, versionId)
+	writeSuccessResponseEmpty(w, r)
+}
+
+// toAccessControlPolicy converts the stored grants to the response of GetObjectAcl
+func (s3a *S3ApiServer) toAccessControlPolicy(ownerId string, grants []*s3.Grant) AccessControlPolicy {
+	policy := AccessControlPolicy{
+		Owner: CanonicalUser{
+			ID:          ownerId,
+			DisplayName: s3a.iam.GetAccountNameById(ownerId),
+		},
+	}
+	for _, grant := range grants {
+		if grant.Grantee == nil || grant.Grantee.Type == nil || grant.Permission == nil {
+			continue
+		}
+		grantee := Grantee{
+			Type:   *grant.Grantee.Type,
+			XMLXSI: *grant.Grantee.Type,
+			XMLNS:  "http://www.w3.org/2001/XMLSchema-instance",
+		}
+		if grant.Grantee.ID != nil {
+			grantee.ID = *grant.Grantee.ID
+			grantee.DisplayName = s3a.iam.GetAccountNameById(grantee.ID)
+		}
+		if grant.Grantee.URI != nil {
+			grantee.URI = *grant.Grantee.URI
+		}
+		policy.AccessControlList.Grant = append(policy.AccessControlList.Grant, Grant{
+			Grantee:    grantee,
+			Permission: Permission(*grant.Permission),
+		})
+	}
+	return policy
+}
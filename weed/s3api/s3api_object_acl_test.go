@@ -0,0 +1,143 @@
+package s3api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/gorilla/mux"
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/pb/iam_pb"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3_constants"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3err"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckAclHeadersWithAclDisabled(t *testing.T) {
+	tests := []struct {
+		header  string
+		value   string
+		errCode s3err.ErrorCode
+	}{
+		{"", "", s3err.ErrNone},
+		{s3_constants.AmzCannedAcl, s3_constants.CannedAclPrivate, s3err.ErrNone},
+		{s3_constants.AmzCannedAcl, s3_constants.CannedAclBucketOwnerFullControl, s3err.ErrNone},
+		{s3_constants.AmzCannedAcl, s3_constants.CannedAclPublicRead, s3err.ErrAccessControlListNotSupported},
+		{s3_constants.AmzAclRead, `uri="http://acs.amazonaws.com/groups/global/AllUsers"`, s3err.ErrAccessControlListNotSupported},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodPut, "/bucket/a.txt", nil)
+		if tt.header != "" {
+			r.Header.Set(tt.header, tt.value)
+		}
+		assert.Equal(t, tt.errCode, checkAclHeadersWithAclDisabled(r), tt.value)
+	}
+}
+
+func TestAuthWithObjectAcl(t *testing.T) {
+	iam := &IdentityAccessManagement{}
+	assert.Nil(t, iam.loadS3ApiConfiguration(&iam_pb.S3ApiConfiguration{
+		Identities: []*iam_pb.Identity{
+			{Name: "reader", Account: &iam_pb.Account{Id: "tenant"}, Actions: []string{"Read"}},
+		},
+	}))
+	_, publicRead, _ := ParseCannedAclHeader(s3_constants.OwnershipObjectWriter, "owner", "owner", s3_constants.CannedAclPublicRead, false)
+	_, private, _ := ParseCannedAclHeader(s3_constants.OwnershipObjectWriter, "owner", "owner", s3_constants.CannedAclPrivate, false)
+	iam.objectAclLookup = func(r *http.Request, bucket, object, versionId string) (string, []*s3.Grant) {
+		switch object {
+		case "/public.txt":
+			return "owner", publicRead
+		case "/private.txt":
+			return "owner", private
+		}
+		return "", nil
+	}
+
+	identity := &Identity{Name: "reader", Account: &Account{Id: "tenant"}, Actions: []Action{s3_constants.ACTION_READ}}
+	anonymous := &Identity{Account: &AccountAnonymous}
+	admin := &Identity{Name: "admin", Account: &AccountAdmin, Actions: []Action{s3_constants.ACTION_ADMIN}}
+	tests := []struct {
+		name     string
+		method   string
+		object   string
+		identity *Identity
+		action   Action
+		granted  bool
+		found    bool
+		allowed  bool
+	}{
+		{"anonymous public read", http.MethodGet, "public.txt", anonymous, s3_constants.ACTION_READ, true, true, true},
+		{"anonymous public head", http.MethodHead, "public.txt", anonymous, s3_constants.ACTION_READ, true, true, true},
+		{"anonymous public acl", http.MethodGet, "public.txt", anonymous, s3_constants.ACTION_READ_ACP, false, true, false},
+		{"anonymous private read", http.MethodGet, "private.txt", anonymous, s3_constants.ACTION_READ, false, true, false},
+		// the acl only adds to the permissions of the identity
+		{"other account private read", http.MethodGet, "private.txt", identity, s3_constants.ACTION_READ, false, true, true},
+		{"other account private acl", http.MethodGet, "private.txt", identity, s3_constants.ACTION_READ_ACP, false, true, false},
+		{"other account public read", http.MethodGet, "public.txt", identity, s3_constants.ACTION_READ, true, true, true},
+		{"admin private read", http.MethodGet, "private.txt", admin, s3_constants.ACTION_READ, false, false, true},
+		{"no acl", http.MethodGet, "other.txt", anonymous, s3_constants.ACTION_READ, false, false, false},
+		{"write", http.MethodPut, "public.txt", anonymous, s3_constants.ACTION_WRITE, false, false, false},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(tt.method, "/bucket/"+tt.object, nil)
+		granted, found := iam.evaluateObjectAcl(r, tt.identity, tt.action, "bucket", "/"+tt.object)
+		assert.Equal(t, tt.granted, granted, tt.name)
+		assert.Equal(t, tt.found, found, tt.name)
+		assert.Equal(t, tt.allowed, iam.canDoWithObjectAcl(r, tt.identity, tt.action, "bucket", "/"+tt.object), tt.name)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/bucket/public.txt", nil)
+	r = mux.SetURLVars(r, map[string]string{"bucket": "bucket", "object": "public.txt"})
+	_, errCode := iam.authRequest(r, s3_constants.ACTION_READ)
+	assert.Equal(t, s3err.ErrNone, errCode)
+
+	r = httptest.NewRequest(http.MethodGet, "/bucket/private.txt", nil)
+	r = mux.SetURLVars(r, map[string]string{"bucket": "bucket", "object": "private.txt"})
+	_, errCode = iam.authRequest(r, s3_constants.ACTION_READ)
+	assert.Equal(t, s3err.ErrAccessDenied, errCode)
+}
+
+func TestAuthReusesObjectLocation(t *testing.T) {
+	iam := &IdentityAccessManagement{}
+	assert.Nil(t, iam.loadS3ApiConfiguration(&iam_pb.S3ApiConfiguration{
+		Identities: []*iam_pb.Identity{
+			{Name: "reader", Account: &iam_pb.Account{Id: "tenant"}, Actions: []string{"Read"}},
+		},
+	}))
+	_, publicRead, _ := ParseCannedAclHeader(s3_constants.OwnershipObjectWriter, "owner", "owner", s3_constants.CannedAclPublicRead, false)
+	entry := &filer_pb.Entry{Name: "public.txt"}
+	iam.objectAclLookup = func(r *http.Request, bucket, object, versionId string) (string, []*s3.Grant) {
+		// as located by getObjectAcl
+		cache := r.Context().Value(objectLocationCacheKey{}).(map[string]*objectLocation)
+		cache[bucket+object+"?versionId="+versionId] = &objectLocation{dir: "/buckets/bucket", name: "public.txt", entry: entry}
+		return "owner", publicRead
+	}
+
+	var s3a *S3ApiServer
+	var located *filer_pb.Entry
+	handler := iam.Auth(func(w http.ResponseWriter, r *http.Request) {
+		// the handler does not look up the object again
+		_, _, located, _ = s3a.locateObjectVersion(r, "bucket", "/public.txt", "")
+	}, s3_constants.ACTION_READ)
+
+	r := httptest.NewRequest(http.MethodGet, "/bucket/public.txt", nil)
+	r = mux.SetURLVars(r, map[string]string{"bucket": "bucket", "object": "public.txt"})
+	handler(httptest.NewRecorder(), r)
+	assert.Equal(t, entry, located)
+}
+
+func TestToAccessControlPolicy(t *testing.T) {
+	s3a := &S3ApiServer{iam: &IdentityAccessManagement{}}
+	assert.Nil(t, s3a.iam.loadS3ApiConfiguration(&iam_pb.S3ApiConfiguration{}))
+	_, grants, _ := ParseCannedAclHeader(s3_constants.OwnershipObjectWriter, AccountAdmin.Id, AccountAdmin.Id, s3_constants.CannedAclPublicRead, false)
+
+	policy := s3a.toAccessControlPolicy(AccountAdmin.Id, grants)
+	assert.Equal(t, AccountAdmin.Id, policy.Owner.ID)
+	assert.Equal(t, AccountAdmin.DisplayName, policy.Owner.DisplayName)
+	assert.Equal(t, 2, len(policy.AccessControlList.Grant))
+	assert.Equal(t, Permission(s3_constants.PermissionFullControl), policy.AccessControlList.Grant[0].Permission)
+	assert.Equal(t, AccountAdmin.Id, policy.AccessControlList.Grant[0].Grantee.ID)
+	assert.Equal(t, s3_constants.GranteeGroupAllUsers, policy.AccessControlList.Grant[1].Grantee.URI)
+	assert.Equal(t, Permission(s3_constants.PermissionRead), policy.AccessControlList.Grant[1].Permission)
+}
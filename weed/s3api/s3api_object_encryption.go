@@ -0,0 +1,483 @@
+package s3api
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/kms"
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3_constants"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3err"
+)
+
+// objectEncryption is the server side encryption of an object.
+//
+// SSE-S3 objects are encrypted by the filer, with a random key for each chunk.
+// SSE-C and SSE-KMS objects are encrypted by the gateway with AES-256 in CTR mode,
+// using the customer key or a data key from the kms, so that any range of them can be read.
+// The key itself is never stored: SSE-C objects keep the key md5, and SSE-KMS objects the data key encrypted by the kms.
+type objectEncryption struct {
+	algorithm        string // x-amz-server-side-encryption of SSE-S3 and SSE-KMS objects
+	kmsKeyId         string
+	encryptedDataKey []byte
+	customerKeyMD5   string // set for SSE-C objects
+
+	key   []byte
+	iv    []byte
+	parts []ssePart // sizes of the parts of multipart objects encrypted by the gateway
+}
+
+// ssePart is a part of a multipart upload, encrypted with its own random iv
+type ssePart struct {
+	number int
+	size   int64
+	iv     []byte
+}
+
+var errContentMd5Mismatch = errors.New("content md5 mismatch")
+
+// isGatewayEncrypted tells whether the object data is encrypted by the gateway, instead of by the filer
+func (sse *objectEncryption) isGatewayEncrypted() bool {
+	return sse != nil && len(sse.key) > 0
+}
+
+// parseSSECustomerKey reads the SSE-C headers, either for the object or for the copy source.
+// It returns a nil key if the request does not use SSE-C.
+func parseSSECustomerKey(header http.Header, isCopySource bool) (key []byte, keyMD5 string, errCode s3err.ErrorCode) {
+	algorithmHeader, keyHeader, keyMD5Header := s3_constants.AmzServerSideEncryptionCustomerAlgorithm, s3_constants.AmzServerSideEncryptionCustomerKey, s3_constants.AmzServerSideEncryptionCustomerKeyMD5
+	if isCopySource {
+		algorithmHeader, keyHeader, keyMD5Header = s3_constants.AmzCopySourceServerSideEncryptionCustomerAlgorithm, s3_constants.AmzCopySourceServerSideEncryptionCustomerKey, s3_constants.AmzCopySourceServerSideEncryptionCustomerKeyMD5
+	}
+	algorithm, encodedKey, keyMD5 := header.Get(algorithmHeader), header.Get(keyHeader), header.Get(keyMD5Header)
+	if algorithm == "" && encodedKey == "" && keyMD5 == "" {
+		return nil, "", s3err.ErrNone
+	}
+	if algorithm != s3_constants.SSEAlgorithmAES256 {
+		return nil, "", s3err.ErrInvalidEncryptionAlgorithm
+	}
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil || len(key) != 32 {
+		return nil, "", s3err.ErrInvalidSSECustomerKey
+	}
+	sum := md5.Sum(key)
+	if keyMD5 != base64.StdEncoding.EncodeToString(sum[:]) {
+		return nil, "", s3err.ErrSSECustomerKeyMD5Mismatch
+	}
+	return key, keyMD5, s3err.ErrNone
+}
+
+// newObjectEncryption decides the encryption of a new object, from the request headers or else from the bucket default encryption.
+// The encryption is also set to the request headers, for the filer to save it in the object metadata.
+func (s3a *S3ApiServer) newObjectEncryption(r *http.Request, bucket string) (sse *objectEncryption, errCode s3err.ErrorCode) {
+	for _, k := range []string{s3_constants.ExtSSEIvKey, s3_constants.ExtSSEKmsDataKeyKey, s3_constants.ExtSSEPartsKey} {
+		r.Header.Del(k)
+	}
+
+	key, keyMD5, errCode := parseSSECustomerKey(r.Header, false)
+	if errCode != s3err.ErrNone {
+		return nil, errCode
+	}
+	algorithm, kmsKeyId := r.Header.Get(s3_constants.AmzServerSideEncryption), r.Header.Get(s3_constants.AmzServerSideEncryptionAwsKmsKeyId)
+	switch {
+	case key != nil:
+		if algorithm != "" {
+			return nil, s3err.ErrInvalidRequest
+		}
+		sse = &objectEncryption{customerKeyMD5: keyMD5, key: key}
+	case algorithm == "":
+		if kmsKeyId != "" {
+			return nil, s3err.ErrInvalidRequest
+		}
+		algorithm, kmsKeyId = s3a.getBucketEncryption(bucket).defaultEncryption()
+	}
+
+	if sse == nil {
+		switch algorithm {
+		case "":
+			return nil, s3err.ErrNone
+		case s3_constants.SSEAlgorithmAES256:
+			if kmsKeyId != "" {
+				return nil, s3err.ErrInvalidRequest
+			}
+			sse = &objectEncryption{algorithm: algorithm}
+		case s3_constants.SSEAlgorithmKMS:
+			if kms.Provider == nil {
+				return nil, s3err.ErrKMSNotConfigured
+			}
+			dataKey, err := kms.Provider.GenerateDataKey(kmsKeyId)
+			if err != nil {
+				return nil, kmsErrorToS3Error(err)
+			}
+			sse = &objectEncryption{
+				algorithm:        algorithm,
+				kmsKeyId:         dataKey.KeyId,
+				encryptedDataKey: dataKey.Ciphertext,
+				key:              dataKey.Plaintext,
+			}
+		default:
+			return nil, s3err.ErrInvalidEncryptionAlgorithm
+		}
+	}
+
+	if sse.isGatewayEncrypted() {
+		sse.iv = make([]byte, aes.BlockSize)
+		if _, err := io.ReadFull(rand.Reader, sse.iv); err != nil {
+			glog.Errorf("generate encryption iv: %v", err)
+			return nil, s3err.ErrInternalError
+		}
+	}
+	sse.setMetadataHeaders(r.Header)
+	return sse, s3err.ErrNone
+}
+
+// getObjectEncryption reads the encryption of an existing object from its metadata,
+// with the key decrypted by the kms, or checked against the SSE-C headers of the request.
+func (s3a *S3ApiServer) getObjectEncryption(metadata http.Header, reqHeader http.Header, isCopySource bool) (sse *objectEncryption, errCode s3err.ErrorCode) {
+	key, keyMD5, errCode := parseSSECustomerKey(reqHeader, isCopySource)
+	if errCode != s3err.ErrNone {
+		return nil, errCode
+	}
+
+	sse = &objectEncryption{
+		algorithm:      metadata.Get(s3_constants.AmzServerSideEncryption),
+		kmsKeyId:       metadata.Get(s3_constants.AmzServerSideEncryptionAwsKmsKeyId),
+		customerKeyMD5: metadata.Get(s3_constants.AmzServerSideEncryptionCustomerKeyMD5),
+	}
+	switch {
+	case sse.customerKeyMD5 != "":
+		if key == nil {
+			return nil, s3err.ErrSSEEncryptedObject
+		}
+		if keyMD5 != sse.customerKeyMD5 {
+			return nil, s3err.ErrSSECustomerKeyMD5Mismatch
+		}
+		sse.key = key
+	case key != nil:
+		// the object is not encrypted with a customer key
+		return nil, s3err.ErrInvalidRequest
+	case sse.algorithm == "":
+		return nil, s3err.ErrNone
+	}
+
+	if encodedDataKey := metadata.Get(s3_constants.ExtSSEKmsDataKeyKey); encodedDataKey != "" {
+		if sse.encryptedDataKey, errCode = decodeBase64Metadata(encodedDataKey); errCode != s3err.ErrNone {
+			return nil, errCode
+		}
+		if kms.Provider == nil {
+			return nil, s3err.ErrKMSNotConfigured
+		}
+		var err error
+		if sse.key, err = kms.Provider.Decrypt(sse.kmsKeyId, sse.encryptedDataKey); err != nil {
+			glog.Errorf("decrypt data key with kms key %s: %v", sse.kmsKeyId, err)
+			return nil, kmsErrorToS3Error(err)
+		}
+	}
+	if sse.isGatewayEncrypted() {
+		if sse.iv, errCode = decodeBase64Metadata(metadata.Get(s3_constants.ExtSSEIvKey)); errCode != s3err.ErrNone || len(sse.iv) != aes.BlockSize {
+			return nil, s3err.ErrInternalError
+		}
+		var err error
+		if sse.parts, err = parseSSEParts(metadata.Get(s3_constants.ExtSSEPartsKey)); err != nil {
+			glog.Errorf("parse encrypted parts: %v", err)
+			return nil, s3err.ErrInternalError
+		}
+	}
+	return sse, s3err.ErrNone
+}
+
+// getUploadEncryption reads the encryption of a multipart upload, for a part to be encrypted with the same key.
+// Each uploaded part gets a new random iv, so that uploading a part number again never reuses a key stream.
+// The encryption is also set to the request headers, saved by the filer in the part metadata,
+// and so that the filer encrypts the parts of SSE-S3 uploads.
+func (s3a *S3ApiServer) getUploadEncryption(r *http.Request, bucket, uploadID string) (*objectEncryption, s3err.ErrorCode) {
+	uploadEntry, err := s3a.getEntry(s3a.genUploadsFolder(bucket), uploadID)
+	if err != nil {
+		return nil, s3err.ErrNoSuchUpload
+	}
+	for _, k := range []string{s3_constants.AmzServerSideEncryption, s3_constants.ExtSSEIvKey, s3_constants.ExtSSEKmsDataKeyKey, s3_constants.ExtSSEPartsKey} {
+		r.Header.Del(k)
+	}
+	sse, errCode := s3a.getObjectEncryption(sseMetadata(uploadEntry.Extended), r.Header, false)
+	if errCode != s3err.ErrNone {
+		return nil, errCode
+	}
+	if sse == nil {
+		return nil, s3err.ErrNone
+	}
+	if sse.isGatewayEncrypted() {
+		sse.iv = make([]byte, aes.BlockSize)
+		if _, err := rand.Read(sse.iv); err != nil {
+			glog.Errorf("generate part iv: %v", err)
+			return nil, s3err.ErrInternalError
+		}
+	}
+	sse.setMetadataHeaders(r.Header)
+	return sse, s3err.ErrNone
+}
+
+func decodeBase64Metadata(value string) ([]byte, s3err.ErrorCode) {
+	data, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		glog.Errorf("decode encryption metadata %s: %v", value, err)
+		return nil, s3err.ErrInternalError
+	}
+	return data, s3err.ErrNone
+}
+
+func kmsErrorToS3Error(err error) s3err.ErrorCode {
+	if errors.Is(err, kms.ErrKeyNotFound) {
+		return s3err.ErrKMSKeyNotFound
+	}
+	glog.Errorf("kms: %v", err)
+	return s3err.ErrInternalError
+}
+
+// sseMetadata picks the encryption metadata from the Extended attributes of an entry
+func sseMetadata(extended map[string][]byte) http.Header {
+	metadata := make(http.Header)
+	for _, k := range s3_constants.SSEMetadataKeys {
+		if v, found := extended[k]; found {
+			metadata.Set(k, string(v))
+		}
+	}
+	return metadata
+}
+
+// setResponseHeaders sets the encryption headers returned to the clients
+func (sse *objectEncryption) setResponseHeaders(h http.Header) {
+	if sse == nil {
+		return
+	}
+	if sse.algorithm != "" {
+		h.Set(s3_constants.AmzServerSideEncryption, sse.algorithm)
+	}
+	if sse.kmsKeyId != "" {
+		h.Set(s3_constants.AmzServerSideEncryptionAwsKmsKeyId, sse.kmsKeyId)
+	}
+	if sse.customerKeyMD5 != "" {
+		h.Set(s3_constants.AmzServerSideEncryptionCustomerAlgorithm, s3_constants.SSEAlgorithmAES256)
+		h.Set(s3_constants.AmzServerSideEncryptionCustomerKeyMD5, sse.customerKeyMD5)
+	}
+}
+
+// setMetadataHeaders sets the encryption headers saved by the filer in the object metadata
+func (sse *objectEncryption) setMetadataHeaders(h http.Header) {
+	for _, k := range s3_constants.SSEMetadataKeys {
+		h.Del(k)
+	}
+	sse.setResponseHeaders(h)
+	if len(sse.encryptedDataKey) > 0 {
+		h.Set(s3_constants.ExtSSEKmsDataKeyKey, base64.StdEncoding.EncodeToString(sse.encryptedDataKey))
+	}
+	if len(sse.iv) > 0 {
+		h.Set(s3_constants.ExtSSEIvKey, base64.StdEncoding.EncodeToString(sse.iv))
+	}
+}
+
+// encrypt wraps the data of an object, or of a part of a multipart upload, to be encrypted by the gateway.
+// Since the filer only sees the encrypted data, the Content-Md5 of the request is checked here instead.
+func (sse *objectEncryption) encrypt(r *http.Request, dataReader io.Reader) (io.Reader, error) {
+	if !sse.isGatewayEncrypted() {
+		return dataReader, nil
+	}
+	if contentMd5 := r.Header.Get("Content-Md5"); contentMd5 != "" {
+		r.Header.Del("Content-Md5")
+		dataReader = &md5CheckReader{reader: dataReader, hash: md5.New(), expected: contentMd5}
+	}
+	stream, err := newSSECipherStream(sse.key, sse.iv, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &cipher.StreamReader{S: stream, R: dataReader}, nil
+}
+
+// decrypt wraps the encrypted object data read from offset
+func (sse *objectEncryption) decrypt(dataReader io.Reader, offset int64) (io.Reader, error) {
+	if !sse.isGatewayEncrypted() {
+		return dataReader, nil
+	}
+	if len(sse.parts) == 0 {
+		stream, err := newSSECipherStream(sse.key, sse.iv, offset)
+		if err != nil {
+			return nil, err
+		}
+		return &cipher.StreamReader{S: stream, R: dataReader}, nil
+	}
+	reader := &ssePartsReader{reader: dataReader, sse: sse, partIndex: -1}
+	for i, part := range sse.parts {
+		if offset < part.size || i == len(sse.parts)-1 {
+			if err := reader.startPart(i, offset); err != nil {
+				return nil, err
+			}
+			break
+		}
+		offset -= part.size
+	}
+	return reader, nil
+}
+
+// newSSECipherStream returns the AES-256-CTR key stream of an object or a part, from the offset in it.
+// The counter starts at the iv.
+func newSSECipherStream(key, iv []byte, offset int64) (cipher.Stream, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	counter := make([]byte, aes.BlockSize)
+	high, low := binary.BigEndian.Uint64(iv[:8]), binary.BigEndian.Uint64(iv[8:])
+	delta := uint64(offset / aes.BlockSize)
+	if low+delta < low {
+		high++
+	}
+	binary.BigEndian.PutUint64(counter[:8], high)
+	binary.BigEndian.PutUint64(counter[8:], low+delta)
+	stream := cipher.NewCTR(block, counter)
+	if skip := offset % aes.BlockSize; skip > 0 {
+		discard := make([]byte, skip)
+		stream.XORKeyStream(discard, discard)
+	}
+	return stream, nil
+}
+
+// ssePartsReader decrypts a multipart object, switching to the key stream of each part at its boundary
+type ssePartsReader struct {
+	reader    io.Reader
+	sse       *objectEncryption
+	partIndex int
+	remaining int64
+	stream    cipher.Stream
+}
+
+func (r *ssePartsReader) startPart(partIndex int, offset int64) (err error) {
+	part := r.sse.parts[partIndex]
+	r.partIndex, r.remaining = partIndex, part.size-offset
+	r.stream, err = newSSECipherStream(r.sse.key, part.iv, offset)
+	return
+}
+
+func (r *ssePartsReader) Read(p []byte) (n int, err error) {
+	for r.remaining <= 0 && r.partIndex < len(r.sse.parts)-1 {
+		if err = r.startPart(r.partIndex+1, 0); err != nil {
+			return 0, err
+		}
+	}
+	if r.remaining > 0 && int64(len(p)) > r.remaining {
+		p = p[:r.remaining]
+	}
+	n, err = r.reader.Read(p)
+	r.stream.XORKeyStream(p[:n], p[:n])
+	r.remaining -= int64(n)
+	return
+}
+
+// md5CheckReader fails the upload at the end of the data if it does not match the expected Content-Md5
+type md5CheckReader struct {
+	reader   io.Reader
+	hash     hash.Hash
+	expected string
+}
+
+func (r *md5CheckReader) Read(p []byte) (n int, err error) {
+	n, err = r.reader.Read(p)
+	r.hash.Write(p[:n])
+	if err == io.EOF {
+		sum := r.hash.Sum(nil)
+		if r.expected != base64.StdEncoding.EncodeToString(sum) && r.expected != fmt.Sprintf("%x", sum) {
+			return n, errContentMd5Mismatch
+		}
+	}
+	return
+}
+
+// newSSEPart records the iv of an uploaded part
+func newSSEPart(partNumber int, size int64, partEntry *filer_pb.Entry) ssePart {
+	part := ssePart{number: partNumber, size: size}
+	part.iv, _ = base64.StdEncoding.DecodeString(string(partEntry.Extended[s3_constants.ExtSSEIvKey]))
+	return part
+}
+
+func formatSSEParts(parts []ssePart) string {
+	var values []string
+	for _, part := range parts {
+		values = append(values, fmt.Sprintf("%d:%d:%s", part.number, part.size, base64.StdEncoding.EncodeToString(part.iv)))
+	}
+	return strings.Join(values, ",")
+}
+
+// parseSSEParts parses the parts of a multipart object, in the form of "1:5242880:<base64 iv>,2:1024:<base64 iv>"
+func parseSSEParts(value string) (parts []ssePart, err error) {
+	if value == "" {
+		return nil, nil
+	}
+	for _, v := range strings.Split(value, ",") {
+		fields := strings.Split(v, ":")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("invalid part %q", v)
+		}
+		number, size, iv := fields[0], fields[1], fields[2]
+		part := ssePart{}
+		if part.number, err = strconv.Atoi(number); err != nil {
+			return nil, err
+		}
+		if part.size, err = strconv.ParseInt(size, 10, 64); err != nil {
+			return nil, err
+		}
+		if part.iv, err = base64.StdEncoding.DecodeString(iv); err != nil {
+			return nil, err
+		}
+		if len(part.iv) != aes.BlockSize {
+			return nil, fmt.Errorf("invalid iv of part %q", v)
+		}
+		parts = append(parts, part)
+	}
+	return parts, nil
+}
+
+// parseContentRangeStart returns the first byte position of a "bytes start-end/size" Content-Range
+func parseContentRangeStart(contentRange string) int64 {
+	start, _, found := strings.Cut(strings.TrimPrefix(contentRange, "bytes "), "-")
+	if !found {
+		return 0
+	}
+	offset, _ := strconv.ParseInt(start, 10, 64)
+	return offset
+}
+
+// decryptResponse passes the filer response through, decrypting the object data if it is encrypted by the gateway
+func (s3a *S3ApiServer) decryptResponse(r *http.Request) func(proxyResponse *http.Response, w http.ResponseWriter) (statusCode int) {
+	return func(proxyResponse *http.Response, w http.ResponseWriter) (statusCode int) {
+		sse, errCode := s3a.getObjectEncryption(proxyResponse.Header, r.Header, false)
+		if errCode != s3err.ErrNone {
+			s3err.WriteErrorResponse(w, r, errCode)
+			return s3err.GetAPIError(errCode).HTTPStatusCode
+		}
+		for _, k := range []string{s3_constants.ExtSSEIvKey, s3_constants.ExtSSEKmsDataKeyKey, s3_constants.ExtSSEPartsKey} {
+			proxyResponse.Header.Del(k)
+		}
+		if sse.isGatewayEncrypted() && r.Method == http.MethodGet {
+			body, err := sse.decrypt(proxyResponse.Body, parseContentRangeStart(proxyResponse.Header.Get("Content-Range")))
+			if err != nil {
+				glog.Errorf("decrypt %s: %v", r.URL, err)
+				s3err.WriteErrorResponse(w, r, s3err.ErrInternalError)
+				return http.StatusInternalServerError
+			}
+			proxyResponse.Body = struct {
+				io.Reader
+				io.Closer
+			}{body, proxyResponse.Body}
+		}
+		return passThroughResponse(proxyResponse, w)
+	}
+}
@@ -0,0 +1,292 @@
+package s3api
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/seaweedfs/seaweedfs/weed/kms"
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3_constants"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3err"
+	"github.com/seaweedfs/seaweedfs/weed/util"
+	"github.com/stretchr/testify/assert"
+)
+
+type testKeyProvider struct {
+	masterKey util.CipherKey
+}
+
+func (k *testKeyProvider) GetName() string {
+	return "test"
+}
+
+func (k *testKeyProvider) Initialize(configuration util.Configuration, prefix string) error {
+	return nil
+}
+
+func (k *testKeyProvider) GenerateDataKey(keyId string) (*kms.DataKey, error) {
+	if keyId == "" {
+		keyId = "default"
+	}
+	if keyId != "default" {
+		return nil, kms.ErrKeyNotFound
+	}
+	plaintext := util.GenCipherKey()
+	ciphertext, err := util.Encrypt(plaintext, k.masterKey)
+	return &kms.DataKey{KeyId: keyId, Plaintext: plaintext, Ciphertext: ciphertext}, err
+}
+
+func (k *testKeyProvider) Decrypt(keyId string, ciphertext []byte) ([]byte, error) {
+	if keyId != "default" {
+		return nil, kms.ErrKeyNotFound
+	}
+	return util.Decrypt(ciphertext, k.masterKey)
+}
+
+func newEncryptionTestServer(encryption *ServerSideEncryptionConfiguration) *S3ApiServer {
+	s3a := &S3ApiServer{option: &S3ApiServerOption{}}
+	s3a.bucketRegistry = &BucketRegistry{
+		metadataCache: map[string]*BucketMetaData{"bucket": {Name: "bucket", Encryption: encryption}},
+		notFound:      map[string]struct{}{},
+		s3a:           s3a,
+	}
+	return s3a
+}
+
+func setSSECustomerKeyHeaders(h http.Header, key []byte) {
+	sum := md5.Sum(key)
+	h.Set(s3_constants.AmzServerSideEncryptionCustomerAlgorithm, "AES256")
+	h.Set(s3_constants.AmzServerSideEncryptionCustomerKey, base64.StdEncoding.EncodeToString(key))
+	h.Set(s3_constants.AmzServerSideEncryptionCustomerKeyMD5, base64.StdEncoding.EncodeToString(sum[:]))
+}
+
+func TestParseSSECustomerKey(t *testing.T) {
+	key := make([]byte, 32)
+	rand.Read(key)
+
+	h := http.Header{}
+	parsed, _, errCode := parseSSECustomerKey(h, false)
+	assert.Equal(t, s3err.ErrNone, errCode)
+	assert.Nil(t, parsed)
+
+	setSSECustomerKeyHeaders(h, key)
+	parsed, keyMD5, errCode := parseSSECustomerKey(h, false)
+	assert.Equal(t, s3err.ErrNone, errCode)
+	assert.Equal(t, key, parsed)
+	assert.Equal(t, h.Get(s3_constants.AmzServerSideEncryptionCustomerKeyMD5), keyMD5)
+
+	parsed, _, _ = parseSSECustomerKey(h, true)
+	assert.Nil(t, parsed, "not a copy source key")
+
+	h.Set(s3_constants.AmzServerSideEncryptionCustomerKeyMD5, "bad")
+	_, _, errCode = parseSSECustomerKey(h, false)
+	assert.Equal(t, s3err.ErrSSECustomerKeyMD5Mismatch, errCode)
+
+	h.Set(s3_constants.AmzServerSideEncryptionCustomerKey, base64.StdEncoding.EncodeToString(key[:16]))
+	_, _, errCode = parseSSECustomerKey(h, false)
+	assert.Equal(t, s3err.ErrInvalidSSECustomerKey, errCode)
+
+	h.Set(s3_constants.AmzServerSideEncryptionCustomerAlgorithm, "AES128")
+	_, _, errCode = parseSSECustomerKey(h, false)
+	assert.Equal(t, s3err.ErrInvalidEncryptionAlgorithm, errCode)
+}
+
+func TestSSECipherStreamParts(t *testing.T) {
+	sse := &objectEncryption{key: make([]byte, 32), iv: make([]byte, 16)}
+	rand.Read(sse.key)
+	rand.Read(sse.iv)
+	// the counter of the last iv block overflows into the upper half
+	copy(sse.iv[8:], []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xf0})
+
+	plain := make([]byte, 3000)
+	rand.Read(plain)
+
+	// a single object, read from any offset
+	encryptedReader, err := sse.encrypt(httptest.NewRequest(http.MethodPut, "/bucket/a", nil), bytes.NewReader(plain))
+	assert.Nil(t, err)
+	encrypted, _ := io.ReadAll(encryptedReader)
+	assert.NotEqual(t, plain, encrypted)
+	for _, offset := range []int64{0, 1, 15, 16, 17, 1000, 2999} {
+		decryptedReader, err := sse.decrypt(bytes.NewReader(encrypted[offset:]), offset)
+		assert.Nil(t, err)
+		decrypted, _ := io.ReadAll(decryptedReader)
+		assert.Equal(t, plain[offset:], decrypted, "offset %d", offset)
+	}
+
+	// a multipart object, with each part encrypted with its own iv
+	uploadIv := sse.iv
+	sse.parts = []ssePart{{number: 1, size: 1000}, {number: 2, size: 1500}, {number: 4, size: 500}}
+	var multipart []byte
+	var offset int64
+	for i, part := range sse.parts {
+		partData := plain[offset : offset+part.size]
+		partSSE := &objectEncryption{key: sse.key, iv: make([]byte, 16)}
+		rand.Read(partSSE.iv)
+		sse.parts[i].iv = partSSE.iv
+		partReader, err := partSSE.encrypt(httptest.NewRequest(http.MethodPut, "/bucket/a", nil), bytes.NewReader(partData))
+		assert.Nil(t, err)
+		encryptedPart, _ := io.ReadAll(partReader)
+		multipart = append(multipart, encryptedPart...)
+		offset += part.size
+	}
+	for _, offset := range []int64{0, 999, 1000, 1001, 2500, 2999} {
+		decryptedReader, err := sse.decrypt(bytes.NewReader(multipart[offset:]), offset)
+		assert.Nil(t, err)
+		decrypted, _ := io.ReadAll(decryptedReader)
+		assert.Equal(t, plain[offset:], decrypted, "offset %d", offset)
+	}
+
+	parsed, err := parseSSEParts(formatSSEParts(sse.parts))
+	assert.Nil(t, err)
+	assert.Equal(t, sse.parts, parsed)
+	_, err = parseSSEParts("1:1000:bad")
+	assert.NotNil(t, err)
+	_, err = parseSSEParts("1:1000")
+	assert.NotNil(t, err, "each part has its own iv")
+
+	partEntry := &filer_pb.Entry{Extended: map[string][]byte{s3_constants.ExtSSEIvKey: []byte(base64.StdEncoding.EncodeToString(sse.parts[0].iv))}}
+	assert.Equal(t, sse.parts[0], newSSEPart(1, 1000, partEntry))
+	assert.NotEqual(t, uploadIv, sse.parts[0].iv)
+}
+
+func TestSSEPartUploadIv(t *testing.T) {
+	sse := &objectEncryption{key: make([]byte, 32), iv: make([]byte, 16)}
+	rand.Read(sse.key)
+	plain := make([]byte, 100)
+
+	// uploading the same part again never reuses the key stream
+	var encrypted [][]byte
+	for i := 0; i < 2; i++ {
+		partSSE := &objectEncryption{key: sse.key, iv: make([]byte, 16)}
+		rand.Read(partSSE.iv)
+		reader, err := partSSE.encrypt(httptest.NewRequest(http.MethodPut, "/bucket/a", nil), bytes.NewReader(plain))
+		assert.Nil(t, err)
+		data, _ := io.ReadAll(reader)
+		encrypted = append(encrypted, data)
+	}
+	assert.NotEqual(t, encrypted[0], encrypted[1])
+}
+
+func TestSSEContentMd5(t *testing.T) {
+	sse := &objectEncryption{key: make([]byte, 32), iv: make([]byte, 16)}
+	data := []byte("hello")
+	sum := md5.Sum(data)
+
+	r := httptest.NewRequest(http.MethodPut, "/bucket/a", nil)
+	r.Header.Set("Content-Md5", base64.StdEncoding.EncodeToString(sum[:]))
+	encryptedReader, _ := sse.encrypt(r, bytes.NewReader(data))
+	_, err := io.ReadAll(encryptedReader)
+	assert.Nil(t, err)
+	assert.Empty(t, r.Header.Get("Content-Md5"), "not checked by the filer")
+
+	r.Header.Set("Content-Md5", base64.StdEncoding.EncodeToString(sum[:]))
+	encryptedReader, _ = sse.encrypt(r, bytes.NewReader([]byte("hallo")))
+	_, err = io.ReadAll(encryptedReader)
+	assert.True(t, errors.Is(err, errContentMd5Mismatch))
+}
+
+func TestObjectEncryptionMetadata(t *testing.T) {
+	kms.Provider = &testKeyProvider{masterKey: util.GenCipherKey()}
+	defer func() {
+		kms.Provider = nil
+	}()
+	s3a := newEncryptionTestServer(&ServerSideEncryptionConfiguration{
+		Rules: []ServerSideEncryptionRule{{ApplyServerSideEncryptionByDefault: ServerSideEncryptionByDefault{SSEAlgorithm: "aws:kms"}}},
+	})
+
+	// bucket default encryption
+	r := httptest.NewRequest(http.MethodPut, "/bucket/a", nil)
+	r.Header.Set(s3_constants.ExtSSEPartsKey, "1:1")
+	sse, errCode := s3a.newObjectEncryption(r, "bucket")
+	assert.Equal(t, s3err.ErrNone, errCode)
+	assert.True(t, sse.isGatewayEncrypted())
+	assert.Equal(t, "aws:kms", r.Header.Get(s3_constants.AmzServerSideEncryption))
+	assert.Equal(t, "default", r.Header.Get(s3_constants.AmzServerSideEncryptionAwsKmsKeyId))
+	assert.NotEmpty(t, r.Header.Get(s3_constants.ExtSSEKmsDataKeyKey))
+	assert.NotEmpty(t, r.Header.Get(s3_constants.ExtSSEIvKey))
+	assert.Empty(t, r.Header.Get(s3_constants.ExtSSEPartsKey), "internal headers are not accepted from clients")
+
+	read, errCode := s3a.getObjectEncryption(r.Header, http.Header{}, false)
+	assert.Equal(t, s3err.ErrNone, errCode)
+	assert.Equal(t, sse.key, read.key)
+	assert.Equal(t, sse.iv, read.iv)
+
+	// SSE-S3 is encrypted by the filer
+	r = httptest.NewRequest(http.MethodPut, "/bucket/a", nil)
+	r.Header.Set(s3_constants.AmzServerSideEncryption, "AES256")
+	sse, errCode = s3a.newObjectEncryption(r, "bucket")
+	assert.Equal(t, s3err.ErrNone, errCode)
+	assert.False(t, sse.isGatewayEncrypted())
+	assert.Empty(t, r.Header.Get(s3_constants.ExtSSEIvKey))
+
+	r = httptest.NewRequest(http.MethodPut, "/bucket/a", nil)
+	r.Header.Set(s3_constants.AmzServerSideEncryption, "aws:kms")
+	r.Header.Set(s3_constants.AmzServerSideEncryptionAwsKmsKeyId, "missing")
+	_, errCode = s3a.newObjectEncryption(r, "bucket")
+	assert.Equal(t, s3err.ErrKMSKeyNotFound, errCode)
+
+	// SSE-C needs the same key to read
+	key := make([]byte, 32)
+	rand.Read(key)
+	r = httptest.NewRequest(http.MethodPut, "/bucket/a", nil)
+	setSSECustomerKeyHeaders(r.Header, key)
+	sse, errCode = s3a.newObjectEncryption(r, "bucket")
+	assert.Equal(t, s3err.ErrNone, errCode)
+	assert.Equal(t, key, sse.key)
+	assert.Empty(t, r.Header.Get(s3_constants.AmzServerSideEncryption))
+	metadata := r.Header.Clone()
+	metadata.Del(s3_constants.AmzServerSideEncryptionCustomerKey)
+
+	_, errCode = s3a.getObjectEncryption(metadata, http.Header{}, false)
+	assert.Equal(t, s3err.ErrSSEEncryptedObject, errCode)
+	otherKey := make([]byte, 32)
+	rand.Read(otherKey)
+	reqHeader := http.Header{}
+	setSSECustomerKeyHeaders(reqHeader, otherKey)
+	_, errCode = s3a.getObjectEncryption(metadata, reqHeader, false)
+	assert.Equal(t, s3err.ErrSSECustomerKeyMD5Mismatch, errCode)
+	setSSECustomerKeyHeaders(reqHeader, key)
+	read, errCode = s3a.getObjectEncryption(metadata, reqHeader, false)
+	assert.Equal(t, s3err.ErrNone, errCode)
+	assert.Equal(t, sse.iv, read.iv)
+
+	w := httptest.NewRecorder()
+	read.setResponseHeaders(w.Header())
+	assert.Equal(t, "AES256", w.Header().Get(s3_constants.AmzServerSideEncryptionCustomerAlgorithm))
+	assert.Equal(t, reqHeader.Get(s3_constants.AmzServerSideEncryptionCustomerKeyMD5), w.Header().Get(s3_constants.AmzServerSideEncryptionCustomerKeyMD5))
+	assert.Empty(t, w.Header().Get(s3_constants.ExtSSEIvKey))
+
+	_, errCode = s3a.getObjectEncryption(http.Header{}, reqHeader, false)
+	assert.Equal(t, s3err.ErrInvalidRequest, errCode, "customer key for an object not encrypted with it")
+}
+
+func TestServerSideEncryptionConfiguration(t *testing.T) {
+	config := &ServerSideEncryptionConfiguration{}
+	assert.Nil(t, xml.Unmarshal([]byte(`<ServerSideEncryptionConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+  <Rule>
+    <ApplyServerSideEncryptionByDefault><SSEAlgorithm>aws:kms</SSEAlgorithm><KMSMasterKeyID>key1</KMSMasterKeyID></ApplyServerSideEncryptionByDefault>
+  </Rule>
+</ServerSideEncryptionConfiguration>`), config))
+	assert.Equal(t, s3err.ErrNone, config.validate())
+	algorithm, kmsKeyId := config.defaultEncryption()
+	assert.Equal(t, "aws:kms", algorithm)
+	assert.Equal(t, "key1", kmsKeyId)
+
+	config.Rules[0].ApplyServerSideEncryptionByDefault.SSEAlgorithm = "AES256"
+	assert.Equal(t, s3err.ErrInvalidRequest, config.validate())
+	config.Rules[0].ApplyServerSideEncryptionByDefault.SSEAlgorithm = "DES"
+	assert.Equal(t, s3err.ErrInvalidEncryptionAlgorithm, config.validate())
+	assert.Equal(t, s3err.ErrMalformedXML, (&ServerSideEncryptionConfiguration{}).validate())
+
+	var none *ServerSideEncryptionConfiguration
+	algorithm, _ = none.defaultEncryption()
+	assert.Empty(t, algorithm)
+}
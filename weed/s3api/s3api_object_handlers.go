@@ -121,8 +121,15 @@ func (s3a *S3ApiServer) GetObjectHandler(w http.ResponseWriter, r *http.Request)
 	}
 
 	destUrl := s3a.toFilerUrl(bucket, object)
+	if versionId := r.URL.Query().Get("versionId"); versionId != "" {
+		var errCode s3err.ErrorCode
+		if destUrl, errCode = s3a.toFilerVersionUrl(w, r, bucket, object, versionId); errCode != s3err.ErrNone {
+			s3err.WriteErrorResponse(w, r, errCode)
+			return
+		}
+	}
 
-	s3a.proxyToFiler(w, r, destUrl, false, passThroughResponse)
+	s3a.proxyToFiler(w, r, destUrl, false, s3a.decryptResponse(r))
 }
 
 func (s3a *S3ApiServer) HeadObjectHandler(w http.ResponseWriter, r *http.Request) {
@@ -131,8 +138,15 @@ func (s3a *S3ApiServer) HeadObjectHandler(w http.ResponseWriter, r *http.Request
 	glog.V(3).Infof("HeadObjectHandler %s %s", bucket, object)
 
 	destUrl := s3a.toFilerUrl(bucket, object)
+	if versionId := r.URL.Query().Get("versionId"); versionId != "" {
+		var errCode s3err.ErrorCode
+		if destUrl, errCode = s3a.toFilerVersionUrl(w, r, bucket, object, versionId); errCode != s3err.ErrNone {
+			s3err.WriteErrorResponse(w, r, errCode)
+			return
+		}
+	}
 
-	s3a.proxyToFiler(w, r, destUrl, false, passThroughResponse)
+	s3a.proxyToFiler(w, r, destUrl, false, s3a.decryptResponse(r))
 }
 
 func (s3a *S3ApiServer) proxyToFiler(w http.ResponseWriter, r *http.Request, destUrl string, isWrite bool, responseFn func(proxyResponse *http.Response, w http.ResponseWriter) (statusCode int)) {
@@ -161,6 +175,8 @@ func (s3a *S3ApiServer) proxyToFiler(w http.ResponseWriter, r *http.Request, des
 	for header, values := range r.Header {
 		proxyReq.Header[header] = values
 	}
+	// the customer keys are never sent to the filer
+	proxyReq.Header.Del(s3_constants.AmzServerSideEncryptionCustomerKey)
 
 	// ensure that the Authorization header is overriding any previous
 	// Authorization header which might be already present in proxyReq
@@ -2,6 +2,7 @@ package s3api
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -11,6 +12,7 @@ import (
 	"modernc.org/strutil"
 
 	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
 	"github.com/seaweedfs/seaweedfs/weed/s3api/s3_constants"
 	"github.com/seaweedfs/seaweedfs/weed/s3api/s3err"
 	"github.com/seaweedfs/seaweedfs/weed/util"
@@ -33,13 +35,27 @@ func (s3a *S3ApiServer) CopyObjectHandler(w http.ResponseWriter, r *http.Request
 		cpSrcPath = r.Header.Get("X-Amz-Copy-Source")
 	}
 
+	cpSrcPath, srcVersionId := splitCopySourceVersionId(cpSrcPath)
 	srcBucket, srcObject := pathToBucketAndObject(cpSrcPath)
 
 	glog.V(3).Infof("CopyObjectHandler %s %s => %s %s", srcBucket, srcObject, dstBucket, dstObject)
 
+	// the version id is only assigned by the gateway
+	r.Header.Del(s3_constants.AmzVersionId)
+
 	replaceMeta, replaceTagging := replaceDirective(r.Header)
 
-	if (srcBucket == dstBucket && srcObject == dstObject || cpSrcPath == "") && (replaceMeta || replaceTagging) {
+	versioning, errCode := s3a.getVersioningState(dstBucket)
+	if errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+	if cpSrcPath == "" && versioning != "" {
+		srcBucket, srcObject = dstBucket, dstObject
+	}
+	// replacing the metadata of a versioned object creates a new version, so the object is copied onto itself
+	isSelfCopy := srcBucket == dstBucket && srcObject == dstObject || cpSrcPath == ""
+	if isSelfCopy && srcVersionId == "" && (replaceMeta || replaceTagging) && versioning == "" {
 		fullPath := util.FullPath(fmt.Sprintf("%s/%s%s", s3a.option.BucketsPath, dstBucket, dstObject))
 		dir, name := fullPath.DirAndName()
 		entry, err := s3a.getEntry(dir, name)
@@ -54,6 +70,16 @@ func (s3a *S3ApiServer) CopyObjectHandler(w http.ResponseWriter, r *http.Request
 			s3err.WriteErrorResponse(w, r, s3err.ErrInvalidTag)
 			return
 		}
+		// like any copy, the object gets the acl of the request
+		if errCode := s3a.setObjectAclHeaders(r, dstBucket); errCode != s3err.ErrNone {
+			s3err.WriteErrorResponse(w, r, errCode)
+			return
+		}
+		for _, k := range []string{s3_constants.ExtAmzOwnerKey, s3_constants.ExtAmzAclKey} {
+			if v := r.Header.Get(k); v != "" {
+				entry.Extended[k] = []byte(v)
+			}
+		}
 		err = s3a.touch(dir, name, entry)
 		if err != nil {
 			s3err.WriteErrorResponse(w, r, s3err.ErrInvalidCopySource)
@@ -71,22 +97,20 @@ func (s3a *S3ApiServer) CopyObjectHandler(w http.ResponseWriter, r *http.Request
 		s3err.WriteErrorResponse(w, r, s3err.ErrInvalidCopySource)
 		return
 	}
-	srcPath := util.FullPath(fmt.Sprintf("%s/%s%s", s3a.option.BucketsPath, srcBucket, srcObject))
-	dir, name := srcPath.DirAndName()
-	if entry, err := s3a.getEntry(dir, name); err != nil || entry.IsDirectory {
-		s3err.WriteErrorResponse(w, r, s3err.ErrInvalidCopySource)
+	dir, name, errCode := s3a.getCopySourceDirAndName(srcBucket, srcObject, srcVersionId)
+	if errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
 		return
 	}
 
-	if srcBucket == dstBucket && srcObject == dstObject {
+	if isSelfCopy && srcVersionId == "" && !replaceMeta && !replaceTagging {
 		s3err.WriteErrorResponse(w, r, s3err.ErrInvalidCopyDest)
 		return
 	}
 
 	dstUrl := fmt.Sprintf("http://%s%s/%s%s",
 		s3a.option.Filer.ToHttpAddress(), s3a.option.BucketsPath, dstBucket, urlEscapeObject(dstObject))
-	srcUrl := fmt.Sprintf("http://%s%s/%s%s",
-		s3a.option.Filer.ToHttpAddress(), s3a.option.BucketsPath, srcBucket, urlEscapeObject(srcObject))
+	srcUrl := fmt.Sprintf("http://%s%s", s3a.option.Filer.ToHttpAddress(), urlEscapeObject(dir+"/"+name))
 
 	_, _, resp, err := util_http.DownloadFile(srcUrl, s3a.maybeGetFilerJwtAuthorizationToken(false))
 	if err != nil {
@@ -100,9 +124,33 @@ func (s3a *S3ApiServer) CopyObjectHandler(w http.ResponseWriter, r *http.Request
 		s3err.WriteErrorResponse(w, r, s3err.ErrInvalidCopySource)
 		return
 	}
+	if errCode := s3a.setObjectLockHeaders(r, dstBucket); errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+	if errCode := s3a.setObjectAclHeaders(r, dstBucket); errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+	dataReader, sse, errCode := s3a.reencryptCopySource(r, resp.Header, resp.Body, dstBucket, 0)
+	if errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+	version, errCode := s3a.prepareObjectVersion(dstBucket, dstObject)
+	if errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+	if version.versionId != "" {
+		r.Header.Set(s3_constants.AmzVersionId, version.versionId)
+	}
+
 	glog.V(2).Infof("copy from %s to %s", srcUrl, dstUrl)
 	destination := fmt.Sprintf("%s/%s%s", s3a.option.BucketsPath, dstBucket, dstObject)
-	etag, errCode := s3a.putToFiler(r, dstUrl, resp.Body, destination, dstBucket)
+	uploadUrl, _ := s3a.uploadTarget(version, dstUrl, destination)
+	etag, errCode := s3a.putToFiler(r, uploadUrl, dataReader, destination, dstBucket)
+	errCode = version.done(errCode)
 
 	if errCode != s3err.ErrNone {
 		s3err.WriteErrorResponse(w, r, errCode)
@@ -110,6 +158,11 @@ func (s3a *S3ApiServer) CopyObjectHandler(w http.ResponseWriter, r *http.Request
 	}
 
 	setEtag(w, etag)
+	setVersionIdHeader(w, version.versionId)
+	sse.setResponseHeaders(w.Header())
+	if srcVersionId != "" {
+		w.Header().Set(s3_constants.AmzCopySourceVersionId, srcVersionId)
+	}
 
 	response := CopyObjectResult{
 		ETag:         etag,
@@ -120,6 +173,31 @@ func (s3a *S3ApiServer) CopyObjectHandler(w http.ResponseWriter, r *http.Request
 
 }
 
+// splitCopySourceVersionId separates the optional ?versionId= suffix of a copy source
+func splitCopySourceVersionId(cpSrcPath string) (path, versionId string) {
+	if idx := strings.Index(cpSrcPath, "?versionId="); idx >= 0 {
+		return cpSrcPath[:idx], cpSrcPath[idx+len("?versionId="):]
+	}
+	return cpSrcPath, ""
+}
+
+// getCopySourceDirAndName locates the copy source object, or the requested version of it
+func (s3a *S3ApiServer) getCopySourceDirAndName(srcBucket, srcObject, srcVersionId string) (dir, name string, errCode s3err.ErrorCode) {
+	if srcVersionId != "" {
+		var entry *filer_pb.Entry
+		dir, name, entry, errCode = s3a.getObjectVersionLocation(srcBucket, srcObject, srcVersionId)
+		if errCode == s3err.ErrNone && (entry.IsDirectory || isDeleteMarker(entry)) {
+			errCode = s3err.ErrInvalidCopySource
+		}
+		return
+	}
+	dir, name = util.FullPath(fmt.Sprintf("%s/%s%s", s3a.option.BucketsPath, srcBucket, srcObject)).DirAndName()
+	if entry, err := s3a.getEntry(dir, name); err != nil || entry.IsDirectory {
+		return "", "", s3err.ErrInvalidCopySource
+	}
+	return dir, name, s3err.ErrNone
+}
+
 func pathToBucketAndObject(path string) (bucket, object string) {
 	path = strings.TrimPrefix(path, "/")
 	parts := strings.SplitN(path, "/", 2)
@@ -146,6 +224,7 @@ func (s3a *S3ApiServer) CopyObjectPartHandler(w http.ResponseWriter, r *http.Req
 		cpSrcPath = r.Header.Get("X-Amz-Copy-Source")
 	}
 
+	cpSrcPath, srcVersionId := splitCopySourceVersionId(cpSrcPath)
 	srcBucket, srcObject := pathToBucketAndObject(cpSrcPath)
 	// If source object is empty or bucket is empty, reply back invalid copy source.
 	if srcObject == "" || srcBucket == "" {
@@ -175,6 +254,14 @@ func (s3a *S3ApiServer) CopyObjectPartHandler(w http.ResponseWriter, r *http.Req
 	dstUrl := s3a.genPartUploadUrl(dstBucket, uploadID, partID)
 	srcUrl := fmt.Sprintf("http://%s%s/%s%s",
 		s3a.option.Filer.ToHttpAddress(), s3a.option.BucketsPath, srcBucket, urlEscapeObject(srcObject))
+	if srcVersionId != "" {
+		dir, name, errCode := s3a.getCopySourceDirAndName(srcBucket, srcObject, srcVersionId)
+		if errCode != s3err.ErrNone {
+			s3err.WriteErrorResponse(w, r, errCode)
+			return
+		}
+		srcUrl = fmt.Sprintf("http://%s%s", s3a.option.Filer.ToHttpAddress(), urlEscapeObject(dir+"/"+name))
+	}
 
 	resp, dataReader, err := util_http.ReadUrlAsReaderCloser(srcUrl, s3a.maybeGetFilerJwtAuthorizationToken(false), rangeHeader)
 	if err != nil {
@@ -184,9 +271,15 @@ func (s3a *S3ApiServer) CopyObjectPartHandler(w http.ResponseWriter, r *http.Req
 	defer util_http.CloseResponse(resp)
 	defer dataReader.Close()
 
+	encryptedReader, sse, errCode := s3a.reencryptCopySource(r, resp.Header, dataReader, dstBucket, partID)
+	if errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+
 	glog.V(2).Infof("copy from %s to %s", srcUrl, dstUrl)
 	destination := fmt.Sprintf("%s/%s%s", s3a.option.BucketsPath, dstBucket, dstObject)
-	etag, errCode := s3a.putToFiler(r, dstUrl, dataReader, destination, dstBucket)
+	etag, errCode := s3a.putToFiler(r, dstUrl, encryptedReader, destination, dstBucket)
 
 	if errCode != s3err.ErrNone {
 		s3err.WriteErrorResponse(w, r, errCode)
@@ -194,6 +287,7 @@ func (s3a *S3ApiServer) CopyObjectPartHandler(w http.ResponseWriter, r *http.Req
 	}
 
 	setEtag(w, etag)
+	sse.setResponseHeaders(w.Header())
 
 	response := CopyPartResult{
 		ETag:         etag,
@@ -204,6 +298,34 @@ func (s3a *S3ApiServer) CopyObjectPartHandler(w http.ResponseWriter, r *http.Req
 
 }
 
+// reencryptCopySource decrypts the copy source, read from the filer with srcHeader response headers,
+// to be encrypted as the new object, or as the part partNumber of the multipart upload of the request.
+func (s3a *S3ApiServer) reencryptCopySource(r *http.Request, srcHeader http.Header, srcReader io.Reader, dstBucket string, partNumber int) (dataReader io.Reader, sse *objectEncryption, errCode s3err.ErrorCode) {
+	srcSSE, errCode := s3a.getObjectEncryption(srcHeader, r.Header, true)
+	if errCode != s3err.ErrNone {
+		return nil, nil, errCode
+	}
+	dataReader, err := srcSSE.decrypt(srcReader, parseContentRangeStart(srcHeader.Get("Content-Range")))
+	if err != nil {
+		glog.Errorf("decrypt copy source: %v", err)
+		return nil, nil, s3err.ErrInternalError
+	}
+
+	if partNumber > 0 {
+		sse, errCode = s3a.getUploadEncryption(r, dstBucket, r.URL.Query().Get("uploadId"))
+	} else {
+		sse, errCode = s3a.newObjectEncryption(r, dstBucket)
+	}
+	if errCode != s3err.ErrNone {
+		return nil, nil, errCode
+	}
+	if dataReader, err = sse.encrypt(r, dataReader); err != nil {
+		glog.Errorf("encrypt copy destination: %v", err)
+		return nil, nil, s3err.ErrInternalError
+	}
+	return dataReader, sse, s3err.ErrNone
+}
+
 func replaceDirective(reqHeader http.Header) (replaceMeta, replaceTagging bool) {
 	return reqHeader.Get(s3_constants.AmzUserMetaDirective) == DirectiveReplace, reqHeader.Get(s3_constants.AmzObjectTaggingDirective) == DirectiveReplace
 }
@@ -263,6 +385,24 @@ func processMetadata(reqHeader, existing http.Header, replaceMeta, replaceTaggin
 func processMetadataBytes(reqHeader http.Header, existing map[string][]byte, replaceMeta, replaceTagging bool) (metadata map[string][]byte, err error) {
 	metadata = make(map[string][]byte)
 
+	if versionId := existing[s3_constants.AmzVersionId]; len(versionId) > 0 {
+		metadata[s3_constants.AmzVersionId] = versionId
+	}
+
+	// object lock is only changed with the retention and legal hold apis
+	for _, k := range []string{s3_constants.AmzObjectLockMode, s3_constants.AmzObjectLockRetainUntilDate, s3_constants.AmzObjectLockLegalHold} {
+		if v := existing[k]; len(v) > 0 {
+			metadata[k] = v
+		}
+	}
+
+	// the object data is not changed, so is its encryption
+	for _, k := range s3_constants.SSEMetadataKeys {
+		if v := existing[k]; len(v) > 0 {
+			metadata[k] = v
+		}
+	}
+
 	if sc := existing[s3_constants.AmzStorageClass]; len(sc) > 0 {
 		metadata[s3_constants.AmzStorageClass] = sc
 	}
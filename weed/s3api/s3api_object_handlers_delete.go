@@ -27,6 +27,28 @@ func (s3a *S3ApiServer) DeleteObjectHandler(w http.ResponseWriter, r *http.Reque
 	bucket, object := s3_constants.GetBucketAndObject(r)
 	glog.V(3).Infof("DeleteObjectHandler %s %s", bucket, object)
 
+	versionId := r.URL.Query().Get("versionId")
+	versioning, errCode := s3a.getVersioningState(bucket)
+	if errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+	if versionId != "" || versioning != "" {
+		bypassGovernance := s3a.canBypassGovernanceRetention(r, bucket, object)
+		resultVersionId, deleteMarker, errCode := s3a.deleteObjectWithVersioning(bucket, object, versionId, versioning, bypassGovernance)
+		if errCode != s3err.ErrNone {
+			s3err.WriteErrorResponse(w, r, errCode)
+			return
+		}
+		setVersionIdHeader(w, resultVersionId)
+		if deleteMarker {
+			w.Header().Set(s3_constants.AmzDeleteMarker, "true")
+		}
+		s3a.deleteEmptyParentDirectories(bucket, object)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
 	target := util.FullPath(fmt.Sprintf("%s/%s%s", s3a.option.BucketsPath, bucket, object))
 	dir, name := target.DirAndName()
 
@@ -61,7 +83,10 @@ func (s3a *S3ApiServer) DeleteObjectHandler(w http.ResponseWriter, r *http.Reque
 
 // / ObjectIdentifier carries key name for the object to delete.
 type ObjectIdentifier struct {
-	ObjectName string `xml:"Key"`
+	ObjectName            string `xml:"Key"`
+	VersionId             string `xml:"VersionId,omitempty"`
+	DeleteMarker          bool   `xml:"DeleteMarker,omitempty"`
+	DeleteMarkerVersionId string `xml:"DeleteMarkerVersionId,omitempty"`
 }
 
 // DeleteObjectsRequest - xml carrying the object key names which needs to be deleted.
@@ -113,6 +138,12 @@ func (s3a *S3ApiServer) DeleteMultipleObjectsHandler(w http.ResponseWriter, r *h
 		return
 	}
 
+	versioning, errCode := s3a.getVersioningState(bucket)
+	if errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+
 	var deletedObjects []ObjectIdentifier
 	var deleteErrors []DeleteError
 	var auditLog *s3err.AccessLog
@@ -137,6 +168,31 @@ func (s3a *S3ApiServer) DeleteMultipleObjectsHandler(w http.ResponseWriter, r *h
 			}
 			parentDirectoryPath = fmt.Sprintf("%s/%s%s", s3a.option.BucketsPath, bucket, parentDirectoryPath)
 
+			if object.VersionId != "" || versioning != "" {
+				bypassGovernance := s3a.canBypassGovernanceRetention(r, bucket, "/"+object.ObjectName)
+				resultVersionId, deleteMarker, errCode := s3a.deleteObjectWithVersioning(bucket, "/"+object.ObjectName, object.VersionId, versioning, bypassGovernance)
+				if errCode == s3err.ErrNone {
+					directoriesWithDeletion[parentDirectoryPath]++
+					deleted := ObjectIdentifier{ObjectName: object.ObjectName, VersionId: object.VersionId, DeleteMarker: deleteMarker}
+					if deleteMarker {
+						deleted.DeleteMarkerVersionId = resultVersionId
+					}
+					deletedObjects = append(deletedObjects, deleted)
+				} else {
+					apiError := s3err.GetAPIError(errCode)
+					deleteErrors = append(deleteErrors, DeleteError{
+						Code:    apiError.Code,
+						Message: apiError.Description,
+						Key:     object.ObjectName,
+					})
+				}
+				if auditLog != nil {
+					auditLog.Key = entryName
+					s3err.PostAccessLog(*auditLog)
+				}
+				continue
+			}
+
 			err := doDeleteEntry(client, parentDirectoryPath, entryName, isDeleteData, isRecursive)
 			if err == nil {
 				directoriesWithDeletion[parentDirectoryPath]++
@@ -197,3 +253,18 @@ func (s3a *S3ApiServer) doDeleteEmptyDirectories(client filer_pb.SeaweedFilerCli
 	}
 	return
 }
+
+// deleteEmptyParentDirectories purges the folders left empty after an object is removed from its location
+func (s3a *S3ApiServer) deleteEmptyParentDirectories(bucket, object string) {
+	if s3a.option.AllowEmptyFolder || strings.LastIndex(object, "/") <= 0 {
+		return
+	}
+	dir, _ := s3a.genObjectDirAndName(bucket, object)
+	s3a.WithFilerClient(false, func(client filer_pb.SeaweedFilerClient) error {
+		directoriesWithDeletion := map[string]int{dir: 1}
+		for len(directoriesWithDeletion) > 0 {
+			directoriesWithDeletion = s3a.doDeleteEmptyDirectories(client, directoriesWithDeletion)
+		}
+		return nil
+	})
+}
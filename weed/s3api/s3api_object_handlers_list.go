@@ -340,7 +340,7 @@ func (s3a *S3ApiServer) doListFilerEntries(client filer_pb.SeaweedFilerClient, d
 	request := &filer_pb.ListEntriesRequest{
 		Directory:          dir,
 		Prefix:             prefix,
-		Limit:              uint32(cursor.maxKeys + 2), // bucket root directory needs to skip additional s3_constants.MultipartUploadsFolder folder
+		Limit:              uint32(cursor.maxKeys + 4), // bucket root directory needs to skip additional s3_constants.MultipartUploadsFolder, s3_constants.VersionsFolder and s3_constants.TrashFolder folders
 		StartFromFileName:  marker,
 		InclusiveStartFrom: inclusiveStartFrom,
 	}
@@ -383,7 +383,7 @@ func (s3a *S3ApiServer) doListFilerEntries(client filer_pb.SeaweedFilerClient, d
 		}
 		if entry.IsDirectory {
 			// glog.V(4).Infof("List Dir Entries %s, file: %s, maxKeys %d", dir, entry.Name, cursor.maxKeys)
-			if entry.Name == s3_constants.MultipartUploadsFolder { // FIXME no need to apply to all directories. this extra also affects maxKeys
+			if entry.Name == s3_constants.MultipartUploadsFolder || entry.Name == s3_constants.VersionsFolder || entry.Name == s3_constants.TrashFolder { // FIXME no need to apply to all directories. this extra also affects maxKeys
 				continue
 			}
 			if delimiter != "/" || cursor.prefixEndsOnDelimiter {
@@ -0,0 +1,339 @@
+package s3api
+
+import (
+	"encoding/xml"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/seaweedfs/seaweedfs/weed/filer"
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3_constants"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3err"
+)
+
+type ListObjectVersionsResult struct {
+	XMLName             xml.Name            `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListVersionsResult"`
+	Name                string              `xml:"Name"`
+	Prefix              string              `xml:"Prefix"`
+	KeyMarker           string              `xml:"KeyMarker"`
+	VersionIdMarker     string              `xml:"VersionIdMarker"`
+	NextKeyMarker       string              `xml:"NextKeyMarker,omitempty"`
+	NextVersionIdMarker string              `xml:"NextVersionIdMarker,omitempty"`
+	MaxKeys             int                 `xml:"MaxKeys"`
+	Delimiter           string              `xml:"Delimiter,omitempty"`
+	IsTruncated         bool                `xml:"IsTruncated"`
+	Versions            []VersionEntry      `xml:"Version,omitempty"`
+	DeleteMarkers       []DeleteMarkerEntry `xml:"DeleteMarker,omitempty"`
+	CommonPrefixes      []PrefixEntry       `xml:"CommonPrefixes,omitempty"`
+	EncodingType        string              `xml:"EncodingType,omitempty"`
+}
+
+// ListObjectVersionsHandler List object versions
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_ListObjectVersions.html
+func (s3a *S3ApiServer) ListObjectVersionsHandler(w http.ResponseWriter, r *http.Request) {
+
+	bucket, _ := s3_constants.GetBucketAndObject(r)
+	glog.V(3).Infof("ListObjectVersionsHandler %s", bucket)
+
+	prefix, keyMarker, versionIdMarker, delimiter, encodingTypeUrl, maxKeys := getListObjectVersionsArgs(r.URL.Query())
+	if maxKeys < 0 {
+		s3err.WriteErrorResponse(w, r, s3err.ErrInvalidMaxKeys)
+		return
+	}
+
+	if exists, existErr := s3a.exists(s3a.option.BucketsPath, bucket, true); existErr == nil && !exists {
+		s3err.WriteErrorResponse(w, r, s3err.ErrNoSuchBucket)
+		return
+	}
+
+	listing := &versionsListing{
+		s3a:             s3a,
+		bucketDir:       fmt.Sprintf("%s/%s", s3a.option.BucketsPath, bucket),
+		prefix:          strings.TrimPrefix(prefix, "/"),
+		delimiter:       delimiter,
+		keyMarker:       keyMarker,
+		versionIdMarker: versionIdMarker,
+		maxKeys:         maxKeys,
+	}
+	if err := listing.walk(""); err != nil {
+		glog.Errorf("ListObjectVersionsHandler %s: %v", bucket, err)
+		s3err.WriteErrorResponse(w, r, s3err.ErrInternalError)
+		return
+	}
+
+	response := ListObjectVersionsResult{
+		Name:            bucket,
+		Prefix:          prefix,
+		KeyMarker:       keyMarker,
+		VersionIdMarker: versionIdMarker,
+		MaxKeys:         maxKeys,
+		Delimiter:       delimiter,
+		IsTruncated:     listing.isTruncated,
+		Versions:        listing.versions,
+		DeleteMarkers:   listing.deleteMarkers,
+		CommonPrefixes:  listing.commonPrefixes,
+	}
+	if listing.isTruncated {
+		response.NextKeyMarker = listing.lastKey
+		response.NextVersionIdMarker = listing.lastVersionId
+	}
+	if encodingTypeUrl {
+		response.EncodingType = s3.EncodingTypeUrl
+		response.Prefix = urlPathEscape(response.Prefix)
+		response.NextKeyMarker = urlPathEscape(response.NextKeyMarker)
+		for i := range response.Versions {
+			response.Versions[i].Key = urlPathEscape(response.Versions[i].Key)
+		}
+		for i := range response.DeleteMarkers {
+			response.DeleteMarkers[i].Key = urlPathEscape(response.DeleteMarkers[i].Key)
+		}
+		for i := range response.CommonPrefixes {
+			response.CommonPrefixes[i].Prefix = urlPathEscape(response.CommonPrefixes[i].Prefix)
+		}
+	}
+
+	writeSuccessResponseXML(w, r, response)
+}
+
+func getListObjectVersionsArgs(values url.Values) (prefix, keyMarker, versionIdMarker, delimiter string, encodingTypeUrl bool, maxkeys int) {
+	prefix = values.Get("prefix")
+	keyMarker = values.Get("key-marker")
+	versionIdMarker = values.Get("version-id-marker")
+	delimiter = values.Get("delimiter")
+	encodingTypeUrl = values.Get("encoding-type") == s3.EncodingTypeUrl
+	maxkeys = maxObjectListSizeLimit
+	if values.Get("max-keys") != "" {
+		if maxKeys, err := strconv.Atoi(values.Get("max-keys")); err == nil {
+			maxkeys = maxKeys
+		}
+	}
+	return
+}
+
+// versionsListing walks the current objects and the versions folder of a bucket side by side,
+// in key order, collecting all versions and delete markers
+type versionsListing struct {
+	s3a             *S3ApiServer
+	bucketDir       string
+	prefix          string
+	delimiter       string
+	keyMarker       string
+	versionIdMarker string
+	maxKeys         int
+
+	count          int
+	isTruncated    bool
+	lastKey        string
+	lastVersionId  string
+	versions       []VersionEntry
+	deleteMarkers  []DeleteMarkerEntry
+	commonPrefixes []PrefixEntry
+}
+
+type versionsListingChild struct {
+	current     *filer_pb.Entry
+	hasVersions bool
+}
+
+func (l *versionsListing) isDone() bool {
+	return l.isTruncated
+}
+
+// add reserves a slot in the response, and returns false once max-keys is reached
+func (l *versionsListing) add(key, versionId string) bool {
+	if l.count >= l.maxKeys {
+		l.isTruncated = true
+		return false
+	}
+	l.count++
+	l.lastKey, l.lastVersionId = key, versionId
+	return true
+}
+
+func (l *versionsListing) walk(relDir string) error {
+	children := make(map[string]*versionsListingChild)
+	getChild := func(name string) *versionsListingChild {
+		child, found := children[name]
+		if !found {
+			child = &versionsListingChild{}
+			children[name] = child
+		}
+		return child
+	}
+
+	currentDir := strings.TrimSuffix(l.bucketDir+"/"+relDir, "/")
+	if err := filer_pb.List(l.s3a, currentDir, "", func(entry *filer_pb.Entry, isLast bool) error {
+		if relDir == "" && (entry.Name == s3_constants.MultipartUploadsFolder || entry.Name == s3_constants.VersionsFolder || entry.Name == s3_constants.TrashFolder) {
+			return nil
+		}
+		getChild(entry.Name).current = entry
+		return nil
+	}, "", false, math.MaxUint32); err != nil && err != filer_pb.ErrNotFound {
+		return err
+	}
+
+	versionsDir := strings.TrimSuffix(l.bucketDir+"/"+s3_constants.VersionsFolder+"/"+relDir, "/")
+	if err := filer_pb.List(l.s3a, versionsDir, "", func(entry *filer_pb.Entry, isLast bool) error {
+		if entry.IsDirectory {
+			getChild(entry.Name).hasVersions = true
+		}
+		return nil
+	}, "", false, math.MaxUint32); err != nil && err != filer_pb.ErrNotFound {
+		return err
+	}
+
+	names := make([]string, 0, len(children))
+	for name := range children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if l.isDone() {
+			return nil
+		}
+		child := children[name]
+		key := name
+		if relDir != "" {
+			key = relDir + "/" + name
+		}
+
+		var archived []*filer_pb.Entry
+		hasArchivedSubtree := false
+		if child.hasVersions {
+			if err := filer_pb.List(l.s3a, versionsDir+"/"+name, "", func(entry *filer_pb.Entry, isLast bool) error {
+				if entry.IsDirectory {
+					hasArchivedSubtree = true
+				} else if !isStagedVersion(entry) {
+					archived = append(archived, entry)
+				}
+				return nil
+			}, "", false, math.MaxUint32); err != nil && err != filer_pb.ErrNotFound {
+				return err
+			}
+			sortVersionsNewestFirst(archived)
+		}
+
+		// versions of the key itself
+		var current *filer_pb.Entry
+		if child.current != nil && !child.current.IsDirectory {
+			current = child.current
+		}
+		if (current != nil || len(archived) > 0) && strings.HasPrefix(key, l.prefix) {
+			if commonPrefix, found := l.commonPrefixOf(key); found {
+				l.addCommonPrefix(commonPrefix)
+			} else {
+				l.addKeyVersions(key, current, archived)
+			}
+		}
+
+		// keys under the key as a folder
+		hasSubtree := hasArchivedSubtree || (child.current != nil && child.current.IsDirectory)
+		if !hasSubtree || !l.shouldWalkInto(key+"/") {
+			continue
+		}
+		if l.delimiter == "/" && strings.HasPrefix(key+"/", l.prefix) {
+			l.addCommonPrefix(key + "/")
+			continue
+		}
+		if err := l.walk(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *versionsListing) shouldWalkInto(dirPrefix string) bool {
+	if !strings.HasPrefix(dirPrefix, l.prefix) && !strings.HasPrefix(l.prefix, dirPrefix) {
+		return false
+	}
+	if l.keyMarker != "" && dirPrefix < l.keyMarker && !strings.HasPrefix(l.keyMarker, dirPrefix) {
+		return false
+	}
+	return true
+}
+
+func (l *versionsListing) commonPrefixOf(key string) (string, bool) {
+	if l.delimiter == "" {
+		return "", false
+	}
+	remaining := key[len(l.prefix):]
+	if idx := strings.Index(remaining, l.delimiter); idx >= 0 {
+		return l.prefix + remaining[:idx+len(l.delimiter)], true
+	}
+	return "", false
+}
+
+func (l *versionsListing) addCommonPrefix(commonPrefix string) {
+	if l.keyMarker != "" && commonPrefix <= l.keyMarker {
+		return
+	}
+	if n := len(l.commonPrefixes); n > 0 && l.commonPrefixes[n-1].Prefix == commonPrefix {
+		return
+	}
+	if l.add(commonPrefix, "") {
+		l.commonPrefixes = append(l.commonPrefixes, PrefixEntry{Prefix: commonPrefix})
+	}
+}
+
+func (l *versionsListing) addKeyVersions(key string, current *filer_pb.Entry, archived []*filer_pb.Entry) {
+	if key < l.keyMarker || key == l.keyMarker && l.versionIdMarker == "" {
+		return
+	}
+
+	entries := archived
+	if current != nil {
+		entries = append([]*filer_pb.Entry{current}, archived...)
+	}
+
+	skipping := key == l.keyMarker
+	for i, entry := range entries {
+		versionId := getEntryVersionId(entry)
+		if skipping {
+			if versionId == l.versionIdMarker {
+				skipping = false
+			}
+			continue
+		}
+		if !l.add(key, versionId) {
+			return
+		}
+		lastModified := time.Unix(entry.Attributes.Mtime, 0).UTC()
+		owner := CanonicalUser{
+			ID:          fmt.Sprintf("%x", entry.Attributes.Uid),
+			DisplayName: entry.Attributes.UserName,
+		}
+		if isDeleteMarker(entry) {
+			l.deleteMarkers = append(l.deleteMarkers, DeleteMarkerEntry{
+				Key:          key,
+				VersionId:    versionId,
+				IsLatest:     i == 0,
+				LastModified: lastModified,
+				Owner:        owner,
+			})
+			continue
+		}
+		storageClass := "STANDARD"
+		if v, ok := entry.Extended[s3_constants.AmzStorageClass]; ok {
+			storageClass = string(v)
+		}
+		l.versions = append(l.versions, VersionEntry{
+			Key:          key,
+			VersionId:    versionId,
+			IsLatest:     i == 0,
+			LastModified: lastModified,
+			ETag:         "\"" + filer.ETag(entry) + "\"",
+			Size:         int64(filer.FileSize(entry)),
+			Owner:        owner,
+			StorageClass: StorageClass(storageClass),
+		})
+	}
+}
@@ -37,6 +37,21 @@ func (s3a *S3ApiServer) NewMultipartUploadHandler(w http.ResponseWriter, r *http
 		Metadata: make(map[string]*string),
 	}
 
+	// the version id is assigned when the upload completes
+	r.Header.Del(s3_constants.AmzVersionId)
+	if errCode := s3a.setObjectLockHeaders(r, bucket); errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+	if errCode := s3a.setObjectAclHeaders(r, bucket); errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+	sse, errCode := s3a.newObjectEncryption(r, bucket)
+	if errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
 	metadata := weed_server.SaveAmzMetaData(r, nil, false)
 	for k, v := range metadata {
 		createMultipartUploadInput.Metadata[k] = aws.String(string(v))
@@ -55,6 +70,7 @@ func (s3a *S3ApiServer) NewMultipartUploadHandler(w http.ResponseWriter, r *http
 		return
 	}
 
+	sse.setResponseHeaders(w.Header())
 	writeSuccessResponseXML(w, r, response)
 
 }
@@ -92,6 +108,9 @@ func (s3a *S3ApiServer) CompleteMultipartUploadHandler(w http.ResponseWriter, r
 		return
 	}
 
+	if response.VersionId != nil {
+		setVersionIdHeader(w, *response.VersionId)
+	}
 	writeSuccessResponseXML(w, r, response)
 
 }
@@ -255,13 +274,26 @@ func (s3a *S3ApiServer) PutObjectPartHandler(w http.ResponseWriter, r *http.Requ
 	}
 	destination := fmt.Sprintf("%s/%s%s", s3a.option.BucketsPath, bucket, object)
 
-	etag, errCode := s3a.putToFiler(r, uploadUrl, dataReader, destination, bucket)
+	sse, errCode := s3a.getUploadEncryption(r, bucket, uploadID)
+	if errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+	encryptedReader, err := sse.encrypt(r, dataReader)
+	if err != nil {
+		glog.Errorf("PutObjectPartHandler encrypt %s %s: %v", bucket, uploadID, err)
+		s3err.WriteErrorResponse(w, r, s3err.ErrInternalError)
+		return
+	}
+
+	etag, errCode := s3a.putToFiler(r, uploadUrl, encryptedReader, destination, bucket)
 	if errCode != s3err.ErrNone {
 		s3err.WriteErrorResponse(w, r, errCode)
 		return
 	}
 
 	setEtag(w, etag)
+	sse.setResponseHeaders(w.Header())
 
 	writeSuccessResponseEmpty(w, r)
 
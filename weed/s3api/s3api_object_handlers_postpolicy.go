@@ -131,17 +131,63 @@ func (s3a *S3ApiServer) PostPolicyBucketHandler(w http.ResponseWriter, r *http.R
 			continue
 		}
 
+		switch k {
+		case s3_constants.AmzServerSideEncryption, s3_constants.AmzServerSideEncryptionAwsKmsKeyId,
+			s3_constants.AmzServerSideEncryptionCustomerAlgorithm, s3_constants.AmzServerSideEncryptionCustomerKey, s3_constants.AmzServerSideEncryptionCustomerKeyMD5:
+			r.Header.Set(k, formValues.Get(k))
+			continue
+		case "Acl":
+			r.Header.Set(s3_constants.AmzCannedAcl, formValues.Get(k))
+			continue
+		}
+
 		if strings.HasPrefix(k, s3_constants.AmzUserMetaPrefix) {
 			r.Header.Set(k, formValues.Get(k))
 		}
 	}
 
-	etag, errCode := s3a.putToFiler(r, uploadUrl, fileBody, "", bucket)
+	if errCode := s3a.setObjectLockHeaders(r, bucket); errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+	if errCode := s3a.setObjectAclHeaders(r, bucket); errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+
+	sse, errCode := s3a.newObjectEncryption(r, bucket)
+	if errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+	encryptedBody, err := sse.encrypt(r, fileBody)
+	if err != nil {
+		glog.Errorf("PostPolicyBucketHandler encrypt %s %s: %v", bucket, object, err)
+		s3err.WriteErrorResponse(w, r, s3err.ErrInternalError)
+		return
+	}
+
+	objectPath := "/" + strings.TrimPrefix(object, "/")
+	version, errCode := s3a.prepareObjectVersion(bucket, objectPath)
+	if errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+	r.Header.Del(s3_constants.AmzVersionId)
+	if version.versionId != "" {
+		r.Header.Set(s3_constants.AmzVersionId, version.versionId)
+	}
+
+	uploadUrl, destination := s3a.uploadTarget(version, uploadUrl, fmt.Sprintf("%s/%s%s", s3a.option.BucketsPath, bucket, objectPath))
+	etag, errCode := s3a.putToFiler(r, uploadUrl, encryptedBody, destination, bucket)
+	errCode = version.done(errCode)
 
 	if errCode != s3err.ErrNone {
 		s3err.WriteErrorResponse(w, r, errCode)
 		return
 	}
+	setVersionIdHeader(w, version.versionId)
+	sse.setResponseHeaders(w.Header())
 
 	if successRedirect != "" {
 		// Replace raw query params..
@@ -3,6 +3,7 @@ package s3api
 import (
 	"crypto/md5"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -10,6 +11,7 @@ import (
 	"time"
 
 	"github.com/pquerna/cachecontrol/cacheobject"
+	"github.com/seaweedfs/seaweedfs/weed/filer"
 	"github.com/seaweedfs/seaweedfs/weed/s3api/s3_constants"
 	"github.com/seaweedfs/seaweedfs/weed/s3api/s3err"
 	"github.com/seaweedfs/seaweedfs/weed/security"
@@ -70,6 +72,9 @@ func (s3a *S3ApiServer) PutObjectHandler(w http.ResponseWriter, r *http.Request)
 	}
 	defer dataReader.Close()
 
+	// the version id is only assigned by the gateway
+	r.Header.Del(s3_constants.AmzVersionId)
+
 	objectContentType := r.Header.Get("Content-Type")
 	if strings.HasSuffix(object, "/") && r.ContentLength <= 1024 {
 		if err := s3a.mkdir(
@@ -92,7 +97,39 @@ func (s3a *S3ApiServer) PutObjectHandler(w http.ResponseWriter, r *http.Request)
 			dataReader = mimeDetect(r, dataReader)
 		}
 
-		etag, errCode := s3a.putToFiler(r, uploadUrl, dataReader, "", bucket)
+		if errCode := s3a.setObjectLockHeaders(r, bucket); errCode != s3err.ErrNone {
+			s3err.WriteErrorResponse(w, r, errCode)
+			return
+		}
+		if errCode := s3a.setObjectAclHeaders(r, bucket); errCode != s3err.ErrNone {
+			s3err.WriteErrorResponse(w, r, errCode)
+			return
+		}
+
+		sse, errCode := s3a.newObjectEncryption(r, bucket)
+		if errCode != s3err.ErrNone {
+			s3err.WriteErrorResponse(w, r, errCode)
+			return
+		}
+		encryptedReader, err := sse.encrypt(r, dataReader)
+		if err != nil {
+			glog.Errorf("PutObjectHandler encrypt %s %s: %v", bucket, object, err)
+			s3err.WriteErrorResponse(w, r, s3err.ErrInternalError)
+			return
+		}
+
+		version, errCode := s3a.prepareObjectVersion(bucket, object)
+		if errCode != s3err.ErrNone {
+			s3err.WriteErrorResponse(w, r, errCode)
+			return
+		}
+		if version.versionId != "" {
+			r.Header.Set(s3_constants.AmzVersionId, version.versionId)
+		}
+
+		uploadUrl, destination := s3a.uploadTarget(version, uploadUrl, fmt.Sprintf("%s/%s%s", s3a.option.BucketsPath, bucket, object))
+		etag, errCode := s3a.putToFiler(r, uploadUrl, encryptedReader, destination, bucket)
+		errCode = version.done(errCode)
 
 		if errCode != s3err.ErrNone {
 			s3err.WriteErrorResponse(w, r, errCode)
@@ -100,6 +137,8 @@ func (s3a *S3ApiServer) PutObjectHandler(w http.ResponseWriter, r *http.Request)
 		}
 
 		setEtag(w, etag)
+		setVersionIdHeader(w, version.versionId)
+		sse.setResponseHeaders(w.Header())
 	}
 
 	writeSuccessResponseEmpty(w, r)
@@ -127,12 +166,21 @@ func (s3a *S3ApiServer) putToFiler(r *http.Request, uploadUrl string, dataReader
 		query.Add("collection", s3a.getCollectionName(bucket))
 		proxyReq.URL.RawQuery = query.Encode()
 	}
+	// SSE-S3 objects are encrypted by the filer
+	if r.Header.Get(s3_constants.AmzServerSideEncryption) == s3_constants.SSEAlgorithmAES256 {
+		query := proxyReq.URL.Query()
+		query.Add("cipher", "true")
+		proxyReq.URL.RawQuery = query.Encode()
+	}
 
 	for header, values := range r.Header {
 		for _, value := range values {
 			proxyReq.Header.Add(header, value)
 		}
 	}
+	// the customer keys are never sent to the filer
+	proxyReq.Header.Del(s3_constants.AmzServerSideEncryptionCustomerKey)
+	proxyReq.Header.Del(s3_constants.AmzCopySourceServerSideEncryptionCustomerKey)
 	// ensure that the Authorization header is overriding any previous
 	// Authorization header which might be already present in proxyReq
 	s3a.maybeAddFilerJwtAuthorization(proxyReq, true)
@@ -140,6 +188,9 @@ func (s3a *S3ApiServer) putToFiler(r *http.Request, uploadUrl string, dataReader
 
 	if postErr != nil {
 		glog.Errorf("post to filer: %v", postErr)
+		if errors.Is(postErr, errContentMd5Mismatch) {
+			return "", s3err.ErrBadDigest
+		}
 		return "", s3err.ErrInternalError
 	}
 	defer resp.Body.Close()
@@ -181,6 +232,10 @@ func filerErrorToS3Error(errString string) s3err.ErrorCode {
 		return s3err.ErrExistingObjectIsDirectory
 	case strings.HasSuffix(errString, "is a file"):
 		return s3err.ErrExistingObjectIsFile
+	case strings.Contains(errString, filer.MsgQuotaExceeded):
+		return s3err.ErrQuotaExceeded
+	case strings.Contains(errString, filer.MsgReadOnlySnapshot):
+		return s3err.ErrAccessDenied
 	default:
 		return s3err.ErrInternalError
 	}
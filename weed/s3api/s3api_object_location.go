@@ -0,0 +1,55 @@
+package s3api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3err"
+)
+
+type objectLocationCacheKey struct{}
+
+type objectLocation struct {
+	dir, name string
+	entry     *filer_pb.Entry
+	errCode   s3err.ErrorCode
+}
+
+// withObjectLocationCache keeps the object versions located for the read request,
+// so that the object looked up to check its acl is not looked up again by the handler
+func withObjectLocationCache(r *http.Request) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), objectLocationCacheKey{}, make(map[string]*objectLocation)))
+}
+
+// locateObjectVersion finds the given version of the object, or the current object without a version id,
+// reusing the location found earlier for the same request
+func (s3a *S3ApiServer) locateObjectVersion(r *http.Request, bucket, object, versionId string) (dir, name string, entry *filer_pb.Entry, errCode s3err.ErrorCode) {
+	cache, _ := r.Context().Value(objectLocationCacheKey{}).(map[string]*objectLocation)
+	cacheKey := bucket + object + "?versionId=" + versionId
+	if location, found := cache[cacheKey]; found {
+		return location.dir, location.name, location.entry, location.errCode
+	}
+
+	if versionId != "" {
+		dir, name, entry, errCode = s3a.getObjectVersionLocation(bucket, object, versionId)
+	} else {
+		current, err := s3a.getCurrentObjectEntry(bucket, object)
+		switch {
+		case err != nil:
+			glog.Errorf("locateObjectVersion %s%s: %v", bucket, object, err)
+			errCode = s3err.ErrInternalError
+		case current == nil:
+			errCode = s3err.ErrNoSuchKey
+		default:
+			dir, name = s3a.genObjectDirAndName(bucket, object)
+			entry = current
+		}
+	}
+
+	if cache != nil && errCode != s3err.ErrInternalError {
+		cache[cacheKey] = &objectLocation{dir: dir, name: name, entry: entry, errCode: errCode}
+	}
+	return
+}
@@ -0,0 +1,444 @@
+package s3api
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3_constants"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3err"
+)
+
+// Object Lock keeps object versions from being deleted or overwritten.
+//
+// The bucket configuration is stored as xml in the bucket entry Extended[ExtObjectLockKey].
+// Object Lock can only be enabled on buckets with versioning enabled, and versioning can not be
+// suspended afterwards, so new writes and deletes without a version id never destroy a version.
+//
+// The retention and legal hold of each object version are kept in its entry Extended attributes,
+// under the same names as the S3 headers, so they are returned as is on GET and HEAD.
+
+// ObjectLockConfiguration is the bucket level Object Lock configuration
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_ObjectLockConfiguration.html
+type ObjectLockConfiguration struct {
+	XMLName           xml.Name        `xml:"ObjectLockConfiguration"`
+	ObjectLockEnabled string          `xml:"ObjectLockEnabled,omitempty"`
+	Rule              *ObjectLockRule `xml:"Rule,omitempty"`
+}
+
+type ObjectLockRule struct {
+	DefaultRetention *DefaultRetention `xml:"DefaultRetention,omitempty"`
+}
+
+type DefaultRetention struct {
+	Mode  string `xml:"Mode,omitempty"`
+	Days  int    `xml:"Days,omitempty"`
+	Years int    `xml:"Years,omitempty"`
+}
+
+// ObjectRetention https://docs.aws.amazon.com/AmazonS3/latest/API/API_ObjectLockRetention.html
+type ObjectRetention struct {
+	XMLName         xml.Name   `xml:"Retention"`
+	Mode            string     `xml:"Mode,omitempty"`
+	RetainUntilDate *time.Time `xml:"RetainUntilDate,omitempty"`
+}
+
+// ObjectLegalHold https://docs.aws.amazon.com/AmazonS3/latest/API/API_ObjectLockLegalHold.html
+type ObjectLegalHold struct {
+	XMLName xml.Name `xml:"LegalHold"`
+	Status  string   `xml:"Status"`
+}
+
+func (c *ObjectLockConfiguration) isEnabled() bool {
+	return c != nil && c.ObjectLockEnabled == s3.ObjectLockEnabledEnabled
+}
+
+func (c *ObjectLockConfiguration) validate() bool {
+	if c.ObjectLockEnabled != s3.ObjectLockEnabledEnabled {
+		return false
+	}
+	if c.Rule == nil {
+		return true
+	}
+	retention := c.Rule.DefaultRetention
+	if retention == nil || !isValidRetentionMode(retention.Mode) || retention.Days < 0 || retention.Years < 0 {
+		return false
+	}
+	// exactly one of Days and Years
+	return (retention.Days > 0) != (retention.Years > 0)
+}
+
+// defaultRetainUntilDate computes the retention applied to new objects without an explicit retention
+func (c *ObjectLockConfiguration) defaultRetainUntilDate(now time.Time) (mode string, retainUntilDate time.Time, found bool) {
+	if !c.isEnabled() || c.Rule == nil || c.Rule.DefaultRetention == nil {
+		return "", time.Time{}, false
+	}
+	retention := c.Rule.DefaultRetention
+	return retention.Mode, now.AddDate(retention.Years, 0, retention.Days).UTC(), true
+}
+
+func isValidRetentionMode(mode string) bool {
+	return mode == s3.ObjectLockRetentionModeGovernance || mode == s3.ObjectLockRetentionModeCompliance
+}
+
+func (s3a *S3ApiServer) getObjectLockConfiguration(bucket string) (*ObjectLockConfiguration, s3err.ErrorCode) {
+	bucketMetadata, errCode := s3a.bucketRegistry.GetBucketMetadata(bucket)
+	if errCode != s3err.ErrNone {
+		return nil, errCode
+	}
+	return bucketMetadata.ObjectLock, s3err.ErrNone
+}
+
+// getEntryRetention reads the retention of an object version, empty mode if there is none
+func getEntryRetention(entry *filer_pb.Entry) (mode string, retainUntilDate time.Time) {
+	if entry.Extended == nil {
+		return "", time.Time{}
+	}
+	mode = string(entry.Extended[s3_constants.AmzObjectLockMode])
+	if mode == "" {
+		return "", time.Time{}
+	}
+	retainUntilDate, err := time.Parse(time.RFC3339, string(entry.Extended[s3_constants.AmzObjectLockRetainUntilDate]))
+	if err != nil {
+		// never loosen a retention that can not be read
+		glog.Warningf("invalid retain until date of %s: %v", entry.Name, err)
+		return mode, time.Unix(1<<62, 0)
+	}
+	return mode, retainUntilDate
+}
+
+func isEntryUnderLegalHold(entry *filer_pb.Entry) bool {
+	return entry.Extended != nil && string(entry.Extended[s3_constants.AmzObjectLockLegalHold]) == s3.ObjectLockLegalHoldStatusOn
+}
+
+// checkObjectLock returns ErrObjectLocked if the object version can not be removed or overwritten
+func checkObjectLock(entry *filer_pb.Entry, bypassGovernance bool) s3err.ErrorCode {
+	if entry == nil {
+		return s3err.ErrNone
+	}
+	if isEntryUnderLegalHold(entry) {
+		return s3err.ErrObjectLocked
+	}
+	mode, retainUntilDate := getEntryRetention(entry)
+	if mode == "" || !retainUntilDate.After(time.Now()) {
+		return s3err.ErrNone
+	}
+	if mode == s3.ObjectLockRetentionModeGovernance && bypassGovernance {
+		return s3err.ErrNone
+	}
+	return s3err.ErrObjectLocked
+}
+
+// canBypassGovernanceRetention honors x-amz-bypass-governance-retention for identities with admin rights on the bucket
+func (s3a *S3ApiServer) canBypassGovernanceRetention(r *http.Request, bucket, object string) bool {
+	if !strings.EqualFold(r.Header.Get(s3_constants.AmzBypassGovernanceRetention), "true") {
+		return false
+	}
+	if !s3a.iam.isEnabled() {
+		return true
+	}
+	identity := getAuthenticatedIdentity(r)
+	return identity != nil && identity.canDo(s3_constants.ACTION_ADMIN, bucket, object)
+}
+
+// setObjectLockHeaders validates the object lock headers of a write request, and applies the
+// bucket default retention if the request has none. The headers are then saved along with the object.
+func (s3a *S3ApiServer) setObjectLockHeaders(r *http.Request, bucket string) s3err.ErrorCode {
+	mode := r.Header.Get(s3_constants.AmzObjectLockMode)
+	retainUntil := r.Header.Get(s3_constants.AmzObjectLockRetainUntilDate)
+	legalHold := r.Header.Get(s3_constants.AmzObjectLockLegalHold)
+
+	lockConfig, errCode := s3a.getObjectLockConfiguration(bucket)
+	if errCode != s3err.ErrNone {
+		return errCode
+	}
+	if !lockConfig.isEnabled() {
+		if mode != "" || retainUntil != "" || legalHold != "" {
+			return s3err.ErrInvalidRequest
+		}
+		return s3err.ErrNone
+	}
+
+	if legalHold != "" && legalHold != s3.ObjectLockLegalHoldStatusOn && legalHold != s3.ObjectLockLegalHoldStatusOff {
+		return s3err.ErrInvalidRequest
+	}
+	if (mode == "") != (retainUntil == "") {
+		return s3err.ErrInvalidRequest
+	}
+
+	if mode != "" {
+		retainUntilDate, err := time.Parse(time.RFC3339, retainUntil)
+		if err != nil {
+			return s3err.ErrMalformedDate
+		}
+		if !isValidRetentionMode(mode) || !retainUntilDate.After(time.Now()) {
+			return s3err.ErrInvalidRetentionPeriod
+		}
+		r.Header.Set(s3_constants.AmzObjectLockRetainUntilDate, retainUntilDate.UTC().Format(time.RFC3339))
+		return s3err.ErrNone
+	}
+
+	if defaultMode, retainUntilDate, found := lockConfig.defaultRetainUntilDate(time.Now()); found {
+		r.Header.Set(s3_constants.AmzObjectLockMode, defaultMode)
+		r.Header.Set(s3_constants.AmzObjectLockRetainUntilDate, retainUntilDate.Format(time.RFC3339))
+	}
+	return s3err.ErrNone
+}
+
+// getObjectLockTarget finds the object version addressed by a retention or legal hold request
+func (s3a *S3ApiServer) getObjectLockTarget(bucket, object, versionId string) (dir string, entry *filer_pb.Entry, errCode s3err.ErrorCode) {
+	lockConfig, errCode := s3a.getObjectLockConfiguration(bucket)
+	if errCode != s3err.ErrNone {
+		return "", nil, errCode
+	}
+	if !lockConfig.isEnabled() {
+		return "", nil, s3err.ErrInvalidRequest
+	}
+
+	if versionId != "" {
+		dir, _, entry, errCode = s3a.getObjectVersionLocation(bucket, object, versionId)
+		if errCode != s3err.ErrNone {
+			return "", nil, errCode
+		}
+	} else {
+		current, err := s3a.getCurrentObjectEntry(bucket, object)
+		if err != nil {
+			glog.Errorf("getObjectLockTarget %s%s: %v", bucket, object, err)
+			return "", nil, s3err.ErrInternalError
+		}
+		if current == nil {
+			return "", nil, s3err.ErrNoSuchKey
+		}
+		dir, _ = s3a.genObjectDirAndName(bucket, object)
+		entry = current
+	}
+
+	if isDeleteMarker(entry) {
+		return "", nil, s3err.ErrMethodNotAllowed
+	}
+	return dir, entry, s3err.ErrNone
+}
+
+// PutObjectRetentionHandler Put object Retention
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutObjectRetention.html
+func (s3a *S3ApiServer) PutObjectRetentionHandler(w http.ResponseWriter, r *http.Request) {
+
+	bucket, object := s3_constants.GetBucketAndObject(r)
+	glog.V(3).Infof("PutObjectRetentionHandler %s %s", bucket, object)
+
+	retention := ObjectRetention{}
+	if err := xmlDecoder(r.Body, &retention, r.ContentLength); err != nil {
+		glog.Warningf("PutObjectRetentionHandler xml decode: %s", err)
+		s3err.WriteErrorResponse(w, r, s3err.ErrMalformedXML)
+		return
+	}
+	removal := retention.Mode == "" && retention.RetainUntilDate == nil
+	if !removal && (!isValidRetentionMode(retention.Mode) || retention.RetainUntilDate == nil || !retention.RetainUntilDate.After(time.Now())) {
+		s3err.WriteErrorResponse(w, r, s3err.ErrInvalidRetentionPeriod)
+		return
+	}
+
+	versionId := r.URL.Query().Get("versionId")
+	dir, entry, errCode := s3a.getObjectLockTarget(bucket, object, versionId)
+	if errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+
+	// an active retention can only be extended, unless governance mode is bypassed
+	if mode, retainUntilDate := getEntryRetention(entry); mode != "" && retainUntilDate.After(time.Now()) {
+		weakened := removal || retention.Mode != mode || retention.RetainUntilDate.Before(retainUntilDate)
+		if weakened && (mode == s3.ObjectLockRetentionModeCompliance || !s3a.canBypassGovernanceRetention(r, bucket, object)) {
+			s3err.WriteErrorResponse(w, r, s3err.ErrObjectLocked)
+			return
+		}
+	}
+
+	if entry.Extended == nil {
+		entry.Extended = make(map[string][]byte)
+	}
+	if removal {
+		delete(entry.Extended, s3_constants.AmzObjectLockMode)
+		delete(entry.Extended, s3_constants.AmzObjectLockRetainUntilDate)
+	} else {
+		entry.Extended[s3_constants.AmzObjectLockMode] = []byte(retention.Mode)
+		entry.Extended[s3_constants.AmzObjectLockRetainUntilDate] = []byte(retention.RetainUntilDate.UTC().Format(time.RFC3339))
+	}
+	if err := s3a.updateEntry(dir, entry); err != nil {
+		glog.Errorf("PutObjectRetentionHandler %s%s: %v", bucket, object, err)
+		s3err.WriteErrorResponse(w, r, s3err.ErrInternalError)
+		return
+	}
+
+	setVersionIdHeader(w, versionId)
+	writeSuccessResponseEmpty(w, r)
+}
+
+// GetObjectRetentionHandler Get object Retention
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_GetObjectRetention.html
+func (s3a *S3ApiServer) GetObjectRetentionHandler(w http.ResponseWriter, r *http.Request) {
+
+	bucket, object := s3_constants.GetBucketAndObject(r)
+	glog.V(3).Infof("GetObjectRetentionHandler %s %s", bucket, object)
+
+	_, entry, errCode := s3a.getObjectLockTarget(bucket, object, r.URL.Query().Get("versionId"))
+	if errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+
+	mode, retainUntilDate := getEntryRetention(entry)
+	if mode == "" {
+		s3err.WriteErrorResponse(w, r, s3err.ErrNoSuchObjectLockConfiguration)
+		return
+	}
+
+	writeSuccessResponseXML(w, r, ObjectRetention{
+		Mode:            mode,
+		RetainUntilDate: &retainUntilDate,
+	})
+}
+
+// PutObjectLegalHoldHandler Put object Legal Hold
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutObjectLegalHold.html
+func (s3a *S3ApiServer) PutObjectLegalHoldHandler(w http.ResponseWriter, r *http.Request) {
+
+	bucket, object := s3_constants.GetBucketAndObject(r)
+	glog.V(3).Infof("PutObjectLegalHoldHandler %s %s", bucket, object)
+
+	legalHold := ObjectLegalHold{}
+	if err := xmlDecoder(r.Body, &legalHold, r.ContentLength); err != nil {
+		glog.Warningf("PutObjectLegalHoldHandler xml decode: %s", err)
+		s3err.WriteErrorResponse(w, r, s3err.ErrMalformedXML)
+		return
+	}
+	if legalHold.Status != s3.ObjectLockLegalHoldStatusOn && legalHold.Status != s3.ObjectLockLegalHoldStatusOff {
+		s3err.WriteErrorResponse(w, r, s3err.ErrMalformedXML)
+		return
+	}
+
+	versionId := r.URL.Query().Get("versionId")
+	dir, entry, errCode := s3a.getObjectLockTarget(bucket, object, versionId)
+	if errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+
+	if entry.Extended == nil {
+		entry.Extended = make(map[string][]byte)
+	}
+	entry.Extended[s3_constants.AmzObjectLockLegalHold] = []byte(legalHold.Status)
+	if err := s3a.updateEntry(dir, entry); err != nil {
+		glog.Errorf("PutObjectLegalHoldHandler %s%s: %v", bucket, object, err)
+		s3err.WriteErrorResponse(w, r, s3err.ErrInternalError)
+		return
+	}
+
+	setVersionIdHeader(w, versionId)
+	writeSuccessResponseEmpty(w, r)
+}
+
+// GetObjectLegalHoldHandler Get object Legal Hold
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_GetObjectLegalHold.html
+func (s3a *S3ApiServer) GetObjectLegalHoldHandler(w http.ResponseWriter, r *http.Request) {
+
+	bucket, object := s3_constants.GetBucketAndObject(r)
+	glog.V(3).Infof("GetObjectLegalHoldHandler %s %s", bucket, object)
+
+	_, entry, errCode := s3a.getObjectLockTarget(bucket, object, r.URL.Query().Get("versionId"))
+	if errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+
+	if entry.Extended == nil || len(entry.Extended[s3_constants.AmzObjectLockLegalHold]) == 0 {
+		s3err.WriteErrorResponse(w, r, s3err.ErrNoSuchObjectLockConfiguration)
+		return
+	}
+
+	writeSuccessResponseXML(w, r, ObjectLegalHold{
+		Status: string(entry.Extended[s3_constants.AmzObjectLockLegalHold]),
+	})
+}
+
+// PutObjectLockConfigurationHandler Put bucket Object Lock configuration
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutObjectLockConfiguration.html
+func (s3a *S3ApiServer) PutObjectLockConfigurationHandler(w http.ResponseWriter, r *http.Request) {
+
+	bucket, _ := s3_constants.GetBucketAndObject(r)
+	glog.V(3).Infof("PutObjectLockConfigurationHandler %s", bucket)
+
+	if err := s3a.checkBucket(r, bucket); err != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, err)
+		return
+	}
+
+	lockConfig := ObjectLockConfiguration{}
+	if err := xmlDecoder(r.Body, &lockConfig, r.ContentLength); err != nil {
+		glog.Warningf("PutObjectLockConfigurationHandler xml decode: %s", err)
+		s3err.WriteErrorResponse(w, r, s3err.ErrMalformedXML)
+		return
+	}
+	if !lockConfig.validate() {
+		s3err.WriteErrorResponse(w, r, s3err.ErrMalformedXML)
+		return
+	}
+
+	bucketEntry, err := s3a.getEntry(s3a.option.BucketsPath, bucket)
+	if err != nil {
+		if err == filer_pb.ErrNotFound {
+			s3err.WriteErrorResponse(w, r, s3err.ErrNoSuchBucket)
+			return
+		}
+		s3err.WriteErrorResponse(w, r, s3err.ErrInternalError)
+		return
+	}
+	if bucketEntry.Extended == nil || string(bucketEntry.Extended[s3_constants.ExtVersioningKey]) != s3.BucketVersioningStatusEnabled {
+		s3err.WriteErrorResponse(w, r, s3err.ErrInvalidBucketState)
+		return
+	}
+
+	lockConfigBytes, err := xml.Marshal(lockConfig)
+	if err != nil {
+		s3err.WriteErrorResponse(w, r, s3err.ErrInternalError)
+		return
+	}
+	bucketEntry.Extended[s3_constants.ExtObjectLockKey] = lockConfigBytes
+	if err = s3a.updateEntry(s3a.option.BucketsPath, bucketEntry); err != nil {
+		glog.Errorf("PutObjectLockConfigurationHandler update bucket %s: %v", bucket, err)
+		s3err.WriteErrorResponse(w, r, s3err.ErrInternalError)
+		return
+	}
+	s3a.bucketRegistry.LoadBucketMetadata(bucketEntry)
+
+	writeSuccessResponseEmpty(w, r)
+}
+
+// GetObjectLockConfigurationHandler Get bucket Object Lock configuration
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_GetObjectLockConfiguration.html
+func (s3a *S3ApiServer) GetObjectLockConfigurationHandler(w http.ResponseWriter, r *http.Request) {
+
+	bucket, _ := s3_constants.GetBucketAndObject(r)
+	glog.V(3).Infof("GetObjectLockConfigurationHandler %s", bucket)
+
+	if err := s3a.checkBucket(r, bucket); err != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, err)
+		return
+	}
+
+	lockConfig, errCode := s3a.getObjectLockConfiguration(bucket)
+	if errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+	if !lockConfig.isEnabled() {
+		s3err.WriteErrorResponse(w, r, s3err.ErrObjectLockConfigurationNotFound)
+		return
+	}
+
+	writeSuccessResponseXML(w, r, lockConfig)
+}
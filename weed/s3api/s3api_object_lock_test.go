@@ -0,0 +1,118 @@
+package s3api
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/gorilla/mux"
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/pb/iam_pb"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3_constants"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3err"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckObjectLock(t *testing.T) {
+	future := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	past := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+
+	newEntry := func(kv ...string) *filer_pb.Entry {
+		entry := &filer_pb.Entry{Name: "test", Extended: map[string][]byte{}}
+		for i := 0; i+1 < len(kv); i += 2 {
+			entry.Extended[kv[i]] = []byte(kv[i+1])
+		}
+		return entry
+	}
+
+	tests := []struct {
+		name   string
+		entry  *filer_pb.Entry
+		bypass bool
+		want   s3err.ErrorCode
+	}{
+		{"no lock", newEntry(), false, s3err.ErrNone},
+		{"legal hold", newEntry(s3_constants.AmzObjectLockLegalHold, s3.ObjectLockLegalHoldStatusOn), true, s3err.ErrObjectLocked},
+		{"legal hold off", newEntry(s3_constants.AmzObjectLockLegalHold, s3.ObjectLockLegalHoldStatusOff), false, s3err.ErrNone},
+		{"compliance", newEntry(s3_constants.AmzObjectLockMode, s3.ObjectLockRetentionModeCompliance, s3_constants.AmzObjectLockRetainUntilDate, future), true, s3err.ErrObjectLocked},
+		{"governance", newEntry(s3_constants.AmzObjectLockMode, s3.ObjectLockRetentionModeGovernance, s3_constants.AmzObjectLockRetainUntilDate, future), false, s3err.ErrObjectLocked},
+		{"governance bypassed", newEntry(s3_constants.AmzObjectLockMode, s3.ObjectLockRetentionModeGovernance, s3_constants.AmzObjectLockRetainUntilDate, future), true, s3err.ErrNone},
+		{"expired", newEntry(s3_constants.AmzObjectLockMode, s3.ObjectLockRetentionModeCompliance, s3_constants.AmzObjectLockRetainUntilDate, past), false, s3err.ErrNone},
+		{"unreadable date", newEntry(s3_constants.AmzObjectLockMode, s3.ObjectLockRetentionModeCompliance, s3_constants.AmzObjectLockRetainUntilDate, "bad"), false, s3err.ErrObjectLocked},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, checkObjectLock(tt.entry, tt.bypass), tt.name)
+	}
+}
+
+func TestObjectLockConfiguration(t *testing.T) {
+	input := `<ObjectLockConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+  <ObjectLockEnabled>Enabled</ObjectLockEnabled>
+  <Rule><DefaultRetention><Mode>GOVERNANCE</Mode><Days>3</Days></DefaultRetention></Rule>
+</ObjectLockConfiguration>`
+
+	lockConfig := &ObjectLockConfiguration{}
+	assert.Nil(t, xml.Unmarshal([]byte(input), lockConfig))
+	assert.True(t, lockConfig.validate())
+	assert.True(t, lockConfig.isEnabled())
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	mode, retainUntilDate, found := lockConfig.defaultRetainUntilDate(now)
+	assert.True(t, found)
+	assert.Equal(t, s3.ObjectLockRetentionModeGovernance, mode)
+	assert.Equal(t, now.AddDate(0, 0, 3), retainUntilDate)
+
+	lockConfig.Rule.DefaultRetention.Years = 1
+	assert.False(t, lockConfig.validate(), "days and years are exclusive")
+
+	var nilConfig *ObjectLockConfiguration
+	assert.False(t, nilConfig.isEnabled())
+}
+
+func TestCanBypassGovernanceRetention(t *testing.T) {
+	s3a := &S3ApiServer{iam: &IdentityAccessManagement{}}
+	assert.Nil(t, s3a.iam.loadS3ApiConfiguration(&iam_pb.S3ApiConfiguration{
+		Identities: []*iam_pb.Identity{
+			{Name: "admin", Actions: []string{"Admin"}},
+			{Name: "writer", Actions: []string{"Write"}},
+		},
+	}))
+	policy, _ := ParseBucketPolicy("bucket", []byte(testBucketPolicy))
+	s3a.iam.bucketPolicyLookup = func(bucket string) *BucketPolicy {
+		return policy
+	}
+
+	// an anonymous request allowed by the bucket policy can not claim another identity
+	var bypass bool
+	var identityId string
+	handler := s3a.iam.Auth(func(w http.ResponseWriter, r *http.Request) {
+		bypass = s3a.canBypassGovernanceRetention(r, "bucket", "/public/a.txt")
+		identityId = r.Header.Get(s3_constants.AmzIdentityId)
+	}, s3_constants.ACTION_READ)
+	r := httptest.NewRequest(http.MethodGet, "/bucket/public/a.txt", nil)
+	r = mux.SetURLVars(r, map[string]string{"bucket": "bucket", "object": "public/a.txt"})
+	r.Header.Set(s3_constants.AmzIdentityId, "admin")
+	r.Header.Set(s3_constants.AmzIsAdmin, "true")
+	r.Header.Set(s3_constants.AmzBypassGovernanceRetention, "true")
+	handler(httptest.NewRecorder(), r)
+	assert.False(t, bypass)
+	assert.Equal(t, "", identityId)
+
+	withIdentity := func(name string) *http.Request {
+		var identity *Identity
+		for _, i := range s3a.iam.identities {
+			if i.Name == name {
+				identity = i
+			}
+		}
+		r := httptest.NewRequest(http.MethodDelete, "/bucket/a.txt", nil)
+		r.Header.Set(s3_constants.AmzBypassGovernanceRetention, "true")
+		return r.WithContext(context.WithValue(r.Context(), authenticatedIdentityKey{}, identity))
+	}
+	assert.True(t, s3a.canBypassGovernanceRetention(withIdentity("admin"), "bucket", "/a.txt"))
+	assert.False(t, s3a.canBypassGovernanceRetention(withIdentity("writer"), "bucket", "/a.txt"))
+}
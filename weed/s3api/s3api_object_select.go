@@ -0,0 +1,249 @@
+package s3api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+
+	"github.com/seaweedfs/seaweedfs/weed/filer"
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/operation"
+	"github.com/seaweedfs/seaweedfs/weed/pb"
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/pb/volume_server_pb"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3_constants"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3err"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3select"
+	"github.com/seaweedfs/seaweedfs/weed/util"
+	util_http "github.com/seaweedfs/seaweedfs/weed/util/http"
+)
+
+// SelectObjectContentHandler filters the records of a CSV, JSON or Parquet object with a SQL expression
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_SelectObjectContent.html
+func (s3a *S3ApiServer) SelectObjectContentHandler(w http.ResponseWriter, r *http.Request) {
+
+	bucket, object := s3_constants.GetBucketAndObject(r)
+	glog.V(3).Infof("SelectObjectContentHandler %s %s", bucket, object)
+
+	request := &s3select.SelectObjectContentRequest{}
+	if err := xmlDecoder(r.Body, request, r.ContentLength); err != nil {
+		glog.Warningf("SelectObjectContentHandler xml decode: %s", err)
+		s3err.WriteErrorResponse(w, r, s3err.ErrMalformedXML)
+		return
+	}
+	sel, errCode := s3select.NewSelect(request, w)
+	if errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+
+	dir, name := util.FullPath(fmt.Sprintf("%s/%s%s", s3a.option.BucketsPath, bucket, object)).DirAndName()
+	entry, err := s3a.getEntry(dir, name)
+	if err != nil || entry.IsDirectory || isDeleteMarker(entry) {
+		s3err.WriteErrorResponse(w, r, s3err.ErrNoSuchKey)
+		return
+	}
+	sse, errCode := s3a.getObjectEncryption(sseMetadata(entry.Extended), r.Header, false)
+	if errCode != s3err.ErrNone {
+		s3err.WriteErrorResponse(w, r, errCode)
+		return
+	}
+
+	// the errors from now on are sent in the event stream
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.WriteHeader(http.StatusOK)
+	objectUrl := fmt.Sprintf("http://%s%s", s3a.option.Filer.ToHttpAddress(), urlEscapeObject(dir+"/"+name))
+	err = s3a.selectObjectContent(sel, entry, objectUrl, sse)
+	if err == nil || err == s3select.ErrLimitReached {
+		err = sel.Finish()
+	}
+	if err != nil {
+		glog.Errorf("select %s%s: %v", bucket, object, err)
+		sel.WriteError("InternalError", err.Error())
+	}
+	s3err.PostLog(r, http.StatusOK, s3err.ErrNone)
+}
+
+// selectObjectContent feeds the object to the select in order.
+// The chunks that can be filtered by the volume servers are pushed down,
+// and the other ones are read together through the filer.
+func (s3a *S3ApiServer) selectObjectContent(sel *s3select.Select, entry *filer_pb.Entry, objectUrl string, sse *objectEncryption) error {
+	size := int64(filer.FileSize(entry))
+	if size == 0 {
+		return nil
+	}
+	if sel.IsParquet() {
+		return sel.ProcessParquet(&filerReaderAt{url: objectUrl, jwt: s3a.maybeGetFilerJwtAuthorizationToken(false), sse: sse}, size)
+	}
+	if !sel.CanPushdown() || sse.isGatewayEncrypted() || len(entry.Content) > 0 {
+		return s3a.selectObjectRange(sel, objectUrl, sse, 0, size)
+	}
+
+	chunks, _, err := filer.ResolveChunkManifest(filer.LookupFn(s3a), entry.GetChunks(), 0, math.MaxInt64)
+	if err != nil {
+		return err
+	}
+	sort.Slice(chunks, func(i, j int) bool {
+		return chunks[i].Offset < chunks[j].Offset
+	})
+	// the records can only be put back together if the chunks follow each other
+	var offset int64
+	for _, chunk := range chunks {
+		if chunk.Offset != offset {
+			return s3a.selectObjectRange(sel, objectUrl, sse, 0, size)
+		}
+		offset += int64(chunk.Size)
+	}
+
+	volumeLocations := make(map[string]*filer_pb.Locations)
+	var localStart int64
+	for _, chunk := range chunks {
+		if sel.Done() {
+			return nil
+		}
+		if len(chunk.CipherKey) > 0 || chunk.IsCompressed {
+			continue
+		}
+		request := sel.QueryRequest(chunk.GetFileIdString())
+		if request == nil && localStart < chunk.Offset {
+			// the chunks before may have the csv header
+			if err := s3a.selectObjectRange(sel, objectUrl, sse, localStart, chunk.Offset); err != nil {
+				return err
+			}
+			localStart = chunk.Offset
+			request = sel.QueryRequest(chunk.GetFileIdString())
+		}
+		if request == nil {
+			continue
+		}
+		stripes, err := s3a.queryVolumeServer(volumeLocations, chunk.GetFileIdString(), request)
+		if err != nil {
+			glog.V(1).Infof("query %s on volume servers, read it through the filer: %v", chunk.GetFileIdString(), err)
+			continue
+		}
+		if localStart < chunk.Offset {
+			if err := s3a.selectObjectRange(sel, objectUrl, sse, localStart, chunk.Offset); err != nil {
+				return err
+			}
+		}
+		for _, stripe := range stripes {
+			if err := sel.ProcessStripe(stripe, int64(chunk.Size)); err != nil {
+				return err
+			}
+		}
+		localStart = chunk.Offset + int64(chunk.Size)
+		if err := sel.Progress(); err != nil {
+			return err
+		}
+	}
+	if localStart < size {
+		return s3a.selectObjectRange(sel, objectUrl, sse, localStart, size)
+	}
+	return nil
+}
+
+// selectObjectRange reads the object from start to stop through the filer
+func (s3a *S3ApiServer) selectObjectRange(sel *s3select.Select, objectUrl string, sse *objectEncryption, start, stop int64) error {
+	if sel.Done() {
+		return nil
+	}
+	resp, reader, err := util_http.ReadUrlAsReaderCloser(objectUrl, s3a.maybeGetFilerJwtAuthorizationToken(false), fmt.Sprintf("bytes=%d-%d", start, stop-1))
+	if err != nil {
+		return err
+	}
+	defer util_http.CloseResponse(resp)
+	defer reader.Close()
+
+	dataReader, err := sse.decrypt(reader, start)
+	if err != nil {
+		return err
+	}
+	if err = sel.ProcessReader(dataReader, stop-start); err != nil {
+		return err
+	}
+	return sel.Progress()
+}
+
+// queryVolumeServer runs the query on one of the volume servers of the file id.
+// The stripes are returned once all are received, so that a failed query can be retried elsewhere.
+func (s3a *S3ApiServer) queryVolumeServer(volumeLocations map[string]*filer_pb.Locations, fileId string, request *volume_server_pb.QueryRequest) (stripes []*volume_server_pb.QueriedStripe, err error) {
+	vid := filer.VolumeId(fileId)
+	locations, found := volumeLocations[vid]
+	if !found {
+		err = s3a.WithFilerClient(false, func(client filer_pb.SeaweedFilerClient) error {
+			resp, err := client.LookupVolume(context.Background(), &filer_pb.LookupVolumeRequest{
+				VolumeIds: []string{vid},
+			})
+			if err != nil {
+				return err
+			}
+			locations = resp.LocationsMap[vid]
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		volumeLocations[vid] = locations
+	}
+	if locations == nil || len(locations.Locations) == 0 {
+		return nil, fmt.Errorf("failed to locate %s", fileId)
+	}
+
+	for _, location := range locations.Locations {
+		stripes = nil
+		volumeServerAddress := pb.NewServerAddressWithGrpcPort(location.Url, int(location.GrpcPort))
+		err = operation.WithVolumeServerClient(true, volumeServerAddress, s3a.option.GrpcDialOption, func(client volume_server_pb.VolumeServerClient) error {
+			stream, err := client.Query(context.Background(), request)
+			if err != nil {
+				return err
+			}
+			for {
+				stripe, err := stream.Recv()
+				if err == io.EOF {
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+				stripes = append(stripes, stripe)
+			}
+		})
+		if err == nil {
+			return stripes, nil
+		}
+		glog.V(1).Infof("query %s on %s: %v", fileId, volumeServerAddress, err)
+	}
+	return nil, err
+}
+
+// filerReaderAt reads ranges of an object through the filer, for the parquet reader
+type filerReaderAt struct {
+	url string
+	jwt string
+	sse *objectEncryption
+}
+
+func (f *filerReaderAt) ReadAt(p []byte, off int64) (n int, err error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	resp, reader, err := util_http.ReadUrlAsReaderCloser(f.url, f.jwt, fmt.Sprintf("bytes=%d-%d", off, off+int64(len(p))-1))
+	if err != nil {
+		return 0, err
+	}
+	defer util_http.CloseResponse(resp)
+	defer reader.Close()
+
+	dataReader, err := f.sse.decrypt(reader, off)
+	if err != nil {
+		return 0, err
+	}
+	n, err = io.ReadFull(dataReader, p)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	return n, err
+}
@@ -0,0 +1,515 @@
+package s3api
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/s3"
+	"golang.org/x/exp/slices"
+
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3_constants"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3err"
+	"github.com/seaweedfs/seaweedfs/weed/util"
+)
+
+// Object versioning layout
+//
+// The latest version of an object always lives at its normal location
+// <bucket>/<key>, so reads, listings and other filer frontends are not affected.
+// Every noncurrent version and every delete marker is kept under
+// <bucket>/.versions/<key>/<versionId>. The version id of an entry is stored
+// in its extended attributes, and objects written before versioning was enabled
+// are treated as the "null" version.
+// While a new version is written over a version to keep, its body is staged as
+// <bucket>/.versions/<key>/<id>.uploading, which is not listed as a version.
+
+const nullVersionId = "null"
+
+// generateVersionId creates a version id which sorts newer versions first
+func generateVersionId() string {
+	return fmt.Sprintf("%016x%08x", math.MaxInt64-time.Now().UnixNano(), rand.Uint32())
+}
+
+func isValidVersionId(versionId string) bool {
+	if versionId == nullVersionId {
+		return true
+	}
+	_, ok := versionIdTsNs(versionId)
+	return ok
+}
+
+// versionIdTsNs returns the creation time encoded in a version id generated by generateVersionId
+func versionIdTsNs(versionId string) (int64, bool) {
+	if len(versionId) != 24 {
+		return 0, false
+	}
+	inverted, err := strconv.ParseInt(versionId[:16], 16, 64)
+	if err != nil || inverted < 0 {
+		return 0, false
+	}
+	if _, err := strconv.ParseUint(versionId[16:], 16, 32); err != nil {
+		return 0, false
+	}
+	return math.MaxInt64 - inverted, true
+}
+
+func getEntryVersionId(entry *filer_pb.Entry) string {
+	if entry.Extended != nil {
+		if versionId, ok := entry.Extended[s3_constants.AmzVersionId]; ok && len(versionId) > 0 {
+			return string(versionId)
+		}
+	}
+	return nullVersionId
+}
+
+func isDeleteMarker(entry *filer_pb.Entry) bool {
+	if entry.Extended == nil {
+		return false
+	}
+	_, ok := entry.Extended[s3_constants.ExtDeleteMarkerKey]
+	return ok
+}
+
+// versionTsNs orders versions of the same object, the larger the newer
+func versionTsNs(entry *filer_pb.Entry) int64 {
+	if tsNs, ok := versionIdTsNs(getEntryVersionId(entry)); ok {
+		return tsNs
+	}
+	return entry.Attributes.Mtime * int64(time.Second)
+}
+
+func (s3a *S3ApiServer) getVersioningState(bucket string) (string, s3err.ErrorCode) {
+	bucketMetadata, errCode := s3a.bucketRegistry.GetBucketMetadata(bucket)
+	if errCode != s3err.ErrNone {
+		return "", errCode
+	}
+	return bucketMetadata.Versioning, s3err.ErrNone
+}
+
+func (s3a *S3ApiServer) genVersionsFolder(bucket, object string) string {
+	return fmt.Sprintf("%s/%s/%s%s", s3a.option.BucketsPath, bucket, s3_constants.VersionsFolder, strings.TrimSuffix(object, "/"))
+}
+
+func (s3a *S3ApiServer) genObjectDirAndName(bucket, object string) (dir, name string) {
+	return util.FullPath(fmt.Sprintf("%s/%s%s", s3a.option.BucketsPath, bucket, object)).DirAndName()
+}
+
+func (s3a *S3ApiServer) renameEntry(oldDir, oldName, newDir, newName string) error {
+	return s3a.WithFilerClient(false, func(client filer_pb.SeaweedFilerClient) error {
+		request := &filer_pb.AtomicRenameEntryRequest{
+			OldDirectory: oldDir,
+			OldName:      oldName,
+			NewDirectory: newDir,
+			NewName:      newName,
+		}
+		glog.V(1).Infof("rename entry %s/%s => %s/%s", oldDir, oldName, newDir, newName)
+		if _, err := client.AtomicRenameEntry(context.Background(), request); err != nil {
+			return fmt.Errorf("rename %s/%s => %s/%s: %v", oldDir, oldName, newDir, newName, err)
+		}
+		return nil
+	})
+}
+
+// getCurrentObjectEntry returns the latest version of the object, or nil if there is none
+func (s3a *S3ApiServer) getCurrentObjectEntry(bucket, object string) (*filer_pb.Entry, error) {
+	dir, name := s3a.genObjectDirAndName(bucket, object)
+	entry, err := s3a.getEntry(dir, name)
+	if err == filer_pb.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if entry.IsDirectory {
+		return nil, nil
+	}
+	return entry, nil
+}
+
+// listNoncurrentVersions returns the archived versions and delete markers of an object, newest first
+func (s3a *S3ApiServer) listNoncurrentVersions(bucket, object string) (versions []*filer_pb.Entry, err error) {
+	err = filer_pb.List(s3a, s3a.genVersionsFolder(bucket, object), "", func(entry *filer_pb.Entry, isLast bool) error {
+		if !entry.IsDirectory && !isStagedVersion(entry) {
+			versions = append(versions, entry)
+		}
+		return nil
+	}, "", false, math.MaxUint32)
+	if err == filer_pb.ErrNotFound {
+		err = nil
+	}
+	sortVersionsNewestFirst(versions)
+	return
+}
+
+func sortVersionsNewestFirst(versions []*filer_pb.Entry) {
+	slices.SortStableFunc(versions, func(a, b *filer_pb.Entry) int {
+		ta, tb := versionTsNs(a), versionTsNs(b)
+		switch {
+		case ta > tb:
+			return -1
+		case ta < tb:
+			return 1
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+}
+
+// archiveCurrentVersion moves the latest version of the object into the versions folder
+func (s3a *S3ApiServer) archiveCurrentVersion(bucket, object string, current *filer_pb.Entry) error {
+	versionId := getEntryVersionId(current)
+	if versionId == nullVersionId {
+		// there can only be one null version
+		if err := s3a.removeArchivedVersion(bucket, object, nullVersionId); err != nil {
+			return err
+		}
+	}
+	dir, name := s3a.genObjectDirAndName(bucket, object)
+	return s3a.renameEntry(dir, name, s3a.genVersionsFolder(bucket, object), versionId)
+}
+
+// restoreArchivedVersion moves an archived version back to the object location
+func (s3a *S3ApiServer) restoreArchivedVersion(bucket, object, versionId string) error {
+	dir, name := s3a.genObjectDirAndName(bucket, object)
+	return s3a.renameEntry(s3a.genVersionsFolder(bucket, object), versionId, dir, name)
+}
+
+func (s3a *S3ApiServer) removeArchivedVersion(bucket, object, versionId string) error {
+	versionsFolder := s3a.genVersionsFolder(bucket, object)
+	return s3a.WithFilerClient(false, func(client filer_pb.SeaweedFilerClient) error {
+		if err := doDeleteEntry(client, versionsFolder, versionId, true, false); err != nil && !strings.Contains(err.Error(), filer_pb.ErrNotFound.Error()) {
+			return err
+		}
+		s3a.deleteEmptyVersionsFolders(client, versionsFolder)
+		return nil
+	})
+}
+
+func (s3a *S3ApiServer) deleteEmptyVersionsFolders(client filer_pb.SeaweedFilerClient, versionsFolder string) {
+	directoriesWithDeletion := map[string]int{versionsFolder: 1}
+	for len(directoriesWithDeletion) > 0 {
+		directoriesWithDeletion = s3a.doDeleteEmptyDirectories(client, directoriesWithDeletion)
+	}
+}
+
+func (s3a *S3ApiServer) createDeleteMarker(bucket, object, versionId string) error {
+	return s3a.mkFile(s3a.genVersionsFolder(bucket, object), versionId, nil, func(entry *filer_pb.Entry) {
+		entry.Extended = map[string][]byte{
+			s3_constants.AmzVersionId:       []byte(versionId),
+			s3_constants.ExtDeleteMarkerKey: []byte("true"),
+		}
+	})
+}
+
+// objectVersionWrite is a new object body written as the latest version of an object
+type objectVersionWrite struct {
+	// the version id to record on the new object, empty if the bucket is not versioned
+	versionId string
+	// the full path the new object body is written to instead of the object location, if any
+	stagedPath string
+	// done makes the written object the latest version, and returns the outcome of the whole write
+	done func(writeErrCode s3err.ErrorCode) s3err.ErrorCode
+}
+
+// stagedVersionSuffix marks the new object bodies written in the versions folder,
+// until they replace the latest version
+const stagedVersionSuffix = ".uploading"
+
+func isStagedVersion(entry *filer_pb.Entry) bool {
+	return !entry.IsDirectory && strings.HasSuffix(entry.Name, stagedVersionSuffix)
+}
+
+// keepsCurrentVersion checks whether the latest version is kept as a noncurrent version when a new one is written.
+// With versioning suspended, a null latest version is simply overwritten.
+func keepsCurrentVersion(versioning string, current *filer_pb.Entry) bool {
+	return current != nil && (versioning == s3.BucketVersioningStatusEnabled || getEntryVersionId(current) != nullVersionId)
+}
+
+// prepareObjectVersion must be called before a new object body is written to bucket/object.
+// If the latest version has to be kept, the new body is written aside in the versions folder, and
+// the latest version is only archived once the new body is written completely, so the object stays
+// readable during the write and is kept if the write fails.
+// The done function of the returned write must be called with the outcome of the write.
+func (s3a *S3ApiServer) prepareObjectVersion(bucket, object string) (version *objectVersionWrite, errCode s3err.ErrorCode) {
+	version = &objectVersionWrite{
+		done: func(writeErrCode s3err.ErrorCode) s3err.ErrorCode { return writeErrCode },
+	}
+
+	versioning, errCode := s3a.getVersioningState(bucket)
+	if errCode != s3err.ErrNone || versioning == "" {
+		return version, errCode
+	}
+
+	current, err := s3a.getCurrentObjectEntry(bucket, object)
+	if err != nil {
+		glog.Errorf("prepareObjectVersion %s%s: %v", bucket, object, err)
+		return nil, s3err.ErrInternalError
+	}
+
+	version.versionId = nullVersionId
+	if versioning == s3.BucketVersioningStatusEnabled {
+		version.versionId = generateVersionId()
+	}
+
+	if current != nil && !keepsCurrentVersion(versioning, current) {
+		if errCode = checkObjectLock(current, false); errCode != s3err.ErrNone {
+			return nil, errCode
+		}
+	}
+	if !keepsCurrentVersion(versioning, current) {
+		version.done = func(writeErrCode s3err.ErrorCode) s3err.ErrorCode {
+			if writeErrCode == s3err.ErrNone && version.versionId == nullVersionId {
+				s3a.removeNullArchivedVersion(bucket, object)
+			}
+			return writeErrCode
+		}
+		return version, s3err.ErrNone
+	}
+
+	versionsFolder := s3a.genVersionsFolder(bucket, object)
+	stagedName := generateVersionId() + stagedVersionSuffix
+	version.stagedPath = versionsFolder + "/" + stagedName
+	version.done = func(writeErrCode s3err.ErrorCode) s3err.ErrorCode {
+		if writeErrCode == s3err.ErrNone {
+			writeErrCode = s3a.promoteStagedVersion(bucket, object, versioning, versionsFolder, stagedName)
+		}
+		if writeErrCode != s3err.ErrNone {
+			if err := s3a.removeArchivedVersion(bucket, object, stagedName); err != nil {
+				glog.Errorf("remove staged version %s/%s: %v", versionsFolder, stagedName, err)
+			}
+			return writeErrCode
+		}
+		if version.versionId == nullVersionId {
+			s3a.removeNullArchivedVersion(bucket, object)
+		}
+		return s3err.ErrNone
+	}
+	return version, s3err.ErrNone
+}
+
+// uploadTarget returns the filer url and the storage destination to write the new object body to,
+// given the url and the full path of the object location
+func (s3a *S3ApiServer) uploadTarget(version *objectVersionWrite, objectUrl, objectPath string) (uploadUrl, destination string) {
+	if version.stagedPath == "" {
+		return objectUrl, ""
+	}
+	return fmt.Sprintf("http://%s%s", s3a.option.Filer.ToHttpAddress(), urlEscapeObject(version.stagedPath)), objectPath
+}
+
+// promoteStagedVersion archives the latest version, and moves the staged version to the object location right after
+func (s3a *S3ApiServer) promoteStagedVersion(bucket, object, versioning, versionsFolder, stagedName string) s3err.ErrorCode {
+	// the latest version may have changed while the new body was written
+	current, err := s3a.getCurrentObjectEntry(bucket, object)
+	if err != nil {
+		glog.Errorf("promote staged version of %s%s: %v", bucket, object, err)
+		return s3err.ErrInternalError
+	}
+	archived := false
+	if keepsCurrentVersion(versioning, current) {
+		if err := s3a.archiveCurrentVersion(bucket, object, current); err != nil {
+			glog.Errorf("promote staged version of %s%s, archive: %v", bucket, object, err)
+			return s3err.ErrInternalError
+		}
+		archived = true
+	} else if current != nil {
+		if errCode := checkObjectLock(current, false); errCode != s3err.ErrNone {
+			return errCode
+		}
+	}
+	dir, name := s3a.genObjectDirAndName(bucket, object)
+	if err := s3a.renameEntry(versionsFolder, stagedName, dir, name); err != nil {
+		glog.Errorf("promote staged version of %s%s: %v", bucket, object, err)
+		if archived {
+			if err := s3a.restoreArchivedVersion(bucket, object, getEntryVersionId(current)); err != nil {
+				glog.Errorf("restore version %s of %s%s: %v", getEntryVersionId(current), bucket, object, err)
+			}
+		}
+		return s3err.ErrInternalError
+	}
+	return s3err.ErrNone
+}
+
+// removeNullArchivedVersion removes the archived null version replaced by a new null version, there can only be one
+func (s3a *S3ApiServer) removeNullArchivedVersion(bucket, object string) {
+	if err := s3a.removeArchivedVersion(bucket, object, nullVersionId); err != nil {
+		glog.Errorf("remove null version of %s%s: %v", bucket, object, err)
+	}
+}
+
+// deleteObjectWithVersioning deletes an object in a bucket with versioning configured.
+// Without a version id, the current version is kept as a noncurrent version and a delete marker
+// becomes the latest version. With a version id, that version is permanently removed,
+// unless it is protected by object lock.
+func (s3a *S3ApiServer) deleteObjectWithVersioning(bucket, object, versionId, versioning string, bypassGovernance bool) (resultVersionId string, deleteMarker bool, errCode s3err.ErrorCode) {
+	if versionId != "" {
+		return s3a.deleteObjectVersion(bucket, object, versionId, bypassGovernance)
+	}
+
+	current, err := s3a.getCurrentObjectEntry(bucket, object)
+	if err != nil {
+		glog.Errorf("deleteObjectWithVersioning %s%s: %v", bucket, object, err)
+		return "", false, s3err.ErrInternalError
+	}
+
+	markerVersionId := nullVersionId
+	if versioning == s3.BucketVersioningStatusEnabled {
+		markerVersionId = generateVersionId()
+	}
+
+	if current != nil {
+		if versioning == s3.BucketVersioningStatusEnabled || getEntryVersionId(current) != nullVersionId {
+			err = s3a.archiveCurrentVersion(bucket, object, current)
+		} else {
+			if errCode = checkObjectLock(current, bypassGovernance); errCode != s3err.ErrNone {
+				return "", false, errCode
+			}
+			dir, name := s3a.genObjectDirAndName(bucket, object)
+			err = s3a.rm(dir, name, true, false)
+		}
+		if err != nil {
+			glog.Errorf("deleteObjectWithVersioning %s%s: %v", bucket, object, err)
+			return "", false, s3err.ErrInternalError
+		}
+	}
+
+	if markerVersionId == nullVersionId {
+		if err = s3a.removeArchivedVersion(bucket, object, nullVersionId); err != nil {
+			glog.Errorf("deleteObjectWithVersioning remove null version %s%s: %v", bucket, object, err)
+			return "", false, s3err.ErrInternalError
+		}
+	}
+
+	if err = s3a.createDeleteMarker(bucket, object, markerVersionId); err != nil {
+		glog.Errorf("deleteObjectWithVersioning create delete marker %s%s: %v", bucket, object, err)
+		return "", false, s3err.ErrInternalError
+	}
+
+	return markerVersionId, true, s3err.ErrNone
+}
+
+// deleteObjectVersion permanently removes one version of an object.
+// If the latest version is removed, the next newest version becomes the latest.
+func (s3a *S3ApiServer) deleteObjectVersion(bucket, object, versionId string, bypassGovernance bool) (resultVersionId string, deleteMarker bool, errCode s3err.ErrorCode) {
+	if !isValidVersionId(versionId) {
+		return "", false, s3err.ErrNoSuchVersion
+	}
+
+	current, err := s3a.getCurrentObjectEntry(bucket, object)
+	if err != nil {
+		glog.Errorf("deleteObjectVersion %s%s: %v", bucket, object, err)
+		return "", false, s3err.ErrInternalError
+	}
+
+	if current != nil && getEntryVersionId(current) == versionId {
+		if errCode = checkObjectLock(current, bypassGovernance); errCode != s3err.ErrNone {
+			return "", false, errCode
+		}
+		dir, name := s3a.genObjectDirAndName(bucket, object)
+		if err = s3a.rm(dir, name, true, false); err != nil {
+			glog.Errorf("deleteObjectVersion %s%s: %v", bucket, object, err)
+			return "", false, s3err.ErrInternalError
+		}
+		current = nil
+	} else {
+		archived, err := s3a.getEntry(s3a.genVersionsFolder(bucket, object), versionId)
+		if err == filer_pb.ErrNotFound {
+			// deleting a version that does not exist is not an error
+			return versionId, false, s3err.ErrNone
+		}
+		if err != nil {
+			glog.Errorf("deleteObjectVersion %s%s %s: %v", bucket, object, versionId, err)
+			return "", false, s3err.ErrInternalError
+		}
+		if errCode = checkObjectLock(archived, bypassGovernance); errCode != s3err.ErrNone {
+			return "", false, errCode
+		}
+		deleteMarker = isDeleteMarker(archived)
+		if err = s3a.removeArchivedVersion(bucket, object, versionId); err != nil {
+			glog.Errorf("deleteObjectVersion %s%s %s: %v", bucket, object, versionId, err)
+			return "", false, s3err.ErrInternalError
+		}
+	}
+
+	if current == nil {
+		if err = s3a.promoteLatestVersion(bucket, object); err != nil {
+			glog.Errorf("deleteObjectVersion promote %s%s: %v", bucket, object, err)
+			return "", false, s3err.ErrInternalError
+		}
+	}
+
+	return versionId, deleteMarker, s3err.ErrNone
+}
+
+// promoteLatestVersion makes the newest noncurrent version the current object,
+// unless the newest noncurrent version is a delete marker.
+func (s3a *S3ApiServer) promoteLatestVersion(bucket, object string) error {
+	versions, err := s3a.listNoncurrentVersions(bucket, object)
+	if err != nil {
+		return err
+	}
+	if len(versions) == 0 || isDeleteMarker(versions[0]) {
+		return nil
+	}
+	if err = s3a.restoreArchivedVersion(bucket, object, versions[0].Name); err != nil {
+		return err
+	}
+	return s3a.WithFilerClient(false, func(client filer_pb.SeaweedFilerClient) error {
+		s3a.deleteEmptyVersionsFolders(client, s3a.genVersionsFolder(bucket, object))
+		return nil
+	})
+}
+
+// getObjectVersionLocation finds where the given version of an object is stored
+func (s3a *S3ApiServer) getObjectVersionLocation(bucket, object, versionId string) (dir, name string, entry *filer_pb.Entry, errCode s3err.ErrorCode) {
+	if !isValidVersionId(versionId) {
+		return "", "", nil, s3err.ErrNoSuchVersion
+	}
+
+	current, err := s3a.getCurrentObjectEntry(bucket, object)
+	if err != nil {
+		glog.Errorf("getObjectVersionLocation %s%s: %v", bucket, object, err)
+		return "", "", nil, s3err.ErrInternalError
+	}
+	if current != nil && getEntryVersionId(current) == versionId {
+		dir, name = s3a.genObjectDirAndName(bucket, object)
+		return dir, name, current, s3err.ErrNone
+	}
+
+	dir, name = s3a.genVersionsFolder(bucket, object), versionId
+	entry, err = s3a.getEntry(dir, name)
+	if err == filer_pb.ErrNotFound {
+		return "", "", nil, s3err.ErrNoSuchVersion
+	}
+	if err != nil {
+		glog.Errorf("getObjectVersionLocation %s%s %s: %v", bucket, object, versionId, err)
+		return "", "", nil, s3err.ErrInternalError
+	}
+	return dir, name, entry, s3err.ErrNone
+}
+
+func setVersionIdHeader(w http.ResponseWriter, versionId string) {
+	if versionId != "" {
+		w.Header().Set(s3_constants.AmzVersionId, versionId)
+	}
+}
+
+// toFilerVersionUrl resolves the filer url of the given version of an object
+func (s3a *S3ApiServer) toFilerVersionUrl(w http.ResponseWriter, r *http.Request, bucket, object, versionId string) (string, s3err.ErrorCode) {
+	dir, name, entry, errCode := s3a.locateObjectVersion(r, bucket, object, versionId)
+	if errCode != s3err.ErrNone {
+		return "", errCode
+	}
+	if isDeleteMarker(entry) {
+		w.Header().Set(s3_constants.AmzDeleteMarker, "true")
+		w.Header().Set(s3_constants.AmzVersionId, versionId)
+		return "", s3err.ErrMethodNotAllowed
+	}
+	return fmt.Sprintf("http://%s%s", s3a.option.Filer.ToHttpAddress(), urlEscapeObject(dir+"/"+name)), s3err.ErrNone
+}
@@ -0,0 +1,73 @@
+package s3api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3_constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateVersionId(t *testing.T) {
+	older := generateVersionId()
+	time.Sleep(time.Millisecond)
+	newer := generateVersionId()
+
+	assert.True(t, isValidVersionId(older))
+	assert.True(t, isValidVersionId(newer))
+	assert.True(t, newer < older, "newer version ids should sort first")
+
+	olderTsNs, _ := versionIdTsNs(older)
+	newerTsNs, _ := versionIdTsNs(newer)
+	assert.True(t, newerTsNs > olderTsNs)
+}
+
+func TestIsValidVersionId(t *testing.T) {
+	assert.True(t, isValidVersionId(nullVersionId))
+	assert.False(t, isValidVersionId(""))
+	assert.False(t, isValidVersionId("abc"))
+	assert.False(t, isValidVersionId("0000000000000001/../../."))
+	assert.False(t, isValidVersionId("../../../../../../../xyz"))
+}
+
+func TestSortVersionsNewestFirst(t *testing.T) {
+	first := generateVersionId()
+	time.Sleep(time.Millisecond)
+	second := generateVersionId()
+
+	versions := []*filer_pb.Entry{
+		{Name: first, Extended: map[string][]byte{s3_constants.AmzVersionId: []byte(first)}},
+		{Name: second, Extended: map[string][]byte{s3_constants.AmzVersionId: []byte(second)}},
+	}
+	sortVersionsNewestFirst(versions)
+	assert.Equal(t, second, versions[0].Name)
+	assert.Equal(t, first, versions[1].Name)
+}
+
+func TestSplitCopySourceVersionId(t *testing.T) {
+	path, versionId := splitCopySourceVersionId("/bucket/dir/key?versionId=abc")
+	assert.Equal(t, "/bucket/dir/key", path)
+	assert.Equal(t, "abc", versionId)
+
+	path, versionId = splitCopySourceVersionId("/bucket/dir/key")
+	assert.Equal(t, "/bucket/dir/key", path)
+	assert.Equal(t, "", versionId)
+}
+
+func TestKeepsCurrentVersion(t *testing.T) {
+	null := &filer_pb.Entry{Name: "k"}
+	versioned := &filer_pb.Entry{Name: "k", Extended: map[string][]byte{s3_constants.AmzVersionId: []byte(generateVersionId())}}
+
+	assert.False(t, keepsCurrentVersion(s3.BucketVersioningStatusEnabled, nil))
+	assert.True(t, keepsCurrentVersion(s3.BucketVersioningStatusEnabled, null))
+	assert.True(t, keepsCurrentVersion(s3.BucketVersioningStatusEnabled, versioned))
+	assert.False(t, keepsCurrentVersion(s3.BucketVersioningStatusSuspended, null), "a null version is overwritten")
+	assert.True(t, keepsCurrentVersion(s3.BucketVersioningStatusSuspended, versioned))
+
+	staged := &filer_pb.Entry{Name: generateVersionId() + stagedVersionSuffix}
+	assert.True(t, isStagedVersion(staged))
+	assert.False(t, isValidVersionId(staged.Name), "a staged version can not be addressed by version id")
+	assert.False(t, isStagedVersion(&filer_pb.Entry{Name: "a" + stagedVersionSuffix, IsDirectory: true}))
+}
@@ -28,7 +28,10 @@ type Rule struct {
 	Filter     Filter     `xml:"Filter,omitempty"`
 	Prefix     Prefix     `xml:"Prefix,omitempty"`
 	Expiration Expiration `xml:"Expiration,omitempty"`
-	Transition Transition `xml:"Transition,omitempty"`
+
+	Transitions                    []Transition                    `xml:"Transition,omitempty"`
+	NoncurrentVersionExpiration    *NoncurrentVersionExpiration    `xml:"NoncurrentVersionExpiration,omitempty"`
+	AbortIncompleteMultipartUpload *AbortIncompleteMultipartUpload `xml:"AbortIncompleteMultipartUpload,omitempty"`
 }
 
 // Filter - a filter for a lifecycle configuration Rule.
@@ -43,6 +46,9 @@ type Filter struct {
 
 	Tag    Tag
 	tagSet bool
+
+	ObjectSizeGreaterThan int64 `xml:"ObjectSizeGreaterThan,omitempty"`
+	ObjectSizeLessThan    int64 `xml:"ObjectSizeLessThan,omitempty"`
 }
 
 // Prefix holds the prefix xml tag in <Rule> and <Filter>
@@ -80,17 +86,54 @@ func (f Filter) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 	if err := e.EncodeToken(start); err != nil {
 		return err
 	}
-	if err := e.EncodeElement(f.Prefix, xml.StartElement{Name: xml.Name{Local: "Prefix"}}); err != nil {
-		return err
+	switch {
+	case f.andSet:
+		if err := e.EncodeElement(f.And, xml.StartElement{Name: xml.Name{Local: "And"}}); err != nil {
+			return err
+		}
+	case f.tagSet:
+		if err := e.EncodeElement(f.Tag, xml.StartElement{Name: xml.Name{Local: "Tag"}}); err != nil {
+			return err
+		}
+	default:
+		if err := e.EncodeElement(f.Prefix, xml.StartElement{Name: xml.Name{Local: "Prefix"}}); err != nil {
+			return err
+		}
+	}
+	if f.ObjectSizeGreaterThan > 0 {
+		if err := e.EncodeElement(f.ObjectSizeGreaterThan, xml.StartElement{Name: xml.Name{Local: "ObjectSizeGreaterThan"}}); err != nil {
+			return err
+		}
+	}
+	if f.ObjectSizeLessThan > 0 {
+		if err := e.EncodeElement(f.ObjectSizeLessThan, xml.StartElement{Name: xml.Name{Local: "ObjectSizeLessThan"}}); err != nil {
+			return err
+		}
 	}
 	return e.EncodeToken(xml.EndElement{Name: start.Name})
 }
 
+// UnmarshalXML decodes Filter field from an XML form, remembering which of its elements are present.
+func (f *Filter) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type filterWrapper Filter
+	wrapper := filterWrapper{}
+	if err := d.DecodeElement(&wrapper, &start); err != nil {
+		return err
+	}
+	*f = Filter(wrapper)
+	f.set = true
+	f.andSet = f.And.XMLName.Local != ""
+	f.tagSet = f.Tag.Key != ""
+	return nil
+}
+
 // And - a tag to combine a prefix and multiple tags for lifecycle configuration rule.
 type And struct {
-	XMLName xml.Name `xml:"And"`
-	Prefix  Prefix   `xml:"Prefix,omitempty"`
-	Tags    []Tag    `xml:"Tag,omitempty"`
+	XMLName               xml.Name `xml:"And"`
+	Prefix                Prefix   `xml:"Prefix,omitempty"`
+	Tags                  []Tag    `xml:"Tag,omitempty"`
+	ObjectSizeGreaterThan int64    `xml:"ObjectSizeGreaterThan,omitempty"`
+	ObjectSizeLessThan    int64    `xml:"ObjectSizeLessThan,omitempty"`
 }
 
 // Expiration - expiration actions for a rule in lifecycle configuration.
@@ -112,6 +155,18 @@ func (e Expiration) MarshalXML(enc *xml.Encoder, startElement xml.StartElement)
 	return enc.EncodeElement(expirationWrapper(e), startElement)
 }
 
+// UnmarshalXML decodes expiration field from an XML form.
+func (e *Expiration) UnmarshalXML(d *xml.Decoder, startElement xml.StartElement) error {
+	type expirationWrapper Expiration
+	wrapper := expirationWrapper{}
+	if err := d.DecodeElement(&wrapper, &startElement); err != nil {
+		return err
+	}
+	*e = Expiration(wrapper)
+	e.set = true
+	return nil
+}
+
 // ExpireDeleteMarker represents value of ExpiredObjectDeleteMarker field in Expiration XML element.
 type ExpireDeleteMarker struct {
 	val bool
@@ -126,6 +181,16 @@ func (b ExpireDeleteMarker) MarshalXML(e *xml.Encoder, startElement xml.StartEle
 	return e.EncodeElement(b.val, startElement)
 }
 
+// UnmarshalXML decodes delete marker boolean from an XML form.
+func (b *ExpireDeleteMarker) UnmarshalXML(d *xml.Decoder, startElement xml.StartElement) error {
+	var val bool
+	if err := d.DecodeElement(&val, &startElement); err != nil {
+		return err
+	}
+	*b = ExpireDeleteMarker{val: val, set: true}
+	return nil
+}
+
 // ExpirationDate is a embedded type containing time.Time to unmarshal
 // Date in Expiration
 type ExpirationDate struct {
@@ -143,10 +208,10 @@ func (eDate ExpirationDate) MarshalXML(e *xml.Encoder, startElement xml.StartEle
 
 // Transition - transition actions for a rule in lifecycle configuration.
 type Transition struct {
-	XMLName      xml.Name  `xml:"Transition"`
-	Days         int       `xml:"Days,omitempty"`
-	Date         time.Time `xml:"Date,omitempty"`
-	StorageClass string    `xml:"StorageClass,omitempty"`
+	XMLName      xml.Name       `xml:"Transition"`
+	Days         int            `xml:"Days,omitempty"`
+	Date         ExpirationDate `xml:"Date,omitempty"`
+	StorageClass string         `xml:"StorageClass,omitempty"`
 
 	set bool
 }
@@ -160,5 +225,28 @@ func (t Transition) MarshalXML(enc *xml.Encoder, start xml.StartElement) error {
 	return enc.EncodeElement(transitionWrapper(t), start)
 }
 
+// UnmarshalXML decodes transition field from an XML form.
+func (t *Transition) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type transitionWrapper Transition
+	wrapper := transitionWrapper{}
+	if err := d.DecodeElement(&wrapper, &start); err != nil {
+		return err
+	}
+	*t = Transition(wrapper)
+	t.set = true
+	return nil
+}
+
+// NoncurrentVersionExpiration - expiration of the noncurrent versions in a versioned bucket.
+type NoncurrentVersionExpiration struct {
+	NoncurrentDays          int `xml:"NoncurrentDays"`
+	NewerNoncurrentVersions int `xml:"NewerNoncurrentVersions,omitempty"`
+}
+
+// AbortIncompleteMultipartUpload - removal of the multipart uploads that are not completed in time.
+type AbortIncompleteMultipartUpload struct {
+	DaysAfterInitiation int `xml:"DaysAfterInitiation"`
+}
+
 // TransitionDays is a type alias to unmarshal Days in Transition
 type TransitionDays int
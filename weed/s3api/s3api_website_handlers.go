@@ -0,0 +1,188 @@
+package s3api
+
+import (
+	"fmt"
+	"html"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3_constants"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3err"
+)
+
+// WebsiteHandler serves the static websites of the buckets on the website listener.
+// The host name of a request is either {bucket}.{websiteDomainName}, or the bucket name itself.
+// The objects are read anonymously, so they need to be public by the bucket policy, object acl or anonymous identity.
+func (s3a *S3ApiServer) WebsiteHandler(w http.ResponseWriter, r *http.Request) {
+	bucket := s3a.websiteBucket(r.Host)
+	glog.V(3).Infof("WebsiteHandler %s %s", bucket, r.URL.Path)
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeWebsiteErrorPage(w, r, "", s3err.ErrMethodNotAllowed)
+		return
+	}
+
+	bucketMetadata, errCode := s3a.bucketRegistry.GetBucketMetadata(bucket)
+	if errCode != s3err.ErrNone {
+		writeWebsiteErrorPage(w, r, "", errCode)
+		return
+	}
+	website := bucketMetadata.Website
+	if website == nil {
+		writeWebsiteErrorPage(w, r, "", s3err.ErrNoSuchWebsiteConfiguration)
+		return
+	}
+	if redirectAll := website.RedirectAllRequestsTo; redirectAll != nil {
+		http.Redirect(w, r, websiteUrl(r, redirectAll.Protocol, redirectAll.HostName, r.URL.RequestURI()), http.StatusMovedPermanently)
+		return
+	}
+
+	key := strings.TrimPrefix(r.URL.Path, "/")
+	if rule := website.findRoutingRule(key, 0); rule != nil {
+		location, statusCode := rule.redirectLocation(r, key)
+		http.Redirect(w, r, location, statusCode)
+		return
+	}
+
+	if key == "" || strings.HasSuffix(key, "/") {
+		key += website.IndexDocument.Suffix
+	}
+	errCode = s3a.checkWebsiteObject(r, bucket, key)
+	if errCode == s3err.ErrNoSuchKey && !strings.HasSuffix(r.URL.Path, "/") {
+		// a folder requested without the trailing slash
+		if s3a.checkWebsiteObject(r, bucket, key+"/"+website.IndexDocument.Suffix) == s3err.ErrNone {
+			http.Redirect(w, r, (&url.URL{Path: "/" + key + "/"}).EscapedPath(), http.StatusFound)
+			return
+		}
+	}
+	if errCode != s3err.ErrNone {
+		s3a.writeWebsiteError(w, r, website, bucket, key, errCode)
+		return
+	}
+
+	s3a.serveWebsiteObject(w, r, bucket, key, http.StatusOK)
+}
+
+// websiteBucket resolves the host name of a website request to the bucket name
+func (s3a *S3ApiServer) websiteBucket(host string) string {
+	if hostName, _, err := net.SplitHostPort(host); err == nil {
+		host = hostName
+	}
+	for _, domainName := range strings.Split(s3a.option.WebsiteDomainName, ",") {
+		if domainName != "" && strings.HasSuffix(host, "."+domainName) {
+			return strings.TrimSuffix(host, "."+domainName)
+		}
+	}
+	return host
+}
+
+// websiteObjectRequest is the anonymous read of the object on behalf of a website request
+func websiteObjectRequest(r *http.Request, bucket, key string) *http.Request {
+	req := r.Clone(r.Context())
+	req.URL.Path = "/" + key
+	req.URL.RawPath = ""
+	req.URL.RawQuery = ""
+	req.Header.Del("Authorization")
+	for _, header := range []string{s3_constants.AmzIdentityId, s3_constants.AmzIsAdmin, s3_constants.AmzAccountId} {
+		req.Header.Del(header)
+	}
+	return mux.SetURLVars(req, map[string]string{"bucket": bucket, "object": key})
+}
+
+// checkWebsiteObject checks that the object exists, and can be read anonymously
+func (s3a *S3ApiServer) checkWebsiteObject(r *http.Request, bucket, key string) s3err.ErrorCode {
+	if s3a.iam.isEnabled() {
+		if _, errCode := s3a.iam.authRequest(websiteObjectRequest(r, bucket, key), s3_constants.ACTION_READ); errCode != s3err.ErrNone {
+			return errCode
+		}
+	}
+	entry, err := s3a.getCurrentObjectEntry(bucket, "/"+key)
+	if err != nil {
+		glog.Errorf("checkWebsiteObject %s/%s: %v", bucket, key, err)
+		return s3err.ErrInternalError
+	}
+	if entry == nil || isDeleteMarker(entry) {
+		return s3err.ErrNoSuchKey
+	}
+	return s3err.ErrNone
+}
+
+// serveWebsiteObject responds with the object content, and the status code of the error if the object is an error document
+func (s3a *S3ApiServer) serveWebsiteObject(w http.ResponseWriter, r *http.Request, bucket, key string, statusCode int) {
+	req := websiteObjectRequest(r, bucket, key)
+	if statusCode != http.StatusOK {
+		for _, header := range []string{"Range", "If-Match", "If-None-Match", "If-Modified-Since", "If-Unmodified-Since"} {
+			req.Header.Del(header)
+		}
+		w = &websiteStatusWriter{ResponseWriter: w, statusCode: statusCode}
+	}
+	if r.Method == http.MethodHead {
+		s3a.HeadObjectHandler(w, req)
+	} else {
+		s3a.GetObjectHandler(w, req)
+	}
+}
+
+// writeWebsiteError redirects by the routing rules for the error, or responds with the error document
+func (s3a *S3ApiServer) writeWebsiteError(w http.ResponseWriter, r *http.Request, website *WebsiteConfiguration, bucket, key string, errCode s3err.ErrorCode) {
+	statusCode := s3err.GetAPIError(errCode).HTTPStatusCode
+	if rule := website.findRoutingRule(key, statusCode); rule != nil {
+		location, redirectCode := rule.redirectLocation(r, key)
+		http.Redirect(w, r, location, redirectCode)
+		return
+	}
+	if website.ErrorDocument != nil && statusCode >= 400 && statusCode < 500 {
+		if s3a.checkWebsiteObject(r, bucket, website.ErrorDocument.Key) == s3err.ErrNone {
+			s3a.serveWebsiteObject(w, r, bucket, website.ErrorDocument.Key, statusCode)
+			return
+		}
+	}
+	writeWebsiteErrorPage(w, r, key, errCode)
+}
+
+// writeWebsiteErrorPage responds with a html page describing the error
+func writeWebsiteErrorPage(w http.ResponseWriter, r *http.Request, key string, errCode s3err.ErrorCode) {
+	apiErr := s3err.GetAPIError(errCode)
+	status := fmt.Sprintf("%d %s", apiErr.HTTPStatusCode, http.StatusText(apiErr.HTTPStatusCode))
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(apiErr.HTTPStatusCode)
+	if r.Method == http.MethodHead {
+		return
+	}
+	var keyItem string
+	if key != "" {
+		keyItem = fmt.Sprintf("<li>Key: %s</li>\n", html.EscapeString(key))
+	}
+	fmt.Fprintf(w, "<html>\n<head><title>%s</title></head>\n<body>\n<h1>%s</h1>\n<ul>\n<li>Code: %s</li>\n<li>Message: %s</li>\n%s</ul>\n<hr/>\n</body>\n</html>\n",
+		status, status, apiErr.Code, html.EscapeString(apiErr.Description), keyItem)
+}
+
+// websiteStatusWriter responds with the status code of the error when serving the error document
+type websiteStatusWriter struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+}
+
+func (w *websiteStatusWriter) WriteHeader(statusCode int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	if statusCode == http.StatusOK {
+		statusCode = w.statusCode
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *websiteStatusWriter) Write(data []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(data)
+}
@@ -1196,10 +1196,6 @@ type MetadataEntry struct {
 // May be one of Enabled, Disabled
 type MfaDeleteStatus string
 
-type NotificationConfiguration struct {
-	TopicConfiguration []TopicConfiguration `xml:"TopicConfiguration,omitempty"`
-}
-
 // May be one of BucketOwner, Requester
 type Payer string
 
@@ -1440,11 +1436,6 @@ type Status struct {
 // May be one of STANDARD, REDUCED_REDUNDANCY, GLACIER, UNKNOWN
 type StorageClass string
 
-type TopicConfiguration struct {
-	Topic string   `xml:"Topic"`
-	Event []string `xml:"Event"`
-}
-
 type User struct {
 }
 
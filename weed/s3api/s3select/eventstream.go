@@ -0,0 +1,96 @@
+package s3select
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"hash/crc32"
+	"io"
+	"net/http"
+)
+
+// eventStreamWriter encodes the response messages in the AWS event stream format:
+// a prelude with the total and the headers length and its crc, the headers, the payload, and the message crc.
+// https://docs.aws.amazon.com/AmazonS3/latest/API/RESTSelectObjectAppendix.html
+type eventStreamWriter struct {
+	w io.Writer
+}
+
+type eventHeader struct {
+	name  string
+	value string
+}
+
+const eventHeaderStringType = 7
+
+func (e *eventStreamWriter) writeMessage(headers []eventHeader, payload []byte) error {
+	var headersBuf bytes.Buffer
+	for _, h := range headers {
+		headersBuf.WriteByte(byte(len(h.name)))
+		headersBuf.WriteString(h.name)
+		headersBuf.WriteByte(eventHeaderStringType)
+		binary.Write(&headersBuf, binary.BigEndian, uint16(len(h.value)))
+		headersBuf.WriteString(h.value)
+	}
+
+	totalLength := 4 + 4 + 4 + headersBuf.Len() + len(payload) + 4
+	message := make([]byte, 0, totalLength)
+	message = binary.BigEndian.AppendUint32(message, uint32(totalLength))
+	message = binary.BigEndian.AppendUint32(message, uint32(headersBuf.Len()))
+	message = binary.BigEndian.AppendUint32(message, crc32.ChecksumIEEE(message))
+	message = append(message, headersBuf.Bytes()...)
+	message = append(message, payload...)
+	message = binary.BigEndian.AppendUint32(message, crc32.ChecksumIEEE(message))
+
+	if _, err := e.w.Write(message); err != nil {
+		return err
+	}
+	if flusher, ok := e.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return nil
+}
+
+func (e *eventStreamWriter) writeEvent(eventType, contentType string, payload []byte) error {
+	headers := []eventHeader{
+		{":event-type", eventType},
+	}
+	if contentType != "" {
+		headers = append(headers, eventHeader{":content-type", contentType})
+	}
+	headers = append(headers, eventHeader{":message-type", "event"})
+	return e.writeMessage(headers, payload)
+}
+
+func (e *eventStreamWriter) writeRecords(records []byte) error {
+	return e.writeEvent("Records", "application/octet-stream", records)
+}
+
+type statsDetails struct {
+	BytesScanned   int64 `xml:"BytesScanned"`
+	BytesProcessed int64 `xml:"BytesProcessed"`
+	BytesReturned  int64 `xml:"BytesReturned"`
+}
+
+func (e *eventStreamWriter) writeStats(eventType string, details statsDetails) error {
+	payload, err := xml.Marshal(struct {
+		XMLName xml.Name
+		statsDetails
+	}{XMLName: xml.Name{Local: eventType}, statsDetails: details})
+	if err != nil {
+		return err
+	}
+	return e.writeEvent(eventType, "text/xml", append([]byte(xml.Header), payload...))
+}
+
+func (e *eventStreamWriter) writeEnd() error {
+	return e.writeEvent("End", "", nil)
+}
+
+func (e *eventStreamWriter) writeError(code, message string) error {
+	return e.writeMessage([]eventHeader{
+		{":error-code", code},
+		{":error-message", message},
+		{":message-type", "error"},
+	}, nil)
+}
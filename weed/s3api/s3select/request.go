@@ -0,0 +1,67 @@
+package s3select
+
+import (
+	"encoding/xml"
+)
+
+// SelectObjectContentRequest is the body of a SelectObjectContent request.
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_SelectObjectContent.html
+type SelectObjectContentRequest struct {
+	XMLName             xml.Name            `xml:"SelectObjectContentRequest"`
+	Expression          string              `xml:"Expression"`
+	ExpressionType      string              `xml:"ExpressionType"`
+	RequestProgress     RequestProgress     `xml:"RequestProgress"`
+	InputSerialization  InputSerialization  `xml:"InputSerialization"`
+	OutputSerialization OutputSerialization `xml:"OutputSerialization"`
+	ScanRange           *ScanRange          `xml:"ScanRange"`
+}
+
+type RequestProgress struct {
+	Enabled bool `xml:"Enabled"`
+}
+
+type InputSerialization struct {
+	CompressionType string        `xml:"CompressionType"` // NONE | GZIP | BZIP2
+	CSV             *CSVInput     `xml:"CSV"`
+	JSON            *JSONInput    `xml:"JSON"`
+	Parquet         *ParquetInput `xml:"Parquet"`
+}
+
+type CSVInput struct {
+	FileHeaderInfo             string `xml:"FileHeaderInfo"`  // NONE | USE | IGNORE
+	RecordDelimiter            string `xml:"RecordDelimiter"` // Default: \n
+	FieldDelimiter             string `xml:"FieldDelimiter"`  // Default: ,
+	QuoteCharacter             string `xml:"QuoteCharacter"`  // Default: "
+	QuoteEscapeCharacter       string `xml:"QuoteEscapeCharacter"`
+	Comments                   string `xml:"Comments"` // Default: #
+	AllowQuotedRecordDelimiter bool   `xml:"AllowQuotedRecordDelimiter"`
+}
+
+type JSONInput struct {
+	Type string `xml:"Type"` // DOCUMENT | LINES
+}
+
+type ParquetInput struct {
+}
+
+type OutputSerialization struct {
+	CSV  *CSVOutput  `xml:"CSV"`
+	JSON *JSONOutput `xml:"JSON"`
+}
+
+type CSVOutput struct {
+	QuoteFields          string `xml:"QuoteFields"`     // ALWAYS | ASNEEDED
+	RecordDelimiter      string `xml:"RecordDelimiter"` // Default: \n
+	FieldDelimiter       string `xml:"FieldDelimiter"`  // Default: ,
+	QuoteCharacter       string `xml:"QuoteCharacter"`  // Default: "
+	QuoteEscapeCharacter string `xml:"QuoteEscapeCharacter"`
+}
+
+type JSONOutput struct {
+	RecordDelimiter string `xml:"RecordDelimiter"` // Default: \n
+}
+
+type ScanRange struct {
+	Start *int64 `xml:"Start"`
+	End   *int64 `xml:"End"`
+}
@@ -0,0 +1,681 @@
+package s3select
+
+import (
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	gocsv "encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/pb/volume_server_pb"
+	"github.com/seaweedfs/seaweedfs/weed/query/csv"
+	queryjson "github.com/seaweedfs/seaweedfs/weed/query/json"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3err"
+	"github.com/tidwall/gjson"
+)
+
+// ErrLimitReached stops the processing once the query LIMIT is reached
+var ErrLimitReached = errors.New("select limit reached")
+
+// the size of the records sent in one event
+const recordsEventSize = 128 * 1024
+
+type inputFormat int
+
+const (
+	inputCSV inputFormat = iota
+	inputJSONLines
+	inputJSONDocument
+	inputParquet
+)
+
+// Select evaluates a query over the records of an object, and writes the selected records as an event stream.
+//
+// The object data is given in order, either as raw bytes with Write or ProcessReader, or as the stripes
+// already filtered by the volume servers with ProcessStripe. A record cut between two writes or stripes is
+// put back together before being evaluated.
+type Select struct {
+	query       *Query
+	format      inputFormat
+	compression string
+
+	// the names of the fields in the conditions and the projections, as csv columns or gjson paths
+	conditionFields  []string
+	projectionFields []string
+
+	// csv input
+	recordDelimiter            []byte
+	fieldDelimiter             rune
+	comments                   string
+	fileHeaderInfo             string
+	allowQuotedRecordDelimiter bool
+	headerRead                 bool
+	header                     map[string]int
+	headerNames                []string
+
+	// output
+	csvOutput             *CSVOutput
+	outputRecordDelimiter string
+	events                eventStreamWriter
+	progress              bool
+
+	pending         []byte // the beginning of a record cut by the end of the data
+	buffer          []byte // the selected records not sent yet
+	returnedRecords int64
+	stats           statsDetails
+}
+
+// NewSelect validates the request, and prepares to write the selected records to w.
+func NewSelect(request *SelectObjectContentRequest, w io.Writer) (*Select, s3err.ErrorCode) {
+	if !strings.EqualFold(request.ExpressionType, "SQL") {
+		return nil, s3err.ErrInvalidExpressionType
+	}
+	if request.ScanRange != nil {
+		return nil, s3err.ErrNotImplemented
+	}
+	query, err := ParseQuery(request.Expression)
+	if err != nil {
+		glog.V(1).Infof("parse select expression %q: %v", request.Expression, err)
+		return nil, s3err.ErrParseSelectExpression
+	}
+
+	s := &Select{
+		query:    query,
+		events:   eventStreamWriter{w: w},
+		progress: request.RequestProgress.Enabled,
+	}
+	if errCode := s.setInput(request.InputSerialization); errCode != s3err.ErrNone {
+		return nil, errCode
+	}
+	if errCode := s.setOutput(request.OutputSerialization); errCode != s3err.ErrNone {
+		return nil, errCode
+	}
+
+	for _, condition := range query.Conditions {
+		field, err := s.fieldName(condition.Path)
+		if err != nil {
+			glog.V(1).Infof("select expression %q: %v", request.Expression, err)
+			return nil, s3err.ErrParseSelectExpression
+		}
+		s.conditionFields = append(s.conditionFields, field)
+	}
+	for _, projection := range query.Projections {
+		field, err := s.fieldName(projection.Path)
+		if err != nil {
+			glog.V(1).Infof("select expression %q: %v", request.Expression, err)
+			return nil, s3err.ErrParseSelectExpression
+		}
+		s.projectionFields = append(s.projectionFields, field)
+	}
+	return s, s3err.ErrNone
+}
+
+func (s *Select) setInput(input InputSerialization) s3err.ErrorCode {
+	s.compression = strings.ToUpper(input.CompressionType)
+	switch s.compression {
+	case "":
+		s.compression = "NONE"
+	case "NONE", "GZIP", "BZIP2":
+	default:
+		return s3err.ErrInvalidCompressionFormat
+	}
+
+	formats := 0
+	if input.CSV != nil {
+		formats++
+		s.format = inputCSV
+		s.recordDelimiter = []byte(stringOrDefault(input.CSV.RecordDelimiter, "\n"))
+		fieldDelimiter, err := singleRune(stringOrDefault(input.CSV.FieldDelimiter, ","))
+		if err != nil {
+			glog.V(1).Infof("select csv field delimiter: %v", err)
+			return s3err.ErrInvalidRequest
+		}
+		s.fieldDelimiter = fieldDelimiter
+		if quote := input.CSV.QuoteCharacter; quote != "" && quote != `"` {
+			return s3err.ErrInvalidRequest
+		}
+		s.comments = stringOrDefault(input.CSV.Comments, "#")
+		s.fileHeaderInfo = strings.ToUpper(stringOrDefault(input.CSV.FileHeaderInfo, "NONE"))
+		switch s.fileHeaderInfo {
+		case "NONE":
+			s.headerRead = true
+		case "USE", "IGNORE":
+		default:
+			return s3err.ErrInvalidRequest
+		}
+		s.allowQuotedRecordDelimiter = input.CSV.AllowQuotedRecordDelimiter
+		if s.allowQuotedRecordDelimiter && string(s.recordDelimiter) != "\n" {
+			return s3err.ErrInvalidRequest
+		}
+	}
+	if input.JSON != nil {
+		formats++
+		switch strings.ToUpper(stringOrDefault(input.JSON.Type, "DOCUMENT")) {
+		case "DOCUMENT":
+			s.format = inputJSONDocument
+		case "LINES":
+			s.format = inputJSONLines
+			s.recordDelimiter = []byte("\n")
+		default:
+			return s3err.ErrInvalidRequest
+		}
+	}
+	if input.Parquet != nil {
+		formats++
+		s.format = inputParquet
+		if s.compression != "NONE" {
+			return s3err.ErrInvalidCompressionFormat
+		}
+	}
+	if formats != 1 {
+		return s3err.ErrInvalidRequest
+	}
+	return s3err.ErrNone
+}
+
+func (s *Select) setOutput(output OutputSerialization) s3err.ErrorCode {
+	switch {
+	case output.CSV != nil && output.JSON == nil:
+		csvOutput := *output.CSV
+		csvOutput.FieldDelimiter = stringOrDefault(csvOutput.FieldDelimiter, ",")
+		csvOutput.QuoteCharacter = stringOrDefault(csvOutput.QuoteCharacter, `"`)
+		csvOutput.QuoteEscapeCharacter = stringOrDefault(csvOutput.QuoteEscapeCharacter, csvOutput.QuoteCharacter)
+		csvOutput.QuoteFields = strings.ToUpper(stringOrDefault(csvOutput.QuoteFields, "ASNEEDED"))
+		if csvOutput.QuoteFields != "ASNEEDED" && csvOutput.QuoteFields != "ALWAYS" {
+			return s3err.ErrInvalidRequest
+		}
+		s.csvOutput = &csvOutput
+		s.outputRecordDelimiter = stringOrDefault(csvOutput.RecordDelimiter, "\n")
+	case output.JSON != nil && output.CSV == nil:
+		s.outputRecordDelimiter = stringOrDefault(output.JSON.RecordDelimiter, "\n")
+	default:
+		return s3err.ErrInvalidRequest
+	}
+	return s3err.ErrNone
+}
+
+// fieldName converts a field path to a csv column name, or to a gjson path
+func (s *Select) fieldName(path []string) (string, error) {
+	if s.format == inputCSV {
+		if len(path) > 1 {
+			return "", fmt.Errorf("csv field %s can not be nested", strings.Join(path, "."))
+		}
+		return path[0], nil
+	}
+	escaped := make([]string, len(path))
+	for i, name := range path {
+		escaped[i] = gjson.Escape(name)
+	}
+	return strings.Join(escaped, "."), nil
+}
+
+// Done tells whether the query LIMIT has been reached, so that the rest of the object does not need to be read
+func (s *Select) Done() bool {
+	return s.query.Limit >= 0 && s.returnedRecords >= s.query.Limit
+}
+
+// IsParquet tells whether the object should be processed with ProcessParquet
+func (s *Select) IsParquet() bool {
+	return s.format == inputParquet
+}
+
+// CanPushdown tells whether the records can be split at chunk boundaries, and filtered by the volume servers
+func (s *Select) CanPushdown() bool {
+	if s.compression != "NONE" || len(s.query.Conditions) == 0 {
+		return false
+	}
+	return s.format == inputJSONLines || s.format == inputCSV && !s.allowQuotedRecordDelimiter
+}
+
+// QueryRequest builds the volume server query to filter one chunk of the object.
+// It returns nil if the chunk has to be read and processed locally,
+// e.g. when the csv header needed by the filter has not been read yet.
+func (s *Select) QueryRequest(fileId string) *volume_server_pb.QueryRequest {
+	if !s.CanPushdown() || !s.headerRead {
+		return nil
+	}
+	request := &volume_server_pb.QueryRequest{
+		FromFileIds: []string{fileId},
+		InputSerialization: &volume_server_pb.QueryRequest_InputSerialization{
+			CompressionType: "NONE",
+		},
+		OutputSerialization: &volume_server_pb.QueryRequest_OutputSerialization{
+			JsonOutput: &volume_server_pb.QueryRequest_OutputSerialization_JSONOutput{},
+		},
+		PartialRecords: true,
+	}
+	if s.format == inputCSV {
+		request.InputSerialization.CsvInput = &volume_server_pb.QueryRequest_InputSerialization_CSVInput{
+			FileHeaderInfo:  "NONE",
+			RecordDelimiter: string(s.recordDelimiter),
+			FieldDelimiter:  string(s.fieldDelimiter),
+			QuoteCharacter:  `"`,
+			Comments:        s.comments,
+		}
+	} else {
+		request.InputSerialization.JsonInput = &volume_server_pb.QueryRequest_InputSerialization_JSONInput{
+			Type: "LINES",
+		}
+	}
+
+	// the volume server evaluates one condition, and all of them are evaluated again on the returned records
+	for i, condition := range s.query.Conditions {
+		field := s.conditionFields[i]
+		if s.format == inputCSV {
+			// the volume servers do not see the header
+			index, found := csv.ColumnIndex(s.header, field)
+			if !found {
+				continue
+			}
+			field = "_" + strconv.Itoa(index+1)
+		}
+		request.Filter = &volume_server_pb.QueryRequest_Filter{
+			Field:   field,
+			Operand: condition.Op,
+			Value:   condition.Value,
+		}
+		return request
+	}
+	return nil
+}
+
+// Write processes raw object data, which continues the data given before.
+func (s *Select) Write(data []byte) (int, error) {
+	s.stats.BytesProcessed += int64(len(data))
+	return len(data), s.consume(data)
+}
+
+func (s *Select) consume(data []byte) error {
+	if s.Done() {
+		return ErrLimitReached
+	}
+	s.pending = append(s.pending, data...)
+	start := 0
+	for {
+		i := bytes.Index(s.pending[start:], s.recordDelimiter)
+		if i < 0 {
+			break
+		}
+		if err := s.processRecord(s.pending[start : start+i]); err != nil {
+			return err
+		}
+		start += i + len(s.recordDelimiter)
+	}
+	s.pending = append(s.pending[:0], s.pending[start:]...)
+	return nil
+}
+
+// ProcessStripe processes one chunk of the object filtered by a volume server.
+func (s *Select) ProcessStripe(stripe *volume_server_pb.QueriedStripe, size int64) error {
+	s.stats.BytesScanned += size
+	s.stats.BytesProcessed += size
+	if err := s.consume(stripe.Head); err != nil {
+		return err
+	}
+	// the records between the head and the tail are complete
+	records := stripe.Records
+	for len(records) > 0 {
+		record := records
+		if i := bytes.Index(records, s.recordDelimiter); i >= 0 {
+			record, records = records[:i], records[i+len(s.recordDelimiter):]
+		} else {
+			records = nil
+		}
+		if err := s.processRecord(record); err != nil {
+			return err
+		}
+	}
+	return s.consume(stripe.Tail)
+}
+
+// ProcessReader processes the object data read from reader, of size bytes as stored.
+func (s *Select) ProcessReader(reader io.Reader, size int64) error {
+	s.stats.BytesScanned += size
+
+	switch s.compression {
+	case "GZIP":
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return err
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	case "BZIP2":
+		reader = bzip2.NewReader(reader)
+	}
+
+	switch {
+	case s.format == inputJSONDocument:
+		decoder := json.NewDecoder(&processedCounter{reader, &s.stats.BytesProcessed})
+		for !s.Done() {
+			var record json.RawMessage
+			if err := decoder.Decode(&record); err == io.EOF {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			if err := s.processJson(string(record)); err != nil {
+				return err
+			}
+		}
+		return ErrLimitReached
+	case s.format == inputCSV && s.allowQuotedRecordDelimiter:
+		csvReader := gocsv.NewReader(&processedCounter{reader, &s.stats.BytesProcessed})
+		csvReader.Comma = s.fieldDelimiter
+		csvReader.LazyQuotes = true
+		csvReader.FieldsPerRecord = -1
+		if comment, size := utf8.DecodeRuneInString(s.comments); size == len(s.comments) {
+			csvReader.Comment = comment
+		}
+		for !s.Done() {
+			fields, err := csvReader.Read()
+			if err == io.EOF {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			if err := s.processCsvFields(fields); err != nil {
+				return err
+			}
+		}
+		return ErrLimitReached
+	}
+	_, err := io.Copy(s, reader)
+	return err
+}
+
+// ProcessParquet processes the rows of a parquet object, as json records.
+func (s *Select) ProcessParquet(readerAt io.ReaderAt, size int64) error {
+	s.stats.BytesScanned += size
+	s.stats.BytesProcessed += size
+
+	// the object is read over the network, so read large ranges at once
+	file, err := parquet.OpenFile(readerAt, size, parquet.ReadBufferSize(4*1024*1024), parquet.SkipBloomFilters(true))
+	if err != nil {
+		return err
+	}
+	reader := parquet.NewReader(file)
+	defer reader.Close()
+
+	columns := file.Schema().Fields()
+	var record bytes.Buffer
+	for !s.Done() {
+		row := make(map[string]interface{})
+		if err := reader.Read(&row); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		// keep the columns in the schema order
+		record.Reset()
+		record.WriteByte('{')
+		for i, column := range columns {
+			if i > 0 {
+				record.WriteByte(',')
+			}
+			name, _ := json.Marshal(column.Name())
+			value, err := json.Marshal(row[column.Name()])
+			if err != nil {
+				return err
+			}
+			record.Write(name)
+			record.WriteByte(':')
+			record.Write(value)
+		}
+		record.WriteByte('}')
+		if err := s.processJson(record.String()); err != nil {
+			return err
+		}
+	}
+	return ErrLimitReached
+}
+
+func (s *Select) processRecord(record []byte) error {
+	if s.Done() {
+		return ErrLimitReached
+	}
+	if s.format != inputCSV {
+		if len(bytes.TrimSpace(record)) == 0 {
+			return nil
+		}
+		return s.processJson(string(record))
+	}
+
+	if string(s.recordDelimiter) == "\n" {
+		record = bytes.TrimSuffix(record, []byte("\r"))
+	}
+	if len(record) == 0 || s.comments != "" && bytes.HasPrefix(record, []byte(s.comments)) {
+		return nil
+	}
+	fields, err := csv.ParseRecord(string(record), s.fieldDelimiter)
+	if err != nil {
+		return fmt.Errorf("parse csv record: %v", err)
+	}
+	return s.processCsvFields(fields)
+}
+
+// outputValue is a field of a selected record
+type outputValue struct {
+	name   string
+	text   string // the value as in csv
+	json   string // the raw json value, or empty for a csv value
+	exists bool
+}
+
+func (s *Select) processCsvFields(fields []string) error {
+	if !s.headerRead {
+		s.headerRead = true
+		if s.fileHeaderInfo == "USE" {
+			s.header = csv.ParseHeader(fields)
+			s.headerNames = append([]string(nil), fields...)
+		}
+		return nil
+	}
+
+	for i, condition := range s.query.Conditions {
+		query := csv.Query{Field: s.conditionFields[i], Op: condition.Op, Value: condition.Value}
+		if passed, _ := csv.QueryCsv(fields, s.header, nil, query); !passed {
+			return nil
+		}
+	}
+
+	var values []outputValue
+	if len(s.query.Projections) == 0 {
+		for i, field := range fields {
+			name := "_" + strconv.Itoa(i+1)
+			if i < len(s.headerNames) {
+				name = s.headerNames[i]
+			}
+			values = append(values, outputValue{name: name, text: field, exists: true})
+		}
+	}
+	for i, projection := range s.query.Projections {
+		value, found := csv.GetField(fields, s.header, s.projectionFields[i])
+		values = append(values, outputValue{name: projection.Name(), text: value, exists: found})
+	}
+	return s.writeRecord(values, "")
+}
+
+func (s *Select) processJson(record string) error {
+	for i, condition := range s.query.Conditions {
+		query := queryjson.Query{Field: s.conditionFields[i], Op: condition.Op, Value: condition.Value}
+		if passed, _ := queryjson.QueryJson(record, nil, query); !passed {
+			return nil
+		}
+	}
+
+	if len(s.query.Projections) == 0 {
+		return s.writeRecord(nil, record)
+	}
+	var values []outputValue
+	for i, projection := range s.query.Projections {
+		values = append(values, jsonOutputValue(projection.Name(), gjson.Get(record, s.projectionFields[i])))
+	}
+	return s.writeRecord(values, "")
+}
+
+func jsonOutputValue(name string, value gjson.Result) outputValue {
+	text := value.Raw
+	if value.Type == gjson.String {
+		text = value.Str
+	}
+	return outputValue{name: name, text: text, json: value.Raw, exists: value.Exists()}
+}
+
+// writeRecord writes a selected record, either the given values, or a whole json record
+func (s *Select) writeRecord(values []outputValue, jsonRecord string) error {
+	if s.csvOutput != nil {
+		if jsonRecord != "" {
+			gjson.Parse(jsonRecord).ForEach(func(key, value gjson.Result) bool {
+				values = append(values, jsonOutputValue(key.String(), value))
+				return true
+			})
+		}
+		s.buffer = s.appendCsv(s.buffer, values)
+	} else if jsonRecord != "" {
+		var compacted bytes.Buffer
+		if err := json.Compact(&compacted, []byte(jsonRecord)); err != nil {
+			return fmt.Errorf("invalid json record: %v", err)
+		}
+		s.buffer = append(s.buffer, compacted.Bytes()...)
+	} else {
+		s.buffer = appendJsonObject(s.buffer, values)
+	}
+	s.buffer = append(s.buffer, s.outputRecordDelimiter...)
+
+	s.returnedRecords++
+	if len(s.buffer) >= recordsEventSize {
+		if err := s.flushRecords(); err != nil {
+			return err
+		}
+	}
+	if s.Done() {
+		return ErrLimitReached
+	}
+	return nil
+}
+
+func appendJsonObject(buf []byte, values []outputValue) []byte {
+	buf = append(buf, '{')
+	first := true
+	for _, value := range values {
+		if !value.exists {
+			continue
+		}
+		if !first {
+			buf = append(buf, ',')
+		}
+		first = false
+		name, _ := json.Marshal(value.name)
+		buf = append(buf, name...)
+		buf = append(buf, ':')
+		if value.json != "" {
+			buf = append(buf, value.json...)
+		} else {
+			text, _ := json.Marshal(value.text)
+			buf = append(buf, text...)
+		}
+	}
+	return append(buf, '}')
+}
+
+func (s *Select) appendCsv(buf []byte, values []outputValue) []byte {
+	output := s.csvOutput
+	for i, value := range values {
+		if i > 0 {
+			buf = append(buf, output.FieldDelimiter...)
+		}
+		if output.QuoteFields == "ALWAYS" || value.text != "" && strings.ContainsAny(value.text, output.FieldDelimiter+output.QuoteCharacter+s.outputRecordDelimiter+"\r\n") {
+			buf = append(buf, output.QuoteCharacter...)
+			buf = append(buf, strings.ReplaceAll(value.text, output.QuoteCharacter, output.QuoteEscapeCharacter+output.QuoteCharacter)...)
+			buf = append(buf, output.QuoteCharacter...)
+		} else {
+			buf = append(buf, value.text...)
+		}
+	}
+	return buf
+}
+
+func (s *Select) flushRecords() error {
+	if len(s.buffer) == 0 {
+		return nil
+	}
+	s.stats.BytesReturned += int64(len(s.buffer))
+	err := s.events.writeRecords(s.buffer)
+	s.buffer = s.buffer[:0]
+	return err
+}
+
+// Progress sends the bytes processed so far, if the request asked for the progress
+func (s *Select) Progress() error {
+	if !s.progress {
+		return nil
+	}
+	if err := s.flushRecords(); err != nil {
+		return err
+	}
+	return s.events.writeStats("Progress", s.stats)
+}
+
+// Finish processes the last record, and ends the event stream with the stats.
+func (s *Select) Finish() error {
+	if len(s.pending) > 0 && !s.Done() {
+		record := s.pending
+		s.pending = nil
+		if err := s.processRecord(record); err != nil && err != ErrLimitReached {
+			return err
+		}
+	}
+	if err := s.flushRecords(); err != nil {
+		return err
+	}
+	if err := s.events.writeStats("Stats", s.stats); err != nil {
+		return err
+	}
+	return s.events.writeEnd()
+}
+
+// WriteError ends the event stream with an error, once the response has started.
+func (s *Select) WriteError(code, message string) error {
+	if err := s.flushRecords(); err != nil {
+		return err
+	}
+	return s.events.writeError(code, message)
+}
+
+type processedCounter struct {
+	reader    io.Reader
+	processed *int64
+}
+
+func (c *processedCounter) Read(p []byte) (n int, err error) {
+	n, err = c.reader.Read(p)
+	*c.processed += int64(n)
+	return
+}
+
+func stringOrDefault(s, defaultValue string) string {
+	if s == "" {
+		return defaultValue
+	}
+	return s
+}
+
+func singleRune(s string) (rune, error) {
+	r, size := utf8.DecodeRuneInString(s)
+	if size != len(s) || r == utf8.RuneError {
+		return 0, fmt.Errorf("%q is not a single character", s)
+	}
+	if strings.ContainsRune("\"\r\n", r) {
+		return 0, fmt.Errorf("%q can not be a delimiter", s)
+	}
+	return r, nil
+}
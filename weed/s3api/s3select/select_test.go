@@ -0,0 +1,203 @@
+package s3select
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/private/protocol/eventstream"
+	"github.com/parquet-go/parquet-go"
+	"github.com/seaweedfs/seaweedfs/weed/pb/volume_server_pb"
+	"github.com/seaweedfs/seaweedfs/weed/s3api/s3err"
+	"github.com/stretchr/testify/assert"
+)
+
+// readEvents decodes the event stream, and returns the records and the types of the events
+func readEvents(t *testing.T, stream []byte) (records string, eventTypes []string) {
+	decoder := eventstream.NewDecoder(bytes.NewReader(stream))
+	for {
+		message, err := decoder.Decode(nil)
+		if err == io.EOF {
+			return
+		}
+		assert.Nil(t, err)
+		if err != nil {
+			return
+		}
+		if messageType := message.Headers.Get(":message-type").String(); messageType != "event" {
+			eventTypes = append(eventTypes, messageType+":"+message.Headers.Get(":error-code").String())
+			continue
+		}
+		eventType := message.Headers.Get(":event-type").String()
+		eventTypes = append(eventTypes, eventType)
+		if eventType == "Records" {
+			records += string(message.Payload)
+		}
+	}
+}
+
+func newTestSelect(t *testing.T, expression string, input InputSerialization, output OutputSerialization) (*Select, *bytes.Buffer) {
+	var out bytes.Buffer
+	sel, errCode := NewSelect(&SelectObjectContentRequest{
+		Expression:          expression,
+		ExpressionType:      "SQL",
+		InputSerialization:  input,
+		OutputSerialization: output,
+	}, &out)
+	assert.Equal(t, s3err.ErrNone, errCode)
+	return sel, &out
+}
+
+func TestSelectCsv(t *testing.T) {
+	data := "name,city,age\n# a comment\nAlice,Paris,30\r\nBob,\"New York, NY\",25\nCarol,Rome,41"
+
+	sel, out := newTestSelect(t, `SELECT s.name, s.city FROM S3Object s WHERE s.age > 26`,
+		InputSerialization{CSV: &CSVInput{FileHeaderInfo: "USE"}},
+		OutputSerialization{CSV: &CSVOutput{}})
+	assert.Nil(t, sel.ProcessReader(strings.NewReader(data), int64(len(data))))
+	assert.Nil(t, sel.Finish())
+	records, eventTypes := readEvents(t, out.Bytes())
+	assert.Equal(t, "Alice,Paris\nCarol,Rome\n", records)
+	assert.Equal(t, []string{"Records", "Stats", "End"}, eventTypes)
+
+	sel, out = newTestSelect(t, `SELECT * FROM S3Object WHERE _2 LIKE 'New%'`,
+		InputSerialization{CSV: &CSVInput{FileHeaderInfo: "IGNORE"}},
+		OutputSerialization{JSON: &JSONOutput{}})
+	assert.Nil(t, sel.ProcessReader(strings.NewReader(data), int64(len(data))))
+	assert.Nil(t, sel.Finish())
+	records, _ = readEvents(t, out.Bytes())
+	assert.Equal(t, `{"_1":"Bob","_2":"New York, NY","_3":"25"}`+"\n", records)
+}
+
+func TestSelectJson(t *testing.T) {
+	data := `{"id":1,"user":{"name":"a"},"tags":["x"]}
+{"id":2,"user":{"name":"b"}}
+
+{"id":3}
+`
+	sel, out := newTestSelect(t, `SELECT s.user.name, s.id FROM S3Object s WHERE s.id >= 2`,
+		InputSerialization{JSON: &JSONInput{Type: "LINES"}},
+		OutputSerialization{JSON: &JSONOutput{}})
+	assert.Nil(t, sel.ProcessReader(strings.NewReader(data), int64(len(data))))
+	assert.Nil(t, sel.Finish())
+	records, _ := readEvents(t, out.Bytes())
+	assert.Equal(t, `{"name":"b","id":2}`+"\n"+`{"id":3}`+"\n", records, "missing fields are omitted")
+
+	document := `{"id": 1,
+  "name": "a"} {"id": 2, "name": "b, c"}`
+	sel, out = newTestSelect(t, `SELECT * FROM S3Object[*] s LIMIT 5`,
+		InputSerialization{JSON: &JSONInput{Type: "DOCUMENT"}},
+		OutputSerialization{CSV: &CSVOutput{QuoteFields: "ASNEEDED"}})
+	assert.Nil(t, sel.ProcessReader(strings.NewReader(document), int64(len(document))))
+	assert.Nil(t, sel.Finish())
+	records, _ = readEvents(t, out.Bytes())
+	assert.Equal(t, "1,a\n2,\"b, c\"\n", records)
+}
+
+func TestSelectCompressedLimit(t *testing.T) {
+	var compressed bytes.Buffer
+	gzipWriter := gzip.NewWriter(&compressed)
+	for i := 0; i < 1000; i++ {
+		gzipWriter.Write([]byte("{\"a\":1}\n"))
+	}
+	gzipWriter.Close()
+
+	sel, out := newTestSelect(t, `SELECT a FROM S3Object LIMIT 2`,
+		InputSerialization{CompressionType: "GZIP", JSON: &JSONInput{Type: "LINES"}},
+		OutputSerialization{JSON: &JSONOutput{RecordDelimiter: ";"}})
+	assert.False(t, sel.CanPushdown())
+	assert.Equal(t, ErrLimitReached, sel.ProcessReader(&compressed, int64(compressed.Len())))
+	assert.True(t, sel.Done())
+	assert.Nil(t, sel.Finish())
+	records, _ := readEvents(t, out.Bytes())
+	assert.Equal(t, `{"a":1};{"a":1};`, records)
+}
+
+func TestSelectStripes(t *testing.T) {
+	sel, out := newTestSelect(t, `SELECT s.name FROM S3Object s WHERE s.age > 20`,
+		InputSerialization{CSV: &CSVInput{FileHeaderInfo: "USE"}},
+		OutputSerialization{CSV: &CSVOutput{}})
+	assert.True(t, sel.CanPushdown())
+	assert.Nil(t, sel.QueryRequest("1,01"), "the header is not read yet")
+
+	// the first chunk is read locally, and ends in the middle of a record
+	chunk := "name,age\nAlice,30\nBo"
+	_, err := sel.Write([]byte(chunk))
+	assert.Nil(t, err)
+
+	request := sel.QueryRequest("1,02")
+	assert.NotNil(t, request)
+	assert.True(t, request.PartialRecords)
+	assert.Equal(t, "_2", request.Filter.Field, "the header is not known by the volume servers")
+	assert.Equal(t, "NONE", request.InputSerialization.CsvInput.FileHeaderInfo)
+
+	// the second chunk is filtered by a volume server
+	assert.Nil(t, sel.ProcessStripe(&volume_server_pb.QueriedStripe{
+		Head:    []byte("b,42\n"),
+		Records: []byte("Carol,25\n"),
+		Tail:    []byte("Dav"),
+	}, 100))
+	// a chunk without any record delimiter
+	assert.Nil(t, sel.ProcessStripe(&volume_server_pb.QueriedStripe{
+		Head: []byte("e,5"),
+	}, 100))
+	_, err = sel.Write([]byte("0\nEve,10"))
+	assert.Nil(t, err)
+	assert.Nil(t, sel.Finish())
+
+	records, _ := readEvents(t, out.Bytes())
+	assert.Equal(t, "Alice\nBob\nCarol\nDave\n", records)
+}
+
+func TestSelectParquet(t *testing.T) {
+	type row struct {
+		Name string `parquet:"name"`
+		Age  int64  `parquet:"age"`
+	}
+	var file bytes.Buffer
+	writer := parquet.NewGenericWriter[row](&file)
+	_, err := writer.Write([]row{{"a", 10}, {"b", 20}, {"c", 30}})
+	assert.Nil(t, err)
+	assert.Nil(t, writer.Close())
+
+	sel, out := newTestSelect(t, `SELECT * FROM S3Object WHERE age >= 20`,
+		InputSerialization{Parquet: &ParquetInput{}},
+		OutputSerialization{JSON: &JSONOutput{}})
+	assert.True(t, sel.IsParquet())
+	assert.Nil(t, sel.ProcessParquet(bytes.NewReader(file.Bytes()), int64(file.Len())))
+	assert.Nil(t, sel.Finish())
+	records, _ := readEvents(t, out.Bytes())
+	assert.Equal(t, `{"name":"b","age":20}`+"\n"+`{"name":"c","age":30}`+"\n", records)
+}
+
+func TestSelectErrors(t *testing.T) {
+	var out bytes.Buffer
+	for _, c := range []struct {
+		request SelectObjectContentRequest
+		errCode s3err.ErrorCode
+	}{
+		{SelectObjectContentRequest{Expression: "SELECT * FROM S3Object", ExpressionType: "XPATH"}, s3err.ErrInvalidExpressionType},
+		{SelectObjectContentRequest{Expression: "SELECT * FROM", ExpressionType: "SQL"}, s3err.ErrParseSelectExpression},
+		{SelectObjectContentRequest{Expression: "SELECT * FROM S3Object", ExpressionType: "SQL",
+			OutputSerialization: OutputSerialization{JSON: &JSONOutput{}}}, s3err.ErrInvalidRequest},
+		{SelectObjectContentRequest{Expression: "SELECT * FROM S3Object", ExpressionType: "SQL",
+			InputSerialization: InputSerialization{CompressionType: "ZIP", JSON: &JSONInput{}}}, s3err.ErrInvalidCompressionFormat},
+		{SelectObjectContentRequest{Expression: "SELECT s.a.b FROM S3Object s", ExpressionType: "SQL",
+			InputSerialization:  InputSerialization{CSV: &CSVInput{}},
+			OutputSerialization: OutputSerialization{CSV: &CSVOutput{}}}, s3err.ErrParseSelectExpression},
+	} {
+		_, errCode := NewSelect(&c.request, &out)
+		assert.Equal(t, c.errCode, errCode, c.request.Expression)
+	}
+
+	sel, _ := newTestSelect(t, `SELECT * FROM S3Object`,
+		InputSerialization{JSON: &JSONInput{Type: "LINES"}},
+		OutputSerialization{JSON: &JSONOutput{}})
+	out.Reset()
+	sel.events.w = &out
+	assert.Nil(t, sel.WriteError("InternalError", "oops"))
+	_, eventTypes := readEvents(t, out.Bytes())
+	assert.Equal(t, []string{"error:InternalError"}, eventTypes)
+}
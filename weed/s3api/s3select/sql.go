@@ -0,0 +1,454 @@
+package s3select
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Query is the supported subset of the S3 Select SQL:
+//
+//	SELECT * | field [AS alias], ... FROM S3Object [alias] [WHERE condition AND ...] [LIMIT n]
+//
+// where each condition compares a field with a literal, with =, !=, <>, <, <=, >, >=, LIKE or NOT LIKE.
+type Query struct {
+	Projections []Projection // empty to select all fields
+	Conditions  []Condition  // all of them must match
+	Limit       int64        // -1 if there is no limit
+}
+
+// Projection is one selected field, named by its alias or else by its last path element
+type Projection struct {
+	Path  []string
+	Alias string
+}
+
+func (p Projection) Name() string {
+	if p.Alias != "" {
+		return p.Alias
+	}
+	return p.Path[len(p.Path)-1]
+}
+
+// Condition compares a field with a literal value.
+// The operators are the ones of the volume server query filter, with "%" and "!%" for LIKE and NOT LIKE,
+// whose value is then a glob pattern.
+type Condition struct {
+	Path  []string
+	Op    string
+	Value string
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdentifier
+	tokenQuotedIdentifier
+	tokenString
+	tokenNumber
+	tokenSymbol
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+}
+
+func (t token) is(keyword string) bool {
+	return t.kind == tokenIdentifier && strings.EqualFold(t.value, keyword)
+}
+
+func (t token) isSymbol(symbol string) bool {
+	return t.kind == tokenSymbol && t.value == symbol
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q", t.value)
+}
+
+func tokenize(expression string) (tokens []token, err error) {
+	s := []rune(expression)
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case unicode.IsLetter(c) || c == '_':
+			j := i + 1
+			for j < len(s) && (unicode.IsLetter(s[j]) || unicode.IsDigit(s[j]) || s[j] == '_') {
+				j++
+			}
+			tokens = append(tokens, token{tokenIdentifier, string(s[i:j])})
+			i = j
+		case unicode.IsDigit(c) || (c == '-' || c == '+') && i+1 < len(s) && unicode.IsDigit(s[i+1]):
+			j := i + 1
+			for j < len(s) && (unicode.IsDigit(s[j]) || s[j] == '.' || s[j] == 'e' || s[j] == 'E' ||
+				(s[j] == '-' || s[j] == '+') && (s[j-1] == 'e' || s[j-1] == 'E')) {
+				j++
+			}
+			number := string(s[i:j])
+			if _, err := strconv.ParseFloat(number, 64); err != nil {
+				return nil, fmt.Errorf("invalid number %s", number)
+			}
+			tokens = append(tokens, token{tokenNumber, strings.TrimPrefix(number, "+")})
+			i = j
+		case c == '\'' || c == '"':
+			var value []rune
+			j := i + 1
+			for ; j < len(s); j++ {
+				if s[j] == c {
+					if j+1 < len(s) && s[j+1] == c {
+						// a doubled quote is an escaped quote
+						value = append(value, c)
+						j++
+						continue
+					}
+					break
+				}
+				value = append(value, s[j])
+			}
+			if j >= len(s) {
+				return nil, fmt.Errorf("unterminated quote at %d", i)
+			}
+			kind := tokenString
+			if c == '"' {
+				kind = tokenQuotedIdentifier
+			}
+			tokens = append(tokens, token{kind, string(value)})
+			i = j + 1
+		case strings.ContainsRune("*,.()[];", c):
+			tokens = append(tokens, token{tokenSymbol, string(c)})
+			i++
+		case strings.ContainsRune("=!<>", c):
+			j := i + 1
+			if j < len(s) && (s[j] == '=' || c == '<' && s[j] == '>') {
+				j++
+			}
+			op := string(s[i:j])
+			if op == "!" {
+				return nil, fmt.Errorf("unexpected character %q", c)
+			}
+			tokens = append(tokens, token{tokenSymbol, op})
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected character %q", c)
+		}
+	}
+	return tokens, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return token{kind: tokenEOF}
+}
+
+func (p *parser) next() token {
+	t := p.peek()
+	if p.pos < len(p.tokens) {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) expectKeyword(keyword string) error {
+	if t := p.next(); !t.is(keyword) {
+		return fmt.Errorf("expected %s, found %v", keyword, t)
+	}
+	return nil
+}
+
+func (p *parser) expectSymbol(symbol string) error {
+	if t := p.next(); !t.isSymbol(symbol) {
+		return fmt.Errorf("expected %q, found %v", symbol, t)
+	}
+	return nil
+}
+
+var reservedKeywords = map[string]bool{
+	"SELECT": true, "FROM": true, "WHERE": true, "AND": true, "OR": true, "NOT": true,
+	"LIKE": true, "LIMIT": true, "AS": true, "CAST": true,
+}
+
+func (p *parser) identifier() (string, error) {
+	t := p.next()
+	switch {
+	case t.kind == tokenQuotedIdentifier:
+		return t.value, nil
+	case t.kind == tokenIdentifier && !reservedKeywords[strings.ToUpper(t.value)]:
+		return t.value, nil
+	}
+	return "", fmt.Errorf("expected a field name, found %v", t)
+}
+
+// path parses a field reference, like s.name or "a.b".c, where a trailing * is returned as an empty element
+func (p *parser) path() (path []string, err error) {
+	if p.peek().is("CAST") {
+		// values are compared as numbers whenever both sides are numbers, so the cast itself has no effect
+		p.next()
+		if err = p.expectSymbol("("); err != nil {
+			return nil, err
+		}
+		if path, err = p.path(); err != nil {
+			return nil, err
+		}
+		if err = p.expectKeyword("AS"); err != nil {
+			return nil, err
+		}
+		if _, err = p.identifier(); err != nil {
+			return nil, err
+		}
+		return path, p.expectSymbol(")")
+	}
+	for {
+		if len(path) > 0 && p.peek().isSymbol("*") {
+			p.next()
+			return append(path, ""), nil
+		}
+		name, err := p.identifier()
+		if err != nil {
+			return nil, err
+		}
+		path = append(path, name)
+		if !p.peek().isSymbol(".") {
+			return path, nil
+		}
+		p.next()
+	}
+}
+
+func (p *parser) literal() (string, bool) {
+	t := p.peek()
+	switch {
+	case t.kind == tokenString, t.kind == tokenNumber:
+	case t.is("TRUE"), t.is("FALSE"):
+		t.value = strings.ToLower(t.value)
+	default:
+		return "", false
+	}
+	p.next()
+	return t.value, true
+}
+
+var flippedOps = map[string]string{"=": "=", "!=": "!=", "<": ">", "<=": ">=", ">": "<", ">=": "<="}
+
+func (p *parser) condition() (condition Condition, err error) {
+	if value, ok := p.literal(); ok {
+		// literal op field
+		op := p.next()
+		flipped, found := flippedOps[strings.Replace(op.value, "<>", "!=", 1)]
+		if op.kind != tokenSymbol || !found {
+			return condition, fmt.Errorf("expected a comparison operator, found %v", op)
+		}
+		if condition.Path, err = p.path(); err != nil {
+			return condition, err
+		}
+		condition.Op, condition.Value = flipped, value
+		return condition, nil
+	}
+
+	if condition.Path, err = p.path(); err != nil {
+		return condition, err
+	}
+	op := p.next()
+	switch {
+	case op.is("LIKE"):
+		condition.Op = "%"
+	case op.is("NOT"):
+		if err = p.expectKeyword("LIKE"); err != nil {
+			return condition, err
+		}
+		condition.Op = "!%"
+	case op.kind == tokenSymbol:
+		condition.Op = strings.Replace(op.value, "<>", "!=", 1)
+		if _, found := flippedOps[condition.Op]; !found {
+			return condition, fmt.Errorf("expected a comparison operator, found %v", op)
+		}
+	default:
+		return condition, fmt.Errorf("expected a comparison operator, found %v", op)
+	}
+
+	value, ok := p.literal()
+	if !ok {
+		return condition, fmt.Errorf("expected a literal value, found %v", p.peek())
+	}
+	if condition.Op == "%" || condition.Op == "!%" {
+		if t := p.tokens[p.pos-1]; t.kind != tokenString {
+			return condition, fmt.Errorf("expected a string pattern, found %v", t)
+		}
+		value = likeToGlob(value)
+	}
+	condition.Value = value
+	return condition, nil
+}
+
+// likeToGlob converts the % and _ wildcards of a LIKE pattern to * and ?
+func likeToGlob(pattern string) string {
+	var b strings.Builder
+	for _, c := range pattern {
+		switch c {
+		case '%':
+			b.WriteRune('*')
+		case '_':
+			b.WriteRune('?')
+		case '*', '?', '\\':
+			b.WriteRune('\\')
+			b.WriteRune(c)
+		default:
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
+
+// ParseQuery parses a S3 Select SQL expression.
+func ParseQuery(expression string) (*Query, error) {
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	query := &Query{Limit: -1}
+
+	if err = p.expectKeyword("SELECT"); err != nil {
+		return nil, err
+	}
+	selectAll := false
+	if p.peek().isSymbol("*") {
+		p.next()
+		selectAll = true
+	} else {
+		for {
+			path, err := p.path()
+			if err != nil {
+				return nil, err
+			}
+			projection := Projection{Path: path}
+			if p.peek().is("AS") {
+				p.next()
+				if projection.Alias, err = p.identifier(); err != nil {
+					return nil, err
+				}
+			} else if t := p.peek(); t.kind == tokenQuotedIdentifier || t.kind == tokenIdentifier && !t.is("FROM") {
+				projection.Alias, _ = p.identifier()
+			}
+			query.Projections = append(query.Projections, projection)
+			if !p.peek().isSymbol(",") {
+				break
+			}
+			p.next()
+		}
+	}
+
+	if err = p.expectKeyword("FROM"); err != nil {
+		return nil, err
+	}
+	if err = p.expectKeyword("S3Object"); err != nil {
+		return nil, err
+	}
+	if p.peek().isSymbol("[") {
+		// S3Object[*], the records of a json document
+		p.next()
+		if err = p.expectSymbol("*"); err != nil {
+			return nil, err
+		}
+		if err = p.expectSymbol("]"); err != nil {
+			return nil, err
+		}
+	}
+	alias := ""
+	if p.peek().is("AS") {
+		p.next()
+		if alias, err = p.identifier(); err != nil {
+			return nil, err
+		}
+	} else if t := p.peek(); t.kind == tokenQuotedIdentifier || t.kind == tokenIdentifier && !reservedKeywords[strings.ToUpper(t.value)] {
+		alias, _ = p.identifier()
+	}
+
+	if p.peek().is("WHERE") {
+		p.next()
+		for {
+			condition, err := p.condition()
+			if err != nil {
+				return nil, err
+			}
+			query.Conditions = append(query.Conditions, condition)
+			if t := p.peek(); t.is("OR") {
+				return nil, fmt.Errorf("OR is not supported")
+			} else if !t.is("AND") {
+				break
+			}
+			p.next()
+		}
+	}
+
+	if p.peek().is("LIMIT") {
+		p.next()
+		t := p.next()
+		if t.kind != tokenNumber {
+			return nil, fmt.Errorf("expected a number, found %v", t)
+		}
+		if query.Limit, err = strconv.ParseInt(t.value, 10, 64); err != nil || query.Limit < 0 {
+			return nil, fmt.Errorf("invalid limit %s", t.value)
+		}
+	}
+
+	if p.peek().isSymbol(";") {
+		p.next()
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %v", t)
+	}
+
+	// remove the table alias from the field paths
+	for i := range query.Projections {
+		if query.Projections[i].Path, err = trimAlias(query.Projections[i].Path, alias); err != nil {
+			return nil, err
+		}
+		if query.Projections[i].Path[len(query.Projections[i].Path)-1] == "" {
+			if len(query.Projections) > 1 || len(query.Projections[i].Path) > 1 {
+				return nil, fmt.Errorf("* can not be combined with other fields")
+			}
+			selectAll = true
+		}
+	}
+	if selectAll {
+		query.Projections = nil
+	}
+	for i := range query.Conditions {
+		if query.Conditions[i].Path, err = trimAlias(query.Conditions[i].Path, alias); err != nil {
+			return nil, err
+		}
+		if last := query.Conditions[i].Path[len(query.Conditions[i].Path)-1]; last == "" {
+			return nil, fmt.Errorf("* can not be compared")
+		}
+	}
+	return query, nil
+}
+
+func trimAlias(path []string, alias string) ([]string, error) {
+	if len(path) > 1 && (strings.EqualFold(path[0], "S3Object") || alias != "" && strings.EqualFold(path[0], alias)) {
+		path = path[1:]
+	}
+	if len(path) == 1 && path[0] == "" {
+		return path, nil
+	}
+	for _, name := range path[:len(path)-1] {
+		if name == "" {
+			return nil, fmt.Errorf("invalid field %s", strings.Join(path, "."))
+		}
+	}
+	return path, nil
+}
@@ -0,0 +1,61 @@
+package s3select
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseQuery(t *testing.T) {
+	query, err := ParseQuery(`SELECT s.name, s."user"."id" AS uid FROM S3Object s WHERE s.age >= 21 AND 'NY' = s.city AND s.name NOT LIKE 'J_n%' LIMIT 10;`)
+	assert.Nil(t, err)
+	assert.Equal(t, []Projection{
+		{Path: []string{"name"}},
+		{Path: []string{"user", "id"}, Alias: "uid"},
+	}, query.Projections)
+	assert.Equal(t, "name", query.Projections[0].Name())
+	assert.Equal(t, "uid", query.Projections[1].Name())
+	assert.Equal(t, []Condition{
+		{Path: []string{"age"}, Op: ">=", Value: "21"},
+		{Path: []string{"city"}, Op: "=", Value: "NY"},
+		{Path: []string{"name"}, Op: "!%", Value: "J?n*"},
+	}, query.Conditions)
+	assert.Equal(t, int64(10), query.Limit)
+
+	query, err = ParseQuery(`select * from s3object`)
+	assert.Nil(t, err)
+	assert.Nil(t, query.Projections)
+	assert.Nil(t, query.Conditions)
+	assert.Equal(t, int64(-1), query.Limit)
+
+	query, err = ParseQuery(`SELECT s.* FROM S3Object[*] AS s WHERE CAST(s._3 AS INT) < -1.5e2 AND s._1 <> 'it''s' AND 1 < _2`)
+	assert.Nil(t, err)
+	assert.Nil(t, query.Projections)
+	assert.Equal(t, []Condition{
+		{Path: []string{"_3"}, Op: "<", Value: "-1.5e2"},
+		{Path: []string{"_1"}, Op: "!=", Value: "it's"},
+		{Path: []string{"_2"}, Op: ">", Value: "1"},
+	}, query.Conditions)
+
+	query, err = ParseQuery(`SELECT S3Object.a FROM S3Object WHERE a = TRUE`)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a"}, query.Projections[0].Path)
+	assert.Equal(t, "true", query.Conditions[0].Value)
+
+	for _, expression := range []string{
+		``,
+		`SELECT`,
+		`SELECT * FROM table`,
+		`SELECT * FROM S3Object WHERE a = 1 OR b = 2`,
+		`SELECT * FROM S3Object WHERE a = b`,
+		`SELECT * FROM S3Object WHERE a LIKE 1`,
+		`SELECT *, a FROM S3Object`,
+		`SELECT count(*) FROM S3Object`,
+		`SELECT * FROM S3Object LIMIT x`,
+		`SELECT * FROM S3Object WHERE a = 'unterminated`,
+		`SELECT * FROM S3Object extra tokens`,
+	} {
+		_, err = ParseQuery(expression)
+		assert.NotNil(t, err, expression)
+	}
+}
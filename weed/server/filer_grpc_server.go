@@ -189,6 +189,18 @@ func (fs *FilerServer) UpdateEntry(ctx context.Context, req *filer_pb.UpdateEntr
 		return &filer_pb.UpdateEntryResponse{}, err
 	}
 
+	if !req.IsFromOtherCluster {
+		if err = fs.filer.CheckSnapshotWritable(ctx, newEntry.FullPath); err != nil {
+			return &filer_pb.UpdateEntryResponse{}, err
+		}
+		if err = fs.filer.CheckTrashWritable(ctx, newEntry.FullPath); err != nil {
+			return &filer_pb.UpdateEntryResponse{}, err
+		}
+		if err = fs.filer.CheckDirectoryQuota(ctx, entry, newEntry); err != nil {
+			return &filer_pb.UpdateEntryResponse{}, err
+		}
+	}
+
 	if err = fs.filer.UpdateEntry(ctx, entry, newEntry); err == nil {
 		fs.filer.DeleteChunksNotRecursive(garbage)
 
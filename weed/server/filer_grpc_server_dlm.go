@@ -9,6 +9,7 @@ import (
 	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"time"
 )
 
@@ -118,6 +119,9 @@ func (fs *FilerServer) TransferLocks(ctx context.Context, req *filer_pb.Transfer
 	for _, lock := range req.Locks {
 		fs.filer.Dlm.InsertLock(lock.Name, lock.ExpiredAtNs, lock.RenewToken, lock.Owner)
 	}
+	for _, rangeLocks := range req.RangeLocks {
+		fs.filer.Dlm.InsertRangeLocks(rangeLocks.Name, fromPbRangeLocks(rangeLocks.Locks))
+	}
 
 	return &filer_pb.TransferLocksResponse{}, nil
 
@@ -125,9 +129,6 @@ func (fs *FilerServer) TransferLocks(ctx context.Context, req *filer_pb.Transfer
 
 func (fs *FilerServer) OnDlmChangeSnapshot(snapshot []pb.ServerAddress) {
 	locks := fs.filer.Dlm.SelectNotOwnedLocks(snapshot)
-	if len(locks) == 0 {
-		return
-	}
 
 	for _, lock := range locks {
 		server := fs.filer.Dlm.CalculateTargetServer(lock.Key, snapshot)
@@ -149,4 +150,104 @@ func (fs *FilerServer) OnDlmChangeSnapshot(snapshot []pb.ServerAddress) {
 		}
 	}
 
+	for key, rangeLocks := range fs.filer.Dlm.SelectNotOwnedRangeLocks(snapshot) {
+		server := fs.filer.Dlm.CalculateTargetServer(key, snapshot)
+		if err := pb.WithFilerClient(false, 0, server, fs.grpcDialOption, func(client filer_pb.SeaweedFilerClient) error {
+			_, err := client.TransferLocks(context.Background(), &filer_pb.TransferLocksRequest{
+				RangeLocks: []*filer_pb.RangeLocks{
+					{
+						Name:  key,
+						Locks: toPbRangeLocks(rangeLocks),
+					},
+				},
+			})
+			return err
+		}); err != nil {
+			// the clients re-create the lost range locks when renewing them
+			glog.Errorf("transfer range locks %v to %v: %v", key, server, err)
+		}
+	}
+
+}
+
+// DistributedRangeLock is a grpc handler to lock, unlock, test or renew the byte range locks of a file
+func (fs *FilerServer) DistributedRangeLock(ctx context.Context, req *filer_pb.RangeLockRequest) (resp *filer_pb.RangeLockResponse, err error) {
+
+	resp = &filer_pb.RangeLockResponse{}
+
+	var movedTo pb.ServerAddress
+	var conflict *lock_manager.RangeLock
+	expiredAtNs := time.Now().Add(time.Duration(req.SecondsToLock) * time.Second).UnixNano()
+	switch {
+	case req.IsRenew:
+		conflict, movedTo, err = fs.filer.Dlm.RenewRangeLocks(req.Name, req.Client, fromPbRangeLocks(req.HeldLocks), expiredAtNs)
+	case req.Lock == nil:
+		err = fmt.Errorf("missing range lock")
+	case req.IsUnlock:
+		movedTo, err = fs.filer.Dlm.RangeUnlock(req.Name, fromPbRangeLock(req.Lock, req.Client, 0))
+	default:
+		conflict, movedTo, err = fs.filer.Dlm.RangeLock(req.Name, fromPbRangeLock(req.Lock, req.Client, expiredAtNs), req.IsTest)
+	}
+	glog.V(3).Infof("range lock %s %v renew=%v unlock=%v test=%v, isMoved=%v %v", req.Name, req.Lock, req.IsRenew, req.IsUnlock, req.IsTest, req.IsMoved, movedTo)
+	if movedTo != "" && movedTo != fs.option.Host && !req.IsMoved {
+		err = pb.WithFilerClient(false, 0, movedTo, fs.grpcDialOption, func(client filer_pb.SeaweedFilerClient) error {
+			movedReq := proto.Clone(req).(*filer_pb.RangeLockRequest)
+			movedReq.IsMoved = true
+			secondResp, err := client.DistributedRangeLock(context.Background(), movedReq)
+			if err == nil {
+				resp.Conflict = secondResp.Conflict
+				resp.Error = secondResp.Error
+			}
+			return err
+		})
+	} else if conflict != nil {
+		resp.Conflict = toPbRangeLock(conflict)
+	}
+
+	if err != nil {
+		resp.Error = fmt.Sprintf("%v", err)
+	}
+	if movedTo != "" {
+		resp.LockHostMovedTo = string(movedTo)
+	}
+
+	return resp, nil
+}
+
+func fromPbRangeLock(lock *filer_pb.RangeLock, client string, expiredAtNs int64) *lock_manager.RangeLock {
+	return &lock_manager.RangeLock{
+		Start:       lock.Start,
+		End:         lock.End,
+		IsExclusive: lock.IsExclusive,
+		Client:      client,
+		Owner:       lock.Owner,
+		Pid:         lock.Pid,
+		ExpiredAtNs: expiredAtNs,
+	}
+}
+
+func fromPbRangeLocks(locks []*filer_pb.RangeLock) (rangeLocks []*lock_manager.RangeLock) {
+	for _, lock := range locks {
+		rangeLocks = append(rangeLocks, fromPbRangeLock(lock, lock.Client, lock.ExpiredAtNs))
+	}
+	return
+}
+
+func toPbRangeLock(lock *lock_manager.RangeLock) *filer_pb.RangeLock {
+	return &filer_pb.RangeLock{
+		Start:       lock.Start,
+		End:         lock.End,
+		IsExclusive: lock.IsExclusive,
+		Client:      lock.Client,
+		Owner:       lock.Owner,
+		Pid:         lock.Pid,
+		ExpiredAtNs: lock.ExpiredAtNs,
+	}
+}
+
+func toPbRangeLocks(locks []*lock_manager.RangeLock) (pbLocks []*filer_pb.RangeLock) {
+	for _, lock := range locks {
+		pbLocks = append(pbLocks, toPbRangeLock(lock))
+	}
+	return
 }
@@ -0,0 +1,15 @@
+package weed_server
+
+import (
+	"context"
+
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+)
+
+func (fs *FilerServer) ListDirectoryQuotas(ctx context.Context, req *filer_pb.ListDirectoryQuotasRequest) (*filer_pb.ListDirectoryQuotasResponse, error) {
+
+	return &filer_pb.ListDirectoryQuotasResponse{
+		Quotas: fs.filer.DirectoryQuotas.List(),
+	}, nil
+
+}
@@ -34,6 +34,17 @@ func (fs *FilerServer) AtomicRenameEntry(ctx context.Context, req *filer_pb.Atom
 		return nil, fmt.Errorf("%s/%s not found: %v", req.OldDirectory, req.OldName, err)
 	}
 
+	if err = fs.filer.CheckSnapshotMove(ctx, oldEntry.FullPath, newParent.Child(req.NewName)); err != nil {
+		fs.filer.RollbackTransaction(ctx)
+		return nil, err
+	}
+	if err = fs.filer.CheckMoveQuota(ctx, oldEntry, newParent.Child(req.NewName)); err != nil {
+		fs.filer.RollbackTransaction(ctx)
+		return nil, err
+	}
+	// the moved entries are checked as a whole
+	ctx = context.WithValue(ctx, "OP", "MV")
+
 	moveErr := fs.moveEntry(ctx, nil, oldParent, oldEntry, newParent, req.NewName, req.Signatures)
 	if moveErr != nil {
 		fs.filer.RollbackTransaction(ctx)
@@ -72,6 +83,17 @@ func (fs *FilerServer) StreamRenameEntry(req *filer_pb.StreamRenameEntryRequest,
 		return fmt.Errorf("%s/%s not found: %v", req.OldDirectory, req.OldName, err)
 	}
 
+	if err = fs.filer.CheckSnapshotMove(ctx, oldEntry.FullPath, newParent.Child(req.NewName)); err != nil {
+		fs.filer.RollbackTransaction(ctx)
+		return err
+	}
+	if err = fs.filer.CheckMoveQuota(ctx, oldEntry, newParent.Child(req.NewName)); err != nil {
+		fs.filer.RollbackTransaction(ctx)
+		return err
+	}
+	// the moved entries are checked as a whole
+	ctx = context.WithValue(ctx, "OP", "MV")
+
 	if oldEntry.IsDirectory() {
 		// follow https://pubs.opengroup.org/onlinepubs/000095399/functions/rename.html
 		targetDir := newParent.Child(req.NewName)
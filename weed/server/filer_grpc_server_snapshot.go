@@ -0,0 +1,37 @@
+package weed_server
+
+import (
+	"context"
+
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/util"
+)
+
+func (fs *FilerServer) CreateSnapshot(ctx context.Context, req *filer_pb.CreateSnapshotRequest) (*filer_pb.CreateSnapshotResponse, error) {
+
+	glog.V(1).Infof("CreateSnapshot %v", req)
+
+	snapshotDir, count, err := fs.filer.CreateSnapshot(ctx, util.FullPath(req.Directory), req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &filer_pb.CreateSnapshotResponse{
+		SnapshotDirectory: string(snapshotDir),
+		EntryCount:        count,
+	}, nil
+
+}
+
+func (fs *FilerServer) DeleteSnapshot(ctx context.Context, req *filer_pb.DeleteSnapshotRequest) (*filer_pb.DeleteSnapshotResponse, error) {
+
+	glog.V(1).Infof("DeleteSnapshot %v", req)
+
+	if err := fs.filer.DeleteSnapshot(ctx, util.FullPath(req.Directory), req.Name); err != nil {
+		return nil, err
+	}
+
+	return &filer_pb.DeleteSnapshotResponse{}, nil
+
+}
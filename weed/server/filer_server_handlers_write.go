@@ -88,6 +88,7 @@ func (fs *FilerServer) PostHandler(w http.ResponseWriter, r *http.Request, conte
 		query.Get("rack"),
 		query.Get("dataNode"),
 		query.Get("saveInside"),
+		query.Get("cipher"),
 	)
 	if err != nil {
 		if err == ErrReadOnly {
@@ -266,7 +267,7 @@ func (fs *FilerServer) detectStorageOption(requestURI, qCollection, qReplication
 	}, nil
 }
 
-func (fs *FilerServer) detectStorageOption0(requestURI, qCollection, qReplication string, qTtl string, diskType string, fsync string, dataCenter, rack, dataNode, saveInside string) (*operation.StorageOption, error) {
+func (fs *FilerServer) detectStorageOption0(requestURI, qCollection, qReplication string, qTtl string, diskType string, fsync string, dataCenter, rack, dataNode, saveInside, cipher string) (*operation.StorageOption, error) {
 
 	ttl, err := needle.ReadTTL(qTtl)
 	if err != nil {
@@ -285,6 +286,8 @@ func (fs *FilerServer) detectStorageOption0(requestURI, qCollection, qReplicatio
 		} else {
 			so.SaveInside = false
 		}
+		// encrypt the chunks of this file, even if the filer does not encrypt all files
+		so.Cipher = cipher == "true"
 	}
 
 	return so, err
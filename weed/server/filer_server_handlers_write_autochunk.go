@@ -48,12 +48,14 @@ func (fs *FilerServer) autoChunk(ctx context.Context, w http.ResponseWriter, r *
 		reply, md5bytes, err = fs.doPutAutoChunk(ctx, w, r, chunkSize, contentLength, so)
 	}
 	if err != nil {
-		if err.Error() == "operation not permitted" {
+		if err.Error() == "operation not permitted" || strings.Contains(err.Error(), filer.MsgReadOnlySnapshot) {
 			writeJsonError(w, r, http.StatusForbidden, err)
 		} else if strings.HasPrefix(err.Error(), "read input:") || err.Error() == io.ErrUnexpectedEOF.Error() {
 			writeJsonError(w, r, util.HttpStatusCancelled, err)
 		} else if strings.HasSuffix(err.Error(), "is a file") || strings.HasSuffix(err.Error(), "already exists") {
 			writeJsonError(w, r, http.StatusConflict, err)
+		} else if strings.Contains(err.Error(), filer.MsgQuotaExceeded) {
+			writeJsonError(w, r, http.StatusInsufficientStorage, err)
 		} else {
 			writeJsonError(w, r, http.StatusInternalServerError, err)
 		}
@@ -338,7 +340,7 @@ func (fs *FilerServer) saveAsChunk(so *operation.StorageOption) filer.SaveDataAs
 			uploadOption := &operation.UploadOption{
 				UploadUrl:         urlLocation,
 				Filename:          name,
-				Cipher:            fs.option.Cipher,
+				Cipher:            fs.option.Cipher || so.Cipher,
 				IsInputCompressed: false,
 				MimeType:          "",
 				PairMap:           nil,
@@ -443,6 +445,22 @@ func SaveAmzMetaData(r *http.Request, existing map[string][]byte, isReplace bool
 		}
 	}
 
+	if versionId := r.Header.Get(s3_constants.AmzVersionId); versionId != "" {
+		metadata[s3_constants.AmzVersionId] = []byte(versionId)
+	}
+
+	for _, k := range []string{s3_constants.AmzObjectLockMode, s3_constants.AmzObjectLockRetainUntilDate, s3_constants.AmzObjectLockLegalHold} {
+		if v := r.Header.Get(k); v != "" {
+			metadata[k] = []byte(v)
+		}
+	}
+
+	for _, k := range s3_constants.SSEMetadataKeys {
+		if v := r.Header.Get(k); v != "" {
+			metadata[k] = []byte(v)
+		}
+	}
+
 	for header, values := range r.Header {
 		if strings.HasPrefix(header, s3_constants.AmzUserMetaPrefix) {
 			for _, value := range values {
@@ -459,7 +477,7 @@ func SaveAmzMetaData(r *http.Request, existing map[string][]byte, isReplace bool
 
 	//acp-grants
 	acpGrants := r.Header.Get(s3_constants.ExtAmzAclKey)
-	if len(acpOwner) > 0 {
+	if len(acpGrants) > 0 {
 		metadata[s3_constants.ExtAmzAclKey] = []byte(acpGrants)
 	}
 
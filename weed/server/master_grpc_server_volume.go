@@ -238,7 +238,7 @@ func (ms *MasterServer) LookupEcVolume(ctx context.Context, req *master_pb.Looku
 
 	resp.VolumeId = req.VolumeId
 
-	for shardId, shardLocations := range ecLocations.Locations {
+	for shardId, shardLocations := range ecLocations.Locations[:ecLocations.Scheme.TotalShards()] {
 		var locations []*master_pb.Location
 		for _, dn := range shardLocations {
 			locations = append(locations, &master_pb.Location{
@@ -1,16 +1,32 @@
 package weed_server
 
 import (
+	"bytes"
+	gocsv "encoding/csv"
+	gojson "encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
 	"github.com/seaweedfs/seaweedfs/weed/glog"
 	"github.com/seaweedfs/seaweedfs/weed/operation"
 	"github.com/seaweedfs/seaweedfs/weed/pb/volume_server_pb"
+	"github.com/seaweedfs/seaweedfs/weed/query/csv"
 	"github.com/seaweedfs/seaweedfs/weed/query/json"
+	"github.com/seaweedfs/seaweedfs/weed/query/sqltypes"
 	"github.com/seaweedfs/seaweedfs/weed/storage/needle"
 	"github.com/tidwall/gjson"
 )
 
 func (vs *VolumeServer) Query(req *volume_server_pb.QueryRequest, stream volume_server_pb.VolumeServer_QueryServer) error {
 
+	if req.InputSerialization == nil {
+		return fmt.Errorf("missing input serialization")
+	}
+	if compressionType := req.InputSerialization.CompressionType; compressionType != "" && compressionType != "NONE" {
+		return fmt.Errorf("unsupported compression type %s", compressionType)
+	}
+
 	for _, fid := range req.FromFileIds {
 
 		vid, id_cookie, err := operation.ParseFileId(fid)
@@ -34,36 +50,258 @@ func (vs *VolumeServer) Query(req *volume_server_pb.QueryRequest, stream volume_
 			return err
 		}
 
-		if req.InputSerialization.CsvInput != nil {
+		if n.IsCompressed() {
+			return fmt.Errorf("volume query fid %s: compressed needle", fid)
+		}
+
+		var stripe *volume_server_pb.QueriedStripe
 
+		if req.InputSerialization.CsvInput != nil {
+			stripe, err = queryCsv(req, n.Data)
+			if err != nil {
+				return fmt.Errorf("volume query fid %s: %v", fid, err)
+			}
 		}
 
 		if req.InputSerialization.JsonInput != nil {
+			stripe = queryJson(req, n.Data)
+		}
+
+		if stripe == nil {
+			return fmt.Errorf("unsupported input serialization")
+		}
+		err = stream.Send(stripe)
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return nil
+}
+
+// splitPartialRecords separates the complete records from the possibly partial first and last record
+func splitPartialRecords(data []byte, recordDelimiter []byte) (head, records, tail []byte) {
+	i := bytes.Index(data, recordDelimiter)
+	if i < 0 {
+		return data, nil, nil
+	}
+	head, data = data[:i+len(recordDelimiter)], data[i+len(recordDelimiter):]
+	j := bytes.LastIndex(data, recordDelimiter)
+	if j < 0 {
+		return head, nil, data
+	}
+	return head, data[:j+len(recordDelimiter)], data[j+len(recordDelimiter):]
+}
+
+func forEachRecord(data []byte, recordDelimiter []byte, fn func(record []byte) bool) {
+	for len(data) > 0 {
+		record := data
+		i := bytes.Index(data, recordDelimiter)
+		if i >= 0 {
+			record, data = data[:i], data[i+len(recordDelimiter):]
+		} else {
+			data = nil
+		}
+		if len(record) == 0 {
+			continue
+		}
+		if !fn(record) {
+			return
+		}
+	}
+}
+
+func queryJson(req *volume_server_pb.QueryRequest, data []byte) *volume_server_pb.QueriedStripe {
+
+	stripe := &volume_server_pb.QueriedStripe{}
+	recordDelimiter := []byte("\n")
+	if req.PartialRecords {
+		stripe.Head, data, stripe.Tail = splitPartialRecords(data, recordDelimiter)
+	}
+	outputDelimiter := jsonOutputRecordDelimiter(req)
+
+	var filter json.Query
+	if req.Filter != nil {
+		filter = json.Query{
+			Field: req.Filter.Field,
+			Op:    req.Filter.Operand,
+			Value: req.Filter.Value,
+		}
+	}
+	gjson.ForEachLine(string(data), func(line gjson.Result) bool {
+		if line.Raw == "" {
+			return true
+		}
+		passedFilter, values := true, []sqltypes.Value(nil)
+		if filter.Field != "" {
+			passedFilter, values = json.QueryJson(line.Raw, req.Selections, filter)
+		} else if len(req.Selections) > 0 {
+			values = json.GetValues(line.Raw, req.Selections)
+		}
+		if !passedFilter {
+			return true
+		}
+		if len(req.Selections) == 0 {
+			// the matching records are returned as is
+			stripe.Records = append(stripe.Records, line.Raw...)
+			stripe.Records = append(stripe.Records, recordDelimiter...)
+			return true
+		}
+		stripe.Records = json.ToJson(stripe.Records, req.Selections, values)
+		stripe.Records = append(stripe.Records, outputDelimiter...)
+		return true
+	})
+
+	return stripe
+}
+
+func jsonOutputRecordDelimiter(req *volume_server_pb.QueryRequest) string {
+	if req.OutputSerialization != nil && req.OutputSerialization.JsonOutput != nil && req.OutputSerialization.JsonOutput.RecordDelimiter != "" {
+		return req.OutputSerialization.JsonOutput.RecordDelimiter
+	}
+	return "\n"
+}
+
+func queryCsv(req *volume_server_pb.QueryRequest, data []byte) (*volume_server_pb.QueriedStripe, error) {
+
+	csvInput := req.InputSerialization.CsvInput
+	if csvInput.AllowQuotedRecordDelimiter {
+		return nil, fmt.Errorf("quoted record delimiters are not supported")
+	}
+	recordDelimiter := []byte(stringOrDefault(csvInput.RecordDelimiter, "\n"))
+	fieldDelimiter, err := singleRune(stringOrDefault(csvInput.FieldDelimiter, ","))
+	if err != nil {
+		return nil, fmt.Errorf("field delimiter: %v", err)
+	}
+	comments := stringOrDefault(csvInput.Comments, "#")
 
-			stripe := &volume_server_pb.QueriedStripe{
-				Records: nil,
+	stripe := &volume_server_pb.QueriedStripe{}
+	fileHeaderInfo := csvInput.FileHeaderInfo
+	if req.PartialRecords {
+		stripe.Head, data, stripe.Tail = splitPartialRecords(data, recordDelimiter)
+		// the header, if any, can only be in the head
+		fileHeaderInfo = "NONE"
+	}
+
+	var filter csv.Query
+	if req.Filter != nil {
+		filter = csv.Query{
+			Field: req.Filter.Field,
+			Op:    req.Filter.Operand,
+			Value: req.Filter.Value,
+		}
+	}
+
+	var csvOutput *volume_server_pb.QueryRequest_OutputSerialization_CSVOutput
+	if req.OutputSerialization != nil {
+		csvOutput = req.OutputSerialization.CsvOutput
+	}
+	outputDelimiter := jsonOutputRecordDelimiter(req)
+
+	var header map[string]int
+	isFirstRecord := true
+	forEachRecord(data, recordDelimiter, func(record []byte) bool {
+		if bytes.Equal(recordDelimiter, []byte("\n")) {
+			record = bytes.TrimSuffix(record, []byte("\r"))
+		}
+		if comments != "" && bytes.HasPrefix(record, []byte(comments)) {
+			return true
+		}
+		if isFirstRecord {
+			isFirstRecord = false
+			switch fileHeaderInfo {
+			case "USE":
+				fields, parseErr := csv.ParseRecord(string(record), fieldDelimiter)
+				if parseErr != nil {
+					err = parseErr
+					return false
+				}
+				header = csv.ParseHeader(fields)
+				return true
+			case "IGNORE":
+				return true
 			}
+		}
 
-			filter := json.Query{
-				Field: req.Filter.Field,
-				Op:    req.Filter.Operand,
-				Value: req.Filter.Value,
+		fields, parseErr := csv.ParseRecord(string(record), fieldDelimiter)
+		if parseErr != nil {
+			err = parseErr
+			return false
+		}
+		passedFilter, values := true, []string(nil)
+		if filter.Field != "" {
+			passedFilter, values = csv.QueryCsv(fields, header, req.Selections, filter)
+		} else {
+			for _, selection := range req.Selections {
+				value, _ := csv.GetField(fields, header, selection)
+				values = append(values, value)
 			}
-			gjson.ForEachLine(string(n.Data), func(line gjson.Result) bool {
-				passedFilter, values := json.QueryJson(line.Raw, req.Selections, filter)
-				if !passedFilter {
-					return true
+		}
+		if !passedFilter {
+			return true
+		}
+		switch {
+		case len(req.Selections) == 0:
+			// the matching records are returned as is
+			stripe.Records = append(stripe.Records, record...)
+			stripe.Records = append(stripe.Records, recordDelimiter...)
+		case csvOutput != nil:
+			stripe.Records, err = appendCsvRecord(stripe.Records, csvOutput, values)
+		default:
+			stripe.Records = append(stripe.Records, '{')
+			for i, value := range values {
+				if i > 0 {
+					stripe.Records = append(stripe.Records, ',')
 				}
-				stripe.Records = json.ToJson(stripe.Records, req.Selections, values)
-				return true
-			})
-			err = stream.Send(stripe)
-			if err != nil {
-				return err
+				stripe.Records = appendJsonString(stripe.Records, req.Selections[i])
+				stripe.Records = append(stripe.Records, ':')
+				stripe.Records = appendJsonString(stripe.Records, value)
 			}
+			stripe.Records = append(stripe.Records, '}')
+			stripe.Records = append(stripe.Records, outputDelimiter...)
 		}
+		return err == nil
+	})
+
+	return stripe, err
+}
 
+func appendCsvRecord(buf []byte, csvOutput *volume_server_pb.QueryRequest_OutputSerialization_CSVOutput, values []string) ([]byte, error) {
+	fieldDelimiter, err := singleRune(stringOrDefault(csvOutput.FieldDelimiter, ","))
+	if err != nil {
+		return buf, fmt.Errorf("output field delimiter: %v", err)
 	}
+	var b bytes.Buffer
+	writer := gocsv.NewWriter(&b)
+	writer.Comma = fieldDelimiter
+	if err = writer.Write(values); err != nil {
+		return buf, err
+	}
+	writer.Flush()
+	buf = append(buf, bytes.TrimSuffix(b.Bytes(), []byte("\n"))...)
+	return append(buf, stringOrDefault(csvOutput.RecordDelimiter, "\n")...), nil
+}
 
-	return nil
+func appendJsonString(buf []byte, s string) []byte {
+	quoted, _ := gojson.Marshal(s)
+	return append(buf, quoted...)
+}
+
+func stringOrDefault(s, defaultValue string) string {
+	if s == "" {
+		return defaultValue
+	}
+	return s
+}
+
+func singleRune(s string) (rune, error) {
+	r, size := utf8.DecodeRuneInString(s)
+	if size != len(s) || r == utf8.RuneError {
+		return 0, fmt.Errorf("%q is not a single character", s)
+	}
+	if strings.ContainsRune("\"\r\n", r) {
+		return 0, fmt.Errorf("%q can not be a delimiter", s)
+	}
+	return r, nil
 }
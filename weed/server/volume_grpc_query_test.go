@@ -0,0 +1,38 @@
+package weed_server
+
+import (
+	"testing"
+
+	"github.com/seaweedfs/seaweedfs/weed/pb/volume_server_pb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryPartialRecords(t *testing.T) {
+	req := &volume_server_pb.QueryRequest{
+		Filter: &volume_server_pb.QueryRequest_Filter{Field: "_2", Operand: ">", Value: "20"},
+		InputSerialization: &volume_server_pb.QueryRequest_InputSerialization{
+			CsvInput: &volume_server_pb.QueryRequest_InputSerialization_CSVInput{},
+		},
+		PartialRecords: true,
+	}
+	stripe, err := queryCsv(req, []byte("ce,30\nBob,25\n#x,99\nCarol,10\nDav"))
+	assert.Nil(t, err)
+	assert.Equal(t, "ce,30\n", string(stripe.Head))
+	assert.Equal(t, "Bob,25\n", string(stripe.Records))
+	assert.Equal(t, "Dav", string(stripe.Tail))
+
+	req.Selections = []string{"_1"}
+	stripe, err = queryCsv(req, []byte("no delimiter"))
+	assert.Nil(t, err)
+	assert.Equal(t, "no delimiter", string(stripe.Head))
+	assert.Nil(t, stripe.Records)
+
+	req.InputSerialization = &volume_server_pb.QueryRequest_InputSerialization{
+		JsonInput: &volume_server_pb.QueryRequest_InputSerialization_JSONInput{Type: "LINES"},
+	}
+	req.Filter = &volume_server_pb.QueryRequest_Filter{Field: "a.b", Operand: "=", Value: "x"}
+	req.Selections = []string{"a.b", "c"}
+	stripe = queryJson(req, []byte(`{"a":{"b":"x"}}`+"\n"+`{"a":{"b":"x"},"c":1}`+"\n"+`{"a":{"b":"y"}}`+"\n"+`{"a":`))
+	assert.Equal(t, `{"a.b":"x","c":1}`+"\n", string(stripe.Records))
+	assert.Equal(t, `{"a":`, string(stripe.Tail))
+}
@@ -167,6 +167,16 @@ func countShards(ecShardInfos []*master_pb.VolumeEcShardInformationMessage) (cou
 	return
 }
 
+// countShardSlotUnits counts the volume slots taken by the shards of their own schemes, in erasure_coding.VolumeSlotUnits
+func countShardSlotUnits(ecShardInfos []*master_pb.VolumeEcShardInformationMessage) (slotUnits int64) {
+	for _, ecShardInfo := range ecShardInfos {
+		scheme := erasure_coding.NewEcScheme(ecShardInfo.DataShards, ecShardInfo.ParityShards)
+		slotUnits += int64(erasure_coding.ShardBits(ecShardInfo.EcIndexBits).ShardIdCount()) * scheme.ShardSlotUnits()
+	}
+	return
+}
+
+// countFreeShardSlots counts the free slots in shards of the default scheme
 func countFreeShardSlots(dn *master_pb.DataNodeInfo, diskType types.DiskType) (count int) {
 	if dn.DiskInfos == nil {
 		return 0
@@ -175,7 +185,8 @@ func countFreeShardSlots(dn *master_pb.DataNodeInfo, diskType types.DiskType) (c
 	if diskInfo == nil {
 		return 0
 	}
-	return int(diskInfo.MaxVolumeCount-diskInfo.VolumeCount)*erasure_coding.DataShardsCount - countShards(diskInfo.EcShardInfos)
+	freeSlotUnits := (diskInfo.MaxVolumeCount-diskInfo.VolumeCount)*erasure_coding.VolumeSlotUnits - countShardSlotUnits(diskInfo.EcShardInfos)
+	return int(freeSlotUnits / erasure_coding.DefaultEcScheme.ShardSlotUnits())
 }
 
 type RackId string
@@ -299,6 +310,23 @@ func findEcVolumeShards(ecNode *EcNode, vid needle.VolumeId) erasure_coding.Shar
 	return 0
 }
 
+// findEcVolumeScheme finds the erasure coding scheme of the ec volume reported by the ec nodes
+func findEcVolumeScheme(ecNodes []*EcNode, vid needle.VolumeId) erasure_coding.EcScheme {
+	for _, ecNode := range ecNodes {
+		diskInfo, found := ecNode.info.DiskInfos[string(types.HardDriveType)]
+		if !found {
+			continue
+		}
+		for _, shardInfo := range diskInfo.EcShardInfos {
+			// the shards only added to the ec nodes in memory do not have the scheme
+			if needle.VolumeId(shardInfo.Id) == vid && shardInfo.DataShards != 0 {
+				return erasure_coding.NewEcScheme(shardInfo.DataShards, shardInfo.ParityShards)
+			}
+		}
+	}
+	return erasure_coding.DefaultEcScheme
+}
+
 func (ecNode *EcNode) addEcVolumeShards(vid needle.VolumeId, collection string, shardIds []uint32) *EcNode {
 
 	foundVolume := false
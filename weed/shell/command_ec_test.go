@@ -5,7 +5,10 @@ import (
 	"testing"
 
 	"github.com/seaweedfs/seaweedfs/weed/pb/master_pb"
+	"github.com/seaweedfs/seaweedfs/weed/storage/erasure_coding"
 	"github.com/seaweedfs/seaweedfs/weed/storage/needle"
+	"github.com/seaweedfs/seaweedfs/weed/storage/types"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestCommandEcDistribution(t *testing.T) {
@@ -15,7 +18,7 @@ func TestCommandEcDistribution(t *testing.T) {
 		newEcNode("dc1", "rack2", "dn2", 100),
 	}
 
-	allocated := balancedEcDistribution(allEcNodes)
+	allocated := balancedEcDistribution(allEcNodes, erasure_coding.DefaultEcScheme)
 
 	fmt.Printf("allocated: %+v", allocated)
 }
@@ -123,6 +126,25 @@ func TestCommandEcBalanceVolumeEvenButRackUneven(t *testing.T) {
 	balanceEcRacks(nil, racks, false)
 }
 
+func TestCountFreeShardSlots(t *testing.T) {
+	dn := &master_pb.DataNodeInfo{
+		DiskInfos: map[string]*master_pb.DiskInfo{
+			"": {
+				MaxVolumeCount: 10,
+				VolumeCount:    7,
+				EcShardInfos: []*master_pb.VolumeEcShardInformationMessage{
+					// 10 shards of the default scheme take one volume slot
+					{Id: 1, EcIndexBits: uint32(erasure_coding.ShardBits(0x3ff))},
+					// 6 shards of a 6+3 volume take one volume slot
+					{Id: 2, EcIndexBits: uint32(erasure_coding.ShardBits(0x3f)), DataShards: 6, ParityShards: 3},
+				},
+			},
+		},
+	}
+	assert.Equal(t, 10, countFreeShardSlots(dn, types.HardDriveType))
+	assert.Equal(t, float64(1), capacityByFreeVolumeCount(types.HardDriveType)(dn))
+}
+
 func newEcNode(dc string, rack string, dataNodeId string, freeEcSlot int) *EcNode {
 	return &EcNode{
 		info: &master_pb.DataNodeInfo{
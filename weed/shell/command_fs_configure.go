@@ -10,6 +10,7 @@ import (
 
 	"github.com/seaweedfs/seaweedfs/weed/filer"
 	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/storage/erasure_coding"
 	"github.com/seaweedfs/seaweedfs/weed/storage/super_block"
 )
 
@@ -37,6 +38,12 @@ func (c *commandFsConfigure) Help() string {
 	# example: configure adding only 1 physical volume for each bucket collection
 	fs.configure -locationPrefix=/buckets/ -volumeGrowthCount=1
 
+	# example: keep the deleted and overwritten files of each bucket in its .trash folder for 30 days
+	fs.configure -locationPrefix=/buckets/ -trash -trashRetentionDays=30
+
+	# example: erasure code the volumes of each bucket collection with 6 data shards and 3 parity shards
+	fs.configure -locationPrefix=/buckets/ -ecScheme=6+3
+
 	# apply the changes
 	fs.configure -locationPrefix=/my/folder -collection=abc -apply
 
@@ -62,6 +69,9 @@ func (c *commandFsConfigure) Do(args []string, commandEnv *CommandEnv, writer io
 	rack := fsConfigureCommand.String("rack", "", "assign writes to this rack")
 	dataNode := fsConfigureCommand.String("dataNode", "", "assign writes to this dataNode")
 	volumeGrowthCount := fsConfigureCommand.Int("volumeGrowthCount", 0, "the number of physical volumes to add if no writable volumes")
+	trash := fsConfigureCommand.Bool("trash", false, "move the deleted and overwritten entries to a .trash folder, see fs.trash.list")
+	trashRetentionDays := fsConfigureCommand.Uint("trashRetentionDays", 0, fmt.Sprintf("days to keep the entries in the trash, %d days if not set", filer.DefaultTrashRetentionDays))
+	ecScheme := fsConfigureCommand.String("ecScheme", "", "the erasure coding scheme of the collection for ec.encode, as <data shards>+<parity shards>, e.g. 6+3")
 	isDelete := fsConfigureCommand.Bool("delete", false, "delete the configuration by locationPrefix")
 	apply := fsConfigureCommand.Bool("apply", false, "update and apply filer configuration")
 	if err = fsConfigureCommand.Parse(args); err != nil {
@@ -76,19 +86,22 @@ func (c *commandFsConfigure) Do(args []string, commandEnv *CommandEnv, writer io
 	if *locationPrefix != "" {
 		infoAboutSimulationMode(writer, *apply, "-apply")
 		locConf := &filer_pb.FilerConf_PathConf{
-			LocationPrefix:    *locationPrefix,
-			Collection:        *collection,
-			Replication:       *replication,
-			Ttl:               *ttl,
-			Fsync:             *fsync,
-			MaxFileNameLength: uint32(*maxFileNameLength),
-			DiskType:          *diskType,
-			VolumeGrowthCount: uint32(*volumeGrowthCount),
-			ReadOnly:          *isReadOnly,
-			DataCenter:        *dataCenter,
-			Rack:              *rack,
-			DataNode:          *dataNode,
-			Worm:              *worm,
+			LocationPrefix:     *locationPrefix,
+			Collection:         *collection,
+			Replication:        *replication,
+			Ttl:                *ttl,
+			Fsync:              *fsync,
+			MaxFileNameLength:  uint32(*maxFileNameLength),
+			DiskType:           *diskType,
+			VolumeGrowthCount:  uint32(*volumeGrowthCount),
+			ReadOnly:           *isReadOnly,
+			DataCenter:         *dataCenter,
+			Rack:               *rack,
+			DataNode:           *dataNode,
+			Worm:               *worm,
+			Trash:              *trash,
+			TrashRetentionDays: uint32(*trashRetentionDays),
+			EcScheme:           *ecScheme,
 		}
 
 		// check collection
@@ -117,6 +130,13 @@ func (c *commandFsConfigure) Do(args []string, commandEnv *CommandEnv, writer io
 			}
 		}
 
+		// check ec scheme
+		if *ecScheme != "" {
+			if _, err := erasure_coding.ParseEcScheme(*ecScheme); err != nil {
+				return err
+			}
+		}
+
 		// save it
 		if *isDelete {
 			fc.DeleteLocationConf(*locationPrefix)
@@ -0,0 +1,78 @@
+package shell
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/util"
+)
+
+func init() {
+	Commands = append(Commands, &commandFsQuotaReport{})
+}
+
+type commandFsQuotaReport struct {
+}
+
+func (c *commandFsQuotaReport) Name() string {
+	return "fs.quota.report"
+}
+
+func (c *commandFsQuotaReport) Help() string {
+	return `report the usage of the directory quotas
+
+	# all the directory quotas
+	fs.quota.report
+
+	# the directory quotas under a path
+	fs.quota.report /home/
+
+	The usage is counted by the filer since the quota is set, see fs.quota.set.
+	A quota is not enforced until its directory is scanned.
+`
+}
+
+func (c *commandFsQuotaReport) Do(args []string, commandEnv *CommandEnv, writer io.Writer) (err error) {
+
+	path, err := commandEnv.parseUrl(findInputDirectory(args))
+	if err != nil {
+		return err
+	}
+	prefix := strings.TrimSuffix(path, "/") + "/"
+
+	var resp *filer_pb.ListDirectoryQuotasResponse
+	if err = commandEnv.WithFilerClient(false, func(client filer_pb.SeaweedFilerClient) error {
+		resp, err = client.ListDirectoryQuotas(context.Background(), &filer_pb.ListDirectoryQuotasRequest{})
+		return err
+	}); err != nil {
+		return fmt.Errorf("list directory quotas: %v", err)
+	}
+
+	count := 0
+	for _, quota := range resp.Quotas {
+		if !strings.HasPrefix(strings.TrimSuffix(quota.Directory, "/")+"/", prefix) {
+			continue
+		}
+		count++
+		fmt.Fprintf(writer, "%s\tsize:%s\tinodes:%s", quota.Directory,
+			formatQuotaUsage(util.BytesToHumanReadable(uint64(quota.UsedBytes)), util.BytesToHumanReadable(uint64(quota.MaxBytes)), quota.UsedBytes, quota.MaxBytes),
+			formatQuotaUsage(fmt.Sprintf("%d", quota.UsedInodes), fmt.Sprintf("%d", quota.MaxInodes), quota.UsedInodes, quota.MaxInodes))
+		if !quota.IsScanned {
+			fmt.Fprintf(writer, "\tscanning")
+		}
+		fmt.Fprintln(writer)
+	}
+	fmt.Fprintf(writer, "%d directory quotas\n", count)
+
+	return nil
+}
+
+func formatQuotaUsage(usedText, maxText string, used, max int64) string {
+	if max <= 0 {
+		return usedText + "/unlimited"
+	}
+	return fmt.Sprintf("%s/%s(%.2f%%)", usedText, maxText, float64(used)*100/float64(max))
+}
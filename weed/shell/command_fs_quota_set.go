@@ -0,0 +1,104 @@
+package shell
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/seaweedfs/seaweedfs/weed/filer"
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"google.golang.org/protobuf/proto"
+)
+
+func init() {
+	Commands = append(Commands, &commandFsQuotaSet{})
+}
+
+type commandFsQuotaSet struct {
+}
+
+func (c *commandFsQuotaSet) Name() string {
+	return "fs.quota.set"
+}
+
+func (c *commandFsQuotaSet) Help() string {
+	return `set or remove the quota of a directory, on the total file size and the number of entries under it
+
+	# limit a home directory to 10GiB and 100000 files and directories
+	fs.quota.set -path=/home/user1 -sizeMB=10240 -inodes=100000 -apply
+
+	# remove the quota
+	fs.quota.set -path=/home/user1 -remove -apply
+
+	The quota is saved in filer.conf, see fs.configure, and enforced by the filers for all the writes under the directory.
+	It is not inherited by the sub directories, which can have their own quotas.
+	The entries kept in the trash are not counted. See fs.quota.report for the usage.
+`
+}
+
+func (c *commandFsQuotaSet) Do(args []string, commandEnv *CommandEnv, writer io.Writer) (err error) {
+
+	fsQuotaSetCommand := flag.NewFlagSet(c.Name(), flag.ContinueOnError)
+	dirPath := fsQuotaSetCommand.String("path", "", "the directory")
+	sizeMB := fsQuotaSetCommand.Int64("sizeMB", 0, "the limit of the total file size in MiB, 0 for no limit")
+	inodes := fsQuotaSetCommand.Int64("inodes", 0, "the limit of the number of files and directories, 0 for no limit")
+	isRemove := fsQuotaSetCommand.Bool("remove", false, "remove the quota")
+	apply := fsQuotaSetCommand.Bool("apply", false, "update and apply filer configuration")
+	if err = fsQuotaSetCommand.Parse(args); err != nil {
+		return nil
+	}
+
+	if *dirPath == "" {
+		return fmt.Errorf("need to have -path")
+	}
+	if !*isRemove && *sizeMB <= 0 && *inodes <= 0 {
+		return fmt.Errorf("need to have -sizeMB or -inodes, or -remove")
+	}
+	dir, err := commandEnv.parseUrl(*dirPath)
+	if err != nil {
+		return err
+	}
+	locationPrefix := strings.TrimSuffix(dir, "/") + "/"
+
+	fc, err := filer.ReadFilerConf(commandEnv.option.FilerAddress, commandEnv.option.GrpcDialOption, commandEnv.MasterClient)
+	if err != nil {
+		return err
+	}
+
+	infoAboutSimulationMode(writer, *apply, "-apply")
+	locConf, found := fc.GetLocationConf(locationPrefix)
+	if !found {
+		locConf = &filer_pb.FilerConf_PathConf{LocationPrefix: locationPrefix}
+	}
+	if *isRemove {
+		locConf.QuotaBytes, locConf.QuotaInodes = 0, 0
+	} else {
+		locConf.QuotaBytes, locConf.QuotaInodes = *sizeMB*1024*1024, *inodes
+	}
+	if proto.Equal(locConf, &filer_pb.FilerConf_PathConf{LocationPrefix: locationPrefix}) {
+		fc.DeleteLocationConf(locationPrefix)
+	} else if err = fc.SetLocationConf(locConf); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	fc.ToText(&buf)
+
+	fmt.Fprintf(writer, string(buf.Bytes()))
+	fmt.Fprintln(writer)
+
+	if *apply {
+
+		if err = commandEnv.WithFilerClient(false, func(client filer_pb.SeaweedFilerClient) error {
+			return filer.SaveInsideFiler(client, filer.DirectoryEtcSeaweedFS, filer.FilerConfName, buf.Bytes())
+		}); err != nil && err != filer_pb.ErrNotFound {
+			return err
+		}
+
+	}
+
+	return nil
+
+}
@@ -0,0 +1,303 @@
+package shell
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/seaweedfs/seaweedfs/weed/filer"
+	"github.com/seaweedfs/seaweedfs/weed/operation"
+	"github.com/seaweedfs/seaweedfs/weed/pb"
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/util"
+	"google.golang.org/protobuf/proto"
+)
+
+func init() {
+	Commands = append(Commands, &commandFsRestore{})
+}
+
+type commandFsRestore struct {
+}
+
+func (c *commandFsRestore) Name() string {
+	return "fs.restore"
+}
+
+func (c *commandFsRestore) Help() string {
+	return `restore a directory tree as it was at a past time, from the filer metadata log
+
+	# see what would be restored
+	fs.restore -path=/dir -time=2024-01-02T15:04:05Z -to=/restored
+
+	# restore the tree into a new directory
+	fs.restore -path=/dir -time=2024-01-02T15:04:05Z -to=/restored -apply
+
+	The changes under the path since the time are undone from the newest to the oldest,
+	using the metadata log under ` + filer.SystemLogDir + `, which must not be purged since the time.
+	The data of the restored files is copied into new chunks, so the restored files and
+	the current files can be changed or deleted independently.
+	The chunks deleted since then can not be restored, and their files are skipped.
+	Keep them with the filer trash or "disable_chunk_deletion", see fs.configure.
+`
+}
+
+func (c *commandFsRestore) Do(args []string, commandEnv *CommandEnv, writer io.Writer) (err error) {
+
+	fsRestoreCommand := flag.NewFlagSet(c.Name(), flag.ContinueOnError)
+	dirPath := fsRestoreCommand.String("path", "", "the directory to restore")
+	timeStr := fsRestoreCommand.String("time", "", "restore the directory as it was at this time, in RFC3339 format, e.g. 2024-01-02T15:04:05Z")
+	toPath := fsRestoreCommand.String("to", "", "a new directory to restore into")
+	verbose := fsRestoreCommand.Bool("v", false, "print each restored entry")
+	apply := fsRestoreCommand.Bool("apply", false, "restore the entries")
+	if err = fsRestoreCommand.Parse(args); err != nil {
+		return nil
+	}
+
+	if *dirPath == "" || *timeStr == "" || *toPath == "" {
+		return fmt.Errorf("need to have -path, -time and -to")
+	}
+	restoreTime, err := time.Parse(time.RFC3339, *timeStr)
+	if err != nil {
+		return fmt.Errorf("parse time %s: %v", *timeStr, err)
+	}
+	if restoreTime.After(time.Now()) {
+		return fmt.Errorf("time %s is in the future", *timeStr)
+	}
+	sourcePath, err := commandEnv.parseUrl(*dirPath)
+	if err != nil {
+		return err
+	}
+	source := util.FullPath(strings.TrimSuffix(sourcePath, "/"))
+	targetPath, err := commandEnv.parseUrl(*toPath)
+	if err != nil {
+		return err
+	}
+	target := util.FullPath(strings.TrimSuffix(targetPath, "/"))
+	if source == "" || isUnderPath(target, source) {
+		return fmt.Errorf("can not restore %s into %s", source, target)
+	}
+	if existing, _ := filer_pb.GetEntry(commandEnv, target); existing != nil {
+		return fmt.Errorf("%s already exists", target)
+	}
+
+	c.warnIfLogPurged(commandEnv, writer, restoreTime)
+
+	pastEntries, err := c.collectPastEntries(commandEnv, source, restoreTime)
+	if err != nil {
+		return err
+	}
+
+	infoAboutSimulationMode(writer, *apply, "-apply")
+	var paths []util.FullPath
+	for p := range pastEntries {
+		paths = append(paths, p)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		return paths[i] < paths[j]
+	})
+
+	var fileCount, dirCount, skippedCount int
+	err = commandEnv.WithFilerClient(false, func(client filer_pb.SeaweedFilerClient) error {
+		var cipher bool
+		if *apply {
+			resp, err := client.GetFilerConfiguration(context.Background(), &filer_pb.GetFilerConfigurationRequest{})
+			if err != nil {
+				return fmt.Errorf("get filer configuration: %v", err)
+			}
+			cipher = resp.Cipher
+		}
+		for _, p := range paths {
+			entry := pastEntries[p]
+			restoredPath := target + p[len(source):]
+			if *verbose {
+				fmt.Fprintf(writer, "restore %s => %s\n", p, restoredPath)
+			}
+			if *apply && !entry.IsDirectory {
+				copiedChunks, err := c.copyChunks(commandEnv, entry.GetChunks(), restoredPath, cipher)
+				if err != nil {
+					fmt.Fprintf(writer, "skip %s: %v\n", p, err)
+					skippedCount++
+					continue
+				}
+				entry.Chunks = copiedChunks
+			}
+			if entry.IsDirectory {
+				dirCount++
+			} else {
+				fileCount++
+			}
+			if !*apply {
+				continue
+			}
+			restoredDir, restoredName := restoredPath.DirAndName()
+			entry.Name = restoredName
+			// the restored entries do not share the hard link with the existing entries
+			entry.HardLinkId, entry.HardLinkCounter = nil, 0
+			if err := filer_pb.CreateEntry(client, &filer_pb.CreateEntryRequest{
+				Directory: restoredDir,
+				Entry:     entry,
+			}); err != nil {
+				return fmt.Errorf("restore %s => %s: %v", p, restoredPath, err)
+			}
+		}
+		return nil
+	})
+	fmt.Fprintf(writer, "%d directories and %d files of %s at %s restored to %s\n", dirCount, fileCount, source, restoreTime.Format(time.RFC3339), target)
+	if err == nil && skippedCount > 0 {
+		err = fmt.Errorf("%d files are not restored", skippedCount)
+	}
+
+	return err
+}
+
+// copyChunks copies the data of the past file into new chunks, not shared with the current or the deleted files
+func (c *commandFsRestore) copyChunks(commandEnv *CommandEnv, chunks []*filer_pb.FileChunk, restoredPath util.FullPath, cipher bool) ([]*filer_pb.FileChunk, error) {
+	if len(chunks) == 0 {
+		return nil, nil
+	}
+	dataChunks, _, err := filer.ResolveChunkManifest(commandEnv.MasterClient.GetLookupFileIdFunction(), chunks, 0, math.MaxInt64)
+	if err != nil {
+		return nil, fmt.Errorf("resolve manifest chunks: %v", err)
+	}
+
+	uploader, err := operation.NewUploader()
+	if err != nil {
+		return nil, err
+	}
+	readChunk := func(chunk *filer_pb.FileChunk) ([]byte, error) {
+		var buf bytes.Buffer
+		err := filer.StreamContent(commandEnv.MasterClient, &buf, []*filer_pb.FileChunk{chunk}, chunk.Offset, int64(chunk.Size))
+		return buf.Bytes(), err
+	}
+	saveFunc := func(reader io.Reader, name string, offset int64, tsNs int64) (*filer_pb.FileChunk, error) {
+		fileId, uploadResult, err, _ := uploader.UploadWithRetry(
+			commandEnv,
+			&filer_pb.AssignVolumeRequest{
+				Count: 1,
+				Path:  string(restoredPath),
+			},
+			&operation.UploadOption{
+				Filename: restoredPath.Name(),
+				Cipher:   cipher,
+			},
+			func(host, fileId string) string {
+				return fmt.Sprintf("http://%s/%s", host, fileId)
+			},
+			reader,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if uploadResult.Error != "" {
+			return nil, fmt.Errorf("upload result: %v", uploadResult.Error)
+		}
+		return uploadResult.ToPbFileChunk(fileId, offset, tsNs), nil
+	}
+
+	return filer.CopyChunks(dataChunks, readChunk, saveFunc)
+}
+
+// collectPastEntries finds the entries under the directory at the time,
+// starting from the current entries and undoing the logged changes since the time:
+// the past entry of a path is the old entry of its first change after the time,
+// or the current entry if it has not changed since.
+func (c *commandFsRestore) collectPastEntries(commandEnv *CommandEnv, dir util.FullPath, restoreTime time.Time) (pastEntries map[util.FullPath]*filer_pb.Entry, err error) {
+
+	// nil for the paths not existing at the time
+	changedEntries := make(map[util.FullPath]*filer_pb.Entry)
+	recordFirstChange := func(p util.FullPath, oldEntry *filer_pb.Entry) {
+		if !isUnderPath(p, dir) {
+			return
+		}
+		if _, found := changedEntries[p]; !found {
+			changedEntries[p] = oldEntry
+		}
+	}
+
+	processEventFn := func(resp *filer_pb.SubscribeMetadataResponse) error {
+		message := resp.EventNotification
+		var oldPath util.FullPath
+		if message.OldEntry != nil {
+			oldPath = util.NewFullPath(resp.Directory, message.OldEntry.Name)
+			recordFirstChange(oldPath, message.OldEntry)
+		}
+		if message.NewEntry != nil {
+			newParentPath := message.NewParentPath
+			if newParentPath == "" {
+				newParentPath = resp.Directory
+			}
+			if newPath := util.NewFullPath(newParentPath, message.NewEntry.Name); newPath != oldPath {
+				recordFirstChange(newPath, nil)
+			}
+		}
+		return nil
+	}
+
+	metadataFollowOption := &pb.MetadataFollowOption{
+		ClientName:     "shell_restore",
+		ClientId:       util.RandomInt32(),
+		PathPrefix:     string(dir),
+		StartTsNs:      restoreTime.UnixNano(),
+		StopTsNs:       time.Now().UnixNano(),
+		EventErrorType: pb.DontLogError,
+	}
+	if err = pb.FollowMetadata(commandEnv.option.FilerAddress, commandEnv.option.GrpcDialOption, metadataFollowOption, processEventFn); err != nil {
+		return nil, fmt.Errorf("read metadata log of %s: %v", dir, err)
+	}
+
+	pastEntries = make(map[util.FullPath]*filer_pb.Entry)
+	var pastEntriesLock sync.Mutex
+	keepPastEntry := func(p util.FullPath, currentEntry *filer_pb.Entry) {
+		pastEntriesLock.Lock()
+		defer pastEntriesLock.Unlock()
+		entry, changed := changedEntries[p]
+		if !changed {
+			entry = currentEntry
+		}
+		if entry != nil {
+			pastEntries[p] = proto.Clone(entry).(*filer_pb.Entry)
+		}
+	}
+
+	if currentDir, _ := filer_pb.GetEntry(commandEnv, dir); currentDir != nil {
+		keepPastEntry(dir, currentDir)
+		if err = filer_pb.TraverseBfs(commandEnv, dir, func(parentPath util.FullPath, entry *filer_pb.Entry) {
+			keepPastEntry(parentPath.Child(entry.Name), entry)
+		}); err != nil {
+			return nil, fmt.Errorf("traverse %s: %v", dir, err)
+		}
+	}
+
+	// the entries deleted or moved away since the time
+	for p, entry := range changedEntries {
+		if _, found := pastEntries[p]; !found && entry != nil {
+			pastEntries[p] = proto.Clone(entry).(*filer_pb.Entry)
+		}
+	}
+
+	return pastEntries, nil
+}
+
+// warnIfLogPurged warns if the metadata log does not go back to the time
+func (c *commandFsRestore) warnIfLogPurged(commandEnv *CommandEnv, writer io.Writer, restoreTime time.Time) {
+	var firstDay string
+	_ = filer_pb.List(commandEnv, filer.SystemLogDir, "", func(entry *filer_pb.Entry, isLast bool) error {
+		firstDay = entry.Name
+		return nil
+	}, "", false, 1)
+	if firstDay > restoreTime.UTC().Format("2006-01-02") {
+		fmt.Fprintf(writer, "warning: the metadata log starts from %s, the changes before are not undone\n", firstDay)
+	}
+}
+
+func isUnderPath(p, dir util.FullPath) bool {
+	return p == dir || strings.HasPrefix(string(p), strings.TrimSuffix(string(dir), "/")+"/")
+}
@@ -0,0 +1,109 @@
+package shell
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/seaweedfs/seaweedfs/weed/filer"
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/util"
+)
+
+func init() {
+	Commands = append(Commands, &commandFsSnapshot{})
+}
+
+type commandFsSnapshot struct {
+}
+
+func (c *commandFsSnapshot) Name() string {
+	return "fs.snapshot"
+}
+
+func (c *commandFsSnapshot) Help() string {
+	return `create, list or delete the read-only snapshots of a directory
+
+	# create a snapshot of the directory in /data/.snapshots/2024-01-01
+	fs.snapshot -path=/data -name=2024-01-01
+
+	# list the snapshots of the directory
+	fs.snapshot -path=/data
+
+	# delete a snapshot
+	fs.snapshot -path=/data -name=2024-01-01 -delete
+
+	A snapshot only copies the metadata of the directory tree, sharing the file chunks with the directory.
+	The shared chunks are counted by the filer, and only deleted with the last file using them.
+	The snapshots can be read through the filer, weed mount and S3, but not changed.
+	The trash and the snapshots of the directory are not included in the snapshot.
+`
+}
+
+func (c *commandFsSnapshot) Do(args []string, commandEnv *CommandEnv, writer io.Writer) (err error) {
+
+	fsSnapshotCommand := flag.NewFlagSet(c.Name(), flag.ContinueOnError)
+	dirPath := fsSnapshotCommand.String("path", "", "the directory")
+	name := fsSnapshotCommand.String("name", "", "the snapshot name")
+	isDelete := fsSnapshotCommand.Bool("delete", false, "delete the snapshot")
+	if err = fsSnapshotCommand.Parse(args); err != nil {
+		return nil
+	}
+
+	if *dirPath == "" {
+		return fmt.Errorf("need to have -path")
+	}
+	dir, err := commandEnv.parseUrl(*dirPath)
+	if err != nil {
+		return err
+	}
+
+	if *name == "" {
+		if *isDelete {
+			return fmt.Errorf("need to have -name to delete")
+		}
+		return listSnapshots(commandEnv, util.FullPath(dir), writer)
+	}
+
+	return commandEnv.WithFilerClient(false, func(client filer_pb.SeaweedFilerClient) error {
+		if *isDelete {
+			if _, err := client.DeleteSnapshot(context.Background(), &filer_pb.DeleteSnapshotRequest{
+				Directory: dir,
+				Name:      *name,
+			}); err != nil {
+				return fmt.Errorf("delete snapshot %s of %s: %v", *name, dir, err)
+			}
+			fmt.Fprintf(writer, "deleted snapshot %s of %s\n", *name, dir)
+			return nil
+		}
+
+		resp, err := client.CreateSnapshot(context.Background(), &filer_pb.CreateSnapshotRequest{
+			Directory: dir,
+			Name:      *name,
+		})
+		if err != nil {
+			return fmt.Errorf("snapshot %s: %v", dir, err)
+		}
+		fmt.Fprintf(writer, "created snapshot %s with %d entries\n", resp.SnapshotDirectory, resp.EntryCount)
+		return nil
+	})
+}
+
+func listSnapshots(commandEnv *CommandEnv, dir util.FullPath, writer io.Writer) error {
+	count := 0
+	err := filer_pb.ReadDirAllEntries(commandEnv, dir.Child(filer.SnapshotFolderName), "", func(entry *filer_pb.Entry, isLast bool) error {
+		if !entry.IsDirectory {
+			return nil
+		}
+		count++
+		fmt.Fprintf(writer, "%s\t%s\n", entry.Name, time.Unix(entry.Attributes.Crtime, 0).Format(time.RFC3339))
+		return nil
+	})
+	if err != nil && err != filer_pb.ErrNotFound {
+		return fmt.Errorf("list snapshots of %s: %v", dir, err)
+	}
+	fmt.Fprintf(writer, "%d snapshots\n", count)
+	return nil
+}
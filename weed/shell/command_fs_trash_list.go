@@ -0,0 +1,125 @@
+package shell
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/seaweedfs/seaweedfs/weed/filer"
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/util"
+)
+
+func init() {
+	Commands = append(Commands, &commandFsTrashList{})
+}
+
+type commandFsTrashList struct {
+}
+
+func (c *commandFsTrashList) Name() string {
+	return "fs.trash.list"
+}
+
+func (c *commandFsTrashList) Help() string {
+	return `list the deleted and overwritten entries kept in the trash
+
+	fs.trash.list /buckets/bucket1
+	fs.trash.list /some/dir/
+
+	The trash is enabled by "fs.configure -locationPrefix=<path> -trash".
+	The entries deleted from the path are listed from the nearest .trash folder of the path or its parents,
+	one trash item per line with the item path, the deletion time, and the original path.
+`
+}
+
+func (c *commandFsTrashList) Do(args []string, commandEnv *CommandEnv, writer io.Writer) (err error) {
+
+	path, err := commandEnv.parseUrl(findInputDirectory(args))
+	if err != nil {
+		return err
+	}
+
+	trashDir, err := findTrashDirectory(commandEnv, util.FullPath(path))
+	if err != nil {
+		return err
+	}
+	items, err := listTrashItems(commandEnv, trashDir, util.FullPath(path))
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		originalPath := item.originalPath
+		if item.entry.IsDirectory {
+			originalPath += "/"
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%d\n", item.holder, item.deletedAt.Format(time.RFC3339), originalPath, filer.FileSize(item.entry))
+	}
+	fmt.Fprintf(writer, "%d entries in trash %s\n", len(items), trashDir)
+
+	return nil
+}
+
+// trashItem is one deleted entry kept in its own folder of the trash
+type trashItem struct {
+	holder       util.FullPath
+	originalPath util.FullPath
+	deletedAt    time.Time
+	entry        *filer_pb.Entry
+}
+
+// findTrashDirectory finds the trash keeping the deletions of the path, the nearest .trash folder of the path or its parents
+func findTrashDirectory(commandEnv *CommandEnv, path util.FullPath) (util.FullPath, error) {
+	if filer.IsInTrash(path) {
+		return path[:strings.Index(string(path)+"/", "/"+filer.TrashFolderName+"/")+len(filer.TrashFolderName)+1], nil
+	}
+	for p := path; ; {
+		if exists, err := filer_pb.Exists(commandEnv, string(p), filer.TrashFolderName, true); err != nil {
+			return "", err
+		} else if exists {
+			return p.Child(filer.TrashFolderName), nil
+		}
+		if p == "/" {
+			return "", fmt.Errorf("no trash found for %s", path)
+		}
+		dir, _ := p.DirAndName()
+		p = util.FullPath(dir)
+	}
+}
+
+// listTrashItems lists the entries kept in the trash, deleted from the path, in the order of deletion
+func listTrashItems(commandEnv *CommandEnv, trashDir util.FullPath, path util.FullPath) (items []*trashItem, err error) {
+	err = filer_pb.ReadDirAllEntries(commandEnv, trashDir, "", func(holder *filer_pb.Entry, isLast bool) error {
+		originalPath := util.FullPath(holder.Extended[filer.ExtTrashPathKey])
+		if !holder.IsDirectory || originalPath == "" {
+			return nil
+		}
+		if !filer.IsInTrash(path) && originalPath != path && !strings.HasPrefix(string(originalPath), strings.TrimSuffix(string(path), "/")+"/") {
+			return nil
+		}
+		item := &trashItem{
+			holder:       trashDir.Child(holder.Name),
+			originalPath: originalPath,
+			deletedAt:    time.Unix(holder.Attributes.Crtime, 0),
+		}
+		if deletedAtNs, parseErr := strconv.ParseInt(holder.Name, 10, 64); parseErr == nil {
+			item.deletedAt = time.Unix(0, deletedAtNs)
+		}
+		entry, getErr := filer_pb.GetEntry(commandEnv, item.holder.Child(originalPath.Name()))
+		if getErr != nil || entry == nil {
+			// an interrupted deletion
+			return nil
+		}
+		item.entry = entry
+		items = append(items, item)
+		return nil
+	})
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].deletedAt.Before(items[j].deletedAt)
+	})
+	return
+}
@@ -0,0 +1,94 @@
+package shell
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/seaweedfs/seaweedfs/weed/filer"
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/util"
+)
+
+func init() {
+	Commands = append(Commands, &commandFsTrashPurge{})
+}
+
+type commandFsTrashPurge struct {
+}
+
+func (c *commandFsTrashPurge) Name() string {
+	return "fs.trash.purge"
+}
+
+func (c *commandFsTrashPurge) Help() string {
+	return `permanently delete the entries kept in the trash, and their file content
+
+	# purge the entries deleted from a path
+	fs.trash.purge -apply /buckets/bucket1
+
+	# purge the entries deleted more than one day ago
+	fs.trash.purge -olderThan=24h -apply /buckets/bucket1
+
+	# purge one trash item, as listed by fs.trash.list
+	fs.trash.purge -apply /buckets/bucket1/.trash/1700000000000000000
+
+	The filer also purges the entries after the trash retention days, see fs.configure.
+`
+}
+
+func (c *commandFsTrashPurge) Do(args []string, commandEnv *CommandEnv, writer io.Writer) (err error) {
+
+	fsTrashPurgeCommand := flag.NewFlagSet(c.Name(), flag.ContinueOnError)
+	olderThan := fsTrashPurgeCommand.Duration("olderThan", 0, "only purge the entries deleted before this duration")
+	apply := fsTrashPurgeCommand.Bool("apply", false, "purge the entries")
+	if err = fsTrashPurgeCommand.Parse(args); err != nil {
+		return nil
+	}
+	if fsTrashPurgeCommand.NArg() != 1 {
+		return fmt.Errorf("need to have one path or trash item")
+	}
+
+	path, err := commandEnv.parseUrl(fsTrashPurgeCommand.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	var items []*trashItem
+	if filer.IsInTrash(util.FullPath(path)) {
+		item, err := findTrashItem(commandEnv, util.FullPath(path))
+		if err != nil {
+			return err
+		}
+		items = append(items, item)
+	} else {
+		trashDir, err := findTrashDirectory(commandEnv, util.FullPath(path))
+		if err != nil {
+			return err
+		}
+		if items, err = listTrashItems(commandEnv, trashDir, util.FullPath(path)); err != nil {
+			return err
+		}
+	}
+
+	infoAboutSimulationMode(writer, *apply, "-apply")
+	purgeCount := 0
+	for _, item := range items {
+		if time.Since(item.deletedAt) < *olderThan {
+			continue
+		}
+		purgeCount++
+		fmt.Fprintf(writer, "purge %s\t%s\n", item.holder, item.originalPath)
+		if !*apply {
+			continue
+		}
+		holderDir, holderName := item.holder.DirAndName()
+		if err = filer_pb.Remove(commandEnv, holderDir, holderName, true, true, true, false, nil); err != nil {
+			return fmt.Errorf("purge %s: %v", item.holder, err)
+		}
+	}
+	fmt.Fprintf(writer, "%d entries purged from trash\n", purgeCount)
+
+	return nil
+}
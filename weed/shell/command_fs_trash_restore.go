@@ -0,0 +1,112 @@
+package shell
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/seaweedfs/seaweedfs/weed/filer"
+	"github.com/seaweedfs/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/seaweedfs/weed/util"
+)
+
+func init() {
+	Commands = append(Commands, &commandFsTrashRestore{})
+}
+
+type commandFsTrashRestore struct {
+}
+
+func (c *commandFsTrashRestore) Name() string {
+	return "fs.trash.restore"
+}
+
+func (c *commandFsTrashRestore) Help() string {
+	return `restore a deleted or overwritten entry from the trash
+
+	# restore one trash item, as listed by fs.trash.list
+	fs.trash.restore /buckets/bucket1/.trash/1700000000000000000
+
+	# restore the last deleted version of a path
+	fs.trash.restore /buckets/bucket1/dir/file.txt
+
+	# restore to another path
+	fs.trash.restore -to=/buckets/bucket1/dir/file.restored.txt /buckets/bucket1/.trash/1700000000000000000
+
+	The entry is not restored over an existing entry, unless with -force,
+	in which case the existing entry goes to the trash.
+`
+}
+
+func (c *commandFsTrashRestore) Do(args []string, commandEnv *CommandEnv, writer io.Writer) (err error) {
+
+	fsTrashRestoreCommand := flag.NewFlagSet(c.Name(), flag.ContinueOnError)
+	to := fsTrashRestoreCommand.String("to", "", "restore to this path instead of the original path")
+	isForce := fsTrashRestoreCommand.Bool("force", false, "replace the existing entry")
+	if err = fsTrashRestoreCommand.Parse(args); err != nil {
+		return nil
+	}
+	if fsTrashRestoreCommand.NArg() != 1 {
+		return fmt.Errorf("need to have one trash item or original path")
+	}
+
+	path, err := commandEnv.parseUrl(fsTrashRestoreCommand.Arg(0))
+	if err != nil {
+		return err
+	}
+	item, err := findTrashItem(commandEnv, util.FullPath(path))
+	if err != nil {
+		return err
+	}
+
+	targetPath := item.originalPath
+	if *to != "" {
+		toPath, err := commandEnv.parseUrl(*to)
+		if err != nil {
+			return err
+		}
+		targetPath = util.FullPath(toPath)
+	}
+	if existing, _ := filer_pb.GetEntry(commandEnv, targetPath); existing != nil && !*isForce {
+		return fmt.Errorf("%s already exists, restore with -force to replace it", targetPath)
+	}
+
+	targetDir, targetName := targetPath.DirAndName()
+	if err = commandEnv.WithFilerClient(false, func(client filer_pb.SeaweedFilerClient) error {
+		if _, err := client.AtomicRenameEntry(context.Background(), &filer_pb.AtomicRenameEntryRequest{
+			OldDirectory: string(item.holder),
+			OldName:      item.entry.Name,
+			NewDirectory: targetDir,
+			NewName:      targetName,
+		}); err != nil {
+			return fmt.Errorf("move %s to %s: %v", item.holder.Child(item.entry.Name), targetPath, err)
+		}
+		holderDir, holderName := item.holder.DirAndName()
+		return filer_pb.DoRemove(client, holderDir, holderName, false, false, false, false, nil)
+	}); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(writer, "restored %s to %s\n", item.holder, targetPath)
+	return nil
+}
+
+// findTrashItem finds the trash item by its path, or the last deleted entry of the original path
+func findTrashItem(commandEnv *CommandEnv, path util.FullPath) (*trashItem, error) {
+	trashDir, err := findTrashDirectory(commandEnv, path)
+	if err != nil {
+		return nil, err
+	}
+	items, err := listTrashItems(commandEnv, trashDir, path)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(items) - 1; i >= 0; i-- {
+		item := items[i]
+		if filer.IsInTrash(path) && (item.holder == path || item.holder.Child(item.entry.Name) == path) || item.originalPath == path {
+			return item, nil
+		}
+	}
+	return nil, fmt.Errorf("%s not found in trash %s", path, trashDir)
+}
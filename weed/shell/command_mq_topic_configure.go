@@ -25,7 +25,18 @@ func (c *commandMqTopicConfigure) Help() string {
 	return `configure a topic with a given name
 
 	Example:
-		mq.topic.configure -namespace <namespace> -topic <topic_name> -partition_count <partition_count>
+		mq.topic.configure -namespace <namespace> -topic <topic_name> -partitionCount <partition_count>
+
+		# delete messages older than 7 days, or beyond 10GiB per partition
+		mq.topic.configure -namespace <namespace> -topic <topic_name> -retention 168h -retentionBytes 10737418240
+
+		# keep only the latest message of each key
+		mq.topic.configure -namespace <namespace> -topic <topic_name> -compacted
+
+		# remove the retention, keeping the messages forever
+		mq.topic.configure -namespace <namespace> -topic <topic_name> -retention 0
+
+	Without any retention option, the current retention of the topic is kept.
 `
 }
 
@@ -36,10 +47,24 @@ func (c *commandMqTopicConfigure) Do(args []string, commandEnv *CommandEnv, writ
 	namespace := mqCommand.String("namespace", "", "namespace name")
 	topicName := mqCommand.String("topic", "", "topic name")
 	partitionCount := mqCommand.Int("partitionCount", 6, "partition count")
+	retention := mqCommand.Duration("retention", 0, "delete messages older than this duration, 0 keeps them forever")
+	retentionBytes := mqCommand.Int64("retentionBytes", 0, "delete the oldest messages beyond this size per partition, 0 is unlimited")
+	compacted := mqCommand.Bool("compacted", false, "keep only the latest message of each key")
 	if err := mqCommand.Parse(args); err != nil {
 		return err
 	}
 
+	var topicRetention *mq_pb.TopicRetention
+	mqCommand.Visit(func(f *flag.Flag) {
+		if f.Name == "retention" || f.Name == "retentionBytes" || f.Name == "compacted" {
+			topicRetention = &mq_pb.TopicRetention{
+				RetentionSeconds: int64(retention.Seconds()),
+				RetentionBytes:   *retentionBytes,
+				Compacted:        *compacted,
+			}
+		}
+	})
+
 	// find the broker balancer
 	brokerBalancer, err := findBrokerBalancer(commandEnv)
 	if err != nil {
@@ -55,6 +80,7 @@ func (c *commandMqTopicConfigure) Do(args []string, commandEnv *CommandEnv, writ
 				Name:      *topicName,
 			},
 			PartitionCount: int32(*partitionCount),
+			Retention:      topicRetention,
 		})
 		if err != nil {
 			return err
@@ -250,6 +250,22 @@ var (
 			Help:      "Resource usage",
 		}, []string{"name", "type"})
 
+	VolumeServerTieredCacheCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "volumeServer",
+			Name:      "tiered_cache_total",
+			Help:      "Counter of the block cache hits, misses and evictions of tiered volumes.",
+		}, []string{"backend", "type"})
+
+	VolumeServerTieredRemoteBytesCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "volumeServer",
+			Name:      "tiered_remote_read_bytes",
+			Help:      "Counter of the bytes read from the remote storage of tiered volumes.",
+		}, []string{"backend"})
+
 	S3RequestCounter = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: Namespace,
@@ -313,6 +329,8 @@ func init() {
 	Gather.MustRegister(VolumeServerReadOnlyVolumeGauge)
 	Gather.MustRegister(VolumeServerDiskSizeGauge)
 	Gather.MustRegister(VolumeServerResourceGauge)
+	Gather.MustRegister(VolumeServerTieredCacheCounter)
+	Gather.MustRegister(VolumeServerTieredRemoteBytesCounter)
 
 	Gather.MustRegister(S3RequestCounter)
 	Gather.MustRegister(S3HandlerCounter)
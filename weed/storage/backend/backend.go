@@ -66,6 +66,11 @@ func LoadConfiguration(config *util.ViperProxy) {
 			if buildErr != nil {
 				glog.Fatalf("fail to create backend storage %s.%s", backendTypeName, backendStorageId)
 			}
+			backendStorage, buildErr = withBlockCache(backendStorage, config,
+				StorageBackendPrefix+"."+backendTypeName+"."+backendStorageId+".", backendTypeName+"."+backendStorageId)
+			if buildErr != nil {
+				glog.Fatalf("fail to create backend storage %s.%s: %v", backendTypeName, backendStorageId, buildErr)
+			}
 			BackendStorages[backendTypeName+"."+backendStorageId] = backendStorage
 			if backendStorageId == "default" {
 				BackendStorages[backendTypeName] = backendStorage
@@ -91,6 +96,10 @@ func LoadFromPbStorageBackends(storageBackends []*master_pb.StorageBackend) {
 		if buildErr != nil {
 			glog.Fatalf("fail to create backend storage %s.%s", storageBackend.Type, storageBackend.Id)
 		}
+		backendStorage, buildErr = withBlockCache(backendStorage, newProperties(storageBackend.Properties), "", storageBackend.Type+"."+storageBackend.Id)
+		if buildErr != nil {
+			glog.Fatalf("fail to create backend storage %s.%s: %v", storageBackend.Type, storageBackend.Id, buildErr)
+		}
 		BackendStorages[storageBackend.Type+"."+storageBackend.Id] = backendStorage
 		if storageBackend.Id == "default" {
 			BackendStorages[storageBackend.Type] = backendStorage
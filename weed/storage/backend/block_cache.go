@@ -0,0 +1,218 @@
+package backend
+
+import (
+	"container/list"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// BlockCache keeps the recently read blocks of the remote files on a local disk,
+// evicting the least recently used blocks when it is full.
+// The blocks are stored in the fixed size slots of one cache file, which only lives as long as the process.
+// The lock only guards the index of the blocks. The cache file is read and written outside of it,
+// while the slot being read or written is pinned, so it is not reused by another block meanwhile.
+type BlockCache struct {
+	sync.Mutex
+	file      *os.File
+	blockSize int64
+	slotCount int64
+	usedSlots int64
+	blocks    map[blockKey]*list.Element
+	lru       *list.List // of *cachedBlock, the most recently used first
+	freeSlots []int64
+}
+
+type blockKey struct {
+	key   string
+	index int64
+}
+
+type cachedBlock struct {
+	blockKey
+	slot    int64
+	size    int
+	readers int  // the reads of the slot in progress
+	writing bool // the slot is being written, and can not be read yet
+	removed bool // the block is removed, and its slot is freed once not pinned
+}
+
+func (b *cachedBlock) isPinned() bool {
+	return b.readers > 0 || b.writing
+}
+
+func NewBlockCache(dir, name string, sizeLimit, blockSize int64) (*BlockCache, error) {
+	if blockSize <= 0 || sizeLimit < blockSize {
+		return nil, fmt.Errorf("invalid block cache size %d with block size %d", sizeLimit, blockSize)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("create block cache dir %s: %v", dir, err)
+	}
+	file, err := os.CreateTemp(dir, name+"_*.cache")
+	if err != nil {
+		return nil, fmt.Errorf("create block cache file in %s: %v", dir, err)
+	}
+	// the cached blocks are not reused after a restart, so the file is removed right away where the platform allows it
+	os.Remove(file.Name())
+	return &BlockCache{
+		file:      file,
+		blockSize: blockSize,
+		slotCount: sizeLimit / blockSize,
+		blocks:    make(map[blockKey]*list.Element),
+		lru:       list.New(),
+	}, nil
+}
+
+func (c *BlockCache) BlockSize() int64 {
+	return c.blockSize
+}
+
+func (c *BlockCache) HasBlock(key string, index int64) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	_, found := c.blocks[blockKey{key, index}]
+	return found
+}
+
+// ReadBlock reads the cached block of the file into data, which must be the size of the block
+func (c *BlockCache) ReadBlock(key string, index int64, data []byte) (found bool) {
+	c.Lock()
+	element, found := c.blocks[blockKey{key, index}]
+	if !found {
+		c.Unlock()
+		return false
+	}
+	block := element.Value.(*cachedBlock)
+	if block.writing {
+		c.Unlock()
+		return false
+	}
+	if block.size != len(data) {
+		c.removeElement(element)
+		c.Unlock()
+		return false
+	}
+	block.readers++
+	c.lru.MoveToFront(element)
+	c.Unlock()
+
+	n, err := c.file.ReadAt(data, block.slot*c.blockSize)
+
+	c.Lock()
+	defer c.Unlock()
+	block.readers--
+	if err != nil || n != len(data) {
+		if !block.removed {
+			c.removeElement(element)
+		}
+		found = false
+	}
+	if block.removed {
+		c.releaseSlot(block)
+	}
+	return found
+}
+
+// WriteBlock caches the block of the file, and tells whether another block is evicted for it
+func (c *BlockCache) WriteBlock(key string, index int64, data []byte) (evicted bool) {
+	if int64(len(data)) > c.blockSize {
+		return false
+	}
+
+	c.Lock()
+	bk := blockKey{key, index}
+	if element, found := c.blocks[bk]; found {
+		c.removeElement(element)
+	}
+	slot, evicted, found := c.allocateSlot()
+	if !found {
+		// all the slots are being read or written
+		c.Unlock()
+		return false
+	}
+	block := &cachedBlock{
+		blockKey: bk,
+		slot:     slot,
+		size:     len(data),
+		writing:  true,
+	}
+	element := c.lru.PushFront(block)
+	c.blocks[bk] = element
+	c.Unlock()
+
+	_, err := c.file.WriteAt(data, slot*c.blockSize)
+
+	c.Lock()
+	defer c.Unlock()
+	block.writing = false
+	if err != nil && !block.removed {
+		c.removeElement(element)
+	}
+	if block.removed {
+		c.releaseSlot(block)
+	}
+	return
+}
+
+// allocateSlot takes a free slot, or else evicts the least recently used block not pinned
+func (c *BlockCache) allocateSlot() (slot int64, evicted, found bool) {
+	if len(c.freeSlots) > 0 {
+		slot = c.freeSlots[len(c.freeSlots)-1]
+		c.freeSlots = c.freeSlots[:len(c.freeSlots)-1]
+		return slot, false, true
+	}
+	if c.usedSlots < c.slotCount {
+		slot = c.usedSlots
+		c.usedSlots++
+		return slot, false, true
+	}
+	for element := c.lru.Back(); element != nil; element = element.Prev() {
+		block := element.Value.(*cachedBlock)
+		if block.isPinned() {
+			continue
+		}
+		c.lru.Remove(element)
+		delete(c.blocks, block.blockKey)
+		return block.slot, true, true
+	}
+	return 0, false, false
+}
+
+// DeleteBlocks drops the cached blocks of the file
+func (c *BlockCache) DeleteBlocks(key string) {
+	c.Lock()
+	defer c.Unlock()
+
+	for bk, element := range c.blocks {
+		if bk.key == key {
+			c.removeElement(element)
+		}
+	}
+}
+
+// removeElement removes the block from the index, and frees its slot unless it is pinned
+func (c *BlockCache) removeElement(element *list.Element) {
+	block := element.Value.(*cachedBlock)
+	c.lru.Remove(element)
+	delete(c.blocks, block.blockKey)
+	block.removed = true
+	c.releaseSlot(block)
+}
+
+// releaseSlot frees the slot of the removed block, once the slot is not being read or written
+func (c *BlockCache) releaseSlot(block *cachedBlock) {
+	if block.isPinned() || block.slot < 0 {
+		return
+	}
+	c.freeSlots = append(c.freeSlots, block.slot)
+	block.slot = -1
+}
+
+func (c *BlockCache) Close() {
+	c.Lock()
+	defer c.Unlock()
+
+	c.file.Close()
+	os.Remove(c.file.Name())
+}
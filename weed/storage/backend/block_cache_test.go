@@ -0,0 +1,111 @@
+package backend
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryStorageFile struct {
+	data      []byte
+	readCount int
+}
+
+func (f *memoryStorageFile) ReadAt(p []byte, off int64) (n int, err error) {
+	f.readCount++
+	return copy(p, f.data[off:]), nil
+}
+func (f *memoryStorageFile) WriteAt(p []byte, off int64) (n int, err error) { return 0, nil }
+func (f *memoryStorageFile) Truncate(off int64) error                       { return nil }
+func (f *memoryStorageFile) Close() error                                   { return nil }
+func (f *memoryStorageFile) GetStat() (int64, time.Time, error) {
+	return int64(len(f.data)), time.Now(), nil
+}
+func (f *memoryStorageFile) Name() string { return "memory" }
+func (f *memoryStorageFile) Sync() error  { return nil }
+
+func TestCachedBackendStorageFile(t *testing.T) {
+	cache, err := NewBlockCache(t.TempDir(), "test", 3*16, 16)
+	assert.Nil(t, err)
+	defer cache.Close()
+
+	remote := &memoryStorageFile{data: bytes.Repeat([]byte("0123456789"), 10)}
+	f := &CachedBackendStorageFile{BackendStorageFile: remote, backendName: "test", key: "k", cache: cache}
+
+	p := make([]byte, 20)
+	n, err := f.ReadAt(p, 5)
+	assert.Nil(t, err)
+	assert.Equal(t, 20, n)
+	assert.Equal(t, remote.data[5:25], p)
+	assert.Equal(t, 1, remote.readCount, "the missing blocks are read together")
+
+	n, err = f.ReadAt(p, 16)
+	assert.Nil(t, err)
+	assert.Equal(t, remote.data[16:36], p[:n])
+	assert.Equal(t, 2, remote.readCount, "only the third block is missing")
+
+	// the oldest block is evicted
+	_, err = f.ReadAt(p[:1], 50)
+	assert.Nil(t, err)
+	assert.False(t, cache.HasBlock("k", 0))
+	assert.True(t, cache.HasBlock("k", 3))
+
+	// the last block is partial
+	n, err = f.ReadAt(p, 90)
+	assert.Equal(t, 10, n)
+	assert.NotNil(t, err)
+	assert.Equal(t, remote.data[90:], p[:n])
+
+	cache.DeleteBlocks("k")
+	assert.False(t, cache.HasBlock("k", 5))
+}
+
+func TestBlockCacheKeepsPinnedSlots(t *testing.T) {
+	cache, err := NewBlockCache(t.TempDir(), "test", 2*4, 4)
+	assert.Nil(t, err)
+	defer cache.Close()
+
+	cache.WriteBlock("k", 0, []byte("0000"))
+	cache.WriteBlock("k", 1, []byte("1111"))
+
+	// the least recently used block is being read, so the other one is evicted
+	pinned := cache.blocks[blockKey{"k", 0}].Value.(*cachedBlock)
+	pinned.readers++
+	assert.True(t, cache.WriteBlock("k", 2, []byte("2222")))
+	assert.True(t, cache.HasBlock("k", 0))
+	assert.False(t, cache.HasBlock("k", 1))
+
+	// the removed block keeps its slot until the read is done
+	cache.DeleteBlocks("k")
+	assert.Equal(t, 1, len(cache.freeSlots))
+	pinned.readers--
+	cache.releaseSlot(pinned)
+	assert.Equal(t, 2, len(cache.freeSlots))
+}
+
+func TestBlockCacheConcurrentAccess(t *testing.T) {
+	cache, err := NewBlockCache(t.TempDir(), "test", 4*16, 16)
+	assert.Nil(t, err)
+	defer cache.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			data := make([]byte, 16)
+			for j := 0; j < 200; j++ {
+				index := int64((i + j) % 8)
+				if cache.ReadBlock("k", index, data) {
+					assert.Equal(t, bytes.Repeat([]byte{byte(index)}, 16), data)
+				} else {
+					cache.WriteBlock("k", index, bytes.Repeat([]byte{byte(index)}, 16))
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
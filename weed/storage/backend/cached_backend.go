@@ -0,0 +1,179 @@
+package backend
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+
+	"github.com/seaweedfs/seaweedfs/weed/glog"
+	"github.com/seaweedfs/seaweedfs/weed/pb/volume_server_pb"
+	"github.com/seaweedfs/seaweedfs/weed/stats"
+	"github.com/seaweedfs/seaweedfs/weed/util/mem"
+)
+
+// The remote files of the tiered volumes can be read through a block cache on a local disk,
+// configured for each backend storage with these properties.
+const (
+	CacheDirProperty         = "cache_dir"
+	CacheSizeMbProperty      = "cache_size_mb"
+	CacheBlockSizeKbProperty = "cache_block_size_kb"
+
+	DefaultCacheBlockSizeKb = 1024
+)
+
+type cachedBackendStorage struct {
+	BackendStorage
+	name        string
+	dir         string
+	sizeMb      int64
+	blockSizeKb int64
+	cacheOnce   sync.Once
+	cache       *BlockCache
+}
+
+// withBlockCache wraps the backend storage with a block cache, if a cache is configured for it
+func withBlockCache(backendStorage BackendStorage, configuration StringProperties, configPrefix string, name string) (BackendStorage, error) {
+	dir := configuration.GetString(configPrefix + CacheDirProperty)
+	sizeMbText := configuration.GetString(configPrefix + CacheSizeMbProperty)
+	if dir == "" || sizeMbText == "" || sizeMbText == "0" {
+		return backendStorage, nil
+	}
+	s := &cachedBackendStorage{
+		BackendStorage: backendStorage,
+		name:           name,
+		dir:            dir,
+		blockSizeKb:    DefaultCacheBlockSizeKb,
+	}
+	var err error
+	if s.sizeMb, err = strconv.ParseInt(sizeMbText, 10, 64); err != nil || s.sizeMb < 0 {
+		return nil, fmt.Errorf("invalid %s %q of backend storage %s", CacheSizeMbProperty, sizeMbText, name)
+	}
+	if blockSizeKbText := configuration.GetString(configPrefix + CacheBlockSizeKbProperty); blockSizeKbText != "" {
+		if s.blockSizeKb, err = strconv.ParseInt(blockSizeKbText, 10, 64); err != nil || s.blockSizeKb <= 0 {
+			return nil, fmt.Errorf("invalid %s %q of backend storage %s", CacheBlockSizeKbProperty, blockSizeKbText, name)
+		}
+	}
+	return s, nil
+}
+
+func (s *cachedBackendStorage) ToProperties() map[string]string {
+	m := s.BackendStorage.ToProperties()
+	m[CacheDirProperty] = s.dir
+	m[CacheSizeMbProperty] = strconv.FormatInt(s.sizeMb, 10)
+	m[CacheBlockSizeKbProperty] = strconv.FormatInt(s.blockSizeKb, 10)
+	return m
+}
+
+// getCache creates the cache when the first remote file is opened, so only the volume servers keep one
+func (s *cachedBackendStorage) getCache() *BlockCache {
+	s.cacheOnce.Do(func() {
+		cache, err := NewBlockCache(s.dir, s.name, s.sizeMb*1024*1024, s.blockSizeKb*1024)
+		if err != nil {
+			glog.Errorf("backend storage %s reads without cache: %v", s.name, err)
+			return
+		}
+		glog.V(0).Infof("backend storage %s caches %d MB in %s", s.name, s.sizeMb, s.dir)
+		s.cache = cache
+	})
+	return s.cache
+}
+
+func (s *cachedBackendStorage) NewStorageFile(key string, tierInfo *volume_server_pb.VolumeInfo) BackendStorageFile {
+	f := s.BackendStorage.NewStorageFile(key, tierInfo)
+	cache := s.getCache()
+	if cache == nil {
+		return f
+	}
+	return &CachedBackendStorageFile{
+		BackendStorageFile: f,
+		backendName:        s.name,
+		key:                key,
+		cache:              cache,
+	}
+}
+
+func (s *cachedBackendStorage) DeleteFile(key string) error {
+	if cache := s.getCache(); cache != nil {
+		cache.DeleteBlocks(key)
+	}
+	return s.BackendStorage.DeleteFile(key)
+}
+
+// CachedBackendStorageFile reads the remote file by blocks, keeping the blocks in the block cache
+type CachedBackendStorageFile struct {
+	BackendStorageFile
+	backendName string
+	key         string
+	cache       *BlockCache
+}
+
+func (f *CachedBackendStorageFile) ReadAt(p []byte, off int64) (n int, err error) {
+	datSize, _, err := f.GetStat()
+	if err != nil {
+		return 0, err
+	}
+	blockSize := f.cache.BlockSize()
+	block := mem.Allocate(int(blockSize))
+	defer mem.Free(block)
+
+	for n < len(p) {
+		pos := off + int64(n)
+		if pos >= datSize {
+			return n, io.EOF
+		}
+		index := pos / blockSize
+		blockStart := index * blockSize
+		data := block[:min(blockSize, datSize-blockStart)]
+
+		if f.cache.ReadBlock(f.key, index, data) {
+			stats.VolumeServerTieredCacheCounter.WithLabelValues(f.backendName, "hit").Inc()
+			n += copy(p[n:], data[pos-blockStart:])
+			continue
+		}
+
+		// fetch the missing blocks in the rest of the range with one remote read
+		stop := min(off+int64(len(p)), datSize)
+		stopIndex := index + 1
+		for ; stopIndex*blockSize < stop; stopIndex++ {
+			if f.cache.HasBlock(f.key, stopIndex) {
+				break
+			}
+		}
+		stats.VolumeServerTieredCacheCounter.WithLabelValues(f.backendName, "miss").Add(float64(stopIndex - index))
+		if n, err = f.readRemoteBlocks(p, n, pos, index, stopIndex, datSize); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// readRemoteBlocks reads the blocks [index, stopIndex) from the remote file into the cache, and copies them from pos into p[n:]
+func (f *CachedBackendStorageFile) readRemoteBlocks(p []byte, n int, pos, index, stopIndex, datSize int64) (int, error) {
+	blockSize := f.cache.BlockSize()
+	blockStart := index * blockSize
+	remoteData := mem.Allocate(int(min(stopIndex*blockSize, datSize) - blockStart))
+	defer mem.Free(remoteData)
+	if err := f.readRemote(remoteData, blockStart); err != nil {
+		return n, err
+	}
+	for i := index; i < stopIndex; i++ {
+		start := (i - index) * blockSize
+		if f.cache.WriteBlock(f.key, i, remoteData[start:min(start+blockSize, int64(len(remoteData)))]) {
+			stats.VolumeServerTieredCacheCounter.WithLabelValues(f.backendName, "evict").Inc()
+		}
+	}
+	return n + copy(p[n:], remoteData[pos-blockStart:]), nil
+}
+
+func (f *CachedBackendStorageFile) readRemote(data []byte, off int64) error {
+	n, err := f.BackendStorageFile.ReadAt(data, off)
+	stats.VolumeServerTieredRemoteBytesCounter.WithLabelValues(f.backendName).Add(float64(n))
+	if err != nil && err != io.EOF {
+		return err
+	}
+	if n != len(data) {
+		return fmt.Errorf("read %s [%d,%d): only %d bytes", f.key, off, off+int64(len(data)), n)
+	}
+	return nil
+}
@@ -228,3 +228,14 @@ func (l *DiskLocation) EcShardCount() int {
 	}
 	return shardCount
 }
+
+// EcShardSlotUnits is the volume slots taken by the ec shards, in erasure_coding.VolumeSlotUnits
+func (l *DiskLocation) EcShardSlotUnits() (slotUnits int64) {
+	l.ecVolumesLock.RLock()
+	defer l.ecVolumesLock.RUnlock()
+
+	for _, ecVolume := range l.ecVolumes {
+		slotUnits += int64(len(ecVolume.Shards)) * ecVolume.Scheme.ShardSlotUnits()
+	}
+	return
+}
@@ -12,8 +12,8 @@ type Interval struct {
 	LargeBlockRowsCount int
 }
 
-func LocateData(largeBlockLength, smallBlockLength int64, shardDatSize int64, offset int64, size types.Size) (intervals []Interval) {
-	blockIndex, isLargeBlock, nLargeBlockRows, innerBlockOffset := locateOffset(largeBlockLength, smallBlockLength, shardDatSize, offset)
+func LocateData(dataShards int, largeBlockLength, smallBlockLength int64, shardDatSize int64, offset int64, size types.Size) (intervals []Interval) {
+	blockIndex, isLargeBlock, nLargeBlockRows, innerBlockOffset := locateOffset(dataShards, largeBlockLength, smallBlockLength, shardDatSize, offset)
 
 	for size > 0 {
 		interval := Interval{
@@ -38,7 +38,7 @@ func LocateData(largeBlockLength, smallBlockLength int64, shardDatSize int64, of
 
 		size -= interval.Size
 		blockIndex += 1
-		if isLargeBlock && blockIndex == interval.LargeBlockRowsCount*DataShardsCount {
+		if isLargeBlock && blockIndex == interval.LargeBlockRowsCount*dataShards {
 			isLargeBlock = false
 			blockIndex = 0
 		}
@@ -48,8 +48,8 @@ func LocateData(largeBlockLength, smallBlockLength int64, shardDatSize int64, of
 	return
 }
 
-func locateOffset(largeBlockLength, smallBlockLength int64, shardDatSize int64, offset int64) (blockIndex int, isLargeBlock bool, nLargeBlockRows int64, innerBlockOffset int64) {
-	largeRowSize := largeBlockLength * DataShardsCount
+func locateOffset(dataShards int, largeBlockLength, smallBlockLength int64, shardDatSize int64, offset int64) (blockIndex int, isLargeBlock bool, nLargeBlockRows int64, innerBlockOffset int64) {
+	largeRowSize := largeBlockLength * int64(dataShards)
 	nLargeBlockRows = (shardDatSize-1)/ largeBlockLength
 
 	// if offset is within the large block area
@@ -71,14 +71,14 @@ func locateOffsetWithinBlocks(blockLength int64, offset int64) (blockIndex int,
 	return
 }
 
-func (interval Interval) ToShardIdAndOffset(largeBlockSize, smallBlockSize int64) (ShardId, int64) {
+func (interval Interval) ToShardIdAndOffset(dataShards int, largeBlockSize, smallBlockSize int64) (ShardId, int64) {
 	ecFileOffset := interval.InnerBlockOffset
-	rowIndex := interval.BlockIndex / DataShardsCount
+	rowIndex := interval.BlockIndex / dataShards
 	if interval.IsLargeBlock {
 		ecFileOffset += int64(rowIndex) * largeBlockSize
 	} else {
 		ecFileOffset += int64(interval.LargeBlockRowsCount)*largeBlockSize + int64(rowIndex)*smallBlockSize
 	}
-	ecFileIndex := interval.BlockIndex % DataShardsCount
+	ecFileIndex := interval.BlockIndex % dataShards
 	return ShardId(ecFileIndex), ecFileOffset
 }
@@ -0,0 +1,86 @@
+package erasure_coding
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/seaweedfs/seaweedfs/weed/pb/volume_server_pb"
+	"github.com/seaweedfs/seaweedfs/weed/storage/volume_info"
+)
+
+// MaxShardCount is limited by the bits of ShardBits
+const MaxShardCount = 32
+
+// EcScheme is the number of the data shards and the parity shards of an ec volume, e.g. 10+4.
+// The scheme is recorded in the .vif file, and the volumes without one use the default 10+4.
+type EcScheme struct {
+	DataShards   int
+	ParityShards int
+}
+
+var DefaultEcScheme = EcScheme{DataShards: DataShardsCount, ParityShards: ParityShardsCount}
+
+// NewEcScheme returns the scheme of the recorded shard counts, or the default one if not recorded
+func NewEcScheme(dataShards, parityShards uint32) EcScheme {
+	if dataShards == 0 || parityShards == 0 {
+		return DefaultEcScheme
+	}
+	return EcScheme{DataShards: int(dataShards), ParityShards: int(parityShards)}
+}
+
+// ParseEcScheme parses the scheme in the form of <data shards>+<parity shards>, e.g. 6+3
+func ParseEcScheme(text string) (scheme EcScheme, err error) {
+	dataText, parityText, found := strings.Cut(text, "+")
+	if !found {
+		return scheme, fmt.Errorf("invalid ec scheme %q, expecting <data shards>+<parity shards>", text)
+	}
+	if scheme.DataShards, err = strconv.Atoi(dataText); err != nil {
+		return scheme, fmt.Errorf("invalid data shards of ec scheme %q", text)
+	}
+	if scheme.ParityShards, err = strconv.Atoi(parityText); err != nil {
+		return scheme, fmt.Errorf("invalid parity shards of ec scheme %q", text)
+	}
+	return scheme, scheme.Validate()
+}
+
+func (s EcScheme) Validate() error {
+	if s.DataShards < 1 || s.ParityShards < 1 || s.TotalShards() > MaxShardCount {
+		return fmt.Errorf("invalid ec scheme %s, expecting at least 1 data shard and 1 parity shard, and at most %d shards", s, MaxShardCount)
+	}
+	return nil
+}
+
+func (s EcScheme) TotalShards() int {
+	return s.DataShards + s.ParityShards
+}
+
+// VolumeSlotUnits divides a volume slot, so that a shard of the schemes with up to 16 data shards
+// takes a whole number of units, and the shards of different schemes can be counted together.
+const VolumeSlotUnits = 720720
+
+// ShardSlotUnits is the units of a volume slot taken by one shard, i.e. 1/DataShards of the volume
+func (s EcScheme) ShardSlotUnits() int64 {
+	if s.DataShards == 0 {
+		s = DefaultEcScheme
+	}
+	return (VolumeSlotUnits + int64(s.DataShards) - 1) / int64(s.DataShards)
+}
+
+func (s EcScheme) String() string {
+	return fmt.Sprintf("%d+%d", s.DataShards, s.ParityShards)
+}
+
+// SetToVolumeInfo records the scheme in the volume info
+func (s EcScheme) SetToVolumeInfo(volumeInfo *volume_server_pb.VolumeInfo) {
+	volumeInfo.DataShards, volumeInfo.ParityShards = uint32(s.DataShards), uint32(s.ParityShards)
+}
+
+// LoadEcScheme reads the scheme recorded in the .vif file of the ec volume
+func LoadEcScheme(dataBaseFileName string) EcScheme {
+	volumeInfo, _, found, _ := volume_info.MaybeLoadVolumeInfo(dataBaseFileName + ".vif")
+	if !found {
+		return DefaultEcScheme
+	}
+	return NewEcScheme(volumeInfo.DataShards, volumeInfo.ParityShards)
+}
@@ -23,7 +23,7 @@ func TestEncodingDecoding(t *testing.T) {
 	bufferSize := 50
 	baseFileName := "1"
 
-	err := generateEcFiles(baseFileName, bufferSize, largeBlockSize, smallBlockSize)
+	err := generateEcFiles(baseFileName, DefaultEcScheme, bufferSize, largeBlockSize, smallBlockSize)
 	if err != nil {
 		t.Logf("generateEcFiles: %v", err)
 	}
@@ -33,16 +33,62 @@ func TestEncodingDecoding(t *testing.T) {
 		t.Logf("WriteSortedFileFromIdx: %v", err)
 	}
 
-	err = validateFiles(baseFileName)
+	err = validateFiles(baseFileName, DefaultEcScheme)
 	if err != nil {
 		t.Logf("WriteSortedFileFromIdx: %v", err)
 	}
 
-	removeGeneratedFiles(baseFileName)
+	removeGeneratedFiles(baseFileName, DefaultEcScheme)
 
 }
 
-func validateFiles(baseFileName string) error {
+func TestEncodingDecodingWithScheme(t *testing.T) {
+	baseFileName := "1"
+	scheme := EcScheme{DataShards: 4, ParityShards: 2}
+	defer removeGeneratedFiles(baseFileName, scheme)
+
+	assert.Nil(t, generateEcFiles(baseFileName, scheme, 50, largeBlockSize, smallBlockSize))
+	assert.FileExists(t, baseFileName+ToExt(5))
+	assert.NoFileExists(t, baseFileName+ToExt(6))
+	assert.Nil(t, validateFiles(baseFileName, scheme))
+
+	// rebuild the lost shards from the remaining ones
+	original, err := os.ReadFile(baseFileName + ToExt(1))
+	assert.Nil(t, err)
+	os.Remove(baseFileName + ToExt(1))
+	os.Remove(baseFileName + ToExt(4))
+	rebuilt, err := generateMissingEcFiles(baseFileName, scheme, 50, largeBlockSize, smallBlockSize)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint32{1, 4}, rebuilt)
+	data, err := os.ReadFile(baseFileName + ToExt(1))
+	assert.Nil(t, err)
+	assert.Equal(t, original, data)
+}
+
+func TestParseEcScheme(t *testing.T) {
+	scheme, err := ParseEcScheme("6+3")
+	assert.Nil(t, err)
+	assert.Equal(t, EcScheme{DataShards: 6, ParityShards: 3}, scheme)
+	assert.Equal(t, 9, scheme.TotalShards())
+	assert.Equal(t, "6+3", scheme.String())
+
+	_, err = ParseEcScheme("6.3")
+	assert.NotNil(t, err)
+	_, err = ParseEcScheme("30+4")
+	assert.NotNil(t, err)
+	_, err = ParseEcScheme("4+0")
+	assert.NotNil(t, err)
+
+	assert.Equal(t, DefaultEcScheme, NewEcScheme(0, 0))
+	assert.Equal(t, EcScheme{DataShards: 12, ParityShards: 4}, NewEcScheme(12, 4))
+
+	// the shards of a volume take one volume slot, whatever the scheme
+	for _, scheme := range []EcScheme{DefaultEcScheme, {DataShards: 4, ParityShards: 2}, {DataShards: 6, ParityShards: 3}, {DataShards: 12, ParityShards: 4}} {
+		assert.Equal(t, int64(VolumeSlotUnits), int64(scheme.DataShards)*scheme.ShardSlotUnits(), scheme.String())
+	}
+}
+
+func validateFiles(baseFileName string, scheme EcScheme) error {
 	nm, err := readNeedleMap(baseFileName)
 	if err != nil {
 		return fmt.Errorf("readNeedleMap: %v", err)
@@ -60,14 +106,14 @@ func validateFiles(baseFileName string) error {
 		return fmt.Errorf("failed to stat dat file: %v", err)
 	}
 
-	ecFiles, err := openEcFiles(baseFileName, true)
+	ecFiles, err := openEcFiles(baseFileName, scheme, true)
 	if err != nil {
 		return fmt.Errorf("error opening ec files: %w", err)
 	}
 	defer closeEcFiles(ecFiles)
 
 	err = nm.AscendingVisit(func(value needle_map.NeedleValue) error {
-		return assertSame(datFile, fi.Size(), ecFiles, value.Offset, value.Size)
+		return assertSame(scheme, datFile, fi.Size(), ecFiles, value.Offset, value.Size)
 	})
 	if err != nil {
 		return fmt.Errorf("failed to check ec files: %v", err)
@@ -75,7 +121,7 @@ func validateFiles(baseFileName string) error {
 	return nil
 }
 
-func assertSame(datFile *os.File, datSize int64, ecFiles []*os.File, offset types.Offset, size types.Size) error {
+func assertSame(scheme EcScheme, datFile *os.File, datSize int64, ecFiles []*os.File, offset types.Offset, size types.Size) error {
 
 	data, err := readDatFile(datFile, offset, size)
 	if err != nil {
@@ -84,7 +130,7 @@ func assertSame(datFile *os.File, datSize int64, ecFiles []*os.File, offset type
 
 	ecFileStat, _ := ecFiles[0].Stat()
 
-	ecData, err := readEcFile(ecFileStat.Size(), ecFiles, offset, size)
+	ecData, err := readEcFile(scheme, ecFileStat.Size(), ecFiles, offset, size)
 	if err != nil {
 		return fmt.Errorf("failed to read ec file: %v", err)
 	}
@@ -109,12 +155,12 @@ func readDatFile(datFile *os.File, offset types.Offset, size types.Size) ([]byte
 	return data, nil
 }
 
-func readEcFile(shardDatSize int64, ecFiles []*os.File, offset types.Offset, size types.Size) (data []byte, err error) {
+func readEcFile(scheme EcScheme, shardDatSize int64, ecFiles []*os.File, offset types.Offset, size types.Size) (data []byte, err error) {
 
-	intervals := LocateData(largeBlockSize, smallBlockSize, shardDatSize, offset.ToActualOffset(), size)
+	intervals := LocateData(scheme.DataShards, largeBlockSize, smallBlockSize, shardDatSize, offset.ToActualOffset(), size)
 
 	for i, interval := range intervals {
-		if d, e := readOneInterval(interval, ecFiles); e != nil {
+		if d, e := readOneInterval(scheme, interval, ecFiles); e != nil {
 			return nil, e
 		} else {
 			if i == 0 {
@@ -128,9 +174,9 @@ func readEcFile(shardDatSize int64, ecFiles []*os.File, offset types.Offset, siz
 	return data, nil
 }
 
-func readOneInterval(interval Interval, ecFiles []*os.File) (data []byte, err error) {
+func readOneInterval(scheme EcScheme, interval Interval, ecFiles []*os.File) (data []byte, err error) {
 
-	ecFileIndex, ecFileOffset := interval.ToShardIdAndOffset(largeBlockSize, smallBlockSize)
+	ecFileIndex, ecFileOffset := interval.ToShardIdAndOffset(scheme.DataShards, largeBlockSize, smallBlockSize)
 
 	data = make([]byte, interval.Size)
 	err = readFromFile(ecFiles[ecFileIndex], data, ecFileOffset)
@@ -184,8 +230,8 @@ func readFromFile(file *os.File, data []byte, ecFileOffset int64) (err error) {
 	return
 }
 
-func removeGeneratedFiles(baseFileName string) {
-	for i := 0; i < DataShardsCount+ParityShardsCount; i++ {
+func removeGeneratedFiles(baseFileName string, scheme EcScheme) {
+	for i := 0; i < scheme.TotalShards(); i++ {
 		fname := fmt.Sprintf("%s.ec%02d", baseFileName, i)
 		os.Remove(fname)
 	}
@@ -193,7 +239,7 @@ func removeGeneratedFiles(baseFileName string) {
 }
 
 func TestLocateData(t *testing.T) {
-	intervals := LocateData(largeBlockSize, smallBlockSize, largeBlockSize+1, DataShardsCount*largeBlockSize, 1)
+	intervals := LocateData(DataShardsCount, largeBlockSize, smallBlockSize, largeBlockSize+1, DataShardsCount*largeBlockSize, 1)
 	if len(intervals) != 1 {
 		t.Errorf("unexpected interval size %d", len(intervals))
 	}
@@ -201,7 +247,7 @@ func TestLocateData(t *testing.T) {
 		t.Errorf("unexpected interval %+v", intervals[0])
 	}
 
-	intervals = LocateData(largeBlockSize, smallBlockSize, largeBlockSize+1, DataShardsCount*largeBlockSize/2+100, DataShardsCount*largeBlockSize+1-DataShardsCount*largeBlockSize/2-100)
+	intervals = LocateData(DataShardsCount, largeBlockSize, smallBlockSize, largeBlockSize+1, DataShardsCount*largeBlockSize/2+100, DataShardsCount*largeBlockSize+1-DataShardsCount*largeBlockSize/2-100)
 	fmt.Printf("%+v\n", intervals)
 }
 
@@ -213,7 +259,7 @@ func (this Interval) sameAs(that Interval) bool {
 }
 
 func TestLocateData2(t *testing.T) {
-	intervals := LocateData(ErasureCodingLargeBlockSize, ErasureCodingSmallBlockSize, 3221225472, 21479557912, 4194339)
+	intervals := LocateData(DataShardsCount, ErasureCodingLargeBlockSize, ErasureCodingSmallBlockSize, 3221225472, 21479557912, 4194339)
 	assert.Equal(t, intervals, []Interval{
 		{BlockIndex: 4, InnerBlockOffset: 527128, Size: 521448, IsLargeBlock: false, LargeBlockRowsCount: 2},
 		{BlockIndex: 5, InnerBlockOffset: 0, Size: 1048576, IsLargeBlock: false, LargeBlockRowsCount: 2},
@@ -224,7 +270,7 @@ func TestLocateData2(t *testing.T) {
 }
 
 func TestLocateData3(t *testing.T) {
-	intervals := LocateData(ErasureCodingLargeBlockSize, ErasureCodingSmallBlockSize, 3221225472, 30782909808, 112568)
+	intervals := LocateData(DataShardsCount, ErasureCodingLargeBlockSize, ErasureCodingSmallBlockSize, 3221225472, 30782909808, 112568)
 	for _, interval := range intervals {
 		fmt.Printf("%+v\n", interval)
 	}
@@ -12,15 +12,17 @@ type EcVolumeInfo struct {
 	ShardBits   ShardBits
 	DiskType    string
 	DestroyTime uint64 //ec volume destroy time, calculated from the ec volume was created
+	Scheme      EcScheme
 }
 
-func NewEcVolumeInfo(diskType string, collection string, vid needle.VolumeId, shardBits ShardBits, destroyTime uint64) *EcVolumeInfo {
+func NewEcVolumeInfo(diskType string, collection string, vid needle.VolumeId, shardBits ShardBits, destroyTime uint64, scheme EcScheme) *EcVolumeInfo {
 	return &EcVolumeInfo{
 		Collection:  collection,
 		VolumeId:    vid,
 		ShardBits:   shardBits,
 		DiskType:    diskType,
 		DestroyTime: destroyTime,
+		Scheme:      scheme,
 	}
 }
 
@@ -44,12 +46,18 @@ func (ecInfo *EcVolumeInfo) ShardIdCount() (count int) {
 	return ecInfo.ShardBits.ShardIdCount()
 }
 
+// ShardSlotUnits is the units of a volume slot taken by the shards
+func (ecInfo *EcVolumeInfo) ShardSlotUnits() int64 {
+	return int64(ecInfo.ShardIdCount()) * ecInfo.Scheme.ShardSlotUnits()
+}
+
 func (ecInfo *EcVolumeInfo) Minus(other *EcVolumeInfo) *EcVolumeInfo {
 	ret := &EcVolumeInfo{
 		VolumeId:   ecInfo.VolumeId,
 		Collection: ecInfo.Collection,
 		ShardBits:  ecInfo.ShardBits.Minus(other.ShardBits),
 		DiskType:   ecInfo.DiskType,
+		Scheme:     ecInfo.Scheme,
 	}
 
 	return ret
@@ -57,11 +65,13 @@ func (ecInfo *EcVolumeInfo) Minus(other *EcVolumeInfo) *EcVolumeInfo {
 
 func (ecInfo *EcVolumeInfo) ToVolumeEcShardInformationMessage() (ret *master_pb.VolumeEcShardInformationMessage) {
 	return &master_pb.VolumeEcShardInformationMessage{
-		Id:          uint32(ecInfo.VolumeId),
-		EcIndexBits: uint32(ecInfo.ShardBits),
-		Collection:  ecInfo.Collection,
-		DiskType:    ecInfo.DiskType,
-		DestroyTime: ecInfo.DestroyTime,
+		Id:           uint32(ecInfo.VolumeId),
+		EcIndexBits:  uint32(ecInfo.ShardBits),
+		Collection:   ecInfo.Collection,
+		DiskType:     ecInfo.DiskType,
+		DestroyTime:  ecInfo.DestroyTime,
+		DataShards:   uint32(ecInfo.Scheme.DataShards),
+		ParityShards: uint32(ecInfo.Scheme.ParityShards),
 	}
 }
 
@@ -80,7 +90,7 @@ func (b ShardBits) HasShardId(id ShardId) bool {
 }
 
 func (b ShardBits) ShardIds() (ret []ShardId) {
-	for i := ShardId(0); i < TotalShardsCount; i++ {
+	for i := ShardId(0); i < MaxShardCount; i++ {
 		if b.HasShardId(i) {
 			ret = append(ret, i)
 		}
@@ -89,7 +99,7 @@ func (b ShardBits) ShardIds() (ret []ShardId) {
 }
 
 func (b ShardBits) ToUint32Slice() (ret []uint32) {
-	for i := uint32(0); i < TotalShardsCount; i++ {
+	for i := uint32(0); i < MaxShardCount; i++ {
 		if b.HasShardId(ShardId(i)) {
 			ret = append(ret, i)
 		}
@@ -112,8 +122,8 @@ func (b ShardBits) Plus(other ShardBits) ShardBits {
 	return b | other
 }
 
-func (b ShardBits) MinusParityShards() ShardBits {
-	for i := DataShardsCount; i < TotalShardsCount; i++ {
+func (b ShardBits) MinusParityShards(scheme EcScheme) ShardBits {
+	for i := scheme.DataShards; i < scheme.TotalShards(); i++ {
 		b = b.RemoveShardId(ShardId(i))
 	}
 	return b
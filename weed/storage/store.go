@@ -139,7 +139,7 @@ func (s *Store) findVolume(vid needle.VolumeId) *Volume {
 	return nil
 }
 func (s *Store) FindFreeLocation(filterFn func(location *DiskLocation) bool) (ret *DiskLocation) {
-	max := int32(0)
+	max := int64(0)
 	for _, location := range s.Locations {
 		if filterFn != nil && !filterFn(location) {
 			continue
@@ -147,10 +147,10 @@ func (s *Store) FindFreeLocation(filterFn func(location *DiskLocation) bool) (re
 		if location.isDiskSpaceLow {
 			continue
 		}
-		currentFreeCount := location.MaxVolumeCount - int32(location.VolumesLen())
-		currentFreeCount *= erasure_coding.DataShardsCount
-		currentFreeCount -= int32(location.EcShardCount())
-		currentFreeCount /= erasure_coding.DataShardsCount
+		currentFreeCount := int64(location.MaxVolumeCount - int32(location.VolumesLen()))
+		currentFreeCount *= erasure_coding.VolumeSlotUnits
+		currentFreeCount -= location.EcShardSlotUnits()
+		currentFreeCount /= erasure_coding.VolumeSlotUnits
 		if currentFreeCount > max {
 			max = currentFreeCount
 			ret = location
@@ -620,8 +620,8 @@ func (s *Store) MaybeAdjustVolumeMax() (hasChanges bool) {
 			unusedSpace := diskLocation.UnUsedSpace(volumeSizeLimit)
 			unclaimedSpaces := int64(diskStatus.Free) - int64(unusedSpace)
 			volCount := diskLocation.VolumesLen()
-			ecShardCount := diskLocation.EcShardCount()
-			maxVolumeCount := int32(volCount) + int32((ecShardCount+erasure_coding.DataShardsCount)/erasure_coding.DataShardsCount)
+			ecShardSlotUnits := diskLocation.EcShardSlotUnits()
+			maxVolumeCount := int32(volCount) + int32((ecShardSlotUnits+erasure_coding.VolumeSlotUnits)/erasure_coding.VolumeSlotUnits)
 			if unclaimedSpaces > int64(volumeSizeLimit) {
 				maxVolumeCount += int32(uint64(unclaimedSpaces)/volumeSizeLimit) - 1
 			}
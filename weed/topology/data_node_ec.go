@@ -26,8 +26,9 @@ func (dn *DataNode) UpdateEcShards(actualShards []*erasure_coding.EcVolumeInfo)
 	existingEcShards := dn.GetEcShards()
 
 	// find out the newShards and deletedShards
-	var newShardCount, deletedShardCount int
 	for _, ecShards := range existingEcShards {
+		var newShardCount, deletedShardCount int
+		var newSlotUnits, deletedSlotUnits int64
 
 		disk := dn.getOrCreateDisk(ecShards.DiskType)
 		deltaDiskUsages := newDiskUsages()
@@ -38,21 +39,25 @@ func (dn *DataNode) UpdateEcShards(actualShards []*erasure_coding.EcVolumeInfo)
 			// dn registered ec shards not found in the new set of ec shards
 			deletedShards = append(deletedShards, ecShards)
 			deletedShardCount += ecShards.ShardIdCount()
+			deletedSlotUnits += ecShards.ShardSlotUnits()
 		} else {
 			// found, but maybe the actual shard could be missing
 			a := actualEcShards.Minus(ecShards)
 			if a.ShardIdCount() > 0 {
 				newShards = append(newShards, a)
 				newShardCount += a.ShardIdCount()
+				newSlotUnits += a.ShardSlotUnits()
 			}
 			d := ecShards.Minus(actualEcShards)
 			if d.ShardIdCount() > 0 {
 				deletedShards = append(deletedShards, d)
 				deletedShardCount += d.ShardIdCount()
+				deletedSlotUnits += d.ShardSlotUnits()
 			}
 		}
 
 		deltaDiskUsage.ecShardCount = int64(newShardCount - deletedShardCount)
+		deltaDiskUsage.ecShardSlotUnits = newSlotUnits - deletedSlotUnits
 		disk.UpAdjustDiskUsageDelta(deltaDiskUsages)
 
 	}
@@ -68,6 +73,7 @@ func (dn *DataNode) UpdateEcShards(actualShards []*erasure_coding.EcVolumeInfo)
 		deltaDiskUsages := newDiskUsages()
 		deltaDiskUsage := deltaDiskUsages.getOrCreateDisk(types.ToDiskType(ecShards.DiskType))
 		deltaDiskUsage.ecShardCount = int64(ecShards.ShardIdCount())
+		deltaDiskUsage.ecShardSlotUnits = ecShards.ShardSlotUnits()
 		disk.UpAdjustDiskUsageDelta(deltaDiskUsages)
 	}
 
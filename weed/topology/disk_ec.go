@@ -20,18 +20,22 @@ func (d *Disk) AddOrUpdateEcShard(s *erasure_coding.EcVolumeInfo) {
 	defer d.ecShardsLock.Unlock()
 
 	delta := 0
+	var deltaSlotUnits int64
 	if existing, ok := d.ecShards[s.VolumeId]; !ok {
 		d.ecShards[s.VolumeId] = s
 		delta = s.ShardBits.ShardIdCount()
+		deltaSlotUnits = s.ShardSlotUnits()
 	} else {
-		oldCount := existing.ShardBits.ShardIdCount()
+		oldCount, oldSlotUnits := existing.ShardBits.ShardIdCount(), existing.ShardSlotUnits()
 		existing.ShardBits = existing.ShardBits.Plus(s.ShardBits)
 		delta = existing.ShardBits.ShardIdCount() - oldCount
+		deltaSlotUnits = existing.ShardSlotUnits() - oldSlotUnits
 	}
 
 	deltaDiskUsages := newDiskUsages()
 	deltaDiskUsage := deltaDiskUsages.getOrCreateDisk(types.ToDiskType(string(d.Id())))
 	deltaDiskUsage.ecShardCount = int64(delta)
+	deltaDiskUsage.ecShardSlotUnits = deltaSlotUnits
 	d.UpAdjustDiskUsageDelta(deltaDiskUsages)
 
 }
@@ -41,13 +45,14 @@ func (d *Disk) DeleteEcShard(s *erasure_coding.EcVolumeInfo) {
 	defer d.ecShardsLock.Unlock()
 
 	if existing, ok := d.ecShards[s.VolumeId]; ok {
-		oldCount := existing.ShardBits.ShardIdCount()
+		oldCount, oldSlotUnits := existing.ShardBits.ShardIdCount(), existing.ShardSlotUnits()
 		existing.ShardBits = existing.ShardBits.Minus(s.ShardBits)
 		delta := existing.ShardBits.ShardIdCount() - oldCount
 
 		deltaDiskUsages := newDiskUsages()
 		deltaDiskUsage := deltaDiskUsages.getOrCreateDisk(types.ToDiskType(string(d.Id())))
 		deltaDiskUsage.ecShardCount = int64(delta)
+		deltaDiskUsage.ecShardSlotUnits = existing.ShardSlotUnits() - oldSlotUnits
 		d.UpAdjustDiskUsageDelta(deltaDiskUsages)
 
 		if existing.ShardBits.ShardIdCount() == 0 {
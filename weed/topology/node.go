@@ -160,7 +160,7 @@ func (n *NodeImpl) AvailableSpaceFor(option *VolumeGrowOption) int64 {
 	freeVolumeSlotCount := atomic.LoadInt64(&t.maxVolumeCount) + atomic.LoadInt64(&t.remoteVolumeCount) - atomic.LoadInt64(&t.volumeCount)
 	ecShardCount := atomic.LoadInt64(&t.ecShardCount)
 	if ecShardCount > 0 {
-		freeVolumeSlotCount = freeVolumeSlotCount - ecShardCount/erasure_coding.DataShardsCount - 1
+		freeVolumeSlotCount = freeVolumeSlotCount - atomic.LoadInt64(&t.ecShardSlotUnits)/erasure_coding.VolumeSlotUnits - 1
 	}
 	return freeVolumeSlotCount
 }
@@ -10,7 +10,8 @@ import (
 
 type EcShardLocations struct {
 	Collection string
-	Locations  [erasure_coding.TotalShardsCount][]*DataNode
+	Scheme     erasure_coding.EcScheme
+	Locations  [erasure_coding.MaxShardCount][]*DataNode
 }
 
 func (t *Topology) SyncDataNodeEcShards(shardInfos []*master_pb.VolumeEcShardInformationMessage, dn *DataNode) (newShards, deletedShards []*erasure_coding.EcVolumeInfo) {
@@ -23,7 +24,8 @@ func (t *Topology) SyncDataNodeEcShards(shardInfos []*master_pb.VolumeEcShardInf
 				shardInfo.Collection,
 				needle.VolumeId(shardInfo.Id),
 				erasure_coding.ShardBits(shardInfo.EcIndexBits),
-				shardInfo.DestroyTime))
+				shardInfo.DestroyTime,
+				erasure_coding.NewEcScheme(shardInfo.DataShards, shardInfo.ParityShards)))
 	}
 	// find out the delta volumes
 	newShards, deletedShards = dn.UpdateEcShards(shards)
@@ -45,7 +47,8 @@ func (t *Topology) IncrementalSyncDataNodeEcShards(newEcShards, deletedEcShards
 				shardInfo.DiskType,
 				shardInfo.Collection,
 				needle.VolumeId(shardInfo.Id),
-				erasure_coding.ShardBits(shardInfo.EcIndexBits), shardInfo.DestroyTime))
+				erasure_coding.ShardBits(shardInfo.EcIndexBits), shardInfo.DestroyTime,
+				erasure_coding.NewEcScheme(shardInfo.DataShards, shardInfo.ParityShards)))
 	}
 	for _, shardInfo := range deletedEcShards {
 		deletedShards = append(deletedShards,
@@ -53,7 +56,8 @@ func (t *Topology) IncrementalSyncDataNodeEcShards(newEcShards, deletedEcShards
 				shardInfo.DiskType,
 				shardInfo.Collection,
 				needle.VolumeId(shardInfo.Id),
-				erasure_coding.ShardBits(shardInfo.EcIndexBits), shardInfo.DestroyTime))
+				erasure_coding.ShardBits(shardInfo.EcIndexBits), shardInfo.DestroyTime,
+				erasure_coding.NewEcScheme(shardInfo.DataShards, shardInfo.ParityShards)))
 	}
 
 	dn.DeltaUpdateEcShards(newShards, deletedShards)
@@ -67,9 +71,10 @@ func (t *Topology) IncrementalSyncDataNodeEcShards(newEcShards, deletedEcShards
 	return
 }
 
-func NewEcShardLocations(collection string) *EcShardLocations {
+func NewEcShardLocations(collection string, scheme erasure_coding.EcScheme) *EcShardLocations {
 	return &EcShardLocations{
 		Collection: collection,
+		Scheme:     scheme,
 	}
 }
 
@@ -106,7 +111,7 @@ func (t *Topology) RegisterEcShards(ecShardInfos *erasure_coding.EcVolumeInfo, d
 
 	locations, found := t.ecShardMap[ecShardInfos.VolumeId]
 	if !found {
-		locations = NewEcShardLocations(ecShardInfos.Collection)
+		locations = NewEcShardLocations(ecShardInfos.Collection, ecShardInfos.Scheme)
 		t.ecShardMap[ecShardInfos.VolumeId] = locations
 	}
 	for _, shardId := range ecShardInfos.ShardIds() {